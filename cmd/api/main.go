@@ -50,7 +50,7 @@ func main() {
 	}()
 
 	// 4. Run Migrations
-	if err := db.RunMigrations(cfg.DatabaseURL, "db/migrations", logger); err != nil {
+	if err := db.RunMigrations(cfg.DatabaseURL, cfg.MigrationsPath, logger); err != nil {
 		logger.Fatal().Err(err).Msg("Failed to run database migrations")
 	}
 
@@ -0,0 +1,42 @@
+// Command rebuild-aggregates recomputes the monthly_category_totals table from
+// the transactions. The database keeps the totals up to date on its own; run
+// it before enabling REPORT_AGGREGATES_ENABLED on a database whose totals may
+// have drifted, or whenever they are suspected to be wrong.
+package main
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/config"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/logger"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+)
+
+func main() {
+	logger := logger.New()
+
+	var cfg config.Config
+	if err := cfg.Load(logger); err != nil {
+		logger.Fatal().Err(err).Msg("could not load config")
+	}
+
+	database, err := sqlx.Connect("postgres", cfg.DatabaseURL)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("could not connect to the database")
+	}
+	defer func() {
+		if err := database.Close(); err != nil {
+			logger.Error().Err(err).Msg("Error closing database connection")
+		}
+	}()
+
+	ctx := logger.WithContext(context.Background())
+	rows, err := repository.NewMonthlyTotalsRepository(database).Rebuild(ctx)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to rebuild the monthly totals")
+	}
+	logger.Info().Int64("rows", rows).Msg("Monthly totals rebuilt")
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS admin_audit_log;
+
+ALTER TABLE users
+DROP COLUMN is_admin,
+DROP COLUMN disabled_at,
+DROP COLUMN password_reset_required;
@@ -0,0 +1,20 @@
+-- Instance administration: admin flag, account suspension and forced password resets.
+ALTER TABLE users
+ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN disabled_at TIMESTAMPTZ,
+ADD COLUMN password_reset_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Every action performed through the admin API is recorded here.
+-- admin_id is kept nullable so the trail survives the deletion of the admin.
+CREATE TABLE admin_audit_log (
+    id SERIAL PRIMARY KEY,
+    admin_id INT,
+    action VARCHAR(100) NOT NULL,
+    target_user_id INT,
+    details JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_admin FOREIGN KEY(admin_id) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_target_user FOREIGN KEY(target_user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_admin_audit_log_created_at ON admin_audit_log(created_at);
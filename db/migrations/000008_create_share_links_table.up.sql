@@ -0,0 +1,19 @@
+-- Read-only links that expose a single report, statement or transaction filter
+-- without authentication. Only the SHA-256 hash of the token is stored.
+CREATE TABLE share_links (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    scope VARCHAR(30) NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}',
+    password_hash VARCHAR(255),
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    access_count INT NOT NULL DEFAULT 0,
+    last_accessed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_share_link_scope CHECK (scope IN ('monthly_report', 'statement', 'transactions'))
+);
+
+CREATE INDEX idx_share_links_user_id ON share_links(user_id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS collaborator_grants;
@@ -0,0 +1,21 @@
+-- Read-only access granted by a user (the owner) to another registered user,
+-- such as an accountant. The grant only becomes usable once the invitee accepts it.
+CREATE TABLE collaborator_grants (
+    id SERIAL PRIMARY KEY,
+    owner_id INT NOT NULL,
+    collaborator_id INT,
+    invitee_email VARCHAR(255) NOT NULL,
+    account_ids INT[] NOT NULL,
+    start_date DATE,
+    end_date DATE,
+    accepted_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_owner FOREIGN KEY(owner_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_collaborator FOREIGN KEY(collaborator_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_collaborator_grant_period CHECK (start_date IS NULL OR end_date IS NULL OR start_date <= end_date)
+);
+
+-- An owner can only have one live invitation per e-mail.
+CREATE UNIQUE INDEX idx_collaborator_grants_owner_email ON collaborator_grants(owner_id, LOWER(invitee_email)) WHERE revoked_at IS NULL;
+CREATE INDEX idx_collaborator_grants_collaborator_id ON collaborator_grants(collaborator_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS points_transfers;
+DROP TABLE IF EXISTS points_lots;
+DROP TABLE IF EXISTS points_earn_rules;
+ALTER TABLE accounts DROP COLUMN IF EXISTS points_value_per_thousand;
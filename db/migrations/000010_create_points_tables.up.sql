@@ -0,0 +1,61 @@
+-- Loyalty points accounts (Livelo, Smiles, Esfera...) reuse the accounts table
+-- with type 'points'. Their balance is kept in lots, not in transactions, and
+-- the value of 1,000 points is used to estimate their worth.
+ALTER TABLE accounts ADD COLUMN points_value_per_thousand DECIMAL(12, 2);
+
+-- How many points a credit card earns in a program per currency unit spent.
+CREATE TABLE points_earn_rules (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    card_account_id INT NOT NULL,
+    points_account_id INT NOT NULL,
+    points_per_unit DECIMAL(10, 4) NOT NULL CHECK (points_per_unit > 0),
+    expiration_months INT CHECK (expiration_months > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_card_account FOREIGN KEY(card_account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_points_account FOREIGN KEY(points_account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    UNIQUE(card_account_id, points_account_id)
+);
+
+-- A lot is a batch of points credited at once. Points are spent from the lots
+-- that expire first, so each lot keeps what is left of it.
+CREATE TABLE points_lots (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    account_id INT NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    points BIGINT NOT NULL CHECK (points >= 0),
+    remaining BIGINT NOT NULL CHECK (remaining >= 0),
+    earned_at DATE NOT NULL,
+    expires_at DATE,
+    rule_id INT,
+    statement_year INT,
+    statement_month INT,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_account FOREIGN KEY(account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_rule FOREIGN KEY(rule_id) REFERENCES points_earn_rules(id) ON DELETE SET NULL,
+    CONSTRAINT chk_points_lot_source CHECK (source IN ('manual', 'statement', 'transfer'))
+);
+
+CREATE INDEX idx_points_lots_account_id ON points_lots(account_id);
+-- Each rule credits a statement only once; accruing it again updates the lot.
+CREATE UNIQUE INDEX idx_points_lots_statement ON points_lots(rule_id, statement_year, statement_month) WHERE rule_id IS NOT NULL;
+
+-- Transfers between programs, kept for history. The debited points come out of
+-- the source lots and the credited points form a new lot in the destination.
+CREATE TABLE points_transfers (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    from_account_id INT NOT NULL,
+    to_account_id INT NOT NULL,
+    points BIGINT NOT NULL CHECK (points > 0),
+    bonus_percent DECIMAL(6, 2) NOT NULL DEFAULT 0,
+    credited_points BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_from_account FOREIGN KEY(from_account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_to_account FOREIGN KEY(to_account_id) REFERENCES accounts(id) ON DELETE CASCADE
+);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS benefit_credits;
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS benefit_allowed_category_ids,
+    DROP COLUMN IF EXISTS benefit_monthly_credit,
+    DROP COLUMN IF EXISTS benefit_credit_day,
+    DROP COLUMN IF EXISTS benefit_expires_unused;
@@ -0,0 +1,24 @@
+-- Meal and food voucher accounts (Vale-Refeição, Vale-Alimentação) reuse the
+-- accounts table with type 'benefit'. They can only pay for the allowed expense
+-- categories and are credited by the employer every month.
+ALTER TABLE accounts
+    ADD COLUMN benefit_allowed_category_ids INT[],
+    ADD COLUMN benefit_monthly_credit DECIMAL(12, 2) CHECK (benefit_monthly_credit > 0),
+    ADD COLUMN benefit_credit_day INT CHECK (benefit_credit_day BETWEEN 1 AND 31),
+    ADD COLUMN benefit_expires_unused BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- One row per monthly credit, so a month is never credited twice. When the
+-- unused balance expires, the amount written off is kept alongside.
+CREATE TABLE benefit_credits (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    account_id INT NOT NULL,
+    year INT NOT NULL,
+    month INT NOT NULL CHECK (month BETWEEN 1 AND 12),
+    amount DECIMAL(12, 2) NOT NULL,
+    expired_amount DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_account FOREIGN KEY(account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    UNIQUE(account_id, year, month)
+);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS asset_valuations;
+ALTER TABLE accounts
+    DROP CONSTRAINT IF EXISTS chk_asset_valuation_method,
+    DROP COLUMN IF EXISTS asset_purchase_value,
+    DROP COLUMN IF EXISTS asset_purchase_date,
+    DROP COLUMN IF EXISTS asset_valuation_method,
+    DROP COLUMN IF EXISTS asset_useful_life_months,
+    DROP COLUMN IF EXISTS asset_annual_depreciation_rate,
+    DROP COLUMN IF EXISTS asset_salvage_value;
@@ -0,0 +1,32 @@
+-- Physical assets such as cars and property reuse the accounts table with type
+-- 'asset'. They hold no transactions; their worth comes from the purchase value
+-- and the valuations below.
+ALTER TABLE accounts
+    ADD COLUMN asset_purchase_value DECIMAL(14, 2) CHECK (asset_purchase_value > 0),
+    ADD COLUMN asset_purchase_date DATE,
+    ADD COLUMN asset_valuation_method VARCHAR(20),
+    ADD COLUMN asset_useful_life_months INT CHECK (asset_useful_life_months > 0),
+    ADD COLUMN asset_annual_depreciation_rate DECIMAL(5, 2) CHECK (asset_annual_depreciation_rate > 0 AND asset_annual_depreciation_rate <= 100),
+    ADD COLUMN asset_salvage_value DECIMAL(14, 2) CHECK (asset_salvage_value >= 0),
+    ADD CONSTRAINT chk_asset_valuation_method CHECK (asset_valuation_method IN ('manual', 'straight_line', 'declining_balance'));
+
+-- Dated values of an asset. Manual revaluations are entered by the user and
+-- depreciation adjustments are generated monthly, starting from the latest
+-- manual value or the purchase.
+CREATE TABLE asset_valuations (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    account_id INT NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    value DECIMAL(14, 2) NOT NULL CHECK (value >= 0),
+    valued_on DATE NOT NULL,
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_account FOREIGN KEY(account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    CONSTRAINT chk_asset_valuation_source CHECK (source IN ('manual', 'depreciation'))
+);
+
+CREATE INDEX idx_asset_valuations_account_id ON asset_valuations(account_id, valued_on);
+-- The job generates each depreciation adjustment only once.
+CREATE UNIQUE INDEX idx_asset_valuations_depreciation ON asset_valuations(account_id, valued_on) WHERE source = 'depreciation';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS paycheck_lines;
+DROP TABLE IF EXISTS paychecks;
+DROP TABLE IF EXISTS paycheck_template_lines;
+DROP TABLE IF EXISTS paycheck_templates;
@@ -0,0 +1,70 @@
+-- A paycheck template describes a salary: its earnings (gross salary, bonuses)
+-- and deductions (INSS, IRRF, health plan...). Posting it creates one income
+-- transaction for the net amount and keeps the breakdown as paycheck lines.
+CREATE TABLE paycheck_templates (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    account_id INT NOT NULL,
+    income_category_id INT,
+    -- Day of the month the salary is posted automatically; NULL posts it only on demand.
+    pay_day INT CHECK (pay_day BETWEEN 1 AND 31),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_account FOREIGN KEY(account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_income_category FOREIGN KEY(income_category_id) REFERENCES categories(id) ON DELETE SET NULL,
+    UNIQUE(user_id, name)
+);
+
+CREATE TABLE paycheck_template_lines (
+    id SERIAL PRIMARY KEY,
+    template_id INT NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    description VARCHAR(100) NOT NULL,
+    category_id INT,
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount > 0),
+    CONSTRAINT fk_template FOREIGN KEY(template_id) REFERENCES paycheck_templates(id) ON DELETE CASCADE,
+    CONSTRAINT fk_category FOREIGN KEY(category_id) REFERENCES categories(id) ON DELETE SET NULL,
+    CONSTRAINT chk_paycheck_template_line_kind CHECK (kind IN ('earning', 'deduction'))
+);
+
+-- A posted paycheck. The net amount is the income transaction; gross and
+-- deductions are kept for tax and benefit reports.
+CREATE TABLE paychecks (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    template_id INT,
+    account_id INT NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    year INT NOT NULL,
+    month INT NOT NULL CHECK (month BETWEEN 1 AND 12),
+    pay_date DATE NOT NULL,
+    gross DECIMAL(12, 2) NOT NULL,
+    deductions DECIMAL(12, 2) NOT NULL,
+    net DECIMAL(12, 2) NOT NULL CHECK (net > 0),
+    transaction_id INT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_template FOREIGN KEY(template_id) REFERENCES paycheck_templates(id) ON DELETE SET NULL,
+    CONSTRAINT fk_account FOREIGN KEY(account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_transaction FOREIGN KEY(transaction_id) REFERENCES transactions(id) ON DELETE SET NULL,
+    CONSTRAINT chk_paycheck_kind CHECK (kind IN ('regular', 'thirteenth', 'vacation', 'bonus'))
+);
+
+CREATE INDEX idx_paychecks_user_year ON paychecks(user_id, year);
+-- Each template is paid once per kind and month, so the scheduler never pays twice.
+CREATE UNIQUE INDEX idx_paychecks_template_period ON paychecks(template_id, year, month, kind) WHERE template_id IS NOT NULL;
+
+CREATE TABLE paycheck_lines (
+    id SERIAL PRIMARY KEY,
+    paycheck_id INT NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    description VARCHAR(100) NOT NULL,
+    category_id INT,
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount > 0),
+    CONSTRAINT fk_paycheck FOREIGN KEY(paycheck_id) REFERENCES paychecks(id) ON DELETE CASCADE,
+    CONSTRAINT fk_category FOREIGN KEY(category_id) REFERENCES categories(id) ON DELETE SET NULL,
+    CONSTRAINT chk_paycheck_line_kind CHECK (kind IN ('earning', 'deduction'))
+);
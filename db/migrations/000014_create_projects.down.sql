@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_transactions_project_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS project_id;
+DROP TABLE IF EXISTS project_category_budgets;
+DROP TABLE IF EXISTS projects;
@@ -0,0 +1,32 @@
+-- A project groups the spending of an event that spans categories, accounts and
+-- months, such as a wedding, a renovation or a trip. Transactions are assigned
+-- to it explicitly.
+CREATE TABLE projects (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255),
+    total_budget DECIMAL(12, 2) NOT NULL CHECK (total_budget > 0),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_project_dates CHECK (end_date >= start_date),
+    UNIQUE(user_id, name)
+);
+
+-- Optional sub-budgets of a project for some of its categories.
+CREATE TABLE project_category_budgets (
+    id SERIAL PRIMARY KEY,
+    project_id INT NOT NULL,
+    category_id INT NOT NULL,
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount > 0),
+    CONSTRAINT fk_project FOREIGN KEY(project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    CONSTRAINT fk_category FOREIGN KEY(category_id) REFERENCES categories(id) ON DELETE CASCADE,
+    UNIQUE(project_id, category_id)
+);
+
+ALTER TABLE transactions ADD COLUMN project_id INT REFERENCES projects(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_transactions_project_id ON transactions(project_id) WHERE project_id IS NOT NULL;
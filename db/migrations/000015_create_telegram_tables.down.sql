@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS telegram_budget_alerts;
+DROP INDEX IF EXISTS idx_telegram_chats_user_id;
+DROP TABLE IF EXISTS telegram_chats;
+DROP TABLE IF EXISTS telegram_link_codes;
@@ -0,0 +1,35 @@
+-- One-time codes a user creates in the API and sends to the bot to link a chat.
+-- Only the hash of the code is stored.
+CREATE TABLE telegram_link_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    code_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Telegram chats linked to a user. Expenses logged through the bot go to the
+-- default account when the message does not name one.
+CREATE TABLE telegram_chats (
+    chat_id BIGINT PRIMARY KEY,
+    user_id INT NOT NULL,
+    default_account_id INT,
+    linked_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_default_account FOREIGN KEY(default_account_id) REFERENCES accounts(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_telegram_chats_user_id ON telegram_chats(user_id);
+
+-- Budget alerts already sent to a chat, so each threshold is only announced once.
+CREATE TABLE telegram_budget_alerts (
+    chat_id BIGINT NOT NULL,
+    budget_id INT NOT NULL,
+    threshold INT NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (chat_id, budget_id, threshold),
+    CONSTRAINT fk_chat FOREIGN KEY(chat_id) REFERENCES telegram_chats(chat_id) ON DELETE CASCADE,
+    CONSTRAINT fk_budget FOREIGN KEY(budget_id) REFERENCES budgets(id) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+ALTER TABLE users
+DROP COLUMN fiscal_month_start_day,
+DROP COLUMN fiscal_month_label;
@@ -0,0 +1,6 @@
+-- Users who think of "the month" as running from payday to payday pick the
+-- day it starts on, and whether it is named after the calendar month it
+-- starts or ends in. The defaults keep calendar months.
+ALTER TABLE users
+ADD COLUMN fiscal_month_start_day SMALLINT NOT NULL DEFAULT 1 CHECK (fiscal_month_start_day BETWEEN 1 AND 31),
+ADD COLUMN fiscal_month_label VARCHAR(10) NOT NULL DEFAULT 'start' CHECK (fiscal_month_label IN ('start', 'end'));
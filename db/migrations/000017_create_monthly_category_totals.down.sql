@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS trg_monthly_category_totals_update ON transactions;
+DROP TRIGGER IF EXISTS trg_monthly_category_totals ON transactions;
+DROP FUNCTION IF EXISTS maintain_monthly_category_totals();
+DROP FUNCTION IF EXISTS apply_monthly_category_total(INT, INT, INT, VARCHAR, TIMESTAMPTZ, DECIMAL, INT);
+DROP TABLE IF EXISTS monthly_category_totals;
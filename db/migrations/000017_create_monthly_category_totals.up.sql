@@ -0,0 +1,77 @@
+-- Running totals of the transactions of each user per category, account, type
+-- and calendar month (in UTC), so reports don't have to add up every
+-- transaction. A trigger keeps them in step with every write to transactions;
+-- a row disappears when its last transaction does.
+CREATE TABLE monthly_category_totals (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    category_id INT,
+    account_id INT NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    year SMALLINT NOT NULL,
+    month SMALLINT NOT NULL,
+    total DECIMAL(14, 2) NOT NULL,
+    count INT NOT NULL,
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Transactions without a category share the row of category 0.
+CREATE UNIQUE INDEX idx_monthly_category_totals_key
+    ON monthly_category_totals (user_id, (COALESCE(category_id, 0)), account_id, type, year, month);
+
+CREATE FUNCTION apply_monthly_category_total(
+    p_user_id INT, p_category_id INT, p_account_id INT, p_type VARCHAR,
+    p_date TIMESTAMPTZ, p_amount DECIMAL, p_sign INT
+) RETURNS VOID AS $$
+DECLARE
+    v_year SMALLINT := EXTRACT(YEAR FROM p_date AT TIME ZONE 'UTC');
+    v_month SMALLINT := EXTRACT(MONTH FROM p_date AT TIME ZONE 'UTC');
+BEGIN
+    IF p_sign > 0 THEN
+        INSERT INTO monthly_category_totals (user_id, category_id, account_id, type, year, month, total, count)
+        VALUES (p_user_id, p_category_id, p_account_id, p_type, v_year, v_month, p_amount, 1)
+        ON CONFLICT (user_id, (COALESCE(category_id, 0)), account_id, type, year, month)
+        DO UPDATE SET total = monthly_category_totals.total + EXCLUDED.total,
+                      count = monthly_category_totals.count + 1;
+    ELSE
+        UPDATE monthly_category_totals
+        SET total = total - p_amount, count = count - 1
+        WHERE user_id = p_user_id AND COALESCE(category_id, 0) = COALESCE(p_category_id, 0)
+          AND account_id = p_account_id AND type = p_type AND year = v_year AND month = v_month;
+
+        DELETE FROM monthly_category_totals
+        WHERE user_id = p_user_id AND COALESCE(category_id, 0) = COALESCE(p_category_id, 0)
+          AND account_id = p_account_id AND type = p_type AND year = v_year AND month = v_month
+          AND count <= 0;
+    END IF;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION maintain_monthly_category_totals() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM apply_monthly_category_total(OLD.user_id, OLD.category_id, OLD.account_id, OLD.type, OLD.date, OLD.amount, -1);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM apply_monthly_category_total(NEW.user_id, NEW.category_id, NEW.account_id, NEW.type, NEW.date, NEW.amount, 1);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_monthly_category_totals
+AFTER INSERT OR DELETE ON transactions
+FOR EACH ROW EXECUTE FUNCTION maintain_monthly_category_totals();
+
+-- Updates that don't touch an aggregated column, such as assigning a project,
+-- leave the totals alone.
+CREATE TRIGGER trg_monthly_category_totals_update
+AFTER UPDATE OF user_id, category_id, account_id, type, date, amount ON transactions
+FOR EACH ROW EXECUTE FUNCTION maintain_monthly_category_totals();
+
+INSERT INTO monthly_category_totals (user_id, category_id, account_id, type, year, month, total, count)
+SELECT user_id, category_id, account_id, type,
+       EXTRACT(YEAR FROM date AT TIME ZONE 'UTC'), EXTRACT(MONTH FROM date AT TIME ZONE 'UTC'),
+       SUM(amount), COUNT(*)
+FROM transactions
+GROUP BY 1, 2, 3, 4, 5, 6;
@@ -0,0 +1,20 @@
+-- Versions of a credit card's closing and due days. Each statement uses the
+-- version in force on its closing date, so changing the days does not move
+-- statements that already closed. The days on the accounts table mirror the
+-- latest version.
+CREATE TABLE billing_cycles (
+    id SERIAL PRIMARY KEY,
+    account_id INT NOT NULL,
+    statement_closing_day INT NOT NULL CHECK (statement_closing_day BETWEEN 1 AND 31),
+    payment_due_day INT NOT NULL CHECK (payment_due_day BETWEEN 1 AND 31),
+    effective_from DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (account_id, effective_from),
+    CONSTRAINT fk_account FOREIGN KEY(account_id) REFERENCES accounts(id) ON DELETE CASCADE
+);
+
+-- Existing cards start with the days they have now.
+INSERT INTO billing_cycles (account_id, statement_closing_day, payment_due_day, effective_from)
+SELECT id, statement_closing_day, payment_due_day, created_at::date
+FROM accounts
+WHERE type = 'credit_card' AND statement_closing_day IS NOT NULL AND payment_due_day IS NOT NULL;
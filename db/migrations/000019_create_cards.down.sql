@@ -0,0 +1,3 @@
+ALTER TABLE transactions DROP COLUMN IF EXISTS card_id;
+DROP INDEX IF EXISTS idx_cards_account_id;
+DROP TABLE IF EXISTS cards;
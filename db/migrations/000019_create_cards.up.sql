@@ -0,0 +1,23 @@
+-- Additional and virtual cards of a credit card account. They share the
+-- account's limit and statement; spend_limit optionally caps what a card may
+-- spend in each statement.
+CREATE TABLE cards (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    account_id INT NOT NULL,
+    holder_name VARCHAR(100) NOT NULL,
+    last_four CHAR(4) NOT NULL CHECK (last_four ~ '^[0-9]{4}$'),
+    virtual BOOLEAN NOT NULL DEFAULT FALSE,
+    spend_limit DECIMAL(14, 2) CHECK (spend_limit > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_account FOREIGN KEY(account_id) REFERENCES accounts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_cards_account_id ON cards(account_id);
+
+-- The card a purchase was made with. Purchases of deleted cards stay on the
+-- account without a card.
+ALTER TABLE transactions
+ADD COLUMN card_id INT REFERENCES cards(id) ON DELETE SET NULL;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_transactions_fee_of_transaction_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS fee_of_transaction_id;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS chk_foreign_purchase;
+ALTER TABLE transactions
+DROP COLUMN IF EXISTS spread_rate,
+DROP COLUMN IF EXISTS exchange_rate,
+DROP COLUMN IF EXISTS original_amount,
+DROP COLUMN IF EXISTS original_currency;
@@ -0,0 +1,19 @@
+-- Purchases abroad keep the amount in their own currency and the rates they
+-- were converted with; amount stays in BRL. spread_rate is the bank's
+-- percentage over the exchange rate.
+ALTER TABLE transactions
+ADD COLUMN original_currency CHAR(3),
+ADD COLUMN original_amount DECIMAL(14, 2) CHECK (original_amount > 0),
+ADD COLUMN exchange_rate DECIMAL(14, 6) CHECK (exchange_rate > 0),
+ADD COLUMN spread_rate DECIMAL(6, 3) CHECK (spread_rate >= 0),
+ADD CONSTRAINT chk_foreign_purchase CHECK (
+    (original_currency IS NULL) = (original_amount IS NULL)
+    AND (original_currency IS NULL) = (exchange_rate IS NULL)
+);
+
+-- The IOF charged on a foreign purchase is a transaction of its own, linked to
+-- the purchase and deleted with it.
+ALTER TABLE transactions
+ADD COLUMN fee_of_transaction_id INT REFERENCES transactions(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_transactions_fee_of_transaction_id ON transactions(fee_of_transaction_id) WHERE fee_of_transaction_id IS NOT NULL;
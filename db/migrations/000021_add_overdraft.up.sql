@@ -0,0 +1,21 @@
+-- Checking accounts may have an overdraft (cheque especial): their balance can
+-- go down to -overdraft_limit, and the days spent below zero are charged
+-- overdraft_monthly_rate percent of interest a month.
+ALTER TABLE accounts
+    ADD COLUMN overdraft_limit DECIMAL(12, 2) CHECK (overdraft_limit > 0),
+    ADD COLUMN overdraft_monthly_rate DECIMAL(6, 3) CHECK (overdraft_monthly_rate >= 0);
+
+-- One row per account and month of overdraft interest, so a month is never
+-- charged twice. Months without interest are kept with a zero amount.
+CREATE TABLE overdraft_interest_charges (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    account_id INT NOT NULL,
+    year INT NOT NULL,
+    month INT NOT NULL CHECK (month BETWEEN 1 AND 12),
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_account FOREIGN KEY(account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    UNIQUE(account_id, year, month)
+);
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS yield_accruals;
+DROP TABLE IF EXISTS index_rates;
+
+ALTER TABLE accounts
+    DROP CONSTRAINT IF EXISTS chk_yield_frequency,
+    DROP CONSTRAINT IF EXISTS chk_yield_type,
+    DROP COLUMN IF EXISTS yield_withholds_ir,
+    DROP COLUMN IF EXISTS yield_start_date,
+    DROP COLUMN IF EXISTS yield_frequency,
+    DROP COLUMN IF EXISTS yield_rate,
+    DROP COLUMN IF EXISTS yield_type;
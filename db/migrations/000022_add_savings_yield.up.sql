@@ -0,0 +1,39 @@
+-- Savings accounts may earn yield: yield_rate percent a year for fixed yields,
+-- yield_rate percent of the CDI for cdi yields, or the poupança rule, which
+-- follows the Selic. The yield is posted as income every yield_frequency,
+-- starting on yield_start_date, with the income tax (IR) optionally withheld.
+ALTER TABLE accounts
+    ADD COLUMN yield_type VARCHAR(20),
+    ADD COLUMN yield_rate DECIMAL(7, 3) CHECK (yield_rate > 0),
+    ADD COLUMN yield_frequency VARCHAR(10),
+    ADD COLUMN yield_start_date DATE,
+    ADD COLUMN yield_withholds_ir BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD CONSTRAINT chk_yield_type CHECK (yield_type IN ('fixed', 'cdi', 'poupanca')),
+    ADD CONSTRAINT chk_yield_frequency CHECK (yield_frequency IN ('daily', 'weekly', 'monthly'));
+
+-- Daily CDI and Selic rates, in percent a year, loaded by the instance admins.
+-- Only business days have a rate.
+CREATE TABLE index_rates (
+    name VARCHAR(10) NOT NULL,
+    date DATE NOT NULL,
+    annual_rate DECIMAL(8, 4) NOT NULL CHECK (annual_rate >= 0),
+    PRIMARY KEY (name, date),
+    CONSTRAINT chk_index_name CHECK (name IN ('cdi', 'selic'))
+);
+
+-- One row per account and period of posted yield, so a period is never posted
+-- twice. Periods without yield are kept with a zero amount.
+CREATE TABLE yield_accruals (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    account_id INT NOT NULL,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    gross_amount DECIMAL(14, 2) NOT NULL CHECK (gross_amount >= 0),
+    income_tax_amount DECIMAL(14, 2) NOT NULL DEFAULT 0 CHECK (income_tax_amount >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_account FOREIGN KEY(account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    CONSTRAINT chk_yield_period CHECK (period_end > period_start),
+    UNIQUE(account_id, period_start)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS savings_rule_runs;
+DROP TABLE IF EXISTS savings_rules;
@@ -0,0 +1,49 @@
+-- Savings rules move money into a savings account automatically. Round-up
+-- rules round each expense of account_id up to the next whole unit and move
+-- the differences of each day from funding_account_id. Percent-of-income rules
+-- move percent of each income in category_id, optionally only the ones of
+-- account_id, from the account the income arrived in.
+CREATE TABLE savings_rules (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    savings_account_id INT NOT NULL,
+    account_id INT,
+    funding_account_id INT,
+    category_id INT,
+    percent DECIMAL(5, 2) CHECK (percent > 0 AND percent <= 100),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_savings_account FOREIGN KEY(savings_account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_account FOREIGN KEY(account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_funding_account FOREIGN KEY(funding_account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_category FOREIGN KEY(category_id) REFERENCES categories(id) ON DELETE CASCADE,
+    CONSTRAINT chk_savings_rule_type CHECK (type IN ('round_up', 'percent_of_income')),
+    CONSTRAINT chk_round_up_rule CHECK (type <> 'round_up' OR (account_id IS NOT NULL AND funding_account_id IS NOT NULL)),
+    CONSTRAINT chk_percent_of_income_rule CHECK (type <> 'percent_of_income' OR (category_id IS NOT NULL AND percent IS NOT NULL))
+);
+
+CREATE INDEX idx_savings_rules_user_id ON savings_rules(user_id);
+
+-- The log of what each rule did with the transactions that triggered it. A
+-- transaction triggers a rule once. Round-ups wait here until the daily
+-- settlement moves them, together with the others of the same day, in one
+-- transfer; percent-of-income runs are settled with their own transfer.
+CREATE TABLE savings_rule_runs (
+    id SERIAL PRIMARY KEY,
+    rule_id INT NOT NULL,
+    user_id INT NOT NULL,
+    transaction_id INT NOT NULL,
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount > 0),
+    occurred_on DATE NOT NULL,
+    transfer_id INT,
+    settled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_rule FOREIGN KEY(rule_id) REFERENCES savings_rules(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_transaction FOREIGN KEY(transaction_id) REFERENCES transactions(id) ON DELETE CASCADE,
+    CONSTRAINT fk_transfer FOREIGN KEY(transfer_id) REFERENCES transactions(id) ON DELETE SET NULL,
+    UNIQUE(rule_id, transaction_id)
+);
+
+CREATE INDEX idx_savings_rule_runs_pending ON savings_rule_runs(rule_id, occurred_on) WHERE settled_at IS NULL;
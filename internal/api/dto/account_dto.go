@@ -2,6 +2,7 @@ package dto
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
@@ -10,11 +11,43 @@ import (
 
 type AccountRequest struct {
 	Name                string            `json:"name" binding:"required,min=1,max=100" example:"Nubank Account" minLength:"2" maxLength:"100"`
-	Type                model.AccountType `json:"type" binding:"required,oneof=checking savings credit_card other" example:"checking" enums:"checking,savings,credit_card,other"`
+	Type                model.AccountType `json:"type" binding:"required,oneof=checking savings credit_card other points benefit asset" example:"checking" enums:"checking,savings,credit_card,other,points,benefit,asset"`
 	InitialBalance      *decimal.Decimal  `json:"initial_balance" binding:"required" example:"1000.50"`
 	CreditLimit         *decimal.Decimal  `json:"credit_limit,omitempty" binding:"omitempty" example:"5000.00"`
 	StatementClosingDay *int              `json:"statement_closing_day,omitempty" binding:"omitempty" example:"28"`
 	PaymentDueDay       *int              `json:"payment_due_day,omitempty" binding:"omitempty" example:"5" `
+	// BillingCycleEffectiveFrom is the YYYY-MM-DD date new closing and due days
+	// take effect from when a card is updated; it defaults to today.
+	BillingCycleEffectiveFrom string `json:"billing_cycle_effective_from,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2025-03-01"`
+	// PointsValuePerThousand is the estimated worth of 1,000 points, only for points accounts.
+	PointsValuePerThousand *decimal.Decimal `json:"points_value_per_thousand,omitempty" binding:"omitempty" example:"35.00"`
+	// Benefit fields configure meal and food voucher accounts. An empty category
+	// list allows any expense; the credit is scheduled when amount and day are set.
+	BenefitAllowedCategoryIds []int64          `json:"benefit_allowed_category_ids,omitempty" binding:"omitempty,dive,gt=0" example:"3,7"`
+	BenefitMonthlyCredit      *decimal.Decimal `json:"benefit_monthly_credit,omitempty" binding:"omitempty" example:"800.00"`
+	BenefitCreditDay          *int             `json:"benefit_credit_day,omitempty" binding:"omitempty" example:"1"`
+	BenefitExpiresUnused      bool             `json:"benefit_expires_unused,omitempty" example:"false"`
+	// Asset fields describe cars, property and other physical assets. The purchase
+	// date uses the YYYY-MM-DD format.
+	AssetPurchaseValue          *decimal.Decimal            `json:"asset_purchase_value,omitempty" binding:"omitempty" example:"90000.00"`
+	AssetPurchaseDate           string                      `json:"asset_purchase_date,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2024-03-15"`
+	AssetValuationMethod        *model.AssetValuationMethod `json:"asset_valuation_method,omitempty" binding:"omitempty,oneof=manual straight_line declining_balance" enums:"manual,straight_line,declining_balance"`
+	AssetUsefulLifeMonths       *int                        `json:"asset_useful_life_months,omitempty" binding:"omitempty,min=1" example:"120"`
+	AssetAnnualDepreciationRate *decimal.Decimal            `json:"asset_annual_depreciation_rate,omitempty" binding:"omitempty" example:"15"`
+	AssetSalvageValue           *decimal.Decimal            `json:"asset_salvage_value,omitempty" binding:"omitempty" example:"20000.00"`
+	// Overdraft fields configure the cheque especial of checking accounts. The
+	// monthly rate is the interest percentage charged on the days below zero.
+	OverdraftLimit       *decimal.Decimal `json:"overdraft_limit,omitempty" binding:"omitempty" example:"2000.00"`
+	OverdraftMonthlyRate *decimal.Decimal `json:"overdraft_monthly_rate,omitempty" binding:"omitempty" example:"7.9"`
+	// Yield fields configure how savings accounts earn interest. The rate is a
+	// yearly percentage for fixed yields and a percentage of the CDI for cdi
+	// ones; the start date uses the YYYY-MM-DD format and defaults to the
+	// account's creation.
+	YieldType        *model.YieldType      `json:"yield_type,omitempty" binding:"omitempty,oneof=fixed cdi poupanca" enums:"fixed,cdi,poupanca"`
+	YieldRate        *decimal.Decimal      `json:"yield_rate,omitempty" binding:"omitempty" example:"110"`
+	YieldFrequency   *model.YieldFrequency `json:"yield_frequency,omitempty" binding:"omitempty,oneof=daily weekly monthly" enums:"daily,weekly,monthly"`
+	YieldStartDate   string                `json:"yield_start_date,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2025-01-15"`
+	YieldWithholdsIR bool                  `json:"yield_withholds_ir,omitempty" example:"true"`
 }
 
 // Validate contains the custom, struct-level validation logic for a AccountRequest.
@@ -53,16 +86,190 @@ func (req *AccountRequest) Validate(sl validator.StructLevel) {
 		if req.CreditLimit != nil {
 			sl.ReportError(req.CreditLimit, "credit_limit", "CreditLimit", "not_allowed_for_non_credit_card", "")
 		}
+		if req.BillingCycleEffectiveFrom != "" {
+			sl.ReportError(req.BillingCycleEffectiveFrom, "billing_cycle_effective_from", "BillingCycleEffectiveFrom", "not_allowed_for_non_credit_card", "")
+		}
+	}
+
+	if req.Type == model.Points {
+		// Points are credited through lots, so the account starts empty.
+		if req.InitialBalance != nil && !req.InitialBalance.IsZero() {
+			sl.ReportError(req.InitialBalance, "initial_balance", "InitialBalance", "zero_for_points", "")
+		}
+		if req.PointsValuePerThousand != nil && req.PointsValuePerThousand.IsNegative() {
+			sl.ReportError(req.PointsValuePerThousand, "points_value_per_thousand", "PointsValuePerThousand", "gte", "0")
+		}
+	} else if req.PointsValuePerThousand != nil {
+		sl.ReportError(req.PointsValuePerThousand, "points_value_per_thousand", "PointsValuePerThousand", "not_allowed_for_non_points", "")
+	}
+
+	if req.Type == model.Benefit {
+		// The monthly credit needs both the amount and the day it arrives.
+		if (req.BenefitMonthlyCredit == nil) != (req.BenefitCreditDay == nil) {
+			sl.ReportError(req.BenefitCreditDay, "benefit_credit_day", "BenefitCreditDay", "required_with_monthly_credit", "")
+		}
+		if req.BenefitMonthlyCredit != nil && !req.BenefitMonthlyCredit.IsPositive() {
+			sl.ReportError(req.BenefitMonthlyCredit, "benefit_monthly_credit", "BenefitMonthlyCredit", "gt", "0")
+		}
+		if req.BenefitCreditDay != nil && (*req.BenefitCreditDay <= 0 || *req.BenefitCreditDay > 31) {
+			sl.ReportError(req.BenefitCreditDay, "benefit_credit_day", "BenefitCreditDay", "day", strconv.Itoa(*req.BenefitCreditDay))
+		}
+	} else if len(req.BenefitAllowedCategoryIds) > 0 || req.BenefitMonthlyCredit != nil || req.BenefitCreditDay != nil || req.BenefitExpiresUnused {
+		sl.ReportError(req.BenefitMonthlyCredit, "benefit_monthly_credit", "BenefitMonthlyCredit", "not_allowed_for_non_benefit", "")
+	}
+
+	if req.Type == model.Checking {
+		if req.OverdraftLimit != nil && !req.OverdraftLimit.IsPositive() {
+			sl.ReportError(req.OverdraftLimit, "overdraft_limit", "OverdraftLimit", "gt", "0")
+		}
+		if rate := req.OverdraftMonthlyRate; rate != nil && (rate.IsNegative() || rate.GreaterThan(decimal.NewFromInt(100))) {
+			sl.ReportError(rate, "overdraft_monthly_rate", "OverdraftMonthlyRate", "percent", rate.String())
+		}
+	} else if req.OverdraftLimit != nil || req.OverdraftMonthlyRate != nil {
+		sl.ReportError(req.OverdraftLimit, "overdraft_limit", "OverdraftLimit", "not_allowed_for_non_checking", "")
+	}
+
+	if req.Type == model.Savings {
+		req.validateYield(sl)
+	} else if req.YieldType != nil || req.YieldRate != nil || req.YieldFrequency != nil || req.YieldStartDate != "" || req.YieldWithholdsIR {
+		sl.ReportError(req.YieldType, "yield_type", "YieldType", "not_allowed_for_non_savings", "")
+	}
+
+	if req.Type == model.Asset {
+		req.validateAsset(sl)
+	} else if req.AssetPurchaseValue != nil || req.AssetPurchaseDate != "" || req.AssetValuationMethod != nil ||
+		req.AssetUsefulLifeMonths != nil || req.AssetAnnualDepreciationRate != nil || req.AssetSalvageValue != nil {
+		sl.ReportError(req.AssetPurchaseValue, "asset_purchase_value", "AssetPurchaseValue", "not_allowed_for_non_asset", "")
+	}
+}
+
+// validateYield checks that the yield settings of a savings account fit its
+// yield type. Accounts without a yield type take no other yield setting.
+func (req *AccountRequest) validateYield(sl validator.StructLevel) {
+	if req.YieldType == nil {
+		if req.YieldRate != nil || req.YieldFrequency != nil || req.YieldStartDate != "" || req.YieldWithholdsIR {
+			sl.ReportError(req.YieldType, "yield_type", "YieldType", "required_with_yield", "")
+		}
+		return
+	}
+	if *req.YieldType == model.YieldPoupanca {
+		// Poupança follows the Selic and is exempt from income tax.
+		if req.YieldRate != nil {
+			sl.ReportError(req.YieldRate, "yield_rate", "YieldRate", "not_allowed_for_poupanca", "")
+		}
+		if req.YieldWithholdsIR {
+			sl.ReportError(req.YieldWithholdsIR, "yield_withholds_ir", "YieldWithholdsIR", "not_allowed_for_poupanca", "")
+		}
+		return
+	}
+	if req.YieldRate == nil {
+		sl.ReportError(req.YieldRate, "yield_rate", "YieldRate", "required_for_yield_type", string(*req.YieldType))
+	} else if !req.YieldRate.IsPositive() || req.YieldRate.GreaterThan(decimal.NewFromInt(1000)) {
+		sl.ReportError(req.YieldRate, "yield_rate", "YieldRate", "percent", req.YieldRate.String())
+	}
+}
+
+// validateAsset checks the purchase and the parameters the valuation method needs.
+func (req *AccountRequest) validateAsset(sl validator.StructLevel) {
+	// The value of an asset comes from valuations, so the account starts empty.
+	if req.InitialBalance != nil && !req.InitialBalance.IsZero() {
+		sl.ReportError(req.InitialBalance, "initial_balance", "InitialBalance", "zero_for_asset", "")
+	}
+	if req.AssetPurchaseValue == nil {
+		sl.ReportError(req.AssetPurchaseValue, "asset_purchase_value", "AssetPurchaseValue", "required_for_asset", "")
+	} else if !req.AssetPurchaseValue.IsPositive() {
+		sl.ReportError(req.AssetPurchaseValue, "asset_purchase_value", "AssetPurchaseValue", "gt", "0")
+	}
+	if req.AssetPurchaseDate == "" {
+		sl.ReportError(req.AssetPurchaseDate, "asset_purchase_date", "AssetPurchaseDate", "required_for_asset", "")
+	}
+	if req.AssetSalvageValue != nil {
+		if req.AssetSalvageValue.IsNegative() {
+			sl.ReportError(req.AssetSalvageValue, "asset_salvage_value", "AssetSalvageValue", "gte", "0")
+		} else if req.AssetPurchaseValue != nil && req.AssetSalvageValue.GreaterThanOrEqual(*req.AssetPurchaseValue) {
+			sl.ReportError(req.AssetSalvageValue, "asset_salvage_value", "AssetSalvageValue", "ltfield", "AssetPurchaseValue")
+		}
+	}
+
+	if req.AssetValuationMethod == nil {
+		sl.ReportError(req.AssetValuationMethod, "asset_valuation_method", "AssetValuationMethod", "required_for_asset", "")
+		return
+	}
+	switch *req.AssetValuationMethod {
+	case model.AssetStraightLine:
+		if req.AssetUsefulLifeMonths == nil {
+			sl.ReportError(req.AssetUsefulLifeMonths, "asset_useful_life_months", "AssetUsefulLifeMonths", "required_for_straight_line", "")
+		}
+	case model.AssetDecliningBalance:
+		rate := req.AssetAnnualDepreciationRate
+		if rate == nil {
+			sl.ReportError(rate, "asset_annual_depreciation_rate", "AssetAnnualDepreciationRate", "required_for_declining_balance", "")
+		} else if !rate.IsPositive() || rate.GreaterThan(decimal.NewFromInt(100)) {
+			sl.ReportError(rate, "asset_annual_depreciation_rate", "AssetAnnualDepreciationRate", "percent", rate.String())
+		}
 	}
 }
 
 type AccountResponse struct {
-	Id                  int64             `json:"id,omitempty"`
-	Name                string            `json:"name,omitempty"`
-	Type                model.AccountType `json:"type,omitempty"`
-	Balance             *decimal.Decimal  `json:"balance,omitempty"`
-	InitialBalance      *decimal.Decimal  `json:"initial_balance,omitempty"`
-	CreditLimit         *decimal.Decimal  `json:"credit_limit,omitempty"`
-	PaymentDueDay       *int              `json:"due_day,omitempty"`
-	StatementClosingDay *int              `json:"closing_day,omitempty"`
+	Id                     int64             `json:"id,omitempty"`
+	Name                   string            `json:"name,omitempty"`
+	Type                   model.AccountType `json:"type,omitempty"`
+	Balance                *decimal.Decimal  `json:"balance,omitempty"`
+	InitialBalance         *decimal.Decimal  `json:"initial_balance,omitempty"`
+	CreditLimit            *decimal.Decimal  `json:"credit_limit,omitempty"`
+	PaymentDueDay          *int              `json:"due_day,omitempty"`
+	StatementClosingDay    *int              `json:"closing_day,omitempty"`
+	PointsValuePerThousand *decimal.Decimal  `json:"points_value_per_thousand,omitempty"`
+	// Benefit fields are only set for benefit accounts.
+	BenefitAllowedCategoryIds []int64          `json:"benefit_allowed_category_ids,omitempty"`
+	BenefitMonthlyCredit      *decimal.Decimal `json:"benefit_monthly_credit,omitempty"`
+	BenefitCreditDay          *int             `json:"benefit_credit_day,omitempty"`
+	BenefitExpiresUnused      bool             `json:"benefit_expires_unused,omitempty"`
+	// Asset fields are only set for asset accounts.
+	AssetPurchaseValue          *decimal.Decimal            `json:"asset_purchase_value,omitempty"`
+	AssetPurchaseDate           *string                     `json:"asset_purchase_date,omitempty"`
+	AssetValuationMethod        *model.AssetValuationMethod `json:"asset_valuation_method,omitempty"`
+	AssetUsefulLifeMonths       *int                        `json:"asset_useful_life_months,omitempty"`
+	AssetAnnualDepreciationRate *decimal.Decimal            `json:"asset_annual_depreciation_rate,omitempty"`
+	AssetSalvageValue           *decimal.Decimal            `json:"asset_salvage_value,omitempty"`
+	// Overdraft fields are only set for checking accounts.
+	OverdraftLimit       *decimal.Decimal `json:"overdraft_limit,omitempty"`
+	OverdraftMonthlyRate *decimal.Decimal `json:"overdraft_monthly_rate,omitempty"`
+	// Yield fields are only set for savings accounts that earn yield.
+	YieldType        *model.YieldType      `json:"yield_type,omitempty"`
+	YieldRate        *decimal.Decimal      `json:"yield_rate,omitempty"`
+	YieldFrequency   *model.YieldFrequency `json:"yield_frequency,omitempty"`
+	YieldStartDate   *string               `json:"yield_start_date,omitempty"`
+	YieldWithholdsIR bool                  `json:"yield_withholds_ir,omitempty"`
+}
+
+// AccountDeletionImpactResponse previews what deleting an account does to the
+// user's other accounts under a deletion policy.
+type AccountDeletionImpactResponse struct {
+	Policy string `json:"policy" example:"delete"`
+	// DeletedTransactions is how many transactions are deleted with the account.
+	DeletedTransactions int64                    `json:"deleted_transactions"`
+	Transfers           []DeletionImpactTransfer `json:"transfers"`
+	BalanceChanges      []DeletionImpactBalance  `json:"balance_changes"`
+}
+
+// DeletionImpactTransfer is a transfer between the account being deleted and
+// another account. Outcome is deleted, or income or expense when the transfer
+// is kept on the counterpart account.
+type DeletionImpactTransfer struct {
+	Id                   int64           `json:"id"`
+	Description          string          `json:"description"`
+	Amount               decimal.Decimal `json:"amount"`
+	Date                 time.Time       `json:"date"`
+	CounterpartAccountId int64           `json:"counterpart_account_id"`
+	Outcome              string          `json:"outcome" example:"deleted"`
+}
+
+// DeletionImpactBalance is how the balance of another account changes.
+type DeletionImpactBalance struct {
+	AccountId      int64           `json:"account_id"`
+	AccountName    string          `json:"account_name"`
+	CurrentBalance decimal.Decimal `json:"current_balance"`
+	Change         decimal.Decimal `json:"change"`
+	BalanceAfter   decimal.Decimal `json:"balance_after"`
 }
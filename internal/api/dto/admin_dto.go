@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AdminUserResponse is the DTO for a user as seen by an instance admin.
+type AdminUserResponse struct {
+	Id                    int64      `json:"id"`
+	Name                  string     `json:"name"`
+	Email                 string     `json:"email"`
+	IsAdmin               bool       `json:"is_admin"`
+	DisabledAt            *time.Time `json:"disabled_at,omitempty"`
+	PasswordResetRequired bool       `json:"password_reset_required"`
+	CreatedAt             time.Time  `json:"created_at"`
+}
+
+// ForcePasswordResetResponse carries the temporary password the admin must hand over.
+type ForcePasswordResetResponse struct {
+	TemporaryPassword string `json:"temporary_password"`
+}
+
+// UserUsageResponse is the DTO for a user's data usage.
+type UserUsageResponse struct {
+	UserId       int64 `json:"user_id"`
+	Accounts     int64 `json:"accounts"`
+	Transactions int64 `json:"transactions"`
+	Categories   int64 `json:"categories"`
+	Budgets      int64 `json:"budgets"`
+	StorageBytes int64 `json:"storage_bytes"`
+}
+
+// JobStatusResponse is the DTO for the status of a background job.
+type JobStatusResponse struct {
+	Name         string     `json:"name"`
+	Interval     string     `json:"interval"`
+	Running      bool       `json:"running"`
+	RunCount     int        `json:"run_count"`
+	FailureCount int        `json:"failure_count"`
+	LastRunAt    *time.Time `json:"last_run_at,omitempty"`
+	LastDuration string     `json:"last_duration"`
+	LastError    string     `json:"last_error,omitempty"`
+	NextRunAt    *time.Time `json:"next_run_at,omitempty"`
+}
+
+// MigrationStatusResponse is the DTO for the database schema version.
+type MigrationStatusResponse struct {
+	Version       uint `json:"version"`
+	LatestVersion uint `json:"latest_version"`
+	Dirty         bool `json:"dirty"`
+	UpToDate      bool `json:"up_to_date"`
+}
+
+// AuditLogEntryResponse is the DTO for an entry of the admin audit trail.
+type AuditLogEntryResponse struct {
+	Id           int64           `json:"id"`
+	AdminId      *int64          `json:"admin_id,omitempty"`
+	Action       string          `json:"action"`
+	TargetUserId *int64          `json:"target_user_id,omitempty"`
+	Details      json.RawMessage `json:"details"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
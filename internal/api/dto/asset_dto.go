@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// RevalueAssetRequest sets the value of an asset on a date. valued_on uses the
+// YYYY-MM-DD format and defaults to today.
+type RevalueAssetRequest struct {
+	Value    decimal.Decimal `json:"value" binding:"required" example:"82000.00"`
+	ValuedOn string          `json:"valued_on,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2025-07-01"`
+	Note     string          `json:"note,omitempty" binding:"max=255" example:"FIPE table"`
+}
+
+// AssetSummaryResponse is the current value of an asset and its valuations.
+type AssetSummaryResponse struct {
+	AccountId       int64                       `json:"account_id"`
+	Name            string                      `json:"name"`
+	PurchaseValue   *decimal.Decimal            `json:"purchase_value,omitempty"`
+	PurchaseDate    *string                     `json:"purchase_date,omitempty"`
+	ValuationMethod *model.AssetValuationMethod `json:"valuation_method,omitempty"`
+	CurrentValue    decimal.Decimal             `json:"current_value"`
+	Valuations      []model.AssetValuation      `json:"valuations"`
+}
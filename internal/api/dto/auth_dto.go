@@ -8,3 +8,13 @@ type LoginRequest struct {
 type LoginResponse struct {
 	Token string `json:"token"`
 }
+
+// MagicLinkRequest is the body for requesting a login link by e-mail.
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// MessageResponse is a generic response carrying only a human-readable message.
+type MessageResponse struct {
+	Message string `json:"message"`
+}
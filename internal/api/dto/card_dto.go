@@ -0,0 +1,12 @@
+package dto
+
+import "github.com/shopspring/decimal"
+
+// CardRequest describes an additional or virtual card of a credit card
+// account. spend_limit caps what the card may spend in each statement.
+type CardRequest struct {
+	HolderName string           `json:"holder_name" binding:"required,max=100" example:"Maria Silva"`
+	LastFour   string           `json:"last_four" binding:"required,len=4,numeric" example:"4321"`
+	Virtual    bool             `json:"virtual,omitempty" example:"true"`
+	SpendLimit *decimal.Decimal `json:"spend_limit,omitempty" example:"1500.00"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+// InviteCollaboratorRequest offers read-only access to some accounts. Dates use
+// the YYYY-MM-DD format, are inclusive and may be omitted to leave the period open.
+type InviteCollaboratorRequest struct {
+	Email      string  `json:"email" binding:"required,email" example:"advisor@example.com"`
+	AccountIds []int64 `json:"account_ids" binding:"required,min=1"`
+	StartDate  string  `json:"start_date,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2025-01-01"`
+	EndDate    string  `json:"end_date,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2025-12-31"`
+}
+
+// CollaboratorGrantResponse describes a grant to its owner or to its collaborator.
+type CollaboratorGrantResponse struct {
+	Id             int64      `json:"id"`
+	OwnerId        int64      `json:"owner_id"`
+	CollaboratorId *int64     `json:"collaborator_id,omitempty"`
+	InviteeEmail   string     `json:"invitee_email"`
+	AccountIds     []int64    `json:"account_ids"`
+	StartDate      *string    `json:"start_date,omitempty" example:"2025-01-01"`
+	EndDate        *string    `json:"end_date,omitempty" example:"2025-12-31"`
+	Status         string     `json:"status" example:"active"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -19,4 +19,38 @@ type StatementResponse struct {
 	PaymentDueDate time.Time             `json:"payment_due_date"`
 	Period         StatementPeriod       `json:"period"`
 	Transactions   []TransactionResponse `json:"transactions"` // We reuse the existing TransactionResponse DTO
+	Cards          []StatementCardTotal  `json:"cards"`
+	// ForeignPurchases groups the purchases made abroad by currency.
+	ForeignPurchases []StatementForeignCurrency `json:"foreign_purchases"`
+}
+
+// StatementCardTotal is what one card spent in a statement. Purchases made
+// without an additional card have no card_id.
+type StatementCardTotal struct {
+	CardId     *int64          `json:"card_id,omitempty"`
+	HolderName *string         `json:"holder_name,omitempty"`
+	LastFour   *string         `json:"last_four,omitempty"`
+	Total      decimal.Decimal `json:"total"`
+}
+
+// StatementForeignCurrency adds up the purchases of a statement made in one
+// currency. Total is in BRL, without the IOF charged on the purchases.
+type StatementForeignCurrency struct {
+	Currency      string                     `json:"currency" example:"USD"`
+	OriginalTotal decimal.Decimal            `json:"original_total"`
+	Total         decimal.Decimal            `json:"total"`
+	IOF           decimal.Decimal            `json:"iof"`
+	Purchases     []StatementForeignPurchase `json:"purchases"`
+}
+
+// StatementForeignPurchase is a purchase abroad with the rates it was converted at.
+type StatementForeignPurchase struct {
+	TransactionId  int64           `json:"transaction_id"`
+	Description    string          `json:"description"`
+	Date           time.Time       `json:"date"`
+	OriginalAmount decimal.Decimal `json:"original_amount"`
+	ExchangeRate   decimal.Decimal `json:"exchange_rate"`
+	SpreadRate     decimal.Decimal `json:"spread_rate"`
+	Amount         decimal.Decimal `json:"amount"`
+	IOF            decimal.Decimal `json:"iof"`
 }
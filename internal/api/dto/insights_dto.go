@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// HealthMetricResponse is one ratio of the financial health report. value and
+// score are omitted when there is not enough data to compute them. The
+// emergency fund is in months of expenses; the other ratios are percentages.
+type HealthMetricResponse struct {
+	Name           string           `json:"name" example:"emergency_fund"`
+	Value          *decimal.Decimal `json:"value,omitempty" example:"4.5"`
+	Score          *int             `json:"score,omitempty" example:"75"`
+	PreviousValue  *decimal.Decimal `json:"previous_value,omitempty" example:"3.2"`
+	PreviousScore  *int             `json:"previous_score,omitempty" example:"53"`
+	Trend          string           `json:"trend" example:"improving"`
+	Recommendation string           `json:"recommendation" example:"grow_emergency_fund"`
+}
+
+// HealthResponse scores the user's finances from 0 to 100.
+type HealthResponse struct {
+	Score       int                    `json:"score" example:"68"`
+	PeriodStart time.Time              `json:"period_start"`
+	PeriodEnd   time.Time              `json:"period_end"`
+	Metrics     []HealthMetricResponse `json:"metrics"`
+}
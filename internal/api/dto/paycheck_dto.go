@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// PaycheckLineRequest is one earning or deduction of a paycheck.
+type PaycheckLineRequest struct {
+	Kind        model.PaycheckLineKind `json:"kind" binding:"required,oneof=earning deduction" example:"deduction"`
+	Description string                 `json:"description" binding:"required,max=100" example:"INSS"`
+	CategoryId  *int64                 `json:"category_id,omitempty"`
+	Amount      decimal.Decimal        `json:"amount" binding:"required" example:"908.85"`
+}
+
+// PaycheckTemplateRequest describes a salary. Templates with a pay_day are
+// posted automatically on that day of every month.
+type PaycheckTemplateRequest struct {
+	Name             string                `json:"name" binding:"required,max=100" example:"ACME salary"`
+	AccountId        int64                 `json:"account_id" binding:"required"`
+	IncomeCategoryId *int64                `json:"income_category_id,omitempty"`
+	PayDay           *int                  `json:"pay_day,omitempty" binding:"omitempty,min=1,max=31" example:"5"`
+	Active           *bool                 `json:"active,omitempty" example:"true"`
+	Lines            []PaycheckLineRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+// PostPaycheckRequest pays a template for a month. Lines replace the template's
+// for variable months; pay_date uses the YYYY-MM-DD format.
+type PostPaycheckRequest struct {
+	Kind    model.PaycheckKind    `json:"kind,omitempty" binding:"omitempty,oneof=regular thirteenth vacation bonus" example:"thirteenth"`
+	Year    int                   `json:"year" binding:"required,min=2000" example:"2025"`
+	Month   int                   `json:"month" binding:"required,min=1,max=12" example:"11"`
+	PayDate string                `json:"pay_date,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2025-11-28"`
+	Lines   []PaycheckLineRequest `json:"lines,omitempty" binding:"omitempty,min=1,dive"`
+}
+
+// PaycheckLineTotalResponse adds up the lines with the same kind, description
+// and category over a year.
+type PaycheckLineTotalResponse struct {
+	Kind        model.PaycheckLineKind `json:"kind"`
+	Description string                 `json:"description"`
+	CategoryId  *int64                 `json:"category_id,omitempty"`
+	Amount      decimal.Decimal        `json:"amount"`
+}
+
+// PaycheckSummaryResponse totals a year of paychecks.
+type PaycheckSummaryResponse struct {
+	Year       int                         `json:"year"`
+	Paychecks  int                         `json:"paychecks"`
+	Gross      decimal.Decimal             `json:"gross"`
+	Deductions decimal.Decimal             `json:"deductions"`
+	Net        decimal.Decimal             `json:"net"`
+	Lines      []PaycheckLineTotalResponse `json:"lines"`
+}
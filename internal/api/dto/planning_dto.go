@@ -0,0 +1,126 @@
+package dto
+
+import (
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// CategoryChangeRequest scales the recurring items of a category, such as 10
+// for a rent that goes up 10%.
+type CategoryChangeRequest struct {
+	CategoryId int64           `json:"category_id" binding:"required"`
+	Percent    decimal.Decimal `json:"percent" example:"10"`
+}
+
+// ScenarioRecurringItemRequest adds a monthly income or expense. Months are
+// counted from 1, the next calendar month; start_month defaults to 1.
+type ScenarioRecurringItemRequest struct {
+	Description string                `json:"description" binding:"max=100" example:"Gym"`
+	Type        model.TransactionType `json:"type" binding:"required,oneof=income expense" example:"expense"`
+	Amount      decimal.Decimal       `json:"amount" binding:"required" example:"120.00"`
+	StartMonth  int                   `json:"start_month,omitempty" binding:"omitempty,min=1" example:"1"`
+	EndMonth    *int                  `json:"end_month,omitempty" binding:"omitempty,min=1" example:"12"`
+}
+
+// ScenarioPurchaseRequest is a one-off expense in a month of the projection.
+type ScenarioPurchaseRequest struct {
+	Description string          `json:"description" binding:"max=100" example:"Vacation"`
+	Amount      decimal.Decimal `json:"amount" binding:"required" example:"6000.00"`
+	Month       int             `json:"month" binding:"required,min=1" example:"7"`
+}
+
+// ScenarioLoanRequest finances a purchase in equal installments. asset_value is
+// what the item bought is worth and defaults to the principal.
+type ScenarioLoanRequest struct {
+	Description         string           `json:"description" binding:"max=100" example:"Car"`
+	Principal           decimal.Decimal  `json:"principal" binding:"required" example:"60000.00"`
+	MonthlyInterestRate decimal.Decimal  `json:"monthly_interest_rate" example:"1.49"`
+	Installments        int              `json:"installments" binding:"required,min=1,max=480" example:"48"`
+	StartMonth          int              `json:"start_month,omitempty" binding:"omitempty,min=1" example:"1"`
+	AssetValue          *decimal.Decimal `json:"asset_value,omitempty" example:"60000.00"`
+}
+
+// ScenarioRequest describes the hypothetical changes of a what-if scenario.
+type ScenarioRequest struct {
+	Months              int                            `json:"months,omitempty" binding:"omitempty,min=1,max=120" example:"24"`
+	IncomeChangePercent decimal.Decimal                `json:"income_change_percent" example:"0"`
+	CategoryChanges     []CategoryChangeRequest        `json:"category_changes,omitempty" binding:"omitempty,dive"`
+	RemoveCategoryIds   []int64                        `json:"remove_category_ids,omitempty"`
+	AddRecurring        []ScenarioRecurringItemRequest `json:"add_recurring,omitempty" binding:"omitempty,dive"`
+	Purchases           []ScenarioPurchaseRequest      `json:"purchases,omitempty" binding:"omitempty,dive"`
+	Loans               []ScenarioLoanRequest          `json:"loans,omitempty" binding:"omitempty,dive"`
+}
+
+// RecurringItemResponse is a monthly income or expense of the baseline.
+type RecurringItemResponse struct {
+	Type         model.TransactionType `json:"type"`
+	CategoryId   *int64                `json:"category_id,omitempty"`
+	CategoryName string                `json:"category_name"`
+	Amount       decimal.Decimal       `json:"amount"`
+}
+
+// ScenarioMonthResponse compares the scenario with the baseline at the end of a month.
+type ScenarioMonthResponse struct {
+	Year             int             `json:"year"`
+	Month            int             `json:"month"`
+	BaselineBalance  decimal.Decimal `json:"baseline_balance"`
+	ScenarioBalance  decimal.Decimal `json:"scenario_balance"`
+	BalanceDelta     decimal.Decimal `json:"balance_delta"`
+	BaselineNetWorth decimal.Decimal `json:"baseline_net_worth"`
+	ScenarioNetWorth decimal.Decimal `json:"scenario_net_worth"`
+	NetWorthDelta    decimal.Decimal `json:"net_worth_delta"`
+	LoanBalance      decimal.Decimal `json:"loan_balance"`
+}
+
+// ScenarioResponse is the month-by-month projection of a what-if scenario.
+type ScenarioResponse struct {
+	StartingBalance    decimal.Decimal         `json:"starting_balance"`
+	StartingNetWorth   decimal.Decimal         `json:"starting_net_worth"`
+	BaselineMonthlyNet decimal.Decimal         `json:"baseline_monthly_net"`
+	BaselineItems      []RecurringItemResponse `json:"baseline_items"`
+	FirstNegativeMonth *int                    `json:"first_negative_month,omitempty"`
+	Months             []ScenarioMonthResponse `json:"months"`
+}
+
+// RetirementRequest holds the accounts and assumptions of a retirement
+// projection. Rates are yearly percentages. monthly_contribution and
+// monthly_expenses default to the averages of the last twelve months; seed
+// makes the Monte Carlo simulation reproducible.
+type RetirementRequest struct {
+	AccountIds          []int64          `json:"account_ids" binding:"required,min=1"`
+	AnnualReturn        decimal.Decimal  `json:"annual_return" binding:"required" example:"10"`
+	Inflation           decimal.Decimal  `json:"inflation" example:"4.5"`
+	WithdrawalRate      *decimal.Decimal `json:"withdrawal_rate,omitempty" example:"4"`
+	Volatility          *decimal.Decimal `json:"volatility,omitempty" example:"12"`
+	MonthlyContribution *decimal.Decimal `json:"monthly_contribution,omitempty" example:"2500.00"`
+	MonthlyExpenses     *decimal.Decimal `json:"monthly_expenses,omitempty" example:"6000.00"`
+	Years               int              `json:"years,omitempty" binding:"omitempty,min=1,max=80" example:"40"`
+	RetirementYears     int              `json:"retirement_years,omitempty" binding:"omitempty,min=1,max=80" example:"30"`
+	Simulations         int              `json:"simulations,omitempty" binding:"omitempty,min=1,max=10000" example:"1000"`
+	Seed                *uint64          `json:"seed,omitempty" example:"42"`
+}
+
+// RetirementYearResponse is the portfolio at the end of a year of the projection.
+type RetirementYearResponse struct {
+	Year           int             `json:"year"`
+	Contributions  decimal.Decimal `json:"contributions"`
+	Growth         decimal.Decimal `json:"growth"`
+	Balance        decimal.Decimal `json:"balance"`
+	NominalBalance decimal.Decimal `json:"nominal_balance"`
+	Independent    bool            `json:"independent"`
+}
+
+// RetirementResponse is a retirement projection. Amounts are in today's money
+// except nominal_balance; success_probability is a percentage.
+type RetirementResponse struct {
+	StartingBalance     decimal.Decimal          `json:"starting_balance"`
+	MonthlyContribution decimal.Decimal          `json:"monthly_contribution"`
+	MonthlyExpenses     decimal.Decimal          `json:"monthly_expenses"`
+	RealReturn          decimal.Decimal          `json:"real_return"`
+	Target              decimal.Decimal          `json:"target"`
+	IndependenceDate    *string                  `json:"independence_date,omitempty"`
+	SuccessProbability  decimal.Decimal          `json:"success_probability"`
+	Simulations         int                      `json:"simulations"`
+	Seed                uint64                   `json:"seed"`
+	Years               []RetirementYearResponse `json:"years"`
+}
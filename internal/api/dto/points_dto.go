@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// PointsRuleRequest links a credit card to a points account.
+type PointsRuleRequest struct {
+	CardAccountId    int64           `json:"card_account_id" binding:"required"`
+	PointsAccountId  int64           `json:"points_account_id" binding:"required"`
+	PointsPerUnit    decimal.Decimal `json:"points_per_unit" binding:"required" example:"2.5"`
+	ExpirationMonths *int            `json:"expiration_months,omitempty" binding:"omitempty,min=1" example:"24"`
+}
+
+// AccruePointsRequest selects the card statement, by due month, to credit points for.
+type AccruePointsRequest struct {
+	Year  int `json:"year" binding:"required,min=2000" example:"2025"`
+	Month int `json:"month" binding:"required,min=1,max=12" example:"7"`
+}
+
+// AddPointsRequest credits points that did not come from a statement. Dates use
+// the YYYY-MM-DD format; earned_at defaults to today.
+type AddPointsRequest struct {
+	Points      int64  `json:"points" binding:"required,min=1" example:"5000"`
+	EarnedAt    string `json:"earned_at,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2025-07-01"`
+	ExpiresAt   string `json:"expires_at,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2027-07-01"`
+	Description string `json:"description,omitempty" binding:"max=255" example:"Opening balance"`
+}
+
+// PointsTransferRequest moves points between programs. The destination receives
+// the points plus bonus_percent of them.
+type PointsTransferRequest struct {
+	FromAccountId int64           `json:"from_account_id" binding:"required"`
+	ToAccountId   int64           `json:"to_account_id" binding:"required"`
+	Points        int64           `json:"points" binding:"required,min=1" example:"10000"`
+	BonusPercent  decimal.Decimal `json:"bonus_percent" example:"80"`
+	ExpiresAt     string          `json:"expires_at,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2027-07-01"`
+}
+
+// PointsSummaryResponse is the current state of a points account.
+type PointsSummaryResponse struct {
+	AccountId      int64             `json:"account_id"`
+	Name           string            `json:"name"`
+	Balance        int64             `json:"balance"`
+	ExpiringSoon   int64             `json:"expiring_soon"`
+	EstimatedValue decimal.Decimal   `json:"estimated_value"`
+	Lots           []model.PointsLot `json:"lots"`
+}
+
+// PointsTransferResponse describes a transfer between programs.
+type PointsTransferResponse struct {
+	Id             int64           `json:"id"`
+	FromAccountId  int64           `json:"from_account_id"`
+	ToAccountId    int64           `json:"to_account_id"`
+	Points         int64           `json:"points"`
+	BonusPercent   decimal.Decimal `json:"bonus_percent"`
+	CreditedPoints int64           `json:"credited_points"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// ProjectCategoryBudgetRequest plans part of a project's budget for a category.
+type ProjectCategoryBudgetRequest struct {
+	CategoryId int64           `json:"category_id" binding:"required"`
+	Amount     decimal.Decimal `json:"amount" binding:"required" example:"15000.00"`
+}
+
+// ProjectRequest creates or replaces a project. Dates use the YYYY-MM-DD format.
+type ProjectRequest struct {
+	Name            string                         `json:"name" binding:"required,max=100" example:"Wedding"`
+	Description     *string                        `json:"description,omitempty" binding:"omitempty,max=255"`
+	TotalBudget     decimal.Decimal                `json:"total_budget" binding:"required" example:"60000.00"`
+	StartDate       string                         `json:"start_date" binding:"required,datetime=2006-01-02" example:"2025-01-01"`
+	EndDate         string                         `json:"end_date" binding:"required,datetime=2006-01-02" example:"2025-11-30"`
+	CategoryBudgets []ProjectCategoryBudgetRequest `json:"category_budgets,omitempty" binding:"omitempty,dive"`
+}
+
+// AssignProjectTransactionsRequest lists the transactions to assign to a project.
+type AssignProjectTransactionsRequest struct {
+	TransactionIds []int64 `json:"transaction_ids" binding:"required,min=1"`
+}
+
+// ProjectResponse describes a project.
+type ProjectResponse struct {
+	Id              int64                         `json:"id"`
+	Name            string                        `json:"name"`
+	Description     *string                       `json:"description,omitempty"`
+	TotalBudget     decimal.Decimal               `json:"total_budget"`
+	StartDate       string                        `json:"start_date"`
+	EndDate         string                        `json:"end_date"`
+	CategoryBudgets []model.ProjectCategoryBudget `json:"category_budgets"`
+}
+
+// ProjectCategoryReportResponse compares what was planned and spent in a category.
+type ProjectCategoryReportResponse struct {
+	CategoryId   *int64           `json:"category_id,omitempty"`
+	CategoryName string           `json:"category_name"`
+	Planned      *decimal.Decimal `json:"planned,omitempty"`
+	Spent        decimal.Decimal  `json:"spent"`
+}
+
+// ProjectMonthResponse is a project's spending in a month and up to it.
+type ProjectMonthResponse struct {
+	Year       int             `json:"year"`
+	Month      int             `json:"month"`
+	Spent      decimal.Decimal `json:"spent"`
+	Cumulative decimal.Decimal `json:"cumulative"`
+}
+
+// ProjectReportResponse compares a project's spending with its budget.
+type ProjectReportResponse struct {
+	Project    ProjectResponse                 `json:"project"`
+	Planned    decimal.Decimal                 `json:"planned"`
+	Spent      decimal.Decimal                 `json:"spent"`
+	Remaining  decimal.Decimal                 `json:"remaining"`
+	Categories []ProjectCategoryReportResponse `json:"categories"`
+	Timeline   []ProjectMonthResponse          `json:"timeline"`
+}
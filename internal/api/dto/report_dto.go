@@ -0,0 +1,106 @@
+package dto
+
+import (
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// CategoryTotalResponse is the money that went in and out of one category.
+type CategoryTotalResponse struct {
+	CategoryId   *int64          `json:"category_id,omitempty"`
+	CategoryName string          `json:"category_name"`
+	Income       decimal.Decimal `json:"income"`
+	Expense      decimal.Decimal `json:"expense"`
+}
+
+// MonthlyReportResponse summarizes the incomes and expenses of a calendar month.
+type MonthlyReportResponse struct {
+	Year             int                     `json:"year"`
+	Month            int                     `json:"month"`
+	Period           StatementPeriod         `json:"period"`
+	TotalIncome      decimal.Decimal         `json:"total_income"`
+	TotalExpense     decimal.Decimal         `json:"total_expense"`
+	CashExpense      decimal.Decimal         `json:"cash_expense"`
+	BenefitExpense   decimal.Decimal         `json:"benefit_expense"`
+	Net              decimal.Decimal         `json:"net"`
+	TransactionCount int                     `json:"transaction_count"`
+	Categories       []CategoryTotalResponse `json:"categories"`
+}
+
+// NetWorthItemResponse is what one account adds to the net worth. Points is
+// only set for points accounts, whose value is an estimate.
+type NetWorthItemResponse struct {
+	AccountId int64             `json:"account_id"`
+	Name      string            `json:"name"`
+	Type      model.AccountType `json:"type"`
+	Value     decimal.Decimal   `json:"value"`
+	Points    *int64            `json:"points,omitempty"`
+}
+
+// NetWorthResponse is the sum of everything the user owns minus what they owe.
+type NetWorthResponse struct {
+	Total decimal.Decimal        `json:"total"`
+	Items []NetWorthItemResponse `json:"items"`
+}
+
+// ReportQueryFilters narrows the transactions a report query looks at. Dates
+// are inclusive days.
+type ReportQueryFilters struct {
+	Description *string                 `json:"description,omitempty" example:"uber"`
+	StartDate   *string                 `json:"start_date,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2025-01-01"`
+	EndDate     *string                 `json:"end_date,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2025-06-30"`
+	Types       []model.TransactionType `json:"types,omitempty" binding:"omitempty,dive,oneof=income expense transfer" example:"expense"`
+	AccountIds  []int64                 `json:"account_ids,omitempty"`
+	CategoryIds []int64                 `json:"category_ids,omitempty"`
+	MinAmount   *decimal.Decimal        `json:"min_amount,omitempty" example:"10.00"`
+	MaxAmount   *decimal.Decimal        `json:"max_amount,omitempty" example:"500.00"`
+}
+
+// ReportQueryRequest asks for the filtered transactions grouped by up to three
+// dimensions (category, account, type, day, week, month, year, weekday), with
+// one or more measures (sum, count, avg, min, max) per group. A pivot needs
+// exactly two dimensions and one measure. Transactions have no parent
+// categories, payees or tags yet, so parent_category, payee and tag are rejected.
+type ReportQueryRequest struct {
+	Filters  ReportQueryFilters `json:"filters"`
+	GroupBy  []string           `json:"group_by" example:"month,category"`
+	Measures []string           `json:"measures" binding:"required" example:"sum,count"`
+	Pivot    bool               `json:"pivot" example:"false"`
+	Limit    int                `json:"limit,omitempty" binding:"omitempty,min=1,max=1000" example:"100"`
+}
+
+// ReportQueryRowResponse is one group: its key for each dimension and the
+// value of each measure, in the order they were asked for. Ids has the id of
+// the category or account of each key, and null for the other dimensions and
+// for transactions without a category.
+type ReportQueryRowResponse struct {
+	Keys   []string          `json:"keys"`
+	Ids    []*int64          `json:"ids"`
+	Values []decimal.Decimal `json:"values"`
+}
+
+// ReportPivotRowResponse is one key of the first dimension of a pivot. Values
+// are null where there were no transactions.
+type ReportPivotRowResponse struct {
+	Key    string             `json:"key"`
+	Id     *int64             `json:"id"`
+	Values []*decimal.Decimal `json:"values"`
+}
+
+// ReportPivotResponse has the keys of the first dimension down the rows and
+// those of the second across the columns, with the id of each like the rows.
+type ReportPivotResponse struct {
+	Columns   []string                 `json:"columns"`
+	ColumnIds []*int64                 `json:"column_ids"`
+	Rows      []ReportPivotRowResponse `json:"rows"`
+}
+
+// ReportQueryResponse is the result of a report query, as rows or as a pivot.
+// Truncated is set when there were more rows than the limit.
+type ReportQueryResponse struct {
+	GroupBy   []string                 `json:"group_by"`
+	Measures  []string                 `json:"measures"`
+	Rows      []ReportQueryRowResponse `json:"rows,omitempty"`
+	Pivot     *ReportPivotResponse     `json:"pivot,omitempty"`
+	Truncated bool                     `json:"truncated"`
+}
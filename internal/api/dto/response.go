@@ -12,7 +12,9 @@ import (
 
 // ErrorResponse is the standardized DTO for sending error messages.
 type ErrorResponse struct {
-	Error   string            `json:"error"`
+	Error string `json:"error"`
+	// Code is a stable, machine-readable identifier for errors clients are expected to handle.
+	Code    string            `json:"code,omitempty"`
 	Details map[string]string `json:"details,omitempty"`
 }
 
@@ -58,6 +60,16 @@ func SendError(c *gin.Context, code int, message string, details map[string]stri
 	c.AbortWithStatusJSON(code, response)
 }
 
+// SendCodedError sends an error response carrying a machine-readable code and aborts the request.
+func SendCodedError(c *gin.Context, code int, errorCode, message string, details map[string]string) {
+	response := ErrorResponse{
+		Error:   message,
+		Code:    errorCode,
+		Details: details,
+	}
+	c.AbortWithStatusJSON(code, response)
+}
+
 // formatValidationErrors is a private helper that translates validator errors
 // into a simple map[string]string for the API response.
 func formatValidationErrors(errs validator.ValidationErrors) map[string]string {
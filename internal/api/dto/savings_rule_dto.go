@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// SavingsRuleRequest creates a rule that saves money automatically. Round-up
+// rules need account_id, the checking or credit card account whose expenses
+// are rounded up, and funding_account_id when it is a card. Percent-of-income
+// rules need category_id and percent, and account_id limits them to one account.
+type SavingsRuleRequest struct {
+	Type             model.SavingsRuleType `json:"type" binding:"required,oneof=round_up percent_of_income" example:"round_up"`
+	SavingsAccountId int64                 `json:"savings_account_id" binding:"required"`
+	AccountId        *int64                `json:"account_id,omitempty"`
+	FundingAccountId *int64                `json:"funding_account_id,omitempty"`
+	CategoryId       *int64                `json:"category_id,omitempty"`
+	Percent          *decimal.Decimal      `json:"percent,omitempty" example:"10"`
+}
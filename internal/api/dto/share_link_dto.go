@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+)
+
+// CreateShareLinkRequest defines a new read-only share link. Year and month select
+// the report or statement, account_id the statement's credit card, and filter the
+// transactions to share.
+type CreateShareLinkRequest struct {
+	Scope          model.ShareLinkScope     `json:"scope" binding:"required,oneof=monthly_report statement transactions" example:"monthly_report"`
+	Year           int                      `json:"year,omitempty" example:"2025"`
+	Month          int                      `json:"month,omitempty" example:"7"`
+	AccountId      *int64                   `json:"account_id,omitempty"`
+	Filter         *model.TransactionFilter `json:"filter,omitempty"`
+	ExpiresInHours int                      `json:"expires_in_hours,omitempty" binding:"omitempty,min=1" example:"72"`
+	Password       string                   `json:"password,omitempty" binding:"omitempty,min=6"`
+}
+
+// ShareLinkResponse describes a share link to its owner. The token is never returned after creation.
+type ShareLinkResponse struct {
+	Id                int64                 `json:"id"`
+	Scope             model.ShareLinkScope  `json:"scope"`
+	Params            model.ShareLinkParams `json:"params"`
+	PasswordProtected bool                  `json:"password_protected"`
+	ExpiresAt         time.Time             `json:"expires_at"`
+	RevokedAt         *time.Time            `json:"revoked_at,omitempty"`
+	AccessCount       int64                 `json:"access_count"`
+	LastAccessedAt    *time.Time            `json:"last_accessed_at,omitempty"`
+	CreatedAt         time.Time             `json:"created_at"`
+}
+
+// CreateShareLinkResponse is returned once, when the link is created.
+type CreateShareLinkResponse struct {
+	ShareLinkResponse
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// SharedContentResponse is what the public share endpoint returns. Only the
+// field matching the scope is set.
+type SharedContentResponse struct {
+	Scope         model.ShareLinkScope   `json:"scope"`
+	ExpiresAt     time.Time              `json:"expires_at"`
+	MonthlyReport *MonthlyReportResponse `json:"monthly_report,omitempty"`
+	Statement     *StatementResponse     `json:"statement,omitempty"`
+	Transactions  []TransactionResponse  `json:"transactions,omitempty"`
+}
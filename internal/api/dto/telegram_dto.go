@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// TelegramLinkCodeResponse is the one-time code that links a Telegram chat to
+// the user. deep_link opens the bot with the code already filled in.
+type TelegramLinkCodeResponse struct {
+	Code      string    `json:"code" example:"K7QX2MZA"`
+	ExpiresAt time.Time `json:"expires_at"`
+	DeepLink  *string   `json:"deep_link,omitempty" example:"https://t.me/GoFinanceBot?start=K7QX2MZA"`
+}
@@ -15,6 +15,14 @@ type CreateTransactionRequest struct {
 	AccountId            int64                 `json:"account_id"`
 	CategoryId           *int64                `json:"category_id"`            // Opcional
 	DestinationAccountId *int64                `json:"destination_account_id"` // Opcional, mas necessário para transferências
+	CardId               *int64                `json:"card_id"`                // Opcional, cartão adicional da conta de cartão de crédito
+
+	// Compras internacionais no cartão de crédito. O amount é calculado a partir
+	// do valor original, do câmbio e do spread, e o IOF é lançado à parte.
+	OriginalCurrency *string          `json:"original_currency" example:"USD"`
+	OriginalAmount   *decimal.Decimal `json:"original_amount" example:"25.00"`
+	ExchangeRate     *decimal.Decimal `json:"exchange_rate" example:"5.4321"`
+	SpreadRate       *decimal.Decimal `json:"spread_rate" example:"4"`
 }
 
 type UpdateTransactionRequest struct {
@@ -25,6 +33,14 @@ type UpdateTransactionRequest struct {
 	AccountId            int64                 `json:"account_id" binding:"required"`
 	CategoryId           *int64                `json:"category_id"`
 	DestinationAccountId *int64                `json:"destination_account_id"`
+	CardId               *int64                `json:"card_id"`
+
+	// Compras internacionais: o amount é recalculado a partir destes campos e o
+	// IOF é lançado novamente. Sem eles, a compra deixa de ser convertida.
+	OriginalCurrency *string          `json:"original_currency" example:"USD"`
+	OriginalAmount   *decimal.Decimal `json:"original_amount" example:"25.00"`
+	ExchangeRate     *decimal.Decimal `json:"exchange_rate" example:"5.4321"`
+	SpreadRate       *decimal.Decimal `json:"spread_rate" example:"4"`
 }
 
 // PatchTransactionRequest define o corpo para uma atualização parcial de transação.
@@ -35,6 +51,13 @@ type PatchTransactionRequest struct {
 	Type        model.TransactionType `json:"type"`
 	AccountId   *int64                `json:"account_id"`
 	CategoryId  *int64                `json:"category_id"`
+
+	// Compras internacionais: o amount não pode ser alterado diretamente, e
+	// sim recalculado a partir destes campos, junto com o IOF.
+	OriginalCurrency *string          `json:"original_currency" example:"USD"`
+	OriginalAmount   *decimal.Decimal `json:"original_amount" example:"25.00"`
+	ExchangeRate     *decimal.Decimal `json:"exchange_rate" example:"5.4321"`
+	SpreadRate       *decimal.Decimal `json:"spread_rate" example:"4"`
 }
 
 // TransactionResponse é o DTO de resposta, com dados enriquecidos e prontos para o frontend.
@@ -49,5 +72,12 @@ type TransactionResponse struct {
 	CategoryId           *int64                `json:"category_id,omitempty"`
 	CategoryName         *string               `json:"category_name,omitempty"`
 	DestinationAccountId *int64                `json:"destination_account_id,omitempty"`
+	ProjectId            *int64                `json:"project_id,omitempty"`
+	CardId               *int64                `json:"card_id,omitempty"`
+	OriginalCurrency     *string               `json:"original_currency,omitempty"`
+	OriginalAmount       *decimal.Decimal      `json:"original_amount,omitempty"`
+	ExchangeRate         *decimal.Decimal      `json:"exchange_rate,omitempty"`
+	SpreadRate           *decimal.Decimal      `json:"spread_rate,omitempty"`
+	FeeOfTransactionId   *int64                `json:"fee_of_transaction_id,omitempty"`
 	CreatedAt            time.Time             `json:"created_at,omitempty"`
 }
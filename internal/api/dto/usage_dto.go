@@ -0,0 +1,17 @@
+package dto
+
+// QuotaMetricResponse is the consumption of a single quota. A limit of 0 means
+// unlimited, in which case remaining is omitted.
+type QuotaMetricResponse struct {
+	Used      int64  `json:"used"`
+	Limit     int64  `json:"limit"`
+	Remaining *int64 `json:"remaining,omitempty"`
+}
+
+// UsageResponse reports the logged-in user's consumption of each quota.
+type UsageResponse struct {
+	Accounts              QuotaMetricResponse `json:"accounts"`
+	TransactionsThisMonth QuotaMetricResponse `json:"transactions_this_month"`
+	StorageBytes          QuotaMetricResponse `json:"storage_bytes"`
+	RequestsToday         QuotaMetricResponse `json:"requests_today"`
+}
@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+)
 
 // CreateUserRequest é o DTO para a requisição de criação de usuário.
 // Contém apenas os campos que o cliente deve enviar, com as devidas validações.
@@ -13,8 +17,24 @@ type CreateUserRequest struct {
 // UserResponse é o DTO para a resposta de um usuário.
 // Contém apenas os campos públicos e seguros que a API deve retornar.
 type UserResponse struct {
-	Id        int64     `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
+	Id                  int64                  `json:"id"`
+	Name                string                 `json:"name"`
+	Email               string                 `json:"email"`
+	FiscalMonthStartDay int                    `json:"fiscal_month_start_day,omitempty"`
+	FiscalMonthLabel    model.FiscalMonthLabel `json:"fiscal_month_label,omitempty"`
+	CreatedAt           time.Time              `json:"created_at"`
+}
+
+// ChangePasswordRequest is the DTO for changing the logged-in user's password.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// FiscalMonthRequest sets the day the user's months start on. Label names each
+// month after the calendar month it starts or ends in; it defaults to the
+// current choice.
+type FiscalMonthRequest struct {
+	StartDay int                    `json:"start_day" binding:"required,min=1,max=31" example:"25"`
+	Label    model.FiscalMonthLabel `json:"label,omitempty" binding:"omitempty,oneof=start end" example:"end"`
 }
@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// IndexRatesRequest loads the daily rates of the CDI or the Selic. Rates
+// already loaded for the same days are replaced.
+type IndexRatesRequest struct {
+	Rates []IndexRateRequest `json:"rates" binding:"required,min=1,max=5000,dive"`
+}
+
+// IndexRateRequest is the rate of a business day, in percent a year, on a
+// YYYY-MM-DD date.
+type IndexRateRequest struct {
+	Date       string          `json:"date" binding:"required,datetime=2006-01-02" example:"2025-07-01"`
+	AnnualRate decimal.Decimal `json:"annual_rate" binding:"required" example:"14.90"`
+}
+
+// IndexRateResponse is the rate of an index on a business day.
+type IndexRateResponse struct {
+	Date       string          `json:"date" example:"2025-07-01"`
+	AnnualRate decimal.Decimal `json:"annual_rate" example:"14.90"`
+}
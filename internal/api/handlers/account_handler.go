@@ -34,6 +34,7 @@ func NewAccountHandler(s *service.AccountService) *AccountHandler {
 //	@Success		201		{object}	dto.AccountResponse
 //	@Failure		400		{object}	dto.ErrorResponse
 //	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse	"Account or storage quota exceeded"
 //	@Failure		500		{object}	dto.ErrorResponse
 //	@Security		BearerAuth
 //	@Router			/accounts [post]
@@ -45,17 +46,38 @@ func (h *AccountHandler) CreateAccount(c *gin.Context) {
 
 	userId := c.MustGet("userId").(int64)
 	account := model.Account{
-		UserId:              userId,
-		Name:                req.Name,
-		Type:                req.Type,
-		InitialBalance:      *req.InitialBalance,
-		CreditLimit:         req.CreditLimit,
-		StatementClosingDay: req.StatementClosingDay,
-		PaymentDueDay:       req.PaymentDueDay,
+		UserId:                      userId,
+		Name:                        req.Name,
+		Type:                        req.Type,
+		InitialBalance:              *req.InitialBalance,
+		CreditLimit:                 req.CreditLimit,
+		StatementClosingDay:         req.StatementClosingDay,
+		PaymentDueDay:               req.PaymentDueDay,
+		PointsValuePerThousand:      req.PointsValuePerThousand,
+		BenefitAllowedCategoryIds:   req.BenefitAllowedCategoryIds,
+		BenefitMonthlyCredit:        req.BenefitMonthlyCredit,
+		BenefitCreditDay:            req.BenefitCreditDay,
+		BenefitExpiresUnused:        req.BenefitExpiresUnused,
+		AssetPurchaseValue:          req.AssetPurchaseValue,
+		AssetPurchaseDate:           parseOptionalDate(req.AssetPurchaseDate),
+		AssetValuationMethod:        req.AssetValuationMethod,
+		AssetUsefulLifeMonths:       req.AssetUsefulLifeMonths,
+		AssetAnnualDepreciationRate: req.AssetAnnualDepreciationRate,
+		AssetSalvageValue:           req.AssetSalvageValue,
+		OverdraftLimit:              req.OverdraftLimit,
+		OverdraftMonthlyRate:        req.OverdraftMonthlyRate,
+		YieldType:                   req.YieldType,
+		YieldRate:                   req.YieldRate,
+		YieldFrequency:              req.YieldFrequency,
+		YieldStartDate:              parseOptionalDate(req.YieldStartDate),
+		YieldWithholdsIR:            req.YieldWithholdsIR,
 	}
 
 	id, err := h.service.CreateAccount(c.Request.Context(), account)
 	if err != nil {
+		if sendQuotaError(c, err) {
+			return
+		}
 		// Check if the error is for a duplicate key (account name)
 		if strings.Contains(err.Error(), "unique constraint") {
 			dto.SendError(c, http.StatusConflict, "account with this name already exists", nil)
@@ -97,12 +119,30 @@ func (h *AccountHandler) ListAccounts(c *gin.Context) {
 	var responses []dto.AccountResponse
 	for _, acc := range accounts {
 		responses = append(responses, dto.AccountResponse{
-			Id:                  acc.Id,
-			Name:                acc.Name,
-			Type:                acc.Type,
-			Balance:             &acc.Balance,
-			StatementClosingDay: acc.StatementClosingDay,
-			PaymentDueDay:       acc.PaymentDueDay,
+			Id:                          acc.Id,
+			Name:                        acc.Name,
+			Type:                        acc.Type,
+			Balance:                     &acc.Balance,
+			StatementClosingDay:         acc.StatementClosingDay,
+			PaymentDueDay:               acc.PaymentDueDay,
+			PointsValuePerThousand:      acc.PointsValuePerThousand,
+			BenefitAllowedCategoryIds:   acc.BenefitAllowedCategoryIds,
+			BenefitMonthlyCredit:        acc.BenefitMonthlyCredit,
+			BenefitCreditDay:            acc.BenefitCreditDay,
+			BenefitExpiresUnused:        acc.BenefitExpiresUnused,
+			AssetPurchaseValue:          acc.AssetPurchaseValue,
+			AssetPurchaseDate:           formatOptionalDate(acc.AssetPurchaseDate),
+			AssetValuationMethod:        acc.AssetValuationMethod,
+			AssetUsefulLifeMonths:       acc.AssetUsefulLifeMonths,
+			AssetAnnualDepreciationRate: acc.AssetAnnualDepreciationRate,
+			AssetSalvageValue:           acc.AssetSalvageValue,
+			OverdraftLimit:              acc.OverdraftLimit,
+			OverdraftMonthlyRate:        acc.OverdraftMonthlyRate,
+			YieldType:                   acc.YieldType,
+			YieldRate:                   acc.YieldRate,
+			YieldFrequency:              acc.YieldFrequency,
+			YieldStartDate:              formatOptionalDate(acc.YieldStartDate),
+			YieldWithholdsIR:            acc.YieldWithholdsIR,
 		})
 	}
 	dto.SendSuccessResponse(c, http.StatusOK, responses)
@@ -136,20 +176,38 @@ func (h *AccountHandler) GetAccount(c *gin.Context) {
 	}
 
 	dto.SendSuccessResponse(c, http.StatusOK, dto.AccountResponse{
-		Id:                  account.Id,
-		Name:                account.Name,
-		Type:                account.Type,
-		InitialBalance:      &account.InitialBalance,
-		Balance:             &account.Balance,
-		StatementClosingDay: account.StatementClosingDay,
-		PaymentDueDay:       account.PaymentDueDay,
+		Id:                          account.Id,
+		Name:                        account.Name,
+		Type:                        account.Type,
+		InitialBalance:              &account.InitialBalance,
+		Balance:                     &account.Balance,
+		StatementClosingDay:         account.StatementClosingDay,
+		PaymentDueDay:               account.PaymentDueDay,
+		PointsValuePerThousand:      account.PointsValuePerThousand,
+		BenefitAllowedCategoryIds:   account.BenefitAllowedCategoryIds,
+		BenefitMonthlyCredit:        account.BenefitMonthlyCredit,
+		BenefitCreditDay:            account.BenefitCreditDay,
+		BenefitExpiresUnused:        account.BenefitExpiresUnused,
+		AssetPurchaseValue:          account.AssetPurchaseValue,
+		AssetPurchaseDate:           formatOptionalDate(account.AssetPurchaseDate),
+		AssetValuationMethod:        account.AssetValuationMethod,
+		AssetUsefulLifeMonths:       account.AssetUsefulLifeMonths,
+		AssetAnnualDepreciationRate: account.AssetAnnualDepreciationRate,
+		AssetSalvageValue:           account.AssetSalvageValue,
+		OverdraftLimit:              account.OverdraftLimit,
+		OverdraftMonthlyRate:        account.OverdraftMonthlyRate,
+		YieldType:                   account.YieldType,
+		YieldRate:                   account.YieldRate,
+		YieldFrequency:              account.YieldFrequency,
+		YieldStartDate:              formatOptionalDate(account.YieldStartDate),
+		YieldWithholdsIR:            account.YieldWithholdsIR,
 	})
 }
 
 // UpdateAccount godoc
 //
 //	@Summary		Update an account
-//	@Description	Updates the details of an existing account
+//	@Description	Updates the details of an existing account. New closing and due days of a credit card apply to the statements closing on or after billing_cycle_effective_from (today by default); earlier statements keep their days.
 //	@Tags			accounts
 //	@Accept			json
 //	@Produce		json
@@ -170,13 +228,37 @@ func (h *AccountHandler) UpdateAccount(c *gin.Context) {
 	}
 
 	userId := c.MustGet("userId").(int64)
+	cycleEffectiveFrom := time.Now().UTC()
+	if date := parseOptionalDate(req.BillingCycleEffectiveFrom); date != nil {
+		cycleEffectiveFrom = *date
+	}
 
 	updatedAcc, err := h.service.UpdateAccount(c.Request.Context(), model.Account{
-		Id:     id,
-		UserId: userId,
-		Name:   req.Name,
-		Type:   req.Type,
-	})
+		Id:                          id,
+		UserId:                      userId,
+		Name:                        req.Name,
+		Type:                        req.Type,
+		StatementClosingDay:         req.StatementClosingDay,
+		PaymentDueDay:               req.PaymentDueDay,
+		PointsValuePerThousand:      req.PointsValuePerThousand,
+		BenefitAllowedCategoryIds:   req.BenefitAllowedCategoryIds,
+		BenefitMonthlyCredit:        req.BenefitMonthlyCredit,
+		BenefitCreditDay:            req.BenefitCreditDay,
+		BenefitExpiresUnused:        req.BenefitExpiresUnused,
+		AssetPurchaseValue:          req.AssetPurchaseValue,
+		AssetPurchaseDate:           parseOptionalDate(req.AssetPurchaseDate),
+		AssetValuationMethod:        req.AssetValuationMethod,
+		AssetUsefulLifeMonths:       req.AssetUsefulLifeMonths,
+		AssetAnnualDepreciationRate: req.AssetAnnualDepreciationRate,
+		AssetSalvageValue:           req.AssetSalvageValue,
+		OverdraftLimit:              req.OverdraftLimit,
+		OverdraftMonthlyRate:        req.OverdraftMonthlyRate,
+		YieldType:                   req.YieldType,
+		YieldRate:                   req.YieldRate,
+		YieldFrequency:              req.YieldFrequency,
+		YieldStartDate:              parseOptionalDate(req.YieldStartDate),
+		YieldWithholdsIR:            req.YieldWithholdsIR,
+	}, cycleEffectiveFrom)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			dto.SendError(c, http.StatusNotFound, "account not found", nil)
@@ -189,23 +271,43 @@ func (h *AccountHandler) UpdateAccount(c *gin.Context) {
 	}
 
 	dto.SendSuccessResponse(c, http.StatusOK, dto.AccountResponse{
-		Id:                  updatedAcc.Id,
-		Name:                updatedAcc.Name,
-		Type:                updatedAcc.Type,
-		InitialBalance:      &updatedAcc.InitialBalance,
-		Balance:             &updatedAcc.Balance,
-		StatementClosingDay: updatedAcc.StatementClosingDay,
-		PaymentDueDay:       updatedAcc.PaymentDueDay,
+		Id:                          updatedAcc.Id,
+		Name:                        updatedAcc.Name,
+		Type:                        updatedAcc.Type,
+		InitialBalance:              &updatedAcc.InitialBalance,
+		Balance:                     &updatedAcc.Balance,
+		StatementClosingDay:         updatedAcc.StatementClosingDay,
+		PaymentDueDay:               updatedAcc.PaymentDueDay,
+		PointsValuePerThousand:      updatedAcc.PointsValuePerThousand,
+		BenefitAllowedCategoryIds:   updatedAcc.BenefitAllowedCategoryIds,
+		BenefitMonthlyCredit:        updatedAcc.BenefitMonthlyCredit,
+		BenefitCreditDay:            updatedAcc.BenefitCreditDay,
+		BenefitExpiresUnused:        updatedAcc.BenefitExpiresUnused,
+		AssetPurchaseValue:          updatedAcc.AssetPurchaseValue,
+		AssetPurchaseDate:           formatOptionalDate(updatedAcc.AssetPurchaseDate),
+		AssetValuationMethod:        updatedAcc.AssetValuationMethod,
+		AssetUsefulLifeMonths:       updatedAcc.AssetUsefulLifeMonths,
+		AssetAnnualDepreciationRate: updatedAcc.AssetAnnualDepreciationRate,
+		AssetSalvageValue:           updatedAcc.AssetSalvageValue,
+		OverdraftLimit:              updatedAcc.OverdraftLimit,
+		OverdraftMonthlyRate:        updatedAcc.OverdraftMonthlyRate,
+		YieldType:                   updatedAcc.YieldType,
+		YieldRate:                   updatedAcc.YieldRate,
+		YieldFrequency:              updatedAcc.YieldFrequency,
+		YieldStartDate:              formatOptionalDate(updatedAcc.YieldStartDate),
+		YieldWithholdsIR:            updatedAcc.YieldWithholdsIR,
 	})
 }
 
 // DeleteAccount godoc
 //
 //	@Summary		Delete an account
-//	@Description	Removes an account from the system. It will fail if the account has associated transactions.
+//	@Description	Removes an account from the system with its transactions. Transfers to or from other accounts are deleted too, changing their balances, unless the keep_counterparts policy turns them into income or expenses of those accounts. Preview the impact with GET /accounts/{id}/deletion-impact.
 //	@Tags			accounts
-//	@Param			id	path	int	true	"Account ID"
+//	@Param			id		path	int		true	"Account ID"
+//	@Param			policy	query	string	false	"What to do with transfers to or from other accounts"	Enums(delete, keep_counterparts)	default(delete)
 //	@Success		204
+//	@Failure		400	{object}	dto.ErrorResponse
 //	@Failure		401	{object}	dto.ErrorResponse
 //	@Failure		404	{object}	dto.ErrorResponse
 //	@Failure		409	{object}	dto.ErrorResponse
@@ -215,8 +317,12 @@ func (h *AccountHandler) DeleteAccount(c *gin.Context) {
 	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
 	userId := c.MustGet("userId").(int64)
 
-	err := h.service.DeleteAccount(c.Request.Context(), id, userId)
+	err := h.service.DeleteAccount(c.Request.Context(), id, userId, service.AccountDeletionPolicy(c.Query("policy")))
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidDeletionPolicy) {
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		if errors.Is(err, sql.ErrNoRows) {
 			dto.SendError(c, http.StatusNotFound, "account not found", nil)
 			return
@@ -234,6 +340,71 @@ func (h *AccountHandler) DeleteAccount(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// GetAccountDeletionImpact godoc
+//
+//	@Summary		Preview an account deletion
+//	@Description	Shows what deleting the account under a policy would do: how many transactions are deleted, what happens to each transfer to or from other accounts and how their balances change. Only the owner can preview it, as only the owner can delete accounts.
+//	@Tags			accounts
+//	@Produce		json
+//	@Param			id		path		int		true	"Account ID"
+//	@Param			policy	query		string	false	"What to do with transfers to or from other accounts"	Enums(delete, keep_counterparts)	default(delete)
+//	@Success		200		{object}	dto.AccountDeletionImpactResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/accounts/{id}/deletion-impact [get]
+func (h *AccountHandler) GetAccountDeletionImpact(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid account Id format")
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	impact, err := h.service.GetDeletionImpact(c.Request.Context(), id, userId, service.AccountDeletionPolicy(c.Query("policy")))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidDeletionPolicy) {
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			dto.SendErrorResponse(c, http.StatusNotFound, "account not found")
+			return
+		}
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to preview account deletion")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to preview account deletion")
+		return
+	}
+
+	response := dto.AccountDeletionImpactResponse{
+		Policy:              string(impact.Policy),
+		DeletedTransactions: impact.DeletedTransactions,
+		Transfers:           make([]dto.DeletionImpactTransfer, 0, len(impact.Transfers)),
+		BalanceChanges:      make([]dto.DeletionImpactBalance, 0, len(impact.BalanceChanges)),
+	}
+	for _, transfer := range impact.Transfers {
+		response.Transfers = append(response.Transfers, dto.DeletionImpactTransfer{
+			Id:                   transfer.Transaction.Id,
+			Description:          transfer.Transaction.Description,
+			Amount:               transfer.Transaction.Amount,
+			Date:                 transfer.Transaction.Date,
+			CounterpartAccountId: transfer.CounterpartAccountId,
+			Outcome:              transfer.Outcome,
+		})
+	}
+	for _, change := range impact.BalanceChanges {
+		response.BalanceChanges = append(response.BalanceChanges, dto.DeletionImpactBalance{
+			AccountId:      change.AccountId,
+			AccountName:    change.AccountName,
+			CurrentBalance: change.CurrentBalance,
+			Change:         change.Change,
+			BalanceAfter:   change.CurrentBalance.Add(change.Change),
+		})
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, response)
+}
+
 // GetAccountStatement godoc
 //
 //	@Summary		Get a credit card statement
@@ -284,33 +455,72 @@ func (h *AccountHandler) GetAccountStatement(c *gin.Context) {
 
 	// Map the internal service struct to the public API DTOs.
 	// This is the "translation" step.
+	response := toStatementResponse(statementDetails)
+
+	dto.SendSuccessResponse(c, http.StatusOK, response)
+}
+
+// toStatementResponse maps a statement computed by the service to its DTO.
+func toStatementResponse(details *service.StatementDetails) dto.StatementResponse {
 	transactions := []dto.TransactionResponse{}
-	for _, tx := range statementDetails.Transactions {
-		transactions = append(transactions, dto.TransactionResponse{
-			Id:                   tx.Id,
-			Description:          tx.Description,
-			Amount:               tx.Amount,
-			Date:                 tx.Date,
-			Type:                 tx.Type,
-			AccountId:            tx.AccountId,
-			AccountName:          tx.AccountName,
-			CategoryId:           tx.CategoryId,
-			CategoryName:         tx.CategoryName,
-			DestinationAccountId: tx.DestinationAccountId,
-			CreatedAt:            tx.CreatedAt,
+	for _, tx := range details.Transactions {
+		transactions = append(transactions, toTransactionResponse(tx))
+	}
+
+	cards := []dto.StatementCardTotal{}
+	for _, card := range details.Cards {
+		cards = append(cards, dto.StatementCardTotal{
+			CardId:     card.CardId,
+			HolderName: card.HolderName,
+			LastFour:   card.LastFour,
+			Total:      card.Total,
+		})
+	}
+
+	foreignPurchases := []dto.StatementForeignCurrency{}
+	for _, currency := range details.ForeignPurchases {
+		purchases := make([]dto.StatementForeignPurchase, len(currency.Purchases))
+		for i, purchase := range currency.Purchases {
+			purchases[i] = dto.StatementForeignPurchase{
+				TransactionId:  purchase.TransactionId,
+				Description:    purchase.Description,
+				Date:           purchase.Date,
+				OriginalAmount: purchase.OriginalAmount,
+				ExchangeRate:   purchase.ExchangeRate,
+				SpreadRate:     purchase.SpreadRate,
+				Amount:         purchase.Amount,
+				IOF:            purchase.IOF,
+			}
+		}
+		foreignPurchases = append(foreignPurchases, dto.StatementForeignCurrency{
+			Currency:      currency.Currency,
+			OriginalTotal: currency.OriginalTotal,
+			Total:         currency.Total,
+			IOF:           currency.IOF,
+			Purchases:     purchases,
 		})
 	}
 
-	response := dto.StatementResponse{
-		AccountName:    statementDetails.AccountName,
-		StatementTotal: statementDetails.StatementTotal,
-		PaymentDueDate: statementDetails.PaymentDueDate,
+	return dto.StatementResponse{
+		AccountName:    details.AccountName,
+		StatementTotal: details.StatementTotal,
+		PaymentDueDate: details.PaymentDueDate,
 		Period: dto.StatementPeriod{
-			Start: statementDetails.StatementPeriod.Start,
-			End:   statementDetails.StatementPeriod.End,
+			Start: details.StatementPeriod.Start,
+			End:   details.StatementPeriod.End,
 		},
 		Transactions: transactions,
+		Cards:        cards,
+
+		ForeignPurchases: foreignPurchases,
 	}
+}
 
-	dto.SendSuccessResponse(c, http.StatusOK, response)
+// formatOptionalDate formats a date as YYYY-MM-DD, keeping nil as nil.
+func formatOptionalDate(date *time.Time) *string {
+	if date == nil {
+		return nil
+	}
+	formatted := date.Format("2006-01-02")
+	return &formatted
 }
@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/rs/zerolog"
+)
+
+const defaultAdminPageSize = 50
+
+type AdminHandler struct {
+	service *service.AdminService
+}
+
+func NewAdminHandler(s *service.AdminService) *AdminHandler {
+	return &AdminHandler{service: s}
+}
+
+// ListUsers godoc
+//
+//	@Summary		List and search users
+//	@Description	Returns the instance users, optionally filtered by name or e-mail. Admin only.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			search	query	string	false	"Search text in name or e-mail (case-insensitive)"
+//	@Param			limit	query	int		false	"Page size (default 50)"
+//	@Param			offset	query	int		false	"Number of users to skip"
+//	@Success		200		{array}		dto.AdminUserResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/users [get]
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	adminId := c.MustGet("userId").(int64)
+	limit, offset := parsePagination(c)
+
+	filters := repository.ListUserFilters{Limit: limit, Offset: offset}
+	if search := c.Query("search"); search != "" {
+		filters.Search = &search
+	}
+
+	users, err := h.service.ListUsers(c.Request.Context(), adminId, filters)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to list users")
+		return
+	}
+
+	responses := []dto.AdminUserResponse{}
+	for _, user := range users {
+		responses = append(responses, toAdminUserResponse(user))
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, responses)
+}
+
+// DisableUser godoc
+//
+//	@Summary		Disable a user
+//	@Description	Prevents the user from authenticating, including with tokens issued before. Admin only.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		int	true	"User Id"
+//	@Success		200	{object}	dto.AdminUserResponse
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/users/{id}/disable [post]
+func (h *AdminHandler) DisableUser(c *gin.Context) {
+	h.setUserDisabled(c, true)
+}
+
+// EnableUser godoc
+//
+//	@Summary		Enable a user
+//	@Description	Re-enables a previously disabled user. Admin only.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		int	true	"User Id"
+//	@Success		200	{object}	dto.AdminUserResponse
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/users/{id}/enable [post]
+func (h *AdminHandler) EnableUser(c *gin.Context) {
+	h.setUserDisabled(c, false)
+}
+
+func (h *AdminHandler) setUserDisabled(c *gin.Context, disabled bool) {
+	adminId := c.MustGet("userId").(int64)
+	userId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid user Id format")
+		return
+	}
+
+	user, err := h.service.SetUserDisabled(c.Request.Context(), adminId, userId, disabled)
+	if err != nil {
+		h.sendServiceError(c, err, "failed to update user")
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, toAdminUserResponse(*user))
+}
+
+// ForcePasswordReset godoc
+//
+//	@Summary		Force a password reset
+//	@Description	Replaces the user's password with a temporary one, returned in the response. The user must change it before using the API again. Admin only.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		int	true	"User Id"
+//	@Success		200	{object}	dto.ForcePasswordResetResponse
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/users/{id}/force-password-reset [post]
+func (h *AdminHandler) ForcePasswordReset(c *gin.Context) {
+	adminId := c.MustGet("userId").(int64)
+	userId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid user Id format")
+		return
+	}
+
+	temporaryPassword, err := h.service.ForcePasswordReset(c.Request.Context(), adminId, userId)
+	if err != nil {
+		h.sendServiceError(c, err, "failed to reset password")
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, dto.ForcePasswordResetResponse{TemporaryPassword: temporaryPassword})
+}
+
+// GetUserUsage godoc
+//
+//	@Summary		Get a user's usage
+//	@Description	Returns how many accounts, transactions, categories and budgets a user has, and an estimate of the storage they use. Admin only.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		int	true	"User Id"
+//	@Success		200	{object}	dto.UserUsageResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/users/{id}/usage [get]
+func (h *AdminHandler) GetUserUsage(c *gin.Context) {
+	adminId := c.MustGet("userId").(int64)
+	userId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid user Id format")
+		return
+	}
+
+	usage, err := h.service.GetUserUsage(c.Request.Context(), adminId, userId)
+	if err != nil {
+		h.sendServiceError(c, err, "failed to get user usage")
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, dto.UserUsageResponse{
+		UserId:       usage.UserId,
+		Accounts:     usage.Accounts,
+		Transactions: usage.Transactions,
+		Categories:   usage.Categories,
+		Budgets:      usage.Budgets,
+		StorageBytes: usage.StorageBytes,
+	})
+}
+
+// ListJobs godoc
+//
+//	@Summary		List background jobs
+//	@Description	Returns the schedule and last execution of every background job. Admin only.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{array}		dto.JobStatusResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/jobs [get]
+func (h *AdminHandler) ListJobs(c *gin.Context) {
+	adminId := c.MustGet("userId").(int64)
+
+	statuses, err := h.service.ListJobs(c.Request.Context(), adminId)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to list jobs")
+		return
+	}
+
+	responses := []dto.JobStatusResponse{}
+	for _, status := range statuses {
+		responses = append(responses, dto.JobStatusResponse{
+			Name:         status.Name,
+			Interval:     status.Interval.String(),
+			Running:      status.Running,
+			RunCount:     status.RunCount,
+			FailureCount: status.FailureCount,
+			LastRunAt:    status.LastRunAt,
+			LastDuration: status.LastDuration.String(),
+			LastError:    status.LastError,
+			NextRunAt:    status.NextRunAt,
+		})
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, responses)
+}
+
+// GetMigrationStatus godoc
+//
+//	@Summary		Get database migration status
+//	@Description	Compares the schema version applied to the database with the latest migration shipped with the application. Admin only.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	dto.MigrationStatusResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/migrations [get]
+func (h *AdminHandler) GetMigrationStatus(c *gin.Context) {
+	logger := zerolog.Ctx(c.Request.Context())
+	adminId := c.MustGet("userId").(int64)
+
+	status, err := h.service.GetMigrationStatus(c.Request.Context(), adminId)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get migration status")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to get migration status")
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, dto.MigrationStatusResponse{
+		Version:       status.Version,
+		LatestVersion: status.LatestVersion,
+		Dirty:         status.Dirty,
+		UpToDate:      !status.Dirty && status.Version == status.LatestVersion,
+	})
+}
+
+// ListAuditLog godoc
+//
+//	@Summary		List the admin audit trail
+//	@Description	Returns the actions performed through the admin API, most recent first. Admin only.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			limit	query	int	false	"Page size (default 50)"
+//	@Param			offset	query	int	false	"Number of entries to skip"
+//	@Success		200		{array}		dto.AuditLogEntryResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/audit-log [get]
+func (h *AdminHandler) ListAuditLog(c *gin.Context) {
+	adminId := c.MustGet("userId").(int64)
+	limit, offset := parsePagination(c)
+
+	entries, err := h.service.ListAuditLog(c.Request.Context(), adminId, limit, offset)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to list audit log")
+		return
+	}
+
+	responses := []dto.AuditLogEntryResponse{}
+	for _, entry := range entries {
+		responses = append(responses, dto.AuditLogEntryResponse{
+			Id:           entry.Id,
+			AdminId:      entry.AdminId,
+			Action:       entry.Action,
+			TargetUserId: entry.TargetUserId,
+			Details:      entry.Details,
+			CreatedAt:    entry.CreatedAt,
+		})
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, responses)
+}
+
+// LoadIndexRates godoc
+//
+//	@Summary		Load index rates
+//	@Description	Loads the daily rates of the CDI or the Selic, in percent a year, replacing the ones already loaded for the same days. Savings yields that follow an index are posted once it is loaded up to the last day of their period. Admin only.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path	string					true	"Index"	Enums(cdi, selic)
+//	@Param			rates	body	dto.IndexRatesRequest	true	"Daily rates"
+//	@Success		204
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/admin/index-rates/{name} [put]
+func (h *AdminHandler) LoadIndexRates(c *gin.Context) {
+	var req dto.IndexRatesRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	adminId := c.MustGet("userId").(int64)
+
+	rates := make([]model.IndexRate, 0, len(req.Rates))
+	for _, rate := range req.Rates {
+		date, err := time.Parse("2006-01-02", rate.Date)
+		if err != nil {
+			dto.SendErrorResponse(c, http.StatusBadRequest, "invalid date format, use YYYY-MM-DD")
+			return
+		}
+		rates = append(rates, model.IndexRate{Date: date, AnnualRate: rate.AnnualRate})
+	}
+
+	if err := h.service.LoadIndexRates(c.Request.Context(), adminId, model.IndexName(c.Param("name")), rates); err != nil {
+		h.sendServiceError(c, err, "failed to load index rates")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// sendServiceError maps the AdminService errors to HTTP responses.
+func (h *AdminHandler) sendServiceError(c *gin.Context, err error, fallbackMessage string) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrAdminCannotTargetSelf), errors.Is(err, service.ErrInvalidIndexRates):
+		dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg(fallbackMessage)
+		dto.SendErrorResponse(c, http.StatusInternalServerError, fallbackMessage)
+	}
+}
+
+// parsePagination reads the limit and offset query parameters, falling back to defaults.
+func parsePagination(c *gin.Context) (uint64, uint64) {
+	limit, err := strconv.ParseUint(c.Query("limit"), 10, 64)
+	if err != nil || limit == 0 {
+		limit = defaultAdminPageSize
+	}
+	offset, _ := strconv.ParseUint(c.Query("offset"), 10, 64)
+	return limit, offset
+}
+
+func toAdminUserResponse(user model.User) dto.AdminUserResponse {
+	return dto.AdminUserResponse{
+		Id:                    user.Id,
+		Name:                  user.Name,
+		Email:                 user.Email,
+		IsAdmin:               user.IsAdmin,
+		DisabledAt:            user.DisabledAt,
+		PasswordResetRequired: user.PasswordResetRequired,
+		CreatedAt:             user.CreatedAt,
+	}
+}
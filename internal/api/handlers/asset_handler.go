@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/rs/zerolog"
+)
+
+type AssetHandler struct {
+	service *service.AssetService
+}
+
+func NewAssetHandler(s *service.AssetService) *AssetHandler {
+	return &AssetHandler{service: s}
+}
+
+// GetAssetSummary godoc
+//
+//	@Summary		Get the value of an asset
+//	@Description	Returns the current value of an asset account and its valuation history: manual revaluations and the monthly depreciation adjustments.
+//	@Tags			assets
+//	@Produce		json
+//	@Param			id	path		int	true	"Account Id"
+//	@Success		200	{object}	dto.AssetSummaryResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/assets/{id} [get]
+func (h *AssetHandler) GetAssetSummary(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid account Id format")
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	summary, err := h.service.GetSummary(c.Request.Context(), userId, id)
+	if err != nil {
+		if errors.Is(err, service.ErrAssetAccountNotFound) {
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to get asset")
+		return
+	}
+
+	valuations := summary.Valuations
+	if valuations == nil {
+		valuations = []model.AssetValuation{}
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, dto.AssetSummaryResponse{
+		AccountId:       summary.Account.Id,
+		Name:            summary.Account.Name,
+		PurchaseValue:   summary.Account.AssetPurchaseValue,
+		PurchaseDate:    formatOptionalDate(summary.Account.AssetPurchaseDate),
+		ValuationMethod: summary.Account.AssetValuationMethod,
+		CurrentValue:    summary.CurrentValue,
+		Valuations:      valuations,
+	})
+}
+
+// RevalueAsset godoc
+//
+//	@Summary		Revalue an asset
+//	@Description	Records what an asset is worth on a date, such as a car's FIPE price or a property appraisal. Depreciation continues from the new value and the adjustments dated after it are regenerated.
+//	@Tags			assets
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int						true	"Account Id"
+//	@Param			valuation	body		dto.RevalueAssetRequest	true	"New value"
+//	@Success		201			{object}	model.AssetValuation
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/assets/{id}/valuations [post]
+func (h *AssetHandler) RevalueAsset(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid account Id format")
+		return
+	}
+	var req dto.RevalueAssetRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	valuation, err := h.service.Revalue(c.Request.Context(), userId, id, service.RevalueAssetInput{
+		Value:    req.Value,
+		ValuedOn: parseOptionalDate(req.ValuedOn),
+		Note:     req.Note,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAssetAccountNotFound):
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrInvalidAssetValue):
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		default:
+			zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to revalue asset")
+			dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to revalue asset")
+		}
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusCreated, valuation)
+}
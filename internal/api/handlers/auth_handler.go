@@ -30,6 +30,7 @@ func NewAuthHandler(s *service.AuthService) *AuthHandler {
 //	@Success		200			{object}	dto.LoginResponse
 //	@Failure		401			{object}	dto.ErrorResponse
 //	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
 //	@Router			/auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	logger := zerolog.Ctx(c.Request.Context()).With().Str("handler", "AuthHandlerLogin").Logger()
@@ -49,6 +50,10 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			dto.SendErrorResponse(c, http.StatusNotFound, "user not found")
 			return
 		}
+		if errors.Is(err, service.ErrUserDisabled) {
+			dto.SendErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
 		dto.SendErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
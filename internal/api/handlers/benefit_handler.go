@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+)
+
+type BenefitHandler struct {
+	service *service.BenefitService
+}
+
+func NewBenefitHandler(s *service.BenefitService) *BenefitHandler {
+	return &BenefitHandler{service: s}
+}
+
+// ListBenefitCredits godoc
+//
+//	@Summary		List benefit credits
+//	@Description	Returns the monthly credits of a meal or food voucher account, newest first, with the unused balance that expired before each one.
+//	@Tags			accounts
+//	@Produce		json
+//	@Param			id	path		int	true	"Account Id"
+//	@Success		200	{array}		model.BenefitCredit
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/accounts/{id}/benefit-credits [get]
+func (h *BenefitHandler) ListBenefitCredits(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid account Id format")
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	credits, err := h.service.ListCredits(c.Request.Context(), userId, id)
+	if err != nil {
+		if errors.Is(err, service.ErrBenefitAccountNotFound) {
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to list benefit credits")
+		return
+	}
+	if credits == nil {
+		credits = []model.BenefitCredit{}
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, credits)
+}
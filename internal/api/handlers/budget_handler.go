@@ -74,7 +74,7 @@ func (h *BudgetHandler) CreateBudget(c *gin.Context) {
 // ListBudgets godoc
 //
 //	@Summary		Lists budgets for a given period
-//	@Description	Retrieves all budgets for the user for a specific month and year. Defaults to the current month/year.
+//	@Description	Retrieves all budgets for the user for a specific month and year, counting spending over the user's fiscal month. Defaults to the current fiscal month.
 //	@Tags			budgets
 //	@Produce		json
 //	@Param			month	query		int	false	"Month to filter (1-12)"
@@ -87,9 +87,14 @@ func (h *BudgetHandler) CreateBudget(c *gin.Context) {
 func (h *BudgetHandler) ListBudgets(c *gin.Context) {
 	userId := c.MustGet("userId").(int64)
 
-	now := time.Now().UTC()
-	month, errMonth := strconv.Atoi(c.DefaultQuery("month", strconv.Itoa(int(now.Month()))))
-	year, errYear := strconv.Atoi(c.DefaultQuery("year", strconv.Itoa(now.Year())))
+	// The default is the user's fiscal month, which may not be the calendar one.
+	currentYear, currentMonth, err := h.service.PeriodOf(c.Request.Context(), userId, time.Now().UTC())
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to list budgets")
+		return
+	}
+	month, errMonth := strconv.Atoi(c.DefaultQuery("month", strconv.Itoa(currentMonth)))
+	year, errYear := strconv.Atoi(c.DefaultQuery("year", strconv.Itoa(currentYear)))
 
 	if errMonth != nil || errYear != nil {
 		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid month or year format")
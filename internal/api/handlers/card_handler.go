@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/rs/zerolog"
+)
+
+type CardHandler struct {
+	service *service.CardService
+}
+
+func NewCardHandler(s *service.CardService) *CardHandler {
+	return &CardHandler{service: s}
+}
+
+// CreateCard godoc
+//
+//	@Summary		Add a card to a credit card account
+//	@Description	Adds an additional or virtual card sharing the account's limit and statement. Purchases made with it are logged with its card_id; spend_limit caps what it may spend in each statement.
+//	@Tags			accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int					true	"Account Id"
+//	@Param			card	body		dto.CardRequest		true	"Holder, digits, kind and limit"
+//	@Success		201		{object}	model.Card
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/accounts/{id}/cards [post]
+func (h *CardHandler) CreateCard(c *gin.Context) {
+	accountId, ok := parseCardAccountId(c)
+	if !ok {
+		return
+	}
+	var req dto.CardRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	card, err := h.service.CreateCard(c.Request.Context(), toCard(userId, accountId, req))
+	if err != nil {
+		sendCardError(c, err, "failed to create card")
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusCreated, card)
+}
+
+// ListCards godoc
+//
+//	@Summary		List the cards of a credit card account
+//	@Tags			accounts
+//	@Produce		json
+//	@Param			id	path		int	true	"Account Id"
+//	@Success		200	{array}		model.Card
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/accounts/{id}/cards [get]
+func (h *CardHandler) ListCards(c *gin.Context) {
+	accountId, ok := parseCardAccountId(c)
+	if !ok {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	cards, err := h.service.ListCards(c.Request.Context(), accountId, userId)
+	if err != nil {
+		sendCardError(c, err, "failed to list cards")
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, cards)
+}
+
+// UpdateCard godoc
+//
+//	@Summary		Update a card
+//	@Description	Changes a card's holder, digits, kind and limit. Purchases already logged keep counting against the new limit.
+//	@Tags			accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int					true	"Account Id"
+//	@Param			cardId	path		int					true	"Card Id"
+//	@Param			card	body		dto.CardRequest		true	"Holder, digits, kind and limit"
+//	@Success		200		{object}	model.Card
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/accounts/{id}/cards/{cardId} [put]
+func (h *CardHandler) UpdateCard(c *gin.Context) {
+	accountId, cardId, ok := parseCardIds(c)
+	if !ok {
+		return
+	}
+	var req dto.CardRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	card := toCard(userId, accountId, req)
+	card.Id = cardId
+	updated, err := h.service.UpdateCard(c.Request.Context(), card)
+	if err != nil {
+		sendCardError(c, err, "failed to update card")
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, updated)
+}
+
+// DeleteCard godoc
+//
+//	@Summary		Delete a card
+//	@Description	Removes a card. Its purchases stay on the account's statements without a card.
+//	@Tags			accounts
+//	@Param			id		path	int	true	"Account Id"
+//	@Param			cardId	path	int	true	"Card Id"
+//	@Success		204
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/accounts/{id}/cards/{cardId} [delete]
+func (h *CardHandler) DeleteCard(c *gin.Context) {
+	accountId, cardId, ok := parseCardIds(c)
+	if !ok {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	if err := h.service.DeleteCard(c.Request.Context(), cardId, accountId, userId); err != nil {
+		sendCardError(c, err, "failed to delete card")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func sendCardError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrCardNotFound),
+		errors.Is(err, service.ErrSourceAccountNotFound):
+		dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrCardRequiresCreditCard),
+		errors.Is(err, service.ErrInvalidCard):
+		dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg(message)
+		dto.SendErrorResponse(c, http.StatusInternalServerError, message)
+	}
+}
+
+func parseCardAccountId(c *gin.Context) (int64, bool) {
+	accountId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid account Id format")
+		return 0, false
+	}
+	return accountId, true
+}
+
+func parseCardIds(c *gin.Context) (int64, int64, bool) {
+	accountId, ok := parseCardAccountId(c)
+	if !ok {
+		return 0, 0, false
+	}
+	cardId, err := strconv.ParseInt(c.Param("cardId"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid card Id format")
+		return 0, 0, false
+	}
+	return accountId, cardId, true
+}
+
+func toCard(userId, accountId int64, req dto.CardRequest) model.Card {
+	return model.Card{
+		UserId:     userId,
+		AccountId:  accountId,
+		HolderName: req.HolderName,
+		LastFour:   req.LastFour,
+		Virtual:    req.Virtual,
+		SpendLimit: req.SpendLimit,
+	}
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/chart"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/rs/zerolog"
+)
+
+// Limits of the chart size query parameters, in pixels.
+const (
+	minChartWidth  = 200
+	maxChartWidth  = 2000
+	minChartHeight = 150
+	maxChartHeight = 1500
+)
+
+type ChartHandler struct {
+	service *service.ChartService
+}
+
+func NewChartHandler(s *service.ChartService) *ChartHandler {
+	return &ChartHandler{service: s}
+}
+
+// GetCashflowChart godoc
+//
+//	@Summary		Cash flow chart
+//	@Description	Renders the income and expenses of the months up to the given one as an SVG bar chart, using the monthly report totals.
+//	@Tags			charts
+//	@Produce		image/svg+xml
+//	@Param			year	query		int		false	"Year of the last month (defaults to the current fiscal month)"
+//	@Param			month	query		int		false	"Last month, 1-12 (defaults to the current fiscal month)"
+//	@Param			months	query		int		false	"How many months to show, 1-24"	default(6)
+//	@Param			width	query		int		false	"Width in pixels, 200-2000"		default(640)
+//	@Param			height	query		int		false	"Height in pixels, 150-1500"	default(360)
+//	@Param			theme	query		string	false	"light or dark"					default(light)
+//	@Param			locale	query		string	false	"en or pt-BR"					default(en)
+//	@Success		200		{file}		file
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/charts/cashflow.svg [get]
+func (h *ChartHandler) GetCashflowChart(c *gin.Context) {
+	opts, year, month, ok := h.parseChartQuery(c)
+	if !ok {
+		return
+	}
+	months, err := strconv.Atoi(c.DefaultQuery("months", "6"))
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, service.ErrInvalidChartMonths.Error())
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	svg, err := h.service.Cashflow(c.Request.Context(), userId, year, month, months, opts)
+	sendChart(c, svg, err)
+}
+
+// GetCategoriesChart godoc
+//
+//	@Summary		Expenses by category chart
+//	@Description	Renders the share of each category in the expenses of a month as an SVG donut chart, using the monthly report totals. Small categories are grouped together.
+//	@Tags			charts
+//	@Produce		image/svg+xml
+//	@Param			year	query		int		false	"Year (defaults to the current fiscal month)"
+//	@Param			month	query		int		false	"Month, 1-12 (defaults to the current fiscal month)"
+//	@Param			width	query		int		false	"Width in pixels, 200-2000"		default(640)
+//	@Param			height	query		int		false	"Height in pixels, 150-1500"	default(360)
+//	@Param			theme	query		string	false	"light or dark"					default(light)
+//	@Param			locale	query		string	false	"en or pt-BR"					default(en)
+//	@Success		200		{file}		file
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/charts/categories.svg [get]
+func (h *ChartHandler) GetCategoriesChart(c *gin.Context) {
+	opts, year, month, ok := h.parseChartQuery(c)
+	if !ok {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	svg, err := h.service.Categories(c.Request.Context(), userId, year, month, opts)
+	sendChart(c, svg, err)
+}
+
+// GetNetWorthChart godoc
+//
+//	@Summary		Net worth chart
+//	@Description	Renders the value of each account as an SVG bar chart, using the net worth report. Debts are drawn below zero.
+//	@Tags			charts
+//	@Produce		image/svg+xml
+//	@Param			width	query		int		false	"Width in pixels, 200-2000"		default(640)
+//	@Param			height	query		int		false	"Height in pixels, 150-1500"	default(360)
+//	@Param			theme	query		string	false	"light or dark"					default(light)
+//	@Param			locale	query		string	false	"en or pt-BR"					default(en)
+//	@Success		200		{file}		file
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/charts/net-worth.svg [get]
+func (h *ChartHandler) GetNetWorthChart(c *gin.Context) {
+	opts, _, _, ok := h.parseChartQuery(c)
+	if !ok {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	svg, err := h.service.NetWorth(c.Request.Context(), userId, opts)
+	sendChart(c, svg, err)
+}
+
+// GetBudgetChart godoc
+//
+//	@Summary		Budget chart
+//	@Description	Renders the budget of each category next to what was spent in a month as an SVG bar chart, using the same totals as the budget list.
+//	@Tags			charts
+//	@Produce		image/svg+xml
+//	@Param			year	query		int		false	"Year (defaults to the current fiscal month)"
+//	@Param			month	query		int		false	"Month, 1-12 (defaults to the current fiscal month)"
+//	@Param			width	query		int		false	"Width in pixels, 200-2000"		default(640)
+//	@Param			height	query		int		false	"Height in pixels, 150-1500"	default(360)
+//	@Param			theme	query		string	false	"light or dark"					default(light)
+//	@Param			locale	query		string	false	"en or pt-BR"					default(en)
+//	@Success		200		{file}		file
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/charts/budget.svg [get]
+func (h *ChartHandler) GetBudgetChart(c *gin.Context) {
+	opts, year, month, ok := h.parseChartQuery(c)
+	if !ok {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	svg, err := h.service.Budget(c.Request.Context(), userId, year, month, opts)
+	sendChart(c, svg, err)
+}
+
+// parseChartQuery reads the size, theme, locale and period shared by every
+// chart; the period defaults to the current fiscal month. It sends an error
+// response and reports false when one is invalid.
+func (h *ChartHandler) parseChartQuery(c *gin.Context) (chart.Options, int, int, bool) {
+	opts := chart.DefaultOptions()
+	var err error
+
+	if opts.Width, err = strconv.Atoi(c.DefaultQuery("width", strconv.Itoa(opts.Width))); err != nil || opts.Width < minChartWidth || opts.Width > maxChartWidth {
+		dto.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("width must be between %d and %d", minChartWidth, maxChartWidth))
+		return opts, 0, 0, false
+	}
+	if opts.Height, err = strconv.Atoi(c.DefaultQuery("height", strconv.Itoa(opts.Height))); err != nil || opts.Height < minChartHeight || opts.Height > maxChartHeight {
+		dto.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("height must be between %d and %d", minChartHeight, maxChartHeight))
+		return opts, 0, 0, false
+	}
+	theme, ok := chart.Themes[c.DefaultQuery("theme", "light")]
+	if !ok {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "theme must be light or dark")
+		return opts, 0, 0, false
+	}
+	locale, ok := chart.Locales[c.DefaultQuery("locale", "en")]
+	if !ok {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "locale must be en or pt-BR")
+		return opts, 0, 0, false
+	}
+	opts.Theme, opts.Locale = theme, locale
+
+	currentYear, currentMonth, err := h.service.PeriodOf(c.Request.Context(), c.MustGet("userId").(int64), time.Now().UTC())
+	if err != nil {
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to render chart")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to render chart")
+		return opts, 0, 0, false
+	}
+	month, _ := strconv.Atoi(c.DefaultQuery("month", strconv.Itoa(currentMonth)))
+	year, _ := strconv.Atoi(c.DefaultQuery("year", strconv.Itoa(currentYear)))
+	return opts, year, month, true
+}
+
+func sendChart(c *gin.Context, svg []byte, err error) {
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidReportPeriod) || errors.Is(err, service.ErrInvalidChartMonths) {
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to render chart")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to render chart")
+		return
+	}
+	c.Data(http.StatusOK, "image/svg+xml", svg)
+}
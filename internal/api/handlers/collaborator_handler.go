@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/rs/zerolog"
+)
+
+type CollaboratorHandler struct {
+	service *service.CollaboratorService
+}
+
+func NewCollaboratorHandler(s *service.CollaboratorService) *CollaboratorHandler {
+	return &CollaboratorHandler{service: s}
+}
+
+// InviteCollaborator godoc
+//
+//	@Summary		Invite a collaborator
+//	@Description	Offers another registered user, such as an accountant, read-only access to the selected accounts within an optional date range. The invitee is notified by e-mail and must accept before the access works.
+//	@Tags			collaborators
+//	@Accept			json
+//	@Produce		json
+//	@Param			invitation	body		dto.InviteCollaboratorRequest	true	"Invitee and access scope"
+//	@Success		201			{object}	dto.CollaboratorGrantResponse
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		409			{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/collaborators [post]
+func (h *CollaboratorHandler) InviteCollaborator(c *gin.Context) {
+	var req dto.InviteCollaboratorRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	grant, err := h.service.Invite(c.Request.Context(), userId, service.InviteCollaboratorInput{
+		Email:      req.Email,
+		AccountIds: req.AccountIds,
+		StartDate:  parseOptionalDate(req.StartDate),
+		EndDate:    parseOptionalDate(req.EndDate),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidCollaboratorAccounts),
+			errors.Is(err, service.ErrInvalidCollaboratorPeriod),
+			errors.Is(err, service.ErrCannotInviteSelf):
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrCollaboratorAlreadyInvited):
+			dto.SendErrorResponse(c, http.StatusConflict, err.Error())
+		default:
+			zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to invite collaborator")
+			dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to invite collaborator")
+		}
+		return
+	}
+
+	dto.SendSuccessResponse(c, http.StatusCreated, toCollaboratorGrantResponse(*grant))
+}
+
+// ListCollaborators godoc
+//
+//	@Summary		List collaborators
+//	@Description	Returns the collaborator grants issued by the logged-in user, including pending and revoked ones.
+//	@Tags			collaborators
+//	@Produce		json
+//	@Success		200	{array}		dto.CollaboratorGrantResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/collaborators [get]
+func (h *CollaboratorHandler) ListCollaborators(c *gin.Context) {
+	userId := c.MustGet("userId").(int64)
+
+	grants, err := h.service.ListGrants(c.Request.Context(), userId)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to list collaborators")
+		return
+	}
+	sendCollaboratorGrants(c, grants)
+}
+
+// RevokeCollaborator godoc
+//
+//	@Summary		Revoke collaborator access
+//	@Description	Ends a grant or cancels a pending invitation immediately. The grant stays in the list for auditing.
+//	@Tags			collaborators
+//	@Param			id	path	int	true	"Grant Id"
+//	@Success		204
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/collaborators/{id} [delete]
+func (h *CollaboratorHandler) RevokeCollaborator(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid grant Id format")
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	if err := h.service.Revoke(c.Request.Context(), userId, id); err != nil {
+		if errors.Is(err, service.ErrCollaboratorGrantNotFound) {
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to revoke collaborator access")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListInvitations godoc
+//
+//	@Summary		List invitations received
+//	@Description	Returns the pending invitations sent to the logged-in user's e-mail and the grants they accepted. Use the owner_id of an active grant in the X-Act-As-User header to read that user's data.
+//	@Tags			collaborators
+//	@Produce		json
+//	@Success		200	{array}		dto.CollaboratorGrantResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/collaborators/invitations [get]
+func (h *CollaboratorHandler) ListInvitations(c *gin.Context) {
+	userId := c.MustGet("userId").(int64)
+
+	grants, err := h.service.ListInvitations(c.Request.Context(), userId)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to list invitations")
+		return
+	}
+	sendCollaboratorGrants(c, grants)
+}
+
+// AcceptInvitation godoc
+//
+//	@Summary		Accept an invitation
+//	@Description	Accepts a pending invitation sent to the logged-in user's e-mail, activating the read-only access.
+//	@Tags			collaborators
+//	@Produce		json
+//	@Param			id	path		int	true	"Grant Id"
+//	@Success		200	{object}	dto.CollaboratorGrantResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/collaborators/invitations/{id}/accept [post]
+func (h *CollaboratorHandler) AcceptInvitation(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid grant Id format")
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	grant, err := h.service.Accept(c.Request.Context(), userId, id)
+	if err != nil {
+		if errors.Is(err, service.ErrCollaboratorInviteNotFound) {
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to accept invitation")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to accept invitation")
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, toCollaboratorGrantResponse(*grant))
+}
+
+func sendCollaboratorGrants(c *gin.Context, grants []model.CollaboratorGrant) {
+	responses := []dto.CollaboratorGrantResponse{}
+	for _, grant := range grants {
+		responses = append(responses, toCollaboratorGrantResponse(grant))
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, responses)
+}
+
+func toCollaboratorGrantResponse(grant model.CollaboratorGrant) dto.CollaboratorGrantResponse {
+	response := dto.CollaboratorGrantResponse{
+		Id:             grant.Id,
+		OwnerId:        grant.OwnerId,
+		CollaboratorId: grant.CollaboratorId,
+		InviteeEmail:   grant.InviteeEmail,
+		AccountIds:     []int64(grant.AccountIds),
+		AcceptedAt:     grant.AcceptedAt,
+		RevokedAt:      grant.RevokedAt,
+		CreatedAt:      grant.CreatedAt,
+	}
+	if grant.StartDate != nil {
+		startDate := grant.StartDate.Format("2006-01-02")
+		response.StartDate = &startDate
+	}
+	if grant.EndDate != nil {
+		endDate := grant.EndDate.Format("2006-01-02")
+		response.EndDate = &endDate
+	}
+	switch {
+	case grant.RevokedAt != nil:
+		response.Status = "revoked"
+	case grant.AcceptedAt != nil:
+		response.Status = "active"
+	default:
+		response.Status = "pending"
+	}
+	return response
+}
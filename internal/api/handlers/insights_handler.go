@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/rs/zerolog"
+)
+
+type InsightsHandler struct {
+	service *service.InsightsService
+}
+
+func NewInsightsHandler(s *service.InsightsService) *InsightsHandler {
+	return &InsightsHandler{service: s}
+}
+
+// GetHealth godoc
+//
+//	@Summary		Get the financial health score
+//	@Description	Computes the emergency fund coverage, savings rate, debt-to-income, credit card utilization and budget adherence of the last six full months. Each metric has a 0 to 100 score, a trend against the six months before and a recommendation code; the overall score is the average of the metrics that could be computed.
+//	@Tags			insights
+//	@Produce		json
+//	@Success		200	{object}	dto.HealthResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/insights/health [get]
+func (h *InsightsHandler) GetHealth(c *gin.Context) {
+	userId := c.MustGet("userId").(int64)
+
+	report, err := h.service.GetHealth(c.Request.Context(), userId)
+	if err != nil {
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to compute financial health")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to compute financial health")
+		return
+	}
+
+	response := dto.HealthResponse{
+		Score:       report.Score,
+		PeriodStart: report.Period.Start,
+		PeriodEnd:   report.Period.End,
+		Metrics:     []dto.HealthMetricResponse{},
+	}
+	for _, metric := range report.Metrics {
+		response.Metrics = append(response.Metrics, dto.HealthMetricResponse{
+			Name:           metric.Name,
+			Value:          metric.Value,
+			Score:          metric.Score,
+			PreviousValue:  metric.PreviousValue,
+			PreviousScore:  metric.PreviousScore,
+			Trend:          metric.Trend,
+			Recommendation: metric.Recommendation,
+		})
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, response)
+}
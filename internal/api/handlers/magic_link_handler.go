@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/rs/zerolog"
+)
+
+type MagicLinkHandler struct {
+	service *service.MagicLinkService
+}
+
+func NewMagicLinkHandler(s *service.MagicLinkService) *MagicLinkHandler {
+	return &MagicLinkHandler{service: s}
+}
+
+// RequestMagicLink godoc
+//
+//	@Summary		Request a login link by e-mail
+//	@Description	Sends a single-use, short-lived login link to the e-mail if it belongs to a user. The response is the same whether the e-mail is registered or not.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.MagicLinkRequest	true	"E-mail to send the link to"
+//	@Success		202		{object}	dto.MessageResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		429		{object}	dto.ErrorResponse
+//	@Router			/auth/magic-link [post]
+func (h *MagicLinkHandler) RequestMagicLink(c *gin.Context) {
+	logger := zerolog.Ctx(c.Request.Context())
+
+	var req dto.MagicLinkRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+
+	if err := h.service.RequestLink(c.Request.Context(), req.Email); err != nil {
+		if errors.Is(err, service.ErrMagicLinkRateLimited) {
+			dto.SendErrorResponse(c, http.StatusTooManyRequests, err.Error())
+			return
+		}
+		logger.Error().Err(err).Msg("failed to issue magic link")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to send login link")
+		return
+	}
+
+	dto.SendSuccessResponse(c, http.StatusAccepted, dto.MessageResponse{
+		Message: "if the e-mail is registered, a login link has been sent",
+	})
+}
+
+// VerifyMagicLink godoc
+//
+//	@Summary		Exchange a login link for a token
+//	@Description	Redeems a login link token and returns a JWT. Each link can only be used once.
+//	@Tags			auth
+//	@Produce		json
+//	@Param			token	query		string	true	"Token received by e-mail"
+//	@Success		200		{object}	dto.LoginResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Router			/auth/magic-link/verify [get]
+func (h *MagicLinkHandler) VerifyMagicLink(c *gin.Context) {
+	logger := zerolog.Ctx(c.Request.Context())
+
+	token, err := h.service.VerifyLink(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		if errors.Is(err, service.ErrMagicLinkInvalid) {
+			dto.SendErrorResponse(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+		logger.Error().Err(err).Msg("failed to verify magic link")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to verify login link")
+		return
+	}
+
+	dto.SendSuccessResponse(c, http.StatusOK, dto.LoginResponse{Token: token})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/rs/zerolog"
+)
+
+type NetWorthHandler struct {
+	service *service.NetWorthService
+}
+
+func NewNetWorthHandler(s *service.NetWorthService) *NetWorthHandler {
+	return &NetWorthHandler{service: s}
+}
+
+// GetNetWorth godoc
+//
+//	@Summary		Get the net worth
+//	@Description	Values every account of the logged-in user and adds them up. Credit card debt is subtracted and points accounts count with their estimated value.
+//	@Tags			reports
+//	@Produce		json
+//	@Success		200	{object}	dto.NetWorthResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/reports/net-worth [get]
+func (h *NetWorthHandler) GetNetWorth(c *gin.Context) {
+	userId := c.MustGet("userId").(int64)
+
+	netWorth, err := h.service.GetNetWorth(c.Request.Context(), userId)
+	if err != nil {
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to compute net worth")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to compute net worth")
+		return
+	}
+
+	response := dto.NetWorthResponse{Total: netWorth.Total, Items: []dto.NetWorthItemResponse{}}
+	for _, item := range netWorth.Items {
+		response.Items = append(response.Items, dto.NetWorthItemResponse{
+			AccountId: item.AccountId,
+			Name:      item.Name,
+			Type:      item.Type,
+			Value:     item.Value,
+			Points:    item.Points,
+		})
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, response)
+}
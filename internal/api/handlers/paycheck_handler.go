@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/rs/zerolog"
+)
+
+type PaycheckHandler struct {
+	service *service.PaycheckService
+}
+
+func NewPaycheckHandler(s *service.PaycheckService) *PaycheckHandler {
+	return &PaycheckHandler{service: s}
+}
+
+// CreatePaycheckTemplate godoc
+//
+//	@Summary		Create a paycheck template
+//	@Description	Describes a salary as its earnings and deductions. Posting it puts the net amount into the account as one income transaction; templates with a pay_day are posted automatically every month.
+//	@Tags			paychecks
+//	@Accept			json
+//	@Produce		json
+//	@Param			template	body		dto.PaycheckTemplateRequest	true	"Account, pay day and lines"
+//	@Success		201			{object}	model.PaycheckTemplate
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		409			{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/paychecks/templates [post]
+func (h *PaycheckHandler) CreatePaycheckTemplate(c *gin.Context) {
+	var req dto.PaycheckTemplateRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	template, err := h.service.CreateTemplate(c.Request.Context(), toPaycheckTemplate(userId, req))
+	if err != nil {
+		h.sendTemplateError(c, err, "failed to create paycheck template")
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusCreated, template)
+}
+
+// ListPaycheckTemplates godoc
+//
+//	@Summary		List paycheck templates
+//	@Tags			paychecks
+//	@Produce		json
+//	@Success		200	{array}		model.PaycheckTemplate
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/paychecks/templates [get]
+func (h *PaycheckHandler) ListPaycheckTemplates(c *gin.Context) {
+	userId := c.MustGet("userId").(int64)
+
+	templates, err := h.service.ListTemplates(c.Request.Context(), userId)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to list paycheck templates")
+		return
+	}
+	if templates == nil {
+		templates = []model.PaycheckTemplate{}
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, templates)
+}
+
+// UpdatePaycheckTemplate godoc
+//
+//	@Summary		Update a paycheck template
+//	@Description	Replaces a template and its lines. Paychecks already posted do not change.
+//	@Tags			paychecks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int							true	"Template Id"
+//	@Param			template	body		dto.PaycheckTemplateRequest	true	"Account, pay day and lines"
+//	@Success		200			{object}	model.PaycheckTemplate
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/paychecks/templates/{id} [put]
+func (h *PaycheckHandler) UpdatePaycheckTemplate(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid template Id format")
+		return
+	}
+	var req dto.PaycheckTemplateRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	template := toPaycheckTemplate(userId, req)
+	template.Id = id
+	updated, err := h.service.UpdateTemplate(c.Request.Context(), template)
+	if err != nil {
+		h.sendTemplateError(c, err, "failed to update paycheck template")
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, updated)
+}
+
+// DeletePaycheckTemplate godoc
+//
+//	@Summary		Delete a paycheck template
+//	@Description	Stops a salary from being posted. Paychecks already posted and their transactions are kept.
+//	@Tags			paychecks
+//	@Param			id	path	int	true	"Template Id"
+//	@Success		204
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/paychecks/templates/{id} [delete]
+func (h *PaycheckHandler) DeletePaycheckTemplate(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid template Id format")
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	if err := h.service.DeleteTemplate(c.Request.Context(), id, userId); err != nil {
+		if errors.Is(err, service.ErrPaycheckTemplateNotFound) {
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to delete paycheck template")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// PostPaycheck godoc
+//
+//	@Summary		Post a paycheck
+//	@Description	Pays a template for a month: the net amount becomes an income transaction and the gross and deductions are kept as the paycheck's lines. Send lines to replace the template's in variable months, such as the 13th salary or vacation pay. Each kind is posted once per month.
+//	@Tags			paychecks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int						true	"Template Id"
+//	@Param			paycheck	body		dto.PostPaycheckRequest	true	"Kind, month and optional lines"
+//	@Success		201			{object}	model.Paycheck
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		409			{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/paychecks/templates/{id}/post [post]
+func (h *PaycheckHandler) PostPaycheck(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid template Id format")
+		return
+	}
+	var req dto.PostPaycheckRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	input := service.PostPaycheckInput{
+		Kind:    req.Kind,
+		Year:    req.Year,
+		Month:   req.Month,
+		PayDate: parseOptionalDate(req.PayDate),
+	}
+	if req.Lines != nil {
+		input.Lines = toPaycheckLines(req.Lines)
+	}
+	paycheck, err := h.service.Post(c.Request.Context(), userId, id, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPaycheckTemplateNotFound):
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrPaycheckAlreadyPosted):
+			dto.SendErrorResponse(c, http.StatusConflict, err.Error())
+		case errors.Is(err, service.ErrInvalidPaycheckLines),
+			errors.Is(err, service.ErrInvalidPaycheckCategory),
+			errors.Is(err, service.ErrInvalidReportPeriod):
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		default:
+			zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to post paycheck")
+			dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to post paycheck")
+		}
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusCreated, paycheck)
+}
+
+// ListPaychecks godoc
+//
+//	@Summary		List paychecks
+//	@Description	Returns the paychecks paid in a year with their earnings and deductions.
+//	@Tags			paychecks
+//	@Produce		json
+//	@Param			year	query		int	false	"Year (e.g., 2025)"
+//	@Success		200		{array}		model.Paycheck
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/paychecks [get]
+func (h *PaycheckHandler) ListPaychecks(c *gin.Context) {
+	userId := c.MustGet("userId").(int64)
+	year, _ := strconv.Atoi(c.DefaultQuery("year", strconv.Itoa(time.Now().Year())))
+
+	paychecks, err := h.service.ListPaychecks(c.Request.Context(), userId, year)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to list paychecks")
+		return
+	}
+	if paychecks == nil {
+		paychecks = []model.Paycheck{}
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, paychecks)
+}
+
+// GetPaycheckSummary godoc
+//
+//	@Summary		Get the annual paycheck report
+//	@Description	Totals the gross, deductions and net paid in a year, with each earning and deduction added up. It is the basis of the income tax return.
+//	@Tags			reports
+//	@Produce		json
+//	@Param			year	query		int	false	"Year (e.g., 2025)"
+//	@Success		200		{object}	dto.PaycheckSummaryResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/reports/paychecks [get]
+func (h *PaycheckHandler) GetPaycheckSummary(c *gin.Context) {
+	userId := c.MustGet("userId").(int64)
+	year, _ := strconv.Atoi(c.DefaultQuery("year", strconv.Itoa(time.Now().Year())))
+
+	summary, err := h.service.GetAnnualSummary(c.Request.Context(), userId, year)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidReportPeriod) {
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to build paycheck report")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to build paycheck report")
+		return
+	}
+
+	response := dto.PaycheckSummaryResponse{
+		Year:       summary.Year,
+		Paychecks:  summary.Paychecks,
+		Gross:      summary.Gross,
+		Deductions: summary.Deductions,
+		Net:        summary.Net,
+		Lines:      []dto.PaycheckLineTotalResponse{},
+	}
+	for _, line := range summary.Lines {
+		response.Lines = append(response.Lines, dto.PaycheckLineTotalResponse{
+			Kind:        line.Kind,
+			Description: line.Description,
+			CategoryId:  line.CategoryId,
+			Amount:      line.Amount,
+		})
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, response)
+}
+
+func (h *PaycheckHandler) sendTemplateError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrPaycheckTemplateNotFound):
+		dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidPaycheckLines),
+		errors.Is(err, service.ErrInvalidPaycheckAccount),
+		errors.Is(err, service.ErrInvalidPaycheckCategory):
+		dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+	case isUniqueViolation(err):
+		dto.SendErrorResponse(c, http.StatusConflict, "a paycheck template with this name already exists")
+	default:
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg(message)
+		dto.SendErrorResponse(c, http.StatusInternalServerError, message)
+	}
+}
+
+func toPaycheckTemplate(userId int64, req dto.PaycheckTemplateRequest) model.PaycheckTemplate {
+	template := model.PaycheckTemplate{
+		UserId:           userId,
+		Name:             req.Name,
+		AccountId:        req.AccountId,
+		IncomeCategoryId: req.IncomeCategoryId,
+		PayDay:           req.PayDay,
+		Active:           true,
+		Lines:            toPaycheckLines(req.Lines),
+	}
+	if req.Active != nil {
+		template.Active = *req.Active
+	}
+	return template
+}
+
+func toPaycheckLines(lines []dto.PaycheckLineRequest) []model.PaycheckLine {
+	result := make([]model.PaycheckLine, 0, len(lines))
+	for _, line := range lines {
+		result = append(result, model.PaycheckLine{
+			Kind:        line.Kind,
+			Description: line.Description,
+			CategoryId:  line.CategoryId,
+			Amount:      line.Amount,
+		})
+	}
+	return result
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"cmp"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+// Defaults of the planning requests.
+const (
+	defaultScenarioMonths        = 12
+	defaultRetirementYears       = 40
+	defaultRetirementLength      = 30
+	defaultRetirementSimulations = 1000
+	defaultRetirementWithdrawal  = 4
+	defaultRetirementVolatility  = 12
+)
+
+type PlanningHandler struct {
+	service *service.PlanningService
+}
+
+func NewPlanningHandler(s *service.PlanningService) *PlanningHandler {
+	return &PlanningHandler{service: s}
+}
+
+// SimulateScenario godoc
+//
+//	@Summary		Simulate a what-if scenario
+//	@Description	Projects the liquid balance and net worth month by month, starting next month, for the baseline and for the scenario. The baseline carries today's balances forward with the average monthly income and expense of each category over the last three full months. The scenario applies the changes: income and category changes, removed categories, new recurring items, one-off purchases and loans. Nothing is saved.
+//	@Tags			planning
+//	@Accept			json
+//	@Produce		json
+//	@Param			scenario	body		dto.ScenarioRequest	true	"Hypothetical changes"
+//	@Success		200			{object}	dto.ScenarioResponse
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/planning/scenarios [post]
+func (h *PlanningHandler) SimulateScenario(c *gin.Context) {
+	var req dto.ScenarioRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	result, err := h.service.SimulateScenario(c.Request.Context(), userId, toScenarioInput(req))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidScenario) {
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to simulate scenario")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to simulate scenario")
+		return
+	}
+
+	response := dto.ScenarioResponse{
+		StartingBalance:    result.StartingBalance,
+		StartingNetWorth:   result.StartingNetWorth,
+		BaselineMonthlyNet: result.BaselineMonthly,
+		BaselineItems:      []dto.RecurringItemResponse{},
+		FirstNegativeMonth: result.FirstNegativeMonth,
+		Months:             []dto.ScenarioMonthResponse{},
+	}
+	for _, item := range result.BaselineItems {
+		response.BaselineItems = append(response.BaselineItems, dto.RecurringItemResponse{
+			Type:         item.Type,
+			CategoryId:   item.CategoryId,
+			CategoryName: item.CategoryName,
+			Amount:       item.Amount,
+		})
+	}
+	for _, month := range result.Months {
+		response.Months = append(response.Months, dto.ScenarioMonthResponse{
+			Year:             month.Year,
+			Month:            month.Month,
+			BaselineBalance:  month.BaselineBalance,
+			ScenarioBalance:  month.ScenarioBalance,
+			BalanceDelta:     month.ScenarioBalance.Sub(month.BaselineBalance),
+			BaselineNetWorth: month.BaselineNetWorth,
+			ScenarioNetWorth: month.ScenarioNetWorth,
+			NetWorthDelta:    month.ScenarioNetWorth.Sub(month.BaselineNetWorth),
+			LoanBalance:      month.LoanBalance,
+		})
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, response)
+}
+
+// ProjectRetirement godoc
+//
+//	@Summary		Project retirement and financial independence
+//	@Description	Projects the balance of the selected accounts year by year in today's money, with the monthly contribution and the expected return discounted by inflation, and estimates when it reaches the target: the portfolio whose withdrawals at the withdrawal rate cover the yearly expenses. A Monte Carlo simulation with random yearly returns gives the probability that the money lasts through the retirement years. The same seed always gives the same result.
+//	@Tags			planning
+//	@Accept			json
+//	@Produce		json
+//	@Param			plan	body		dto.RetirementRequest	true	"Accounts and assumptions"
+//	@Success		200		{object}	dto.RetirementResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/planning/retirement [post]
+func (h *PlanningHandler) ProjectRetirement(c *gin.Context) {
+	var req dto.RetirementRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	input := service.RetirementInput{
+		AccountIds:          req.AccountIds,
+		AnnualReturn:        req.AnnualReturn,
+		Inflation:           req.Inflation,
+		WithdrawalRate:      decimal.NewFromInt(defaultRetirementWithdrawal),
+		Volatility:          decimal.NewFromInt(defaultRetirementVolatility),
+		MonthlyContribution: req.MonthlyContribution,
+		MonthlyExpenses:     req.MonthlyExpenses,
+		Years:               cmp.Or(req.Years, defaultRetirementYears),
+		RetirementYears:     cmp.Or(req.RetirementYears, defaultRetirementLength),
+		Simulations:         cmp.Or(req.Simulations, defaultRetirementSimulations),
+		Seed:                req.Seed,
+	}
+	if req.WithdrawalRate != nil {
+		input.WithdrawalRate = *req.WithdrawalRate
+	}
+	if req.Volatility != nil {
+		input.Volatility = *req.Volatility
+	}
+
+	projection, err := h.service.ProjectRetirement(c.Request.Context(), userId, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRetirementPlan):
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrRetirementAccountNotFound):
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		default:
+			zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to project retirement")
+			dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to project retirement")
+		}
+		return
+	}
+
+	response := dto.RetirementResponse{
+		StartingBalance:     projection.StartingBalance,
+		MonthlyContribution: projection.MonthlyContribution,
+		MonthlyExpenses:     projection.MonthlyExpenses,
+		RealReturn:          projection.RealReturn,
+		Target:              projection.Target,
+		IndependenceDate:    formatOptionalDate(projection.IndependenceDate),
+		SuccessProbability:  projection.SuccessProbability,
+		Simulations:         projection.Simulations,
+		Seed:                projection.Seed,
+		Years:               []dto.RetirementYearResponse{},
+	}
+	for _, year := range projection.Years {
+		response.Years = append(response.Years, dto.RetirementYearResponse{
+			Year:           year.Year,
+			Contributions:  year.Contributions,
+			Growth:         year.Growth,
+			Balance:        year.Balance,
+			NominalBalance: year.NominalBalance,
+			Independent:    year.Independent,
+		})
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, response)
+}
+
+func toScenarioInput(req dto.ScenarioRequest) service.ScenarioInput {
+	input := service.ScenarioInput{
+		Months:              req.Months,
+		IncomeChangePercent: req.IncomeChangePercent,
+		RemoveCategoryIds:   req.RemoveCategoryIds,
+	}
+	if input.Months == 0 {
+		input.Months = defaultScenarioMonths
+	}
+	for _, change := range req.CategoryChanges {
+		input.CategoryChanges = append(input.CategoryChanges, service.CategoryChange{CategoryId: change.CategoryId, Percent: change.Percent})
+	}
+	for _, item := range req.AddRecurring {
+		input.AddRecurring = append(input.AddRecurring, service.ScenarioRecurringItem{
+			Description: item.Description,
+			Type:        item.Type,
+			Amount:      item.Amount,
+			StartMonth:  max(item.StartMonth, 1),
+			EndMonth:    item.EndMonth,
+		})
+	}
+	for _, purchase := range req.Purchases {
+		input.Purchases = append(input.Purchases, service.ScenarioPurchase{
+			Description: purchase.Description,
+			Amount:      purchase.Amount,
+			Month:       purchase.Month,
+		})
+	}
+	for _, loan := range req.Loans {
+		input.Loans = append(input.Loans, service.ScenarioLoan{
+			Description:         loan.Description,
+			Principal:           loan.Principal,
+			MonthlyInterestRate: loan.MonthlyInterestRate,
+			Installments:        loan.Installments,
+			StartMonth:          max(loan.StartMonth, 1),
+			AssetValue:          loan.AssetValue,
+		})
+	}
+	return input
+}
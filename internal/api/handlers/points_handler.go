@@ -0,0 +1,345 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/rs/zerolog"
+)
+
+type PointsHandler struct {
+	service *service.PointsService
+}
+
+func NewPointsHandler(s *service.PointsService) *PointsHandler {
+	return &PointsHandler{service: s}
+}
+
+// CreatePointsRule godoc
+//
+//	@Summary		Create a points earn rule
+//	@Description	Makes a credit card earn points in a points account for every currency unit of expenses in its statements.
+//	@Tags			points
+//	@Accept			json
+//	@Produce		json
+//	@Param			rule	body		dto.PointsRuleRequest	true	"Card, points account and rate"
+//	@Success		201		{object}	model.PointsEarnRule
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/points/rules [post]
+func (h *PointsHandler) CreatePointsRule(c *gin.Context) {
+	var req dto.PointsRuleRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	rule, err := h.service.CreateRule(c.Request.Context(), model.PointsEarnRule{
+		UserId:           userId,
+		CardAccountId:    req.CardAccountId,
+		PointsAccountId:  req.PointsAccountId,
+		PointsPerUnit:    req.PointsPerUnit,
+		ExpirationMonths: req.ExpirationMonths,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPointsRule) {
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if isUniqueViolation(err) {
+			dto.SendErrorResponse(c, http.StatusConflict, "this card already earns points in this account")
+			return
+		}
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to create points rule")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to create points rule")
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusCreated, rule)
+}
+
+// ListPointsRules godoc
+//
+//	@Summary		List points earn rules
+//	@Tags			points
+//	@Produce		json
+//	@Success		200	{array}		model.PointsEarnRule
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/points/rules [get]
+func (h *PointsHandler) ListPointsRules(c *gin.Context) {
+	userId := c.MustGet("userId").(int64)
+
+	rules, err := h.service.ListRules(c.Request.Context(), userId)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to list points rules")
+		return
+	}
+	if rules == nil {
+		rules = []model.PointsEarnRule{}
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, rules)
+}
+
+// DeletePointsRule godoc
+//
+//	@Summary		Delete a points earn rule
+//	@Description	Stops a card from earning points. Points already credited are kept.
+//	@Tags			points
+//	@Param			id	path	int	true	"Rule Id"
+//	@Success		204
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/points/rules/{id} [delete]
+func (h *PointsHandler) DeletePointsRule(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid rule Id format")
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	if err := h.service.DeleteRule(c.Request.Context(), id, userId); err != nil {
+		if errors.Is(err, service.ErrPointsRuleNotFound) {
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to delete points rule")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AccruePoints godoc
+//
+//	@Summary		Credit the points of a statement
+//	@Description	Computes the points a rule earned from the card's expenses in the statement due in the given month. Closed statements are credited automatically every day; accruing a statement again updates its lot.
+//	@Tags			points
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int							true	"Rule Id"
+//	@Param			statement	body		dto.AccruePointsRequest		true	"Statement due month"
+//	@Success		200			{object}	model.PointsLot
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/points/rules/{id}/accrue [post]
+func (h *PointsHandler) AccruePoints(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid rule Id format")
+		return
+	}
+	var req dto.AccruePointsRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	lot, err := h.service.AccrueStatement(c.Request.Context(), userId, id, req.Year, req.Month)
+	if err != nil {
+		if errors.Is(err, service.ErrPointsRuleNotFound) {
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to accrue points")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to accrue points")
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, lot)
+}
+
+// GetPointsSummary godoc
+//
+//	@Summary		Get a points account
+//	@Description	Returns the valid points, how many expire in the next 90 days, the estimated value and every lot of a points account.
+//	@Tags			points
+//	@Produce		json
+//	@Param			id	path		int	true	"Points account Id"
+//	@Success		200	{object}	dto.PointsSummaryResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/points/accounts/{id} [get]
+func (h *PointsHandler) GetPointsSummary(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid account Id format")
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	summary, err := h.service.GetSummary(c.Request.Context(), userId, id)
+	if err != nil {
+		if errors.Is(err, service.ErrPointsAccountNotFound) {
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to load points account")
+		return
+	}
+
+	lots := summary.Lots
+	if lots == nil {
+		lots = []model.PointsLot{}
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, dto.PointsSummaryResponse{
+		AccountId:      summary.Account.Id,
+		Name:           summary.Account.Name,
+		Balance:        summary.Balance,
+		ExpiringSoon:   summary.ExpiringSoon,
+		EstimatedValue: summary.EstimatedValue,
+		Lots:           lots,
+	})
+}
+
+// AddPoints godoc
+//
+//	@Summary		Add points to an account
+//	@Description	Credits a lot of points that did not come from a card statement, such as an opening balance or a promotion.
+//	@Tags			points
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int						true	"Points account Id"
+//	@Param			lot	body		dto.AddPointsRequest	true	"Points and dates"
+//	@Success		201	{object}	model.PointsLot
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/points/accounts/{id}/lots [post]
+func (h *PointsHandler) AddPoints(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid account Id format")
+		return
+	}
+	var req dto.AddPointsRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	input := service.AddPointsInput{Points: req.Points, Description: req.Description, ExpiresAt: parseOptionalDate(req.ExpiresAt)}
+	if earnedAt := parseOptionalDate(req.EarnedAt); earnedAt != nil {
+		input.EarnedAt = *earnedAt
+	}
+
+	lot, err := h.service.AddPoints(c.Request.Context(), userId, id, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPointsAccountNotFound):
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrInvalidPointsAmount):
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		default:
+			dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to add points")
+		}
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusCreated, lot)
+}
+
+// TransferPoints godoc
+//
+//	@Summary		Transfer points between programs
+//	@Description	Debits the points that expire first from the source account and credits them, plus the bonus, to the destination.
+//	@Tags			points
+//	@Accept			json
+//	@Produce		json
+//	@Param			transfer	body		dto.PointsTransferRequest	true	"Accounts, points and bonus"
+//	@Success		201			{object}	dto.PointsTransferResponse
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		422			{object}	dto.ErrorResponse	"Not enough valid points"
+//	@Security		BearerAuth
+//	@Router			/points/transfers [post]
+func (h *PointsHandler) TransferPoints(c *gin.Context) {
+	var req dto.PointsTransferRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	transfer, err := h.service.Transfer(c.Request.Context(), userId, service.TransferPointsInput{
+		FromAccountId: req.FromAccountId,
+		ToAccountId:   req.ToAccountId,
+		Points:        req.Points,
+		BonusPercent:  req.BonusPercent,
+		ExpiresAt:     parseOptionalDate(req.ExpiresAt),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPointsAccountNotFound):
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrInvalidPointsAmount), errors.Is(err, service.ErrInvalidPointsTransfer):
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrInsufficientPoints):
+			dto.SendErrorResponse(c, http.StatusUnprocessableEntity, err.Error())
+		default:
+			zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to transfer points")
+			dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to transfer points")
+		}
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusCreated, toPointsTransferResponse(*transfer))
+}
+
+// ListPointsTransfers godoc
+//
+//	@Summary		List points transfers
+//	@Tags			points
+//	@Produce		json
+//	@Success		200	{array}		dto.PointsTransferResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/points/transfers [get]
+func (h *PointsHandler) ListPointsTransfers(c *gin.Context) {
+	userId := c.MustGet("userId").(int64)
+
+	transfers, err := h.service.ListTransfers(c.Request.Context(), userId)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to list points transfers")
+		return
+	}
+	responses := []dto.PointsTransferResponse{}
+	for _, transfer := range transfers {
+		responses = append(responses, toPointsTransferResponse(transfer))
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, responses)
+}
+
+func toPointsTransferResponse(transfer model.PointsTransfer) dto.PointsTransferResponse {
+	return dto.PointsTransferResponse{
+		Id:             transfer.Id,
+		FromAccountId:  transfer.FromAccountId,
+		ToAccountId:    transfer.ToAccountId,
+		Points:         transfer.Points,
+		BonusPercent:   transfer.BonusPercent,
+		CreditedPoints: transfer.CreditedPoints,
+		CreatedAt:      transfer.CreatedAt,
+	}
+}
+
+// parseOptionalDate parses a YYYY-MM-DD date already checked by the binding.
+// An empty string yields nil.
+func parseOptionalDate(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil
+	}
+	return &date
+}
+
+// isUniqueViolation reports whether the database rejected a duplicate row.
+func isUniqueViolation(err error) bool {
+	return strings.Contains(err.Error(), "unique constraint")
+}
@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/rs/zerolog"
+)
+
+type ProjectHandler struct {
+	service *service.ProjectService
+}
+
+func NewProjectHandler(s *service.ProjectService) *ProjectHandler {
+	return &ProjectHandler{service: s}
+}
+
+// CreateProject godoc
+//
+//	@Summary		Create a project
+//	@Description	Creates a budget for an event, such as a wedding, a renovation or a trip, that spans categories, accounts and months. Category budgets are optional and must fit in the total.
+//	@Tags			projects
+//	@Accept			json
+//	@Produce		json
+//	@Param			project	body		dto.ProjectRequest	true	"Name, budget and dates"
+//	@Success		201		{object}	dto.ProjectResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/projects [post]
+func (h *ProjectHandler) CreateProject(c *gin.Context) {
+	var req dto.ProjectRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	project, err := h.service.Create(c.Request.Context(), toProject(userId, req))
+	if err != nil {
+		h.sendProjectError(c, err, "failed to create project")
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusCreated, toProjectResponse(*project))
+}
+
+// ListProjects godoc
+//
+//	@Summary		List projects
+//	@Tags			projects
+//	@Produce		json
+//	@Success		200	{array}		dto.ProjectResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/projects [get]
+func (h *ProjectHandler) ListProjects(c *gin.Context) {
+	userId := c.MustGet("userId").(int64)
+
+	projects, err := h.service.List(c.Request.Context(), userId)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to list projects")
+		return
+	}
+	responses := []dto.ProjectResponse{}
+	for _, project := range projects {
+		responses = append(responses, toProjectResponse(project))
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, responses)
+}
+
+// GetProject godoc
+//
+//	@Summary		Get a project
+//	@Tags			projects
+//	@Produce		json
+//	@Param			id	path		int	true	"Project Id"
+//	@Success		200	{object}	dto.ProjectResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/projects/{id} [get]
+func (h *ProjectHandler) GetProject(c *gin.Context) {
+	id, ok := parseProjectId(c)
+	if !ok {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	project, err := h.service.GetById(c.Request.Context(), id, userId)
+	if err != nil {
+		h.sendProjectError(c, err, "failed to get project")
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, toProjectResponse(*project))
+}
+
+// UpdateProject godoc
+//
+//	@Summary		Update a project
+//	@Description	Replaces a project and its category budgets. Assigned transactions are kept.
+//	@Tags			projects
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int					true	"Project Id"
+//	@Param			project	body		dto.ProjectRequest	true	"Name, budget and dates"
+//	@Success		200		{object}	dto.ProjectResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/projects/{id} [put]
+func (h *ProjectHandler) UpdateProject(c *gin.Context) {
+	id, ok := parseProjectId(c)
+	if !ok {
+		return
+	}
+	var req dto.ProjectRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	project := toProject(userId, req)
+	project.Id = id
+	updated, err := h.service.Update(c.Request.Context(), project)
+	if err != nil {
+		h.sendProjectError(c, err, "failed to update project")
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, toProjectResponse(*updated))
+}
+
+// DeleteProject godoc
+//
+//	@Summary		Delete a project
+//	@Description	Deletes a project. Its transactions are kept, no longer assigned to it.
+//	@Tags			projects
+//	@Param			id	path	int	true	"Project Id"
+//	@Success		204
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/projects/{id} [delete]
+func (h *ProjectHandler) DeleteProject(c *gin.Context) {
+	id, ok := parseProjectId(c)
+	if !ok {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	if err := h.service.Delete(c.Request.Context(), id, userId); err != nil {
+		h.sendProjectError(c, err, "failed to delete project")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AssignProjectTransactions godoc
+//
+//	@Summary		Assign transactions to a project
+//	@Description	Assigns transactions to a project, moving them out of any other project. Either all of them are assigned or none is.
+//	@Tags			projects
+//	@Accept			json
+//	@Param			id				path	int										true	"Project Id"
+//	@Param			transactions	body	dto.AssignProjectTransactionsRequest	true	"Transaction Ids"
+//	@Success		204
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/projects/{id}/transactions [post]
+func (h *ProjectHandler) AssignProjectTransactions(c *gin.Context) {
+	id, ok := parseProjectId(c)
+	if !ok {
+		return
+	}
+	var req dto.AssignProjectTransactionsRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	if err := h.service.AssignTransactions(c.Request.Context(), userId, id, req.TransactionIds); err != nil {
+		h.sendProjectError(c, err, "failed to assign transactions")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// UnassignProjectTransaction godoc
+//
+//	@Summary		Remove a transaction from a project
+//	@Tags			projects
+//	@Param			id				path	int	true	"Project Id"
+//	@Param			transactionId	path	int	true	"Transaction Id"
+//	@Success		204
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/projects/{id}/transactions/{transactionId} [delete]
+func (h *ProjectHandler) UnassignProjectTransaction(c *gin.Context) {
+	id, ok := parseProjectId(c)
+	if !ok {
+		return
+	}
+	transactionId, err := strconv.ParseInt(c.Param("transactionId"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid transaction Id format")
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	if err := h.service.UnassignTransaction(c.Request.Context(), userId, id, transactionId); err != nil {
+		h.sendProjectError(c, err, "failed to remove transaction from project")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListProjectTransactions godoc
+//
+//	@Summary		List the transactions of a project
+//	@Tags			projects
+//	@Produce		json
+//	@Param			id	path		int	true	"Project Id"
+//	@Success		200	{array}		dto.TransactionResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/projects/{id}/transactions [get]
+func (h *ProjectHandler) ListProjectTransactions(c *gin.Context) {
+	id, ok := parseProjectId(c)
+	if !ok {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	transactions, err := h.service.ListTransactions(c.Request.Context(), userId, id)
+	if err != nil {
+		h.sendProjectError(c, err, "failed to list project transactions")
+		return
+	}
+	responses := []dto.TransactionResponse{}
+	for _, tx := range transactions {
+		responses = append(responses, toTransactionResponse(tx))
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, responses)
+}
+
+// GetProjectReport godoc
+//
+//	@Summary		Get a project report
+//	@Description	Compares a project's spending with its budget, in total, per category and month by month. Incomes assigned to the project, such as refunds, reduce the spending.
+//	@Tags			projects
+//	@Produce		json
+//	@Param			id	path		int	true	"Project Id"
+//	@Success		200	{object}	dto.ProjectReportResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/projects/{id}/report [get]
+func (h *ProjectHandler) GetProjectReport(c *gin.Context) {
+	id, ok := parseProjectId(c)
+	if !ok {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	report, err := h.service.GetReport(c.Request.Context(), userId, id)
+	if err != nil {
+		h.sendProjectError(c, err, "failed to build project report")
+		return
+	}
+
+	response := dto.ProjectReportResponse{
+		Project:    toProjectResponse(report.Project),
+		Planned:    report.Project.TotalBudget,
+		Spent:      report.Spent,
+		Remaining:  report.Remaining,
+		Categories: []dto.ProjectCategoryReportResponse{},
+		Timeline:   []dto.ProjectMonthResponse{},
+	}
+	for _, category := range report.Categories {
+		response.Categories = append(response.Categories, dto.ProjectCategoryReportResponse{
+			CategoryId:   category.CategoryId,
+			CategoryName: category.CategoryName,
+			Planned:      category.Planned,
+			Spent:        category.Spent,
+		})
+	}
+	for _, month := range report.Timeline {
+		response.Timeline = append(response.Timeline, dto.ProjectMonthResponse{
+			Year:       month.Year,
+			Month:      month.Month,
+			Spent:      month.Spent,
+			Cumulative: month.Cumulative,
+		})
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, response)
+}
+
+func (h *ProjectHandler) sendProjectError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrProjectNotFound),
+		errors.Is(err, service.ErrProjectTransactionNotFound):
+		dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidProject),
+		errors.Is(err, service.ErrInvalidProjectCategory):
+		dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+	case isUniqueViolation(err):
+		dto.SendErrorResponse(c, http.StatusConflict, "a project with this name already exists")
+	default:
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg(message)
+		dto.SendErrorResponse(c, http.StatusInternalServerError, message)
+	}
+}
+
+func parseProjectId(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid project Id format")
+		return 0, false
+	}
+	return id, true
+}
+
+func toProject(userId int64, req dto.ProjectRequest) model.Project {
+	// The dates were validated by the binding.
+	startDate, _ := time.Parse("2006-01-02", req.StartDate)
+	endDate, _ := time.Parse("2006-01-02", req.EndDate)
+	project := model.Project{
+		UserId:          userId,
+		Name:            req.Name,
+		Description:     req.Description,
+		TotalBudget:     req.TotalBudget,
+		StartDate:       startDate,
+		EndDate:         endDate,
+		CategoryBudgets: []model.ProjectCategoryBudget{},
+	}
+	for _, budget := range req.CategoryBudgets {
+		project.CategoryBudgets = append(project.CategoryBudgets, model.ProjectCategoryBudget{
+			CategoryId: budget.CategoryId,
+			Amount:     budget.Amount,
+		})
+	}
+	return project
+}
+
+func toProjectResponse(project model.Project) dto.ProjectResponse {
+	response := dto.ProjectResponse{
+		Id:              project.Id,
+		Name:            project.Name,
+		Description:     project.Description,
+		TotalBudget:     project.TotalBudget,
+		StartDate:       project.StartDate.Format("2006-01-02"),
+		EndDate:         project.EndDate.Format("2006-01-02"),
+		CategoryBudgets: project.CategoryBudgets,
+	}
+	if response.CategoryBudgets == nil {
+		response.CategoryBudgets = []model.ProjectCategoryBudget{}
+	}
+	return response
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/rs/zerolog"
+)
+
+type ReportHandler struct {
+	service *service.ReportService
+}
+
+func NewReportHandler(s *service.ReportService) *ReportHandler {
+	return &ReportHandler{service: s}
+}
+
+// GetMonthlyReport godoc
+//
+//	@Summary		Get a monthly report
+//	@Description	Totals the logged-in user's incomes and expenses of a fiscal month, overall and per category. Transfers are not counted. Defaults to the current fiscal month.
+//	@Tags			reports
+//	@Produce		json
+//	@Param			year	query		int	false	"Year (e.g., 2025)"
+//	@Param			month	query		int	false	"Month (1-12)"
+//	@Success		200		{object}	dto.MonthlyReportResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/reports/monthly [get]
+func (h *ReportHandler) GetMonthlyReport(c *gin.Context) {
+	userId := c.MustGet("userId").(int64)
+
+	currentYear, currentMonth, err := h.service.PeriodOf(c.Request.Context(), userId, time.Now().UTC())
+	if err != nil {
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to build monthly report")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to build monthly report")
+		return
+	}
+	month, _ := strconv.Atoi(c.DefaultQuery("month", strconv.Itoa(currentMonth)))
+	year, _ := strconv.Atoi(c.DefaultQuery("year", strconv.Itoa(currentYear)))
+
+	report, err := h.service.GetMonthlyReport(c.Request.Context(), userId, year, month)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidReportPeriod) {
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to build monthly report")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to build monthly report")
+		return
+	}
+
+	dto.SendSuccessResponse(c, http.StatusOK, toMonthlyReportResponse(report))
+}
+
+// QueryReport godoc
+//
+//	@Summary		Run a custom report
+//	@Description	Groups the logged-in user's transactions by up to three dimensions (category, account, type, day, week, month, year, weekday) and computes sum, count, avg, min or max for each group. Returns up to 1000 rows, or a pivot of the first dimension by the second when pivot is set.
+//	@Tags			reports
+//	@Accept			json
+//	@Produce		json
+//	@Param			query	body		dto.ReportQueryRequest	true	"Filters, dimensions and measures"
+//	@Success		200		{object}	dto.ReportQueryResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/reports/query [post]
+func (h *ReportHandler) QueryReport(c *gin.Context) {
+	var req dto.ReportQueryRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	query := repository.ReportQuery{Limit: req.Limit}
+	for _, dimension := range req.GroupBy {
+		query.GroupBy = append(query.GroupBy, repository.ReportDimension(dimension))
+	}
+	for _, measure := range req.Measures {
+		query.Measures = append(query.Measures, repository.ReportMeasure(measure))
+	}
+	filters := &query.Filters
+	filters.Description = req.Filters.Description
+	filters.Types = req.Filters.Types
+	filters.AccountIds = req.Filters.AccountIds
+	filters.CategoryIds = req.Filters.CategoryIds
+	filters.MinAmount = req.Filters.MinAmount
+	filters.MaxAmount = req.Filters.MaxAmount
+	// The binding already checked the date format.
+	if req.Filters.StartDate != nil {
+		startDate, _ := time.Parse("2006-01-02", *req.Filters.StartDate)
+		filters.StartDate = &startDate
+	}
+	if req.Filters.EndDate != nil {
+		endDate, _ := time.Parse("2006-01-02", *req.Filters.EndDate)
+		// To include the whole day, we set the time to the end of the day.
+		endOfDay := endDate.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+		filters.EndDate = &endOfDay
+	}
+
+	result, err := h.service.Query(c.Request.Context(), userId, query, req.Pivot)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidReportGroupBy),
+			errors.Is(err, service.ErrUnavailableReportDimension),
+			errors.Is(err, service.ErrInvalidReportMeasures),
+			errors.Is(err, service.ErrInvalidReportPivot),
+			errors.Is(err, service.ErrReportTooManyGroups):
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		default:
+			zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to run report query")
+			dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to run report query")
+		}
+		return
+	}
+
+	dto.SendSuccessResponse(c, http.StatusOK, toReportQueryResponse(result))
+}
+
+// toReportQueryResponse maps a report query result to its DTO.
+func toReportQueryResponse(result *service.ReportQueryResult) dto.ReportQueryResponse {
+	response := dto.ReportQueryResponse{GroupBy: []string{}, Measures: []string{}, Truncated: result.Truncated}
+	for _, dimension := range result.GroupBy {
+		response.GroupBy = append(response.GroupBy, string(dimension))
+	}
+	for _, measure := range result.Measures {
+		response.Measures = append(response.Measures, string(measure))
+	}
+	for _, row := range result.Rows {
+		response.Rows = append(response.Rows, dto.ReportQueryRowResponse{Keys: row.Keys, Ids: row.Ids, Values: row.Values})
+	}
+	if result.Pivot != nil {
+		response.Pivot = &dto.ReportPivotResponse{Columns: result.Pivot.Columns, ColumnIds: result.Pivot.ColumnIds, Rows: []dto.ReportPivotRowResponse{}}
+		for _, row := range result.Pivot.Rows {
+			response.Pivot.Rows = append(response.Pivot.Rows, dto.ReportPivotRowResponse{Key: row.Key, Id: row.Id, Values: row.Values})
+		}
+	}
+	return response
+}
+
+// toMonthlyReportResponse maps a monthly report to its DTO.
+func toMonthlyReportResponse(report *service.MonthlyReport) dto.MonthlyReportResponse {
+	categories := []dto.CategoryTotalResponse{}
+	for _, total := range report.Categories {
+		categories = append(categories, dto.CategoryTotalResponse{
+			CategoryId:   total.CategoryId,
+			CategoryName: total.CategoryName,
+			Income:       total.Income,
+			Expense:      total.Expense,
+		})
+	}
+
+	return dto.MonthlyReportResponse{
+		Year:  report.Year,
+		Month: report.Month,
+		Period: dto.StatementPeriod{
+			Start: report.Period.Start,
+			End:   report.Period.End,
+		},
+		TotalIncome:      report.TotalIncome,
+		TotalExpense:     report.TotalExpense,
+		CashExpense:      report.CashExpense,
+		BenefitExpense:   report.BenefitExpense,
+		Net:              report.Net,
+		TransactionCount: report.TransactionCount,
+		Categories:       categories,
+	}
+}
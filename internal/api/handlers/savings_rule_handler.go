@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/rs/zerolog"
+)
+
+type SavingsRuleHandler struct {
+	service *service.SavingsRuleService
+}
+
+func NewSavingsRuleHandler(s *service.SavingsRuleService) *SavingsRuleHandler {
+	return &SavingsRuleHandler{service: s}
+}
+
+// CreateSavingsRule godoc
+//
+//	@Summary		Create a savings rule
+//	@Description	Round-up rules round each expense of a checking or credit card account up to the next whole unit and move the difference into savings once a day. Percent-of-income rules move a percentage of each income in a category into savings as soon as it is created.
+//	@Tags			savings-rules
+//	@Accept			json
+//	@Produce		json
+//	@Param			rule	body		dto.SavingsRuleRequest	true	"Rule"
+//	@Success		201		{object}	model.SavingsRule
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/savings-rules [post]
+func (h *SavingsRuleHandler) CreateSavingsRule(c *gin.Context) {
+	var req dto.SavingsRuleRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	rule, err := h.service.CreateRule(c.Request.Context(), model.SavingsRule{
+		UserId:           userId,
+		Type:             req.Type,
+		SavingsAccountId: req.SavingsAccountId,
+		AccountId:        req.AccountId,
+		FundingAccountId: req.FundingAccountId,
+		CategoryId:       req.CategoryId,
+		Percent:          req.Percent,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSavingsRule) {
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to create savings rule")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to create savings rule")
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusCreated, rule)
+}
+
+// ListSavingsRules godoc
+//
+//	@Summary		List savings rules
+//	@Tags			savings-rules
+//	@Produce		json
+//	@Success		200	{array}		model.SavingsRule
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/savings-rules [get]
+func (h *SavingsRuleHandler) ListSavingsRules(c *gin.Context) {
+	userId := c.MustGet("userId").(int64)
+
+	rules, err := h.service.ListRules(c.Request.Context(), userId)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to list savings rules")
+		return
+	}
+	if rules == nil {
+		rules = []model.SavingsRule{}
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, rules)
+}
+
+// DeleteSavingsRule godoc
+//
+//	@Summary		Delete a savings rule
+//	@Description	Stops a rule from saving. Money it already moved stays in the savings account, and round-ups not settled yet are dropped.
+//	@Tags			savings-rules
+//	@Param			id	path	int	true	"Rule Id"
+//	@Success		204
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/savings-rules/{id} [delete]
+func (h *SavingsRuleHandler) DeleteSavingsRule(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid rule Id format")
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	if err := h.service.DeleteRule(c.Request.Context(), id, userId); err != nil {
+		if errors.Is(err, service.ErrSavingsRuleNotFound) {
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to delete savings rule")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListSavingsRuleRuns godoc
+//
+//	@Summary		List what a savings rule did
+//	@Description	Returns each transaction that triggered the rule, newest first, with the amount it saved and the transfer that moved it. Round-ups have no transfer until their day is settled, and income a rule could not move, because of the funding account's overdraft limit or the monthly transaction quota, is logged without one.
+//	@Tags			savings-rules
+//	@Produce		json
+//	@Param			id	path		int	true	"Rule Id"
+//	@Success		200	{array}		model.SavingsRuleRun
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/savings-rules/{id}/runs [get]
+func (h *SavingsRuleHandler) ListSavingsRuleRuns(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid rule Id format")
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	runs, err := h.service.ListRuns(c.Request.Context(), userId, id)
+	if err != nil {
+		if errors.Is(err, service.ErrSavingsRuleNotFound) {
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to list savings rule runs")
+		return
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, runs)
+}
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/pdf"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/rs/zerolog"
+)
+
+// SharePasswordHeader carries the password of a protected share link.
+const SharePasswordHeader = "X-Share-Password"
+
+type ShareLinkHandler struct {
+	service *service.ShareLinkService
+}
+
+func NewShareLinkHandler(s *service.ShareLinkService) *ShareLinkHandler {
+	return &ShareLinkHandler{service: s}
+}
+
+// CreateShareLink godoc
+//
+//	@Summary		Create a read-only share link
+//	@Description	Creates an expiring link that anyone can open without logging in, optionally protected by a password. The token is only returned in this response.
+//	@Tags			share-links
+//	@Accept			json
+//	@Produce		json
+//	@Param			link	body		dto.CreateShareLinkRequest	true	"Content to share and link settings"
+//	@Success		201		{object}	dto.CreateShareLinkResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/share-links [post]
+func (h *ShareLinkHandler) CreateShareLink(c *gin.Context) {
+	var req dto.CreateShareLinkRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	link, token, err := h.service.CreateLink(c.Request.Context(), userId, service.CreateShareLinkInput{
+		Scope: req.Scope,
+		Params: model.ShareLinkParams{
+			Year:      req.Year,
+			Month:     req.Month,
+			AccountId: req.AccountId,
+			Filter:    req.Filter,
+		},
+		ExpiresIn: time.Duration(req.ExpiresInHours) * time.Hour,
+		Password:  req.Password,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidShareLinkScope) ||
+			errors.Is(err, service.ErrInvalidShareLinkParams) ||
+			errors.Is(err, service.ErrShareLinkTTLTooLong) {
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to create share link")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to create share link")
+		return
+	}
+
+	dto.SendSuccessResponse(c, http.StatusCreated, dto.CreateShareLinkResponse{
+		ShareLinkResponse: toShareLinkResponse(*link),
+		Token:             token,
+		URL:               h.service.URL(token),
+	})
+}
+
+// ListShareLinks godoc
+//
+//	@Summary		List share links
+//	@Description	Returns the logged-in user's share links with their access counts, including expired and revoked ones.
+//	@Tags			share-links
+//	@Produce		json
+//	@Success		200	{array}		dto.ShareLinkResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/share-links [get]
+func (h *ShareLinkHandler) ListShareLinks(c *gin.Context) {
+	userId := c.MustGet("userId").(int64)
+
+	links, err := h.service.ListLinks(c.Request.Context(), userId)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to list share links")
+		return
+	}
+
+	responses := []dto.ShareLinkResponse{}
+	for _, link := range links {
+		responses = append(responses, toShareLinkResponse(link))
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, responses)
+}
+
+// RevokeShareLink godoc
+//
+//	@Summary		Revoke a share link
+//	@Description	Disables a share link immediately. The link stays in the list for auditing.
+//	@Tags			share-links
+//	@Param			id	path	int	true	"Share link Id"
+//	@Success		204
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/share-links/{id} [delete]
+func (h *ShareLinkHandler) RevokeShareLink(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid share link Id format")
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	if err := h.service.RevokeLink(c.Request.Context(), id, userId); err != nil {
+		if errors.Is(err, service.ErrShareLinkNotFound) {
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to revoke share link")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetSharedContent godoc
+//
+//	@Summary		Open a share link
+//	@Description	Returns the shared report, statement or transactions as JSON or PDF. No login is required; password-protected links need the X-Share-Password header.
+//	@Tags			share-links
+//	@Produce		json
+//	@Produce		application/pdf
+//	@Param			token				path		string	true	"Share link token"
+//	@Param			format				query		string	false	"Response format"	Enums(json, pdf)
+//	@Param			X-Share-Password	header		string	false	"Password of a protected link"
+//	@Success		200					{object}	dto.SharedContentResponse
+//	@Failure		401					{object}	dto.ErrorResponse
+//	@Failure		404					{object}	dto.ErrorResponse
+//	@Failure		429					{object}	dto.ErrorResponse
+//	@Router			/shared/{token} [get]
+func (h *ShareLinkHandler) GetSharedContent(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "pdf" {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "format must be json or pdf")
+		return
+	}
+
+	content, err := h.service.OpenLink(c.Request.Context(), c.Param("token"), c.GetHeader(SharePasswordHeader))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrShareLinkNotFound):
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrShareLinkPasswordRequired):
+			dto.SendErrorResponse(c, http.StatusUnauthorized, err.Error())
+		case errors.Is(err, service.ErrShareLinkTooManyAttempts):
+			dto.SendErrorResponse(c, http.StatusTooManyRequests, err.Error())
+		default:
+			zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to open share link")
+			dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to load shared content")
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	if format == "pdf" {
+		filename := fmt.Sprintf("%s-%d.pdf", content.Link.Scope, content.Link.Id)
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
+		c.Data(http.StatusOK, "application/pdf", renderSharedPDF(content))
+		return
+	}
+
+	response := dto.SharedContentResponse{
+		Scope:     content.Link.Scope,
+		ExpiresAt: content.Link.ExpiresAt,
+	}
+	switch {
+	case content.MonthlyReport != nil:
+		report := toMonthlyReportResponse(content.MonthlyReport)
+		response.MonthlyReport = &report
+	case content.Statement != nil:
+		statement := toStatementResponse(content.Statement)
+		response.Statement = &statement
+	default:
+		response.Transactions = []dto.TransactionResponse{}
+		for _, tx := range content.Transactions {
+			response.Transactions = append(response.Transactions, toTransactionResponse(tx))
+		}
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, response)
+}
+
+func toShareLinkResponse(link model.ShareLink) dto.ShareLinkResponse {
+	return dto.ShareLinkResponse{
+		Id:                link.Id,
+		Scope:             link.Scope,
+		Params:            link.Params,
+		PasswordProtected: link.PasswordHash != nil,
+		ExpiresAt:         link.ExpiresAt,
+		RevokedAt:         link.RevokedAt,
+		AccessCount:       link.AccessCount,
+		LastAccessedAt:    link.LastAccessedAt,
+		CreatedAt:         link.CreatedAt,
+	}
+}
+
+// renderSharedPDF lays the shared content out as a plain-text PDF.
+func renderSharedPDF(content *service.SharedContent) []byte {
+	var doc *pdf.Document
+
+	switch {
+	case content.MonthlyReport != nil:
+		report := content.MonthlyReport
+		doc = pdf.New(fmt.Sprintf("Monthly report %04d-%02d", report.Year, report.Month))
+		doc.Heading(fmt.Sprintf("Monthly report - %s %d", time.Month(report.Month), report.Year))
+		doc.Blank()
+		doc.Textf("Total income:   %s", report.TotalIncome.StringFixed(2))
+		doc.Textf("Total expense:  %s", report.TotalExpense.StringFixed(2))
+		doc.Textf("  Cash:         %s", report.CashExpense.StringFixed(2))
+		doc.Textf("  Benefits:     %s", report.BenefitExpense.StringFixed(2))
+		doc.Textf("Net:            %s", report.Net.StringFixed(2))
+		doc.Textf("Transactions:   %d", report.TransactionCount)
+		doc.Blank()
+		doc.Heading("By category")
+		for _, total := range report.Categories {
+			doc.Textf("%s - income %s, expense %s", total.CategoryName, total.Income.StringFixed(2), total.Expense.StringFixed(2))
+		}
+	case content.Statement != nil:
+		statement := content.Statement
+		doc = pdf.New("Statement " + statement.AccountName)
+		doc.Heading("Statement - " + statement.AccountName)
+		doc.Blank()
+		doc.Textf("Period:    %s to %s", statement.StatementPeriod.Start.Format(time.DateOnly), statement.StatementPeriod.End.Format(time.DateOnly))
+		doc.Textf("Due date:  %s", statement.PaymentDueDate.Format(time.DateOnly))
+		doc.Textf("Total:     %s", statement.StatementTotal.StringFixed(2))
+		doc.Blank()
+		doc.Heading("Transactions")
+		writeTransactionLines(doc, statement.Transactions)
+	default:
+		doc = pdf.New("Transactions")
+		doc.Heading("Transactions")
+		doc.Blank()
+		writeTransactionLines(doc, content.Transactions)
+	}
+
+	doc.Blank()
+	doc.Textf("Shared read-only link, valid until %s.", content.Link.ExpiresAt.UTC().Format(time.RFC1123))
+	return doc.Bytes()
+}
+
+func writeTransactionLines(doc *pdf.Document, transactions []model.Transaction) {
+	if len(transactions) == 0 {
+		doc.Text("No transactions.")
+		return
+	}
+	for _, tx := range transactions {
+		doc.Textf("%s  %-8s  %12s  %s", tx.Date.Format(time.DateOnly), tx.Type, tx.Amount.StringFixed(2), tx.Description)
+	}
+}
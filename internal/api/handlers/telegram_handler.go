@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/telegram"
+	"github.com/rs/zerolog"
+)
+
+// TelegramSecretHeader carries the secret Telegram sends with every webhook call.
+const TelegramSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+type TelegramHandler struct {
+	service       *service.BotService
+	botUsername   string
+	webhookSecret string
+}
+
+func NewTelegramHandler(s *service.BotService, botUsername, webhookSecret string) *TelegramHandler {
+	return &TelegramHandler{service: s, botUsername: botUsername, webhookSecret: webhookSecret}
+}
+
+// CreateTelegramLinkCode godoc
+//
+//	@Summary		Create a Telegram link code
+//	@Description	Issues a one-time code that links a Telegram chat to the logged-in user. Send "/start CODE" to the bot, or open the deep link, before the code expires.
+//	@Tags			telegram
+//	@Produce		json
+//	@Success		201	{object}	dto.TelegramLinkCodeResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/telegram/link-codes [post]
+func (h *TelegramHandler) CreateTelegramLinkCode(c *gin.Context) {
+	userId := c.MustGet("userId").(int64)
+
+	code, expiresAt, err := h.service.CreateLinkCode(c.Request.Context(), userId)
+	if err != nil {
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to create telegram link code")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to create link code")
+		return
+	}
+
+	response := dto.TelegramLinkCodeResponse{Code: code, ExpiresAt: expiresAt}
+	if h.botUsername != "" {
+		deepLink := "https://t.me/" + h.botUsername + "?start=" + code
+		response.DeepLink = &deepLink
+	}
+	dto.SendSuccessResponse(c, http.StatusCreated, response)
+}
+
+// ListTelegramChats godoc
+//
+//	@Summary		List linked Telegram chats
+//	@Tags			telegram
+//	@Produce		json
+//	@Success		200	{array}		model.TelegramChat
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/telegram/chats [get]
+func (h *TelegramHandler) ListTelegramChats(c *gin.Context) {
+	userId := c.MustGet("userId").(int64)
+
+	chats, err := h.service.ListChats(c.Request.Context(), userId)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to list telegram chats")
+		return
+	}
+	if chats == nil {
+		chats = []model.TelegramChat{}
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, chats)
+}
+
+// UnlinkTelegramChat godoc
+//
+//	@Summary		Unlink a Telegram chat
+//	@Description	The bot stops answering the chat and sending it budget alerts.
+//	@Tags			telegram
+//	@Param			chatId	path	int	true	"Telegram chat Id"
+//	@Success		204
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/telegram/chats/{chatId} [delete]
+func (h *TelegramHandler) UnlinkTelegramChat(c *gin.Context) {
+	chatId, err := strconv.ParseInt(c.Param("chatId"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid chat Id format")
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	if err := h.service.Unlink(c.Request.Context(), userId, chatId); err != nil {
+		if errors.Is(err, service.ErrTelegramChatNotFound) {
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to unlink telegram chat")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// TelegramWebhook receives the updates pushed by Telegram when the bot runs in
+// webhook mode. It is not part of the public API documentation.
+func (h *TelegramHandler) TelegramWebhook(c *gin.Context) {
+	secret := c.GetHeader(TelegramSecretHeader)
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(h.webhookSecret)) != 1 {
+		dto.SendErrorResponse(c, http.StatusUnauthorized, "invalid webhook secret")
+		return
+	}
+
+	var update telegram.Update
+	if err := c.ShouldBindJSON(&update); err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	// Telegram retries updates that are not acknowledged, so failures are only logged.
+	telegram.Dispatch(c.Request.Context(), h.service, update)
+	c.Status(http.StatusOK)
+}
@@ -26,13 +26,14 @@ func NewTransactionHandler(s *service.TransactionService) *TransactionHandler {
 // CreateTransaction godoc
 //
 //	@Summary		Cria uma nova transação
-//	@Description	Adiciona uma nova transação ao sistema. Para transferências, o campo destination_account_id é obrigatório.
+//	@Description	Adiciona uma nova transação ao sistema. Para transferências, o campo destination_account_id é obrigatório. Compras internacionais no cartão de crédito informam original_currency, original_amount, exchange_rate e spread_rate; o amount é calculado e o IOF é lançado como uma transação vinculada.
 //	@Tags			transactions
 //	@Accept			json
 //	@Produce		json
 //	@Param			transaction	body		dto.CreateTransactionRequest	true	"Dados da Transação para Criar"
 //	@Success		201			{object}	dto.TransactionResponse
 //	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse	"Monthly transaction or storage quota exceeded"
 //	@Failure		500			{object}	dto.ErrorResponse
 //	@Security		BearerAuth
 //	@Router			/transactions [post]
@@ -53,10 +54,18 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 		AccountId:            req.AccountId,
 		CategoryId:           req.CategoryId,
 		DestinationAccountId: req.DestinationAccountId,
+		CardId:               req.CardId,
+		OriginalCurrency:     req.OriginalCurrency,
+		OriginalAmount:       req.OriginalAmount,
+		ExchangeRate:         req.ExchangeRate,
+		SpreadRate:           req.SpreadRate,
 	}
 
 	id, err := h.service.CreateTransaction(c.Request.Context(), tx)
 	if err != nil {
+		if sendQuotaError(c, err) {
+			return
+		}
 		// O serviço agora retorna erros de negócio específicos
 		dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -141,6 +150,13 @@ func (h *TransactionHandler) ListTransactions(c *gin.Context) {
 			CategoryId:           tx.CategoryId,
 			CategoryName:         tx.CategoryName,
 			DestinationAccountId: tx.DestinationAccountId,
+			ProjectId:            tx.ProjectId,
+			CardId:               tx.CardId,
+			OriginalCurrency:     tx.OriginalCurrency,
+			OriginalAmount:       tx.OriginalAmount,
+			ExchangeRate:         tx.ExchangeRate,
+			SpreadRate:           tx.SpreadRate,
+			FeeOfTransactionId:   tx.FeeOfTransactionId,
 			CreatedAt:            tx.CreatedAt,
 		})
 	}
@@ -184,6 +200,13 @@ func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 		CategoryId:           tx.CategoryId,
 		CategoryName:         tx.CategoryName,
 		DestinationAccountId: tx.DestinationAccountId,
+		ProjectId:            tx.ProjectId,
+		CardId:               tx.CardId,
+		OriginalCurrency:     tx.OriginalCurrency,
+		OriginalAmount:       tx.OriginalAmount,
+		ExchangeRate:         tx.ExchangeRate,
+		SpreadRate:           tx.SpreadRate,
+		FeeOfTransactionId:   tx.FeeOfTransactionId,
 		CreatedAt:            tx.CreatedAt,
 	}
 	dto.SendSuccessResponse(c, http.StatusOK, response)
@@ -214,14 +237,19 @@ func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
 	}
 
 	tx := model.Transaction{
-		Id:          id,
-		UserId:      userId,
-		Description: req.Description,
-		Amount:      req.Amount,
-		Date:        req.Date,
-		Type:        req.Type,
-		AccountId:   req.AccountId,
-		CategoryId:  req.CategoryId,
+		Id:               id,
+		UserId:           userId,
+		Description:      req.Description,
+		Amount:           req.Amount,
+		Date:             req.Date,
+		Type:             req.Type,
+		AccountId:        req.AccountId,
+		CategoryId:       req.CategoryId,
+		CardId:           req.CardId,
+		OriginalCurrency: req.OriginalCurrency,
+		OriginalAmount:   req.OriginalAmount,
+		ExchangeRate:     req.ExchangeRate,
+		SpreadRate:       req.SpreadRate,
 	}
 
 	updatedTx, err := h.service.UpdateTransaction(c.Request.Context(), tx)
@@ -230,6 +258,10 @@ func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
 			dto.SendErrorResponse(c, http.StatusNotFound, "transaction not found")
 			return
 		}
+		if isTransactionRuleError(err) {
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to update transaction")
 		return
 	}
@@ -245,6 +277,13 @@ func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
 		CategoryId:           updatedTx.CategoryId,
 		CategoryName:         updatedTx.CategoryName,
 		DestinationAccountId: updatedTx.DestinationAccountId,
+		ProjectId:            updatedTx.ProjectId,
+		CardId:               updatedTx.CardId,
+		OriginalCurrency:     updatedTx.OriginalCurrency,
+		OriginalAmount:       updatedTx.OriginalAmount,
+		ExchangeRate:         updatedTx.ExchangeRate,
+		SpreadRate:           updatedTx.SpreadRate,
+		FeeOfTransactionId:   updatedTx.FeeOfTransactionId,
 		CreatedAt:            updatedTx.CreatedAt,
 	}
 
@@ -282,6 +321,10 @@ func (h *TransactionHandler) PatchTransaction(c *gin.Context) {
 			dto.SendErrorResponse(c, http.StatusNotFound, "transaction not found")
 			return
 		}
+		if isTransactionRuleError(err) {
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to update transaction")
 		return
 	}
@@ -298,6 +341,13 @@ func (h *TransactionHandler) PatchTransaction(c *gin.Context) {
 		CategoryId:           updatedTx.CategoryId,
 		CategoryName:         updatedTx.CategoryName,
 		DestinationAccountId: updatedTx.DestinationAccountId,
+		ProjectId:            updatedTx.ProjectId,
+		CardId:               updatedTx.CardId,
+		OriginalCurrency:     updatedTx.OriginalCurrency,
+		OriginalAmount:       updatedTx.OriginalAmount,
+		ExchangeRate:         updatedTx.ExchangeRate,
+		SpreadRate:           updatedTx.SpreadRate,
+		FeeOfTransactionId:   updatedTx.FeeOfTransactionId,
 		CreatedAt:            updatedTx.CreatedAt,
 	})
 }
@@ -328,3 +378,42 @@ func (h *TransactionHandler) DeleteTransaction(c *gin.Context) {
 
 	dto.SendSuccessResponse(c, http.StatusNoContent, nil)
 }
+
+// toTransactionResponse maps a transaction model to its DTO.
+func toTransactionResponse(tx model.Transaction) dto.TransactionResponse {
+	return dto.TransactionResponse{
+		Id:                   tx.Id,
+		Description:          tx.Description,
+		Amount:               tx.Amount,
+		Date:                 tx.Date,
+		Type:                 tx.Type,
+		AccountId:            tx.AccountId,
+		AccountName:          tx.AccountName,
+		CategoryId:           tx.CategoryId,
+		CategoryName:         tx.CategoryName,
+		DestinationAccountId: tx.DestinationAccountId,
+		ProjectId:            tx.ProjectId,
+		CardId:               tx.CardId,
+		OriginalCurrency:     tx.OriginalCurrency,
+		OriginalAmount:       tx.OriginalAmount,
+		ExchangeRate:         tx.ExchangeRate,
+		SpreadRate:           tx.SpreadRate,
+		FeeOfTransactionId:   tx.FeeOfTransactionId,
+		CreatedAt:            tx.CreatedAt,
+	}
+}
+
+// isTransactionRuleError reports whether an update was rejected by an account
+// rule rather than a failure, so it is answered with 400.
+func isTransactionRuleError(err error) bool {
+	return errors.Is(err, service.ErrBenefitCategoryNotAllowed) ||
+		errors.Is(err, service.ErrBenefitAccountTransfer) ||
+		errors.Is(err, service.ErrPointsAccountTransaction) ||
+		errors.Is(err, service.ErrAssetAccountTransaction) ||
+		errors.Is(err, service.ErrSourceAccountNotFound) ||
+		errors.Is(err, service.ErrNewAccountNotFound) ||
+		errors.Is(err, service.ErrCardNotFound) ||
+		errors.Is(err, service.ErrInvalidForeignPurchase) ||
+		errors.Is(err, service.ErrForeignPurchaseRequiresCreditCard) ||
+		errors.Is(err, service.ErrForeignPurchaseAmount)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/rs/zerolog"
+)
+
+type UsageHandler struct {
+	service *service.QuotaService
+}
+
+func NewUsageHandler(s *service.QuotaService) *UsageHandler {
+	return &UsageHandler{service: s}
+}
+
+// GetMyUsage godoc
+//
+//	@Summary		Get the logged-in user's quota usage
+//	@Description	Returns how much of each per-user quota has been used. A limit of 0 means unlimited.
+//	@Tags			users
+//	@Produce		json
+//	@Success		200	{object}	dto.UsageResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		429	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/me/usage [get]
+func (h *UsageHandler) GetMyUsage(c *gin.Context) {
+	userId := c.MustGet("userId").(int64)
+
+	usage, err := h.service.GetUsage(c.Request.Context(), userId)
+	if err != nil {
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to get usage")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to get usage")
+		return
+	}
+
+	dto.SendSuccessResponse(c, http.StatusOK, dto.UsageResponse{
+		Accounts:              toQuotaMetricResponse(usage.Accounts),
+		TransactionsThisMonth: toQuotaMetricResponse(usage.TransactionsThisMonth),
+		StorageBytes:          toQuotaMetricResponse(usage.StorageBytes),
+		RequestsToday:         toQuotaMetricResponse(usage.RequestsToday),
+	})
+}
+
+func toQuotaMetricResponse(metric model.QuotaMetric) dto.QuotaMetricResponse {
+	response := dto.QuotaMetricResponse{Used: metric.Used, Limit: metric.Limit}
+	if metric.Limit > 0 {
+		remaining := max(metric.Limit-metric.Used, 0)
+		response.Remaining = &remaining
+	}
+	return response
+}
+
+// sendQuotaError answers with 403 and the quota error code when err is a quota
+// violation, and reports whether it did.
+func sendQuotaError(c *gin.Context, err error) bool {
+	var quotaErr *service.QuotaExceededError
+	if !errors.As(err, &quotaErr) {
+		return false
+	}
+	dto.SendCodedError(c, http.StatusForbidden, quotaErr.Code, quotaErr.Error(), map[string]string{
+		"limit": strconv.FormatInt(quotaErr.Limit, 10),
+		"used":  strconv.FormatInt(quotaErr.Used, 10),
+	})
+	return true
+}
@@ -95,12 +95,81 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 	}
 
 	// Mapeia o modelo de domínio para o DTO de resposta
-	response := dto.UserResponse{
-		Id:        user.Id,
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
+	dto.SendSuccessResponse(c, http.StatusOK, toUserResponse(user))
+}
+
+// UpdateFiscalMonth godoc
+//
+//	@Summary		Set the logged-in user's fiscal month
+//	@Description	Budgets and reports count each month from start_day, e.g. the 25th through the 24th for someone paid on the 25th. Short months start on their last day. With label "start" that month is named after the calendar month it starts in, with "end" after the one it ends in.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			fiscal_month	body		dto.FiscalMonthRequest	true	"Start day and label"
+//	@Success		200				{object}	dto.UserResponse
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/me/fiscal-month [put]
+func (h *UserHandler) UpdateFiscalMonth(c *gin.Context) {
+	var req dto.FiscalMonthRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	user, err := h.service.UpdateFiscalMonth(c.Request.Context(), userId, req.StartDay, req.Label)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidFiscalMonth) {
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to update fiscal month")
+		return
+	}
+
+	dto.SendSuccessResponse(c, http.StatusOK, toUserResponse(user))
+}
+
+// toUserResponse maps a user to its DTO.
+func toUserResponse(user *model.User) dto.UserResponse {
+	return dto.UserResponse{
+		Id:                  user.Id,
+		Name:                user.Name,
+		Email:               user.Email,
+		FiscalMonthStartDay: user.FiscalMonthStartDay,
+		FiscalMonthLabel:    user.FiscalMonthLabel,
+		CreatedAt:           user.CreatedAt,
+	}
+}
+
+// ChangePassword godoc
+//
+//	@Summary		Change the logged-in user's password
+//	@Description	Replaces the password after checking the current one. Required after an admin forces a password reset.
+//	@Tags			users
+//	@Accept			json
+//	@Param			passwords	body	dto.ChangePasswordRequest	true	"Current and new passwords"
+//	@Success		204
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/me/password [put]
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	var req dto.ChangePasswordRequest
+	if !dto.BindAndValidate(c, &req) {
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	err := h.service.ChangePassword(c.Request.Context(), userId, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCurrentPassword) {
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to change password")
+		return
 	}
 
-	dto.SendSuccessResponse(c, http.StatusOK, response)
+	c.Status(http.StatusNoContent)
 }
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/rs/zerolog"
+)
+
+type YieldHandler struct {
+	service *service.YieldService
+}
+
+func NewYieldHandler(s *service.YieldService) *YieldHandler {
+	return &YieldHandler{service: s}
+}
+
+// ListYieldAccruals godoc
+//
+//	@Summary		List savings yield
+//	@Description	Returns the yield posted to a savings account for each period, newest first, with the income tax withheld from it. The IR rate follows the regressive table by the days since the account started earning yield, for the whole balance: later deposits are not counted on their own.
+//	@Tags			accounts
+//	@Produce		json
+//	@Param			id	path		int	true	"Account Id"
+//	@Success		200	{array}		model.YieldAccrual
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/accounts/{id}/yields [get]
+func (h *YieldHandler) ListYieldAccruals(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid account Id format")
+		return
+	}
+	userId := c.MustGet("userId").(int64)
+
+	accruals, err := h.service.ListAccruals(c.Request.Context(), userId, id)
+	if err != nil {
+		if errors.Is(err, service.ErrYieldAccountNotFound) {
+			dto.SendErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to list savings yield")
+		return
+	}
+	if accruals == nil {
+		accruals = []model.YieldAccrual{}
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, accruals)
+}
+
+// ListIndexRates godoc
+//
+//	@Summary		List index rates
+//	@Description	Returns the daily rates of the CDI or the Selic loaded in the instance, in percent a year. The period defaults to the last 30 days.
+//	@Tags			index-rates
+//	@Produce		json
+//	@Param			name	path		string	true	"Index"	Enums(cdi, selic)
+//	@Param			from	query		string	false	"First day, YYYY-MM-DD"
+//	@Param			to		query		string	false	"Last day, YYYY-MM-DD"
+//	@Success		200		{array}		dto.IndexRateResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/index-rates/{name} [get]
+func (h *YieldHandler) ListIndexRates(c *gin.Context) {
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -30)
+	var err error
+	if value := c.Query("from"); value != "" {
+		if from, err = time.Parse("2006-01-02", value); err != nil {
+			dto.SendErrorResponse(c, http.StatusBadRequest, "from must be a YYYY-MM-DD date")
+			return
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		if to, err = time.Parse("2006-01-02", value); err != nil {
+			dto.SendErrorResponse(c, http.StatusBadRequest, "to must be a YYYY-MM-DD date")
+			return
+		}
+	}
+
+	rates, err := h.service.ListIndexRates(c.Request.Context(), model.IndexName(c.Param("name")), from, to.AddDate(0, 0, 1))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidIndexRates) {
+			dto.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("failed to list index rates")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to list index rates")
+		return
+	}
+
+	responses := []dto.IndexRateResponse{}
+	for _, rate := range rates {
+		responses = append(responses, dto.IndexRateResponse{Date: rate.Date.Format("2006-01-02"), AnnualRate: rate.AnnualRate})
+	}
+	dto.SendSuccessResponse(c, http.StatusOK, responses)
+}
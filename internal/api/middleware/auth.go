@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -8,10 +10,15 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
 	"github.com/rs/zerolog"
 )
 
-func AuthMiddleware(jwtKey string, logger zerolog.Logger) gin.HandlerFunc {
+// ActAsUserHeader lets a collaborator act on behalf of the user who granted them
+// access. Its value is the owner's user Id.
+const ActAsUserHeader = "X-Act-As-User"
+
+func AuthMiddleware(jwtKey string, userRepo repository.UserRepository, collaboratorRepo repository.CollaboratorRepository, logger zerolog.Logger) gin.HandlerFunc {
 	key := []byte(jwtKey)
 	logger = logger.With().Str("middleware", "AuthMiddleware").Logger()
 
@@ -49,8 +56,111 @@ func AuthMiddleware(jwtKey string, logger zerolog.Logger) gin.HandlerFunc {
 			return
 		}
 
+		// The user is loaded on every request so that disabling an account
+		// takes effect immediately, even for tokens that are still valid.
+		user, err := userRepo.GetById(c.Request.Context(), userId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				logger.Warn().Int64("userId", userId).Msg("Token subject does not exist")
+				dto.SendErrorResponse(c, http.StatusUnauthorized, "invalid token")
+				return
+			}
+			logger.Error().Err(err).Msg("Could not load user for token")
+			dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to authenticate user")
+			return
+		}
+		if user.DisabledAt != nil {
+			logger.Warn().Int64("userId", userId).Msg("Disabled user tried to access the API")
+			dto.SendErrorResponse(c, http.StatusForbidden, "user account is disabled")
+			return
+		}
+
+		c.Set("passwordResetRequired", user.PasswordResetRequired)
+		if c.GetHeader(ActAsUserHeader) != "" {
+			actOnBehalf(c, collaboratorRepo, userId, logger)
+			return
+		}
+
 		logger.Debug().Int64("userId", userId).Msg("Token is valid. Setting userId in context.")
 		c.Set("userId", userId)
+		c.Set("isAdmin", user.IsAdmin)
+		c.Next()
+	}
+}
+
+// actOnBehalf switches the request to the owner named in ActAsUserHeader when
+// the collaborator holds an active grant. Handlers then see the owner's userId,
+// repositories restrict reads to the grant scope and every mutating method is
+// rejected, as collaborator access is read-only.
+func actOnBehalf(c *gin.Context, collaboratorRepo repository.CollaboratorRepository, collaboratorId int64, logger zerolog.Logger) {
+	ownerId, err := strconv.ParseInt(c.GetHeader(ActAsUserHeader), 10, 64)
+	if err != nil {
+		dto.SendErrorResponse(c, http.StatusBadRequest, "invalid "+ActAsUserHeader+" header")
+		return
+	}
+
+	grant, err := collaboratorRepo.GetActive(c.Request.Context(), ownerId, collaboratorId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Warn().Int64("userId", collaboratorId).Int64("ownerId", ownerId).Msg("Collaborator access denied")
+			dto.SendErrorResponse(c, http.StatusForbidden, "no active collaborator access for this user")
+			return
+		}
+		logger.Error().Err(err).Msg("Could not load collaborator grant")
+		dto.SendErrorResponse(c, http.StatusInternalServerError, "failed to authenticate user")
+		return
+	}
+
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		dto.SendErrorResponse(c, http.StatusForbidden, "collaborator access is read-only")
+		return
+	}
+
+	c.Request = c.Request.WithContext(repository.WithAccessScope(c.Request.Context(), grant.Scope()))
+	c.Set("userId", ownerId)
+	c.Set("actorId", collaboratorId)
+	// Admin privileges never carry over to someone else's data.
+	c.Set("isAdmin", false)
+	c.Next()
+}
+
+// AdminMiddleware only lets instance admins through. It must run after AuthMiddleware.
+func AdminMiddleware(logger zerolog.Logger) gin.HandlerFunc {
+	logger = logger.With().Str("middleware", "AdminMiddleware").Logger()
+
+	return func(c *gin.Context) {
+		if !c.GetBool("isAdmin") {
+			logger.Warn().Int64("userId", c.GetInt64("userId")).Msg("Non-admin user tried to access admin routes")
+			dto.SendErrorResponse(c, http.StatusForbidden, "admin privileges required")
+			return
+		}
+		c.Next()
+	}
+}
+
+// OwnerOnly rejects collaborators acting on behalf of another user, for routes
+// that manage the owner's sharing settings or only make sense to the owner.
+// It must run after AuthMiddleware.
+func OwnerOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, acting := c.Get("actorId"); acting {
+			dto.SendErrorResponse(c, http.StatusForbidden, "not available to collaborators")
+			return
+		}
+		c.Next()
+	}
+}
+
+// PasswordResetGuard blocks users whose password reset was forced by an admin
+// until they choose a new password. It must run after AuthMiddleware.
+func PasswordResetGuard() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool("passwordResetRequired") {
+			dto.SendErrorResponse(c, http.StatusForbidden, "password change required")
+			return
+		}
 		c.Next()
 	}
 }
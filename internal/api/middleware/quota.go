@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+)
+
+// QuotaMiddleware counts the authenticated user's API requests and rejects them
+// once the daily quota is used up. It must run after AuthMiddleware.
+func QuotaMiddleware(quotas *service.QuotaService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := quotas.AllowRequest(c.GetInt64("userId"))
+		var quotaErr *service.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			dto.SendCodedError(c, http.StatusTooManyRequests, quotaErr.Code, "daily API request quota exceeded", map[string]string{
+				"limit": strconv.FormatInt(quotaErr.Limit, 10),
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,381 @@
+// Package chart renders bar and donut charts as standalone SVG documents, so
+// clients that cannot draw charts, such as e-mail digests or chat bots, can
+// embed them as images. The output only depends on the input, which keeps it
+// cacheable and testable against golden files.
+package chart
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Theme holds the colors of a chart. Series take the palette colors in order.
+type Theme struct {
+	Background string
+	Text       string
+	Grid       string
+	Axis       string
+	Palette    []string
+}
+
+// Themes are the themes accepted by the chart endpoints.
+var Themes = map[string]Theme{
+	"light": {
+		Background: "#ffffff",
+		Text:       "#1f2933",
+		Grid:       "#e4e7eb",
+		Axis:       "#9aa5b1",
+		Palette:    []string{"#2f80ed", "#eb5757", "#27ae60", "#f2994a", "#9b51e0", "#56ccf2", "#f2c94c", "#6fcf97", "#bb6bd9"},
+	},
+	"dark": {
+		Background: "#1a1d23",
+		Text:       "#e4e7eb",
+		Grid:       "#323842",
+		Axis:       "#616e7c",
+		Palette:    []string{"#5b9cf5", "#f47c7c", "#4cd18a", "#f5b06e", "#b983ea", "#7fd8f6", "#f5d77a", "#97dfb4", "#cf94e3"},
+	},
+}
+
+// Locale controls how numbers and months are written. Words holds the
+// translations of the titles and series names used by the chart endpoints.
+type Locale struct {
+	Decimal   string
+	Thousands string
+	Months    [12]string
+	NoData    string
+	Words     map[string]string
+}
+
+// Locales are the locales accepted by the chart endpoints.
+var Locales = map[string]Locale{
+	"en": {
+		Decimal:   ".",
+		Thousands: ",",
+		Months:    [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		NoData:    "No data",
+		Words: map[string]string{
+			"cashflow":   "Cash flow",
+			"income":     "Income",
+			"expenses":   "Expenses",
+			"categories": "Expenses by category",
+			"other":      "Other",
+			"net_worth":  "Net worth",
+			"value":      "Value",
+			"budget":     "Budget",
+			"budgets":    "Budgets",
+			"spent":      "Spent",
+		},
+	},
+	"pt-BR": {
+		Decimal:   ",",
+		Thousands: ".",
+		Months:    [12]string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+		NoData:    "Sem dados",
+		Words: map[string]string{
+			"cashflow":   "Fluxo de caixa",
+			"income":     "Receitas",
+			"expenses":   "Despesas",
+			"categories": "Despesas por categoria",
+			"other":      "Outros",
+			"net_worth":  "Patrimônio",
+			"value":      "Valor",
+			"budget":     "Orçamento",
+			"budgets":    "Orçamentos",
+			"spent":      "Gasto",
+		},
+	},
+}
+
+// FormatNumber writes a number with the locale's separators.
+func (l Locale) FormatNumber(value float64, decimals int) string {
+	formatted := strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(formatted, ".")
+
+	var out strings.Builder
+	if value < 0 && strings.Trim(formatted, "0.") != "" {
+		out.WriteByte('-')
+	}
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			out.WriteString(l.Thousands)
+		}
+		out.WriteRune(digit)
+	}
+	if fraction != "" {
+		out.WriteString(l.Decimal)
+		out.WriteString(fraction)
+	}
+	return out.String()
+}
+
+// MonthLabel writes a month as "Jul 2025".
+func (l Locale) MonthLabel(year int, month time.Month) string {
+	return fmt.Sprintf("%s %d", l.Months[month-1], year)
+}
+
+// Options are the size, theme and locale of a chart.
+type Options struct {
+	Width  int
+	Height int
+	Theme  Theme
+	Locale Locale
+}
+
+// DefaultOptions is a 640x360 light chart in English.
+func DefaultOptions() Options {
+	return Options{Width: 640, Height: 360, Theme: Themes["light"], Locale: Locales["en"]}
+}
+
+// Series is a named set of values, one per label of a bar chart.
+type Series struct {
+	Name   string
+	Values []float64
+}
+
+// Slice is a share of a donut chart.
+type Slice struct {
+	Label string
+	Value float64
+}
+
+const (
+	padding       = 16
+	titleSize     = 16
+	fontSize      = 12
+	charWidth     = 0.6 * fontSize // Rough width of a character, used to fit labels.
+	legendY       = 48
+	plotTop       = 68
+	axisLabelRoom = 28
+	tickCount     = 5
+)
+
+// Bars renders a grouped bar chart with one group per label and one bar per
+// series. Negative values are drawn below the zero line.
+func Bars(title string, labels []string, series []Series, opts Options) []byte {
+	doc := newDocument(title, opts)
+	doc.legend(seriesNames(series))
+
+	lo, hi := 0.0, 0.0
+	empty := true
+	for _, s := range series {
+		for _, v := range s.Values {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+			empty = empty && v == 0
+		}
+	}
+	if len(labels) == 0 || empty {
+		doc.noData()
+		return doc.bytes()
+	}
+
+	step := niceStep((hi - lo) / (tickCount - 1))
+	lo, hi = math.Floor(lo/step)*step, math.Ceil(hi/step)*step
+	decimals := 0
+	if step < 1 {
+		decimals = 2
+	}
+
+	ticks := make([]float64, int(math.Round((hi-lo)/step))+1)
+	tickWidth := 0
+	for i := range ticks {
+		ticks[i] = lo + float64(i)*step
+		tickWidth = max(tickWidth, len([]rune(opts.Locale.FormatNumber(ticks[i], decimals))))
+	}
+	left := float64(padding) + float64(tickWidth)*charWidth + 8
+	right := float64(opts.Width - padding)
+	bottom := float64(opts.Height - axisLabelRoom)
+	y := func(v float64) float64 { return plotTop + (hi-v)/(hi-lo)*(bottom-plotTop) }
+
+	for _, tick := range ticks {
+		doc.line(left, y(tick), right, y(tick), opts.Theme.Grid)
+		doc.text(left-6, y(tick)+4, "end", fontSize, false, opts.Locale.FormatNumber(tick, decimals))
+	}
+	doc.line(left, y(0), right, y(0), opts.Theme.Axis)
+
+	groupWidth := (right - left) / float64(len(labels))
+	barWidth := groupWidth * 0.7 / float64(max(len(series), 1))
+	maxChars := int(groupWidth / charWidth)
+	for i, label := range labels {
+		groupLeft := left + float64(i)*groupWidth + groupWidth*0.15
+		for j, s := range series {
+			if i >= len(s.Values) {
+				continue
+			}
+			v := s.Values[i]
+			doc.rect(groupLeft+float64(j)*barWidth, y(math.Max(v, 0)), barWidth, y(math.Min(v, 0))-y(math.Max(v, 0)),
+				color(opts.Theme, j), s.Name+": "+opts.Locale.FormatNumber(v, 2))
+		}
+		doc.text(left+float64(i)*groupWidth+groupWidth/2, bottom+18, "middle", fontSize, false, truncate(label, maxChars))
+	}
+	return doc.bytes()
+}
+
+// Donut renders the share of each slice around a ring, with the total in the
+// middle and the percentages in the legend. Slices that are not positive are
+// left out.
+func Donut(title string, slices []Slice, opts Options) []byte {
+	doc := newDocument(title, opts)
+
+	total := 0.0
+	var shown []Slice
+	for _, slice := range slices {
+		if slice.Value > 0 {
+			shown = append(shown, slice)
+			total += slice.Value
+		}
+	}
+	if len(shown) == 0 {
+		doc.noData()
+		return doc.bytes()
+	}
+
+	top := float64(legendY - 8)
+	radius := math.Min(float64(opts.Height)-top-padding, float64(opts.Width)/2-2*padding) / 2
+	inner := radius * 0.6
+	cx, cy := padding+radius, top+radius+(float64(opts.Height)-top-padding-2*radius)/2
+
+	angle := -math.Pi / 2
+	for i, slice := range shown {
+		sweep := slice.Value / total * 2 * math.Pi
+		label := slice.Label + ": " + opts.Locale.FormatNumber(slice.Value, 2)
+		if len(shown) == 1 {
+			// A single arc cannot close a full ring, so it is drawn as a thick circle.
+			fmt.Fprintf(&doc.body, `<circle cx="%s" cy="%s" r="%s" fill="none" stroke="%s" stroke-width="%s"><title>%s</title></circle>`+"\n",
+				num(cx), num(cy), num((radius+inner)/2), color(opts.Theme, i), num(radius-inner), escape(label))
+			break
+		}
+		end := angle + sweep
+		large := 0
+		if sweep > math.Pi {
+			large = 1
+		}
+		fmt.Fprintf(&doc.body, `<path d="M%s,%s A%s,%s 0 %d 1 %s,%s L%s,%s A%s,%s 0 %d 0 %s,%s Z" fill="%s"><title>%s</title></path>`+"\n",
+			num(cx+radius*math.Cos(angle)), num(cy+radius*math.Sin(angle)),
+			num(radius), num(radius), large, num(cx+radius*math.Cos(end)), num(cy+radius*math.Sin(end)),
+			num(cx+inner*math.Cos(end)), num(cy+inner*math.Sin(end)),
+			num(inner), num(inner), large, num(cx+inner*math.Cos(angle)), num(cy+inner*math.Sin(angle)),
+			color(opts.Theme, i), escape(label))
+		angle = end
+	}
+	doc.text(cx, cy+5, "middle", titleSize, true, opts.Locale.FormatNumber(total, 2))
+
+	legendX := cx + radius + 2*padding
+	maxChars := int((float64(opts.Width) - legendX - padding - 14) / charWidth)
+	for i, slice := range shown {
+		rowY := top + 12 + float64(i)*20
+		if rowY > float64(opts.Height-padding) {
+			break
+		}
+		percent := opts.Locale.FormatNumber(slice.Value/total*100, 1) + "%"
+		doc.rect(legendX, rowY-10, 10, 10, color(opts.Theme, i), "")
+		doc.text(legendX+14, rowY, "start", fontSize, false, truncate(slice.Label+" "+percent, maxChars))
+	}
+	return doc.bytes()
+}
+
+type document struct {
+	opts Options
+	body strings.Builder
+}
+
+func newDocument(title string, opts Options) *document {
+	doc := &document{opts: opts}
+	fmt.Fprintf(&doc.body, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif" role="img" aria-label="%s">`+"\n",
+		opts.Width, opts.Height, opts.Width, opts.Height, escape(title))
+	fmt.Fprintf(&doc.body, "<title>%s</title>\n", escape(title))
+	fmt.Fprintf(&doc.body, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", opts.Theme.Background)
+	doc.text(padding, padding+titleSize, "start", titleSize, true, title)
+	return doc
+}
+
+func (d *document) legend(names []string) {
+	x := float64(padding)
+	for i, name := range names {
+		d.rect(x, legendY-10, 10, 10, color(d.opts.Theme, i), "")
+		d.text(x+14, legendY, "start", fontSize, false, name)
+		x += 14 + float64(len([]rune(name)))*charWidth + padding
+	}
+}
+
+func (d *document) noData() {
+	d.text(float64(d.opts.Width)/2, float64(d.opts.Height)/2, "middle", fontSize, false, d.opts.Locale.NoData)
+}
+
+func (d *document) line(x1, y1, x2, y2 float64, stroke string) {
+	fmt.Fprintf(&d.body, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s"/>`+"\n", num(x1), num(y1), num(x2), num(y2), stroke)
+}
+
+func (d *document) rect(x, y, width, height float64, fill, title string) {
+	if title == "" {
+		fmt.Fprintf(&d.body, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n", num(x), num(y), num(width), num(height), fill)
+		return
+	}
+	fmt.Fprintf(&d.body, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"><title>%s</title></rect>`+"\n",
+		num(x), num(y), num(width), num(height), fill, escape(title))
+}
+
+func (d *document) text(x, y float64, anchor string, size int, bold bool, text string) {
+	weight := ""
+	if bold {
+		weight = ` font-weight="bold"`
+	}
+	fmt.Fprintf(&d.body, `<text x="%s" y="%s" text-anchor="%s" font-size="%d" fill="%s"%s>%s</text>`+"\n",
+		num(x), num(y), anchor, size, d.opts.Theme.Text, weight, escape(text))
+}
+
+func (d *document) bytes() []byte {
+	d.body.WriteString("</svg>\n")
+	return []byte(d.body.String())
+}
+
+// niceStep rounds a raw axis step up to 1, 2, 2.5 or 5 times a power of ten.
+func niceStep(raw float64) float64 {
+	if raw <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, factor := range []float64{1, 2, 2.5, 5, 10} {
+		if raw <= factor*magnitude {
+			return factor * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+func seriesNames(series []Series) []string {
+	names := make([]string, len(series))
+	for i, s := range series {
+		names[i] = s.Name
+	}
+	return names
+}
+
+func color(theme Theme, i int) string {
+	return theme.Palette[i%len(theme.Palette)]
+}
+
+// num writes a coordinate with one decimal place.
+func num(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 1, 64)
+	if s == "-0.0" {
+		return "0.0"
+	}
+	return s
+}
+
+func truncate(text string, maxChars int) string {
+	runes := []rune(text)
+	if maxChars < 2 || len(runes) <= maxChars {
+		return text
+	}
+	return string(runes[:maxChars-1]) + "…"
+}
+
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&#39;")
+
+func escape(text string) string {
+	return escaper.Replace(text)
+}
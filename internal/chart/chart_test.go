@@ -0,0 +1,77 @@
+package chart
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// assertGolden compares a chart with testdata/<name>.svg. Run the tests with
+// -update to accept a new output.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".svg")
+	if *update {
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestBars(t *testing.T) {
+	labels := []string{"Jan 2025", "Feb 2025", "Mar 2025"}
+	series := []Series{
+		{Name: "Income", Values: []float64{5000, 5200, 4800}},
+		{Name: "Expenses", Values: []float64{4100.5, 5600, 3900}},
+	}
+
+	t.Run("should render grouped bars", func(t *testing.T) {
+		assertGolden(t, "bars_light_en", Bars("Cash flow", labels, series, DefaultOptions()))
+	})
+
+	t.Run("should follow the theme, size and locale", func(t *testing.T) {
+		opts := Options{Width: 480, Height: 300, Theme: Themes["dark"], Locale: Locales["pt-BR"]}
+		assertGolden(t, "bars_dark_pt_br", Bars("Fluxo de caixa", labels, series, opts))
+	})
+
+	t.Run("should draw negative values below the zero line", func(t *testing.T) {
+		values := []Series{{Name: "Value", Values: []float64{12000, -3500.75, 800}}}
+		assertGolden(t, "bars_negative", Bars("Net worth", []string{"Checking", "Card", "Wallet"}, values, DefaultOptions()))
+	})
+
+	t.Run("should say when there is no data", func(t *testing.T) {
+		out := string(Bars("Cash flow", nil, nil, DefaultOptions()))
+		assert.Contains(t, out, ">No data</text>")
+	})
+
+	t.Run("should always produce the same output", func(t *testing.T) {
+		assert.Equal(t, Bars("Cash flow", labels, series, DefaultOptions()), Bars("Cash flow", labels, series, DefaultOptions()))
+	})
+}
+
+func TestDonut(t *testing.T) {
+	t.Run("should render one arc per positive slice", func(t *testing.T) {
+		slices := []Slice{{Label: "Rent", Value: 1500}, {Label: "Food & drinks", Value: 800}, {Label: "Transport", Value: 200}, {Label: "Refunds", Value: -50}}
+		assertGolden(t, "donut", Donut("Expenses by category", slices, DefaultOptions()))
+	})
+
+	t.Run("should draw a single slice as a full ring", func(t *testing.T) {
+		out := string(Donut("Expenses", []Slice{{Label: "Rent", Value: 1500}}, DefaultOptions()))
+		assert.Contains(t, out, "<circle")
+		assert.Contains(t, out, "Rent 100.0%")
+	})
+}
+
+func TestLocale(t *testing.T) {
+	assert.Equal(t, "1,234,567.89", Locales["en"].FormatNumber(1234567.891, 2))
+	assert.Equal(t, "-1.234,5", Locales["pt-BR"].FormatNumber(-1234.5, 1))
+	assert.Equal(t, "0", Locales["en"].FormatNumber(-0.1, 0))
+	assert.Equal(t, "jul 2025", Locales["pt-BR"].MonthLabel(2025, 7))
+}
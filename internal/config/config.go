@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/joeshaw/envdecode"
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog"
@@ -14,6 +16,51 @@ type Config struct {
 	ServerHostName string `env:"SERVER_HOSTNAME,default=localhost"`
 	DatabaseURL    string `env:"DATABASE_URL,required"`
 	JWTSecretKey   string `env:"JWT_SECRET_KEY,required"`
+	MigrationsPath string `env:"MIGRATIONS_PATH,default=db/migrations"`
+
+	// E-mails of the users promoted to instance admin at startup, separated by ';'.
+	AdminEmails []string `env:"ADMIN_EMAILS"`
+
+	// Outgoing e-mail. When SMTPHost is empty, messages are only logged.
+	SMTPHost     string `env:"SMTP_HOST"`
+	SMTPPort     string `env:"SMTP_PORT,default=587"`
+	SMTPUsername string `env:"SMTP_USERNAME"`
+	SMTPPassword string `env:"SMTP_PASSWORD"`
+	MailFrom     string `env:"MAIL_FROM,default=no-reply@gofinance.local"`
+
+	// Per-user quotas. Zero means unlimited.
+	QuotaMaxAccounts             int64 `env:"QUOTA_MAX_ACCOUNTS,default=0"`
+	QuotaMaxTransactionsPerMonth int64 `env:"QUOTA_MAX_TRANSACTIONS_PER_MONTH,default=0"`
+	QuotaMaxStorageBytes         int64 `env:"QUOTA_MAX_STORAGE_BYTES,default=0"`
+	QuotaMaxRequestsPerDay       int64 `env:"QUOTA_MAX_REQUESTS_PER_DAY,default=0"`
+
+	// Read-only share links.
+	ShareLinkBaseURL    string        `env:"SHARE_LINK_BASE_URL,default=http://localhost:8080/v1/shared"`
+	ShareLinkDefaultTTL time.Duration `env:"SHARE_LINK_DEFAULT_TTL,default=168h"`
+	ShareLinkMaxTTL     time.Duration `env:"SHARE_LINK_MAX_TTL,default=2160h"`
+
+	// Magic-link (passwordless) login.
+	MagicLinkEnabled     bool          `env:"MAGIC_LINK_ENABLED,default=false"`
+	MagicLinkBaseURL     string        `env:"MAGIC_LINK_BASE_URL,default=http://localhost:8080/v1/auth/magic-link/verify"`
+	MagicLinkTTL         time.Duration `env:"MAGIC_LINK_TTL,default=15m"`
+	MagicLinkMaxRequests int           `env:"MAGIC_LINK_MAX_REQUESTS,default=3"`
+	MagicLinkRateWindow  time.Duration `env:"MAGIC_LINK_RATE_WINDOW,default=15m"`
+
+	// Read budgets and monthly reports from the monthly_category_totals
+	// aggregates instead of adding up every transaction. Run the
+	// rebuild-aggregates command before turning it on for an existing database.
+	ReportAggregatesEnabled bool `env:"REPORT_AGGREGATES_ENABLED,default=false"`
+
+	// Percentage of IOF charged on credit card purchases abroad.
+	IOFForeignPurchaseRate float64 `env:"IOF_FOREIGN_PURCHASE_RATE,default=3.5"`
+
+	// Telegram bot. It is disabled when TelegramBotToken is empty. Without a
+	// webhook secret, updates are fetched through long polling.
+	TelegramBotToken      string        `env:"TELEGRAM_BOT_TOKEN"`
+	TelegramBotUsername   string        `env:"TELEGRAM_BOT_USERNAME"`
+	TelegramAPIURL        string        `env:"TELEGRAM_API_URL,default=https://api.telegram.org"`
+	TelegramWebhookSecret string        `env:"TELEGRAM_WEBHOOK_SECRET"`
+	TelegramLinkCodeTTL   time.Duration `env:"TELEGRAM_LINK_CODE_TTL,default=15m"`
 }
 
 // LoadConfig carrega as configurações das variáveis de ambiente para a struct Config.
@@ -70,3 +70,24 @@ func GetMigrationsPath() (string, error) {
 
 	return "", fmt.Errorf("could not find project root (go.mod file)")
 }
+
+// LatestMigrationVersion returns the highest version available in the migrations
+// directory, so it can be compared with the version applied to the database.
+func LatestMigrationVersion(migrationsPath string) (uint, error) {
+	files, err := filepath.Glob(filepath.Join(migrationsPath, "*.up.sql"))
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint
+	for _, file := range files {
+		var version uint
+		if _, err := fmt.Sscanf(filepath.Base(file), "%d_", &version); err != nil {
+			continue
+		}
+		if version > latest {
+			latest = version
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// AssetDepreciator generates the depreciation adjustments that are due. It is
+// implemented by the asset service.
+type AssetDepreciator interface {
+	DepreciateAll(ctx context.Context) (int, error)
+}
+
+// AssetDepreciationJob writes the monthly depreciation adjustments of assets.
+type AssetDepreciationJob struct {
+	depreciator AssetDepreciator
+}
+
+// NewAssetDepreciationJob creates a new AssetDepreciationJob.
+func NewAssetDepreciationJob(depreciator AssetDepreciator) *AssetDepreciationJob {
+	return &AssetDepreciationJob{depreciator: depreciator}
+}
+
+func (j *AssetDepreciationJob) Name() string { return "asset_depreciation" }
+
+func (j *AssetDepreciationJob) Run(ctx context.Context) error {
+	created, err := j.depreciator.DepreciateAll(ctx)
+	if err != nil {
+		return err
+	}
+	zerolog.Ctx(ctx).Info().Int("adjustments", created).Msg("generated asset depreciation adjustments")
+	return nil
+}
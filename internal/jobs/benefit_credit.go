@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// BenefitCreditor credits the meal and food voucher accounts whose monthly
+// credit is due. It is implemented by the benefit service.
+type BenefitCreditor interface {
+	CreditDueAccounts(ctx context.Context) (int, error)
+}
+
+// BenefitCreditJob schedules the monthly credits of benefit accounts.
+type BenefitCreditJob struct {
+	creditor BenefitCreditor
+}
+
+// NewBenefitCreditJob creates a new BenefitCreditJob.
+func NewBenefitCreditJob(creditor BenefitCreditor) *BenefitCreditJob {
+	return &BenefitCreditJob{creditor: creditor}
+}
+
+func (j *BenefitCreditJob) Name() string { return "benefit_credit" }
+
+func (j *BenefitCreditJob) Run(ctx context.Context) error {
+	credited, err := j.creditor.CreditDueAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	zerolog.Ctx(ctx).Info().Int("accounts", credited).Msg("credited benefit accounts")
+	return nil
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// BudgetAlertSender warns linked chats about budgets that reached an alert
+// threshold. It is implemented by the bot service.
+type BudgetAlertSender interface {
+	SendBudgetAlerts(ctx context.Context) (int, error)
+}
+
+// BudgetAlertJob schedules the budget alerts of the chat bot.
+type BudgetAlertJob struct {
+	sender BudgetAlertSender
+}
+
+// NewBudgetAlertJob creates a new BudgetAlertJob.
+func NewBudgetAlertJob(sender BudgetAlertSender) *BudgetAlertJob {
+	return &BudgetAlertJob{sender: sender}
+}
+
+func (j *BudgetAlertJob) Name() string { return "budget_alerts" }
+
+func (j *BudgetAlertJob) Run(ctx context.Context) error {
+	sent, err := j.sender.SendBudgetAlerts(ctx)
+	if err != nil {
+		return err
+	}
+	zerolog.Ctx(ctx).Info().Int("alerts", sent).Msg("sent budget alerts")
+	return nil
+}
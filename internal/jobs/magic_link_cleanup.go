@@ -0,0 +1,29 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/rs/zerolog"
+)
+
+// MagicLinkCleanupJob deletes login tokens that are expired or already used.
+type MagicLinkCleanupJob struct {
+	repo repository.MagicLinkRepository
+}
+
+// NewMagicLinkCleanupJob creates a new MagicLinkCleanupJob.
+func NewMagicLinkCleanupJob(repo repository.MagicLinkRepository) *MagicLinkCleanupJob {
+	return &MagicLinkCleanupJob{repo: repo}
+}
+
+func (j *MagicLinkCleanupJob) Name() string { return "magic_link_cleanup" }
+
+func (j *MagicLinkCleanupJob) Run(ctx context.Context) error {
+	deleted, err := j.repo.DeleteExpired(ctx)
+	if err != nil {
+		return err
+	}
+	zerolog.Ctx(ctx).Info().Int64("deleted", deleted).Msg("removed stale magic link tokens")
+	return nil
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// OverdraftInterestCharger charges the overdraft interest of the month that
+// ended. It is implemented by the overdraft service.
+type OverdraftInterestCharger interface {
+	ChargeLastMonth(ctx context.Context) (int, error)
+}
+
+// OverdraftInterestJob posts the month-end overdraft interest of checking accounts.
+type OverdraftInterestJob struct {
+	charger OverdraftInterestCharger
+}
+
+// NewOverdraftInterestJob creates a new OverdraftInterestJob.
+func NewOverdraftInterestJob(charger OverdraftInterestCharger) *OverdraftInterestJob {
+	return &OverdraftInterestJob{charger: charger}
+}
+
+func (j *OverdraftInterestJob) Name() string { return "overdraft_interest" }
+
+func (j *OverdraftInterestJob) Run(ctx context.Context) error {
+	charged, err := j.charger.ChargeLastMonth(ctx)
+	if err != nil {
+		return err
+	}
+	zerolog.Ctx(ctx).Info().Int("accounts", charged).Msg("charged overdraft interest")
+	return nil
+}
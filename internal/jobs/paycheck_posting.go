@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// PaycheckPoster posts the regular paychecks whose pay day has arrived. It is
+// implemented by the paycheck service.
+type PaycheckPoster interface {
+	PostDueTemplates(ctx context.Context) (int, error)
+}
+
+// PaycheckPostingJob schedules the monthly paychecks of the templates with a pay day.
+type PaycheckPostingJob struct {
+	poster PaycheckPoster
+}
+
+// NewPaycheckPostingJob creates a new PaycheckPostingJob.
+func NewPaycheckPostingJob(poster PaycheckPoster) *PaycheckPostingJob {
+	return &PaycheckPostingJob{poster: poster}
+}
+
+func (j *PaycheckPostingJob) Name() string { return "paycheck_posting" }
+
+func (j *PaycheckPostingJob) Run(ctx context.Context) error {
+	posted, err := j.poster.PostDueTemplates(ctx)
+	if err != nil {
+		return err
+	}
+	zerolog.Ctx(ctx).Info().Int("paychecks", posted).Msg("posted scheduled paychecks")
+	return nil
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// StatementPointsAccruer credits the points earned in closed card statements.
+// It is implemented by the points service.
+type StatementPointsAccruer interface {
+	AccrueClosedStatements(ctx context.Context) (int, error)
+}
+
+// PointsAccrualJob credits loyalty points for every card statement that closed.
+type PointsAccrualJob struct {
+	accruer StatementPointsAccruer
+}
+
+// NewPointsAccrualJob creates a new PointsAccrualJob.
+func NewPointsAccrualJob(accruer StatementPointsAccruer) *PointsAccrualJob {
+	return &PointsAccrualJob{accruer: accruer}
+}
+
+func (j *PointsAccrualJob) Name() string { return "points_accrual" }
+
+func (j *PointsAccrualJob) Run(ctx context.Context) error {
+	accrued, err := j.accruer.AccrueClosedStatements(ctx)
+	if err != nil {
+		return err
+	}
+	zerolog.Ctx(ctx).Info().Int("rules", accrued).Msg("accrued points for closed statements")
+	return nil
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// RoundUpSettler moves pending round-ups into savings. It is implemented by the
+// savings rule service.
+type RoundUpSettler interface {
+	SettleRoundUps(ctx context.Context) (int, error)
+}
+
+// SavingsRoundUpsJob settles the round-ups of the days that ended.
+type SavingsRoundUpsJob struct {
+	settler RoundUpSettler
+}
+
+// NewSavingsRoundUpsJob creates a new SavingsRoundUpsJob.
+func NewSavingsRoundUpsJob(settler RoundUpSettler) *SavingsRoundUpsJob {
+	return &SavingsRoundUpsJob{settler: settler}
+}
+
+func (j *SavingsRoundUpsJob) Name() string { return "savings_round_ups" }
+
+func (j *SavingsRoundUpsJob) Run(ctx context.Context) error {
+	settled, err := j.settler.SettleRoundUps(ctx)
+	if err != nil {
+		return err
+	}
+	zerolog.Ctx(ctx).Info().Int("transfers", settled).Msg("settled savings round-ups")
+	return nil
+}
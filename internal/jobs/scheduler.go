@@ -0,0 +1,151 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+var ErrJobNotFound = errors.New("job not found")
+
+// Job is a unit of background work executed periodically by the Scheduler.
+type Job interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+// Status is a snapshot of a registered job, exposed for monitoring.
+type Status struct {
+	Name         string        `json:"name"`
+	Interval     time.Duration `json:"interval"`
+	Running      bool          `json:"running"`
+	RunCount     int           `json:"run_count"`
+	FailureCount int           `json:"failure_count"`
+	LastRunAt    *time.Time    `json:"last_run_at,omitempty"`
+	LastDuration time.Duration `json:"last_duration"`
+	LastError    string        `json:"last_error,omitempty"`
+	NextRunAt    *time.Time    `json:"next_run_at,omitempty"`
+}
+
+type entry struct {
+	job      Job
+	interval time.Duration
+	status   Status
+	runMu    sync.Mutex // Prevents overlapping runs of the same job
+}
+
+// Scheduler runs registered jobs on fixed intervals, each in its own goroutine,
+// and keeps track of their execution status in memory.
+type Scheduler struct {
+	mu      sync.RWMutex
+	entries map[string]*entry
+	logger  zerolog.Logger
+}
+
+// NewScheduler creates a new, empty Scheduler.
+func NewScheduler(logger zerolog.Logger) *Scheduler {
+	return &Scheduler{
+		entries: make(map[string]*entry),
+		logger:  logger.With().Str("component", "Scheduler").Logger(),
+	}
+}
+
+// Register adds a job to be run every interval once the scheduler is started.
+// Registering a job with a name that is already taken replaces it.
+func (s *Scheduler) Register(job Job, interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[job.Name()] = &entry{
+		job:      job,
+		interval: interval,
+		status:   Status{Name: job.Name(), Interval: interval},
+	}
+}
+
+// Start launches every registered job. Jobs stop when ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, e := range s.entries {
+		go s.loop(ctx, e)
+	}
+}
+
+// RunNow executes a job immediately, outside its regular schedule.
+func (s *Scheduler) RunNow(ctx context.Context, name string) error {
+	s.mu.RLock()
+	e, ok := s.entries[name]
+	s.mu.RUnlock()
+	if !ok {
+		return ErrJobNotFound
+	}
+	return s.run(ctx, e)
+}
+
+// Statuses returns a snapshot of all registered jobs, ordered by name.
+func (s *Scheduler) Statuses() []Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make([]Status, 0, len(s.entries))
+	for _, e := range s.entries {
+		statuses = append(statuses, e.status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	s.setNextRun(e, time.Now().Add(e.interval))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = s.run(ctx, e)
+			s.setNextRun(e, time.Now().Add(e.interval))
+		}
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, e *entry) error {
+	e.runMu.Lock()
+	defer e.runMu.Unlock()
+
+	start := time.Now()
+	s.mu.Lock()
+	e.status.Running = true
+	s.mu.Unlock()
+
+	logger := s.logger.With().Str("job", e.job.Name()).Logger()
+	err := e.job.Run(logger.WithContext(ctx))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.status.Running = false
+	e.status.RunCount++
+	e.status.LastRunAt = &start
+	e.status.LastDuration = time.Since(start)
+	e.status.LastError = ""
+	if err != nil {
+		e.status.FailureCount++
+		e.status.LastError = err.Error()
+		logger.Error().Err(err).Msg("job failed")
+	} else {
+		logger.Debug().Dur("duration", e.status.LastDuration).Msg("job finished")
+	}
+	return err
+}
+
+func (s *Scheduler) setNextRun(e *entry, next time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.status.NextRunAt = &next
+}
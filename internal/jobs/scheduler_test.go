@@ -0,0 +1,70 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type funcJob struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func (j funcJob) Name() string                  { return j.name }
+func (j funcJob) Run(ctx context.Context) error { return j.fn(ctx) }
+
+func TestScheduler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("RunNow should record successes and failures", func(t *testing.T) {
+		scheduler := NewScheduler(zerolog.Nop())
+		fail := false
+		scheduler.Register(funcJob{name: "flaky", fn: func(ctx context.Context) error {
+			if fail {
+				return errors.New("boom")
+			}
+			return nil
+		}}, time.Hour)
+
+		require.NoError(t, scheduler.RunNow(ctx, "flaky"))
+		fail = true
+		require.Error(t, scheduler.RunNow(ctx, "flaky"))
+
+		statuses := scheduler.Statuses()
+		require.Len(t, statuses, 1)
+		assert.Equal(t, 2, statuses[0].RunCount)
+		assert.Equal(t, 1, statuses[0].FailureCount)
+		assert.Equal(t, "boom", statuses[0].LastError)
+		assert.NotNil(t, statuses[0].LastRunAt)
+	})
+
+	t.Run("RunNow should fail for unknown jobs", func(t *testing.T) {
+		scheduler := NewScheduler(zerolog.Nop())
+		assert.ErrorIs(t, scheduler.RunNow(ctx, "missing"), ErrJobNotFound)
+	})
+
+	t.Run("Start should run jobs on their interval until cancelled", func(t *testing.T) {
+		scheduler := NewScheduler(zerolog.Nop())
+		runs := make(chan struct{}, 10)
+		scheduler.Register(funcJob{name: "tick", fn: func(ctx context.Context) error {
+			runs <- struct{}{}
+			return nil
+		}}, 10*time.Millisecond)
+
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		scheduler.Start(runCtx)
+
+		select {
+		case <-runs:
+		case <-time.After(time.Second):
+			t.Fatal("job did not run")
+		}
+	})
+}
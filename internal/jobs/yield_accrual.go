@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// YieldAccruer posts the yield of savings accounts. It is implemented by the
+// yield service.
+type YieldAccruer interface {
+	AccrueDueAccounts(ctx context.Context) (int, error)
+}
+
+// YieldAccrualJob posts the yield of savings accounts for the periods that ended.
+type YieldAccrualJob struct {
+	accruer YieldAccruer
+}
+
+// NewYieldAccrualJob creates a new YieldAccrualJob.
+func NewYieldAccrualJob(accruer YieldAccruer) *YieldAccrualJob {
+	return &YieldAccrualJob{accruer: accruer}
+}
+
+func (j *YieldAccrualJob) Name() string { return "yield_accrual" }
+
+func (j *YieldAccrualJob) Run(ctx context.Context) error {
+	posted, err := j.accruer.AccrueDueAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	zerolog.Ctx(ctx).Info().Int("periods", posted).Msg("posted savings yield")
+	return nil
+}
@@ -0,0 +1,112 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// Message is a plain-text e-mail.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer abstracts how e-mails leave the application, so services never
+// depend on a concrete transport.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTPMailer delivers messages through an SMTP server using PLAIN auth.
+type SMTPMailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+// NewSMTPMailer creates a new SMTPMailer.
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	return &SMTPMailer{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+// Send delivers the message. The context is only used for logging, as net/smtp
+// does not support cancellation.
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", m.from)
+	fmt.Fprintf(&body, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&body, "Subject: %s\r\n", msg.Subject)
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n\r\n")
+	body.WriteString(msg.Body)
+
+	addr := net.JoinHostPort(m.host, m.port)
+	if err := smtp.SendMail(addr, auth, m.from, []string{msg.To}, []byte(body.String())); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("to", msg.To).Msg("failed to send e-mail")
+		return fmt.Errorf("failed to send e-mail: %w", err)
+	}
+	return nil
+}
+
+// LogMailer only logs the recipient and subject. It is used when no SMTP
+// server is configured, e.g. in local development.
+type LogMailer struct {
+	logger zerolog.Logger
+}
+
+// NewLogMailer creates a new LogMailer.
+func NewLogMailer(logger zerolog.Logger) *LogMailer {
+	return &LogMailer{logger: logger.With().Str("component", "LogMailer").Logger()}
+}
+
+// Send logs the message metadata. The body is never logged, as it may carry secrets.
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	m.logger.Warn().Str("to", msg.To).Str("subject", msg.Subject).Msg("SMTP is not configured, e-mail was not delivered")
+	return nil
+}
+
+// InMemoryMailer keeps every sent message in memory. It is meant for tests.
+type InMemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewInMemoryMailer creates a new, empty InMemoryMailer.
+func NewInMemoryMailer() *InMemoryMailer {
+	return &InMemoryMailer{}
+}
+
+// Send stores the message.
+func (m *InMemoryMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages returns a copy of all messages sent so far.
+func (m *InMemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.messages...)
+}
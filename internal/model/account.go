@@ -1,8 +1,10 @@
 package model
 
 import (
+	"slices"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/shopspring/decimal"
 )
 
@@ -13,6 +15,12 @@ const (
 	Savings    AccountType = "savings"
 	CreditCard AccountType = "credit_card"
 	Other      AccountType = "other"
+	// Points accounts hold loyalty points or airline miles instead of money.
+	Points AccountType = "points"
+	// Benefit accounts hold meal or food vouchers (VR/VA) that only pay for some categories.
+	Benefit AccountType = "benefit"
+	// Asset accounts hold physical assets, such as cars and property, valued over time.
+	Asset AccountType = "asset"
 )
 
 type Account struct {
@@ -27,4 +35,41 @@ type Account struct {
 	CreditLimit         *decimal.Decimal `json:"credit_limit,omitempty" db:"credit_limit"`
 	StatementClosingDay *int             `json:"statement_closing_day,omitempty" db:"statement_closing_day"`
 	PaymentDueDay       *int             `json:"payment_due_day,omitempty" db:"payment_due_day"`
+	// PointsValuePerThousand is the estimated worth of 1,000 points, only used by points accounts.
+	PointsValuePerThousand *decimal.Decimal `json:"points_value_per_thousand,omitempty" db:"points_value_per_thousand"`
+	// Benefit fields are only used by benefit accounts. An empty category list
+	// allows any expense; the monthly credit is skipped when it is not set.
+	BenefitAllowedCategoryIds pq.Int64Array    `json:"benefit_allowed_category_ids,omitempty" db:"benefit_allowed_category_ids"`
+	BenefitMonthlyCredit      *decimal.Decimal `json:"benefit_monthly_credit,omitempty" db:"benefit_monthly_credit"`
+	BenefitCreditDay          *int             `json:"benefit_credit_day,omitempty" db:"benefit_credit_day"`
+	BenefitExpiresUnused      bool             `json:"benefit_expires_unused" db:"benefit_expires_unused"`
+	// Asset fields are only used by asset accounts. The useful life is needed by
+	// straight-line depreciation and the annual rate by declining-balance.
+	AssetPurchaseValue          *decimal.Decimal      `json:"asset_purchase_value,omitempty" db:"asset_purchase_value"`
+	AssetPurchaseDate           *time.Time            `json:"asset_purchase_date,omitempty" db:"asset_purchase_date"`
+	AssetValuationMethod        *AssetValuationMethod `json:"asset_valuation_method,omitempty" db:"asset_valuation_method"`
+	AssetUsefulLifeMonths       *int                  `json:"asset_useful_life_months,omitempty" db:"asset_useful_life_months"`
+	AssetAnnualDepreciationRate *decimal.Decimal      `json:"asset_annual_depreciation_rate,omitempty" db:"asset_annual_depreciation_rate"`
+	AssetSalvageValue           *decimal.Decimal      `json:"asset_salvage_value,omitempty" db:"asset_salvage_value"`
+	// Overdraft fields are only used by checking accounts. The balance may go
+	// down to -OverdraftLimit; OverdraftMonthlyRate is the interest percentage.
+	OverdraftLimit       *decimal.Decimal `json:"overdraft_limit,omitempty" db:"overdraft_limit"`
+	OverdraftMonthlyRate *decimal.Decimal `json:"overdraft_monthly_rate,omitempty" db:"overdraft_monthly_rate"`
+	// Yield fields are only used by savings accounts. YieldRate is a yearly
+	// percentage for fixed yields and a percentage of the CDI for cdi ones; the
+	// yield starts on YieldStartDate, or when the account was created.
+	YieldType        *YieldType       `json:"yield_type,omitempty" db:"yield_type"`
+	YieldRate        *decimal.Decimal `json:"yield_rate,omitempty" db:"yield_rate"`
+	YieldFrequency   *YieldFrequency  `json:"yield_frequency,omitempty" db:"yield_frequency"`
+	YieldStartDate   *time.Time       `json:"yield_start_date,omitempty" db:"yield_start_date"`
+	YieldWithholdsIR bool             `json:"yield_withholds_ir" db:"yield_withholds_ir"`
+}
+
+// AllowsBenefitCategory reports whether a benefit account may pay for an
+// expense in the given category.
+func (a Account) AllowsBenefitCategory(categoryId *int64) bool {
+	if len(a.BenefitAllowedCategoryIds) == 0 {
+		return true
+	}
+	return categoryId != nil && slices.Contains(a.BenefitAllowedCategoryIds, *categoryId)
 }
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditLogEntry records a single action performed through the admin API.
+type AuditLogEntry struct {
+	Id           int64           `json:"id" db:"id"`
+	AdminId      *int64          `json:"admin_id,omitempty" db:"admin_id"`
+	Action       string          `json:"action" db:"action"`
+	TargetUserId *int64          `json:"target_user_id,omitempty" db:"target_user_id"`
+	Details      json.RawMessage `json:"details" db:"details"`
+	CreatedAt    time.Time       `json:"created_at" db:"created_at"`
+}
+
+// UserUsage summarizes how much data a user keeps in the instance.
+type UserUsage struct {
+	UserId       int64 `json:"user_id" db:"user_id"`
+	Accounts     int64 `json:"accounts" db:"accounts"`
+	Transactions int64 `json:"transactions" db:"transactions"`
+	Categories   int64 `json:"categories" db:"categories"`
+	Budgets      int64 `json:"budgets" db:"budgets"`
+	// StorageBytes is an estimate of the space used by the user's rows.
+	StorageBytes int64 `json:"storage_bytes" db:"storage_bytes"`
+}
+
+// MigrationStatus describes the schema version applied to the database.
+type MigrationStatus struct {
+	Version       uint `json:"version" db:"version"`
+	Dirty         bool `json:"dirty" db:"dirty"`
+	LatestVersion uint `json:"latest_version"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AssetValuationMethod tells how the value of an asset changes over time.
+type AssetValuationMethod string
+
+const (
+	// AssetManual assets only change value through manual revaluations.
+	AssetManual AssetValuationMethod = "manual"
+	// AssetStraightLine assets lose the same amount every month over their useful life.
+	AssetStraightLine AssetValuationMethod = "straight_line"
+	// AssetDecliningBalance assets lose a fixed percentage of their value every year.
+	AssetDecliningBalance AssetValuationMethod = "declining_balance"
+)
+
+// AssetValuationSource tells whether a valuation was entered or generated.
+type AssetValuationSource string
+
+const (
+	AssetValuationManual       AssetValuationSource = "manual"
+	AssetValuationDepreciation AssetValuationSource = "depreciation"
+)
+
+// AssetValuation is the value of an asset on a given date. The latest one on
+// or before a date is the asset's value then.
+type AssetValuation struct {
+	Id        int64                `json:"id" db:"id"`
+	UserId    int64                `json:"-" db:"user_id"`
+	AccountId int64                `json:"account_id" db:"account_id"`
+	Source    AssetValuationSource `json:"source" db:"source"`
+	Value     decimal.Decimal      `json:"value" db:"value"`
+	ValuedOn  time.Time            `json:"valued_on" db:"valued_on"`
+	Note      string               `json:"note,omitempty" db:"note"`
+	CreatedAt time.Time            `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BenefitCredit records the monthly credit of a benefit account. ExpiredAmount
+// is the unused balance written off before the credit, if the account expires it.
+type BenefitCredit struct {
+	Id            int64           `json:"id" db:"id"`
+	UserId        int64           `json:"-" db:"user_id"`
+	AccountId     int64           `json:"account_id" db:"account_id"`
+	Year          int             `json:"year" db:"year"`
+	Month         int             `json:"month" db:"month"`
+	Amount        decimal.Decimal `json:"amount" db:"amount"`
+	ExpiredAmount decimal.Decimal `json:"expired_amount" db:"expired_amount"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// BillingCycle is a version of a credit card's closing and due days, in force
+// for the statements closing on or after EffectiveFrom until the next version.
+type BillingCycle struct {
+	Id                  int64     `json:"id" db:"id"`
+	AccountId           int64     `json:"account_id" db:"account_id"`
+	StatementClosingDay int       `json:"statement_closing_day" db:"statement_closing_day"`
+	PaymentDueDay       int       `json:"payment_due_day" db:"payment_due_day"`
+	EffectiveFrom       time.Time `json:"effective_from" db:"effective_from"`
+	CreatedAt           time.Time `json:"created_at" db:"created_at"`
+}
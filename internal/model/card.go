@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Card is an additional or virtual card of a credit card account. It shares
+// the account's limit and statement; SpendLimit optionally caps what the card
+// may spend in each statement.
+type Card struct {
+	Id         int64            `json:"id" db:"id"`
+	UserId     int64            `json:"-" db:"user_id"`
+	AccountId  int64            `json:"account_id" db:"account_id"`
+	HolderName string           `json:"holder_name" db:"holder_name"`
+	LastFour   string           `json:"last_four" db:"last_four"`
+	Virtual    bool             `json:"virtual" db:"virtual"`
+	SpendLimit *decimal.Decimal `json:"spend_limit,omitempty" db:"spend_limit"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at" db:"updated_at"`
+}
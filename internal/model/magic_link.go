@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// MagicLinkToken is a single-use login token sent by e-mail.
+// Only the hash of the token is persisted.
+type MagicLinkToken struct {
+	Id        int64      `json:"id" db:"id"`
+	UserId    int64      `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import "github.com/shopspring/decimal"
+
+// MonthlyCategoryTotal adds up a user's transactions of one category, account
+// and type in a calendar month. CategoryName and AccountType are loaded for
+// reports.
+type MonthlyCategoryTotal struct {
+	UserId       int64           `json:"user_id" db:"user_id"`
+	CategoryId   *int64          `json:"category_id,omitempty" db:"category_id"`
+	CategoryName *string         `json:"category_name,omitempty" db:"category_name"`
+	AccountId    int64           `json:"account_id" db:"account_id"`
+	AccountType  AccountType     `json:"account_type" db:"account_type"`
+	Type         TransactionType `json:"type" db:"type"`
+	Year         int             `json:"year" db:"year"`
+	Month        int             `json:"month" db:"month"`
+	Total        decimal.Decimal `json:"total" db:"total"`
+	Count        int             `json:"count" db:"count"`
+}
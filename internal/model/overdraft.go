@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// OverdraftInterestCharge records the overdraft interest charged to a checking
+// account for a month. Months without interest are recorded with a zero amount.
+type OverdraftInterestCharge struct {
+	Id        int64           `json:"id" db:"id"`
+	UserId    int64           `json:"-" db:"user_id"`
+	AccountId int64           `json:"account_id" db:"account_id"`
+	Year      int             `json:"year" db:"year"`
+	Month     int             `json:"month" db:"month"`
+	Amount    decimal.Decimal `json:"amount" db:"amount"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PaycheckLineKind tells whether a paycheck line adds to or takes from the gross.
+type PaycheckLineKind string
+
+const (
+	PaycheckEarning   PaycheckLineKind = "earning"
+	PaycheckDeduction PaycheckLineKind = "deduction"
+)
+
+// PaycheckKind separates the regular monthly salary from the extra payments of
+// variable months.
+type PaycheckKind string
+
+const (
+	PaycheckRegular    PaycheckKind = "regular"
+	PaycheckThirteenth PaycheckKind = "thirteenth"
+	PaycheckVacation   PaycheckKind = "vacation"
+	PaycheckBonus      PaycheckKind = "bonus"
+)
+
+// PaycheckLine is one earning or deduction of a paycheck, such as the gross
+// salary, INSS, IRRF or the health plan.
+type PaycheckLine struct {
+	Id          int64            `json:"id" db:"id"`
+	Kind        PaycheckLineKind `json:"kind" db:"kind"`
+	Description string           `json:"description" db:"description"`
+	CategoryId  *int64           `json:"category_id,omitempty" db:"category_id"`
+	Amount      decimal.Decimal  `json:"amount" db:"amount"`
+}
+
+// PaycheckTemplate describes a salary. PayDay is the day of the month it is
+// posted automatically; without it the template is only posted on demand.
+type PaycheckTemplate struct {
+	Id               int64          `json:"id" db:"id"`
+	UserId           int64          `json:"-" db:"user_id"`
+	Name             string         `json:"name" db:"name"`
+	AccountId        int64          `json:"account_id" db:"account_id"`
+	IncomeCategoryId *int64         `json:"income_category_id,omitempty" db:"income_category_id"`
+	PayDay           *int           `json:"pay_day,omitempty" db:"pay_day"`
+	Active           bool           `json:"active" db:"active"`
+	CreatedAt        time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at" db:"updated_at"`
+	Lines            []PaycheckLine `json:"lines" db:"-"`
+}
+
+// Paycheck is a posted salary. Net went into the account as an income
+// transaction; the lines keep the gross and the deductions.
+type Paycheck struct {
+	Id            int64           `json:"id" db:"id"`
+	UserId        int64           `json:"-" db:"user_id"`
+	TemplateId    *int64          `json:"template_id,omitempty" db:"template_id"`
+	AccountId     int64           `json:"account_id" db:"account_id"`
+	Kind          PaycheckKind    `json:"kind" db:"kind"`
+	Year          int             `json:"year" db:"year"`
+	Month         int             `json:"month" db:"month"`
+	PayDate       time.Time       `json:"pay_date" db:"pay_date"`
+	Gross         decimal.Decimal `json:"gross" db:"gross"`
+	Deductions    decimal.Decimal `json:"deductions" db:"deductions"`
+	Net           decimal.Decimal `json:"net" db:"net"`
+	TransactionId *int64          `json:"transaction_id,omitempty" db:"transaction_id"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	Lines         []PaycheckLine  `json:"lines" db:"-"`
+}
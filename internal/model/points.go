@@ -0,0 +1,65 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PointsLotSource tells where the points of a lot came from.
+type PointsLotSource string
+
+const (
+	PointsSourceManual    PointsLotSource = "manual"
+	PointsSourceStatement PointsLotSource = "statement"
+	PointsSourceTransfer  PointsLotSource = "transfer"
+)
+
+// PointsEarnRule says how many points a credit card earns in a points account
+// per currency unit of expenses in each statement.
+type PointsEarnRule struct {
+	Id               int64           `json:"id" db:"id"`
+	UserId           int64           `json:"-" db:"user_id"`
+	CardAccountId    int64           `json:"card_account_id" db:"card_account_id"`
+	PointsAccountId  int64           `json:"points_account_id" db:"points_account_id"`
+	PointsPerUnit    decimal.Decimal `json:"points_per_unit" db:"points_per_unit"`
+	ExpirationMonths *int            `json:"expiration_months,omitempty" db:"expiration_months"`
+	CreatedAt        time.Time       `json:"created_at" db:"created_at"`
+}
+
+// PointsLot is a batch of points credited at once. Remaining is what is left
+// after transfers; the lot is worthless after ExpiresAt.
+type PointsLot struct {
+	Id             int64           `json:"id" db:"id"`
+	UserId         int64           `json:"-" db:"user_id"`
+	AccountId      int64           `json:"account_id" db:"account_id"`
+	Source         PointsLotSource `json:"source" db:"source"`
+	Points         int64           `json:"points" db:"points"`
+	Remaining      int64           `json:"remaining" db:"remaining"`
+	EarnedAt       time.Time       `json:"earned_at" db:"earned_at"`
+	ExpiresAt      *time.Time      `json:"expires_at,omitempty" db:"expires_at"`
+	RuleId         *int64          `json:"rule_id,omitempty" db:"rule_id"`
+	StatementYear  *int            `json:"statement_year,omitempty" db:"statement_year"`
+	StatementMonth *int            `json:"statement_month,omitempty" db:"statement_month"`
+	Description    string          `json:"description" db:"description"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+}
+
+// Expired reports whether the lot can no longer be used at the given instant.
+// Points remain valid until the end of their expiration day.
+func (l PointsLot) Expired(asOf time.Time) bool {
+	return l.ExpiresAt != nil && !asOf.Before(l.ExpiresAt.AddDate(0, 0, 1))
+}
+
+// PointsTransfer moves points between programs. CreditedPoints includes the
+// bonus offered by the destination program.
+type PointsTransfer struct {
+	Id             int64           `json:"id" db:"id"`
+	UserId         int64           `json:"-" db:"user_id"`
+	FromAccountId  int64           `json:"from_account_id" db:"from_account_id"`
+	ToAccountId    int64           `json:"to_account_id" db:"to_account_id"`
+	Points         int64           `json:"points" db:"points"`
+	BonusPercent   decimal.Decimal `json:"bonus_percent" db:"bonus_percent"`
+	CreditedPoints int64           `json:"credited_points" db:"credited_points"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+}
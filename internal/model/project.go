@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Project is a budget for an event that spans categories, accounts and months,
+// such as a wedding, a renovation or a trip.
+type Project struct {
+	Id              int64                   `json:"id" db:"id"`
+	UserId          int64                   `json:"-" db:"user_id"`
+	Name            string                  `json:"name" db:"name"`
+	Description     *string                 `json:"description,omitempty" db:"description"`
+	TotalBudget     decimal.Decimal         `json:"total_budget" db:"total_budget"`
+	StartDate       time.Time               `json:"start_date" db:"start_date"`
+	EndDate         time.Time               `json:"end_date" db:"end_date"`
+	CreatedAt       time.Time               `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time               `json:"updated_at" db:"updated_at"`
+	CategoryBudgets []ProjectCategoryBudget `json:"category_budgets" db:"-"`
+}
+
+// ProjectCategoryBudget is the part of a project's budget planned for a category.
+type ProjectCategoryBudget struct {
+	CategoryId   int64           `json:"category_id" db:"category_id"`
+	Amount       decimal.Decimal `json:"amount" db:"amount"`
+	CategoryName string          `json:"category_name,omitempty" db:"category_name"`
+}
@@ -0,0 +1,15 @@
+package model
+
+// QuotaMetric is the consumption of a single quota. A Limit of zero means unlimited.
+type QuotaMetric struct {
+	Used  int64 `json:"used"`
+	Limit int64 `json:"limit"`
+}
+
+// QuotaUsage reports a user's consumption of each configured quota.
+type QuotaUsage struct {
+	Accounts              QuotaMetric `json:"accounts"`
+	TransactionsThisMonth QuotaMetric `json:"transactions_this_month"`
+	StorageBytes          QuotaMetric `json:"storage_bytes"`
+	RequestsToday         QuotaMetric `json:"requests_today"`
+}
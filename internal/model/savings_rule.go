@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+// SavingsRuleType tells what makes a savings rule move money.
+type SavingsRuleType string
+
+const (
+	// SavingsRoundUp rules round each expense of an account up to the next
+	// whole unit and save the difference, settled once a day.
+	SavingsRoundUp SavingsRuleType = "round_up"
+	// SavingsPercentOfIncome rules save a percentage of each income in a
+	// category as soon as it arrives (pay yourself first).
+	SavingsPercentOfIncome SavingsRuleType = "percent_of_income"
+)
+
+// SavingsRule moves money into SavingsAccountId automatically. AccountId is
+// the account whose expenses are rounded up, or the only account whose income
+// is saved when set on a percent-of-income rule. Round-ups are paid from
+// FundingAccountId, and income is saved from the account it arrived in.
+type SavingsRule struct {
+	Id               int64            `json:"id" db:"id"`
+	UserId           int64            `json:"-" db:"user_id"`
+	Type             SavingsRuleType  `json:"type" db:"type"`
+	SavingsAccountId int64            `json:"savings_account_id" db:"savings_account_id"`
+	AccountId        *int64           `json:"account_id,omitempty" db:"account_id"`
+	FundingAccountId *int64           `json:"funding_account_id,omitempty" db:"funding_account_id"`
+	CategoryId       *int64           `json:"category_id,omitempty" db:"category_id"`
+	Percent          *decimal.Decimal `json:"percent,omitempty" db:"percent"`
+	CreatedAt        time.Time        `json:"created_at" db:"created_at"`
+}
+
+// SavingsRuleRun records what a rule did with the transaction that triggered
+// it. TransferId is the transfer that moved Amount, once the run is settled.
+type SavingsRuleRun struct {
+	Id            int64           `json:"id" db:"id"`
+	RuleId        int64           `json:"rule_id" db:"rule_id"`
+	UserId        int64           `json:"-" db:"user_id"`
+	TransactionId int64           `json:"transaction_id" db:"transaction_id"`
+	Amount        decimal.Decimal `json:"amount" db:"amount"`
+	OccurredOn    time.Time       `json:"occurred_on" db:"occurred_on"`
+	TransferId    *int64          `json:"transfer_id,omitempty" db:"transfer_id"`
+	SettledAt     *time.Time      `json:"settled_at,omitempty" db:"settled_at"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+}
+
+// PendingRoundUp adds up the round-ups of a rule on one day that are waiting
+// to be settled, with the runs they come from.
+type PendingRoundUp struct {
+	RuleId     int64           `db:"rule_id"`
+	UserId     int64           `db:"user_id"`
+	OccurredOn time.Time       `db:"occurred_on"`
+	Amount     decimal.Decimal `db:"amount"`
+	RunIds     pq.Int64Array   `db:"run_ids"`
+}
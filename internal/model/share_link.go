@@ -0,0 +1,73 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ShareLinkScope is the kind of content a share link exposes.
+type ShareLinkScope string
+
+const (
+	ShareScopeMonthlyReport ShareLinkScope = "monthly_report"
+	ShareScopeStatement     ShareLinkScope = "statement"
+	ShareScopeTransactions  ShareLinkScope = "transactions"
+)
+
+// TransactionFilter is a saved set of transaction filters. Dates use the YYYY-MM-DD format.
+type TransactionFilter struct {
+	Description *string          `json:"description,omitempty"`
+	StartDate   *string          `json:"start_date,omitempty"`
+	EndDate     *string          `json:"end_date,omitempty"`
+	Type        *TransactionType `json:"type,omitempty"`
+	AccountId   *int64           `json:"account_id,omitempty"`
+	CategoryIds []int64          `json:"category_ids,omitempty"`
+}
+
+// ShareLinkParams identifies the content within the scope of a share link.
+// Year and Month are used by reports and statements, AccountId by statements
+// and Filter by transaction lists.
+type ShareLinkParams struct {
+	Year      int                `json:"year,omitempty"`
+	Month     int                `json:"month,omitempty"`
+	AccountId *int64             `json:"account_id,omitempty"`
+	Filter    *TransactionFilter `json:"filter,omitempty"`
+}
+
+// Value stores the params as JSONB.
+func (p ShareLinkParams) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// Scan reads the params from JSONB.
+func (p *ShareLinkParams) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	case nil:
+		*p = ShareLinkParams{}
+		return nil
+	default:
+		return errors.New("unsupported type for ShareLinkParams")
+	}
+}
+
+// ShareLink is a read-only, expiring and revocable link to a report, statement
+// or transaction list. Only the hash of the token is persisted.
+type ShareLink struct {
+	Id             int64           `json:"id" db:"id"`
+	UserId         int64           `json:"user_id" db:"user_id"`
+	TokenHash      string          `json:"-" db:"token_hash"`
+	Scope          ShareLinkScope  `json:"scope" db:"scope"`
+	Params         ShareLinkParams `json:"params" db:"params"`
+	PasswordHash   *string         `json:"-" db:"password_hash"`
+	ExpiresAt      time.Time       `json:"expires_at" db:"expires_at"`
+	RevokedAt      *time.Time      `json:"revoked_at,omitempty" db:"revoked_at"`
+	AccessCount    int64           `json:"access_count" db:"access_count"`
+	LastAccessedAt *time.Time      `json:"last_accessed_at,omitempty" db:"last_accessed_at"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// TelegramLinkCode is a one-time code that links a Telegram chat to its owner.
+// Only the hash of the code is persisted.
+type TelegramLinkCode struct {
+	Id        int64      `json:"id" db:"id"`
+	UserId    int64      `json:"user_id" db:"user_id"`
+	CodeHash  string     `json:"-" db:"code_hash"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// TelegramChat is a chat linked to a user. The bot acts as that user.
+type TelegramChat struct {
+	ChatId           int64     `json:"chat_id" db:"chat_id"`
+	UserId           int64     `json:"-" db:"user_id"`
+	DefaultAccountId *int64    `json:"default_account_id,omitempty" db:"default_account_id"`
+	LinkedAt         time.Time `json:"linked_at" db:"linked_at"`
+}
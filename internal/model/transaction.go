@@ -26,11 +26,29 @@ type Transaction struct {
 	AccountId            int64           `json:"account_id" db:"account_id"`
 	DestinationAccountId *int64          `json:"destination_account_id,omitempty" db:"destination_account_id"`
 	CategoryId           *int64          `json:"category_id,omitempty" db:"category_id"`
+	ProjectId            *int64          `json:"project_id,omitempty" db:"project_id"`
+	CardId               *int64          `json:"card_id,omitempty" db:"card_id"`
 	CreatedAt            time.Time       `json:"created_at" db:"created_at"`
 	UpdatedAt            time.Time       `json:"updated_at" db:"updated_at"`
 
+	// Purchases abroad keep what was paid in the original currency. Amount is
+	// OriginalAmount converted at ExchangeRate plus SpreadRate percent.
+	OriginalCurrency *string          `json:"original_currency,omitempty" db:"original_currency"`
+	OriginalAmount   *decimal.Decimal `json:"original_amount,omitempty" db:"original_amount"`
+	ExchangeRate     *decimal.Decimal `json:"exchange_rate,omitempty" db:"exchange_rate"`
+	SpreadRate       *decimal.Decimal `json:"spread_rate,omitempty" db:"spread_rate"`
+	// FeeOfTransactionId links a fee, like the IOF of a foreign purchase, to the purchase.
+	FeeOfTransactionId *int64 `json:"fee_of_transaction_id,omitempty" db:"fee_of_transaction_id"`
+
 	// Campos populados para respostas de API, não são colunas diretas
 	CategoryName *string `json:"category_name,omitempty" db:"category_name"`
 	AccountName  string  `json:"account_name" db:"account_name"`
 	Tags         []Tag   `json:"tags,omitempty"`
+
+	// Card fields are only populated in statements.
+	CardHolderName *string `json:"card_holder_name,omitempty" db:"card_holder_name"`
+	CardLastFour   *string `json:"card_last_four,omitempty" db:"card_last_four"`
+
+	// AccountType lets reports tell benefit spending apart; it is not sent to clients.
+	AccountType AccountType `json:"-" db:"account_type"`
 }
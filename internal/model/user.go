@@ -22,6 +22,29 @@ type User struct {
 	// em qualquer resposta da API, por segurança.
 	PasswordHash string `json:"-" db:"password_hash"`
 
+	// IsAdmin grants access to the instance administration routes.
+	IsAdmin bool `json:"is_admin" db:"is_admin"`
+	// DisabledAt is set when an admin suspends the user; disabled users cannot authenticate.
+	DisabledAt *time.Time `json:"disabled_at,omitempty" db:"disabled_at"`
+	// PasswordResetRequired forces the user to choose a new password before using the API.
+	PasswordResetRequired bool `json:"password_reset_required" db:"password_reset_required"`
+
+	// FiscalMonthStartDay is the day budgets and reports start each month on.
+	// Short months start on their last day instead.
+	FiscalMonthStartDay int `json:"fiscal_month_start_day" db:"fiscal_month_start_day"`
+	// FiscalMonthLabel tells which calendar month names a fiscal month.
+	FiscalMonthLabel FiscalMonthLabel `json:"fiscal_month_label" db:"fiscal_month_label"`
+
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
+
+// FiscalMonthLabel names a fiscal month after the calendar month it starts or
+// ends in. With the 25th as start day, 25 Jan to 24 Feb is January for
+// FiscalMonthLabelStart and February for FiscalMonthLabelEnd.
+type FiscalMonthLabel string
+
+const (
+	FiscalMonthLabelStart FiscalMonthLabel = "start"
+	FiscalMonthLabelEnd   FiscalMonthLabel = "end"
+)
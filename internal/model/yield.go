@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// YieldType tells how a savings account earns yield.
+type YieldType string
+
+const (
+	// YieldFixed accounts earn YieldRate percent a year.
+	YieldFixed YieldType = "fixed"
+	// YieldCDI accounts earn YieldRate percent of the CDI, like most CDBs and RDBs.
+	YieldCDI YieldType = "cdi"
+	// YieldPoupanca accounts follow the poupança rule: 0.5% a month while the
+	// Selic is above 8.5% a year, and 70% of the Selic otherwise.
+	YieldPoupanca YieldType = "poupanca"
+)
+
+// YieldFrequency tells how often the yield of an account is posted.
+type YieldFrequency string
+
+const (
+	YieldDaily   YieldFrequency = "daily"
+	YieldWeekly  YieldFrequency = "weekly"
+	YieldMonthly YieldFrequency = "monthly"
+)
+
+// IndexName identifies an interest rate index.
+type IndexName string
+
+const (
+	IndexCDI   IndexName = "cdi"
+	IndexSelic IndexName = "selic"
+)
+
+// IndexRate is the rate of an index on a business day, in percent a year.
+type IndexRate struct {
+	Name       IndexName       `json:"name" db:"name"`
+	Date       time.Time       `json:"date" db:"date"`
+	AnnualRate decimal.Decimal `json:"annual_rate" db:"annual_rate"`
+}
+
+// YieldAccrual records the yield posted to a savings account for the days from
+// PeriodStart until the day before PeriodEnd. IncomeTaxAmount is the IR
+// withheld from GrossAmount, if the account withholds it.
+type YieldAccrual struct {
+	Id              int64           `json:"id" db:"id"`
+	UserId          int64           `json:"-" db:"user_id"`
+	AccountId       int64           `json:"account_id" db:"account_id"`
+	PeriodStart     time.Time       `json:"period_start" db:"period_start"`
+	PeriodEnd       time.Time       `json:"period_end" db:"period_end"`
+	GrossAmount     decimal.Decimal `json:"gross_amount" db:"gross_amount"`
+	IncomeTaxAmount decimal.Decimal `json:"income_tax_amount" db:"income_tax_amount"`
+	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
+}
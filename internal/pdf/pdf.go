@@ -0,0 +1,151 @@
+// Package pdf writes simple, text-only PDF documents using the standard
+// Helvetica fonts, which every PDF reader ships with.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	pageWidth    = 595 // A4, in points
+	pageHeight   = 842
+	margin       = 50
+	fontSize     = 10
+	headingSize  = 14
+	lineHeight   = 14
+	linesPerPage = (pageHeight - 2*margin) / lineHeight
+)
+
+type line struct {
+	text    string
+	heading bool
+}
+
+// Document accumulates lines of text and lays them out on A4 pages.
+type Document struct {
+	title string
+	lines []line
+}
+
+// New creates an empty document. The title is stored in the document metadata.
+func New(title string) *Document {
+	return &Document{title: title}
+}
+
+// Heading adds a line in bold, larger type.
+func (d *Document) Heading(text string) {
+	d.lines = append(d.lines, line{text: text, heading: true})
+}
+
+// Text adds a line in regular type.
+func (d *Document) Text(text string) {
+	d.lines = append(d.lines, line{text: text})
+}
+
+// Textf adds a formatted line in regular type.
+func (d *Document) Textf(format string, args ...any) {
+	d.Text(fmt.Sprintf(format, args...))
+}
+
+// Blank adds an empty line.
+func (d *Document) Blank() {
+	d.Text("")
+}
+
+// Bytes renders the document.
+func (d *Document) Bytes() []byte {
+	pages := d.paginate()
+
+	// Object layout: 1 catalog, 2 page tree, 3 regular font, 4 bold font,
+	// 5 info, then a page object and a content stream per page.
+	const firstPageObject = 6
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"", // page tree, filled in below
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Title (%s) /Producer (gofinance-tracker-api) >>", escape(d.title)),
+	}
+
+	kids := make([]string, 0, len(pages))
+	for i, page := range pages {
+		pageObject := firstPageObject + 2*i
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageObject))
+
+		content := renderPage(page)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pageWidth, pageHeight, pageObject+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xrefOffset := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xrefOffset)
+	return buf.Bytes()
+}
+
+// paginate splits the lines into pages. A document always has at least one page.
+func (d *Document) paginate() [][]line {
+	pages := [][]line{}
+	for start := 0; start < len(d.lines); start += linesPerPage {
+		end := min(start+linesPerPage, len(d.lines))
+		pages = append(pages, d.lines[start:end])
+	}
+	if len(pages) == 0 {
+		pages = append(pages, nil)
+	}
+	return pages
+}
+
+func renderPage(lines []line) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "BT\n%d TL\n%d %d Td\n", lineHeight, margin, pageHeight-margin)
+	for _, l := range lines {
+		if l.heading {
+			fmt.Fprintf(&b, "/F2 %d Tf\n", headingSize)
+		} else {
+			fmt.Fprintf(&b, "/F1 %d Tf\n", fontSize)
+		}
+		fmt.Fprintf(&b, "(%s) Tj T*\n", escape(l.text))
+	}
+	b.WriteString("ET")
+	return b.String()
+}
+
+// escape converts text to a WinAnsi-encoded PDF string literal body. Characters
+// outside Latin-1 are replaced with '?'.
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r < 32:
+			b.WriteByte(' ')
+		case r < 128:
+			b.WriteByte(byte(r))
+		case r >= 160 && r < 256:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,67 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument(t *testing.T) {
+	t.Run("should render a well-formed single page document", func(t *testing.T) {
+		// Arrange
+		doc := New("Relatório")
+		doc.Heading("Monthly report (July)")
+		doc.Textf("Total: %s", "1500.00")
+
+		// Act
+		out := doc.Bytes()
+
+		// Assert
+		assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+		assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+		assert.Contains(t, string(out), `(Monthly report \(July\)) Tj`)
+		assert.Contains(t, string(out), `(Total: 1500.00) Tj`)
+		assert.Contains(t, string(out), `/Title (Relat\363rio)`)
+		assert.Contains(t, string(out), "/Count 1")
+		assertXrefOffsets(t, out)
+	})
+
+	t.Run("should split long documents into pages", func(t *testing.T) {
+		// Arrange
+		doc := New("Long")
+		for i := 0; i < linesPerPage*2+1; i++ {
+			doc.Textf("line %d", i)
+		}
+
+		// Act
+		out := doc.Bytes()
+
+		// Assert
+		assert.Contains(t, string(out), "/Count 3")
+		assertXrefOffsets(t, out)
+	})
+
+	t.Run("should render an empty document as one blank page", func(t *testing.T) {
+		out := New("Empty").Bytes()
+		assert.Contains(t, string(out), "/Count 1")
+		assertXrefOffsets(t, out)
+	})
+}
+
+// assertXrefOffsets checks that every cross-reference entry points at the start of its object.
+func assertXrefOffsets(t *testing.T, out []byte) {
+	t.Helper()
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out, -1)
+	require.NotEmpty(t, entries)
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(string(entry[1]))
+		require.NoError(t, err)
+		expected := fmt.Sprintf("%d 0 obj", i+1)
+		assert.True(t, bytes.HasPrefix(out[offset:], []byte(expected)), "xref entry %d should point to %q", i+1, expected)
+	}
+}
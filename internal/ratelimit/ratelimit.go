@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter is an in-memory, fixed-window rate limiter keyed by an arbitrary string
+// (e.g. an e-mail address). It is safe for concurrent use.
+type Limiter struct {
+	mu      sync.Mutex
+	max     int
+	window  time.Duration
+	now     func() time.Time
+	windows map[string]*window
+}
+
+type window struct {
+	start time.Time
+	count int
+}
+
+// New creates a Limiter that allows at most max events per key in each window.
+func New(max int, windowSize time.Duration) *Limiter {
+	return &Limiter{
+		max:     max,
+		window:  windowSize,
+		now:     time.Now,
+		windows: make(map[string]*window),
+	}
+}
+
+// Allow records an event for the key and reports whether it is within the limit.
+func (l *Limiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.evictExpired(now)
+
+	w, ok := l.windows[key]
+	if !ok {
+		w = &window{start: now}
+		l.windows[key] = w
+	}
+	if w.count >= l.max {
+		return false
+	}
+	w.count++
+	return true
+}
+
+// Count returns how many events were recorded for the key in its current window.
+func (l *Limiter) Count(key string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	w, ok := l.windows[key]
+	if !ok || l.now().Sub(w.start) >= l.window {
+		return 0
+	}
+	return w.count
+}
+
+// evictExpired drops finished windows so the map does not grow without bound.
+func (l *Limiter) evictExpired(now time.Time) {
+	for key, w := range l.windows {
+		if now.Sub(w.start) >= l.window {
+			delete(l.windows, key)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	current := time.Date(2025, time.July, 1, 12, 0, 0, 0, time.UTC)
+	limiter := New(2, time.Minute)
+	limiter.now = func() time.Time { return current }
+
+	t.Run("should allow events up to the limit per key", func(t *testing.T) {
+		assert.True(t, limiter.Allow("a@test.com"))
+		assert.True(t, limiter.Allow("a@test.com"))
+		assert.False(t, limiter.Allow("a@test.com"))
+
+		// Other keys have their own window.
+		assert.True(t, limiter.Allow("b@test.com"))
+	})
+
+	t.Run("should count the events of the current window", func(t *testing.T) {
+		assert.Equal(t, 2, limiter.Count("a@test.com"))
+		assert.Equal(t, 0, limiter.Count("unknown@test.com"))
+	})
+
+	t.Run("should reset the window after it expires", func(t *testing.T) {
+		current = current.Add(time.Minute)
+		assert.Equal(t, 0, limiter.Count("a@test.com"))
+		assert.True(t, limiter.Allow("a@test.com"))
+	})
+}
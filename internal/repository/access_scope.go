@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+)
+
+type accessScopeKey struct{}
+
+// WithAccessScope marks the context as a collaborator acting on behalf of the
+// scope owner. Repositories then only return data within the scope.
+func WithAccessScope(ctx context.Context, scope *model.AccessScope) context.Context {
+	return context.WithValue(ctx, accessScopeKey{}, scope)
+}
+
+// AccessScopeFromContext returns the collaborator scope of the request, or nil
+// when the owner is acting on their own data.
+func AccessScopeFromContext(ctx context.Context) *model.AccessScope {
+	scope, _ := ctx.Value(accessScopeKey{}).(*model.AccessScope)
+	return scope
+}
+
+// applyTransactionScope restricts a transactions query, aliased as t, to the
+// accounts and period of the scope. A nil scope leaves the query untouched.
+func applyTransactionScope(builder squirrel.SelectBuilder, scope *model.AccessScope) squirrel.SelectBuilder {
+	if scope == nil {
+		return builder
+	}
+	builder = builder.Where(squirrel.Or{
+		squirrel.Eq{"t.account_id": scope.AccountIds},
+		squirrel.Eq{"t.destination_account_id": scope.AccountIds},
+	})
+	if scope.StartDate != nil {
+		builder = builder.Where(squirrel.GtOrEq{"t.date": *scope.StartDate})
+	}
+	if end := scope.PeriodEnd(); end != nil {
+		builder = builder.Where(squirrel.Lt{"t.date": *end})
+	}
+	return builder
+}
+
+// ErrReadOnlyAccess is returned by write operations attempted under a
+// collaborator scope. The API already rejects such requests; this is a safety net.
+var ErrReadOnlyAccess = errors.New("collaborator access is read-only")
+
+// denyWrites fails when the context carries a collaborator scope.
+func denyWrites(ctx context.Context) error {
+	if AccessScopeFromContext(ctx) != nil {
+		return ErrReadOnlyAccess
+	}
+	return nil
+}
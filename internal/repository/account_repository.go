@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"slices"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
@@ -18,6 +20,11 @@ type AccountRepository interface {
 	Update(ctx context.Context, acc model.Account) error
 	Delete(ctx context.Context, id, userId int64) error
 	GetCurrentBalance(ctx context.Context, accountID int64, userId int64) (decimal.Decimal, error)
+	// ListBillingCycles returns the versions of a card's billing cycle, oldest first.
+	ListBillingCycles(ctx context.Context, accountId, userId int64) ([]model.BillingCycle, error)
+	// SaveBillingCycle records a version of a card's billing cycle, replacing
+	// the one with the same effective date.
+	SaveBillingCycle(ctx context.Context, cycle model.BillingCycle, userId int64) error
 }
 
 type pqAccountRepository struct {
@@ -29,10 +36,28 @@ func NewAccountRepository(db *sqlx.DB) AccountRepository {
 }
 
 func (r *pqAccountRepository) Create(ctx context.Context, acc model.Account) (int64, error) {
+	if err := denyWrites(ctx); err != nil {
+		return 0, err
+	}
+	// Cards get the first version of their billing cycle in the same statement.
 	query := `
-		INSERT INTO accounts (user_id, name, type, initial_balance, statement_closing_day, payment_due_day) 
-		VALUES (:user_id, :name, :type, :initial_balance, :statement_closing_day, :payment_due_day) 
-		RETURNING id
+		WITH account AS (
+			INSERT INTO accounts (user_id, name, type, initial_balance, statement_closing_day, payment_due_day, points_value_per_thousand,
+				benefit_allowed_category_ids, benefit_monthly_credit, benefit_credit_day, benefit_expires_unused,
+				asset_purchase_value, asset_purchase_date, asset_valuation_method, asset_useful_life_months, asset_annual_depreciation_rate, asset_salvage_value,
+				overdraft_limit, overdraft_monthly_rate, yield_type, yield_rate, yield_frequency, yield_start_date, yield_withholds_ir) 
+			VALUES (:user_id, :name, :type, :initial_balance, :statement_closing_day, :payment_due_day, :points_value_per_thousand,
+				:benefit_allowed_category_ids, :benefit_monthly_credit, :benefit_credit_day, :benefit_expires_unused,
+				:asset_purchase_value, :asset_purchase_date, :asset_valuation_method, :asset_useful_life_months, :asset_annual_depreciation_rate, :asset_salvage_value,
+				:overdraft_limit, :overdraft_monthly_rate, :yield_type, :yield_rate, :yield_frequency, :yield_start_date, :yield_withholds_ir) 
+			RETURNING id, type, statement_closing_day, payment_due_day, created_at
+		), cycle AS (
+			INSERT INTO billing_cycles (account_id, statement_closing_day, payment_due_day, effective_from)
+			SELECT id, statement_closing_day, payment_due_day, created_at::date
+			FROM account
+			WHERE type = 'credit_card' AND statement_closing_day IS NOT NULL AND payment_due_day IS NOT NULL
+		)
+		SELECT id FROM account
 	`
 
 	rows, err := r.db.NamedQueryContext(ctx, query, acc)
@@ -58,6 +83,9 @@ func (r *pqAccountRepository) GetById(ctx context.Context, id, userId int64) (*m
 	var acc model.Account
 	query := `SELECT * FROM accounts WHERE id = $1 AND user_id = $2`
 	err := r.db.GetContext(ctx, &acc, query, id, userId)
+	if err == nil && !accountInScope(ctx, acc.Id) {
+		return &acc, sql.ErrNoRows
+	}
 	return &acc, err
 }
 
@@ -70,6 +98,9 @@ func (r *pqAccountRepository) GetByName(ctx context.Context, name string, userId
 	// GetContext is perfect here as we expect exactly one result.
 	// It will correctly return sql.ErrNoRows if the account is not found.
 	err := r.db.GetContext(ctx, &acc, query, name, userId)
+	if err == nil && !accountInScope(ctx, acc.Id) {
+		return &acc, sql.ErrNoRows
+	}
 	return &acc, err
 }
 
@@ -77,10 +108,21 @@ func (r *pqAccountRepository) ListByUserId(ctx context.Context, userId int64) ([
 	var accounts []model.Account
 	query := `SELECT * FROM accounts WHERE user_id = $1 ORDER BY name`
 	err := r.db.SelectContext(ctx, &accounts, query, userId)
-	return accounts, err
+	if err != nil {
+		return accounts, err
+	}
+	if scope := AccessScopeFromContext(ctx); scope != nil {
+		accounts = slices.DeleteFunc(accounts, func(acc model.Account) bool {
+			return !scope.AllowsAccount(acc.Id)
+		})
+	}
+	return accounts, nil
 }
 
 func (r *pqAccountRepository) Update(ctx context.Context, acc model.Account) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
 	query := `
 		UPDATE accounts 
 		SET 
@@ -89,6 +131,24 @@ func (r *pqAccountRepository) Update(ctx context.Context, acc model.Account) err
 			initial_balance = :initial_balance,
 			statement_closing_day = :statement_closing_day,
 			payment_due_day = :payment_due_day,
+			points_value_per_thousand = :points_value_per_thousand,
+			benefit_allowed_category_ids = :benefit_allowed_category_ids,
+			benefit_monthly_credit = :benefit_monthly_credit,
+			benefit_credit_day = :benefit_credit_day,
+			benefit_expires_unused = :benefit_expires_unused,
+			asset_purchase_value = :asset_purchase_value,
+			asset_purchase_date = :asset_purchase_date,
+			asset_valuation_method = :asset_valuation_method,
+			asset_useful_life_months = :asset_useful_life_months,
+			asset_annual_depreciation_rate = :asset_annual_depreciation_rate,
+			asset_salvage_value = :asset_salvage_value,
+			overdraft_limit = :overdraft_limit,
+			overdraft_monthly_rate = :overdraft_monthly_rate,
+			yield_type = :yield_type,
+			yield_rate = :yield_rate,
+			yield_frequency = :yield_frequency,
+			yield_start_date = :yield_start_date,
+			yield_withholds_ir = :yield_withholds_ir,
 			updated_at = NOW() 
 		WHERE 
 			id = :id AND user_id = :user_id
@@ -106,6 +166,9 @@ func (r *pqAccountRepository) Update(ctx context.Context, acc model.Account) err
 }
 
 func (r *pqAccountRepository) Delete(ctx context.Context, id, userId int64) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
 	// ATENÇÃO: A constraint 'ON DELETE RESTRICT' na tabela 'transactions'
 	// impedirá a exclusão de uma conta que tenha transações.
 	query := `DELETE FROM accounts WHERE id = $1 AND user_id = $2`
@@ -122,6 +185,9 @@ func (r *pqAccountRepository) Delete(ctx context.Context, id, userId int64) erro
 
 func (r *pqAccountRepository) GetCurrentBalance(ctx context.Context, accountID int64, userId int64) (decimal.Decimal, error) {
 	var balance decimal.Decimal
+	if !accountInScope(ctx, accountID) {
+		return balance, sql.ErrNoRows
+	}
 
 	query := `
 		WITH movements AS (
@@ -145,3 +211,73 @@ func (r *pqAccountRepository) GetCurrentBalance(ctx context.Context, accountID i
 	err := r.db.GetContext(ctx, &balance, query, accountID, userId)
 	return balance, err
 }
+
+// selectDailyBalances returns the balance of the account at the end of each day
+// from start until the day before end. It is shared by the jobs that charge or
+// pay interest on daily balances.
+func selectDailyBalances(ctx context.Context, db *sqlx.DB, account model.Account, start, end time.Time) ([]decimal.Decimal, error) {
+	var balances []decimal.Decimal
+	query := `
+		WITH movements AS (
+			SELECT date, amount FROM transactions WHERE destination_account_id = $1 AND type = 'transfer' AND user_id = $2
+			UNION ALL
+			SELECT date, amount FROM transactions WHERE account_id = $1 AND type = 'income' AND user_id = $2
+			UNION ALL
+			SELECT date, -amount FROM transactions WHERE account_id = $1 AND type IN ('expense', 'transfer') AND user_id = $2
+		)
+		SELECT a.initial_balance + (
+			SELECT COALESCE(SUM(m.amount), 0) FROM movements m WHERE m.date < day + INTERVAL '1 day'
+		)
+		FROM accounts a
+		CROSS JOIN generate_series($3::timestamptz, $4::timestamptz - INTERVAL '1 day', INTERVAL '1 day') AS day
+		WHERE a.id = $1 AND a.user_id = $2
+		ORDER BY day
+	`
+	err := db.SelectContext(ctx, &balances, query, account.Id, account.UserId, start, end)
+	return balances, err
+}
+
+func (r *pqAccountRepository) ListBillingCycles(ctx context.Context, accountId, userId int64) ([]model.BillingCycle, error) {
+	cycles := []model.BillingCycle{}
+	if !accountInScope(ctx, accountId) {
+		return cycles, sql.ErrNoRows
+	}
+	query := `
+		SELECT bc.id, bc.account_id, bc.statement_closing_day, bc.payment_due_day, bc.effective_from, bc.created_at
+		FROM billing_cycles bc
+		JOIN accounts a ON a.id = bc.account_id
+		WHERE bc.account_id = $1 AND a.user_id = $2
+		ORDER BY bc.effective_from
+	`
+	err := r.db.SelectContext(ctx, &cycles, query, accountId, userId)
+	return cycles, err
+}
+
+func (r *pqAccountRepository) SaveBillingCycle(ctx context.Context, cycle model.BillingCycle, userId int64) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO billing_cycles (account_id, statement_closing_day, payment_due_day, effective_from)
+		SELECT id, $2, $3, $4 FROM accounts WHERE id = $1 AND user_id = $5
+		ON CONFLICT (account_id, effective_from) DO UPDATE SET
+			statement_closing_day = EXCLUDED.statement_closing_day,
+			payment_due_day = EXCLUDED.payment_due_day
+	`
+	result, err := r.db.ExecContext(ctx, query, cycle.AccountId, cycle.StatementClosingDay, cycle.PaymentDueDay, cycle.EffectiveFrom, userId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// accountInScope reports whether a collaborator acting on behalf of the owner
+// may see the account. It is always true for the owner.
+func accountInScope(ctx context.Context, accountId int64) bool {
+	scope := AccessScopeFromContext(ctx)
+	return scope == nil || scope.AllowsAccount(accountId)
+}
@@ -162,3 +162,53 @@ func TestAccountRepositoryGetCurrentBalance(t *testing.T) {
 		require.True(initialBalance.Equal(currentBalance), "Expected current balance to be equal to initial balance")
 	})
 }
+
+func TestAccountRepositoryBillingCycles(t *testing.T) {
+	testhelper.TruncateTables(t, testDB)
+	ctx, require, userRepo, accountRepo, _ := setupTestAccountRepository(t, testDB)
+
+	userId, err := userRepo.Create(ctx, model.User{Name: "Card User", Email: "card@test.com", PasswordHash: "hash"})
+	require.NoError(err)
+	otherUserId, err := userRepo.Create(ctx, model.User{Name: "Other User", Email: "other@test.com", PasswordHash: "hash"})
+	require.NoError(err)
+	cardId, err := accountRepo.Create(ctx, model.Account{
+		UserId: userId, Name: "Card", Type: model.CreditCard, StatementClosingDay: testhelper.Ptr(10), PaymentDueDay: testhelper.Ptr(18),
+	})
+	require.NoError(err)
+
+	t.Run("should record the first billing cycle when a card is created", func(t *testing.T) {
+		cycles, err := accountRepo.ListBillingCycles(ctx, cardId, userId)
+
+		require.NoError(err)
+		require.Len(cycles, 1)
+		require.Equal(10, cycles[0].StatementClosingDay)
+		require.Equal(18, cycles[0].PaymentDueDay)
+	})
+
+	t.Run("should list versions oldest first and replace one with the same date", func(t *testing.T) {
+		// Arrange
+		effectiveFrom := time.Now().UTC().AddDate(0, 1, 0).Truncate(24 * time.Hour)
+		require.NoError(accountRepo.SaveBillingCycle(ctx, model.BillingCycle{AccountId: cardId, StatementClosingDay: 25, PaymentDueDay: 5, EffectiveFrom: effectiveFrom}, userId))
+
+		// Act
+		err := accountRepo.SaveBillingCycle(ctx, model.BillingCycle{AccountId: cardId, StatementClosingDay: 20, PaymentDueDay: 28, EffectiveFrom: effectiveFrom}, userId)
+
+		// Assert
+		require.NoError(err)
+		cycles, err := accountRepo.ListBillingCycles(ctx, cardId, userId)
+		require.NoError(err)
+		require.Len(cycles, 2)
+		require.Equal(10, cycles[0].StatementClosingDay)
+		require.Equal(20, cycles[1].StatementClosingDay)
+		require.True(effectiveFrom.Equal(cycles[1].EffectiveFrom))
+	})
+
+	t.Run("security: user A cannot see or change user B's billing cycles", func(t *testing.T) {
+		cycles, err := accountRepo.ListBillingCycles(ctx, cardId, otherUserId)
+		require.NoError(err)
+		require.Empty(cycles)
+
+		err = accountRepo.SaveBillingCycle(ctx, model.BillingCycle{AccountId: cardId, StatementClosingDay: 1, PaymentDueDay: 9, EffectiveFrom: time.Now()}, otherUserId)
+		require.ErrorIs(err, sql.ErrNoRows)
+	})
+}
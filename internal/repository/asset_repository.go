@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/rs/zerolog"
+)
+
+type AssetRepository interface {
+	ListDepreciatingAccounts(ctx context.Context) ([]model.Account, error)
+	CreateValuation(ctx context.Context, valuation model.AssetValuation) (int64, error)
+	CreateDepreciation(ctx context.Context, valuation model.AssetValuation) (bool, error)
+	ListValuations(ctx context.Context, userId, accountId int64) ([]model.AssetValuation, error)
+}
+
+type pqAssetRepository struct {
+	db *sqlx.DB
+}
+
+func NewAssetRepository(db *sqlx.DB) AssetRepository {
+	return &pqAssetRepository{db: db}
+}
+
+// ListDepreciatingAccounts returns the asset accounts of every user that lose
+// value over time. It is meant for background jobs.
+func (r *pqAssetRepository) ListDepreciatingAccounts(ctx context.Context) ([]model.Account, error) {
+	var accounts []model.Account
+	query := `
+		SELECT * FROM accounts
+		WHERE type = 'asset' AND asset_valuation_method IN ('straight_line', 'declining_balance')
+		ORDER BY id
+	`
+	err := r.db.SelectContext(ctx, &accounts, query)
+	return accounts, err
+}
+
+// CreateValuation stores a manual revaluation. The depreciation adjustments
+// dated after it were based on an older value, so they are dropped in the same
+// database transaction.
+func (r *pqAssetRepository) CreateValuation(ctx context.Context, valuation model.AssetValuation) (int64, error) {
+	if err := denyWrites(ctx); err != nil {
+		return 0, err
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error rolling back asset valuation")
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM asset_valuations
+		WHERE account_id = $1 AND source = 'depreciation' AND valued_on > $2::date
+	`, valuation.AccountId, valuation.ValuedOn); err != nil {
+		return 0, err
+	}
+
+	var id int64
+	err = tx.GetContext(ctx, &id, `
+		INSERT INTO asset_valuations (user_id, account_id, source, value, valued_on, note)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, valuation.UserId, valuation.AccountId, valuation.Source, valuation.Value, valuation.ValuedOn, valuation.Note)
+	if err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+// CreateDepreciation stores a generated depreciation adjustment. It returns
+// false when the adjustment for that date already exists.
+func (r *pqAssetRepository) CreateDepreciation(ctx context.Context, valuation model.AssetValuation) (bool, error) {
+	if err := denyWrites(ctx); err != nil {
+		return false, err
+	}
+	query := `
+		INSERT INTO asset_valuations (user_id, account_id, source, value, valued_on, note)
+		VALUES ($1, $2, 'depreciation', $3, $4, $5)
+		ON CONFLICT (account_id, valued_on) WHERE source = 'depreciation' DO NOTHING
+	`
+	result, err := r.db.ExecContext(ctx, query, valuation.UserId, valuation.AccountId, valuation.Value, valuation.ValuedOn, valuation.Note)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
+// ListValuations returns the valuations of an asset, oldest first.
+func (r *pqAssetRepository) ListValuations(ctx context.Context, userId, accountId int64) ([]model.AssetValuation, error) {
+	var valuations []model.AssetValuation
+	if !accountInScope(ctx, accountId) {
+		return valuations, nil
+	}
+	query := `
+		SELECT * FROM asset_valuations
+		WHERE user_id = $1 AND account_id = $2
+		ORDER BY valued_on, id
+	`
+	err := r.db.SelectContext(ctx, &valuations, query, userId, accountId)
+	return valuations, err
+}
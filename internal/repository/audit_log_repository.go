@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+)
+
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry model.AuditLogEntry) (int64, error)
+	List(ctx context.Context, limit, offset uint64) ([]model.AuditLogEntry, error)
+}
+
+type pqAuditLogRepository struct {
+	db *sqlx.DB
+}
+
+func NewAuditLogRepository(db *sqlx.DB) AuditLogRepository {
+	return &pqAuditLogRepository{db: db}
+}
+
+func (r *pqAuditLogRepository) Create(ctx context.Context, entry model.AuditLogEntry) (int64, error) {
+	return insertAuditLogEntry(ctx, r.db, entry)
+}
+
+// List returns the most recent entries first.
+func (r *pqAuditLogRepository) List(ctx context.Context, limit, offset uint64) ([]model.AuditLogEntry, error) {
+	var entries []model.AuditLogEntry
+	query := `SELECT * FROM admin_audit_log ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2`
+	err := r.db.SelectContext(ctx, &entries, query, limit, offset)
+	return entries, err
+}
+
+// insertAuditLogEntry writes an entry with the database or inside a database
+// transaction, so that other repositories can audit their own changes atomically.
+func insertAuditLogEntry(ctx context.Context, db sqlx.ExtContext, entry model.AuditLogEntry) (int64, error) {
+	if len(entry.Details) == 0 {
+		entry.Details = []byte("{}")
+	}
+	query, args, err := sqlx.Named(`
+		INSERT INTO admin_audit_log (admin_id, action, target_user_id, details)
+		VALUES (:admin_id, :action, :target_user_id, :details)
+		RETURNING id
+	`, entry)
+	if err != nil {
+		return 0, err
+	}
+	var id int64
+	err = sqlx.GetContext(ctx, db, &id, db.Rebind(query), args...)
+	return id, err
+}
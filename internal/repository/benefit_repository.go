@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/rs/zerolog"
+)
+
+type BenefitRepository interface {
+	ListScheduledAccounts(ctx context.Context) ([]model.Account, error)
+	RecordCredit(ctx context.Context, credit model.BenefitCredit, transactions []model.Transaction) (bool, error)
+	ListCredits(ctx context.Context, userId, accountId int64) ([]model.BenefitCredit, error)
+}
+
+type pqBenefitRepository struct {
+	db *sqlx.DB
+}
+
+func NewBenefitRepository(db *sqlx.DB) BenefitRepository {
+	return &pqBenefitRepository{db: db}
+}
+
+// ListScheduledAccounts returns the benefit accounts of every user that have a
+// monthly credit. It is meant for background jobs.
+func (r *pqBenefitRepository) ListScheduledAccounts(ctx context.Context) ([]model.Account, error) {
+	var accounts []model.Account
+	query := `
+		SELECT * FROM accounts
+		WHERE type = 'benefit' AND benefit_monthly_credit IS NOT NULL AND benefit_credit_day IS NOT NULL
+		ORDER BY id
+	`
+	err := r.db.SelectContext(ctx, &accounts, query)
+	return accounts, err
+}
+
+// RecordCredit stores the monthly credit and its transactions in one database
+// transaction, setting the id of each transaction. It returns false, writing
+// nothing, when the month was already credited.
+func (r *pqBenefitRepository) RecordCredit(ctx context.Context, credit model.BenefitCredit, transactions []model.Transaction) (bool, error) {
+	if err := denyWrites(ctx); err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error rolling back benefit credit")
+		}
+	}()
+
+	var id int64
+	err = tx.GetContext(ctx, &id, `
+		INSERT INTO benefit_credits (user_id, account_id, year, month, amount, expired_amount)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (account_id, year, month) DO NOTHING
+		RETURNING id
+	`, credit.UserId, credit.AccountId, credit.Year, credit.Month, credit.Amount, credit.ExpiredAmount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for i := range transactions {
+		query, args, err := sqlx.Named(insertTransactionQuery, transactions[i])
+		if err != nil {
+			return false, err
+		}
+		if err := tx.GetContext(ctx, &transactions[i].Id, tx.Rebind(query), args...); err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *pqBenefitRepository) ListCredits(ctx context.Context, userId, accountId int64) ([]model.BenefitCredit, error) {
+	var credits []model.BenefitCredit
+	if !accountInScope(ctx, accountId) {
+		return credits, nil
+	}
+	query := `
+		SELECT * FROM benefit_credits
+		WHERE user_id = $1 AND account_id = $2
+		ORDER BY year DESC, month DESC
+	`
+	err := r.db.SelectContext(ctx, &credits, query, userId, accountId)
+	return credits, err
+}
@@ -28,6 +28,9 @@ func NewBudgetRepository(db *sqlx.DB) BudgetRepository {
 }
 
 func (r *pqBudgetRepository) Create(ctx context.Context, budget model.Budget) (int64, error) {
+	if err := denyWrites(ctx); err != nil {
+		return 0, err
+	}
 	query := `
         INSERT INTO budgets (user_id, category_id, amount, month, year)
         VALUES (:user_id, :category_id, :amount, :month, :year)
@@ -80,6 +83,9 @@ func (r *pqBudgetRepository) ListByUserAndPeriod(ctx context.Context, userId int
 }
 
 func (r *pqBudgetRepository) Update(ctx context.Context, budget model.Budget) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
 	query := `
         UPDATE budgets SET amount = :amount, updated_at = NOW()
         WHERE id = :id AND user_id = :user_id
@@ -96,6 +102,9 @@ func (r *pqBudgetRepository) Update(ctx context.Context, budget model.Budget) er
 }
 
 func (r *pqBudgetRepository) Delete(ctx context.Context, id, userId int64) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
 	query := `DELETE FROM budgets WHERE id = $1 AND user_id = $2`
 	result, err := r.db.ExecContext(ctx, query, id, userId)
 	if err != nil {
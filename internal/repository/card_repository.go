@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+)
+
+type CardRepository interface {
+	Create(ctx context.Context, card model.Card) (int64, error)
+	GetById(ctx context.Context, id, userId int64) (*model.Card, error)
+	ListByAccountId(ctx context.Context, accountId, userId int64) ([]model.Card, error)
+	Update(ctx context.Context, card model.Card) error
+	Delete(ctx context.Context, id, userId int64) error
+}
+
+type pqCardRepository struct {
+	db *sqlx.DB
+}
+
+func NewCardRepository(db *sqlx.DB) CardRepository {
+	return &pqCardRepository{db: db}
+}
+
+func (r *pqCardRepository) Create(ctx context.Context, card model.Card) (int64, error) {
+	if err := denyWrites(ctx); err != nil {
+		return 0, err
+	}
+	var id int64
+	query := `
+		INSERT INTO cards (user_id, account_id, holder_name, last_four, virtual, spend_limit)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	err := r.db.GetContext(ctx, &id, query, card.UserId, card.AccountId, card.HolderName, card.LastFour, card.Virtual, card.SpendLimit)
+	return id, err
+}
+
+func (r *pqCardRepository) GetById(ctx context.Context, id, userId int64) (*model.Card, error) {
+	var card model.Card
+	query := `SELECT * FROM cards WHERE id = $1 AND user_id = $2`
+	err := r.db.GetContext(ctx, &card, query, id, userId)
+	if err == nil && !accountInScope(ctx, card.AccountId) {
+		return &card, sql.ErrNoRows
+	}
+	return &card, err
+}
+
+func (r *pqCardRepository) ListByAccountId(ctx context.Context, accountId, userId int64) ([]model.Card, error) {
+	cards := []model.Card{}
+	if !accountInScope(ctx, accountId) {
+		return cards, nil
+	}
+	query := `SELECT * FROM cards WHERE account_id = $1 AND user_id = $2 ORDER BY id`
+	err := r.db.SelectContext(ctx, &cards, query, accountId, userId)
+	return cards, err
+}
+
+func (r *pqCardRepository) Update(ctx context.Context, card model.Card) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
+	query := `
+		UPDATE cards
+		SET holder_name = $1, last_four = $2, virtual = $3, spend_limit = $4, updated_at = NOW()
+		WHERE id = $5 AND user_id = $6
+	`
+	result, err := r.db.ExecContext(ctx, query, card.HolderName, card.LastFour, card.Virtual, card.SpendLimit, card.Id, card.UserId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *pqCardRepository) Delete(ctx context.Context, id, userId int64) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx, `DELETE FROM cards WHERE id = $1 AND user_id = $2`, id, userId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -27,6 +27,9 @@ func NewCategoryRepository(db *sqlx.DB) CategoryRepository {
 }
 
 func (r *pqCategoryRepository) Create(ctx context.Context, ct model.Category) (int64, error) {
+	if err := denyWrites(ctx); err != nil {
+		return 0, err
+	}
 	query := `INSERT INTO categories (user_id, name, type) VALUES (:user_id, :name, :type) RETURNING id`
 	rows, err := r.db.NamedQueryContext(ctx, query, ct)
 	if err != nil {
@@ -73,6 +76,9 @@ func (r *pqCategoryRepository) ListByUserId(ctx context.Context, userId int64) (
 }
 
 func (r *pqCategoryRepository) Update(ctx context.Context, ct model.Category) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
 	query := `UPDATE categories SET name = :name, type = :type, updated_at = NOW() WHERE id = :id AND user_id = :user_id`
 	result, err := r.db.NamedExecContext(ctx, query, ct)
 	if err != nil {
@@ -86,6 +92,9 @@ func (r *pqCategoryRepository) Update(ctx context.Context, ct model.Category) er
 }
 
 func (r *pqCategoryRepository) Delete(ctx context.Context, id, userId int64) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
 	// ATENÇÃO: A constraint 'ON DELETE RESTRICT' na tabela 'transactions'
 	// impedirá a exclusão de uma conta que tenha transações.
 	query := `DELETE FROM categories WHERE id = $1 AND user_id = $2`
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/rs/zerolog"
+)
+
+type CollaboratorRepository interface {
+	Create(ctx context.Context, grant model.CollaboratorGrant) (int64, error)
+	GetById(ctx context.Context, id int64) (*model.CollaboratorGrant, error)
+	GetActive(ctx context.Context, ownerId, collaboratorId int64) (*model.CollaboratorGrant, error)
+	ListByOwnerId(ctx context.Context, ownerId int64) ([]model.CollaboratorGrant, error)
+	ListByInvitee(ctx context.Context, collaboratorId int64, email string) ([]model.CollaboratorGrant, error)
+	Accept(ctx context.Context, id, collaboratorId int64) error
+	Revoke(ctx context.Context, id, ownerId int64) error
+}
+
+type pqCollaboratorRepository struct {
+	db *sqlx.DB
+}
+
+func NewCollaboratorRepository(db *sqlx.DB) CollaboratorRepository {
+	return &pqCollaboratorRepository{db: db}
+}
+
+func (r *pqCollaboratorRepository) Create(ctx context.Context, grant model.CollaboratorGrant) (int64, error) {
+	query := `
+		INSERT INTO collaborator_grants (owner_id, invitee_email, account_ids, start_date, end_date)
+		VALUES (:owner_id, :invitee_email, :account_ids, :start_date, :end_date)
+		RETURNING id
+	`
+	rows, err := r.db.NamedQueryContext(ctx, query, grant)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error closing rows")
+		}
+	}()
+
+	var id int64
+	if rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+func (r *pqCollaboratorRepository) GetById(ctx context.Context, id int64) (*model.CollaboratorGrant, error) {
+	var grant model.CollaboratorGrant
+	query := `SELECT * FROM collaborator_grants WHERE id = $1`
+	err := r.db.GetContext(ctx, &grant, query, id)
+	if err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+// GetActive returns the accepted, non-revoked grant that lets the collaborator
+// act on behalf of the owner.
+func (r *pqCollaboratorRepository) GetActive(ctx context.Context, ownerId, collaboratorId int64) (*model.CollaboratorGrant, error) {
+	var grant model.CollaboratorGrant
+	query := `
+		SELECT * FROM collaborator_grants
+		WHERE owner_id = $1 AND collaborator_id = $2
+		  AND accepted_at IS NOT NULL AND revoked_at IS NULL
+	`
+	err := r.db.GetContext(ctx, &grant, query, ownerId, collaboratorId)
+	if err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+func (r *pqCollaboratorRepository) ListByOwnerId(ctx context.Context, ownerId int64) ([]model.CollaboratorGrant, error) {
+	var grants []model.CollaboratorGrant
+	query := `SELECT * FROM collaborator_grants WHERE owner_id = $1 ORDER BY created_at DESC`
+	err := r.db.SelectContext(ctx, &grants, query, ownerId)
+	return grants, err
+}
+
+// ListByInvitee returns the grants accepted by the collaborator and the pending
+// invitations sent to their e-mail.
+func (r *pqCollaboratorRepository) ListByInvitee(ctx context.Context, collaboratorId int64, email string) ([]model.CollaboratorGrant, error) {
+	var grants []model.CollaboratorGrant
+	query := `
+		SELECT * FROM collaborator_grants
+		WHERE collaborator_id = $1
+		   OR (collaborator_id IS NULL AND LOWER(invitee_email) = LOWER($2))
+		ORDER BY created_at DESC
+	`
+	err := r.db.SelectContext(ctx, &grants, query, collaboratorId, email)
+	return grants, err
+}
+
+// Accept binds a pending, non-revoked invitation to the collaborator.
+func (r *pqCollaboratorRepository) Accept(ctx context.Context, id, collaboratorId int64) error {
+	query := `
+		UPDATE collaborator_grants
+		SET collaborator_id = $2, accepted_at = NOW()
+		WHERE id = $1 AND collaborator_id IS NULL AND revoked_at IS NULL
+	`
+	result, err := r.db.ExecContext(ctx, query, id, collaboratorId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Revoke ends a grant or cancels a pending invitation. Revoking twice is a no-op.
+func (r *pqCollaboratorRepository) Revoke(ctx context.Context, id, ownerId int64) error {
+	query := `UPDATE collaborator_grants SET revoked_at = COALESCE(revoked_at, NOW()) WHERE id = $1 AND owner_id = $2`
+	result, err := r.db.ExecContext(ctx, query, id, ownerId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/rs/zerolog"
+)
+
+type MagicLinkRepository interface {
+	Create(ctx context.Context, token model.MagicLinkToken) (int64, error)
+	Consume(ctx context.Context, tokenHash string) (int64, error)
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type pqMagicLinkRepository struct {
+	db *sqlx.DB
+}
+
+func NewMagicLinkRepository(db *sqlx.DB) MagicLinkRepository {
+	return &pqMagicLinkRepository{db: db}
+}
+
+func (r *pqMagicLinkRepository) Create(ctx context.Context, token model.MagicLinkToken) (int64, error) {
+	query := `
+		INSERT INTO magic_link_tokens (user_id, token_hash, expires_at)
+		VALUES (:user_id, :token_hash, :expires_at)
+		RETURNING id
+	`
+	rows, err := r.db.NamedQueryContext(ctx, query, token)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error closing rows")
+		}
+	}()
+
+	var id int64
+	if rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+// Consume marks a valid token as used and returns the owner's user Id.
+// The check and the update happen in a single statement, so a token can only
+// be redeemed once even under concurrent requests. It returns sql.ErrNoRows
+// when the token does not exist, has expired or was already used.
+func (r *pqMagicLinkRepository) Consume(ctx context.Context, tokenHash string) (int64, error) {
+	var userId int64
+	query := `
+		UPDATE magic_link_tokens
+		SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id
+	`
+	err := r.db.GetContext(ctx, &userId, query, tokenHash)
+	return userId, err
+}
+
+// DeleteExpired removes tokens that can no longer be redeemed and returns how many were deleted.
+func (r *pqMagicLinkRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	query := `DELETE FROM magic_link_tokens WHERE expires_at <= NOW() OR used_at IS NOT NULL`
+	result, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
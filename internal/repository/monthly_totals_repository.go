@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/rs/zerolog"
+)
+
+// MonthlyTotalsRepository reads the monthly_category_totals aggregates, which
+// a database trigger keeps in step with the transactions table.
+type MonthlyTotalsRepository interface {
+	ListByPeriod(ctx context.Context, userId int64, year, month int) ([]model.MonthlyCategoryTotal, error)
+	Rebuild(ctx context.Context) (int64, error)
+}
+
+type pqMonthlyTotalsRepository struct {
+	db *sqlx.DB
+}
+
+func NewMonthlyTotalsRepository(db *sqlx.DB) MonthlyTotalsRepository {
+	return &pqMonthlyTotalsRepository{db: db}
+}
+
+// ListByPeriod returns the user's totals of a calendar month with the names of
+// their categories and the types of their accounts.
+func (r *pqMonthlyTotalsRepository) ListByPeriod(ctx context.Context, userId int64, year, month int) ([]model.MonthlyCategoryTotal, error) {
+	query := `
+		SELECT mt.user_id, mt.category_id, c.name AS category_name, mt.account_id, a.type AS account_type,
+			mt.type, mt.year, mt.month, mt.total, mt.count
+		FROM monthly_category_totals mt
+		JOIN accounts a ON a.id = mt.account_id
+		LEFT JOIN categories c ON c.id = mt.category_id
+		WHERE mt.user_id = $1 AND mt.year = $2 AND mt.month = $3
+		ORDER BY mt.category_id, mt.account_id, mt.type
+	`
+	var totals []model.MonthlyCategoryTotal
+	err := r.db.SelectContext(ctx, &totals, query, userId, year, month)
+	return totals, err
+}
+
+// rebuildMonthlyTotalsQuery recomputes every total from the transactions. It
+// must match what the trigger of migration 000017 maintains.
+const rebuildMonthlyTotalsQuery = `
+	INSERT INTO monthly_category_totals (user_id, category_id, account_id, type, year, month, total, count)
+	SELECT user_id, category_id, account_id, type,
+		EXTRACT(YEAR FROM date AT TIME ZONE 'UTC'), EXTRACT(MONTH FROM date AT TIME ZONE 'UTC'),
+		SUM(amount), COUNT(*)
+	FROM transactions
+	GROUP BY 1, 2, 3, 4, 5, 6
+`
+
+// Rebuild throws the totals away and recomputes them from the transactions,
+// returning how many rows were written. Transactions cannot be changed while
+// it runs.
+func (r *pqMonthlyTotalsRepository) Rebuild(ctx context.Context) (int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error rolling back monthly totals rebuild")
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE transactions IN SHARE MODE`); err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM monthly_category_totals`); err != nil {
+		return 0, err
+	}
+	result, err := tx.ExecContext(ctx, rebuildMonthlyTotalsQuery)
+	if err != nil {
+		return 0, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows, tx.Commit()
+}
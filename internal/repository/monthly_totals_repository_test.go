@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/testhelper"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+// monthlyTotalRow is a monthly total in a form that compares exactly.
+type monthlyTotalRow struct {
+	UserId     int64  `db:"user_id"`
+	CategoryId int64  `db:"category_id"`
+	AccountId  int64  `db:"account_id"`
+	Type       string `db:"type"`
+	Year       int    `db:"year"`
+	Month      int    `db:"month"`
+	Total      string `db:"total"`
+	Count      int    `db:"count"`
+}
+
+const storedMonthlyTotalsQuery = `
+	SELECT user_id, COALESCE(category_id, 0) AS category_id, account_id, type, year, month, total::text AS total, count
+	FROM monthly_category_totals
+	ORDER BY 1, 2, 3, 4, 5, 6
+`
+
+const recomputedMonthlyTotalsQuery = `
+	SELECT user_id, COALESCE(category_id, 0) AS category_id, account_id, type,
+		EXTRACT(YEAR FROM date AT TIME ZONE 'UTC')::int AS year, EXTRACT(MONTH FROM date AT TIME ZONE 'UTC')::int AS month,
+		SUM(amount)::text AS total, COUNT(*) AS count
+	FROM transactions
+	GROUP BY 1, 2, 3, 4, 5, 6
+	ORDER BY 1, 2, 3, 4, 5, 6
+`
+
+func requireTotalsMatchTransactions(t *testing.T, require *require.Assertions) {
+	t.Helper()
+	var stored, recomputed []monthlyTotalRow
+	require.NoError(testDB.Select(&stored, storedMonthlyTotalsQuery))
+	require.NoError(testDB.Select(&recomputed, recomputedMonthlyTotalsQuery))
+	require.Equal(recomputed, stored)
+}
+
+func TestMonthlyTotalsRepository(t *testing.T) {
+	testhelper.TruncateTables(t, testDB)
+	ctx, require, userRepo, accountRepo, txRepo := setupTestTransaction(t, testDB)
+	categoryRepo := NewCategoryRepository(testDB)
+	totalsRepo := NewMonthlyTotalsRepository(testDB)
+
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Totals User", Email: "totals@test.com", PasswordHash: "hash"})
+	var accountIds, categoryIds []int64
+	for _, name := range []string{"Checking", "Card", "Vouchers"} {
+		id, err := accountRepo.Create(ctx, model.Account{UserId: userId, Name: name, Type: model.Checking})
+		require.NoError(err)
+		accountIds = append(accountIds, id)
+	}
+	for _, name := range []string{"Food", "Transport", "Salary"} {
+		id, err := categoryRepo.Create(ctx, model.Category{UserId: userId, Name: name, Type: model.Expense})
+		require.NoError(err)
+		categoryIds = append(categoryIds, id)
+	}
+
+	t.Run("should keep the totals equal to a full recomputation under random mutations", func(t *testing.T) {
+		// A fixed seed keeps failures reproducible.
+		random := rand.New(rand.NewSource(42))
+		types := []model.TransactionType{model.Income, model.Expense, model.Transfer}
+		randomTransaction := func() model.Transaction {
+			tx := model.Transaction{
+				UserId:      userId,
+				AccountId:   accountIds[random.Intn(len(accountIds))],
+				Description: "random",
+				Amount:      decimal.New(int64(random.Intn(100000)+1), -2),
+				Type:        types[random.Intn(len(types))],
+				// Dates around month boundaries catch time zone mistakes.
+				Date: time.Date(2025, time.Month(random.Intn(4)+1), 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(random.Intn(96)-48) * time.Hour),
+			}
+			if random.Intn(4) > 0 {
+				tx.CategoryId = &categoryIds[random.Intn(len(categoryIds))]
+			}
+			return tx
+		}
+
+		var ids []int64
+		for range 300 {
+			switch op := random.Intn(10); {
+			case op < 5 || len(ids) == 0:
+				id, err := txRepo.Create(ctx, randomTransaction())
+				require.NoError(err)
+				ids = append(ids, id)
+			case op < 8:
+				tx := randomTransaction()
+				tx.Id = ids[random.Intn(len(ids))]
+				require.NoError(txRepo.Update(ctx, tx))
+			default:
+				i := random.Intn(len(ids))
+				require.NoError(txRepo.Delete(ctx, ids[i], userId))
+				ids = append(ids[:i], ids[i+1:]...)
+			}
+		}
+		requireTotalsMatchTransactions(t, require)
+
+		// Deleting a category moves its transactions to no category.
+		require.NoError(categoryRepo.Delete(ctx, categoryIds[0], userId))
+		requireTotalsMatchTransactions(t, require)
+
+		// Deleting an account's transactions removes their totals.
+		require.NoError(txRepo.DeleteByAccountId(ctx, userId, accountIds[1]))
+		requireTotalsMatchTransactions(t, require)
+	})
+
+	t.Run("should rebuild the totals from the transactions", func(t *testing.T) {
+		// Arrange: corrupt the totals behind the trigger's back.
+		_, err := testDB.Exec(`UPDATE monthly_category_totals SET total = total + 1`)
+		require.NoError(err)
+
+		// Act
+		rows, err := totalsRepo.Rebuild(context.Background())
+
+		// Assert
+		require.NoError(err)
+		require.NotZero(rows)
+		requireTotalsMatchTransactions(t, require)
+	})
+
+	t.Run("should list the totals of a month with category names and account types", func(t *testing.T) {
+		// Act
+		totals, err := totalsRepo.ListByPeriod(ctx, userId, 2025, 2)
+
+		// Assert
+		require.NoError(err)
+		require.NotEmpty(totals)
+		for _, total := range totals {
+			require.Equal(2025, total.Year)
+			require.Equal(2, total.Month)
+			require.Equal(model.Checking, total.AccountType)
+			require.Equal(total.CategoryId == nil, total.CategoryName == nil)
+		}
+	})
+}
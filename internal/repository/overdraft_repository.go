@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+type OverdraftRepository interface {
+	ListChargeableAccounts(ctx context.Context) ([]model.Account, error)
+	DailyBalances(ctx context.Context, account model.Account, start, end time.Time) ([]decimal.Decimal, error)
+	RecordCharge(ctx context.Context, charge model.OverdraftInterestCharge, transactions []model.Transaction) (bool, error)
+}
+
+type pqOverdraftRepository struct {
+	db *sqlx.DB
+}
+
+func NewOverdraftRepository(db *sqlx.DB) OverdraftRepository {
+	return &pqOverdraftRepository{db: db}
+}
+
+// ListChargeableAccounts returns the checking accounts of every user that are
+// charged overdraft interest. It is meant for background jobs.
+func (r *pqOverdraftRepository) ListChargeableAccounts(ctx context.Context) ([]model.Account, error) {
+	var accounts []model.Account
+	query := `
+		SELECT * FROM accounts
+		WHERE type = 'checking' AND overdraft_monthly_rate > 0
+		ORDER BY id
+	`
+	err := r.db.SelectContext(ctx, &accounts, query)
+	return accounts, err
+}
+
+// DailyBalances returns the balance of the account at the end of each day from
+// start until the day before end.
+func (r *pqOverdraftRepository) DailyBalances(ctx context.Context, account model.Account, start, end time.Time) ([]decimal.Decimal, error) {
+	return selectDailyBalances(ctx, r.db, account, start, end)
+}
+
+// RecordCharge stores the month's interest charge and its transactions in one
+// database transaction, setting the id of each transaction. It returns false,
+// writing nothing, when the month was already charged.
+func (r *pqOverdraftRepository) RecordCharge(ctx context.Context, charge model.OverdraftInterestCharge, transactions []model.Transaction) (bool, error) {
+	if err := denyWrites(ctx); err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error rolling back overdraft interest charge")
+		}
+	}()
+
+	var id int64
+	err = tx.GetContext(ctx, &id, `
+		INSERT INTO overdraft_interest_charges (user_id, account_id, year, month, amount)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (account_id, year, month) DO NOTHING
+		RETURNING id
+	`, charge.UserId, charge.AccountId, charge.Year, charge.Month, charge.Amount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for i := range transactions {
+		query, args, err := sqlx.Named(insertTransactionQuery, transactions[i])
+		if err != nil {
+			return false, err
+		}
+		if err := tx.GetContext(ctx, &transactions[i].Id, tx.Rebind(query), args...); err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/rs/zerolog"
+)
+
+// ErrPaycheckAlreadyPosted is returned when a template was already paid for the same kind and month.
+var ErrPaycheckAlreadyPosted = errors.New("this paycheck was already posted for the month")
+
+type PaycheckRepository interface {
+	CreateTemplate(ctx context.Context, template model.PaycheckTemplate) (int64, error)
+	GetTemplate(ctx context.Context, id, userId int64) (*model.PaycheckTemplate, error)
+	ListTemplates(ctx context.Context, userId int64) ([]model.PaycheckTemplate, error)
+	ListScheduledTemplates(ctx context.Context) ([]model.PaycheckTemplate, error)
+	UpdateTemplate(ctx context.Context, template model.PaycheckTemplate) error
+	DeleteTemplate(ctx context.Context, id, userId int64) error
+	CreatePaycheck(ctx context.Context, paycheck model.Paycheck, income *model.Transaction) (int64, error)
+	ListPaychecks(ctx context.Context, userId int64, year int) ([]model.Paycheck, error)
+}
+
+type pqPaycheckRepository struct {
+	db *sqlx.DB
+}
+
+func NewPaycheckRepository(db *sqlx.DB) PaycheckRepository {
+	return &pqPaycheckRepository{db: db}
+}
+
+// CreateTemplate stores a template and its lines in one database transaction.
+func (r *pqPaycheckRepository) CreateTemplate(ctx context.Context, template model.PaycheckTemplate) (int64, error) {
+	if err := denyWrites(ctx); err != nil {
+		return 0, err
+	}
+	var id int64
+	err := r.inTx(ctx, func(tx *sqlx.Tx) error {
+		err := tx.GetContext(ctx, &id, `
+			INSERT INTO paycheck_templates (user_id, name, account_id, income_category_id, pay_day, active)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`, template.UserId, template.Name, template.AccountId, template.IncomeCategoryId, template.PayDay, template.Active)
+		if err != nil {
+			return err
+		}
+		return insertPaycheckLines(ctx, tx, "paycheck_template_lines", "template_id", id, template.Lines)
+	})
+	return id, err
+}
+
+func (r *pqPaycheckRepository) GetTemplate(ctx context.Context, id, userId int64) (*model.PaycheckTemplate, error) {
+	var template model.PaycheckTemplate
+	query := `SELECT * FROM paycheck_templates WHERE id = $1 AND user_id = $2`
+	if err := r.db.GetContext(ctx, &template, query, id, userId); err != nil {
+		return nil, err
+	}
+	if !accountInScope(ctx, template.AccountId) {
+		return nil, sql.ErrNoRows
+	}
+	templates := []model.PaycheckTemplate{template}
+	if err := r.loadTemplateLines(ctx, templates); err != nil {
+		return nil, err
+	}
+	return &templates[0], nil
+}
+
+func (r *pqPaycheckRepository) ListTemplates(ctx context.Context, userId int64) ([]model.PaycheckTemplate, error) {
+	var templates []model.PaycheckTemplate
+	query := `SELECT * FROM paycheck_templates WHERE user_id = $1 ORDER BY name`
+	if err := r.db.SelectContext(ctx, &templates, query, userId); err != nil {
+		return nil, err
+	}
+	templates = slices.DeleteFunc(templates, func(t model.PaycheckTemplate) bool {
+		return !accountInScope(ctx, t.AccountId)
+	})
+	return templates, r.loadTemplateLines(ctx, templates)
+}
+
+// ListScheduledTemplates returns the active templates of every user that have
+// a pay day. It is meant for background jobs.
+func (r *pqPaycheckRepository) ListScheduledTemplates(ctx context.Context) ([]model.PaycheckTemplate, error) {
+	var templates []model.PaycheckTemplate
+	query := `SELECT * FROM paycheck_templates WHERE active AND pay_day IS NOT NULL ORDER BY id`
+	if err := r.db.SelectContext(ctx, &templates, query); err != nil {
+		return nil, err
+	}
+	return templates, r.loadTemplateLines(ctx, templates)
+}
+
+// UpdateTemplate replaces a template and all of its lines.
+func (r *pqPaycheckRepository) UpdateTemplate(ctx context.Context, template model.PaycheckTemplate) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
+	return r.inTx(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE paycheck_templates
+			SET name = $1, account_id = $2, income_category_id = $3, pay_day = $4, active = $5, updated_at = NOW()
+			WHERE id = $6 AND user_id = $7
+		`, template.Name, template.AccountId, template.IncomeCategoryId, template.PayDay, template.Active, template.Id, template.UserId)
+		if err != nil {
+			return err
+		}
+		rowsAffected, _ := result.RowsAffected()
+		if rowsAffected == 0 {
+			return sql.ErrNoRows
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM paycheck_template_lines WHERE template_id = $1`, template.Id); err != nil {
+			return err
+		}
+		return insertPaycheckLines(ctx, tx, "paycheck_template_lines", "template_id", template.Id, template.Lines)
+	})
+}
+
+// DeleteTemplate removes a template. Paychecks already posted are kept.
+func (r *pqPaycheckRepository) DeleteTemplate(ctx context.Context, id, userId int64) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
+	query := `DELETE FROM paycheck_templates WHERE id = $1 AND user_id = $2`
+	result, err := r.db.ExecContext(ctx, query, id, userId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CreatePaycheck posts the net income transaction and records the paycheck
+// with its lines, all in one database transaction, and sets the id of the
+// income. It returns ErrPaycheckAlreadyPosted when the template was already
+// paid for that kind and month.
+func (r *pqPaycheckRepository) CreatePaycheck(ctx context.Context, paycheck model.Paycheck, income *model.Transaction) (int64, error) {
+	if err := denyWrites(ctx); err != nil {
+		return 0, err
+	}
+	var id int64
+	err := r.inTx(ctx, func(tx *sqlx.Tx) error {
+		err := tx.GetContext(ctx, &id, `
+			INSERT INTO paychecks (user_id, template_id, account_id, kind, year, month, pay_date, gross, deductions, net)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			ON CONFLICT (template_id, year, month, kind) WHERE template_id IS NOT NULL DO NOTHING
+			RETURNING id
+		`, paycheck.UserId, paycheck.TemplateId, paycheck.AccountId, paycheck.Kind, paycheck.Year, paycheck.Month,
+			paycheck.PayDate, paycheck.Gross, paycheck.Deductions, paycheck.Net)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrPaycheckAlreadyPosted
+		}
+		if err != nil {
+			return err
+		}
+
+		query, args, err := sqlx.Named(insertTransactionQuery, *income)
+		if err != nil {
+			return err
+		}
+		if err := tx.GetContext(ctx, &income.Id, tx.Rebind(query), args...); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE paychecks SET transaction_id = $1 WHERE id = $2`, income.Id, id); err != nil {
+			return err
+		}
+		return insertPaycheckLines(ctx, tx, "paycheck_lines", "paycheck_id", id, paycheck.Lines)
+	})
+	return id, err
+}
+
+// ListPaychecks returns the paychecks paid in a year, with their lines, oldest first.
+func (r *pqPaycheckRepository) ListPaychecks(ctx context.Context, userId int64, year int) ([]model.Paycheck, error) {
+	var paychecks []model.Paycheck
+	query := `SELECT * FROM paychecks WHERE user_id = $1 AND year = $2 ORDER BY pay_date, id`
+	if err := r.db.SelectContext(ctx, &paychecks, query, userId, year); err != nil {
+		return nil, err
+	}
+	if scope := AccessScopeFromContext(ctx); scope != nil {
+		paychecks = slices.DeleteFunc(paychecks, func(p model.Paycheck) bool {
+			return !scope.AllowsAccount(p.AccountId) || !scope.AllowsDate(p.PayDate)
+		})
+	}
+	if len(paychecks) == 0 {
+		return paychecks, nil
+	}
+
+	ids := make(pq.Int64Array, len(paychecks))
+	for i, paycheck := range paychecks {
+		ids[i] = paycheck.Id
+	}
+	lines, err := r.selectLines(ctx, "paycheck_lines", "paycheck_id", ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range paychecks {
+		paychecks[i].Lines = lines[paychecks[i].Id]
+	}
+	return paychecks, nil
+}
+
+func (r *pqPaycheckRepository) loadTemplateLines(ctx context.Context, templates []model.PaycheckTemplate) error {
+	if len(templates) == 0 {
+		return nil
+	}
+	ids := make(pq.Int64Array, len(templates))
+	for i, template := range templates {
+		ids[i] = template.Id
+	}
+	lines, err := r.selectLines(ctx, "paycheck_template_lines", "template_id", ids)
+	if err != nil {
+		return err
+	}
+	for i := range templates {
+		templates[i].Lines = lines[templates[i].Id]
+	}
+	return nil
+}
+
+// selectLines loads the lines of several templates or paychecks, grouped by owner.
+func (r *pqPaycheckRepository) selectLines(ctx context.Context, table, ownerColumn string, ownerIds pq.Int64Array) (map[int64][]model.PaycheckLine, error) {
+	var rows []struct {
+		OwnerId int64 `db:"owner_id"`
+		model.PaycheckLine
+	}
+	// table and ownerColumn are constants chosen by this file, never user input.
+	query := `
+		SELECT ` + ownerColumn + ` AS owner_id, id, kind, description, category_id, amount
+		FROM ` + table + `
+		WHERE ` + ownerColumn + ` = ANY($1)
+		ORDER BY kind, id
+	`
+	if err := r.db.SelectContext(ctx, &rows, query, ownerIds); err != nil {
+		return nil, err
+	}
+	lines := map[int64][]model.PaycheckLine{}
+	for _, row := range rows {
+		lines[row.OwnerId] = append(lines[row.OwnerId], row.PaycheckLine)
+	}
+	return lines, nil
+}
+
+// insertPaycheckLines writes the lines of a template or a paycheck.
+func insertPaycheckLines(ctx context.Context, tx *sqlx.Tx, table, ownerColumn string, ownerId int64, lines []model.PaycheckLine) error {
+	query := `INSERT INTO ` + table + ` (` + ownerColumn + `, kind, description, category_id, amount) VALUES ($1, $2, $3, $4, $5)`
+	for _, line := range lines {
+		if _, err := tx.ExecContext(ctx, query, ownerId, line.Kind, line.Description, line.CategoryId, line.Amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inTx runs fn in a database transaction, committing when it succeeds.
+func (r *pqPaycheckRepository) inTx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error rolling back paycheck changes")
+		}
+	}()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/rs/zerolog"
+)
+
+// ErrInsufficientPoints is returned when a transfer needs more valid points than the account holds.
+var ErrInsufficientPoints = errors.New("not enough valid points in the source account")
+
+type PointsRepository interface {
+	CreateRule(ctx context.Context, rule model.PointsEarnRule) (int64, error)
+	GetRule(ctx context.Context, id, userId int64) (*model.PointsEarnRule, error)
+	ListRules(ctx context.Context, userId int64) ([]model.PointsEarnRule, error)
+	ListAllRules(ctx context.Context) ([]model.PointsEarnRule, error)
+	DeleteRule(ctx context.Context, id, userId int64) error
+	CreateLot(ctx context.Context, lot model.PointsLot) (int64, error)
+	UpsertStatementLot(ctx context.Context, lot model.PointsLot) (*model.PointsLot, error)
+	ListLots(ctx context.Context, userId, accountId int64) ([]model.PointsLot, error)
+	GetBalance(ctx context.Context, userId, accountId int64, asOf time.Time) (int64, error)
+	Transfer(ctx context.Context, transfer model.PointsTransfer, creditedLot model.PointsLot, asOf time.Time) (int64, error)
+	ListTransfers(ctx context.Context, userId int64) ([]model.PointsTransfer, error)
+}
+
+type pqPointsRepository struct {
+	db *sqlx.DB
+}
+
+func NewPointsRepository(db *sqlx.DB) PointsRepository {
+	return &pqPointsRepository{db: db}
+}
+
+func (r *pqPointsRepository) CreateRule(ctx context.Context, rule model.PointsEarnRule) (int64, error) {
+	if err := denyWrites(ctx); err != nil {
+		return 0, err
+	}
+	query := `
+		INSERT INTO points_earn_rules (user_id, card_account_id, points_account_id, points_per_unit, expiration_months)
+		VALUES (:user_id, :card_account_id, :points_account_id, :points_per_unit, :expiration_months)
+		RETURNING id
+	`
+	return r.insert(ctx, query, rule)
+}
+
+func (r *pqPointsRepository) GetRule(ctx context.Context, id, userId int64) (*model.PointsEarnRule, error) {
+	var rule model.PointsEarnRule
+	query := `SELECT * FROM points_earn_rules WHERE id = $1 AND user_id = $2`
+	err := r.db.GetContext(ctx, &rule, query, id, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !accountInScope(ctx, rule.CardAccountId) || !accountInScope(ctx, rule.PointsAccountId) {
+		return nil, sql.ErrNoRows
+	}
+	return &rule, nil
+}
+
+func (r *pqPointsRepository) ListRules(ctx context.Context, userId int64) ([]model.PointsEarnRule, error) {
+	var rules []model.PointsEarnRule
+	query := `SELECT * FROM points_earn_rules WHERE user_id = $1 ORDER BY id`
+	if err := r.db.SelectContext(ctx, &rules, query, userId); err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(rules, func(rule model.PointsEarnRule) bool {
+		return !accountInScope(ctx, rule.CardAccountId) || !accountInScope(ctx, rule.PointsAccountId)
+	}), nil
+}
+
+// ListAllRules returns the rules of every user. It is meant for background jobs.
+func (r *pqPointsRepository) ListAllRules(ctx context.Context) ([]model.PointsEarnRule, error) {
+	var rules []model.PointsEarnRule
+	query := `SELECT * FROM points_earn_rules ORDER BY id`
+	err := r.db.SelectContext(ctx, &rules, query)
+	return rules, err
+}
+
+// DeleteRule removes a rule. Lots it already credited are kept.
+func (r *pqPointsRepository) DeleteRule(ctx context.Context, id, userId int64) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
+	query := `DELETE FROM points_earn_rules WHERE id = $1 AND user_id = $2`
+	result, err := r.db.ExecContext(ctx, query, id, userId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *pqPointsRepository) CreateLot(ctx context.Context, lot model.PointsLot) (int64, error) {
+	if err := denyWrites(ctx); err != nil {
+		return 0, err
+	}
+	query := `
+		INSERT INTO points_lots (user_id, account_id, source, points, remaining, earned_at, expires_at, description)
+		VALUES (:user_id, :account_id, :source, :points, :points, :earned_at, :expires_at, :description)
+		RETURNING id
+	`
+	return r.insert(ctx, query, lot)
+}
+
+// UpsertStatementLot credits the points a rule earned in a statement. Accruing
+// the same statement again replaces the amount, keeping what was already spent.
+func (r *pqPointsRepository) UpsertStatementLot(ctx context.Context, lot model.PointsLot) (*model.PointsLot, error) {
+	if err := denyWrites(ctx); err != nil {
+		return nil, err
+	}
+	query := `
+		INSERT INTO points_lots (user_id, account_id, source, points, remaining, earned_at, expires_at, rule_id, statement_year, statement_month, description)
+		VALUES (:user_id, :account_id, :source, :points, :points, :earned_at, :expires_at, :rule_id, :statement_year, :statement_month, :description)
+		ON CONFLICT (rule_id, statement_year, statement_month) WHERE rule_id IS NOT NULL
+		DO UPDATE SET
+			remaining = GREATEST(points_lots.remaining + EXCLUDED.points - points_lots.points, 0),
+			points = EXCLUDED.points,
+			earned_at = EXCLUDED.earned_at,
+			expires_at = EXCLUDED.expires_at
+		RETURNING *
+	`
+	rows, err := r.db.NamedQueryContext(ctx, query, lot)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error closing rows")
+		}
+	}()
+
+	var stored model.PointsLot
+	if rows.Next() {
+		if err := rows.StructScan(&stored); err != nil {
+			return nil, err
+		}
+	}
+	return &stored, rows.Err()
+}
+
+func (r *pqPointsRepository) ListLots(ctx context.Context, userId, accountId int64) ([]model.PointsLot, error) {
+	var lots []model.PointsLot
+	if !accountInScope(ctx, accountId) {
+		return lots, nil
+	}
+	query := `
+		SELECT * FROM points_lots
+		WHERE user_id = $1 AND account_id = $2
+		ORDER BY expires_at NULLS LAST, earned_at, id
+	`
+	err := r.db.SelectContext(ctx, &lots, query, userId, accountId)
+	return lots, err
+}
+
+// GetBalance sums the points left in lots that have not expired by asOf.
+func (r *pqPointsRepository) GetBalance(ctx context.Context, userId, accountId int64, asOf time.Time) (int64, error) {
+	var balance int64
+	if !accountInScope(ctx, accountId) {
+		return 0, sql.ErrNoRows
+	}
+	query := `
+		SELECT COALESCE(SUM(remaining), 0) FROM points_lots
+		WHERE user_id = $1 AND account_id = $2
+		  AND (expires_at IS NULL OR expires_at >= $3::date)
+	`
+	err := r.db.GetContext(ctx, &balance, query, userId, accountId, asOf)
+	return balance, err
+}
+
+// Transfer debits the points from the source lots that expire first, credits
+// the destination lot and records the transfer, all in one database transaction.
+func (r *pqPointsRepository) Transfer(ctx context.Context, transfer model.PointsTransfer, creditedLot model.PointsLot, asOf time.Time) (int64, error) {
+	if err := denyWrites(ctx); err != nil {
+		return 0, err
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error rolling back points transfer")
+		}
+	}()
+
+	var lots []model.PointsLot
+	query := `
+		SELECT * FROM points_lots
+		WHERE user_id = $1 AND account_id = $2 AND remaining > 0
+		  AND (expires_at IS NULL OR expires_at >= $3::date)
+		ORDER BY expires_at NULLS LAST, earned_at, id
+		FOR UPDATE
+	`
+	if err := tx.SelectContext(ctx, &lots, query, transfer.UserId, transfer.FromAccountId, asOf); err != nil {
+		return 0, err
+	}
+
+	missing := transfer.Points
+	for _, lot := range lots {
+		if missing == 0 {
+			break
+		}
+		debit := min(lot.Remaining, missing)
+		if _, err := tx.ExecContext(ctx, `UPDATE points_lots SET remaining = remaining - $1 WHERE id = $2`, debit, lot.Id); err != nil {
+			return 0, err
+		}
+		missing -= debit
+	}
+	if missing > 0 {
+		return 0, ErrInsufficientPoints
+	}
+
+	if _, err := tx.NamedExecContext(ctx, `
+		INSERT INTO points_lots (user_id, account_id, source, points, remaining, earned_at, expires_at, description)
+		VALUES (:user_id, :account_id, :source, :points, :points, :earned_at, :expires_at, :description)
+	`, creditedLot); err != nil {
+		return 0, fmt.Errorf("failed to credit transferred points: %w", err)
+	}
+
+	var id int64
+	err = tx.GetContext(ctx, &id, `
+		INSERT INTO points_transfers (user_id, from_account_id, to_account_id, points, bonus_percent, credited_points)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, transfer.UserId, transfer.FromAccountId, transfer.ToAccountId, transfer.Points, transfer.BonusPercent, transfer.CreditedPoints)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (r *pqPointsRepository) ListTransfers(ctx context.Context, userId int64) ([]model.PointsTransfer, error) {
+	var transfers []model.PointsTransfer
+	query := `SELECT * FROM points_transfers WHERE user_id = $1 ORDER BY created_at DESC`
+	if err := r.db.SelectContext(ctx, &transfers, query, userId); err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(transfers, func(t model.PointsTransfer) bool {
+		return !accountInScope(ctx, t.FromAccountId) || !accountInScope(ctx, t.ToAccountId)
+	}), nil
+}
+
+// insert runs a named INSERT ... RETURNING id query.
+func (r *pqPointsRepository) insert(ctx context.Context, query string, arg any) (int64, error) {
+	rows, err := r.db.NamedQueryContext(ctx, query, arg)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error closing rows")
+		}
+	}()
+
+	var id int64
+	if rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/rs/zerolog"
+)
+
+type ProjectRepository interface {
+	Create(ctx context.Context, project model.Project) (int64, error)
+	GetById(ctx context.Context, id, userId int64) (*model.Project, error)
+	List(ctx context.Context, userId int64) ([]model.Project, error)
+	Update(ctx context.Context, project model.Project) error
+	Delete(ctx context.Context, id, userId int64) error
+	AssignTransactions(ctx context.Context, id, userId int64, transactionIds []int64) error
+	UnassignTransaction(ctx context.Context, id, userId, transactionId int64) error
+	ListTransactions(ctx context.Context, id, userId int64) ([]model.Transaction, error)
+}
+
+type pqProjectRepository struct {
+	db *sqlx.DB
+}
+
+func NewProjectRepository(db *sqlx.DB) ProjectRepository {
+	return &pqProjectRepository{db: db}
+}
+
+// Create stores a project and its category budgets in one database transaction.
+func (r *pqProjectRepository) Create(ctx context.Context, project model.Project) (int64, error) {
+	if err := denyWrites(ctx); err != nil {
+		return 0, err
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer rollbackProject(ctx, tx)
+
+	var id int64
+	err = tx.GetContext(ctx, &id, `
+		INSERT INTO projects (user_id, name, description, total_budget, start_date, end_date)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, project.UserId, project.Name, project.Description, project.TotalBudget, project.StartDate, project.EndDate)
+	if err != nil {
+		return 0, err
+	}
+	if err := insertProjectBudgets(ctx, tx, id, project.CategoryBudgets); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+func (r *pqProjectRepository) GetById(ctx context.Context, id, userId int64) (*model.Project, error) {
+	var project model.Project
+	query := `SELECT * FROM projects WHERE id = $1 AND user_id = $2`
+	if err := r.db.GetContext(ctx, &project, query, id, userId); err != nil {
+		return nil, err
+	}
+	projects := []model.Project{project}
+	if err := r.loadCategoryBudgets(ctx, projects); err != nil {
+		return nil, err
+	}
+	return &projects[0], nil
+}
+
+func (r *pqProjectRepository) List(ctx context.Context, userId int64) ([]model.Project, error) {
+	var projects []model.Project
+	query := `SELECT * FROM projects WHERE user_id = $1 ORDER BY start_date DESC, id`
+	if err := r.db.SelectContext(ctx, &projects, query, userId); err != nil {
+		return nil, err
+	}
+	return projects, r.loadCategoryBudgets(ctx, projects)
+}
+
+// Update replaces a project and all of its category budgets.
+func (r *pqProjectRepository) Update(ctx context.Context, project model.Project) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollbackProject(ctx, tx)
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE projects
+		SET name = $1, description = $2, total_budget = $3, start_date = $4, end_date = $5, updated_at = NOW()
+		WHERE id = $6 AND user_id = $7
+	`, project.Name, project.Description, project.TotalBudget, project.StartDate, project.EndDate, project.Id, project.UserId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM project_category_budgets WHERE project_id = $1`, project.Id); err != nil {
+		return err
+	}
+	if err := insertProjectBudgets(ctx, tx, project.Id, project.CategoryBudgets); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Delete removes a project. Its transactions are kept, no longer assigned.
+func (r *pqProjectRepository) Delete(ctx context.Context, id, userId int64) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
+	query := `DELETE FROM projects WHERE id = $1 AND user_id = $2`
+	result, err := r.db.ExecContext(ctx, query, id, userId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// AssignTransactions moves transactions into a project. Either all of them are
+// assigned or, when one does not belong to the user, none is and sql.ErrNoRows
+// is returned.
+func (r *pqProjectRepository) AssignTransactions(ctx context.Context, id, userId int64, transactionIds []int64) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollbackProject(ctx, tx)
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE transactions SET project_id = $1, updated_at = NOW()
+		WHERE user_id = $2 AND id = ANY($3)
+	`, id, userId, pq.Int64Array(transactionIds))
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected != int64(len(transactionIds)) {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+func (r *pqProjectRepository) UnassignTransaction(ctx context.Context, id, userId, transactionId int64) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
+	query := `UPDATE transactions SET project_id = NULL, updated_at = NOW() WHERE id = $1 AND user_id = $2 AND project_id = $3`
+	result, err := r.db.ExecContext(ctx, query, transactionId, userId, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ListTransactions returns the transactions assigned to a project, oldest first.
+func (r *pqProjectRepository) ListTransactions(ctx context.Context, id, userId int64) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+	query := `
+		SELECT
+			t.*,
+			a.name as account_name,
+			a.type as account_type,
+			c.name as category_name
+		FROM transactions t
+		JOIN accounts a ON t.account_id = a.id
+		LEFT JOIN categories c ON t.category_id = c.id
+		WHERE t.project_id = $1 AND t.user_id = $2
+		ORDER BY t.date, t.id
+	`
+	if err := r.db.SelectContext(ctx, &transactions, query, id, userId); err != nil {
+		return nil, err
+	}
+	if scope := AccessScopeFromContext(ctx); scope != nil {
+		transactions = slices.DeleteFunc(transactions, func(t model.Transaction) bool {
+			return !scope.AllowsTransaction(t)
+		})
+	}
+	return transactions, nil
+}
+
+func (r *pqProjectRepository) loadCategoryBudgets(ctx context.Context, projects []model.Project) error {
+	if len(projects) == 0 {
+		return nil
+	}
+	ids := make(pq.Int64Array, len(projects))
+	for i, project := range projects {
+		ids[i] = project.Id
+	}
+	var rows []struct {
+		ProjectId int64 `db:"project_id"`
+		model.ProjectCategoryBudget
+	}
+	query := `
+		SELECT b.project_id, b.category_id, b.amount, c.name as category_name
+		FROM project_category_budgets b
+		JOIN categories c ON b.category_id = c.id
+		WHERE b.project_id = ANY($1)
+		ORDER BY c.name
+	`
+	if err := r.db.SelectContext(ctx, &rows, query, ids); err != nil {
+		return err
+	}
+	budgets := map[int64][]model.ProjectCategoryBudget{}
+	for _, row := range rows {
+		budgets[row.ProjectId] = append(budgets[row.ProjectId], row.ProjectCategoryBudget)
+	}
+	for i := range projects {
+		projects[i].CategoryBudgets = budgets[projects[i].Id]
+		if projects[i].CategoryBudgets == nil {
+			projects[i].CategoryBudgets = []model.ProjectCategoryBudget{}
+		}
+	}
+	return nil
+}
+
+func insertProjectBudgets(ctx context.Context, tx *sqlx.Tx, projectId int64, budgets []model.ProjectCategoryBudget) error {
+	query := `INSERT INTO project_category_budgets (project_id, category_id, amount) VALUES ($1, $2, $3)`
+	for _, budget := range budgets {
+		if _, err := tx.ExecContext(ctx, query, projectId, budget.CategoryId, budget.Amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func rollbackProject(ctx context.Context, tx *sqlx.Tx) {
+	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("Error rolling back project changes")
+	}
+}
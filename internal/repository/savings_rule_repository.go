@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+type SavingsRuleRepository interface {
+	CreateRule(ctx context.Context, rule model.SavingsRule) (int64, error)
+	GetRule(ctx context.Context, id, userId int64) (*model.SavingsRule, error)
+	ListRules(ctx context.Context, userId int64) ([]model.SavingsRule, error)
+	DeleteRule(ctx context.Context, id, userId int64) error
+	// ListRulesToRun returns all of the user's rules, whatever the collaborator
+	// scope in the context, since rules act on behalf of the owner.
+	ListRulesToRun(ctx context.Context, userId int64) ([]model.SavingsRule, error)
+	// RecordRun logs what a rule did with a transaction, moving the amount
+	// right away when a transfer is given. It returns false, writing nothing,
+	// when the transaction already triggered the rule.
+	RecordRun(ctx context.Context, run model.SavingsRuleRun, transfer *model.Transaction) (bool, error)
+	ListRuns(ctx context.Context, userId, ruleId int64) ([]model.SavingsRuleRun, error)
+	// ListPendingRoundUps returns the round-ups of every user waiting to be
+	// settled from the days before the given one. Unsettled runs of other rules
+	// are not included. It is meant for background jobs.
+	ListPendingRoundUps(ctx context.Context, before time.Time) ([]model.PendingRoundUp, error)
+	// SettleRoundUps moves the given pending round-ups of a rule with one
+	// transfer, whose amount is their total. Runs already settled are skipped.
+	// It returns the amount moved.
+	SettleRoundUps(ctx context.Context, ruleId int64, runIds []int64, transfer model.Transaction) (decimal.Decimal, error)
+}
+
+type pqSavingsRuleRepository struct {
+	db *sqlx.DB
+}
+
+func NewSavingsRuleRepository(db *sqlx.DB) SavingsRuleRepository {
+	return &pqSavingsRuleRepository{db: db}
+}
+
+func (r *pqSavingsRuleRepository) CreateRule(ctx context.Context, rule model.SavingsRule) (int64, error) {
+	if err := denyWrites(ctx); err != nil {
+		return 0, err
+	}
+	query := `
+		INSERT INTO savings_rules (user_id, type, savings_account_id, account_id, funding_account_id, category_id, percent)
+		VALUES (:user_id, :type, :savings_account_id, :account_id, :funding_account_id, :category_id, :percent)
+		RETURNING id
+	`
+	query, args, err := sqlx.Named(query, rule)
+	if err != nil {
+		return 0, err
+	}
+	var id int64
+	err = r.db.GetContext(ctx, &id, r.db.Rebind(query), args...)
+	return id, err
+}
+
+func (r *pqSavingsRuleRepository) GetRule(ctx context.Context, id, userId int64) (*model.SavingsRule, error) {
+	var rule model.SavingsRule
+	query := `SELECT * FROM savings_rules WHERE id = $1 AND user_id = $2`
+	if err := r.db.GetContext(ctx, &rule, query, id, userId); err != nil {
+		return nil, err
+	}
+	if !savingsRuleInScope(ctx, rule) {
+		return nil, sql.ErrNoRows
+	}
+	return &rule, nil
+}
+
+func (r *pqSavingsRuleRepository) ListRules(ctx context.Context, userId int64) ([]model.SavingsRule, error) {
+	var rules []model.SavingsRule
+	query := `SELECT * FROM savings_rules WHERE user_id = $1 ORDER BY id`
+	if err := r.db.SelectContext(ctx, &rules, query, userId); err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(rules, func(rule model.SavingsRule) bool {
+		return !savingsRuleInScope(ctx, rule)
+	}), nil
+}
+
+func (r *pqSavingsRuleRepository) ListRulesToRun(ctx context.Context, userId int64) ([]model.SavingsRule, error) {
+	var rules []model.SavingsRule
+	err := r.db.SelectContext(ctx, &rules, `SELECT * FROM savings_rules WHERE user_id = $1 ORDER BY id`, userId)
+	return rules, err
+}
+
+// DeleteRule removes a rule and its log. Money it already moved stays in the
+// savings account, and round-ups not settled yet are dropped.
+func (r *pqSavingsRuleRepository) DeleteRule(ctx context.Context, id, userId int64) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx, `DELETE FROM savings_rules WHERE id = $1 AND user_id = $2`, id, userId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// RecordRun stores the run and its transfer in one database transaction.
+func (r *pqSavingsRuleRepository) RecordRun(ctx context.Context, run model.SavingsRuleRun, transfer *model.Transaction) (bool, error) {
+	if err := denyWrites(ctx); err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error rolling back savings rule run")
+		}
+	}()
+
+	var runId int64
+	err = tx.GetContext(ctx, &runId, `
+		INSERT INTO savings_rule_runs (rule_id, user_id, transaction_id, amount, occurred_on)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (rule_id, transaction_id) DO NOTHING
+		RETURNING id
+	`, run.RuleId, run.UserId, run.TransactionId, run.Amount, run.OccurredOn)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if transfer != nil {
+		query, args, err := sqlx.Named(insertTransactionQuery, *transfer)
+		if err != nil {
+			return false, err
+		}
+		var transferId int64
+		if err := tx.GetContext(ctx, &transferId, tx.Rebind(query), args...); err != nil {
+			return false, err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE savings_rule_runs SET transfer_id = $1, settled_at = NOW() WHERE id = $2
+		`, transferId, runId); err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *pqSavingsRuleRepository) ListRuns(ctx context.Context, userId, ruleId int64) ([]model.SavingsRuleRun, error) {
+	runs := []model.SavingsRuleRun{}
+	if _, err := r.GetRule(ctx, ruleId, userId); err != nil {
+		return runs, err
+	}
+	query := `
+		SELECT * FROM savings_rule_runs
+		WHERE user_id = $1 AND rule_id = $2
+		ORDER BY occurred_on DESC, id DESC
+	`
+	err := r.db.SelectContext(ctx, &runs, query, userId, ruleId)
+	return runs, err
+}
+
+func (r *pqSavingsRuleRepository) ListPendingRoundUps(ctx context.Context, before time.Time) ([]model.PendingRoundUp, error) {
+	var pending []model.PendingRoundUp
+	query := `
+		SELECT run.rule_id, run.user_id, run.occurred_on, SUM(run.amount) AS amount,
+			ARRAY_AGG(run.id ORDER BY run.id) AS run_ids
+		FROM savings_rule_runs run
+		JOIN savings_rules rule ON rule.id = run.rule_id
+		WHERE rule.type = 'round_up' AND run.settled_at IS NULL AND run.occurred_on < $1
+		GROUP BY run.rule_id, run.user_id, run.occurred_on
+		ORDER BY run.occurred_on, run.rule_id
+	`
+	err := r.db.SelectContext(ctx, &pending, query, before)
+	return pending, err
+}
+
+// SettleRoundUps marks the listed round-ups as settled before adding them up,
+// so the transfer never exceeds what the caller checked: a round-up recorded
+// meanwhile waits for the next settlement, and one settled meanwhile is not
+// moved twice.
+func (r *pqSavingsRuleRepository) SettleRoundUps(ctx context.Context, ruleId int64, runIds []int64, transfer model.Transaction) (decimal.Decimal, error) {
+	if err := denyWrites(ctx); err != nil {
+		return decimal.Zero, err
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error rolling back round-up settlement")
+		}
+	}()
+
+	var settled []struct {
+		Id     int64           `db:"id"`
+		Amount decimal.Decimal `db:"amount"`
+	}
+	err = tx.SelectContext(ctx, &settled, `
+		UPDATE savings_rule_runs SET settled_at = NOW()
+		WHERE rule_id = $1 AND id = ANY($2) AND settled_at IS NULL
+		RETURNING id, amount
+	`, ruleId, pq.Array(runIds))
+	if err != nil || len(settled) == 0 {
+		return decimal.Zero, err
+	}
+
+	ids := make([]int64, 0, len(settled))
+	transfer.Amount = decimal.Zero
+	for _, run := range settled {
+		ids = append(ids, run.Id)
+		transfer.Amount = transfer.Amount.Add(run.Amount)
+	}
+	query, args, err := sqlx.Named(insertTransactionQuery, transfer)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	var transferId int64
+	if err := tx.GetContext(ctx, &transferId, tx.Rebind(query), args...); err != nil {
+		return decimal.Zero, err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE savings_rule_runs SET transfer_id = $1 WHERE id = ANY($2)`, transferId, pq.Array(ids)); err != nil {
+		return decimal.Zero, err
+	}
+	return transfer.Amount, tx.Commit()
+}
+
+// savingsRuleInScope reports whether every account of a rule is visible to a
+// collaborator scope in the context.
+func savingsRuleInScope(ctx context.Context, rule model.SavingsRule) bool {
+	for _, accountId := range []*int64{&rule.SavingsAccountId, rule.AccountId, rule.FundingAccountId} {
+		if accountId != nil && !accountInScope(ctx, *accountId) {
+			return false
+		}
+	}
+	return true
+}
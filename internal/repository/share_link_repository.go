@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/rs/zerolog"
+)
+
+type ShareLinkRepository interface {
+	Create(ctx context.Context, link model.ShareLink) (int64, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*model.ShareLink, error)
+	ListByUserId(ctx context.Context, userId int64) ([]model.ShareLink, error)
+	Revoke(ctx context.Context, id, userId int64) error
+	RecordAccess(ctx context.Context, id int64) error
+}
+
+type pqShareLinkRepository struct {
+	db *sqlx.DB
+}
+
+func NewShareLinkRepository(db *sqlx.DB) ShareLinkRepository {
+	return &pqShareLinkRepository{db: db}
+}
+
+func (r *pqShareLinkRepository) Create(ctx context.Context, link model.ShareLink) (int64, error) {
+	query := `
+		INSERT INTO share_links (user_id, token_hash, scope, params, password_hash, expires_at)
+		VALUES (:user_id, :token_hash, :scope, :params, :password_hash, :expires_at)
+		RETURNING id
+	`
+	rows, err := r.db.NamedQueryContext(ctx, query, link)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error closing rows")
+		}
+	}()
+
+	var id int64
+	if rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+// GetByTokenHash returns the link regardless of its expiration or revocation;
+// the caller decides whether it can still be used.
+func (r *pqShareLinkRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*model.ShareLink, error) {
+	var link model.ShareLink
+	query := `SELECT * FROM share_links WHERE token_hash = $1`
+	err := r.db.GetContext(ctx, &link, query, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *pqShareLinkRepository) ListByUserId(ctx context.Context, userId int64) ([]model.ShareLink, error) {
+	var links []model.ShareLink
+	query := `SELECT * FROM share_links WHERE user_id = $1 ORDER BY created_at DESC`
+	err := r.db.SelectContext(ctx, &links, query, userId)
+	return links, err
+}
+
+// Revoke disables a link for good. Revoking an already revoked link is a no-op.
+func (r *pqShareLinkRepository) Revoke(ctx context.Context, id, userId int64) error {
+	query := `UPDATE share_links SET revoked_at = COALESCE(revoked_at, NOW()) WHERE id = $1 AND user_id = $2`
+	result, err := r.db.ExecContext(ctx, query, id, userId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// RecordAccess increments the access counter and stamps the access time.
+func (r *pqShareLinkRepository) RecordAccess(ctx context.Context, id int64) error {
+	query := `UPDATE share_links SET access_count = access_count + 1, last_accessed_at = NOW() WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/rs/zerolog"
+)
+
+type TelegramRepository interface {
+	CreateLinkCode(ctx context.Context, code model.TelegramLinkCode) (int64, error)
+	ConsumeLinkCode(ctx context.Context, codeHash string, chatId int64) (int64, error)
+	GetChat(ctx context.Context, chatId int64) (*model.TelegramChat, error)
+	ListChats(ctx context.Context, userId int64) ([]model.TelegramChat, error)
+	ListAllChats(ctx context.Context) ([]model.TelegramChat, error)
+	SetDefaultAccount(ctx context.Context, chatId int64, accountId *int64) error
+	DeleteChat(ctx context.Context, chatId, userId int64) error
+	RecordBudgetAlert(ctx context.Context, chatId, budgetId int64, threshold int) (bool, error)
+}
+
+type pqTelegramRepository struct {
+	db *sqlx.DB
+}
+
+func NewTelegramRepository(db *sqlx.DB) TelegramRepository {
+	return &pqTelegramRepository{db: db}
+}
+
+func (r *pqTelegramRepository) CreateLinkCode(ctx context.Context, code model.TelegramLinkCode) (int64, error) {
+	if err := denyWrites(ctx); err != nil {
+		return 0, err
+	}
+	var id int64
+	query := `
+		INSERT INTO telegram_link_codes (user_id, code_hash, expires_at)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+	err := r.db.GetContext(ctx, &id, query, code.UserId, code.CodeHash, code.ExpiresAt)
+	return id, err
+}
+
+// ConsumeLinkCode redeems a code and links the chat to the code's owner, in one
+// database transaction. A chat that was linked to someone else is moved over.
+// It returns sql.ErrNoRows when the code does not exist, has expired or was
+// already used.
+func (r *pqTelegramRepository) ConsumeLinkCode(ctx context.Context, codeHash string, chatId int64) (int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error rolling back telegram link")
+		}
+	}()
+
+	var userId int64
+	err = tx.GetContext(ctx, &userId, `
+		UPDATE telegram_link_codes
+		SET used_at = NOW()
+		WHERE code_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id
+	`, codeHash)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO telegram_chats (chat_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (chat_id) DO UPDATE SET user_id = EXCLUDED.user_id, default_account_id = NULL, linked_at = NOW()
+	`, chatId, userId)
+	if err != nil {
+		return 0, err
+	}
+	return userId, tx.Commit()
+}
+
+func (r *pqTelegramRepository) GetChat(ctx context.Context, chatId int64) (*model.TelegramChat, error) {
+	var chat model.TelegramChat
+	query := `SELECT * FROM telegram_chats WHERE chat_id = $1`
+	if err := r.db.GetContext(ctx, &chat, query, chatId); err != nil {
+		return nil, err
+	}
+	return &chat, nil
+}
+
+func (r *pqTelegramRepository) ListChats(ctx context.Context, userId int64) ([]model.TelegramChat, error) {
+	var chats []model.TelegramChat
+	query := `SELECT * FROM telegram_chats WHERE user_id = $1 ORDER BY linked_at`
+	err := r.db.SelectContext(ctx, &chats, query, userId)
+	return chats, err
+}
+
+// ListAllChats returns the chats of every user. It is meant for background jobs.
+func (r *pqTelegramRepository) ListAllChats(ctx context.Context) ([]model.TelegramChat, error) {
+	var chats []model.TelegramChat
+	query := `SELECT * FROM telegram_chats ORDER BY user_id, chat_id`
+	err := r.db.SelectContext(ctx, &chats, query)
+	return chats, err
+}
+
+func (r *pqTelegramRepository) SetDefaultAccount(ctx context.Context, chatId int64, accountId *int64) error {
+	query := `UPDATE telegram_chats SET default_account_id = $1 WHERE chat_id = $2`
+	result, err := r.db.ExecContext(ctx, query, accountId, chatId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *pqTelegramRepository) DeleteChat(ctx context.Context, chatId, userId int64) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
+	query := `DELETE FROM telegram_chats WHERE chat_id = $1 AND user_id = $2`
+	result, err := r.db.ExecContext(ctx, query, chatId, userId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// RecordBudgetAlert remembers that a budget threshold was announced to a chat.
+// It reports false when the alert had already been recorded.
+func (r *pqTelegramRepository) RecordBudgetAlert(ctx context.Context, chatId, budgetId int64, threshold int) (bool, error) {
+	query := `
+		INSERT INTO telegram_budget_alerts (chat_id, budget_id, threshold)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`
+	result, err := r.db.ExecContext(ctx, query, chatId, budgetId, threshold)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
@@ -18,13 +18,23 @@ import (
 // Usar uma interface aqui é uma boa prática para permitir testes e mocks.
 type TransactionRepository interface {
 	Create(ctx context.Context, tx model.Transaction) (int64, error)
+	CreateWithFee(ctx context.Context, purchase, fee model.Transaction) (int64, error)
 	GetById(ctx context.Context, id, userId int64) (*model.Transaction, error)
 	Update(ctx context.Context, tx model.Transaction) error
+	UpdateWithFee(ctx context.Context, purchase model.Transaction, fee *model.Transaction) error
 	Delete(ctx context.Context, id int64, userId int64) error
 	List(ctx context.Context, userId int64, filters ListTransactionFilters) ([]model.Transaction, error)
 	ListByAccountAndDateRange(ctx context.Context, userID, accountID int64, startDate, endDate time.Time) ([]model.Transaction, error)
 	DeleteByAccountId(ctx context.Context, userId, accountId int64) error
+	// DeleteByAccountIdKeepingCounterparts removes the account's transactions
+	// like DeleteByAccountId, but turns its transfers into income or expenses
+	// of the other accounts, so their balances do not change.
+	DeleteByAccountIdKeepingCounterparts(ctx context.Context, userId, accountId int64) error
+	CountByAccountId(ctx context.Context, userId, accountId int64) (int64, error)
+	// ListTransfersByAccountId returns the transfers from or to the account, oldest first.
+	ListTransfersByAccountId(ctx context.Context, userId, accountId int64) ([]model.Transaction, error)
 	SumExpensesByCategoryAndPeriod(ctx context.Context, userID, categoryID int64, startDate, endDate time.Time) (decimal.Decimal, error)
+	Aggregate(ctx context.Context, userId int64, query ReportQuery) ([]ReportRow, error)
 }
 
 // ListTransactionFilters holds all possible optional filters for listing transactions.
@@ -43,6 +53,18 @@ type pqTransactionRepository struct {
 	db *sqlx.DB
 }
 
+const insertTransactionQuery = `
+	INSERT INTO transactions (
+		user_id, description, amount, date, type, account_id, destination_account_id, category_id, card_id,
+		original_currency, original_amount, exchange_rate, spread_rate, fee_of_transaction_id
+	)
+	VALUES (
+		:user_id, :description, :amount, :date, :type, :account_id, :destination_account_id, :category_id, :card_id,
+		:original_currency, :original_amount, :exchange_rate, :spread_rate, :fee_of_transaction_id
+	)
+	RETURNING id
+`
+
 // NewTransactionRepository cria uma nova instância do repositório.
 func NewTransactionRepository(db *sqlx.DB) TransactionRepository {
 	return &pqTransactionRepository{db: db}
@@ -50,12 +72,10 @@ func NewTransactionRepository(db *sqlx.DB) TransactionRepository {
 
 // Create insere uma nova transação no banco de dados.
 func (r *pqTransactionRepository) Create(ctx context.Context, tx model.Transaction) (int64, error) {
-	query := `
-		INSERT INTO transactions (user_id, description, amount, date, type, account_id, destination_account_id, category_id)
-		VALUES (:user_id, :description, :amount, :date, :type, :account_id, :destination_account_id, :category_id)
-		RETURNING id
-	`
-	rows, err := r.db.NamedQueryContext(ctx, query, tx)
+	if err := denyWrites(ctx); err != nil {
+		return 0, err
+	}
+	rows, err := r.db.NamedQueryContext(ctx, insertTransactionQuery, tx)
 	if err != nil {
 		return 0, err
 	}
@@ -74,6 +94,42 @@ func (r *pqTransactionRepository) Create(ctx context.Context, tx model.Transacti
 	return id, nil
 }
 
+// CreateWithFee creates a purchase and the fee charged on it, linked to the
+// purchase, in a single database transaction.
+func (r *pqTransactionRepository) CreateWithFee(ctx context.Context, purchase, fee model.Transaction) (int64, error) {
+	if err := denyWrites(ctx); err != nil {
+		return 0, err
+	}
+	dbTx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := dbTx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error rolling back purchase with fee")
+		}
+	}()
+
+	insert := func(tx model.Transaction) (int64, error) {
+		query, args, err := sqlx.Named(insertTransactionQuery, tx)
+		if err != nil {
+			return 0, err
+		}
+		var id int64
+		err = dbTx.GetContext(ctx, &id, dbTx.Rebind(query), args...)
+		return id, err
+	}
+	id, err := insert(purchase)
+	if err != nil {
+		return 0, err
+	}
+	fee.FeeOfTransactionId = &id
+	if _, err := insert(fee); err != nil {
+		return 0, err
+	}
+	return id, dbTx.Commit()
+}
+
 // GetById busca uma transação por seu Id.
 func (r *pqTransactionRepository) GetById(ctx context.Context, id, userId int64) (*model.Transaction, error) {
 	var tx model.Transaction
@@ -81,6 +137,7 @@ func (r *pqTransactionRepository) GetById(ctx context.Context, id, userId int64)
 		SELECT 
 			t.*, 
 			a.name as account_name,
+			a.type as account_type,
 			c.name as category_name
 		FROM transactions t
 		JOIN accounts a ON t.account_id = a.id
@@ -88,12 +145,24 @@ func (r *pqTransactionRepository) GetById(ctx context.Context, id, userId int64)
 		WHERE t.id = $1 AND t.user_id = $2
 	`
 	err := r.db.GetContext(ctx, &tx, query, id, userId)
+	if scope := AccessScopeFromContext(ctx); err == nil && scope != nil && !scope.AllowsTransaction(tx) {
+		return &tx, sql.ErrNoRows
+	}
 	return &tx, err
 }
 
 // Update atualiza uma transação existente no banco de dados.
 func (r *pqTransactionRepository) Update(ctx context.Context, tx model.Transaction) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
+	// The fees of a purchase follow it, so they stay in the same statement.
 	query := `
+		WITH fees AS (
+			UPDATE transactions
+			SET date = :date, account_id = :account_id, card_id = :card_id, updated_at = NOW()
+			WHERE fee_of_transaction_id = :id AND user_id = :user_id
+		)
 		UPDATE transactions
 		SET
 			description = :description,
@@ -103,6 +172,7 @@ func (r *pqTransactionRepository) Update(ctx context.Context, tx model.Transacti
 			account_id = :account_id,
 			category_id = :category_id,
 			destination_account_id = :destination_account_id,
+			card_id = :card_id,
 			updated_at = NOW()
 		WHERE id = :id AND user_id = :user_id
 	`
@@ -122,8 +192,74 @@ func (r *pqTransactionRepository) Update(ctx context.Context, tx model.Transacti
 	return nil
 }
 
+// UpdateWithFee updates a purchase, with the conversion of a purchase abroad,
+// and replaces the fee charged on it in a single database transaction. A nil
+// fee removes the purchase's fee.
+func (r *pqTransactionRepository) UpdateWithFee(ctx context.Context, purchase model.Transaction, fee *model.Transaction) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
+	dbTx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := dbTx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error rolling back purchase with fee")
+		}
+	}()
+
+	result, err := dbTx.NamedExecContext(ctx, `
+		UPDATE transactions
+		SET
+			description = :description,
+			amount = :amount,
+			date = :date,
+			type = :type,
+			account_id = :account_id,
+			category_id = :category_id,
+			destination_account_id = :destination_account_id,
+			card_id = :card_id,
+			original_currency = :original_currency,
+			original_amount = :original_amount,
+			exchange_rate = :exchange_rate,
+			spread_rate = :spread_rate,
+			updated_at = NOW()
+		WHERE id = :id AND user_id = :user_id
+	`, purchase)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if _, err := dbTx.ExecContext(ctx, `DELETE FROM transactions WHERE fee_of_transaction_id = $1 AND user_id = $2`, purchase.Id, purchase.UserId); err != nil {
+		return err
+	}
+	if fee != nil {
+		fee.FeeOfTransactionId = &purchase.Id
+		query, args, err := sqlx.Named(insertTransactionQuery, *fee)
+		if err != nil {
+			return err
+		}
+		var id int64
+		if err := dbTx.GetContext(ctx, &id, dbTx.Rebind(query), args...); err != nil {
+			return err
+		}
+	}
+	return dbTx.Commit()
+}
+
 // Delete remove uma transação do banco de dados pelo seu Id.
 func (r *pqTransactionRepository) Delete(ctx context.Context, id int64, userId int64) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
 	query := `DELETE FROM transactions WHERE id = $1 AND user_id = $2`
 	result, err := r.db.ExecContext(ctx, query, id, userId)
 	if err != nil {
@@ -149,6 +285,7 @@ func (r *pqTransactionRepository) List(ctx context.Context, userId int64, filter
 	queryBuilder := psql.Select(
 		"t.*",
 		"a.name as account_name",
+		"a.type as account_type",
 		"c.name as category_name",
 	).
 		From("transactions t").
@@ -158,6 +295,23 @@ func (r *pqTransactionRepository) List(ctx context.Context, userId int64, filter
 		OrderBy("t.date DESC, t.created_at DESC")
 
 	// Apply optional filters dynamically
+	queryBuilder = applyTransactionFilters(queryBuilder, filters)
+	// Collaborators only see the accounts and period they were granted.
+	queryBuilder = applyTransactionScope(queryBuilder, AccessScopeFromContext(ctx))
+
+	// Generate the final SQL query and arguments
+	sql, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build list transaction query: %w", err)
+	}
+
+	var transactions []model.Transaction
+	err = r.db.SelectContext(ctx, &transactions, sql, args...)
+	return transactions, err
+}
+
+// applyTransactionFilters narrows a query over "transactions t" to the filters that were provided.
+func applyTransactionFilters(queryBuilder squirrel.SelectBuilder, filters ListTransactionFilters) squirrel.SelectBuilder {
 	if filters.Description != nil && *filters.Description != "" {
 		// Using ILIKE for case-insensitive search in PostgreSQL
 		queryBuilder = queryBuilder.Where(squirrel.ILike{"t.description": "%" + *filters.Description + "%"})
@@ -177,37 +331,38 @@ func (r *pqTransactionRepository) List(ctx context.Context, userId int64, filter
 	if len(filters.CategoryIds) > 0 {
 		queryBuilder = queryBuilder.Where(squirrel.Eq{"t.category_id": filters.CategoryIds}) // Handles IN (...) clause
 	}
-
-	// Generate the final SQL query and arguments
-	sql, args, err := queryBuilder.ToSql()
-	if err != nil {
-		return nil, fmt.Errorf("failed to build list transaction query: %w", err)
-	}
-
-	var transactions []model.Transaction
-	err = r.db.SelectContext(ctx, &transactions, sql, args...)
-	return transactions, err
+	return queryBuilder
 }
 
 // ListByAccountAndDateRange retrieves all transactions for a specific account within a date range.
 func (r *pqTransactionRepository) ListByAccountAndDateRange(ctx context.Context, userID, accountID int64, startDate, endDate time.Time) ([]model.Transaction, error) {
-	var transactions []model.Transaction
 	// This query is straightforward as the complex date calculation is done in the service.
-	query := `
-        SELECT * FROM transactions
-        WHERE user_id = $1
-          AND account_id = $2
-          AND date >= $3
-          AND date <= $4
-        ORDER BY date DESC
-    `
-	err := r.db.SelectContext(ctx, &transactions, query, userID, accountID, startDate, endDate)
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("t.*", "cd.holder_name as card_holder_name", "cd.last_four as card_last_four").
+		From("transactions t").
+		LeftJoin("cards cd ON t.card_id = cd.id").
+		Where(squirrel.Eq{"t.user_id": userID, "t.account_id": accountID}).
+		Where(squirrel.GtOrEq{"t.date": startDate}).
+		Where(squirrel.LtOrEq{"t.date": endDate}).
+		OrderBy("t.date DESC")
+	queryBuilder = applyTransactionScope(queryBuilder, AccessScopeFromContext(ctx))
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build statement transactions query: %w", err)
+	}
+
+	var transactions []model.Transaction
+	err = r.db.SelectContext(ctx, &transactions, query, args...)
 	return transactions, err
 }
 
 // DeleteByAccountId removes all transactions associated with a specific account and user.
 // This is used when deleting an account.
 func (r *pqTransactionRepository) DeleteByAccountId(ctx context.Context, userId, accountId int64) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
 	// The key change is the OR clause to check both source and destination Ids.
 	query := `
 		DELETE FROM transactions
@@ -217,23 +372,275 @@ func (r *pqTransactionRepository) DeleteByAccountId(ctx context.Context, userId,
 	return err
 }
 
+// DeleteByAccountIdKeepingCounterparts converts the transfers and deletes the
+// remaining transactions in a single database transaction.
+func (r *pqTransactionRepository) DeleteByAccountIdKeepingCounterparts(ctx context.Context, userId, accountId int64) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
+	dbTx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := dbTx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error rolling back account transactions deletion")
+		}
+	}()
+
+	// Money sent from the account becomes income of the destination, and money
+	// received by it becomes an expense of the source.
+	convert := `
+		UPDATE transactions SET
+			type = CASE WHEN account_id = $2 THEN 'income' ELSE 'expense' END,
+			account_id = CASE WHEN account_id = $2 THEN destination_account_id ELSE account_id END,
+			destination_account_id = NULL,
+			updated_at = NOW()
+		WHERE user_id = $1 AND type = 'transfer' AND (account_id = $2 OR destination_account_id = $2)
+	`
+	if _, err := dbTx.ExecContext(ctx, convert, userId, accountId); err != nil {
+		return err
+	}
+	if _, err := dbTx.ExecContext(ctx, `DELETE FROM transactions WHERE user_id = $1 AND account_id = $2`, userId, accountId); err != nil {
+		return err
+	}
+	return dbTx.Commit()
+}
+
+// CountByAccountId counts the transactions from or to an account.
+func (r *pqTransactionRepository) CountByAccountId(ctx context.Context, userId, accountId int64) (int64, error) {
+	var count int64
+	query := `
+		SELECT COUNT(*) FROM transactions
+		WHERE user_id = $1 AND (account_id = $2 OR destination_account_id = $2)
+	`
+	err := r.db.GetContext(ctx, &count, query, userId, accountId)
+	return count, err
+}
+
+func (r *pqTransactionRepository) ListTransfersByAccountId(ctx context.Context, userId, accountId int64) ([]model.Transaction, error) {
+	transfers := []model.Transaction{}
+	query := `
+		SELECT * FROM transactions
+		WHERE user_id = $1 AND type = 'transfer' AND (account_id = $2 OR destination_account_id = $2)
+		ORDER BY date, id
+	`
+	err := r.db.SelectContext(ctx, &transfers, query, userId, accountId)
+	return transfers, err
+}
+
 // SumExpensesByCategoryAndPeriod calculates the total amount of expenses for a given
 // category within a specific date range for a user.
 func (r *pqTransactionRepository) SumExpensesByCategoryAndPeriod(ctx context.Context, userID, categoryID int64, startDate, endDate time.Time) (decimal.Decimal, error) {
 	var totalExpenses decimal.Decimal
-	query := `
-        SELECT COALESCE(SUM(amount), 0) 
-        FROM transactions
-        WHERE user_id = $1
-          AND category_id = $2
-          AND type = 'expense'
-          AND date >= $3 AND date < $4
-    `
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("COALESCE(SUM(t.amount), 0)").
+		From("transactions t").
+		Where(squirrel.Eq{"t.user_id": userID, "t.category_id": categoryID, "t.type": model.Expense}).
+		Where(squirrel.GtOrEq{"t.date": startDate}).
+		Where(squirrel.Lt{"t.date": endDate})
+	queryBuilder = applyTransactionScope(queryBuilder, AccessScopeFromContext(ctx))
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to build expenses sum query: %w", err)
+	}
+
 	// We use GetContext because we expect a single row (the sum) in return.
-	err := r.db.GetContext(ctx, &totalExpenses, query, userID, categoryID, startDate, endDate)
+	err = r.db.GetContext(ctx, &totalExpenses, query, args...)
 	// sql.ErrNoRows is not a problem here; it just means the sum is zero, which COALESCE handles.
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return decimal.Zero, err
 	}
 	return totalExpenses, nil
 }
+
+// ReportDimension is something transactions can be grouped by in a report query.
+type ReportDimension string
+
+const (
+	DimensionCategory ReportDimension = "category"
+	DimensionAccount  ReportDimension = "account"
+	DimensionType     ReportDimension = "type"
+	DimensionDay      ReportDimension = "day"
+	DimensionWeek     ReportDimension = "week"
+	DimensionMonth    ReportDimension = "month"
+	DimensionYear     ReportDimension = "year"
+	DimensionWeekday  ReportDimension = "weekday"
+)
+
+// reportDimensionColumn is how a dimension is grouped: by its key, or by the
+// id of the row the key names, so rows with the same name stay apart.
+type reportDimensionColumn struct {
+	key string
+	id  string
+}
+
+// reportDimensionColumns is the whitelist of what a report query can group by.
+// Every key is a text expression, so rows sort the same way they read: days
+// are "2025-01-31", weeks "2025-W05" (ISO), months "2025-01" and weekdays
+// "1" (Monday) to "7" (Sunday).
+var reportDimensionColumns = map[ReportDimension]reportDimensionColumn{
+	DimensionCategory: {key: "c.name", id: "t.category_id"},
+	DimensionAccount:  {key: "a.name", id: "t.account_id"},
+	DimensionType:     {key: "t.type"},
+	DimensionDay:      {key: "to_char(t.date, 'YYYY-MM-DD')"},
+	DimensionWeek:     {key: `to_char(t.date, 'IYYY-"W"IW')`},
+	DimensionMonth:    {key: "to_char(t.date, 'YYYY-MM')"},
+	DimensionYear:     {key: "to_char(t.date, 'YYYY')"},
+	DimensionWeekday:  {key: "to_char(t.date, 'ID')"},
+}
+
+// Valid reports whether the dimension is in the whitelist.
+func (d ReportDimension) Valid() bool {
+	_, ok := reportDimensionColumns[d]
+	return ok
+}
+
+// ReportMeasure is how the amounts of each group are summarized.
+type ReportMeasure string
+
+const (
+	MeasureSum   ReportMeasure = "sum"
+	MeasureCount ReportMeasure = "count"
+	MeasureAvg   ReportMeasure = "avg"
+	MeasureMin   ReportMeasure = "min"
+	MeasureMax   ReportMeasure = "max"
+)
+
+// reportMeasureColumns is the whitelist of what a report query can compute.
+var reportMeasureColumns = map[ReportMeasure]string{
+	MeasureSum:   "COALESCE(SUM(t.amount), 0)",
+	MeasureCount: "COUNT(*)",
+	MeasureAvg:   "COALESCE(ROUND(AVG(t.amount), 2), 0)",
+	MeasureMin:   "COALESCE(MIN(t.amount), 0)",
+	MeasureMax:   "COALESCE(MAX(t.amount), 0)",
+}
+
+// Valid reports whether the measure is in the whitelist.
+func (m ReportMeasure) Valid() bool {
+	_, ok := reportMeasureColumns[m]
+	return ok
+}
+
+// ReportFilters extends the transaction list filters with what only report
+// queries need.
+type ReportFilters struct {
+	ListTransactionFilters
+	AccountIds []int64
+	Types      []model.TransactionType
+	MinAmount  *decimal.Decimal
+	MaxAmount  *decimal.Decimal
+}
+
+// ReportQuery groups the filtered transactions by GroupBy and computes the
+// Measures of each group. At most Limit rows are returned.
+type ReportQuery struct {
+	Filters  ReportFilters
+	GroupBy  []ReportDimension
+	Measures []ReportMeasure
+	Limit    int
+}
+
+// ReportRow is one group of a report query: its key for each dimension, nil
+// when the transactions have no value for it, and the value of each measure.
+// Ids holds the id of the category or account a key names, and nil for the
+// other dimensions.
+type ReportRow struct {
+	Keys   []*string
+	Ids    []*int64
+	Values []decimal.Decimal
+}
+
+// Aggregate runs a report query. Dimensions and measures are only ever
+// compiled from the whitelists, never from the query itself.
+func (r *pqTransactionRepository) Aggregate(ctx context.Context, userId int64, query ReportQuery) ([]ReportRow, error) {
+	// Each dimension selects its key and the id of what it names, if any, and
+	// is grouped by both, sorted by the key first.
+	var columns, groupBy []string
+	for _, dimension := range query.GroupBy {
+		column, ok := reportDimensionColumns[dimension]
+		if !ok {
+			return nil, fmt.Errorf("unknown report dimension %q", dimension)
+		}
+		if column.id == "" {
+			columns = append(columns, column.key, "NULL::bigint")
+			groupBy = append(groupBy, fmt.Sprint(len(columns)-1))
+			continue
+		}
+		columns = append(columns, column.key, column.id)
+		groupBy = append(groupBy, fmt.Sprint(len(columns)-1), fmt.Sprint(len(columns)))
+	}
+	for _, measure := range query.Measures {
+		column, ok := reportMeasureColumns[measure]
+		if !ok {
+			return nil, fmt.Errorf("unknown report measure %q", measure)
+		}
+		columns = append(columns, column)
+	}
+
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select(columns...).
+		From("transactions t").
+		Join("accounts a ON t.account_id = a.id").
+		LeftJoin("categories c ON t.category_id = c.id").
+		Where(squirrel.Eq{"t.user_id": userId})
+	if len(groupBy) > 0 {
+		queryBuilder = queryBuilder.GroupBy(groupBy...).OrderBy(groupBy...)
+	}
+	if query.Limit > 0 {
+		queryBuilder = queryBuilder.Limit(uint64(query.Limit))
+	}
+
+	filters := query.Filters
+	queryBuilder = applyTransactionFilters(queryBuilder, filters.ListTransactionFilters)
+	if len(filters.AccountIds) > 0 {
+		queryBuilder = queryBuilder.Where(squirrel.Eq{"t.account_id": filters.AccountIds})
+	}
+	if len(filters.Types) > 0 {
+		queryBuilder = queryBuilder.Where(squirrel.Eq{"t.type": filters.Types})
+	}
+	if filters.MinAmount != nil {
+		queryBuilder = queryBuilder.Where(squirrel.GtOrEq{"t.amount": *filters.MinAmount})
+	}
+	if filters.MaxAmount != nil {
+		queryBuilder = queryBuilder.Where(squirrel.LtOrEq{"t.amount": *filters.MaxAmount})
+	}
+	queryBuilder = applyTransactionScope(queryBuilder, AccessScopeFromContext(ctx))
+
+	sql, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build report query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error closing rows")
+		}
+	}()
+
+	var result []ReportRow
+	for rows.Next() {
+		row := ReportRow{
+			Keys:   make([]*string, len(query.GroupBy)),
+			Ids:    make([]*int64, len(query.GroupBy)),
+			Values: make([]decimal.Decimal, len(query.Measures)),
+		}
+		dest := make([]any, 0, len(columns))
+		for i := range row.Keys {
+			dest = append(dest, &row.Keys[i], &row.Ids[i])
+		}
+		for i := range row.Values {
+			dest = append(dest, &row.Values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
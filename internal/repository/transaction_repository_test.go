@@ -157,6 +157,44 @@ func TestTransactionRepository(t *testing.T) {
 		_, err = txRepo.GetById(ctx, tx3_Id, userId)
 		require.NoError(err)
 	})
+
+	t.Run("should keep the transfers of the other accounts when deleting by account Id", func(t *testing.T) {
+		ctx, require, userRepo, accountRepo, txRepo := setupTestTransaction(t, testDB)
+
+		// Arrange
+		userId, _ := userRepo.Create(ctx, model.User{Name: "Counterpart User", Email: "counterpart@test.com", PasswordHash: "hash"})
+		accountA_Id, _ := accountRepo.Create(ctx, model.Account{UserId: userId, Name: "Account A", Type: "checking"})
+		accountB_Id, _ := accountRepo.Create(ctx, model.Account{UserId: userId, Name: "Account B", Type: "checking"})
+		accountC_Id, _ := accountRepo.Create(ctx, model.Account{UserId: userId, Name: "Account C", Type: "checking"})
+		tx1_Id, _ := txRepo.Create(ctx, model.Transaction{UserId: userId, AccountId: accountA_Id, Description: "Expense from A", Amount: decimal.NewFromInt(10), Type: "expense", Date: time.Now()})
+		tx2_Id, _ := txRepo.Create(ctx, model.Transaction{UserId: userId, AccountId: accountB_Id, DestinationAccountId: &accountA_Id, Description: "Transfer to A", Amount: decimal.NewFromInt(20), Type: "transfer", Date: time.Now()})
+		tx3_Id, _ := txRepo.Create(ctx, model.Transaction{UserId: userId, AccountId: accountA_Id, DestinationAccountId: &accountC_Id, Description: "Transfer from A", Amount: decimal.NewFromInt(30), Type: "transfer", Date: time.Now()})
+
+		count, err := txRepo.CountByAccountId(ctx, userId, accountA_Id)
+		require.NoError(err)
+		require.Equal(int64(3), count)
+		transfers, err := txRepo.ListTransfersByAccountId(ctx, userId, accountA_Id)
+		require.NoError(err)
+		require.Len(transfers, 2)
+
+		// Act
+		err = txRepo.DeleteByAccountIdKeepingCounterparts(ctx, userId, accountA_Id)
+		require.NoError(err)
+
+		// Assert: only A's own expense is gone; the transfers stay with B and C.
+		_, err = txRepo.GetById(ctx, tx1_Id, userId)
+		require.ErrorIs(err, sql.ErrNoRows)
+		sent, err := txRepo.GetById(ctx, tx2_Id, userId)
+		require.NoError(err)
+		require.Equal(model.Expense, sent.Type)
+		require.Equal(accountB_Id, sent.AccountId)
+		require.Nil(sent.DestinationAccountId)
+		received, err := txRepo.GetById(ctx, tx3_Id, userId)
+		require.NoError(err)
+		require.Equal(model.Income, received.Type)
+		require.Equal(accountC_Id, received.AccountId)
+		require.Nil(received.DestinationAccountId)
+	})
 }
 
 // TestTransactionRepositoryListWithFilters tests the dynamic filtering logic.
@@ -243,3 +281,208 @@ func TestTransactionRepositoryListWithFilters(t *testing.T) {
 		})
 	}
 }
+
+func TestTransactionRepositoryAggregate(t *testing.T) {
+	// ARRANGE
+	ctx, require, userRepo, accountRepo, txRepo := setupTestTransaction(t, testDB)
+
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Aggregate User", Email: "aggregate@test.com", PasswordHash: "hash"})
+	accountId, _ := accountRepo.Create(ctx, model.Account{UserId: userId, Name: "Bank A", Type: model.Checking})
+	catFoodId, _ := NewCategoryRepository(testDB).Create(ctx, model.Category{UserId: userId, Name: "Food"})
+
+	_, _ = txRepo.Create(ctx, model.Transaction{UserId: userId, AccountId: accountId, CategoryId: &catFoodId, Description: "Groceries", Amount: decimal.NewFromInt(150), Type: model.Expense, Date: time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)})
+	_, _ = txRepo.Create(ctx, model.Transaction{UserId: userId, AccountId: accountId, CategoryId: &catFoodId, Description: "Dinner", Amount: decimal.NewFromInt(80), Type: model.Expense, Date: time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)})
+	_, _ = txRepo.Create(ctx, model.Transaction{UserId: userId, AccountId: accountId, Description: "Bus fare", Amount: decimal.NewFromInt(5), Type: model.Expense, Date: time.Date(2025, 6, 16, 12, 0, 0, 0, time.UTC)})
+	_, _ = txRepo.Create(ctx, model.Transaction{UserId: userId, AccountId: accountId, Description: "Salary", Amount: decimal.NewFromInt(5000), Type: model.Income, Date: time.Date(2025, 6, 5, 12, 0, 0, 0, time.UTC)})
+
+	t.Run("should group by month and category, sorted by key", func(t *testing.T) {
+		// ACT
+		rows, err := txRepo.Aggregate(ctx, userId, ReportQuery{
+			Filters:  ReportFilters{Types: []model.TransactionType{model.Expense}},
+			GroupBy:  []ReportDimension{DimensionMonth, DimensionCategory},
+			Measures: []ReportMeasure{MeasureSum, MeasureCount},
+		})
+
+		// ASSERT
+		require.NoError(err)
+		require.Len(rows, 3)
+		require.Equal("2025-05", *rows[0].Keys[0])
+		require.Equal("Food", *rows[0].Keys[1])
+		require.Nil(rows[0].Ids[0])
+		require.Equal(catFoodId, *rows[0].Ids[1])
+		require.True(rows[0].Values[0].Equal(decimal.NewFromInt(150)))
+		require.True(rows[0].Values[1].Equal(decimal.NewFromInt(1)))
+		require.Equal("2025-06", *rows[2].Keys[0])
+		require.Nil(rows[2].Keys[1], "transactions without a category have no key")
+		require.Nil(rows[2].Ids[1])
+	})
+
+	t.Run("should total everything when not grouped", func(t *testing.T) {
+		// ACT
+		rows, err := txRepo.Aggregate(ctx, userId, ReportQuery{
+			Filters:  ReportFilters{MinAmount: testhelper.Ptr(decimal.NewFromInt(50))},
+			Measures: []ReportMeasure{MeasureMax, MeasureAvg},
+		})
+
+		// ASSERT
+		require.NoError(err)
+		require.Len(rows, 1)
+		require.True(rows[0].Values[0].Equal(decimal.NewFromInt(5000)))
+		require.True(rows[0].Values[1].Equal(decimal.RequireFromString("1743.33")))
+	})
+
+	t.Run("should reject a dimension outside the whitelist", func(t *testing.T) {
+		// ACT
+		_, err := txRepo.Aggregate(ctx, userId, ReportQuery{
+			GroupBy:  []ReportDimension{"description; DROP TABLE transactions"},
+			Measures: []ReportMeasure{MeasureSum},
+		})
+
+		// ASSERT
+		require.Error(err)
+	})
+}
+
+func TestTransactionRepositoryCards(t *testing.T) {
+	testhelper.TruncateTables(t, testDB)
+	ctx, require, userRepo, accountRepo, txRepo := setupTestTransaction(t, testDB)
+	cardRepo := NewCardRepository(testDB)
+
+	// ARRANGE
+	userId, err := userRepo.Create(ctx, model.User{Name: "Card Holder", Email: "cards@test.com", PasswordHash: "hash"})
+	require.NoError(err)
+	otherUserId, err := userRepo.Create(ctx, model.User{Name: "Other User", Email: "other-cards@test.com", PasswordHash: "hash"})
+	require.NoError(err)
+	accountId, err := accountRepo.Create(ctx, model.Account{
+		UserId: userId, Name: "Card", Type: model.CreditCard, StatementClosingDay: testhelper.Ptr(10), PaymentDueDay: testhelper.Ptr(18),
+	})
+	require.NoError(err)
+	cardId, err := cardRepo.Create(ctx, model.Card{
+		UserId: userId, AccountId: accountId, HolderName: "Ana", LastFour: "9876", Virtual: true, SpendLimit: testhelper.Ptr(decimal.NewFromInt(500)),
+	})
+	require.NoError(err)
+	date := time.Date(2025, time.June, 5, 12, 0, 0, 0, time.UTC)
+	_, err = txRepo.Create(ctx, model.Transaction{UserId: userId, AccountId: accountId, CardId: &cardId, Description: "Dinner", Amount: decimal.NewFromInt(80), Type: model.Expense, Date: date})
+	require.NoError(err)
+
+	t.Run("should list the card of each statement transaction", func(t *testing.T) {
+		// ACT
+		transactions, err := txRepo.ListByAccountAndDateRange(ctx, userId, accountId, date.AddDate(0, 0, -1), date.AddDate(0, 0, 1))
+
+		// ASSERT
+		require.NoError(err)
+		require.Len(transactions, 1)
+		require.Equal(cardId, *transactions[0].CardId)
+		require.Equal("Ana", *transactions[0].CardHolderName)
+		require.Equal("9876", *transactions[0].CardLastFour)
+	})
+
+	t.Run("security: user A cannot see or change user B's cards", func(t *testing.T) {
+		_, err := cardRepo.GetById(ctx, cardId, otherUserId)
+		require.ErrorIs(err, sql.ErrNoRows)
+
+		cards, err := cardRepo.ListByAccountId(ctx, accountId, otherUserId)
+		require.NoError(err)
+		require.Empty(cards)
+
+		err = cardRepo.Delete(ctx, cardId, otherUserId)
+		require.ErrorIs(err, sql.ErrNoRows)
+	})
+
+	t.Run("should keep the transactions of a deleted card", func(t *testing.T) {
+		// ACT
+		require.NoError(cardRepo.Delete(ctx, cardId, userId))
+
+		// ASSERT
+		transactions, err := txRepo.ListByAccountAndDateRange(ctx, userId, accountId, date.AddDate(0, 0, -1), date.AddDate(0, 0, 1))
+		require.NoError(err)
+		require.Len(transactions, 1)
+		require.Nil(transactions[0].CardId)
+	})
+}
+
+func TestTransactionRepositoryCreateWithFee(t *testing.T) {
+	testhelper.TruncateTables(t, testDB)
+	ctx, require, userRepo, accountRepo, txRepo := setupTestTransaction(t, testDB)
+
+	// ARRANGE
+	userId, err := userRepo.Create(ctx, model.User{Name: "Traveller", Email: "traveller@test.com", PasswordHash: "hash"})
+	require.NoError(err)
+	cardId, err := accountRepo.Create(ctx, model.Account{
+		UserId: userId, Name: "Card", Type: model.CreditCard, StatementClosingDay: testhelper.Ptr(10), PaymentDueDay: testhelper.Ptr(18),
+	})
+	require.NoError(err)
+	otherCardId, err := accountRepo.Create(ctx, model.Account{
+		UserId: userId, Name: "Other Card", Type: model.CreditCard, StatementClosingDay: testhelper.Ptr(10), PaymentDueDay: testhelper.Ptr(18),
+	})
+	require.NoError(err)
+	date := time.Date(2025, time.June, 5, 12, 0, 0, 0, time.UTC)
+	purchase := model.Transaction{
+		UserId: userId, AccountId: cardId, Description: "Hotel", Amount: decimal.NewFromInt(520), Type: model.Expense, Date: date,
+		OriginalCurrency: testhelper.Ptr("USD"), OriginalAmount: testhelper.Ptr(decimal.NewFromInt(100)),
+		ExchangeRate: testhelper.Ptr(decimal.NewFromInt(5)), SpreadRate: testhelper.Ptr(decimal.NewFromInt(4)),
+	}
+	fee := model.Transaction{UserId: userId, AccountId: cardId, Description: "IOF: Hotel", Amount: decimal.RequireFromString("18.20"), Type: model.Expense, Date: date}
+	listFees := func(accountId int64) []model.Transaction {
+		transactions, err := txRepo.ListByAccountAndDateRange(ctx, userId, accountId, date.AddDate(0, -1, 0), date.AddDate(0, 1, 0))
+		require.NoError(err)
+		var fees []model.Transaction
+		for _, tx := range transactions {
+			if tx.FeeOfTransactionId != nil {
+				fees = append(fees, tx)
+			}
+		}
+		return fees
+	}
+
+	// ACT
+	purchaseId, err := txRepo.CreateWithFee(ctx, purchase, fee)
+
+	// ASSERT
+	require.NoError(err)
+	saved, err := txRepo.GetById(ctx, purchaseId, userId)
+	require.NoError(err)
+	require.Equal("USD", *saved.OriginalCurrency)
+	require.True(decimal.NewFromInt(5).Equal(*saved.ExchangeRate))
+	fees := listFees(cardId)
+	require.Len(fees, 1)
+	require.Equal(purchaseId, *fees[0].FeeOfTransactionId)
+
+	t.Run("should move the fee with its purchase", func(t *testing.T) {
+		saved.AccountId = otherCardId
+		saved.Date = date.AddDate(0, 0, 10)
+		require.NoError(txRepo.Update(ctx, *saved))
+
+		require.Empty(listFees(cardId))
+		moved := listFees(otherCardId)
+		require.Len(moved, 1)
+		require.True(saved.Date.Equal(moved[0].Date))
+	})
+
+	t.Run("should replace the fee of an edited purchase", func(t *testing.T) {
+		edited := *saved
+		edited.OriginalAmount = testhelper.Ptr(decimal.NewFromInt(200))
+		edited.Amount = decimal.NewFromInt(1040)
+		newFee := fee
+		newFee.AccountId, newFee.Date, newFee.Amount = otherCardId, edited.Date, decimal.RequireFromString("36.40")
+		require.NoError(txRepo.UpdateWithFee(ctx, edited, &newFee))
+
+		updated, err := txRepo.GetById(ctx, purchaseId, userId)
+		require.NoError(err)
+		require.True(decimal.NewFromInt(200).Equal(*updated.OriginalAmount))
+		replaced := listFees(otherCardId)
+		require.Len(replaced, 1)
+		require.True(newFee.Amount.Equal(replaced[0].Amount))
+
+		require.NoError(txRepo.UpdateWithFee(ctx, edited, nil))
+		require.Empty(listFees(otherCardId))
+	})
+
+	t.Run("should delete the fee with its purchase", func(t *testing.T) {
+		require.NoError(txRepo.UpdateWithFee(ctx, *saved, &fee))
+		require.Len(listFees(cardId), 1)
+		require.NoError(txRepo.Delete(ctx, purchaseId, userId))
+
+		require.Empty(listFees(cardId))
+	})
+}
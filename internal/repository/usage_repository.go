@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+)
+
+// UsageRepository reads instance-level statistics about users and the database.
+type UsageRepository interface {
+	GetUserUsage(ctx context.Context, userId int64) (*model.UserUsage, error)
+	GetMigrationStatus(ctx context.Context) (*model.MigrationStatus, error)
+	CountAccounts(ctx context.Context, userId int64) (int64, error)
+	CountTransactionsCreatedSince(ctx context.Context, userId int64, since time.Time) (int64, error)
+	EstimateStorageBytes(ctx context.Context, userId int64) (int64, error)
+}
+
+type pqUsageRepository struct {
+	db *sqlx.DB
+}
+
+func NewUsageRepository(db *sqlx.DB) UsageRepository {
+	return &pqUsageRepository{db: db}
+}
+
+// storageBytesExpression sums the size of every row owned by u.id.
+const storageBytesExpression = `
+	(SELECT COALESCE(SUM(pg_column_size(a.*)), 0) FROM accounts a WHERE a.user_id = u.id) +
+	(SELECT COALESCE(SUM(pg_column_size(t.*)), 0) FROM transactions t WHERE t.user_id = u.id) +
+	(SELECT COALESCE(SUM(pg_column_size(c.*)), 0) FROM categories c WHERE c.user_id = u.id) +
+	(SELECT COALESCE(SUM(pg_column_size(b.*)), 0) FROM budgets b WHERE b.user_id = u.id)`
+
+// GetUserUsage counts the user's rows. Storage is estimated with pg_column_size,
+// which reports the on-disk size of each row without indexes.
+func (r *pqUsageRepository) GetUserUsage(ctx context.Context, userId int64) (*model.UserUsage, error) {
+	var usage model.UserUsage
+	query := `
+		SELECT
+			u.id AS user_id,
+			(SELECT COUNT(*) FROM accounts WHERE user_id = u.id) AS accounts,
+			(SELECT COUNT(*) FROM transactions WHERE user_id = u.id) AS transactions,
+			(SELECT COUNT(*) FROM categories WHERE user_id = u.id) AS categories,
+			(SELECT COUNT(*) FROM budgets WHERE user_id = u.id) AS budgets,
+			(` + storageBytesExpression + `) AS storage_bytes
+		FROM users u
+		WHERE u.id = $1
+	`
+	err := r.db.GetContext(ctx, &usage, query, userId)
+	return &usage, err
+}
+
+// GetMigrationStatus reads the version recorded by golang-migrate.
+func (r *pqUsageRepository) GetMigrationStatus(ctx context.Context) (*model.MigrationStatus, error) {
+	var status model.MigrationStatus
+	query := `SELECT version, dirty FROM schema_migrations LIMIT 1`
+	err := r.db.GetContext(ctx, &status, query)
+	return &status, err
+}
+
+// CountAccounts returns how many accounts the user has.
+func (r *pqUsageRepository) CountAccounts(ctx context.Context, userId int64) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM accounts WHERE user_id = $1`
+	err := r.db.GetContext(ctx, &count, query, userId)
+	return count, err
+}
+
+// CountTransactionsCreatedSince returns how many transactions the user has
+// created from the given instant on, regardless of the transaction date.
+func (r *pqUsageRepository) CountTransactionsCreatedSince(ctx context.Context, userId int64, since time.Time) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM transactions WHERE user_id = $1 AND created_at >= $2`
+	err := r.db.GetContext(ctx, &count, query, userId, since)
+	return count, err
+}
+
+// EstimateStorageBytes returns the same storage estimate reported by GetUserUsage.
+func (r *pqUsageRepository) EstimateStorageBytes(ctx context.Context, userId int64) (int64, error) {
+	var bytes int64
+	query := `SELECT (` + storageBytesExpression + `) FROM users u WHERE u.id = $1`
+	err := r.db.GetContext(ctx, &bytes, query, userId)
+	return bytes, err
+}
@@ -2,8 +2,14 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 
+	"github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
 	"github.com/rs/zerolog"
 )
@@ -12,6 +18,23 @@ type UserRepository interface {
 	Create(ctx context.Context, user model.User) (int64, error)
 	GetByEmail(ctx context.Context, email string) (*model.User, error)
 	GetById(ctx context.Context, id int64) (*model.User, error)
+	List(ctx context.Context, filters ListUserFilters) ([]model.User, error)
+	SetDisabled(ctx context.Context, id int64, disabled bool) error
+	UpdatePassword(ctx context.Context, id int64, passwordHash string, resetRequired bool) error
+	// SetDisabledAudited and UpdatePasswordAudited make the same changes as
+	// SetDisabled and UpdatePassword and write the admin audit entry in the
+	// same database transaction, so neither is stored without the other.
+	SetDisabledAudited(ctx context.Context, id int64, disabled bool, entry model.AuditLogEntry) error
+	UpdatePasswordAudited(ctx context.Context, id int64, passwordHash string, resetRequired bool, entry model.AuditLogEntry) error
+	PromoteToAdmin(ctx context.Context, emails []string) (int64, error)
+	UpdateFiscalMonth(ctx context.Context, id int64, startDay int, label model.FiscalMonthLabel) error
+}
+
+// ListUserFilters holds the optional filters for listing users.
+type ListUserFilters struct {
+	Search *string // Matches name or e-mail, case-insensitive
+	Limit  uint64
+	Offset uint64
 }
 
 type pqUserRepository struct {
@@ -42,16 +65,22 @@ func (r *pqUserRepository) Create(ctx context.Context, user model.User) (int64,
 	return id, nil
 }
 
+// GetByEmail compares e-mails case-insensitively. Should two users differ only
+// in case, the exact match wins.
 func (r *pqUserRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
-	query := `SELECT * FROM users WHERE email = $1`
+	query := `SELECT * FROM users WHERE LOWER(email) = LOWER($1) ORDER BY email = $1 DESC, id LIMIT 1`
 	err := r.db.GetContext(ctx, &user, query, email)
 	return &user, err
 }
 
 func (r *pqUserRepository) GetById(ctx context.Context, id int64) (*model.User, error) {
 	var user model.User
-	query := `SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`
+	query := `
+		SELECT id, name, email, is_admin, disabled_at, password_reset_required,
+			fiscal_month_start_day, fiscal_month_label, created_at, updated_at
+		FROM users WHERE id = $1
+	`
 
 	// Usamos o db.Get do sqlx que é perfeito para buscar um único registro
 	err := r.db.GetContext(ctx, &user, query, id)
@@ -60,3 +89,138 @@ func (r *pqUserRepository) GetById(ctx context.Context, id int64) (*model.User,
 	// que será tratado pela camada de serviço/handler para retornar um 404 Not Found.
 	return &user, err
 }
+
+// List returns users ordered by Id, optionally filtered by a search term.
+// The password hash is never selected.
+func (r *pqUserRepository) List(ctx context.Context, filters ListUserFilters) ([]model.User, error) {
+	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+	queryBuilder := psql.Select("id", "name", "email", "is_admin", "disabled_at", "password_reset_required", "fiscal_month_start_day", "fiscal_month_label", "created_at", "updated_at").
+		From("users").
+		OrderBy("id")
+
+	if filters.Search != nil && *filters.Search != "" {
+		term := "%" + *filters.Search + "%"
+		queryBuilder = queryBuilder.Where(squirrel.Or{
+			squirrel.ILike{"name": term},
+			squirrel.ILike{"email": term},
+		})
+	}
+	if filters.Limit > 0 {
+		queryBuilder = queryBuilder.Limit(filters.Limit)
+	}
+	if filters.Offset > 0 {
+		queryBuilder = queryBuilder.Offset(filters.Offset)
+	}
+
+	sql, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build list users query: %w", err)
+	}
+
+	var users []model.User
+	err = r.db.SelectContext(ctx, &users, sql, args...)
+	return users, err
+}
+
+const setDisabledQuery = `
+	UPDATE users
+	SET disabled_at = CASE WHEN $2 THEN COALESCE(disabled_at, NOW()) ELSE NULL END,
+		updated_at = NOW()
+	WHERE id = $1
+`
+
+const updatePasswordQuery = `
+	UPDATE users
+	SET password_hash = $2, password_reset_required = $3, updated_at = NOW()
+	WHERE id = $1
+`
+
+// SetDisabled disables (or re-enables) a user.
+func (r *pqUserRepository) SetDisabled(ctx context.Context, id int64, disabled bool) error {
+	return updateUser(ctx, r.db, setDisabledQuery, id, disabled)
+}
+
+// UpdatePassword replaces the password hash and sets whether the user must change it again.
+func (r *pqUserRepository) UpdatePassword(ctx context.Context, id int64, passwordHash string, resetRequired bool) error {
+	return updateUser(ctx, r.db, updatePasswordQuery, id, passwordHash, resetRequired)
+}
+
+func (r *pqUserRepository) SetDisabledAudited(ctx context.Context, id int64, disabled bool, entry model.AuditLogEntry) error {
+	return r.withAuditEntry(ctx, entry, func(tx *sqlx.Tx) error {
+		return updateUser(ctx, tx, setDisabledQuery, id, disabled)
+	})
+}
+
+func (r *pqUserRepository) UpdatePasswordAudited(ctx context.Context, id int64, passwordHash string, resetRequired bool, entry model.AuditLogEntry) error {
+	return r.withAuditEntry(ctx, entry, func(tx *sqlx.Tx) error {
+		return updateUser(ctx, tx, updatePasswordQuery, id, passwordHash, resetRequired)
+	})
+}
+
+// withAuditEntry runs the change and writes the audit entry in one database transaction.
+func (r *pqUserRepository) withAuditEntry(ctx context.Context, entry model.AuditLogEntry, change func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error rolling back audited user change")
+		}
+	}()
+
+	if err := change(tx); err != nil {
+		return err
+	}
+	if _, err := insertAuditLogEntry(ctx, tx, entry); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return tx.Commit()
+}
+
+// updateUser runs an UPDATE of one user, returning sql.ErrNoRows when the user does not exist.
+func updateUser(ctx context.Context, db sqlx.ExecerContext, query string, args ...any) error {
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// PromoteToAdmin grants the admin flag to the users with the given e-mails,
+// compared case-insensitively, and returns how many users were changed.
+func (r *pqUserRepository) PromoteToAdmin(ctx context.Context, emails []string) (int64, error) {
+	query := `UPDATE users SET is_admin = TRUE, updated_at = NOW() WHERE LOWER(email) = ANY($1) AND NOT is_admin`
+	normalized := make([]string, 0, len(emails))
+	for _, email := range emails {
+		normalized = append(normalized, strings.ToLower(strings.TrimSpace(email)))
+	}
+	result, err := r.db.ExecContext(ctx, query, pq.Array(normalized))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// UpdateFiscalMonth sets the day the user's months start on and how they are named.
+func (r *pqUserRepository) UpdateFiscalMonth(ctx context.Context, id int64, startDay int, label model.FiscalMonthLabel) error {
+	query := `
+		UPDATE users
+		SET fiscal_month_start_day = $2, fiscal_month_label = $3, updated_at = NOW()
+		WHERE id = $1
+	`
+	result, err := r.db.ExecContext(ctx, query, id, startDay, label)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -63,6 +63,21 @@ func TestUserRepository(t *testing.T) {
 		require.Contains(err.Error(), "violates unique constraint")
 	})
 
+	t.Run("get by email should ignore the case of the e-mail", func(t *testing.T) {
+		ctx, require, userRepo, _, _ := setupTestUser(t, testDB)
+
+		// Arrange
+		id, err := userRepo.Create(ctx, model.User{Name: "John Mixed", Email: "John.Mixed@Example.com", PasswordHash: "hash"})
+		require.NoError(err)
+
+		// Act
+		foundUser, err := userRepo.GetByEmail(ctx, "john.mixed@example.com")
+
+		// Assert
+		require.NoError(err)
+		require.Equal(id, foundUser.Id)
+	})
+
 	t.Run("get by email should return error for non-existent user", func(t *testing.T) {
 		ctx, require, userRepo, _, _ := setupTestUser(t, testDB)
 
@@ -73,4 +88,55 @@ func TestUserRepository(t *testing.T) {
 		require.Error(err)
 		require.ErrorIs(err, sql.ErrNoRows)
 	})
+	t.Run("should disable, search and promote users", func(t *testing.T) {
+		ctx, require, userRepo, _, _ := setupTestUser(t, testDB)
+
+		// Arrange
+		id, err := userRepo.Create(ctx, model.User{Name: "Operator", Email: "operator@example.com", PasswordHash: "hash"})
+		require.NoError(err)
+
+		// Act
+		require.NoError(userRepo.SetDisabled(ctx, id, true))
+		promoted, err := userRepo.PromoteToAdmin(ctx, []string{"OPERATOR@example.com"})
+		require.NoError(err)
+		search := "operat"
+		users, err := userRepo.List(ctx, ListUserFilters{Search: &search, Limit: 10})
+		require.NoError(err)
+
+		// Assert
+		require.Equal(int64(1), promoted)
+		require.Len(users, 1)
+		require.True(users[0].IsAdmin)
+		require.NotNil(users[0].DisabledAt)
+		require.ErrorIs(userRepo.SetDisabled(ctx, id+1000, true), sql.ErrNoRows)
+	})
+
+	t.Run("should keep an audited change only when its audit entry is written", func(t *testing.T) {
+		ctx, require, userRepo, _, _ := setupTestUser(t, testDB)
+
+		// Arrange
+		adminId, err := userRepo.Create(ctx, model.User{Name: "Admin", Email: "audit.admin@example.com", PasswordHash: "hash"})
+		require.NoError(err)
+		id, err := userRepo.Create(ctx, model.User{Name: "Audited", Email: "audited@example.com", PasswordHash: "hash"})
+		require.NoError(err)
+		missingAdminId := adminId + 1000
+
+		// Act: the entry of an admin that does not exist cannot be written.
+		errFailed := userRepo.SetDisabledAudited(ctx, id, true, model.AuditLogEntry{AdminId: &missingAdminId, Action: "disable_user", TargetUserId: &id})
+		userAfterFailure, err := userRepo.GetById(ctx, id)
+		require.NoError(err)
+		errWritten := userRepo.SetDisabledAudited(ctx, id, true, model.AuditLogEntry{AdminId: &adminId, Action: "disable_user", TargetUserId: &id})
+		userAfterSuccess, err := userRepo.GetById(ctx, id)
+		require.NoError(err)
+		entries, err := NewAuditLogRepository(testDB).List(ctx, 10, 0)
+		require.NoError(err)
+
+		// Assert
+		require.ErrorContains(errFailed, "failed to write audit log")
+		require.Nil(userAfterFailure.DisabledAt)
+		require.NoError(errWritten)
+		require.NotNil(userAfterSuccess.DisabledAt)
+		require.Len(entries, 1)
+		require.Equal(id, *entries[0].TargetUserId)
+	})
 }
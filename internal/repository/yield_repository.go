@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+type YieldRepository interface {
+	ListYieldAccounts(ctx context.Context) ([]model.Account, error)
+	// LastAccrualEnd returns the end of the latest period posted to the
+	// account, or nil when none was.
+	LastAccrualEnd(ctx context.Context, accountId int64) (*time.Time, error)
+	DailyBalances(ctx context.Context, account model.Account, start, end time.Time) ([]decimal.Decimal, error)
+	// RecordAccrual stores the period's yield with its income and, when the IR
+	// is withheld, the tax linked to the income.
+	RecordAccrual(ctx context.Context, accrual model.YieldAccrual, income *model.Transaction, incomeTax *model.Transaction) (bool, error)
+	ListAccruals(ctx context.Context, userId, accountId int64) ([]model.YieldAccrual, error)
+
+	// SaveIndexRates stores the rates, replacing the ones of the same index and
+	// day, and writes the admin's audit entry in the same database transaction.
+	SaveIndexRates(ctx context.Context, rates []model.IndexRate, entry model.AuditLogEntry) error
+	// ListIndexRates returns the rates of an index from start until the day before end, oldest first.
+	ListIndexRates(ctx context.Context, name model.IndexName, start, end time.Time) ([]model.IndexRate, error)
+	// LatestIndexDate returns the last day the index has a rate for, or nil when it has none.
+	LatestIndexDate(ctx context.Context, name model.IndexName) (*time.Time, error)
+}
+
+type pqYieldRepository struct {
+	db *sqlx.DB
+}
+
+func NewYieldRepository(db *sqlx.DB) YieldRepository {
+	return &pqYieldRepository{db: db}
+}
+
+// ListYieldAccounts returns the savings accounts of every user that earn
+// yield. It is meant for background jobs.
+func (r *pqYieldRepository) ListYieldAccounts(ctx context.Context) ([]model.Account, error) {
+	var accounts []model.Account
+	query := `
+		SELECT * FROM accounts
+		WHERE type = 'savings' AND yield_type IS NOT NULL
+		ORDER BY id
+	`
+	err := r.db.SelectContext(ctx, &accounts, query)
+	return accounts, err
+}
+
+func (r *pqYieldRepository) LastAccrualEnd(ctx context.Context, accountId int64) (*time.Time, error) {
+	var end *time.Time
+	err := r.db.GetContext(ctx, &end, `SELECT MAX(period_end) FROM yield_accruals WHERE account_id = $1`, accountId)
+	return end, err
+}
+
+// DailyBalances returns the balance of the account at the end of each day from
+// start until the day before end.
+func (r *pqYieldRepository) DailyBalances(ctx context.Context, account model.Account, start, end time.Time) ([]decimal.Decimal, error) {
+	return selectDailyBalances(ctx, r.db, account, start, end)
+}
+
+// RecordAccrual stores the period's yield and its transactions in one database
+// transaction. It returns false, writing nothing, when the period was already posted.
+func (r *pqYieldRepository) RecordAccrual(ctx context.Context, accrual model.YieldAccrual, income *model.Transaction, incomeTax *model.Transaction) (bool, error) {
+	if err := denyWrites(ctx); err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error rolling back yield accrual")
+		}
+	}()
+
+	var id int64
+	err = tx.GetContext(ctx, &id, `
+		INSERT INTO yield_accruals (user_id, account_id, period_start, period_end, gross_amount, income_tax_amount)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (account_id, period_start) DO NOTHING
+		RETURNING id
+	`, accrual.UserId, accrual.AccountId, accrual.PeriodStart, accrual.PeriodEnd, accrual.GrossAmount, accrual.IncomeTaxAmount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	insert := func(transaction model.Transaction) (int64, error) {
+		query, args, err := sqlx.Named(insertTransactionQuery, transaction)
+		if err != nil {
+			return 0, err
+		}
+		var id int64
+		err = tx.GetContext(ctx, &id, tx.Rebind(query), args...)
+		return id, err
+	}
+	if income != nil {
+		incomeId, err := insert(*income)
+		if err != nil {
+			return false, err
+		}
+		if incomeTax != nil {
+			incomeTax.FeeOfTransactionId = &incomeId
+			if _, err := insert(*incomeTax); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *pqYieldRepository) ListAccruals(ctx context.Context, userId, accountId int64) ([]model.YieldAccrual, error) {
+	var accruals []model.YieldAccrual
+	if !accountInScope(ctx, accountId) {
+		return accruals, nil
+	}
+	query := `
+		SELECT * FROM yield_accruals
+		WHERE user_id = $1 AND account_id = $2
+		ORDER BY period_start DESC
+	`
+	err := r.db.SelectContext(ctx, &accruals, query, userId, accountId)
+	return accruals, err
+}
+
+func (r *pqYieldRepository) SaveIndexRates(ctx context.Context, rates []model.IndexRate, entry model.AuditLogEntry) error {
+	if err := denyWrites(ctx); err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error rolling back index rates")
+		}
+	}()
+
+	for _, rate := range rates {
+		if _, err := tx.NamedExecContext(ctx, `
+			INSERT INTO index_rates (name, date, annual_rate)
+			VALUES (:name, :date, :annual_rate)
+			ON CONFLICT (name, date) DO UPDATE SET annual_rate = EXCLUDED.annual_rate
+		`, rate); err != nil {
+			return err
+		}
+	}
+	if _, err := insertAuditLogEntry(ctx, tx, entry); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *pqYieldRepository) ListIndexRates(ctx context.Context, name model.IndexName, start, end time.Time) ([]model.IndexRate, error) {
+	rates := []model.IndexRate{}
+	query := `
+		SELECT name, date, annual_rate FROM index_rates
+		WHERE name = $1 AND date >= $2 AND date < $3
+		ORDER BY date
+	`
+	err := r.db.SelectContext(ctx, &rates, query, name, start, end)
+	return rates, err
+}
+
+func (r *pqYieldRepository) LatestIndexDate(ctx context.Context, name model.IndexName) (*time.Time, error) {
+	var date *time.Time
+	err := r.db.GetContext(ctx, &date, `SELECT MAX(date) FROM index_rates WHERE name = $1`, name)
+	return date, err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -12,9 +13,14 @@ import (
 	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/middleware"
 	customvalidator "github.com/matheusmazzoni/gofinance-tracker-api/internal/api/validator"
 	"github.com/matheusmazzoni/gofinance-tracker-api/internal/config"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/jobs"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/mailer"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/ratelimit"
 	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
 	"github.com/matheusmazzoni/gofinance-tracker-api/internal/service"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/telegram"
 	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -26,6 +32,10 @@ type Server struct {
 	db         *sqlx.DB
 	router     *gin.Engine
 	httpServer *http.Server
+	scheduler  *jobs.Scheduler
+	stopJobs   context.CancelFunc
+	// telegramPoller is set when the Telegram bot fetches updates through long polling.
+	telegramPoller *telegram.Poller
 }
 
 // NewServer creates and configures a new instance of the API server.
@@ -35,9 +45,10 @@ func NewServer(cfg config.Config, db *sqlx.DB, logger *zerolog.Logger) *Server {
 	router := gin.New()
 
 	server := &Server{
-		config: cfg,
-		db:     db,
-		router: router,
+		config:    cfg,
+		db:        db,
+		router:    router,
+		scheduler: jobs.NewScheduler(*logger),
 	}
 
 	server.setupRouter(logger)
@@ -51,8 +62,15 @@ func NewServer(cfg config.Config, db *sqlx.DB, logger *zerolog.Logger) *Server {
 	return server
 }
 
-// Start runs the HTTP server. This call is blocking.
+// Start runs the background jobs and the HTTP server. This call is blocking.
 func (s *Server) Start() error {
+	jobsCtx, cancel := context.WithCancel(context.Background())
+	s.stopJobs = cancel
+	s.scheduler.Start(jobsCtx)
+	if s.telegramPoller != nil {
+		go s.telegramPoller.Run(jobsCtx)
+	}
+
 	// ListenAndServe blocks until an error occurs or the server is shut down.
 	// We check for ErrServerClosed to know if it was a graceful shutdown.
 	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -63,6 +81,9 @@ func (s *Server) Start() error {
 
 // Shutdown gracefully shuts down the server with a timeout.
 func (s *Server) Shutdown(ctx context.Context) error {
+	if s.stopJobs != nil {
+		s.stopJobs()
+	}
 	return s.httpServer.Shutdown(ctx)
 }
 
@@ -75,22 +96,131 @@ func (s *Server) setupRouter(logger *zerolog.Logger) {
 	categoryRepo := repository.NewCategoryRepository(s.db)
 	transactionRepo := repository.NewTransactionRepository(s.db)
 	budgetRepo := repository.NewBudgetRepository(s.db)
+	magicLinkRepo := repository.NewMagicLinkRepository(s.db)
+	auditLogRepo := repository.NewAuditLogRepository(s.db)
+	usageRepo := repository.NewUsageRepository(s.db)
+	shareLinkRepo := repository.NewShareLinkRepository(s.db)
+	collaboratorRepo := repository.NewCollaboratorRepository(s.db)
+	pointsRepo := repository.NewPointsRepository(s.db)
+	benefitRepo := repository.NewBenefitRepository(s.db)
+	assetRepo := repository.NewAssetRepository(s.db)
+	paycheckRepo := repository.NewPaycheckRepository(s.db)
+	projectRepo := repository.NewProjectRepository(s.db)
+	telegramRepo := repository.NewTelegramRepository(s.db)
+	cardRepo := repository.NewCardRepository(s.db)
+	overdraftRepo := repository.NewOverdraftRepository(s.db)
+	yieldRepo := repository.NewYieldRepository(s.db)
+	savingsRuleRepo := repository.NewSavingsRuleRepository(s.db)
+	// Without the feature flag, reports keep adding up the raw transactions.
+	var monthlyTotalsRepo repository.MonthlyTotalsRepository
+	if s.config.ReportAggregatesEnabled {
+		monthlyTotalsRepo = repository.NewMonthlyTotalsRepository(s.db)
+	}
+
+	// Jobs
+	s.scheduler.Register(jobs.NewMagicLinkCleanupJob(magicLinkRepo), time.Hour)
 
 	// Serviços
 	authService := service.NewAuthService(userRepo, s.config.JWTSecretKey)
 	userService := service.NewUserService(userRepo, categoryRepo)
-	accountService := service.NewAccountService(accountRepo, transactionRepo)
+	quotaService := service.NewQuotaService(usageRepo, service.QuotaLimits{
+		MaxAccounts:             s.config.QuotaMaxAccounts,
+		MaxTransactionsPerMonth: s.config.QuotaMaxTransactionsPerMonth,
+		MaxStorageBytes:         s.config.QuotaMaxStorageBytes,
+		MaxRequestsPerDay:       s.config.QuotaMaxRequestsPerDay,
+	})
+	accountService := service.NewAccountService(accountRepo, transactionRepo, quotaService)
 	categoryService := service.NewCategoryService(categoryRepo, transactionRepo)
-	transactionService := service.NewTransactionService(transactionRepo, accountRepo)
-	budgetService := service.NewBudgetService(budgetRepo, categoryRepo, transactionRepo)
+	cardService := service.NewCardService(cardRepo, accountRepo, transactionRepo, accountService)
+	transactionService := service.NewTransactionService(transactionRepo, accountRepo, quotaService, cardService, service.TransactionOptions{
+		IOFRate: decimal.NewFromFloat(s.config.IOFForeignPurchaseRate),
+	})
+	budgetService := service.NewBudgetService(budgetRepo, categoryRepo, transactionRepo, userRepo, monthlyTotalsRepo)
+	reportService := service.NewReportService(transactionRepo, userRepo, monthlyTotalsRepo)
+	pointsService := service.NewPointsService(pointsRepo, accountRepo, accountService)
+	assetService := service.NewAssetService(assetRepo, accountRepo)
+	netWorthService := service.NewNetWorthService(accountService, pointsService, assetService)
+	benefitService := service.NewBenefitService(benefitRepo, accountRepo, quotaService, transactionService)
+	overdraftService := service.NewOverdraftService(overdraftRepo, quotaService, transactionService)
+	yieldService := service.NewYieldService(yieldRepo, accountRepo)
+	savingsRuleService := service.NewSavingsRuleService(savingsRuleRepo, accountRepo, categoryRepo, quotaService)
+	transactionService.AddHook(savingsRuleService)
+	paycheckService := service.NewPaycheckService(paycheckRepo, accountRepo, categoryRepo, quotaService, transactionService)
+	projectService := service.NewProjectService(projectRepo, categoryRepo)
+	planningService := service.NewPlanningService(transactionRepo, accountRepo, netWorthService)
+	insightsService := service.NewInsightsService(accountService, transactionRepo, budgetRepo)
+	chartService := service.NewChartService(reportService, netWorthService, budgetService)
+	shareLinkService := service.NewShareLinkService(shareLinkRepo, transactionRepo, accountService, reportService, service.ShareLinkOptions{
+		BaseURL:    s.config.ShareLinkBaseURL,
+		DefaultTTL: s.config.ShareLinkDefaultTTL,
+		MaxTTL:     s.config.ShareLinkMaxTTL,
+	})
+	mail := s.buildMailer(logger)
+	magicLinkService := service.NewMagicLinkService(
+		userRepo,
+		magicLinkRepo,
+		authService,
+		mail,
+		ratelimit.New(s.config.MagicLinkMaxRequests, s.config.MagicLinkRateWindow),
+		service.MagicLinkOptions{BaseURL: s.config.MagicLinkBaseURL, TTL: s.config.MagicLinkTTL},
+	)
+	collaboratorService := service.NewCollaboratorService(collaboratorRepo, userRepo, accountRepo, mail)
+	telegramClient := telegram.NewClient(s.config.TelegramAPIURL, s.config.TelegramBotToken)
+	botService := service.NewBotService(
+		telegramRepo,
+		userRepo,
+		categoryRepo,
+		accountService,
+		transactionService,
+		budgetService,
+		telegramClient,
+		service.BotOptions{LinkCodeTTL: s.config.TelegramLinkCodeTTL},
+	)
+	adminService := service.NewAdminService(userRepo, auditLogRepo, usageRepo, yieldRepo, s.scheduler, s.config.MigrationsPath)
+	if err := adminService.PromoteAdmins(logger.WithContext(context.Background()), s.config.AdminEmails); err != nil {
+		logger.Error().Err(err).Msg("failed to promote configured admins")
+	}
+
+	s.scheduler.Register(jobs.NewPointsAccrualJob(pointsService), 24*time.Hour)
+	s.scheduler.Register(jobs.NewBenefitCreditJob(benefitService), 24*time.Hour)
+	s.scheduler.Register(jobs.NewAssetDepreciationJob(assetService), 24*time.Hour)
+	s.scheduler.Register(jobs.NewPaycheckPostingJob(paycheckService), 24*time.Hour)
+	s.scheduler.Register(jobs.NewOverdraftInterestJob(overdraftService), 24*time.Hour)
+	s.scheduler.Register(jobs.NewYieldAccrualJob(yieldService), 24*time.Hour)
+	s.scheduler.Register(jobs.NewSavingsRoundUpsJob(savingsRuleService), 24*time.Hour)
+	if s.config.TelegramBotToken != "" {
+		s.scheduler.Register(jobs.NewBudgetAlertJob(botService), time.Hour)
+		if s.config.TelegramWebhookSecret == "" {
+			s.telegramPoller = telegram.NewPoller(telegramClient, botService, *logger)
+		}
+	}
 
 	// Handlers
 	authHandler := handlers.NewAuthHandler(authService)
 	userHandler := handlers.NewUserHandler(userService)
 	accountHandler := handlers.NewAccountHandler(accountService)
+	cardHandler := handlers.NewCardHandler(cardService)
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
 	transactionHandler := handlers.NewTransactionHandler(transactionService)
 	budgetHandler := handlers.NewBudgetHandler(budgetService)
+	magicLinkHandler := handlers.NewMagicLinkHandler(magicLinkService)
+	adminHandler := handlers.NewAdminHandler(adminService)
+	usageHandler := handlers.NewUsageHandler(quotaService)
+	reportHandler := handlers.NewReportHandler(reportService)
+	shareLinkHandler := handlers.NewShareLinkHandler(shareLinkService)
+	collaboratorHandler := handlers.NewCollaboratorHandler(collaboratorService)
+	pointsHandler := handlers.NewPointsHandler(pointsService)
+	netWorthHandler := handlers.NewNetWorthHandler(netWorthService)
+	benefitHandler := handlers.NewBenefitHandler(benefitService)
+	yieldHandler := handlers.NewYieldHandler(yieldService)
+	savingsRuleHandler := handlers.NewSavingsRuleHandler(savingsRuleService)
+	assetHandler := handlers.NewAssetHandler(assetService)
+	paycheckHandler := handlers.NewPaycheckHandler(paycheckService)
+	projectHandler := handlers.NewProjectHandler(projectService)
+	planningHandler := handlers.NewPlanningHandler(planningService)
+	insightsHandler := handlers.NewInsightsHandler(insightsService)
+	chartHandler := handlers.NewChartHandler(chartService)
+	telegramHandler := handlers.NewTelegramHandler(botService, s.config.TelegramBotUsername, s.config.TelegramWebhookSecret)
 
 	// --- Middlewares Globais ---
 	s.router.Use(middleware.LoggerMiddleware(*logger))
@@ -107,19 +237,34 @@ func (s *Server) setupRouter(logger *zerolog.Logger) {
 		authRoutes := v1.Group("/auth")
 		{
 			authRoutes.POST("/login", authHandler.Login)
+			if s.config.MagicLinkEnabled {
+				authRoutes.POST("/magic-link", magicLinkHandler.RequestMagicLink)
+				authRoutes.GET("/magic-link/verify", magicLinkHandler.VerifyMagicLink)
+			}
 		}
 		usersPublicRoutes := v1.Group("/users")
 		{
 			usersPublicRoutes.POST("", userHandler.CreateUser)
 		}
+		v1.GET("/shared/:token", shareLinkHandler.GetSharedContent)
+		if s.config.TelegramBotToken != "" && s.config.TelegramWebhookSecret != "" {
+			v1.POST("/telegram/webhook", telegramHandler.TelegramWebhook)
+		}
+
+		// Rotas Autenticadas, liberadas mesmo quando a troca de senha é obrigatória
+		authenticated := v1.Group("")
+		authenticated.Use(middleware.AuthMiddleware(s.config.JWTSecretKey, userRepo, collaboratorRepo, *logger))
+		authenticated.PUT("/users/me/password", userHandler.ChangePassword)
 
 		// Rotas Protegidas
-		protected := v1.Group("")
-		protected.Use(middleware.AuthMiddleware(s.config.JWTSecretKey, *logger))
+		protected := authenticated.Group("")
+		protected.Use(middleware.PasswordResetGuard(), middleware.QuotaMiddleware(quotaService))
 		{
 			userRoutes := protected.Group("/users")
 			{
 				userRoutes.GET("/me", userHandler.GetProfile)
+				userRoutes.PUT("/me/fiscal-month", userHandler.UpdateFiscalMonth)
+				userRoutes.GET("/me/usage", usageHandler.GetMyUsage)
 			}
 
 			accounts := protected.Group("/accounts")
@@ -128,6 +273,14 @@ func (s *Server) setupRouter(logger *zerolog.Logger) {
 				accounts.GET("", accountHandler.ListAccounts)
 				accounts.GET("/:id", accountHandler.GetAccount)
 				accounts.GET("/:id/statement", accountHandler.GetAccountStatement)
+				// Deleting is up to the owner, so collaborators cannot preview it either.
+				accounts.GET("/:id/deletion-impact", middleware.OwnerOnly(), accountHandler.GetAccountDeletionImpact)
+				accounts.GET("/:id/benefit-credits", benefitHandler.ListBenefitCredits)
+				accounts.GET("/:id/yields", yieldHandler.ListYieldAccruals)
+				accounts.GET("/:id/cards", cardHandler.ListCards)
+				accounts.POST("/:id/cards", cardHandler.CreateCard)
+				accounts.PUT("/:id/cards/:cardId", cardHandler.UpdateCard)
+				accounts.DELETE("/:id/cards/:cardId", cardHandler.DeleteCard)
 				accounts.PUT("/:id", accountHandler.UpdateAccount)
 				accounts.DELETE("/:id", accountHandler.DeleteAccount)
 			}
@@ -158,15 +311,144 @@ func (s *Server) setupRouter(logger *zerolog.Logger) {
 				transactions.PATCH("/:id", transactionHandler.PatchTransaction)
 				transactions.DELETE("/:id", transactionHandler.DeleteTransaction)
 			}
+
+			reports := protected.Group("/reports")
+			{
+				reports.GET("/monthly", reportHandler.GetMonthlyReport)
+				reports.POST("/query", reportHandler.QueryReport)
+				reports.GET("/net-worth", netWorthHandler.GetNetWorth)
+				reports.GET("/paychecks", paycheckHandler.GetPaycheckSummary)
+			}
+
+			points := protected.Group("/points")
+			{
+				points.POST("/rules", pointsHandler.CreatePointsRule)
+				points.GET("/rules", pointsHandler.ListPointsRules)
+				points.DELETE("/rules/:id", pointsHandler.DeletePointsRule)
+				points.POST("/rules/:id/accrue", pointsHandler.AccruePoints)
+				points.GET("/accounts/:id", pointsHandler.GetPointsSummary)
+				points.POST("/accounts/:id/lots", pointsHandler.AddPoints)
+				points.POST("/transfers", pointsHandler.TransferPoints)
+				points.GET("/transfers", pointsHandler.ListPointsTransfers)
+			}
+
+			savingsRules := protected.Group("/savings-rules")
+			{
+				savingsRules.POST("", savingsRuleHandler.CreateSavingsRule)
+				savingsRules.GET("", savingsRuleHandler.ListSavingsRules)
+				savingsRules.DELETE("/:id", savingsRuleHandler.DeleteSavingsRule)
+				savingsRules.GET("/:id/runs", savingsRuleHandler.ListSavingsRuleRuns)
+			}
+
+			assets := protected.Group("/assets")
+			{
+				assets.GET("/:id", assetHandler.GetAssetSummary)
+				assets.POST("/:id/valuations", assetHandler.RevalueAsset)
+			}
+
+			paychecks := protected.Group("/paychecks")
+			{
+				paychecks.GET("", paycheckHandler.ListPaychecks)
+				paychecks.POST("/templates", paycheckHandler.CreatePaycheckTemplate)
+				paychecks.GET("/templates", paycheckHandler.ListPaycheckTemplates)
+				paychecks.PUT("/templates/:id", paycheckHandler.UpdatePaycheckTemplate)
+				paychecks.DELETE("/templates/:id", paycheckHandler.DeletePaycheckTemplate)
+				paychecks.POST("/templates/:id/post", paycheckHandler.PostPaycheck)
+			}
+
+			projects := protected.Group("/projects")
+			{
+				projects.POST("", projectHandler.CreateProject)
+				projects.GET("", projectHandler.ListProjects)
+				projects.GET("/:id", projectHandler.GetProject)
+				projects.PUT("/:id", projectHandler.UpdateProject)
+				projects.DELETE("/:id", projectHandler.DeleteProject)
+				projects.GET("/:id/report", projectHandler.GetProjectReport)
+				projects.GET("/:id/transactions", projectHandler.ListProjectTransactions)
+				projects.POST("/:id/transactions", projectHandler.AssignProjectTransactions)
+				projects.DELETE("/:id/transactions/:transactionId", projectHandler.UnassignProjectTransaction)
+			}
+
+			planning := protected.Group("/planning")
+			{
+				planning.POST("/scenarios", planningHandler.SimulateScenario)
+				planning.POST("/retirement", planningHandler.ProjectRetirement)
+			}
+
+			charts := protected.Group("/charts")
+			{
+				charts.GET("/cashflow.svg", chartHandler.GetCashflowChart)
+				charts.GET("/categories.svg", chartHandler.GetCategoriesChart)
+				charts.GET("/net-worth.svg", chartHandler.GetNetWorthChart)
+				charts.GET("/budget.svg", chartHandler.GetBudgetChart)
+			}
+
+			protected.GET("/index-rates/:name", yieldHandler.ListIndexRates)
+
+			insights := protected.Group("/insights")
+			{
+				insights.GET("/health", insightsHandler.GetHealth)
+			}
+
+			if s.config.TelegramBotToken != "" {
+				telegramRoutes := protected.Group("/telegram")
+				telegramRoutes.Use(middleware.OwnerOnly())
+				{
+					telegramRoutes.POST("/link-codes", telegramHandler.CreateTelegramLinkCode)
+					telegramRoutes.GET("/chats", telegramHandler.ListTelegramChats)
+					telegramRoutes.DELETE("/chats/:chatId", telegramHandler.UnlinkTelegramChat)
+				}
+			}
+
+			shareLinks := protected.Group("/share-links")
+			shareLinks.Use(middleware.OwnerOnly())
+			{
+				shareLinks.POST("", shareLinkHandler.CreateShareLink)
+				shareLinks.GET("", shareLinkHandler.ListShareLinks)
+				shareLinks.DELETE("/:id", shareLinkHandler.RevokeShareLink)
+			}
+
+			collaborators := protected.Group("/collaborators")
+			collaborators.Use(middleware.OwnerOnly())
+			{
+				collaborators.POST("", collaboratorHandler.InviteCollaborator)
+				collaborators.GET("", collaboratorHandler.ListCollaborators)
+				collaborators.DELETE("/:id", collaboratorHandler.RevokeCollaborator)
+				collaborators.GET("/invitations", collaboratorHandler.ListInvitations)
+				collaborators.POST("/invitations/:id/accept", collaboratorHandler.AcceptInvitation)
+			}
+
+			admin := protected.Group("/admin")
+			admin.Use(middleware.AdminMiddleware(*logger))
+			{
+				admin.GET("/users", adminHandler.ListUsers)
+				admin.POST("/users/:id/disable", adminHandler.DisableUser)
+				admin.POST("/users/:id/enable", adminHandler.EnableUser)
+				admin.POST("/users/:id/force-password-reset", adminHandler.ForcePasswordReset)
+				admin.GET("/users/:id/usage", adminHandler.GetUserUsage)
+				admin.GET("/jobs", adminHandler.ListJobs)
+				admin.GET("/migrations", adminHandler.GetMigrationStatus)
+				admin.GET("/audit-log", adminHandler.ListAuditLog)
+				admin.PUT("/index-rates/:name", adminHandler.LoadIndexRates)
+			}
 		}
 	}
 }
 
+// buildMailer returns an SMTP mailer when SMTP is configured, or a mailer that
+// only logs otherwise.
+func (s *Server) buildMailer(logger *zerolog.Logger) mailer.Mailer {
+	if s.config.SMTPHost == "" {
+		return mailer.NewLogMailer(*logger)
+	}
+	return mailer.NewSMTPMailer(s.config.SMTPHost, s.config.SMTPPort, s.config.SMTPUsername, s.config.SMTPPassword, s.config.MailFrom)
+}
+
 // buildCORSConfig builds the CORS configuration.
 func (s *Server) buildCORSConfig() cors.Config {
 	config := cors.DefaultConfig()
 	config.AllowAllOrigins = true
 	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Authorization"}
+	config.AllowHeaders = []string{"Origin", "Content-Type", "Authorization", handlers.SharePasswordHeader, middleware.ActAsUserHeader}
 	return config
 }
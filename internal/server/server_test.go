@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -36,7 +37,7 @@ func TestMain(m *testing.M) {
 	var pgContainer testcontainers.Container
 
 	testLogger := zerolog.Nop()
-	testCfg := config.Config{JWTSecretKey: "account_handler_test_key"}
+	testCfg := config.Config{JWTSecretKey: "account_handler_test_key", IOFForeignPurchaseRate: 3.5}
 	testDB, pgContainer := testhelper.SetupTestDB()
 	testServer = NewServer(testCfg, testDB, &testLogger)
 
@@ -53,9 +54,15 @@ func TestMain(m *testing.M) {
 func TestAuthMiddleware(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
+	ctx := context.Background()
+
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+	userId, err := userRepo.Create(ctx, model.User{Name: "Middleware", Email: "middleware@test.com", PasswordHash: "hash"})
+	require.NoError(t, err)
 
 	// Cria uma instância do middleware com uma chave secreta de teste e um logger "mudo".
-	authMiddleware := middleware.AuthMiddleware(testServer.config.JWTSecretKey, zerolog.Nop())
+	authMiddleware := middleware.AuthMiddleware(testServer.config.JWTSecretKey, userRepo, repository.NewCollaboratorRepository(testServer.db), zerolog.Nop())
 
 	// Cria uma rota protegida de exemplo.
 	router.GET("/protected", authMiddleware, func(c *gin.Context) {
@@ -133,7 +140,7 @@ func TestAuthMiddleware(t *testing.T) {
 
 	t.Run("should grant access when token is valid", func(t *testing.T) {
 		recorder := httptest.NewRecorder()
-		validToken := testhelper.GenerateTestToken(t, 123, testServer.config.JWTSecretKey)
+		validToken := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
 
 		req, _ := http.NewRequest("GET", "/protected", nil)
 		req.Header.Set("Authorization", "Bearer "+validToken)
@@ -165,7 +172,7 @@ func TestAuthMiddleware(t *testing.T) {
 	})
 
 	t.Run("should allow access when token is valid", func(t *testing.T) {
-		validToken := testhelper.GenerateTestToken(t, 123, testServer.config.JWTSecretKey)
+		validToken := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
 
 		req, _ := http.NewRequest("GET", "/protected", nil)
 		req.Header.Set("Authorization", "Bearer "+validToken)
@@ -175,6 +182,34 @@ func TestAuthMiddleware(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, recorder.Code)
 	})
+
+	t.Run("should return 401 Unauthorized when the token user no longer exists", func(t *testing.T) {
+		validToken := testhelper.GenerateTestToken(t, userId+1000, testServer.config.JWTSecretKey)
+
+		req, _ := http.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+validToken)
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+
+	t.Run("should return 403 Forbidden when the user is disabled", func(t *testing.T) {
+		disabledId, err := userRepo.Create(ctx, model.User{Name: "Disabled", Email: "disabled@test.com", PasswordHash: "hash"})
+		require.NoError(t, err)
+		require.NoError(t, userRepo.SetDisabled(ctx, disabledId, true))
+		token := testhelper.GenerateTestToken(t, disabledId, testServer.config.JWTSecretKey)
+
+		req, _ := http.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "user account is disabled")
+	})
 }
 
 // Routes
@@ -299,6 +334,29 @@ func TestUserRoutes(t *testing.T) {
 
 		}, 2*time.Second, 100*time.Millisecond, "it should create the default categories within the time limit")
 	})
+	t.Run("should report the logged-in user's quota usage", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		userRepo := repository.NewUserRepository(testServer.db)
+		accountRepo := repository.NewAccountRepository(testServer.db)
+		userId, err := userRepo.Create(ctx, model.User{Name: "Usage", Email: "usage@test.com", PasswordHash: "hash"})
+		require.NoError(err)
+		_, err = accountRepo.Create(ctx, model.Account{UserId: userId, Name: "Wallet", Type: model.Checking})
+		require.NoError(err)
+		token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "GET", "/v1/users/me/usage", token, bytes.NewBuffer(nil))
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var usage dto.UsageResponse
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &usage))
+		assert.Equal(t, int64(1), usage.Accounts.Used)
+		assert.Equal(t, int64(0), usage.Accounts.Limit)
+		assert.Nil(t, usage.Accounts.Remaining)
+		assert.Equal(t, int64(1), usage.RequestsToday.Used)
+	})
 }
 
 func TestLoginRoutes(t *testing.T) {
@@ -1484,6 +1542,1381 @@ func TestBudgetRoutes(t *testing.T) {
 	})
 }
 
+func TestShareLinkRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+	accountRepo := repository.NewAccountRepository(testServer.db)
+	txRepo := repository.NewTransactionRepository(testServer.db)
+
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Sharer", Email: "sharer@test.com", PasswordHash: "hash"})
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+	accountId, _ := accountRepo.Create(ctx, model.Account{UserId: userId, Name: "Checking", Type: model.Checking})
+	_, _ = txRepo.Create(ctx, model.Transaction{UserId: userId, AccountId: accountId, Description: "Salary", Amount: decimal.NewFromInt(3000), Type: model.Income, Date: time.Date(2025, 7, 5, 12, 0, 0, 0, time.UTC)})
+	_, _ = txRepo.Create(ctx, model.Transaction{UserId: userId, AccountId: accountId, Description: "Rent", Amount: decimal.NewFromInt(1200), Type: model.Expense, Date: time.Date(2025, 7, 10, 12, 0, 0, 0, time.UTC)})
+
+	openLink := func(path, password string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		if password != "" {
+			req.Header.Set("X-Share-Password", password)
+		}
+		recorder := httptest.NewRecorder()
+		testServer.router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	t.Run("should share a monthly report as JSON and PDF without login", func(t *testing.T) {
+		// Arrange
+		body, _ := json.Marshal(dto.CreateShareLinkRequest{Scope: model.ShareScopeMonthlyReport, Year: 2025, Month: 7, Password: "accountant"})
+		recorderCreate := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/share-links", token, bytes.NewBuffer(body))
+		require.Equal(http.StatusCreated, recorderCreate.Code)
+		var created dto.CreateShareLinkResponse
+		require.NoError(json.Unmarshal(recorderCreate.Body.Bytes(), &created))
+		require.True(created.PasswordProtected)
+
+		// Act & Assert: the password is required
+		assert.Equal(t, http.StatusUnauthorized, openLink("/v1/shared/"+created.Token, "").Code)
+
+		// Act & Assert: JSON
+		recorderJSON := openLink("/v1/shared/"+created.Token, "accountant")
+		require.Equal(http.StatusOK, recorderJSON.Code)
+		var shared dto.SharedContentResponse
+		require.NoError(json.Unmarshal(recorderJSON.Body.Bytes(), &shared))
+		require.NotNil(shared.MonthlyReport)
+		assert.True(t, decimal.NewFromInt(1800).Equal(shared.MonthlyReport.Net))
+
+		// Act & Assert: PDF
+		recorderPDF := openLink("/v1/shared/"+created.Token+"?format=pdf", "accountant")
+		require.Equal(http.StatusOK, recorderPDF.Code)
+		assert.Equal(t, "application/pdf", recorderPDF.Header().Get("Content-Type"))
+		assert.True(t, bytes.HasPrefix(recorderPDF.Body.Bytes(), []byte("%PDF-")))
+
+		// Assert: both successful accesses were counted
+		recorderList := testhelper.MakeAPIRequest(t, testServer.router, "GET", "/v1/share-links", token, nil)
+		var links []dto.ShareLinkResponse
+		require.NoError(json.Unmarshal(recorderList.Body.Bytes(), &links))
+		require.Len(links, 1)
+		assert.Equal(t, int64(2), links[0].AccessCount)
+		assert.NotNil(t, links[0].LastAccessedAt)
+	})
+
+	t.Run("should stop serving a revoked link", func(t *testing.T) {
+		// Arrange
+		body, _ := json.Marshal(dto.CreateShareLinkRequest{Scope: model.ShareScopeTransactions, Filter: &model.TransactionFilter{AccountId: &accountId}})
+		recorderCreate := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/share-links", token, bytes.NewBuffer(body))
+		require.Equal(http.StatusCreated, recorderCreate.Code)
+		var created dto.CreateShareLinkResponse
+		require.NoError(json.Unmarshal(recorderCreate.Body.Bytes(), &created))
+		require.Equal(http.StatusOK, openLink("/v1/shared/"+created.Token, "").Code)
+
+		// Act
+		recorderRevoke := testhelper.MakeAPIRequest(t, testServer.router, "DELETE", fmt.Sprintf("/v1/share-links/%d", created.Id), token, nil)
+
+		// Assert
+		assert.Equal(t, http.StatusNoContent, recorderRevoke.Code)
+		assert.Equal(t, http.StatusNotFound, openLink("/v1/shared/"+created.Token, "").Code)
+	})
+}
+
+func TestCollaboratorRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+	accountRepo := repository.NewAccountRepository(testServer.db)
+	txRepo := repository.NewTransactionRepository(testServer.db)
+
+	ownerId, _ := userRepo.Create(ctx, model.User{Name: "Owner", Email: "owner@test.com", PasswordHash: "hash"})
+	advisorId, _ := userRepo.Create(ctx, model.User{Name: "Advisor", Email: "advisor@test.com", PasswordHash: "hash"})
+	ownerToken := testhelper.GenerateTestToken(t, ownerId, testServer.config.JWTSecretKey)
+	advisorToken := testhelper.GenerateTestToken(t, advisorId, testServer.config.JWTSecretKey)
+
+	sharedAccountId, _ := accountRepo.Create(ctx, model.Account{UserId: ownerId, Name: "Checking", Type: model.Checking})
+	privateAccountId, _ := accountRepo.Create(ctx, model.Account{UserId: ownerId, Name: "Savings", Type: model.Savings})
+	_, _ = txRepo.Create(ctx, model.Transaction{UserId: ownerId, AccountId: sharedAccountId, Description: "In range", Amount: decimal.NewFromInt(100), Type: model.Expense, Date: time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)})
+	_, _ = txRepo.Create(ctx, model.Transaction{UserId: ownerId, AccountId: sharedAccountId, Description: "Too late", Amount: decimal.NewFromInt(100), Type: model.Expense, Date: time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)})
+	_, _ = txRepo.Create(ctx, model.Transaction{UserId: ownerId, AccountId: privateAccountId, Description: "Private", Amount: decimal.NewFromInt(100), Type: model.Income, Date: time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)})
+
+	actAs := func(method, path string, body *bytes.Buffer) *httptest.ResponseRecorder {
+		var req *http.Request
+		if body != nil {
+			req, _ = http.NewRequest(method, path, body)
+		} else {
+			req, _ = http.NewRequest(method, path, nil)
+		}
+		req.Header.Set("Authorization", "Bearer "+advisorToken)
+		req.Header.Set(middleware.ActAsUserHeader, fmt.Sprint(ownerId))
+		recorder := httptest.NewRecorder()
+		testServer.router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	// Invite and accept
+	body, _ := json.Marshal(dto.InviteCollaboratorRequest{Email: "advisor@test.com", AccountIds: []int64{sharedAccountId}, StartDate: "2025-01-01", EndDate: "2025-03-31"})
+	recorderInvite := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/collaborators", ownerToken, bytes.NewBuffer(body))
+	require.Equal(http.StatusCreated, recorderInvite.Code)
+	var grant dto.CollaboratorGrantResponse
+	require.NoError(json.Unmarshal(recorderInvite.Body.Bytes(), &grant))
+	assert.Equal(t, "pending", grant.Status)
+
+	t.Run("should deny access before the invitation is accepted", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, actAs("GET", "/v1/accounts", nil).Code)
+	})
+
+	recorderAccept := testhelper.MakeAPIRequest(t, testServer.router, "POST", fmt.Sprintf("/v1/collaborators/invitations/%d/accept", grant.Id), advisorToken, nil)
+	require.Equal(http.StatusOK, recorderAccept.Code)
+
+	t.Run("should only expose the granted accounts and period", func(t *testing.T) {
+		// Act
+		recorderAccounts := actAs("GET", "/v1/accounts", nil)
+		recorderTransactions := actAs("GET", "/v1/transactions", nil)
+		recorderPrivate := actAs("GET", fmt.Sprintf("/v1/accounts/%d", privateAccountId), nil)
+
+		// Assert
+		require.Equal(http.StatusOK, recorderAccounts.Code)
+		var accounts []dto.AccountResponse
+		require.NoError(json.Unmarshal(recorderAccounts.Body.Bytes(), &accounts))
+		require.Len(accounts, 1)
+		assert.Equal(t, sharedAccountId, accounts[0].Id)
+
+		require.Equal(http.StatusOK, recorderTransactions.Code)
+		var transactions []dto.TransactionResponse
+		require.NoError(json.Unmarshal(recorderTransactions.Body.Bytes(), &transactions))
+		require.Len(transactions, 1)
+		assert.Equal(t, "In range", transactions[0].Description)
+
+		assert.Equal(t, http.StatusNotFound, recorderPrivate.Code)
+	})
+
+	t.Run("should reject mutating requests and sharing settings", func(t *testing.T) {
+		// Arrange
+		body, _ := json.Marshal(dto.AccountRequest{Name: "Sneaky", Type: model.Checking, InitialBalance: testhelper.Ptr(decimal.Zero)})
+
+		// Act & Assert
+		assert.Equal(t, http.StatusForbidden, actAs("POST", "/v1/accounts", bytes.NewBuffer(body)).Code)
+		assert.Equal(t, http.StatusForbidden, actAs("DELETE", fmt.Sprintf("/v1/accounts/%d", sharedAccountId), nil).Code)
+		assert.Equal(t, http.StatusForbidden, actAs("GET", fmt.Sprintf("/v1/accounts/%d/deletion-impact", sharedAccountId), nil).Code)
+		assert.Equal(t, http.StatusForbidden, actAs("GET", "/v1/collaborators", nil).Code)
+	})
+
+	t.Run("should stop access once revoked", func(t *testing.T) {
+		// Act
+		recorderRevoke := testhelper.MakeAPIRequest(t, testServer.router, "DELETE", fmt.Sprintf("/v1/collaborators/%d", grant.Id), ownerToken, nil)
+
+		// Assert
+		assert.Equal(t, http.StatusNoContent, recorderRevoke.Code)
+		assert.Equal(t, http.StatusForbidden, actAs("GET", "/v1/accounts", nil).Code)
+	})
+}
+
+func TestPointsRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+	accountRepo := repository.NewAccountRepository(testServer.db)
+
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Traveler", Email: "traveler@test.com", PasswordHash: "hash"})
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+	checkingId, _ := accountRepo.Create(ctx, model.Account{UserId: userId, Name: "Checking", Type: model.Checking, InitialBalance: decimal.NewFromInt(1000)})
+	liveloId, _ := accountRepo.Create(ctx, model.Account{UserId: userId, Name: "Livelo", Type: model.Points, PointsValuePerThousand: testhelper.Ptr(decimal.NewFromInt(40))})
+	smilesId, _ := accountRepo.Create(ctx, model.Account{UserId: userId, Name: "Smiles", Type: model.Points, PointsValuePerThousand: testhelper.Ptr(decimal.NewFromInt(20))})
+
+	t.Run("should add, transfer and value points", func(t *testing.T) {
+		// Arrange
+		body, _ := json.Marshal(dto.AddPointsRequest{Points: 10000, Description: "Opening balance"})
+		recorderAdd := testhelper.MakeAPIRequest(t, testServer.router, "POST", fmt.Sprintf("/v1/points/accounts/%d/lots", liveloId), token, bytes.NewBuffer(body))
+		require.Equal(http.StatusCreated, recorderAdd.Code)
+
+		// Act
+		body, _ = json.Marshal(dto.PointsTransferRequest{FromAccountId: liveloId, ToAccountId: smilesId, Points: 5000, BonusPercent: decimal.NewFromInt(100)})
+		recorderTransfer := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/points/transfers", token, bytes.NewBuffer(body))
+		body, _ = json.Marshal(dto.PointsTransferRequest{FromAccountId: liveloId, ToAccountId: smilesId, Points: 6000})
+		recorderTooMuch := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/points/transfers", token, bytes.NewBuffer(body))
+		recorderNetWorth := testhelper.MakeAPIRequest(t, testServer.router, "GET", "/v1/reports/net-worth", token, nil)
+
+		// Assert
+		require.Equal(http.StatusCreated, recorderTransfer.Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, recorderTooMuch.Code)
+
+		require.Equal(http.StatusOK, recorderNetWorth.Code)
+		var netWorth dto.NetWorthResponse
+		require.NoError(json.Unmarshal(recorderNetWorth.Body.Bytes(), &netWorth))
+		// 1000 in checking + 5,000 Livelo points at 40 + 10,000 Smiles points at 20
+		assert.True(t, decimal.NewFromInt(1400).Equal(netWorth.Total), netWorth.Total.String())
+	})
+
+	t.Run("should reject transactions on points accounts", func(t *testing.T) {
+		// Arrange
+		body, _ := json.Marshal(dto.CreateTransactionRequest{Description: "Miles", Amount: decimal.NewFromInt(10), Type: model.Transfer, Date: time.Now(), AccountId: checkingId, DestinationAccountId: &liveloId})
+
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/transactions", token, bytes.NewBuffer(body))
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestBenefitRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+	categoryRepo := repository.NewCategoryRepository(testServer.db)
+
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Worker", Email: "worker@test.com", PasswordHash: "hash"})
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+	restaurantsId, _ := categoryRepo.Create(ctx, model.Category{UserId: userId, Name: "Restaurants", Type: model.Expense})
+	travelId, _ := categoryRepo.Create(ctx, model.Category{UserId: userId, Name: "Travel", Type: model.Expense})
+
+	body, _ := json.Marshal(dto.AccountRequest{
+		Name:                      "VR",
+		Type:                      model.Benefit,
+		InitialBalance:            testhelper.Ptr(decimal.NewFromInt(500)),
+		BenefitAllowedCategoryIds: []int64{restaurantsId},
+		BenefitMonthlyCredit:      testhelper.Ptr(decimal.NewFromInt(800)),
+		BenefitCreditDay:          testhelper.Ptr(1),
+	})
+	recorderAccount := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/accounts", token, bytes.NewBuffer(body))
+	require.Equal(http.StatusCreated, recorderAccount.Code)
+	var account dto.AccountResponse
+	require.NoError(json.Unmarshal(recorderAccount.Body.Bytes(), &account))
+
+	t.Run("should only pay for the allowed categories", func(t *testing.T) {
+		// Arrange
+		lunch, _ := json.Marshal(dto.CreateTransactionRequest{Description: "Lunch", Amount: decimal.NewFromInt(45), Type: model.Expense, Date: time.Now(), AccountId: account.Id, CategoryId: &restaurantsId})
+		flight, _ := json.Marshal(dto.CreateTransactionRequest{Description: "Flight", Amount: decimal.NewFromInt(300), Type: model.Expense, Date: time.Now(), AccountId: account.Id, CategoryId: &travelId})
+
+		// Act
+		recorderLunch := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/transactions", token, bytes.NewBuffer(lunch))
+		recorderFlight := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/transactions", token, bytes.NewBuffer(flight))
+		recorderReport := testhelper.MakeAPIRequest(t, testServer.router, "GET", fmt.Sprintf("/v1/reports/monthly?year=%d&month=%d", time.Now().Year(), time.Now().Month()), token, nil)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, recorderLunch.Code)
+		assert.Equal(t, http.StatusBadRequest, recorderFlight.Code)
+		require.Equal(http.StatusOK, recorderReport.Code)
+		var report dto.MonthlyReportResponse
+		require.NoError(json.Unmarshal(recorderReport.Body.Bytes(), &report))
+		assert.True(t, decimal.NewFromInt(45).Equal(report.BenefitExpense), report.BenefitExpense.String())
+		assert.True(t, report.CashExpense.IsZero())
+	})
+
+	t.Run("should list the monthly credits of the account", func(t *testing.T) {
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "GET", fmt.Sprintf("/v1/accounts/%d/benefit-credits", account.Id), token, nil)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+}
+
+func TestAssetRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Owner", Email: "owner@test.com", PasswordHash: "hash"})
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+
+	method := model.AssetManual
+	body, _ := json.Marshal(dto.AccountRequest{
+		Name:                 "Apartment",
+		Type:                 model.Asset,
+		InitialBalance:       testhelper.Ptr(decimal.Zero),
+		AssetPurchaseValue:   testhelper.Ptr(decimal.NewFromInt(300000)),
+		AssetPurchaseDate:    "2020-05-10",
+		AssetValuationMethod: &method,
+	})
+	recorderAccount := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/accounts", token, bytes.NewBuffer(body))
+	require.Equal(http.StatusCreated, recorderAccount.Code)
+	var account dto.AccountResponse
+	require.NoError(json.Unmarshal(recorderAccount.Body.Bytes(), &account))
+
+	t.Run("should revalue the asset and count it in the net worth", func(t *testing.T) {
+		// Arrange
+		body, _ := json.Marshal(dto.RevalueAssetRequest{Value: decimal.NewFromInt(350000), ValuedOn: "2024-01-15", Note: "Appraisal"})
+
+		// Act
+		recorderRevalue := testhelper.MakeAPIRequest(t, testServer.router, "POST", fmt.Sprintf("/v1/assets/%d/valuations", account.Id), token, bytes.NewBuffer(body))
+		recorderSummary := testhelper.MakeAPIRequest(t, testServer.router, "GET", fmt.Sprintf("/v1/assets/%d", account.Id), token, nil)
+		recorderNetWorth := testhelper.MakeAPIRequest(t, testServer.router, "GET", "/v1/reports/net-worth", token, nil)
+
+		// Assert
+		require.Equal(http.StatusCreated, recorderRevalue.Code)
+		require.Equal(http.StatusOK, recorderSummary.Code)
+		var summary dto.AssetSummaryResponse
+		require.NoError(json.Unmarshal(recorderSummary.Body.Bytes(), &summary))
+		assert.True(t, decimal.NewFromInt(350000).Equal(summary.CurrentValue), summary.CurrentValue.String())
+		assert.Len(t, summary.Valuations, 1)
+
+		require.Equal(http.StatusOK, recorderNetWorth.Code)
+		var netWorth dto.NetWorthResponse
+		require.NoError(json.Unmarshal(recorderNetWorth.Body.Bytes(), &netWorth))
+		assert.True(t, decimal.NewFromInt(350000).Equal(netWorth.Total), netWorth.Total.String())
+	})
+
+	t.Run("should reject transactions on asset accounts", func(t *testing.T) {
+		// Arrange
+		body, _ := json.Marshal(dto.CreateTransactionRequest{Description: "Rent", Amount: decimal.NewFromInt(10), Type: model.Income, Date: time.Now(), AccountId: account.Id})
+
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/transactions", token, bytes.NewBuffer(body))
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestPaycheckRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Owner", Email: "owner@test.com", PasswordHash: "hash"})
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+	accountId := testhelper.CreateAccount(t, testServer.router, token, dto.AccountRequest{
+		Name:           "Checking",
+		Type:           model.Checking,
+		InitialBalance: testhelper.Ptr(decimal.Zero),
+	})
+
+	body, _ := json.Marshal(dto.PaycheckTemplateRequest{
+		Name:      "ACME",
+		AccountId: accountId,
+		Lines: []dto.PaycheckLineRequest{
+			{Kind: model.PaycheckEarning, Description: "Salary", Amount: decimal.NewFromInt(8000)},
+			{Kind: model.PaycheckDeduction, Description: "INSS", Amount: decimal.RequireFromString("908.85")},
+		},
+	})
+	recorderTemplate := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/paychecks/templates", token, bytes.NewBuffer(body))
+	require.Equal(http.StatusCreated, recorderTemplate.Code)
+	var template model.PaycheckTemplate
+	require.NoError(json.Unmarshal(recorderTemplate.Body.Bytes(), &template))
+	require.Len(template.Lines, 2)
+
+	t.Run("should post the net amount into the account once per month", func(t *testing.T) {
+		// Arrange
+		body, _ := json.Marshal(dto.PostPaycheckRequest{Year: 2025, Month: 3, PayDate: "2025-03-05"})
+
+		// Act
+		recorderFirst := testhelper.MakeAPIRequest(t, testServer.router, "POST", fmt.Sprintf("/v1/paychecks/templates/%d/post", template.Id), token, bytes.NewBuffer(body))
+		recorderSecond := testhelper.MakeAPIRequest(t, testServer.router, "POST", fmt.Sprintf("/v1/paychecks/templates/%d/post", template.Id), token, bytes.NewBuffer(body))
+		recorderAccount := testhelper.MakeAPIRequest(t, testServer.router, "GET", fmt.Sprintf("/v1/accounts/%d", accountId), token, nil)
+
+		// Assert
+		require.Equal(http.StatusCreated, recorderFirst.Code)
+		assert.Equal(t, http.StatusConflict, recorderSecond.Code)
+		require.Equal(http.StatusOK, recorderAccount.Code)
+		var account dto.AccountResponse
+		require.NoError(json.Unmarshal(recorderAccount.Body.Bytes(), &account))
+		require.NotNil(account.Balance)
+		assert.True(t, decimal.RequireFromString("7091.15").Equal(*account.Balance), account.Balance.String())
+	})
+
+	t.Run("should report the gross and deductions of the year", func(t *testing.T) {
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "GET", "/v1/reports/paychecks?year=2025", token, nil)
+
+		// Assert
+		require.Equal(http.StatusOK, recorder.Code)
+		var summary dto.PaycheckSummaryResponse
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &summary))
+		assert.Equal(t, 1, summary.Paychecks)
+		assert.True(t, decimal.NewFromInt(8000).Equal(summary.Gross), summary.Gross.String())
+		assert.Len(t, summary.Lines, 2)
+	})
+}
+
+func TestProjectRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Owner", Email: "owner@test.com", PasswordHash: "hash"})
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+	accountId := testhelper.CreateAccount(t, testServer.router, token, dto.AccountRequest{
+		Name:           "Checking",
+		Type:           model.Checking,
+		InitialBalance: testhelper.Ptr(decimal.NewFromInt(10000)),
+	})
+	today := time.Now().UTC()
+	body, _ := json.Marshal(dto.ProjectRequest{
+		Name:        "Renovation",
+		TotalBudget: decimal.NewFromInt(5000),
+		StartDate:   today.AddDate(0, -1, 0).Format("2006-01-02"),
+		EndDate:     today.AddDate(0, 1, 0).Format("2006-01-02"),
+	})
+	recorderProject := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/projects", token, bytes.NewBuffer(body))
+	require.Equal(http.StatusCreated, recorderProject.Code)
+	var project dto.ProjectResponse
+	require.NoError(json.Unmarshal(recorderProject.Body.Bytes(), &project))
+
+	t.Run("should report the spending of assigned transactions", func(t *testing.T) {
+		// Arrange
+		paint := testhelper.CreateTransaction(t, testServer.router, token, accountId, "Paint", "expense", "800")
+		testhelper.CreateTransaction(t, testServer.router, token, accountId, "Groceries", "expense", "300")
+		body, _ := json.Marshal(dto.AssignProjectTransactionsRequest{TransactionIds: []int64{paint}})
+
+		// Act
+		recorderAssign := testhelper.MakeAPIRequest(t, testServer.router, "POST", fmt.Sprintf("/v1/projects/%d/transactions", project.Id), token, bytes.NewBuffer(body))
+		recorderReport := testhelper.MakeAPIRequest(t, testServer.router, "GET", fmt.Sprintf("/v1/projects/%d/report", project.Id), token, nil)
+
+		// Assert
+		require.Equal(http.StatusNoContent, recorderAssign.Code)
+		require.Equal(http.StatusOK, recorderReport.Code)
+		var report dto.ProjectReportResponse
+		require.NoError(json.Unmarshal(recorderReport.Body.Bytes(), &report))
+		assert.True(t, decimal.NewFromInt(800).Equal(report.Spent), report.Spent.String())
+		assert.True(t, decimal.NewFromInt(4200).Equal(report.Remaining), report.Remaining.String())
+		assert.Len(t, report.Timeline, 3)
+	})
+
+	t.Run("should not assign transactions of other users", func(t *testing.T) {
+		// Arrange
+		body, _ := json.Marshal(dto.AssignProjectTransactionsRequest{TransactionIds: []int64{99999}})
+
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "POST", fmt.Sprintf("/v1/projects/%d/transactions", project.Id), token, bytes.NewBuffer(body))
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestPlanningRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Owner", Email: "owner@test.com", PasswordHash: "hash"})
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+	testhelper.CreateAccount(t, testServer.router, token, dto.AccountRequest{
+		Name:           "Checking",
+		Type:           model.Checking,
+		InitialBalance: testhelper.Ptr(decimal.NewFromInt(1000)),
+	})
+
+	t.Run("should project a scenario without writing transactions", func(t *testing.T) {
+		// Arrange
+		body, _ := json.Marshal(dto.ScenarioRequest{
+			Months:    6,
+			Purchases: []dto.ScenarioPurchaseRequest{{Description: "Laptop", Amount: decimal.NewFromInt(1500), Month: 2}},
+		})
+
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/planning/scenarios", token, bytes.NewBuffer(body))
+		recorderTransactions := testhelper.MakeAPIRequest(t, testServer.router, "GET", "/v1/transactions", token, nil)
+
+		// Assert
+		require.Equal(http.StatusOK, recorder.Code)
+		var scenario dto.ScenarioResponse
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &scenario))
+		require.Len(scenario.Months, 6)
+		assert.True(t, decimal.NewFromInt(-500).Equal(scenario.Months[1].ScenarioBalance), scenario.Months[1].ScenarioBalance.String())
+		assert.True(t, decimal.NewFromInt(-1500).Equal(scenario.Months[5].BalanceDelta))
+		require.NotNil(scenario.FirstNegativeMonth)
+		assert.Equal(t, 2, *scenario.FirstNegativeMonth)
+
+		require.Equal(http.StatusOK, recorderTransactions.Code)
+		assert.NotContains(t, recorderTransactions.Body.String(), "Laptop")
+	})
+
+	t.Run("should reject loans without installments", func(t *testing.T) {
+		// Arrange
+		body := bytes.NewBufferString(`{"loans": [{"principal": "1000", "installments": 0}]}`)
+
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/planning/scenarios", token, body)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("should project retirement from the selected accounts", func(t *testing.T) {
+		// Arrange
+		savingsId := testhelper.CreateAccount(t, testServer.router, token, dto.AccountRequest{
+			Name:           "Savings",
+			Type:           model.Savings,
+			InitialBalance: testhelper.Ptr(decimal.NewFromInt(50000)),
+		})
+		body, _ := json.Marshal(dto.RetirementRequest{
+			AccountIds:          []int64{savingsId},
+			AnnualReturn:        decimal.NewFromInt(8),
+			Inflation:           decimal.NewFromInt(4),
+			MonthlyContribution: testhelper.Ptr(decimal.NewFromInt(3000)),
+			MonthlyExpenses:     testhelper.Ptr(decimal.NewFromInt(5000)),
+			Years:               30,
+			Simulations:         200,
+			Seed:                testhelper.Ptr(uint64(7)),
+		})
+
+		// Act
+		first := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/planning/retirement", token, bytes.NewBuffer(body))
+		second := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/planning/retirement", token, bytes.NewBuffer(body))
+
+		// Assert
+		require.Equal(http.StatusOK, first.Code)
+		var projection dto.RetirementResponse
+		require.NoError(json.Unmarshal(first.Body.Bytes(), &projection))
+		assert.True(t, decimal.NewFromInt(50000).Equal(projection.StartingBalance), projection.StartingBalance.String())
+		assert.Len(t, projection.Years, 30)
+		assert.NotNil(t, projection.IndependenceDate)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+	})
+}
+
+func TestInsightsRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Owner", Email: "owner@test.com", PasswordHash: "hash"})
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+	testhelper.CreateAccount(t, testServer.router, token, dto.AccountRequest{
+		Name:           "Checking",
+		Type:           model.Checking,
+		InitialBalance: testhelper.Ptr(decimal.NewFromInt(1000)),
+	})
+
+	t.Run("should report every health metric", func(t *testing.T) {
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "GET", "/v1/insights/health", token, nil)
+
+		// Assert
+		require.Equal(http.StatusOK, recorder.Code)
+		var health dto.HealthResponse
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &health))
+		require.Len(health.Metrics, 5)
+		for _, metric := range health.Metrics {
+			if metric.Name == service.MetricBudgetAdherence {
+				assert.Nil(t, metric.Score)
+				assert.Equal(t, "create_budgets", metric.Recommendation)
+			}
+		}
+		assert.True(t, health.PeriodEnd.After(health.PeriodStart))
+	})
+}
+
+func TestTelegramRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+
+	// A local stub stands in for the Bot API and keeps every message sent.
+	var sent []string
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params struct {
+			Text string `json:"text"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&params)
+		sent = append(sent, params.Text)
+		_, _ = w.Write([]byte(`{"ok": true, "result": {}}`))
+	}))
+	defer stub.Close()
+
+	logger := zerolog.Nop()
+	botServer := NewServer(config.Config{
+		JWTSecretKey:          testServer.config.JWTSecretKey,
+		TelegramBotToken:      "test-token",
+		TelegramBotUsername:   "GoFinanceBot",
+		TelegramAPIURL:        stub.URL,
+		TelegramWebhookSecret: "webhook-secret",
+		TelegramLinkCodeTTL:   time.Minute,
+	}, testServer.db, &logger)
+
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Owner", Email: "owner@test.com", PasswordHash: "hash"})
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+	testhelper.CreateAccount(t, botServer.router, token, dto.AccountRequest{
+		Name:           "Checking",
+		Type:           model.Checking,
+		InitialBalance: testhelper.Ptr(decimal.NewFromInt(1000)),
+	})
+
+	sendUpdate := func(secret, text string) int {
+		body := fmt.Sprintf(`{"update_id": 1, "message": {"message_id": 1, "chat": {"id": 777}, "text": %q}}`, text)
+		req, _ := http.NewRequest("POST", "/v1/telegram/webhook", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Telegram-Bot-Api-Secret-Token", secret)
+		recorder := httptest.NewRecorder()
+		botServer.router.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	t.Run("should link a chat and answer its commands", func(t *testing.T) {
+		// Arrange
+		recorder := testhelper.MakeAPIRequest(t, botServer.router, "POST", "/v1/telegram/link-codes", token, nil)
+		require.Equal(http.StatusCreated, recorder.Code)
+		var linkCode dto.TelegramLinkCodeResponse
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &linkCode))
+		require.NotNil(linkCode.DeepLink)
+		assert.Equal(t, "https://t.me/GoFinanceBot?start="+linkCode.Code, *linkCode.DeepLink)
+
+		// Act
+		linkStatus := sendUpdate("webhook-secret", "/start "+linkCode.Code)
+		expenseStatus := sendUpdate("webhook-secret", "/gasto 45,90 ifood")
+		recorderChats := testhelper.MakeAPIRequest(t, botServer.router, "GET", "/v1/telegram/chats", token, nil)
+		recorderTransactions := testhelper.MakeAPIRequest(t, botServer.router, "GET", "/v1/transactions", token, nil)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, linkStatus)
+		assert.Equal(t, http.StatusOK, expenseStatus)
+		require.Len(sent, 2)
+		assert.Contains(t, sent[0], "Chat vinculado")
+		assert.Equal(t, "✅ Gasto de R$ 45,90 registrado em Checking: ifood", sent[1])
+
+		require.Equal(http.StatusOK, recorderChats.Code)
+		assert.Contains(t, recorderChats.Body.String(), `"chat_id":777`)
+		require.Equal(http.StatusOK, recorderTransactions.Code)
+		assert.Contains(t, recorderTransactions.Body.String(), "ifood")
+	})
+
+	t.Run("should not reuse a link code", func(t *testing.T) {
+		// Arrange
+		recorder := testhelper.MakeAPIRequest(t, botServer.router, "POST", "/v1/telegram/link-codes", token, nil)
+		var linkCode dto.TelegramLinkCodeResponse
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &linkCode))
+		sendUpdate("webhook-secret", "/start "+linkCode.Code)
+		sent = nil
+
+		// Act
+		sendUpdate("webhook-secret", "/start "+linkCode.Code)
+
+		// Assert
+		require.Len(sent, 1)
+		assert.Contains(t, sent[0], "Código inválido")
+	})
+
+	t.Run("should reject webhook calls without the secret", func(t *testing.T) {
+		// Act
+		status := sendUpdate("wrong", "/saldo")
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, status)
+	})
+
+	t.Run("should unlink a chat", func(t *testing.T) {
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, botServer.router, "DELETE", "/v1/telegram/chats/777", token, nil)
+		recorderAgain := testhelper.MakeAPIRequest(t, botServer.router, "DELETE", "/v1/telegram/chats/777", token, nil)
+
+		// Assert
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.Equal(t, http.StatusNotFound, recorderAgain.Code)
+	})
+
+	t.Run("should not expose the bot routes when it is disabled", func(t *testing.T) {
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/telegram/link-codes", token, nil)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestChartRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Owner", Email: "owner@test.com", PasswordHash: "hash"})
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+	testhelper.CreateAccount(t, testServer.router, token, dto.AccountRequest{
+		Name:           "Checking",
+		Type:           model.Checking,
+		InitialBalance: testhelper.Ptr(decimal.NewFromInt(1000)),
+	})
+
+	for _, path := range []string{"cashflow", "categories", "net-worth", "budget"} {
+		t.Run("should render the "+path+" chart as SVG", func(t *testing.T) {
+			// Act
+			recorder := testhelper.MakeAPIRequest(t, testServer.router, "GET", "/v1/charts/"+path+".svg?theme=dark&locale=pt-BR&width=800", token, nil)
+
+			// Assert
+			require.Equal(http.StatusOK, recorder.Code)
+			assert.Equal(t, "image/svg+xml", recorder.Header().Get("Content-Type"))
+			assert.True(t, strings.HasPrefix(recorder.Body.String(), `<svg xmlns="http://www.w3.org/2000/svg" width="800"`))
+		})
+	}
+
+	t.Run("should reject an unknown theme", func(t *testing.T) {
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "GET", "/v1/charts/cashflow.svg?theme=neon", token, nil)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestReportQueryRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Owner", Email: "owner@test.com", PasswordHash: "hash"})
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+	accountId := testhelper.CreateAccount(t, testServer.router, token, dto.AccountRequest{
+		Name:           "Checking",
+		Type:           model.Checking,
+		InitialBalance: testhelper.Ptr(decimal.NewFromInt(1000)),
+	})
+	testhelper.CreateTransaction(t, testServer.router, token, accountId, "Groceries", "expense", "120.00")
+	testhelper.CreateTransaction(t, testServer.router, token, accountId, "Pharmacy", "expense", "30.00")
+	testhelper.CreateTransaction(t, testServer.router, token, accountId, "Salary", "income", "5000.00")
+
+	t.Run("should group transactions by type", func(t *testing.T) {
+		// Arrange
+		body, _ := json.Marshal(dto.ReportQueryRequest{GroupBy: []string{"type"}, Measures: []string{"sum", "count"}})
+
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/reports/query", token, bytes.NewBuffer(body))
+
+		// Assert
+		require.Equal(http.StatusOK, recorder.Code)
+		var response dto.ReportQueryResponse
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &response))
+		require.Len(response.Rows, 2)
+		assert.Equal(t, []string{"expense"}, response.Rows[0].Keys)
+		assert.True(t, response.Rows[0].Values[0].Equal(decimal.NewFromInt(150)))
+		assert.True(t, response.Rows[0].Values[1].Equal(decimal.NewFromInt(2)))
+		assert.False(t, response.Truncated)
+	})
+
+	t.Run("should return a pivot", func(t *testing.T) {
+		// Arrange
+		body, _ := json.Marshal(dto.ReportQueryRequest{
+			Filters:  dto.ReportQueryFilters{Types: []model.TransactionType{model.Expense}},
+			GroupBy:  []string{"account", "type"},
+			Measures: []string{"sum"},
+			Pivot:    true,
+		})
+
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/reports/query", token, bytes.NewBuffer(body))
+
+		// Assert
+		require.Equal(http.StatusOK, recorder.Code)
+		var response dto.ReportQueryResponse
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &response))
+		require.NotNil(response.Pivot)
+		assert.Equal(t, []string{"expense"}, response.Pivot.Columns)
+		require.Len(response.Pivot.Rows, 1)
+		assert.Equal(t, "Checking", response.Pivot.Rows[0].Key)
+		require.NotNil(response.Pivot.Rows[0].Id)
+		assert.Equal(t, accountId, *response.Pivot.Rows[0].Id)
+		assert.Equal(t, []*int64{nil}, response.Pivot.ColumnIds)
+	})
+
+	t.Run("should reject a dimension outside the whitelist", func(t *testing.T) {
+		// Arrange
+		body, _ := json.Marshal(dto.ReportQueryRequest{GroupBy: []string{"description"}, Measures: []string{"sum"}})
+
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/reports/query", token, bytes.NewBuffer(body))
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestFiscalMonthRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Owner", Email: "owner@test.com", PasswordHash: "hash"})
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+
+	t.Run("should save the fiscal month and use it in reports", func(t *testing.T) {
+		// Arrange
+		body, _ := json.Marshal(dto.FiscalMonthRequest{StartDay: 25, Label: model.FiscalMonthLabelEnd})
+
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "PUT", "/v1/users/me/fiscal-month", token, bytes.NewBuffer(body))
+		reportRecorder := testhelper.MakeAPIRequest(t, testServer.router, "GET", "/v1/reports/monthly?year=2025&month=3", token, nil)
+
+		// Assert
+		require.Equal(http.StatusOK, recorder.Code)
+		var user dto.UserResponse
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &user))
+		assert.Equal(t, 25, user.FiscalMonthStartDay)
+		assert.Equal(t, model.FiscalMonthLabelEnd, user.FiscalMonthLabel)
+
+		require.Equal(http.StatusOK, reportRecorder.Code)
+		var report dto.MonthlyReportResponse
+		require.NoError(json.Unmarshal(reportRecorder.Body.Bytes(), &report))
+		assert.Equal(t, time.Date(2025, time.February, 25, 0, 0, 0, 0, time.UTC), report.Period.Start.UTC())
+	})
+
+	t.Run("should reject a start day past the 31st", func(t *testing.T) {
+		// Arrange
+		body, _ := json.Marshal(dto.FiscalMonthRequest{StartDay: 32})
+
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "PUT", "/v1/users/me/fiscal-month", token, bytes.NewBuffer(body))
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestBillingCycleRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+	accountRepo := repository.NewAccountRepository(testServer.db)
+
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Owner", Email: "owner@test.com", PasswordHash: "hash"})
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+	cardId, _ := accountRepo.Create(ctx, model.Account{
+		UserId: userId, Name: "Card", Type: model.CreditCard, StatementClosingDay: testhelper.Ptr(10), PaymentDueDay: testhelper.Ptr(18),
+	})
+	// The card has closed on the 10th since 2024.
+	_, err := testServer.db.Exec(`UPDATE billing_cycles SET effective_from = '2024-01-01' WHERE account_id = $1`, cardId)
+	require.NoError(err)
+
+	t.Run("should keep past statements when the closing day changes", func(t *testing.T) {
+		// Arrange
+		body, _ := json.Marshal(dto.AccountRequest{
+			Name:                      "Card",
+			Type:                      model.CreditCard,
+			InitialBalance:            &decimal.Zero,
+			CreditLimit:               testhelper.Ptr(decimal.NewFromInt(5000)),
+			StatementClosingDay:       testhelper.Ptr(20),
+			PaymentDueDay:             testhelper.Ptr(28),
+			BillingCycleEffectiveFrom: "2025-03-15",
+		})
+		statement := func(month int) dto.StatementResponse {
+			recorder := testhelper.MakeAPIRequest(t, testServer.router, "GET", fmt.Sprintf("/v1/accounts/%d/statement?year=2025&month=%d", cardId, month), token, nil)
+			require.Equal(http.StatusOK, recorder.Code)
+			var resp dto.StatementResponse
+			require.NoError(json.Unmarshal(recorder.Body.Bytes(), &resp))
+			return resp
+		}
+
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "PUT", fmt.Sprintf("/v1/accounts/%d", cardId), token, bytes.NewBuffer(body))
+
+		// Assert
+		require.Equal(http.StatusOK, recorder.Code)
+		february, march, april := statement(2), statement(3), statement(4)
+		assert.Equal(t, time.Date(2025, time.February, 10, 0, 0, 0, 0, time.UTC), february.Period.End.UTC())
+		assert.Equal(t, time.Date(2025, time.February, 18, 0, 0, 0, 0, time.UTC), february.PaymentDueDate.UTC())
+		// March closed on the 10th, before the change.
+		assert.Equal(t, time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC), march.Period.End.UTC())
+		assert.Equal(t, time.Date(2025, time.March, 18, 0, 0, 0, 0, time.UTC), march.PaymentDueDate.UTC())
+		// April is the transition statement, from the old closing day to the new one.
+		assert.Equal(t, time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC), april.Period.Start.UTC())
+		assert.Equal(t, time.Date(2025, time.April, 20, 0, 0, 0, 0, time.UTC), april.Period.End.UTC())
+	})
+}
+
+func TestCardRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+	accountRepo := repository.NewAccountRepository(testServer.db)
+
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Owner", Email: "owner@test.com", PasswordHash: "hash"})
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+	accountId, _ := accountRepo.Create(ctx, model.Account{
+		UserId: userId, Name: "Card", Type: model.CreditCard, CreditLimit: testhelper.Ptr(decimal.NewFromInt(5000)),
+		StatementClosingDay: testhelper.Ptr(10), PaymentDueDay: testhelper.Ptr(18),
+	})
+	checkingId, _ := accountRepo.Create(ctx, model.Account{UserId: userId, Name: "Checking", Type: model.Checking})
+	cardBody, _ := json.Marshal(dto.CardRequest{HolderName: "Ana", LastFour: "9876", Virtual: true, SpendLimit: testhelper.Ptr(decimal.NewFromInt(100))})
+	var card model.Card
+
+	t.Run("should add a card to a credit card account only", func(t *testing.T) {
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "POST", fmt.Sprintf("/v1/accounts/%d/cards", accountId), token, bytes.NewBuffer(cardBody))
+		require.Equal(http.StatusCreated, recorder.Code)
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &card))
+		assert.Equal(t, "9876", card.LastFour)
+
+		recorder = testhelper.MakeAPIRequest(t, testServer.router, "POST", fmt.Sprintf("/v1/accounts/%d/cards", checkingId), token, bytes.NewBuffer(cardBody))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("should enforce the card's limit and break the statement down by card", func(t *testing.T) {
+		require.NotZero(card.Id, "card must be created first")
+		purchase := func(amount int64, cardId *int64) int {
+			body, _ := json.Marshal(dto.CreateTransactionRequest{
+				Description: "Purchase",
+				Amount:      decimal.NewFromInt(amount),
+				Date:        time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC),
+				Type:        model.Expense,
+				AccountId:   accountId,
+				CardId:      cardId,
+			})
+			return testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/transactions", token, bytes.NewBuffer(body)).Code
+		}
+
+		require.Equal(http.StatusCreated, purchase(70, &card.Id))
+		require.Equal(http.StatusBadRequest, purchase(40, &card.Id), "the card's limit is 100 per statement")
+		require.Equal(http.StatusCreated, purchase(40, nil))
+
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "GET", fmt.Sprintf("/v1/accounts/%d/statement?year=2025&month=6", accountId), token, nil)
+		require.Equal(http.StatusOK, recorder.Code)
+		var statement dto.StatementResponse
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &statement))
+		require.Len(statement.Cards, 2)
+		assert.Nil(t, statement.Cards[0].CardId)
+		assert.Equal(t, "Ana", *statement.Cards[1].HolderName)
+		assert.True(t, decimal.NewFromInt(70).Equal(statement.Cards[1].Total))
+	})
+}
+
+func TestForeignPurchaseRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+	accountRepo := repository.NewAccountRepository(testServer.db)
+
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Traveller", Email: "traveller@test.com", PasswordHash: "hash"})
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+	cardId, _ := accountRepo.Create(ctx, model.Account{
+		UserId: userId, Name: "Card", Type: model.CreditCard, CreditLimit: testhelper.Ptr(decimal.NewFromInt(5000)),
+		StatementClosingDay: testhelper.Ptr(10), PaymentDueDay: testhelper.Ptr(18),
+	})
+
+	t.Run("should post the IOF and show the purchase in the statement", func(t *testing.T) {
+		// Arrange
+		body, _ := json.Marshal(dto.CreateTransactionRequest{
+			Description:      "Hotel",
+			Date:             time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC),
+			Type:             model.Expense,
+			AccountId:        cardId,
+			OriginalCurrency: testhelper.Ptr("USD"),
+			OriginalAmount:   testhelper.Ptr(decimal.NewFromInt(100)),
+			ExchangeRate:     testhelper.Ptr(decimal.NewFromInt(5)),
+			SpreadRate:       testhelper.Ptr(decimal.NewFromInt(4)),
+		})
+
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/transactions", token, bytes.NewBuffer(body))
+
+		// Assert
+		require.Equal(http.StatusCreated, recorder.Code)
+		recorder = testhelper.MakeAPIRequest(t, testServer.router, "GET", fmt.Sprintf("/v1/accounts/%d/statement?year=2025&month=6", cardId), token, nil)
+		require.Equal(http.StatusOK, recorder.Code)
+		var statement dto.StatementResponse
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &statement))
+		require.Len(statement.Transactions, 2)
+		require.Len(statement.ForeignPurchases, 1)
+		usd := statement.ForeignPurchases[0]
+		assert.Equal(t, "USD", usd.Currency)
+		assert.True(t, decimal.NewFromInt(520).Equal(usd.Total))
+		// IOF of 3.5% on 520.00.
+		assert.True(t, decimal.RequireFromString("18.20").Equal(usd.IOF))
+		assert.True(t, decimal.RequireFromString("538.20").Equal(statement.StatementTotal))
+	})
+
+	t.Run("should reject foreign purchases outside credit cards", func(t *testing.T) {
+		checkingId, _ := accountRepo.Create(ctx, model.Account{UserId: userId, Name: "Checking", Type: model.Checking})
+		body, _ := json.Marshal(dto.CreateTransactionRequest{
+			Description:      "Hotel",
+			Date:             time.Now(),
+			Type:             model.Expense,
+			AccountId:        checkingId,
+			OriginalCurrency: testhelper.Ptr("USD"),
+			OriginalAmount:   testhelper.Ptr(decimal.NewFromInt(100)),
+			ExchangeRate:     testhelper.Ptr(decimal.NewFromInt(5)),
+		})
+
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/transactions", token, bytes.NewBuffer(body))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestOverdraftRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+	userId, _ := userRepo.Create(context.Background(), model.User{Name: "Overdraft User", Email: "overdraft@test.com", PasswordHash: "hash"})
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+
+	// Arrange: a checking account with 100 and 500 of overdraft.
+	body, _ := json.Marshal(dto.AccountRequest{
+		Name:                 "Checking",
+		Type:                 "checking",
+		InitialBalance:       testhelper.Ptr(decimal.NewFromInt(100)),
+		OverdraftLimit:       testhelper.Ptr(decimal.NewFromInt(500)),
+		OverdraftMonthlyRate: testhelper.Ptr(decimal.RequireFromString("7.5")),
+	})
+	recorder := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/accounts", token, bytes.NewBuffer(body))
+	require.Equal(http.StatusCreated, recorder.Code)
+	var account dto.AccountResponse
+	require.NoError(json.Unmarshal(recorder.Body.Bytes(), &account))
+
+	expense := func(amount int64) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(dto.CreateTransactionRequest{
+			Description: "Rent",
+			Amount:      decimal.NewFromInt(amount),
+			Date:        time.Now(),
+			Type:        model.Expense,
+			AccountId:   account.Id,
+		})
+		return testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/transactions", token, bytes.NewBuffer(body))
+	}
+
+	t.Run("should return the overdraft of the account", func(t *testing.T) {
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "GET", fmt.Sprintf("/v1/accounts/%d", account.Id), token, nil)
+
+		require.Equal(http.StatusOK, recorder.Code)
+		var got dto.AccountResponse
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &got))
+		require.NotNil(got.OverdraftLimit)
+		assert.True(t, decimal.NewFromInt(500).Equal(*got.OverdraftLimit))
+	})
+
+	t.Run("should reject an expense beyond the overdraft", func(t *testing.T) {
+		recorder := expense(601)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("should accept an expense within the overdraft", func(t *testing.T) {
+		recorder := expense(600)
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+	})
+
+	t.Run("should reject an overdraft on other account types", func(t *testing.T) {
+		body, _ := json.Marshal(dto.AccountRequest{
+			Name:           "Savings",
+			Type:           "savings",
+			OverdraftLimit: testhelper.Ptr(decimal.NewFromInt(500)),
+		})
+
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/accounts", token, bytes.NewBuffer(body))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestYieldRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+	adminId, _ := userRepo.Create(ctx, model.User{Name: "Admin", Email: "yield-admin@test.com", PasswordHash: "hash"})
+	_, err := userRepo.PromoteToAdmin(ctx, []string{"yield-admin@test.com"})
+	require.NoError(err)
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Saver", Email: "saver@test.com", PasswordHash: "hash"})
+	adminToken := testhelper.GenerateTestToken(t, adminId, testServer.config.JWTSecretKey)
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+
+	t.Run("should let admins load the CDI", func(t *testing.T) {
+		// Arrange
+		body, _ := json.Marshal(dto.IndexRatesRequest{Rates: []dto.IndexRateRequest{
+			{Date: "2025-07-03", AnnualRate: decimal.RequireFromString("14.90")},
+			{Date: "2025-07-04", AnnualRate: decimal.RequireFromString("14.90")},
+		}})
+
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "PUT", "/v1/admin/index-rates/cdi", adminToken, bytes.NewBuffer(body))
+
+		// Assert
+		require.Equal(http.StatusNoContent, recorder.Code)
+		recorder = testhelper.MakeAPIRequest(t, testServer.router, "GET", "/v1/index-rates/cdi?from=2025-07-01&to=2025-07-31", token, nil)
+		require.Equal(http.StatusOK, recorder.Code)
+		var rates []dto.IndexRateResponse
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &rates))
+		require.Len(rates, 2)
+		assert.Equal(t, "2025-07-03", rates[0].Date)
+
+		recorder = testhelper.MakeAPIRequest(t, testServer.router, "GET", "/v1/admin/audit-log?limit=2", adminToken, nil)
+		require.Equal(http.StatusOK, recorder.Code)
+		var entries []dto.AuditLogEntryResponse
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &entries))
+		require.Len(entries, 2)
+		assert.Equal(t, service.AuditActionLoadIndexRates, entries[1].Action)
+	})
+
+	t.Run("should not let users load index rates", func(t *testing.T) {
+		body, _ := json.Marshal(dto.IndexRatesRequest{Rates: []dto.IndexRateRequest{{Date: "2025-07-03", AnnualRate: decimal.NewFromInt(1)}}})
+
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "PUT", "/v1/admin/index-rates/cdi", token, bytes.NewBuffer(body))
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+
+	t.Run("should reject unknown indexes", func(t *testing.T) {
+		body, _ := json.Marshal(dto.IndexRatesRequest{Rates: []dto.IndexRateRequest{{Date: "2025-07-03", AnnualRate: decimal.NewFromInt(1)}}})
+
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "PUT", "/v1/admin/index-rates/ipca", adminToken, bytes.NewBuffer(body))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("should configure the yield of savings accounts", func(t *testing.T) {
+		// Arrange
+		cdi, monthly := model.YieldCDI, model.YieldMonthly
+		body, _ := json.Marshal(dto.AccountRequest{
+			Name:             "CDB",
+			Type:             model.Savings,
+			InitialBalance:   testhelper.Ptr(decimal.NewFromInt(10000)),
+			YieldType:        &cdi,
+			YieldRate:        testhelper.Ptr(decimal.NewFromInt(110)),
+			YieldFrequency:   &monthly,
+			YieldStartDate:   "2025-07-01",
+			YieldWithholdsIR: true,
+		})
+
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/accounts", token, bytes.NewBuffer(body))
+
+		// Assert
+		require.Equal(http.StatusCreated, recorder.Code)
+		var created dto.AccountResponse
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &created))
+		recorder = testhelper.MakeAPIRequest(t, testServer.router, "GET", fmt.Sprintf("/v1/accounts/%d", created.Id), token, nil)
+		require.Equal(http.StatusOK, recorder.Code)
+		var account dto.AccountResponse
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &account))
+		require.NotNil(account.YieldType)
+		assert.Equal(t, model.YieldCDI, *account.YieldType)
+		assert.Equal(t, "2025-07-01", *account.YieldStartDate)
+		assert.True(t, account.YieldWithholdsIR)
+
+		recorder = testhelper.MakeAPIRequest(t, testServer.router, "GET", fmt.Sprintf("/v1/accounts/%d/yields", created.Id), token, nil)
+		require.Equal(http.StatusOK, recorder.Code)
+		assert.JSONEq(t, "[]", recorder.Body.String())
+	})
+
+	t.Run("should reject a yield outside savings accounts", func(t *testing.T) {
+		fixed := model.YieldFixed
+		body, _ := json.Marshal(dto.AccountRequest{
+			Name:           "Checking",
+			Type:           model.Checking,
+			InitialBalance: testhelper.Ptr(decimal.Zero),
+			YieldType:      &fixed,
+			YieldRate:      testhelper.Ptr(decimal.NewFromInt(12)),
+		})
+
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/accounts", token, bytes.NewBuffer(body))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestSavingsRuleRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Saver", Email: "rules@test.com", PasswordHash: "hash"})
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+	salaryId, err := repository.NewCategoryRepository(testServer.db).Create(ctx, model.Category{UserId: userId, Name: "Salary", Type: model.Income})
+	require.NoError(err)
+	checkingId := testhelper.CreateAccount(t, testServer.router, token, dto.AccountRequest{
+		Name: "Checking", Type: model.Checking, InitialBalance: testhelper.Ptr(decimal.NewFromInt(1000)),
+	})
+	savingsId := testhelper.CreateAccount(t, testServer.router, token, dto.AccountRequest{
+		Name: "Savings", Type: model.Savings, InitialBalance: testhelper.Ptr(decimal.Zero),
+	})
+
+	createRule := func(req dto.SavingsRuleRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		return testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/savings-rules", token, bytes.NewBuffer(body))
+	}
+
+	t.Run("should save a percentage of each salary", func(t *testing.T) {
+		// Arrange
+		recorder := createRule(dto.SavingsRuleRequest{
+			Type: model.SavingsPercentOfIncome, SavingsAccountId: savingsId, CategoryId: &salaryId, Percent: testhelper.Ptr(decimal.NewFromInt(10)),
+		})
+		require.Equal(http.StatusCreated, recorder.Code)
+		var rule model.SavingsRule
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &rule))
+		body, _ := json.Marshal(dto.CreateTransactionRequest{
+			Description: "Salary", Amount: decimal.NewFromInt(5000), Date: time.Now().UTC(), Type: model.Income, AccountId: checkingId, CategoryId: &salaryId,
+		})
+
+		// Act
+		recorder = testhelper.MakeAPIRequest(t, testServer.router, "POST", "/v1/transactions", token, bytes.NewBuffer(body))
+
+		// Assert: 500 of the 5,000 went into savings.
+		require.Equal(http.StatusCreated, recorder.Code)
+		assert.True(t, decimal.NewFromInt(500).Equal(testhelper.GetAccountBalance(t, testServer.router, token, savingsId)))
+		assert.True(t, decimal.NewFromInt(5500).Equal(testhelper.GetAccountBalance(t, testServer.router, token, checkingId)))
+		recorder = testhelper.MakeAPIRequest(t, testServer.router, "GET", fmt.Sprintf("/v1/savings-rules/%d/runs", rule.Id), token, nil)
+		require.Equal(http.StatusOK, recorder.Code)
+		var runs []model.SavingsRuleRun
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &runs))
+		require.Len(runs, 1)
+		assert.NotNil(t, runs[0].TransferId)
+	})
+
+	t.Run("should keep round-ups pending until their day is settled", func(t *testing.T) {
+		// Arrange
+		recorder := createRule(dto.SavingsRuleRequest{Type: model.SavingsRoundUp, SavingsAccountId: savingsId, AccountId: &checkingId})
+		require.Equal(http.StatusCreated, recorder.Code)
+		var rule model.SavingsRule
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &rule))
+		require.NotNil(rule.FundingAccountId)
+		assert.Equal(t, checkingId, *rule.FundingAccountId)
+
+		// Act
+		testhelper.CreateTransaction(t, testServer.router, token, checkingId, "Coffee", "expense", "7.30")
+
+		// Assert
+		recorder = testhelper.MakeAPIRequest(t, testServer.router, "GET", fmt.Sprintf("/v1/savings-rules/%d/runs", rule.Id), token, nil)
+		require.Equal(http.StatusOK, recorder.Code)
+		var runs []model.SavingsRuleRun
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &runs))
+		require.Len(runs, 1)
+		assert.True(t, decimal.RequireFromString("0.70").Equal(runs[0].Amount))
+		assert.Nil(t, runs[0].TransferId)
+	})
+
+	t.Run("should reject a rule saving into a checking account", func(t *testing.T) {
+		recorder := createRule(dto.SavingsRuleRequest{
+			Type: model.SavingsPercentOfIncome, SavingsAccountId: checkingId, CategoryId: &salaryId, Percent: testhelper.Ptr(decimal.NewFromInt(10)),
+		})
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("should delete a rule", func(t *testing.T) {
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "GET", "/v1/savings-rules", token, nil)
+		require.Equal(http.StatusOK, recorder.Code)
+		var rules []model.SavingsRule
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &rules))
+		require.Len(rules, 2)
+
+		recorder = testhelper.MakeAPIRequest(t, testServer.router, "DELETE", fmt.Sprintf("/v1/savings-rules/%d", rules[0].Id), token, nil)
+
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+		recorder = testhelper.MakeAPIRequest(t, testServer.router, "DELETE", fmt.Sprintf("/v1/savings-rules/%d", rules[0].Id), token, nil)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestAccountDeletionImpactRoutes(t *testing.T) {
+	require := require.New(t)
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	testhelper.TruncateTables(t, testServer.db)
+	userRepo := repository.NewUserRepository(testServer.db)
+	userId, _ := userRepo.Create(ctx, model.User{Name: "Closer", Email: "closer@test.com", PasswordHash: "hash"})
+	token := testhelper.GenerateTestToken(t, userId, testServer.config.JWTSecretKey)
+
+	createAccounts := func() (int64, int64) {
+		closingId := testhelper.CreateAccount(t, testServer.router, token, dto.AccountRequest{
+			Name: "Old Checking", Type: model.Checking, InitialBalance: testhelper.Ptr(decimal.NewFromInt(1000)),
+		})
+		savingsId := testhelper.CreateAccount(t, testServer.router, token, dto.AccountRequest{
+			Name: "Savings", Type: model.Savings, InitialBalance: testhelper.Ptr(decimal.NewFromInt(200)),
+		})
+		testhelper.CreateTransaction(t, testServer.router, token, closingId, "Groceries", "expense", "50")
+		testhelper.CreateTransfer(t, testServer.router, token, "Save", "300", closingId, savingsId)
+		testhelper.CreateTransfer(t, testServer.router, token, "Withdraw", "100", savingsId, closingId)
+		return closingId, savingsId
+	}
+
+	t.Run("should preview the balance changes on other accounts", func(t *testing.T) {
+		// Arrange
+		closingId, savingsId := createAccounts()
+
+		// Act
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "GET", fmt.Sprintf("/v1/accounts/%d/deletion-impact", closingId), token, nil)
+
+		// Assert
+		require.Equal(http.StatusOK, recorder.Code)
+		var impact dto.AccountDeletionImpactResponse
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &impact))
+		assert.Equal(t, "delete", impact.Policy)
+		assert.Equal(t, int64(3), impact.DeletedTransactions)
+		assert.Len(t, impact.Transfers, 2)
+		require.Len(impact.BalanceChanges, 1)
+		assert.Equal(t, savingsId, impact.BalanceChanges[0].AccountId)
+		assert.True(t, decimal.NewFromInt(-200).Equal(impact.BalanceChanges[0].Change))
+		assert.True(t, decimal.NewFromInt(200).Equal(impact.BalanceChanges[0].BalanceAfter))
+	})
+
+	t.Run("should keep the balances of other accounts when keeping counterparts", func(t *testing.T) {
+		// Arrange
+		closingId, savingsId := createAccounts()
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "GET", fmt.Sprintf("/v1/accounts/%d/deletion-impact?policy=keep_counterparts", closingId), token, nil)
+		require.Equal(http.StatusOK, recorder.Code)
+		var impact dto.AccountDeletionImpactResponse
+		require.NoError(json.Unmarshal(recorder.Body.Bytes(), &impact))
+		assert.Equal(t, int64(1), impact.DeletedTransactions)
+		assert.Empty(t, impact.BalanceChanges)
+
+		// Act
+		recorder = testhelper.MakeAPIRequest(t, testServer.router, "DELETE", fmt.Sprintf("/v1/accounts/%d?policy=keep_counterparts", closingId), token, nil)
+
+		// Assert
+		require.Equal(http.StatusNoContent, recorder.Code)
+		testhelper.AssertAccountNotFound(t, testServer.router, token, closingId)
+		assert.True(t, decimal.NewFromInt(400).Equal(testhelper.GetAccountBalance(t, testServer.router, token, savingsId)))
+	})
+
+	t.Run("should reject an unknown policy", func(t *testing.T) {
+		closingId, _ := createAccounts()
+
+		recorder := testhelper.MakeAPIRequest(t, testServer.router, "DELETE", fmt.Sprintf("/v1/accounts/%d?policy=archive", closingId), token, nil)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
 // TestBusinessScenarios validates complex, multi-step user workflows.
 func TestBusinessScenarios(t *testing.T) {
 	require := require.New(t)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"sort"
 	"time"
 
 	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
@@ -13,12 +14,70 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+var (
+	ErrStatementRequiresCreditCard = errors.New("operation only valid for credit card accounts")
+	ErrBillingCycleRequired        = errors.New("credit card account must have billing cycle data")
+	ErrInvalidDeletionPolicy       = errors.New("deletion policy must be delete or keep_counterparts")
+)
+
+// AccountDeletionPolicy decides what happens to the transfers between an
+// account being deleted and the user's other accounts.
+type AccountDeletionPolicy string
+
+const (
+	// DeleteTransfers deletes the transfers with the account, which changes
+	// the balances of the other accounts. It is the default.
+	DeleteTransfers AccountDeletionPolicy = "delete"
+	// KeepCounterparts turns each transfer into income or an expense of the
+	// other account, so its balance does not change.
+	KeepCounterparts AccountDeletionPolicy = "keep_counterparts"
+)
+
+// AccountDeletionImpact is what deleting an account would do to the rest of
+// the user's transactions under a policy.
+type AccountDeletionImpact struct {
+	Policy AccountDeletionPolicy
+	// DeletedTransactions is how many transactions would be deleted with the account.
+	DeletedTransactions int64
+	// Transfers are the transfers between the account and other accounts.
+	Transfers []CounterpartTransfer
+	// BalanceChanges lists the other accounts whose balance would change.
+	BalanceChanges []AccountBalanceChange
+}
+
+// CounterpartTransfer is a transfer to or from the account being deleted.
+// Outcome is what it becomes: deleted, or income or an expense of the
+// counterpart account.
+type CounterpartTransfer struct {
+	Transaction          model.Transaction
+	CounterpartAccountId int64
+	Outcome              string
+}
+
+// AccountBalanceChange is how much deleting an account changes the balance
+// of another account.
+type AccountBalanceChange struct {
+	AccountId      int64
+	AccountName    string
+	CurrentBalance decimal.Decimal
+	Change         decimal.Decimal
+}
+
 // StatementPeriod represents the start and end dates of a statement period
 type StatementPeriod struct {
 	Start time.Time
 	End   time.Time
 }
 
+// CardStatementTotal is what one card of the account spent in a statement.
+// Purchases made without a card have no CardId.
+type CardStatementTotal struct {
+	CardId     *int64
+	HolderName *string
+	LastFour   *string
+	Total      decimal.Decimal
+}
+
 // StatementDetails is an internal struct to hold all calculated information for a statement.
 type StatementDetails struct {
 	AccountName     string
@@ -26,21 +85,29 @@ type StatementDetails struct {
 	PaymentDueDate  time.Time
 	StatementPeriod StatementPeriod
 	Transactions    []model.Transaction
+	Cards           []CardStatementTotal
+	// ForeignPurchases groups the purchases made abroad by currency.
+	ForeignPurchases []ForeignCurrencyTotal
 }
 
 type AccountService struct {
 	repo            repository.AccountRepository
 	transactionRepo repository.TransactionRepository
+	quotas          *QuotaService
 }
 
-func NewAccountService(repo repository.AccountRepository, transactionRepo repository.TransactionRepository) *AccountService {
+func NewAccountService(repo repository.AccountRepository, transactionRepo repository.TransactionRepository, quotas *QuotaService) *AccountService {
 	return &AccountService{
 		repo:            repo,
 		transactionRepo: transactionRepo,
+		quotas:          quotas,
 	}
 }
 
 func (s *AccountService) CreateAccount(ctx context.Context, acc model.Account) (int64, error) {
+	if err := s.quotas.CheckAccountCreation(ctx, acc.UserId); err != nil {
+		return 0, err
+	}
 	return s.repo.Create(ctx, acc)
 }
 
@@ -111,7 +178,10 @@ func (s *AccountService) ListAccountsByUserId(ctx context.Context, userId int64)
 	return accounts, nil
 }
 
-func (s *AccountService) UpdateAccount(ctx context.Context, acc model.Account) (*model.Account, error) {
+// UpdateAccount saves the account. When a card's closing or due day changes,
+// the new days take effect for the statements closing on or after
+// cycleEffectiveFrom, and earlier statements keep the days they closed with.
+func (s *AccountService) UpdateAccount(ctx context.Context, acc model.Account, cycleEffectiveFrom time.Time) (*model.Account, error) {
 	logger := zerolog.Ctx(ctx)
 
 	err := s.repo.Update(ctx, acc)
@@ -119,6 +189,13 @@ func (s *AccountService) UpdateAccount(ctx context.Context, acc model.Account) (
 		return nil, err
 	}
 
+	if acc.Type == model.CreditCard && acc.StatementClosingDay != nil && acc.PaymentDueDay != nil {
+		if err := s.recordBillingCycle(ctx, acc, cycleEffectiveFrom); err != nil {
+			logger.Error().Err(err).Int64("account_id", acc.Id).Msg("failed to record billing cycle")
+			return nil, err
+		}
+	}
+
 	balance, err := s.repo.GetCurrentBalance(ctx, acc.Id, acc.UserId)
 	if err != nil {
 		logger.Error().Err(err).Int64("account_id", acc.Id).Msg("failed to calculate account balance")
@@ -134,10 +211,45 @@ func (s *AccountService) UpdateAccount(ctx context.Context, acc model.Account) (
 	return account, nil
 }
 
-func (s *AccountService) DeleteAccount(ctx context.Context, id, userId int64) error {
+// recordBillingCycle adds a version of the card's billing cycle when its days
+// differ from the version in force on effectiveFrom.
+func (s *AccountService) recordBillingCycle(ctx context.Context, acc model.Account, effectiveFrom time.Time) error {
+	effectiveFrom = time.Date(effectiveFrom.Year(), effectiveFrom.Month(), effectiveFrom.Day(), 0, 0, 0, 0, time.UTC)
+
+	cycles, err := s.repo.ListBillingCycles(ctx, acc.Id, acc.UserId)
+	if err != nil {
+		return err
+	}
+	if len(cycles) > 0 {
+		current := cycles[0]
+		for _, cycle := range cycles {
+			if !cycle.EffectiveFrom.After(effectiveFrom) {
+				current = cycle
+			}
+		}
+		if current.StatementClosingDay == *acc.StatementClosingDay && current.PaymentDueDay == *acc.PaymentDueDay {
+			return nil
+		}
+	}
+
+	return s.repo.SaveBillingCycle(ctx, model.BillingCycle{
+		AccountId:           acc.Id,
+		StatementClosingDay: *acc.StatementClosingDay,
+		PaymentDueDay:       *acc.PaymentDueDay,
+		EffectiveFrom:       effectiveFrom,
+	}, acc.UserId)
+}
+
+// DeleteAccount removes an account with its transactions. Transfers to or from
+// other accounts are handled by the policy; an empty policy deletes them.
+func (s *AccountService) DeleteAccount(ctx context.Context, id, userId int64, policy AccountDeletionPolicy) error {
 	logger := zerolog.Ctx(ctx)
 
-	_, err := s.repo.GetById(ctx, id, userId)
+	policy, err := checkDeletionPolicy(policy)
+	if err != nil {
+		return err
+	}
+	_, err = s.repo.GetById(ctx, id, userId)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return errors.New("account not found to be deleted")
@@ -145,7 +257,11 @@ func (s *AccountService) DeleteAccount(ctx context.Context, id, userId int64) er
 		return err
 	}
 
-	err = s.transactionRepo.DeleteByAccountId(ctx, userId, id)
+	if policy == KeepCounterparts {
+		err = s.transactionRepo.DeleteByAccountIdKeepingCounterparts(ctx, userId, id)
+	} else {
+		err = s.transactionRepo.DeleteByAccountId(ctx, userId, id)
+	}
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to delete transactions for account")
 		return errors.New("could not delete associated transactions")
@@ -160,6 +276,90 @@ func (s *AccountService) DeleteAccount(ctx context.Context, id, userId int64) er
 	return nil
 }
 
+// GetDeletionImpact previews what deleting an account under the policy would
+// do: how many transactions go with it, what happens to its transfers and how
+// the balances of the other accounts change.
+func (s *AccountService) GetDeletionImpact(ctx context.Context, id, userId int64, policy AccountDeletionPolicy) (*AccountDeletionImpact, error) {
+	policy, err := checkDeletionPolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetById(ctx, id, userId); err != nil {
+		return nil, err
+	}
+	count, err := s.transactionRepo.CountByAccountId(ctx, userId, id)
+	if err != nil {
+		return nil, err
+	}
+	transfers, err := s.transactionRepo.ListTransfersByAccountId(ctx, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	impact := &AccountDeletionImpact{
+		Policy:              policy,
+		DeletedTransactions: count,
+		Transfers:           make([]CounterpartTransfer, 0, len(transfers)),
+		BalanceChanges:      []AccountBalanceChange{},
+	}
+	changes := map[int64]decimal.Decimal{}
+	var counterparts []int64
+	for _, transfer := range transfers {
+		// Deleting a transfer gives the money back to the source and takes it
+		// from the destination.
+		counterpart, change, outcome := transfer.AccountId, transfer.Amount, string(model.Expense)
+		if transfer.AccountId == id {
+			counterpart, change, outcome = *transfer.DestinationAccountId, transfer.Amount.Neg(), string(model.Income)
+		}
+		if policy == KeepCounterparts {
+			impact.DeletedTransactions--
+		} else {
+			outcome = "deleted"
+			if _, ok := changes[counterpart]; !ok {
+				counterparts = append(counterparts, counterpart)
+			}
+			changes[counterpart] = changes[counterpart].Add(change)
+		}
+		impact.Transfers = append(impact.Transfers, CounterpartTransfer{
+			Transaction:          transfer,
+			CounterpartAccountId: counterpart,
+			Outcome:              outcome,
+		})
+	}
+
+	for _, accountId := range counterparts {
+		if changes[accountId].IsZero() {
+			continue
+		}
+		account, err := s.repo.GetById(ctx, accountId, userId)
+		if err != nil {
+			return nil, err
+		}
+		balance, err := s.repo.GetCurrentBalance(ctx, accountId, userId)
+		if err != nil {
+			return nil, err
+		}
+		impact.BalanceChanges = append(impact.BalanceChanges, AccountBalanceChange{
+			AccountId:      accountId,
+			AccountName:    account.Name,
+			CurrentBalance: balance,
+			Change:         changes[accountId],
+		})
+	}
+	return impact, nil
+}
+
+// checkDeletionPolicy validates a policy, defaulting to DeleteTransfers.
+func checkDeletionPolicy(policy AccountDeletionPolicy) (AccountDeletionPolicy, error) {
+	switch policy {
+	case "":
+		return DeleteTransfers, nil
+	case DeleteTransfers, KeepCounterparts:
+		return policy, nil
+	}
+	return "", ErrInvalidDeletionPolicy
+}
+
 // GetStatementDetails calculates the statement period and fetches related transactions.
 func (s *AccountService) GetStatementDetails(ctx context.Context, userId, accountId int64, targetYear, targetMonth int) (*StatementDetails, error) {
 	logger := zerolog.Ctx(ctx)
@@ -172,15 +372,21 @@ func (s *AccountService) GetStatementDetails(ctx context.Context, userId, accoun
 
 	// Validate account type and billing cycle data
 	if account.Type != model.CreditCard {
-		return nil, errors.New("operation only valid for credit card accounts")
+		return nil, ErrStatementRequiresCreditCard
 	}
 	if account.StatementClosingDay == nil || account.PaymentDueDay == nil {
-		return nil, errors.New("credit card account must have billing cycle data")
+		return nil, ErrBillingCycleRequired
+	}
+
+	cycles, err := s.repo.ListBillingCycles(ctx, accountId, userId)
+	if err != nil {
+		return nil, err
 	}
 
 	// Calculate statement period dates
-	statementPeriod := s.calculateStatementPeriod(targetYear, targetMonth, *account.StatementClosingDay)
-	paymentDueDate := s.calculatePaymentDueDate(targetYear, targetMonth, *account.PaymentDueDay)
+	statementPeriod := s.calculateStatementPeriod(account, cycles, targetYear, targetMonth)
+	_, paymentDueDay := s.billingCycleFor(account, cycles, targetYear, time.Month(targetMonth))
+	paymentDueDate := s.calculatePaymentDueDate(targetYear, targetMonth, paymentDueDay)
 
 	logger.Debug().
 		Str("start_period", statementPeriod.Start.Format("2006-01-02")).
@@ -205,22 +411,73 @@ func (s *AccountService) GetStatementDetails(ctx context.Context, userId, accoun
 		PaymentDueDate:  paymentDueDate,
 		StatementPeriod: statementPeriod,
 		Transactions:    transactions,
+		Cards:           s.calculateCardTotals(transactions),
+
+		ForeignPurchases: groupForeignPurchases(transactions),
 	}
 
 	return statementDetails, nil
 }
 
-// calculateStatementPeriod calculates the start and end dates for a statement period
-func (s *AccountService) calculateStatementPeriod(targetYear, targetMonth, closingDay int) StatementPeriod {
+// StatementPeriodOf returns the period of the card statement that a purchase
+// made at date falls in.
+func (s *AccountService) StatementPeriodOf(ctx context.Context, account *model.Account, date time.Time) (StatementPeriod, error) {
+	if account.StatementClosingDay == nil || account.PaymentDueDay == nil {
+		return StatementPeriod{}, ErrBillingCycleRequired
+	}
+	cycles, err := s.repo.ListBillingCycles(ctx, account.Id, account.UserId)
+	if err != nil {
+		return StatementPeriod{}, err
+	}
+
+	year, month := date.Year(), int(date.Month())
+	period := s.calculateStatementPeriod(account, cycles, year, month)
+	if date.After(period.End) {
+		next := time.Date(year, time.Month(month)+1, 1, 0, 0, 0, 0, time.UTC)
+		period = s.calculateStatementPeriod(account, cycles, next.Year(), int(next.Month()))
+	} else if date.Before(period.Start) {
+		previousMonth, previousYear := s.getPreviousMonth(year, month)
+		period = s.calculateStatementPeriod(account, cycles, previousYear, int(previousMonth))
+	}
+	return period, nil
+}
+
+// lastClosedStatement returns the month of the most recent statement of the
+// card that closed on or before now, with the billing cycle in force for it.
+func (s *AccountService) lastClosedStatement(ctx context.Context, card *model.Account, now time.Time) (int, int, error) {
+	if card.StatementClosingDay == nil || card.PaymentDueDay == nil {
+		return 0, 0, ErrBillingCycleRequired
+	}
+	cycles, err := s.repo.ListBillingCycles(ctx, card.Id, card.UserId)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	year, month := now.Year(), int(now.Month())
+	closingDay, _ := s.billingCycleFor(card, cycles, year, time.Month(month))
+	if now.Before(s.calculateStatementDate(year, time.Month(month), closingDay)) {
+		previousMonth, previousYear := s.getPreviousMonth(year, month)
+		return previousYear, int(previousMonth), nil
+	}
+	return year, month, nil
+}
+
+// calculateStatementPeriod calculates the start and end dates for a statement period.
+// The statement starts when the previous one closed, each closing with the billing
+// cycle in force for it, so the statement after a closing day change is longer or
+// shorter than a month instead of overlapping or leaving a gap.
+func (s *AccountService) calculateStatementPeriod(account *model.Account, cycles []model.BillingCycle, targetYear, targetMonth int) StatementPeriod {
 	// Calculate statement period bounds
 	currentMonth := time.Month(targetMonth)
 	previousMonth, previousYear := s.getPreviousMonth(targetYear, targetMonth)
 
 	// Calculate end of statement (current month)
+	closingDay, _ := s.billingCycleFor(account, cycles, targetYear, currentMonth)
 	endDate := s.calculateStatementDate(targetYear, currentMonth, closingDay)
 
 	// Calculate start of statement (previous month)
-	startDate := s.calculateStatementDate(previousYear, previousMonth, closingDay)
+	previousClosingDay, _ := s.billingCycleFor(account, cycles, previousYear, previousMonth)
+	startDate := s.calculateStatementDate(previousYear, previousMonth, previousClosingDay)
 
 	return StatementPeriod{
 		Start: startDate,
@@ -228,6 +485,24 @@ func (s *AccountService) calculateStatementPeriod(targetYear, targetMonth, closi
 	}
 }
 
+// billingCycleFor returns the closing and due days of the statement closing in
+// the given month. A version applies from the first month whose statement, under
+// the version before it, had not closed yet when it took effect, so a statement
+// that already closed is never moved. Statements older than every version use
+// the first one, and cards without versions use the days on the account.
+func (s *AccountService) billingCycleFor(account *model.Account, cycles []model.BillingCycle, year int, month time.Month) (closingDay, dueDay int) {
+	for i := len(cycles) - 1; i > 0; i-- {
+		previousClosingDate := s.calculateStatementDate(year, month, cycles[i-1].StatementClosingDay)
+		if !cycles[i].EffectiveFrom.After(previousClosingDate) {
+			return cycles[i].StatementClosingDay, cycles[i].PaymentDueDay
+		}
+	}
+	if len(cycles) > 0 {
+		return cycles[0].StatementClosingDay, cycles[0].PaymentDueDay
+	}
+	return *account.StatementClosingDay, *account.PaymentDueDay
+}
+
 // getPreviousMonth returns the previous month and year, handling year rollover
 func (s *AccountService) getPreviousMonth(targetYear, targetMonth int) (time.Month, int) {
 	previousMonth := targetMonth - 1
@@ -280,3 +555,33 @@ func (s *AccountService) calculateStatementTotal(transactions []model.Transactio
 
 	return total
 }
+
+// calculateCardTotals adds up the expenses of the statement by card, starting
+// with the purchases made without one.
+func (s *AccountService) calculateCardTotals(transactions []model.Transaction) []CardStatementTotal {
+	var totals []CardStatementTotal
+	indexes := map[int64]int{}
+	for _, tx := range transactions {
+		if tx.Type != model.Expense {
+			continue
+		}
+		var key int64
+		if tx.CardId != nil {
+			key = *tx.CardId
+		}
+		i, ok := indexes[key]
+		if !ok {
+			i = len(totals)
+			indexes[key] = i
+			totals = append(totals, CardStatementTotal{CardId: tx.CardId, HolderName: tx.CardHolderName, LastFour: tx.CardLastFour})
+		}
+		totals[i].Total = totals[i].Total.Add(tx.Amount)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].CardId == nil || totals[j].CardId == nil {
+			return totals[i].CardId == nil && totals[j].CardId != nil
+		}
+		return *totals[i].CardId < *totals[j].CardId
+	})
+	return totals
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type MockAccountRepository struct {
@@ -68,6 +69,19 @@ func (m *MockAccountRepository) GetCurrentBalance(ctx context.Context, accountId
 	return args.Get(0).(decimal.Decimal), args.Error(1)
 }
 
+func (m *MockAccountRepository) ListBillingCycles(ctx context.Context, accountId, userId int64) ([]model.BillingCycle, error) {
+	args := m.Called(ctx, accountId, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.BillingCycle), args.Error(1)
+}
+
+func (m *MockAccountRepository) SaveBillingCycle(ctx context.Context, cycle model.BillingCycle, userId int64) error {
+	args := m.Called(ctx, cycle, userId)
+	return args.Error(0)
+}
+
 // TestAccountService tests the business logic of the AccountService.
 func TestAccountService(t *testing.T) {
 	// Disable logging for tests to keep output clean
@@ -76,7 +90,7 @@ func TestAccountService(t *testing.T) {
 	// --- Tests for CreateAccount ---
 	t.Run("CreateAccount", func(t *testing.T) {
 		mockAccountRepo := new(MockAccountRepository)
-		accountService := NewAccountService(mockAccountRepo, nil)
+		accountService := NewAccountService(mockAccountRepo, nil, unlimitedQuotas())
 		ctx := context.Background()
 
 		accountToCreate := model.Account{UserId: 1, Name: "New Savings"}
@@ -96,7 +110,7 @@ func TestAccountService(t *testing.T) {
 	// --- Tests for GetAccountById ---
 	t.Run("GetAccountById", func(t *testing.T) {
 		mockAccountRepo := new(MockAccountRepository)
-		accountService := NewAccountService(mockAccountRepo, nil)
+		accountService := NewAccountService(mockAccountRepo, nil, unlimitedQuotas())
 		ctx := context.Background()
 
 		// Arrange
@@ -119,7 +133,7 @@ func TestAccountService(t *testing.T) {
 	t.Run("ListAccountsByUserId", func(t *testing.T) {
 		t.Run("should list accounts and calculate all balances successfully", func(t *testing.T) {
 			mockAccountRepo := new(MockAccountRepository)
-			accountService := NewAccountService(mockAccountRepo, nil)
+			accountService := NewAccountService(mockAccountRepo, nil, unlimitedQuotas())
 			ctx := context.Background()
 
 			// Arrange
@@ -141,7 +155,7 @@ func TestAccountService(t *testing.T) {
 
 		t.Run("should return accounts even if one balance calculation fails", func(t *testing.T) {
 			mockAccountRepo := new(MockAccountRepository)
-			accountService := NewAccountService(mockAccountRepo, nil)
+			accountService := NewAccountService(mockAccountRepo, nil, unlimitedQuotas())
 			ctx := context.Background()
 
 			// Arrange
@@ -167,7 +181,7 @@ func TestAccountService(t *testing.T) {
 	// --- Tests for UpdateAccount ---
 	t.Run("UpdateAccount", func(t *testing.T) {
 		mockAccountRepo := new(MockAccountRepository)
-		accountService := NewAccountService(mockAccountRepo, nil)
+		accountService := NewAccountService(mockAccountRepo, nil, unlimitedQuotas())
 		ctx := context.Background()
 
 		// Arrange
@@ -178,7 +192,7 @@ func TestAccountService(t *testing.T) {
 		mockAccountRepo.On("GetCurrentBalance", ctx, int64(10), int64(1)).Return(decimal.NewFromInt(100), nil).Once()
 
 		// Act
-		resultAccount, err := accountService.UpdateAccount(ctx, accountToUpdate)
+		resultAccount, err := accountService.UpdateAccount(ctx, accountToUpdate, time.Now())
 
 		// Assert
 		assert.NoError(t, err)
@@ -186,11 +200,62 @@ func TestAccountService(t *testing.T) {
 		mockAccountRepo.AssertExpectations(t)
 	})
 
+	t.Run("UpdateAccount records a billing cycle when a card's days change", func(t *testing.T) {
+		mockAccountRepo := new(MockAccountRepository)
+		accountService := NewAccountService(mockAccountRepo, nil, unlimitedQuotas())
+		ctx := context.Background()
+
+		// Arrange
+		card := model.Account{Id: 10, UserId: 1, Type: model.CreditCard, StatementClosingDay: testhelper.Ptr(20), PaymentDueDay: testhelper.Ptr(28)}
+		effectiveFrom := time.Date(2025, time.March, 15, 14, 30, 0, 0, time.UTC)
+		mockAccountRepo.On("Update", ctx, card).Return(nil).Once()
+		mockAccountRepo.On("ListBillingCycles", ctx, int64(10), int64(1)).Return([]model.BillingCycle{
+			{AccountId: 10, StatementClosingDay: 10, PaymentDueDay: 18, EffectiveFrom: time.Date(2024, time.May, 2, 0, 0, 0, 0, time.UTC)},
+		}, nil).Once()
+		mockAccountRepo.On("SaveBillingCycle", ctx, model.BillingCycle{
+			AccountId:           10,
+			StatementClosingDay: 20,
+			PaymentDueDay:       28,
+			EffectiveFrom:       time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC),
+		}, int64(1)).Return(nil).Once()
+		mockAccountRepo.On("GetById", ctx, int64(10), int64(1)).Return(&card, nil).Once()
+		mockAccountRepo.On("GetCurrentBalance", ctx, int64(10), int64(1)).Return(decimal.Zero, nil).Once()
+
+		// Act
+		_, err := accountService.UpdateAccount(ctx, card, effectiveFrom)
+
+		// Assert
+		assert.NoError(t, err)
+		mockAccountRepo.AssertExpectations(t)
+	})
+
+	t.Run("UpdateAccount keeps the billing cycle when a card's days do not change", func(t *testing.T) {
+		mockAccountRepo := new(MockAccountRepository)
+		accountService := NewAccountService(mockAccountRepo, nil, unlimitedQuotas())
+		ctx := context.Background()
+
+		// Arrange
+		card := model.Account{Id: 10, UserId: 1, Name: "Renamed", Type: model.CreditCard, StatementClosingDay: testhelper.Ptr(10), PaymentDueDay: testhelper.Ptr(18)}
+		mockAccountRepo.On("Update", ctx, card).Return(nil).Once()
+		mockAccountRepo.On("ListBillingCycles", ctx, int64(10), int64(1)).Return([]model.BillingCycle{
+			{AccountId: 10, StatementClosingDay: 10, PaymentDueDay: 18, EffectiveFrom: time.Date(2024, time.May, 2, 0, 0, 0, 0, time.UTC)},
+		}, nil).Once()
+		mockAccountRepo.On("GetById", ctx, int64(10), int64(1)).Return(&card, nil).Once()
+		mockAccountRepo.On("GetCurrentBalance", ctx, int64(10), int64(1)).Return(decimal.Zero, nil).Once()
+
+		// Act
+		_, err := accountService.UpdateAccount(ctx, card, time.Now())
+
+		// Assert
+		assert.NoError(t, err)
+		mockAccountRepo.AssertNotCalled(t, "SaveBillingCycle", mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	// --- Tests for DeleteAccount ---
 	t.Run("DeleteAccount", func(t *testing.T) {
 		mockAccountRepo := new(MockAccountRepository)
 		mockTransactionRepo := new(MockTransactionRepository)
-		accountService := NewAccountService(mockAccountRepo, mockTransactionRepo)
+		accountService := NewAccountService(mockAccountRepo, mockTransactionRepo, unlimitedQuotas())
 		ctx := context.Background()
 
 		// Arrange
@@ -199,13 +264,102 @@ func TestAccountService(t *testing.T) {
 		mockAccountRepo.On("Delete", ctx, int64(10), int64(1)).Return(nil).Once()
 
 		// Act
-		err := accountService.DeleteAccount(ctx, 10, 1)
+		err := accountService.DeleteAccount(ctx, 10, 1, "")
+
+		// Assert
+		assert.NoError(t, err)
+		mockAccountRepo.AssertExpectations(t)
+		mockTransactionRepo.AssertExpectations(t)
+	})
+}
+
+func TestAccountServiceDeletionImpact(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	checking := &model.Account{Id: 10, UserId: 1, Name: "Checking"}
+	savings := &model.Account{Id: 20, UserId: 1, Name: "Savings"}
+	card := &model.Account{Id: 30, UserId: 1, Name: "Card"}
+	transfers := []model.Transaction{
+		{Id: 1, UserId: 1, AccountId: checking.Id, DestinationAccountId: &savings.Id, Amount: decimal.NewFromInt(300), Type: model.Transfer},
+		{Id: 2, UserId: 1, AccountId: savings.Id, DestinationAccountId: &checking.Id, Amount: decimal.NewFromInt(100), Type: model.Transfer},
+		{Id: 3, UserId: 1, AccountId: checking.Id, DestinationAccountId: &card.Id, Amount: decimal.NewFromInt(450), Type: model.Transfer},
+	}
+
+	setup := func() (*AccountService, *MockAccountRepository, *MockTransactionRepository) {
+		mockAccountRepo := new(MockAccountRepository)
+		mockTransactionRepo := new(MockTransactionRepository)
+		for _, account := range []*model.Account{checking, savings, card} {
+			mockAccountRepo.On("GetById", ctx, account.Id, int64(1)).Return(account, nil).Maybe()
+		}
+		mockTransactionRepo.On("CountByAccountId", ctx, int64(1), checking.Id).Return(int64(5), nil).Maybe()
+		mockTransactionRepo.On("ListTransfersByAccountId", ctx, int64(1), checking.Id).Return(transfers, nil).Maybe()
+		return NewAccountService(mockAccountRepo, mockTransactionRepo, unlimitedQuotas()), mockAccountRepo, mockTransactionRepo
+	}
+
+	t.Run("should show how deleting the transfers changes the other balances", func(t *testing.T) {
+		// Arrange
+		accountService, mockAccountRepo, _ := setup()
+		mockAccountRepo.On("GetCurrentBalance", ctx, savings.Id, int64(1)).Return(decimal.NewFromInt(1000), nil).Once()
+		mockAccountRepo.On("GetCurrentBalance", ctx, card.Id, int64(1)).Return(decimal.Zero, nil).Once()
+
+		// Act
+		impact, err := accountService.GetDeletionImpact(ctx, checking.Id, 1, "")
+
+		// Assert: savings loses the 300 it received and gets back the 100 it sent.
+		assert.NoError(t, err)
+		assert.Equal(t, DeleteTransfers, impact.Policy)
+		assert.Equal(t, int64(5), impact.DeletedTransactions)
+		assert.Len(t, impact.Transfers, 3)
+		assert.Equal(t, "deleted", impact.Transfers[0].Outcome)
+		if assert.Len(t, impact.BalanceChanges, 2) {
+			assert.Equal(t, savings.Id, impact.BalanceChanges[0].AccountId)
+			assert.Equal(t, "Savings", impact.BalanceChanges[0].AccountName)
+			assert.True(t, decimal.NewFromInt(-200).Equal(impact.BalanceChanges[0].Change), impact.BalanceChanges[0].Change.String())
+			assert.True(t, decimal.NewFromInt(-450).Equal(impact.BalanceChanges[1].Change), impact.BalanceChanges[1].Change.String())
+		}
+	})
+
+	t.Run("should keep the other balances when keeping counterparts", func(t *testing.T) {
+		// Arrange
+		accountService, mockAccountRepo, _ := setup()
+
+		// Act
+		impact, err := accountService.GetDeletionImpact(ctx, checking.Id, 1, KeepCounterparts)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), impact.DeletedTransactions)
+		assert.Empty(t, impact.BalanceChanges)
+		assert.Equal(t, string(model.Income), impact.Transfers[0].Outcome)
+		assert.Equal(t, savings.Id, impact.Transfers[0].CounterpartAccountId)
+		assert.Equal(t, string(model.Expense), impact.Transfers[1].Outcome)
+		mockAccountRepo.AssertNotCalled(t, "GetCurrentBalance", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should delete the account keeping the counterparts", func(t *testing.T) {
+		// Arrange
+		accountService, mockAccountRepo, mockTransactionRepo := setup()
+		mockTransactionRepo.On("DeleteByAccountIdKeepingCounterparts", ctx, int64(1), checking.Id).Return(nil).Once()
+		mockAccountRepo.On("Delete", ctx, checking.Id, int64(1)).Return(nil).Once()
+
+		// Act
+		err := accountService.DeleteAccount(ctx, checking.Id, 1, KeepCounterparts)
 
 		// Assert
 		assert.NoError(t, err)
+		mockTransactionRepo.AssertNotCalled(t, "DeleteByAccountId", mock.Anything, mock.Anything, mock.Anything)
 		mockAccountRepo.AssertExpectations(t)
 		mockTransactionRepo.AssertExpectations(t)
 	})
+
+	t.Run("should reject an unknown policy", func(t *testing.T) {
+		accountService, mockAccountRepo, _ := setup()
+
+		err := accountService.DeleteAccount(ctx, checking.Id, 1, "archive")
+
+		assert.ErrorIs(t, err, ErrInvalidDeletionPolicy)
+		mockAccountRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
 func TestAccountServiceGetStatementDetails(t *testing.T) {
@@ -215,11 +369,24 @@ func TestAccountServiceGetStatementDetails(t *testing.T) {
 	const testUserID = int64(1)
 	const testAccountID = int64(10)
 
+	cardClosingOn20th := &model.Account{
+		Id:                  testAccountID,
+		UserId:              testUserID,
+		Type:                model.CreditCard,
+		StatementClosingDay: testhelper.Ptr(20),
+		PaymentDueDay:       testhelper.Ptr(28),
+	}
+	closingDayMovedFrom10thTo20th := []model.BillingCycle{
+		{AccountId: testAccountID, StatementClosingDay: 10, PaymentDueDay: 18, EffectiveFrom: time.Date(2024, time.May, 2, 0, 0, 0, 0, time.UTC)},
+		{AccountId: testAccountID, StatementClosingDay: 20, PaymentDueDay: 28, EffectiveFrom: time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC)},
+	}
+
 	testCases := []struct {
 		name                   string
 		targetYear             int
 		targetMonth            time.Month
 		mockAccount            *model.Account
+		mockCycles             []model.BillingCycle
 		mockTransactions       []model.Transaction
 		mockAccountError       error
 		mockTxError            error
@@ -293,6 +460,58 @@ func TestAccountServiceGetStatementDetails(t *testing.T) {
 			expectedEndDate:        time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
 			expectedPaymentDueDate: time.Date(2024, time.February, 15, 0, 0, 0, 0, time.UTC),
 		},
+		{
+			name:        "should keep the closing day of statements that closed before a change",
+			targetYear:  2025,
+			targetMonth: time.February,
+			mockAccount: cardClosingOn20th,
+			mockCycles:  closingDayMovedFrom10thTo20th,
+			// The change only takes effect on 15 March, so February still closes on the 10th.
+			expectedStartDate:      time.Date(2025, time.January, 10, 0, 0, 0, 0, time.UTC),
+			expectedEndDate:        time.Date(2025, time.February, 10, 0, 0, 0, 0, time.UTC),
+			expectedPaymentDueDate: time.Date(2025, time.February, 18, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "should keep the statement that closed in the month of the change",
+			targetYear:  2025,
+			targetMonth: time.March,
+			mockAccount: cardClosingOn20th,
+			mockCycles:  closingDayMovedFrom10thTo20th,
+			// March closed on the 10th, before the change took effect on the 15th.
+			expectedStartDate:      time.Date(2025, time.February, 10, 0, 0, 0, 0, time.UTC),
+			expectedEndDate:        time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC),
+			expectedPaymentDueDate: time.Date(2025, time.March, 18, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:                   "should run the transition statement from the old closing day to the new one",
+			targetYear:             2025,
+			targetMonth:            time.April,
+			mockAccount:            cardClosingOn20th,
+			mockCycles:             closingDayMovedFrom10thTo20th,
+			expectedStartDate:      time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC),
+			expectedEndDate:        time.Date(2025, time.April, 20, 0, 0, 0, 0, time.UTC),
+			expectedPaymentDueDate: time.Date(2025, time.April, 28, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:                   "should use the new closing day after the transition",
+			targetYear:             2025,
+			targetMonth:            time.May,
+			mockAccount:            cardClosingOn20th,
+			mockCycles:             closingDayMovedFrom10thTo20th,
+			expectedStartDate:      time.Date(2025, time.April, 20, 0, 0, 0, 0, time.UTC),
+			expectedEndDate:        time.Date(2025, time.May, 20, 0, 0, 0, 0, time.UTC),
+			expectedPaymentDueDate: time.Date(2025, time.May, 28, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:                   "should use the first billing cycle for statements older than every version",
+			targetYear:             2023,
+			targetMonth:            time.June,
+			mockAccount:            cardClosingOn20th,
+			mockCycles:             closingDayMovedFrom10thTo20th,
+			expectedStartDate:      time.Date(2023, time.May, 10, 0, 0, 0, 0, time.UTC),
+			expectedEndDate:        time.Date(2023, time.June, 10, 0, 0, 0, 0, time.UTC),
+			expectedPaymentDueDate: time.Date(2023, time.June, 18, 0, 0, 0, 0, time.UTC),
+		},
 		{
 			name:             "should return error if account is not found",
 			targetYear:       2025,
@@ -334,7 +553,7 @@ func TestAccountServiceGetStatementDetails(t *testing.T) {
 			// Arrange
 			mockAccountRepo := new(MockAccountRepository)
 			mockTxRepo := new(MockTransactionRepository)
-			accountService := NewAccountService(mockAccountRepo, mockTxRepo)
+			accountService := NewAccountService(mockAccountRepo, mockTxRepo, unlimitedQuotas())
 
 			// Setup mocks based on the test case
 			if tc.mockAccountError != nil {
@@ -345,6 +564,7 @@ func TestAccountServiceGetStatementDetails(t *testing.T) {
 
 			// Only set up the transaction mock if no error is expected before that call
 			if !tc.expectError {
+				mockAccountRepo.On("ListBillingCycles", ctx, testAccountID, testUserID).Return(tc.mockCycles, nil)
 				mockTxRepo.On("ListByAccountAndDateRange", ctx, testUserID, testAccountID, tc.expectedStartDate, tc.expectedEndDate).Return(tc.mockTransactions, tc.mockTxError)
 			}
 
@@ -371,3 +591,86 @@ func TestAccountServiceGetStatementDetails(t *testing.T) {
 		})
 	}
 }
+
+func TestAccountServiceStatementCardTotals(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+
+	// Arrange
+	mockAccountRepo := new(MockAccountRepository)
+	mockTxRepo := new(MockTransactionRepository)
+	accountService := NewAccountService(mockAccountRepo, mockTxRepo, unlimitedQuotas())
+	account := &model.Account{Id: 10, UserId: 1, Type: model.CreditCard, StatementClosingDay: testhelper.Ptr(20), PaymentDueDay: testhelper.Ptr(28)}
+	start := time.Date(2025, time.June, 20, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.July, 20, 0, 0, 0, 0, time.UTC)
+	primary, additional := int64(3), int64(4)
+
+	mockAccountRepo.On("GetById", ctx, account.Id, account.UserId).Return(account, nil)
+	mockAccountRepo.On("ListBillingCycles", ctx, account.Id, account.UserId).Return(nil, nil)
+	mockTxRepo.On("ListByAccountAndDateRange", ctx, account.UserId, account.Id, start, end).Return([]model.Transaction{
+		{Type: model.Expense, Amount: decimal.NewFromInt(80), CardId: &additional, CardHolderName: testhelper.Ptr("Ana"), CardLastFour: testhelper.Ptr("9876")},
+		{Type: model.Expense, Amount: decimal.NewFromInt(100), CardId: &primary, CardHolderName: testhelper.Ptr("Maria"), CardLastFour: testhelper.Ptr("4321")},
+		{Type: model.Expense, Amount: decimal.NewFromInt(20), CardId: &additional, CardHolderName: testhelper.Ptr("Ana"), CardLastFour: testhelper.Ptr("9876")},
+		{Type: model.Expense, Amount: decimal.NewFromInt(15)},
+		{Type: model.Income, Amount: decimal.NewFromInt(50), CardId: &primary},
+	}, nil)
+
+	// Act
+	statement, err := accountService.GetStatementDetails(ctx, account.UserId, account.Id, 2025, int(time.July))
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, statement.Cards, 3)
+	assert.Nil(t, statement.Cards[0].CardId, "purchases without a card come first")
+	assert.True(t, decimal.NewFromInt(15).Equal(statement.Cards[0].Total))
+	assert.Equal(t, primary, *statement.Cards[1].CardId)
+	assert.True(t, decimal.NewFromInt(100).Equal(statement.Cards[1].Total), "refunds do not count towards the card's spend")
+	assert.Equal(t, additional, *statement.Cards[2].CardId)
+	assert.Equal(t, "Ana", *statement.Cards[2].HolderName)
+	assert.True(t, decimal.NewFromInt(100).Equal(statement.Cards[2].Total))
+}
+
+func TestAccountServiceStatementForeignPurchases(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+
+	// Arrange
+	mockAccountRepo := new(MockAccountRepository)
+	mockTxRepo := new(MockTransactionRepository)
+	accountService := NewAccountService(mockAccountRepo, mockTxRepo, unlimitedQuotas())
+	account := &model.Account{Id: 10, UserId: 1, Type: model.CreditCard, StatementClosingDay: testhelper.Ptr(20), PaymentDueDay: testhelper.Ptr(28)}
+	start := time.Date(2025, time.June, 20, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.July, 20, 0, 0, 0, 0, time.UTC)
+	hotel, dinner, museum := int64(1), int64(3), int64(5)
+
+	mockAccountRepo.On("GetById", ctx, account.Id, account.UserId).Return(account, nil)
+	mockAccountRepo.On("ListBillingCycles", ctx, account.Id, account.UserId).Return(nil, nil)
+	mockTxRepo.On("ListByAccountAndDateRange", ctx, account.UserId, account.Id, start, end).Return([]model.Transaction{
+		{Id: hotel, Type: model.Expense, Amount: decimal.NewFromInt(520), OriginalCurrency: testhelper.Ptr("USD"), OriginalAmount: testhelper.Ptr(decimal.NewFromInt(100)), ExchangeRate: testhelper.Ptr(decimal.NewFromInt(5)), SpreadRate: testhelper.Ptr(decimal.NewFromInt(4))},
+		{Id: 2, Type: model.Expense, Amount: decimal.RequireFromString("18.20"), FeeOfTransactionId: &hotel},
+		{Id: museum, Type: model.Expense, Amount: decimal.NewFromInt(120), OriginalCurrency: testhelper.Ptr("EUR"), OriginalAmount: testhelper.Ptr(decimal.NewFromInt(20)), ExchangeRate: testhelper.Ptr(decimal.NewFromInt(6))},
+		{Id: dinner, Type: model.Expense, Amount: decimal.NewFromInt(260), OriginalCurrency: testhelper.Ptr("USD"), OriginalAmount: testhelper.Ptr(decimal.NewFromInt(50)), ExchangeRate: testhelper.Ptr(decimal.NewFromInt(5)), SpreadRate: testhelper.Ptr(decimal.NewFromInt(4))},
+		{Id: 4, Type: model.Expense, Amount: decimal.RequireFromString("9.10"), FeeOfTransactionId: &dinner},
+		{Id: 6, Type: model.Expense, Amount: decimal.NewFromInt(35)},
+	}, nil)
+
+	// Act
+	statement, err := accountService.GetStatementDetails(ctx, account.UserId, account.Id, 2025, int(time.July))
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, statement.ForeignPurchases, 2)
+	eur, usd := statement.ForeignPurchases[0], statement.ForeignPurchases[1]
+	assert.Equal(t, "EUR", eur.Currency)
+	assert.True(t, eur.IOF.IsZero())
+	assert.Equal(t, "USD", usd.Currency)
+	assert.True(t, decimal.NewFromInt(150).Equal(usd.OriginalTotal))
+	assert.True(t, decimal.NewFromInt(780).Equal(usd.Total))
+	assert.True(t, decimal.RequireFromString("27.30").Equal(usd.IOF))
+	require.Len(t, usd.Purchases, 2)
+	assert.Equal(t, hotel, usd.Purchases[0].TransactionId)
+	assert.True(t, decimal.RequireFromString("18.20").Equal(usd.Purchases[0].IOF))
+	assert.True(t, decimal.NewFromInt(4).Equal(usd.Purchases[0].SpreadRate))
+	// The IOF is part of the statement like any other charge.
+	assert.True(t, decimal.RequireFromString("962.30").Equal(statement.StatementTotal))
+}
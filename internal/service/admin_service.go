@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/db"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/jobs"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/rs/zerolog"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrUserNotFound          = errors.New("user not found")
+	ErrAdminCannotTargetSelf = errors.New("admins cannot disable or reset their own account")
+)
+
+// Audit log actions recorded by the AdminService.
+const (
+	AuditActionListUsers           = "list_users"
+	AuditActionDisableUser         = "disable_user"
+	AuditActionEnableUser          = "enable_user"
+	AuditActionForcePasswordReset  = "force_password_reset"
+	AuditActionViewUserUsage       = "view_user_usage"
+	AuditActionViewJobs            = "view_jobs"
+	AuditActionViewMigrationStatus = "view_migration_status"
+	AuditActionViewAuditLog        = "view_audit_log"
+	AuditActionLoadIndexRates      = "load_index_rates"
+)
+
+// AdminService implements the instance operator features. Every public method
+// takes the acting admin's Id and writes an entry to the audit trail.
+type AdminService struct {
+	userRepo       repository.UserRepository
+	auditLogRepo   repository.AuditLogRepository
+	usageRepo      repository.UsageRepository
+	yieldRepo      repository.YieldRepository
+	scheduler      *jobs.Scheduler
+	migrationsPath string
+}
+
+// NewAdminService creates a new instance of AdminService.
+func NewAdminService(
+	userRepo repository.UserRepository,
+	auditLogRepo repository.AuditLogRepository,
+	usageRepo repository.UsageRepository,
+	yieldRepo repository.YieldRepository,
+	scheduler *jobs.Scheduler,
+	migrationsPath string,
+) *AdminService {
+	return &AdminService{
+		userRepo:       userRepo,
+		auditLogRepo:   auditLogRepo,
+		usageRepo:      usageRepo,
+		yieldRepo:      yieldRepo,
+		scheduler:      scheduler,
+		migrationsPath: migrationsPath,
+	}
+}
+
+// PromoteAdmins grants the admin flag to the users with the given e-mails.
+// It is used at startup to bootstrap the first operators and is not audited.
+func (s *AdminService) PromoteAdmins(ctx context.Context, emails []string) error {
+	if len(emails) == 0 {
+		return nil
+	}
+	promoted, err := s.userRepo.PromoteToAdmin(ctx, emails)
+	if err != nil {
+		return err
+	}
+	zerolog.Ctx(ctx).Info().Int64("promoted", promoted).Msg("Bootstrapped admin users")
+	return nil
+}
+
+// ListUsers lists and searches the instance users.
+func (s *AdminService) ListUsers(ctx context.Context, adminId int64, filters repository.ListUserFilters) ([]model.User, error) {
+	if err := s.audit(ctx, adminId, AuditActionListUsers, nil, map[string]any{"search": filters.Search}); err != nil {
+		return nil, err
+	}
+	return s.userRepo.List(ctx, filters)
+}
+
+// SetUserDisabled disables or re-enables a user. Disabled users are rejected by the auth middleware.
+func (s *AdminService) SetUserDisabled(ctx context.Context, adminId, userId int64, disabled bool) (*model.User, error) {
+	if adminId == userId {
+		return nil, ErrAdminCannotTargetSelf
+	}
+
+	action := AuditActionEnableUser
+	if disabled {
+		action = AuditActionDisableUser
+	}
+	entry, err := newAuditEntry(adminId, action, &userId, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetDisabledAudited(ctx, userId, disabled, entry); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		zerolog.Ctx(ctx).Error().Err(err).Str("action", action).Msg("failed to change user with audit log entry")
+		return nil, err
+	}
+	return s.userRepo.GetById(ctx, userId)
+}
+
+// ForcePasswordReset replaces the user's password with a random temporary one and
+// requires the user to change it before using the API again. The temporary
+// password is returned so the admin can hand it over.
+func (s *AdminService) ForcePasswordReset(ctx context.Context, adminId, userId int64) (string, error) {
+	if adminId == userId {
+		return "", ErrAdminCannotTargetSelf
+	}
+
+	temporaryPassword, err := generateTemporaryPassword()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate temporary password: %w", err)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(temporaryPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	entry, err := newAuditEntry(adminId, AuditActionForcePasswordReset, &userId, nil)
+	if err != nil {
+		return "", err
+	}
+	if err := s.userRepo.UpdatePasswordAudited(ctx, userId, string(hash), true, entry); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrUserNotFound
+		}
+		zerolog.Ctx(ctx).Error().Err(err).Str("action", AuditActionForcePasswordReset).Msg("failed to change user with audit log entry")
+		return "", err
+	}
+	return temporaryPassword, nil
+}
+
+// GetUserUsage returns how much data a user keeps in the instance.
+func (s *AdminService) GetUserUsage(ctx context.Context, adminId, userId int64) (*model.UserUsage, error) {
+	usage, err := s.usageRepo.GetUserUsage(ctx, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if err := s.audit(ctx, adminId, AuditActionViewUserUsage, &userId, nil); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// ListJobs returns the status of the background jobs.
+func (s *AdminService) ListJobs(ctx context.Context, adminId int64) ([]jobs.Status, error) {
+	if err := s.audit(ctx, adminId, AuditActionViewJobs, nil, nil); err != nil {
+		return nil, err
+	}
+	return s.scheduler.Statuses(), nil
+}
+
+// GetMigrationStatus compares the schema version applied to the database with
+// the latest migration shipped with the application.
+func (s *AdminService) GetMigrationStatus(ctx context.Context, adminId int64) (*model.MigrationStatus, error) {
+	status, err := s.usageRepo.GetMigrationStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := db.LatestMigrationVersion(s.migrationsPath)
+	if err != nil {
+		return nil, err
+	}
+	status.LatestVersion = latest
+
+	if err := s.audit(ctx, adminId, AuditActionViewMigrationStatus, nil, nil); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// ListAuditLog returns the audit trail, most recent first.
+func (s *AdminService) ListAuditLog(ctx context.Context, adminId int64, limit, offset uint64) ([]model.AuditLogEntry, error) {
+	if err := s.audit(ctx, adminId, AuditActionViewAuditLog, nil, nil); err != nil {
+		return nil, err
+	}
+	return s.auditLogRepo.List(ctx, limit, offset)
+}
+
+// LoadIndexRates stores the daily rates of an index, replacing the ones
+// already loaded for the same days. The rates and their audit entry are
+// written in the same database transaction.
+func (s *AdminService) LoadIndexRates(ctx context.Context, adminId int64, name model.IndexName, rates []model.IndexRate) error {
+	if name != model.IndexCDI && name != model.IndexSelic {
+		return ErrInvalidIndexRates
+	}
+	for i, rate := range rates {
+		if rate.Date.IsZero() || rate.AnnualRate.IsNegative() {
+			return ErrInvalidIndexRates
+		}
+		rates[i].Name = name
+	}
+
+	entry, err := newAuditEntry(adminId, AuditActionLoadIndexRates, nil, map[string]any{"index": name, "days": len(rates)})
+	if err != nil {
+		return err
+	}
+	if err := s.yieldRepo.SaveIndexRates(ctx, rates, entry); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("action", AuditActionLoadIndexRates).Msg("failed to load index rates with audit log entry")
+		return err
+	}
+	return nil
+}
+
+// audit writes an entry to the audit trail. A failure to audit is returned to
+// the caller so that unaudited admin activity never goes unnoticed. Changes to
+// users and index rates are audited by their repositories in the same database
+// transaction instead, so that they are not kept when their entry cannot be written.
+func (s *AdminService) audit(ctx context.Context, adminId int64, action string, targetUserId *int64, details map[string]any) error {
+	entry, err := newAuditEntry(adminId, action, targetUserId, details)
+	if err != nil {
+		return err
+	}
+	if _, err := s.auditLogRepo.Create(ctx, entry); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("action", action).Msg("failed to write audit log entry")
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// newAuditEntry builds an audit trail entry of an action taken by the admin.
+func newAuditEntry(adminId int64, action string, targetUserId *int64, details map[string]any) (model.AuditLogEntry, error) {
+	entry := model.AuditLogEntry{
+		AdminId:      &adminId,
+		Action:       action,
+		TargetUserId: targetUserId,
+	}
+	if details != nil {
+		raw, err := json.Marshal(details)
+		if err != nil {
+			return entry, err
+		}
+		entry.Details = raw
+	}
+	return entry, nil
+}
+
+// generateTemporaryPassword returns a random 16-character password.
+func generateTemporaryPassword() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
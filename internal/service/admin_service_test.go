@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/jobs"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MockAuditLogRepository is a mock implementation of the AuditLogRepository interface.
+type MockAuditLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditLogRepository) Create(ctx context.Context, entry model.AuditLogEntry) (int64, error) {
+	args := m.Called(ctx, entry)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAuditLogRepository) List(ctx context.Context, limit, offset uint64) ([]model.AuditLogEntry, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AuditLogEntry), args.Error(1)
+}
+
+// MockUsageRepository is a mock implementation of the UsageRepository interface.
+type MockUsageRepository struct {
+	mock.Mock
+}
+
+func (m *MockUsageRepository) GetUserUsage(ctx context.Context, userId int64) (*model.UserUsage, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserUsage), args.Error(1)
+}
+
+func (m *MockUsageRepository) GetMigrationStatus(ctx context.Context) (*model.MigrationStatus, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.MigrationStatus), args.Error(1)
+}
+
+func (m *MockUsageRepository) CountAccounts(ctx context.Context, userId int64) (int64, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUsageRepository) CountTransactionsCreatedSince(ctx context.Context, userId int64, since time.Time) (int64, error) {
+	args := m.Called(ctx, userId, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUsageRepository) EstimateStorageBytes(ctx context.Context, userId int64) (int64, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestAdminService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	adminId := int64(1)
+	userId := int64(2)
+
+	setupWithYield := func() (*AdminService, *MockUserRepository, *MockAuditLogRepository, *MockUsageRepository, *MockYieldRepository) {
+		mockUserRepo := new(MockUserRepository)
+		mockAuditLogRepo := new(MockAuditLogRepository)
+		mockUsageRepo := new(MockUsageRepository)
+		mockYieldRepo := new(MockYieldRepository)
+		adminService := NewAdminService(mockUserRepo, mockAuditLogRepo, mockUsageRepo, mockYieldRepo, jobs.NewScheduler(zerolog.Nop()), "")
+		return adminService, mockUserRepo, mockAuditLogRepo, mockUsageRepo, mockYieldRepo
+	}
+
+	setup := func() (*AdminService, *MockUserRepository, *MockAuditLogRepository, *MockUsageRepository) {
+		adminService, mockUserRepo, mockAuditLogRepo, mockUsageRepo, _ := setupWithYield()
+		return adminService, mockUserRepo, mockAuditLogRepo, mockUsageRepo
+	}
+
+	auditEntry := func(action string) interface{} {
+		return mock.MatchedBy(func(entry model.AuditLogEntry) bool {
+			return entry.Action == action && entry.AdminId != nil && *entry.AdminId == adminId
+		})
+	}
+
+	t.Run("SetUserDisabled", func(t *testing.T) {
+		t.Run("should disable the user and record it in the audit log", func(t *testing.T) {
+			// Arrange
+			adminService, mockUserRepo, mockAuditLogRepo, _ := setup()
+			mockUserRepo.On("SetDisabledAudited", ctx, userId, true, auditEntry(AuditActionDisableUser)).Return(nil).Once()
+			mockUserRepo.On("GetById", ctx, userId).Return(&model.User{Id: userId}, nil).Once()
+
+			// Act
+			user, err := adminService.SetUserDisabled(ctx, adminId, userId, true)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, userId, user.Id)
+			mockUserRepo.AssertExpectations(t)
+			mockAuditLogRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should not allow an admin to disable themselves", func(t *testing.T) {
+			// Arrange
+			adminService, mockUserRepo, mockAuditLogRepo, _ := setup()
+
+			// Act
+			_, err := adminService.SetUserDisabled(ctx, adminId, adminId, true)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrAdminCannotTargetSelf)
+			mockUserRepo.AssertNotCalled(t, "SetDisabledAudited", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockAuditLogRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should return ErrUserNotFound for an unknown user", func(t *testing.T) {
+			// Arrange
+			adminService, mockUserRepo, _, _ := setup()
+			mockUserRepo.On("SetDisabledAudited", ctx, int64(99), true, mock.Anything).Return(sql.ErrNoRows).Once()
+
+			// Act
+			_, err := adminService.SetUserDisabled(ctx, adminId, 99, true)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrUserNotFound)
+		})
+
+		t.Run("should fail when the audit entry cannot be written", func(t *testing.T) {
+			// Arrange
+			adminService, mockUserRepo, _, _ := setup()
+			mockUserRepo.On("SetDisabledAudited", ctx, userId, false, auditEntry(AuditActionEnableUser)).
+				Return(fmt.Errorf("failed to write audit log: %w", errors.New("db down"))).Once()
+
+			// Act
+			_, err := adminService.SetUserDisabled(ctx, adminId, userId, false)
+
+			// Assert
+			assert.ErrorContains(t, err, "failed to write audit log")
+			mockUserRepo.AssertNotCalled(t, "GetById", mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("ForcePasswordReset", func(t *testing.T) {
+		t.Run("should store a hash of the returned temporary password and require a change", func(t *testing.T) {
+			// Arrange
+			adminService, mockUserRepo, _, _ := setup()
+			var capturedHash string
+			mockUserRepo.On("UpdatePasswordAudited", ctx, userId, mock.AnythingOfType("string"), true, auditEntry(AuditActionForcePasswordReset)).
+				Run(func(args mock.Arguments) { capturedHash = args.String(2) }).
+				Return(nil).Once()
+
+			// Act
+			temporaryPassword, err := adminService.ForcePasswordReset(ctx, adminId, userId)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, temporaryPassword, 16)
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(capturedHash), []byte(temporaryPassword)))
+			mockUserRepo.AssertExpectations(t)
+		})
+	})
+
+	t.Run("LoadIndexRates", func(t *testing.T) {
+		t.Run("should store the rates with their audit entry", func(t *testing.T) {
+			// Arrange
+			adminService, _, mockAuditLogRepo, _, mockYieldRepo := setupWithYield()
+			date := time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)
+			mockYieldRepo.On("SaveIndexRates", ctx, []model.IndexRate{{Name: model.IndexCDI, Date: date, AnnualRate: decimal.RequireFromString("14.9")}}, auditEntry(AuditActionLoadIndexRates)).Return(nil).Once()
+
+			// Act
+			err := adminService.LoadIndexRates(ctx, adminId, model.IndexCDI, []model.IndexRate{{Date: date, AnnualRate: decimal.RequireFromString("14.9")}})
+
+			// Assert
+			assert.NoError(t, err)
+			mockYieldRepo.AssertExpectations(t)
+			mockAuditLogRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should reject rates of an unknown index", func(t *testing.T) {
+			// Arrange
+			adminService, _, _, _, mockYieldRepo := setupWithYield()
+
+			// Act
+			err := adminService.LoadIndexRates(ctx, adminId, "ipca", []model.IndexRate{{Date: time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC), AnnualRate: decimal.NewFromInt(5)}})
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidIndexRates)
+			mockYieldRepo.AssertNotCalled(t, "SaveIndexRates", mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("ListAuditLog", func(t *testing.T) {
+		t.Run("should record the read itself before listing", func(t *testing.T) {
+			// Arrange
+			adminService, _, mockAuditLogRepo, _ := setup()
+			entries := []model.AuditLogEntry{{Id: 1, Action: AuditActionDisableUser}}
+			mockAuditLogRepo.On("Create", ctx, auditEntry(AuditActionViewAuditLog)).Return(int64(2), nil).Once()
+			mockAuditLogRepo.On("List", ctx, uint64(50), uint64(0)).Return(entries, nil).Once()
+
+			// Act
+			result, err := adminService.ListAuditLog(ctx, adminId, 50, 0)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, entries, result)
+			mockAuditLogRepo.AssertExpectations(t)
+		})
+	})
+}
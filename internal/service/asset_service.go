@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrAssetAccountNotFound    = errors.New("asset account not found")
+	ErrInvalidAssetValue       = errors.New("asset values cannot be negative")
+	ErrAssetAccountTransaction = errors.New("asset accounts do not accept transactions, record their value with valuations instead")
+)
+
+// maxDepreciationMonths bounds the schedule generated for a single asset.
+const maxDepreciationMonths = 100 * 12
+
+// AssetSummary is the current value of an asset and how it got there.
+type AssetSummary struct {
+	Account      model.Account
+	CurrentValue decimal.Decimal
+	Valuations   []model.AssetValuation
+}
+
+// RevalueAssetInput sets the value of an asset on a date, which defaults to today.
+type RevalueAssetInput struct {
+	Value    decimal.Decimal
+	ValuedOn *time.Time
+	Note     string
+}
+
+// AssetService values physical assets such as cars and property, through
+// manual revaluations and monthly depreciation adjustments.
+type AssetService struct {
+	repo        repository.AssetRepository
+	accountRepo repository.AccountRepository
+	now         func() time.Time
+}
+
+// NewAssetService creates a new instance of AssetService.
+func NewAssetService(repo repository.AssetRepository, accountRepo repository.AccountRepository) *AssetService {
+	return &AssetService{
+		repo:        repo,
+		accountRepo: accountRepo,
+		now:         time.Now,
+	}
+}
+
+// Revalue records the value of an asset on a date. Depreciation continues
+// from the new value.
+func (s *AssetService) Revalue(ctx context.Context, userId, accountId int64, input RevalueAssetInput) (*model.AssetValuation, error) {
+	if input.Value.IsNegative() {
+		return nil, ErrInvalidAssetValue
+	}
+	account, err := s.getAssetAccount(ctx, userId, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	valuation := model.AssetValuation{
+		UserId:    userId,
+		AccountId: accountId,
+		Source:    model.AssetValuationManual,
+		Value:     input.Value,
+		Note:      input.Note,
+	}
+	if input.ValuedOn != nil {
+		valuation.ValuedOn = *input.ValuedOn
+	} else {
+		valuation.ValuedOn = truncateToDay(s.now())
+	}
+	valuation.Id, err = s.repo.CreateValuation(ctx, valuation)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.depreciate(ctx, *account); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Int64("accountId", accountId).Msg("failed to depreciate revalued asset")
+	}
+	return &valuation, nil
+}
+
+// GetSummary returns the current value and the valuations of an asset.
+func (s *AssetService) GetSummary(ctx context.Context, userId, accountId int64) (*AssetSummary, error) {
+	account, err := s.getAssetAccount(ctx, userId, accountId)
+	if err != nil {
+		return nil, err
+	}
+	valuations, err := s.repo.ListValuations(ctx, userId, accountId)
+	if err != nil {
+		return nil, err
+	}
+	return &AssetSummary{
+		Account:      *account,
+		CurrentValue: assetValueAsOf(*account, valuations, s.now()),
+		Valuations:   valuations,
+	}, nil
+}
+
+// CurrentValue returns what an asset account is worth today.
+func (s *AssetService) CurrentValue(ctx context.Context, account model.Account) (decimal.Decimal, error) {
+	valuations, err := s.repo.ListValuations(ctx, account.UserId, account.Id)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return assetValueAsOf(account, valuations, s.now()), nil
+}
+
+// DepreciateAll generates the missing monthly depreciation adjustments of every
+// depreciating asset. It is run periodically; existing adjustments are kept.
+func (s *AssetService) DepreciateAll(ctx context.Context) (int, error) {
+	logger := zerolog.Ctx(ctx)
+
+	accounts, err := s.repo.ListDepreciatingAccounts(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, account := range accounts {
+		count, err := s.depreciate(ctx, account)
+		if err != nil {
+			logger.Error().Err(err).Int64("accountId", account.Id).Msg("failed to depreciate asset")
+			continue
+		}
+		created += count
+	}
+	return created, nil
+}
+
+// depreciate writes one adjustment per month since the latest manual value, or
+// the purchase, up to today.
+func (s *AssetService) depreciate(ctx context.Context, account model.Account) (int, error) {
+	if account.AssetValuationMethod == nil || *account.AssetValuationMethod == model.AssetManual ||
+		account.AssetPurchaseValue == nil || account.AssetPurchaseDate == nil {
+		return 0, nil
+	}
+	today := truncateToDay(s.now())
+
+	valuations, err := s.repo.ListValuations(ctx, account.UserId, account.Id)
+	if err != nil {
+		return 0, err
+	}
+	baseValue, baseDate := *account.AssetPurchaseValue, *account.AssetPurchaseDate
+	var lastAdjustment time.Time
+	for _, valuation := range valuations {
+		if valuation.ValuedOn.After(today) {
+			continue
+		}
+		switch valuation.Source {
+		case model.AssetValuationManual:
+			baseValue, baseDate = valuation.Value, valuation.ValuedOn
+			lastAdjustment = time.Time{}
+		case model.AssetValuationDepreciation:
+			lastAdjustment = valuation.ValuedOn
+		}
+	}
+
+	created := 0
+	for months := 1; months <= maxDepreciationMonths; months++ {
+		date := addMonthsClamped(baseDate, months)
+		if date.After(today) {
+			break
+		}
+		value := depreciatedValue(account, baseValue, months)
+		if date.After(lastAdjustment) {
+			ok, err := s.repo.CreateDepreciation(ctx, model.AssetValuation{
+				UserId:    account.UserId,
+				AccountId: account.Id,
+				Source:    model.AssetValuationDepreciation,
+				Value:     value,
+				ValuedOn:  date,
+				Note:      string(*account.AssetValuationMethod) + " depreciation",
+			})
+			if err != nil {
+				return created, err
+			}
+			if ok {
+				created++
+			}
+		}
+		if value.Equal(assetSalvageValue(account)) {
+			break
+		}
+	}
+	return created, nil
+}
+
+func (s *AssetService) getAssetAccount(ctx context.Context, userId, accountId int64) (*model.Account, error) {
+	account, err := s.accountRepo.GetById(ctx, accountId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAssetAccountNotFound
+		}
+		return nil, err
+	}
+	if account.Type != model.Asset {
+		return nil, ErrAssetAccountNotFound
+	}
+	return account, nil
+}
+
+// depreciatedValue is the value of an asset the given number of months after
+// it was worth baseValue. It never goes below the salvage value.
+func depreciatedValue(account model.Account, baseValue decimal.Decimal, months int) decimal.Decimal {
+	salvage := assetSalvageValue(account)
+	value := baseValue
+
+	switch *account.AssetValuationMethod {
+	case model.AssetStraightLine:
+		if account.AssetUsefulLifeMonths == nil {
+			return baseValue
+		}
+		monthly := account.AssetPurchaseValue.Sub(salvage).Div(decimal.NewFromInt(int64(*account.AssetUsefulLifeMonths)))
+		value = baseValue.Sub(monthly.Mul(decimal.NewFromInt(int64(months))))
+	case model.AssetDecliningBalance:
+		if account.AssetAnnualDepreciationRate == nil {
+			return baseValue
+		}
+		annualFactor := 1 - account.AssetAnnualDepreciationRate.InexactFloat64()/100
+		value = baseValue.Mul(decimal.NewFromFloat(math.Pow(annualFactor, float64(months)/12)))
+	}
+
+	value = value.Round(2)
+	if value.LessThan(salvage) {
+		return salvage
+	}
+	return value
+}
+
+// assetValueAsOf is the latest valuation on or before asOf, or the purchase value.
+func assetValueAsOf(account model.Account, valuations []model.AssetValuation, asOf time.Time) decimal.Decimal {
+	value := decimal.Zero
+	if account.AssetPurchaseValue != nil {
+		value = *account.AssetPurchaseValue
+	}
+	for _, valuation := range valuations {
+		if valuation.ValuedOn.After(asOf) {
+			break
+		}
+		value = valuation.Value
+	}
+	return value
+}
+
+func assetSalvageValue(account model.Account) decimal.Decimal {
+	if account.AssetSalvageValue == nil {
+		return decimal.Zero
+	}
+	return *account.AssetSalvageValue
+}
+
+// addMonthsClamped moves a date by whole months, keeping it on the last day of
+// shorter months instead of overflowing into the next one.
+func addMonthsClamped(date time.Time, months int) time.Time {
+	firstOfMonth := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), min(date.Day(), lastDay), 0, 0, 0, 0, time.UTC)
+}
+
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
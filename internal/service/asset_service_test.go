@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/testhelper"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAssetRepository is a mock for the AssetRepository interface.
+type MockAssetRepository struct {
+	mock.Mock
+}
+
+func (m *MockAssetRepository) ListDepreciatingAccounts(ctx context.Context) ([]model.Account, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Account), args.Error(1)
+}
+
+func (m *MockAssetRepository) CreateValuation(ctx context.Context, valuation model.AssetValuation) (int64, error) {
+	args := m.Called(ctx, valuation)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAssetRepository) CreateDepreciation(ctx context.Context, valuation model.AssetValuation) (bool, error) {
+	args := m.Called(ctx, valuation)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAssetRepository) ListValuations(ctx context.Context, userId, accountId int64) ([]model.AssetValuation, error) {
+	args := m.Called(ctx, userId, accountId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AssetValuation), args.Error(1)
+}
+
+func TestAssetService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	userId := int64(1)
+	now := time.Date(2025, 4, 30, 10, 0, 0, 0, time.UTC)
+	purchaseDate := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	straightLine, decliningBalance := model.AssetStraightLine, model.AssetDecliningBalance
+	car := model.Account{
+		Id:                    10,
+		UserId:                userId,
+		Name:                  "Car",
+		Type:                  model.Asset,
+		AssetPurchaseValue:    testhelper.Ptr(decimal.NewFromInt(60000)),
+		AssetPurchaseDate:     &purchaseDate,
+		AssetValuationMethod:  &straightLine,
+		AssetUsefulLifeMonths: testhelper.Ptr(100),
+		AssetSalvageValue:     testhelper.Ptr(decimal.NewFromInt(10000)),
+	}
+
+	setup := func() (*AssetService, *MockAssetRepository, *MockAccountRepository) {
+		mockRepo := new(MockAssetRepository)
+		mockAccountRepo := new(MockAccountRepository)
+		assetService := NewAssetService(mockRepo, mockAccountRepo)
+		assetService.now = func() time.Time { return now }
+		return assetService, mockRepo, mockAccountRepo
+	}
+
+	t.Run("DepreciateAll", func(t *testing.T) {
+		t.Run("should write one straight-line adjustment per month, clamped to short months", func(t *testing.T) {
+			// Arrange
+			assetService, mockRepo, _ := setup()
+			mockRepo.On("ListDepreciatingAccounts", ctx).Return([]model.Account{car}, nil).Once()
+			mockRepo.On("ListValuations", ctx, userId, car.Id).Return([]model.AssetValuation{}, nil).Once()
+			var adjustments []model.AssetValuation
+			mockRepo.On("CreateDepreciation", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				adjustments = append(adjustments, args.Get(1).(model.AssetValuation))
+			}).Return(true, nil)
+
+			// Act
+			created, err := assetService.DepreciateAll(ctx)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 3, created)
+			// (60,000 - 10,000) / 100 months = 500 a month
+			assert.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), adjustments[0].ValuedOn)
+			assert.True(t, decimal.NewFromInt(59500).Equal(adjustments[0].Value), adjustments[0].Value.String())
+			assert.Equal(t, time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), adjustments[1].ValuedOn)
+			assert.True(t, decimal.NewFromInt(58500).Equal(adjustments[2].Value), adjustments[2].Value.String())
+		})
+
+		t.Run("should continue from the latest manual value and skip existing adjustments", func(t *testing.T) {
+			// Arrange
+			assetService, mockRepo, _ := setup()
+			mockRepo.On("ListDepreciatingAccounts", ctx).Return([]model.Account{car}, nil).Once()
+			mockRepo.On("ListValuations", ctx, userId, car.Id).Return([]model.AssetValuation{
+				{Source: model.AssetValuationDepreciation, Value: decimal.NewFromInt(59500), ValuedOn: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)},
+				{Source: model.AssetValuationManual, Value: decimal.NewFromInt(55000), ValuedOn: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)},
+			}, nil).Once()
+			mockRepo.On("CreateDepreciation", ctx, mock.MatchedBy(func(v model.AssetValuation) bool {
+				return v.ValuedOn.Equal(time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC)) && v.Value.Equal(decimal.NewFromInt(54500))
+			})).Return(true, nil).Once()
+
+			// Act
+			created, err := assetService.DepreciateAll(ctx)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 1, created)
+			mockRepo.AssertExpectations(t)
+		})
+
+		t.Run("should apply the annual declining-balance rate and stop at the salvage value", func(t *testing.T) {
+			// Arrange
+			assetService, mockRepo, _ := setup()
+			assetService.now = func() time.Time { return time.Date(2035, 1, 31, 0, 0, 0, 0, time.UTC) }
+			laptop := car
+			laptop.AssetValuationMethod = &decliningBalance
+			laptop.AssetAnnualDepreciationRate = testhelper.Ptr(decimal.NewFromInt(50))
+			laptop.AssetPurchaseValue = testhelper.Ptr(decimal.NewFromInt(8000))
+			laptop.AssetSalvageValue = testhelper.Ptr(decimal.NewFromInt(1000))
+			mockRepo.On("ListDepreciatingAccounts", ctx).Return([]model.Account{laptop}, nil).Once()
+			mockRepo.On("ListValuations", ctx, userId, laptop.Id).Return([]model.AssetValuation{}, nil).Once()
+			var adjustments []model.AssetValuation
+			mockRepo.On("CreateDepreciation", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				adjustments = append(adjustments, args.Get(1).(model.AssetValuation))
+			}).Return(true, nil)
+
+			// Act
+			_, err := assetService.DepreciateAll(ctx)
+
+			// Assert
+			assert.NoError(t, err)
+			// Half of the value is gone after twelve months.
+			assert.True(t, decimal.NewFromInt(4000).Equal(adjustments[11].Value), adjustments[11].Value.String())
+			last := adjustments[len(adjustments)-1]
+			assert.True(t, decimal.NewFromInt(1000).Equal(last.Value), last.Value.String())
+			// 8,000 halves to below 1,000 during the fourth year, well before 2035.
+			assert.Less(t, len(adjustments), 48)
+		})
+	})
+
+	t.Run("CurrentValue", func(t *testing.T) {
+		t.Run("should use the latest valuation up to today, or the purchase value", func(t *testing.T) {
+			// Arrange
+			assetService, mockRepo, _ := setup()
+			mockRepo.On("ListValuations", ctx, userId, car.Id).Return([]model.AssetValuation{}, nil).Once()
+			mockRepo.On("ListValuations", ctx, userId, car.Id).Return([]model.AssetValuation{
+				{Value: decimal.NewFromInt(58000), ValuedOn: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)},
+				{Value: decimal.NewFromInt(50000), ValuedOn: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)},
+			}, nil).Once()
+
+			// Act
+			purchaseValue, _ := assetService.CurrentValue(ctx, car)
+			currentValue, err := assetService.CurrentValue(ctx, car)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.True(t, decimal.NewFromInt(60000).Equal(purchaseValue))
+			assert.True(t, decimal.NewFromInt(58000).Equal(currentValue))
+		})
+	})
+
+	t.Run("Revalue", func(t *testing.T) {
+		t.Run("should reject accounts that are not assets", func(t *testing.T) {
+			// Arrange
+			assetService, mockRepo, mockAccountRepo := setup()
+			mockAccountRepo.On("GetById", ctx, int64(20), userId).Return(&model.Account{Id: 20, Type: model.Savings}, nil).Once()
+
+			// Act
+			_, err := assetService.Revalue(ctx, userId, 20, RevalueAssetInput{Value: decimal.NewFromInt(100)})
+
+			// Assert
+			assert.ErrorIs(t, err, ErrAssetAccountNotFound)
+			mockRepo.AssertNotCalled(t, "CreateValuation", mock.Anything, mock.Anything)
+		})
+	})
+}
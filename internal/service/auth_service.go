@@ -11,6 +11,8 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+var ErrUserDisabled = errors.New("user account is disabled")
+
 type AuthService struct {
 	userRepo repository.UserRepository
 	jwtKey   []byte
@@ -34,11 +36,21 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (string
 		return "", errors.New("invalid credentials")
 	}
 
+	if user.DisabledAt != nil {
+		return "", ErrUserDisabled
+	}
+
 	// Credenciais válidas, gerar token
+	return s.IssueToken(user.Id)
+}
+
+// IssueToken generates a signed JWT for the given user, valid for 24 hours.
+// It is shared by every login flow (password, magic link).
+func (s *AuthService) IssueToken(userId int64) (string, error) {
 	expirationTime := time.Now().Add(24 * time.Hour)
 	claims := &jwt.RegisteredClaims{
 		ExpiresAt: jwt.NewNumericDate(expirationTime),
-		Subject:   strconv.FormatInt(user.Id, 10),
+		Subject:   strconv.FormatInt(userId, 10),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
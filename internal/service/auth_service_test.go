@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
@@ -67,6 +68,19 @@ func TestAuthService_Login(t *testing.T) {
 			expectError:   true,
 			expectedError: "invalid credentials",
 		},
+		{
+			name:     "Failure: disabled user should not receive a token",
+			email:    "disabled@example.com",
+			password: validPassword,
+			setupMock: func(mockRepo *MockUserRepository) {
+				disabledAt := time.Now()
+				disabledUser := &model.User{Id: 2, Email: "disabled@example.com", PasswordHash: string(hashedPassword), DisabledAt: &disabledAt}
+				mockRepo.On("GetByEmail", ctx, "disabled@example.com").Return(disabledUser, nil).Once()
+			},
+			expectToken:   false,
+			expectError:   true,
+			expectedError: "user account is disabled",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrBenefitAccountNotFound    = errors.New("benefit account not found")
+	ErrBenefitCategoryNotAllowed = errors.New("the category is not allowed for this benefit account")
+	ErrBenefitAccountTransfer    = errors.New("benefit accounts cannot send or receive transfers")
+)
+
+// BenefitService credits meal and food voucher (VR/VA) accounts every month and
+// writes off the unused balance of the accounts that expire it.
+type BenefitService struct {
+	repo         repository.BenefitRepository
+	accountRepo  repository.AccountRepository
+	quotas       *QuotaService
+	transactions *TransactionService
+	now          func() time.Time
+}
+
+// NewBenefitService creates a new instance of BenefitService.
+func NewBenefitService(repo repository.BenefitRepository, accountRepo repository.AccountRepository, quotas *QuotaService, transactions *TransactionService) *BenefitService {
+	return &BenefitService{
+		repo:         repo,
+		accountRepo:  accountRepo,
+		quotas:       quotas,
+		transactions: transactions,
+		now:          time.Now,
+	}
+}
+
+// CreditDueAccounts credits every benefit account whose credit day of the
+// current month has arrived. It is run periodically; a month is credited once.
+func (s *BenefitService) CreditDueAccounts(ctx context.Context) (int, error) {
+	logger := zerolog.Ctx(ctx)
+	now := s.now()
+
+	accounts, err := s.repo.ListScheduledAccounts(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	credited := 0
+	for _, account := range accounts {
+		creditDate := benefitCreditDate(now.Year(), now.Month(), *account.BenefitCreditDay)
+		if now.Before(creditDate) {
+			continue
+		}
+		ok, err := s.credit(ctx, account, creditDate)
+		if err != nil {
+			logger.Error().Err(err).Int64("accountId", account.Id).Msg("failed to credit benefit account")
+			continue
+		}
+		if ok {
+			credited++
+		}
+	}
+	return credited, nil
+}
+
+func (s *BenefitService) credit(ctx context.Context, account model.Account, creditDate time.Time) (bool, error) {
+	credit := model.BenefitCredit{
+		UserId:        account.UserId,
+		AccountId:     account.Id,
+		Year:          creditDate.Year(),
+		Month:         int(creditDate.Month()),
+		Amount:        *account.BenefitMonthlyCredit,
+		ExpiredAmount: decimal.Zero,
+	}
+
+	var transactions []model.Transaction
+	if account.BenefitExpiresUnused {
+		balance, err := s.accountRepo.GetCurrentBalance(ctx, account.Id, account.UserId)
+		if err != nil {
+			return false, err
+		}
+		if balance.IsPositive() {
+			credit.ExpiredAmount = balance
+			transactions = append(transactions, model.Transaction{
+				UserId:      account.UserId,
+				Description: "Expired benefit balance",
+				Amount:      balance,
+				Date:        creditDate,
+				Type:        model.Expense,
+				AccountId:   account.Id,
+			})
+		}
+	}
+	transactions = append(transactions, model.Transaction{
+		UserId:      account.UserId,
+		Description: fmt.Sprintf("%s credit %04d-%02d", account.Name, credit.Year, credit.Month),
+		Amount:      credit.Amount,
+		Date:        creditDate,
+		Type:        model.Income,
+		AccountId:   account.Id,
+	})
+
+	if err := s.quotas.CheckTransactionCreation(ctx, account.UserId, int64(len(transactions))); err != nil {
+		return false, err
+	}
+	ok, err := s.repo.RecordCredit(ctx, credit, transactions)
+	if err != nil || !ok {
+		return ok, err
+	}
+	for _, transaction := range transactions {
+		s.transactions.NotifyCreated(ctx, transaction)
+	}
+	return true, nil
+}
+
+// ListCredits returns the monthly credits of a benefit account, newest first.
+func (s *BenefitService) ListCredits(ctx context.Context, userId, accountId int64) ([]model.BenefitCredit, error) {
+	account, err := s.accountRepo.GetById(ctx, accountId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrBenefitAccountNotFound
+		}
+		return nil, err
+	}
+	if account.Type != model.Benefit {
+		return nil, ErrBenefitAccountNotFound
+	}
+	return s.repo.ListCredits(ctx, userId, accountId)
+}
+
+// checkBenefitTransaction enforces the rules of benefit accounts on a
+// transaction paid from one: no transfers and only the allowed categories.
+func checkBenefitTransaction(account *model.Account, tx model.Transaction) error {
+	if account.Type != model.Benefit {
+		return nil
+	}
+	if tx.Type == model.Transfer {
+		return ErrBenefitAccountTransfer
+	}
+	if tx.Type == model.Expense && !account.AllowsBenefitCategory(tx.CategoryId) {
+		return ErrBenefitCategoryNotAllowed
+	}
+	return nil
+}
+
+// benefitCreditDate is the credit day in the given month, moved back to the
+// last day of shorter months.
+func benefitCreditDate(year int, month time.Month, day int) time.Time {
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return time.Date(year, month, min(day, lastDay), 0, 0, 0, 0, time.UTC)
+}
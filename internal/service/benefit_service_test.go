@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/testhelper"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBenefitRepository is a mock for the BenefitRepository interface.
+type MockBenefitRepository struct {
+	mock.Mock
+}
+
+func (m *MockBenefitRepository) ListScheduledAccounts(ctx context.Context) ([]model.Account, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Account), args.Error(1)
+}
+
+func (m *MockBenefitRepository) RecordCredit(ctx context.Context, credit model.BenefitCredit, transactions []model.Transaction) (bool, error) {
+	args := m.Called(ctx, credit, transactions)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBenefitRepository) ListCredits(ctx context.Context, userId, accountId int64) ([]model.BenefitCredit, error) {
+	args := m.Called(ctx, userId, accountId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.BenefitCredit), args.Error(1)
+}
+
+func TestBenefitService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	userId := int64(1)
+	now := time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC)
+
+	setup := func() (*BenefitService, *MockBenefitRepository, *MockAccountRepository) {
+		mockRepo := new(MockBenefitRepository)
+		mockAccountRepo := new(MockAccountRepository)
+		benefitService := NewBenefitService(mockRepo, mockAccountRepo, unlimitedQuotas(), NewTransactionService(nil, mockAccountRepo, unlimitedQuotas(), nil, TransactionOptions{}))
+		benefitService.now = func() time.Time { return now }
+		return benefitService, mockRepo, mockAccountRepo
+	}
+
+	t.Run("CreditDueAccounts", func(t *testing.T) {
+		t.Run("should credit on the last day of short months", func(t *testing.T) {
+			// Arrange
+			benefitService, mockRepo, _ := setup()
+			vr := model.Account{Id: 10, UserId: userId, Name: "VR", Type: model.Benefit, BenefitMonthlyCredit: testhelper.Ptr(decimal.NewFromInt(800)), BenefitCreditDay: testhelper.Ptr(31)}
+			mockRepo.On("ListScheduledAccounts", ctx).Return([]model.Account{vr}, nil).Once()
+			var recorded []model.Transaction
+			mockRepo.On("RecordCredit", ctx, mock.MatchedBy(func(c model.BenefitCredit) bool {
+				return c.AccountId == vr.Id && c.Year == 2025 && c.Month == 2 && c.Amount.Equal(decimal.NewFromInt(800))
+			}), mock.Anything).Run(func(args mock.Arguments) {
+				recorded = args.Get(2).([]model.Transaction)
+			}).Return(true, nil).Once()
+
+			// Act
+			credited, err := benefitService.CreditDueAccounts(ctx)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 1, credited)
+			assert.Len(t, recorded, 1)
+			assert.Equal(t, model.Income, recorded[0].Type)
+			assert.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), recorded[0].Date)
+			mockRepo.AssertExpectations(t)
+		})
+
+		t.Run("should skip accounts whose credit day has not arrived", func(t *testing.T) {
+			// Arrange
+			benefitService, mockRepo, _ := setup()
+			benefitService.now = func() time.Time { return time.Date(2025, 2, 10, 9, 0, 0, 0, time.UTC) }
+			va := model.Account{Id: 20, UserId: userId, Name: "VA", Type: model.Benefit, BenefitMonthlyCredit: testhelper.Ptr(decimal.NewFromInt(500)), BenefitCreditDay: testhelper.Ptr(15)}
+			mockRepo.On("ListScheduledAccounts", ctx).Return([]model.Account{va}, nil).Once()
+
+			// Act
+			credited, err := benefitService.CreditDueAccounts(ctx)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 0, credited)
+			mockRepo.AssertNotCalled(t, "RecordCredit", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		t.Run("should write off the unused balance before crediting", func(t *testing.T) {
+			// Arrange
+			benefitService, mockRepo, mockAccountRepo := setup()
+			va := model.Account{Id: 10, UserId: userId, Name: "VA", Type: model.Benefit, BenefitMonthlyCredit: testhelper.Ptr(decimal.NewFromInt(600)), BenefitCreditDay: testhelper.Ptr(1), BenefitExpiresUnused: true}
+			mockRepo.On("ListScheduledAccounts", ctx).Return([]model.Account{va}, nil).Once()
+			mockAccountRepo.On("GetCurrentBalance", ctx, va.Id, userId).Return(decimal.RequireFromString("42.50"), nil).Once()
+			var credit model.BenefitCredit
+			var recorded []model.Transaction
+			mockRepo.On("RecordCredit", ctx, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				credit = args.Get(1).(model.BenefitCredit)
+				recorded = args.Get(2).([]model.Transaction)
+			}).Return(true, nil).Once()
+
+			// Act
+			_, err := benefitService.CreditDueAccounts(ctx)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.True(t, credit.ExpiredAmount.Equal(decimal.RequireFromString("42.50")))
+			assert.Len(t, recorded, 2)
+			assert.Equal(t, model.Expense, recorded[0].Type)
+			assert.True(t, recorded[0].Amount.Equal(decimal.RequireFromString("42.50")))
+			assert.Equal(t, model.Income, recorded[1].Type)
+		})
+
+		t.Run("should not count a month that was already credited", func(t *testing.T) {
+			// Arrange
+			benefitService, mockRepo, _ := setup()
+			vr := model.Account{Id: 10, UserId: userId, Name: "VR", Type: model.Benefit, BenefitMonthlyCredit: testhelper.Ptr(decimal.NewFromInt(800)), BenefitCreditDay: testhelper.Ptr(5)}
+			mockRepo.On("ListScheduledAccounts", ctx).Return([]model.Account{vr}, nil).Once()
+			mockRepo.On("RecordCredit", ctx, mock.Anything, mock.Anything).Return(false, nil).Once()
+
+			// Act
+			credited, err := benefitService.CreditDueAccounts(ctx)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 0, credited)
+		})
+	})
+
+	t.Run("ListCredits", func(t *testing.T) {
+		t.Run("should reject accounts that are not benefit accounts", func(t *testing.T) {
+			// Arrange
+			benefitService, mockRepo, mockAccountRepo := setup()
+			mockAccountRepo.On("GetById", ctx, int64(10), userId).Return(&model.Account{Id: 10, Type: model.Checking}, nil).Once()
+
+			// Act
+			_, err := benefitService.ListCredits(ctx, userId, 10)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrBenefitAccountNotFound)
+			mockRepo.AssertNotCalled(t, "ListCredits", mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+}
@@ -0,0 +1,536 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+var ErrTelegramChatNotFound = errors.New("telegram chat not found")
+
+const (
+	// linkCodeAlphabet leaves out characters that are easy to confuse when typed.
+	linkCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	linkCodeLength   = 8
+)
+
+// budgetAlertThresholds are the usage percentages announced to linked chats,
+// highest first.
+var budgetAlertThresholds = []int{100, 80}
+
+const botHelpMessage = `Comandos disponíveis:
+/gasto 45,90 ifood #alimentacao @nubank - registra um gasto; categoria e conta são opcionais
+/saldo - saldo das contas
+/orcamento - orçamentos do mês
+/fatura nubank - fatura aberta do cartão
+/conta nubank - define a conta padrão dos gastos
+/desvincular - desvincula este chat`
+
+const botUnlinkedMessage = "Este chat ainda não está vinculado. Gere um código no app e envie /start CODIGO."
+
+// BotMessenger delivers the bot's replies. It is implemented by the Telegram client.
+type BotMessenger interface {
+	SendMessage(ctx context.Context, chatId int64, text string) error
+}
+
+// BotOptions holds the deployment-specific settings of the chat bot.
+type BotOptions struct {
+	// LinkCodeTTL is how long a link code stays valid after being issued.
+	LinkCodeTTL time.Duration
+}
+
+// BotService implements the chat bot: it links chats to users through one-time
+// codes, answers commands on their behalf and sends budget alerts.
+type BotService struct {
+	repo               repository.TelegramRepository
+	userRepo           repository.UserRepository
+	categoryRepo       repository.CategoryRepository
+	accountService     *AccountService
+	transactionService *TransactionService
+	budgetService      *BudgetService
+	messenger          BotMessenger
+	options            BotOptions
+	now                func() time.Time
+}
+
+// NewBotService creates a new instance of BotService.
+func NewBotService(
+	repo repository.TelegramRepository,
+	userRepo repository.UserRepository,
+	categoryRepo repository.CategoryRepository,
+	accountService *AccountService,
+	transactionService *TransactionService,
+	budgetService *BudgetService,
+	messenger BotMessenger,
+	options BotOptions,
+) *BotService {
+	return &BotService{
+		repo:               repo,
+		userRepo:           userRepo,
+		categoryRepo:       categoryRepo,
+		accountService:     accountService,
+		transactionService: transactionService,
+		budgetService:      budgetService,
+		messenger:          messenger,
+		options:            options,
+		now:                time.Now,
+	}
+}
+
+// CreateLinkCode issues a one-time code the user sends to the bot with /start.
+func (s *BotService) CreateLinkCode(ctx context.Context, userId int64) (string, time.Time, error) {
+	code, err := generateLinkCode()
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate link code: %w", err)
+	}
+	expiresAt := s.now().UTC().Add(s.options.LinkCodeTTL)
+	_, err = s.repo.CreateLinkCode(ctx, model.TelegramLinkCode{
+		UserId:    userId,
+		CodeHash:  hashToken(code),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return code, expiresAt, nil
+}
+
+// ListChats returns the chats linked to the user.
+func (s *BotService) ListChats(ctx context.Context, userId int64) ([]model.TelegramChat, error) {
+	return s.repo.ListChats(ctx, userId)
+}
+
+// Unlink removes a chat of the user. The bot stops answering it.
+func (s *BotService) Unlink(ctx context.Context, userId, chatId int64) error {
+	if err := s.repo.DeleteChat(ctx, chatId, userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTelegramChatNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// HandleMessage answers a message sent to the bot. Mistakes in the command are
+// answered in the chat; only unexpected failures are returned.
+func (s *BotService) HandleMessage(ctx context.Context, chatId int64, text string) error {
+	command, args := parseBotCommand(text)
+	if command == "start" {
+		return s.reply(ctx, chatId, s.link(ctx, chatId, args))
+	}
+
+	chat, err := s.repo.GetChat(ctx, chatId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return s.reply(ctx, chatId, botUnlinkedMessage)
+		}
+		return err
+	}
+	user, err := s.userRepo.GetById(ctx, chat.UserId)
+	if err != nil {
+		return err
+	}
+	if user.DisabledAt != nil {
+		return s.reply(ctx, chatId, "Sua conta está suspensa.")
+	}
+
+	var reply string
+	var logged bool
+	switch command {
+	case "gasto":
+		reply, logged, err = s.logExpense(ctx, *chat, args)
+	case "saldo":
+		reply, err = s.balances(ctx, *chat)
+	case "orcamento":
+		reply, err = s.budgets(ctx, *chat)
+	case "fatura":
+		reply, err = s.statement(ctx, *chat, args)
+	case "conta":
+		reply, err = s.setDefaultAccount(ctx, *chat, args)
+	case "desvincular":
+		err = s.repo.DeleteChat(ctx, chatId, chat.UserId)
+		reply = "Chat desvinculado. Até logo!"
+	default:
+		reply = botHelpMessage
+	}
+	if err != nil {
+		if replyErr := s.reply(ctx, chatId, "Algo deu errado, tente novamente mais tarde."); replyErr != nil {
+			zerolog.Ctx(ctx).Error().Err(replyErr).Int64("chatId", chatId).Msg("failed to send bot error reply")
+		}
+		return err
+	}
+	if err := s.reply(ctx, chatId, reply); err != nil {
+		return err
+	}
+	if logged {
+		if _, err := s.alertBudgets(ctx, *chat); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Int64("chatId", chatId).Msg("failed to send budget alerts")
+		}
+	}
+	return nil
+}
+
+// SendBudgetAlerts warns every linked chat about the budgets of the current
+// month that reached an alert threshold. Each threshold is announced once.
+func (s *BotService) SendBudgetAlerts(ctx context.Context) (int, error) {
+	chats, err := s.repo.ListAllChats(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, chat := range chats {
+		alerts, err := s.alertBudgets(ctx, chat)
+		sent += alerts
+		if err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Int64("chatId", chat.ChatId).Msg("failed to send budget alerts")
+		}
+	}
+	return sent, nil
+}
+
+func (s *BotService) alertBudgets(ctx context.Context, chat model.TelegramChat) (int, error) {
+	year, month, err := s.budgetService.PeriodOf(ctx, chat.UserId, s.now())
+	if err != nil {
+		return 0, err
+	}
+	budgets, err := s.budgetService.ListEnrichedBudgetsByPeriod(ctx, chat.UserId, month, year)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, budget := range budgets {
+		if !budget.Amount.IsPositive() {
+			continue
+		}
+		usage := budget.SpentAmount.Div(budget.Amount).Mul(decimal.NewFromInt(100))
+		for _, threshold := range budgetAlertThresholds {
+			if usage.LessThan(decimal.NewFromInt(int64(threshold))) {
+				continue
+			}
+			recorded, err := s.repo.RecordBudgetAlert(ctx, chat.ChatId, budget.Id, threshold)
+			if err != nil {
+				return sent, err
+			}
+			if recorded {
+				if err := s.reply(ctx, chat.ChatId, budgetAlertMessage(budget, usage)); err != nil {
+					return sent, err
+				}
+				sent++
+			}
+			break
+		}
+	}
+	return sent, nil
+}
+
+func (s *BotService) link(ctx context.Context, chatId int64, args []string) string {
+	if len(args) == 0 {
+		return botUnlinkedMessage
+	}
+	code := strings.ToUpper(args[0])
+	if _, err := s.repo.ConsumeLinkCode(ctx, hashToken(code), chatId); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			zerolog.Ctx(ctx).Error().Err(err).Int64("chatId", chatId).Msg("failed to link telegram chat")
+		}
+		return "Código inválido ou expirado. Gere um novo código no app."
+	}
+	return "Chat vinculado! 🎉\n\n" + botHelpMessage
+}
+
+// logExpense creates the expense of a /gasto command and reports whether it was logged.
+func (s *BotService) logExpense(ctx context.Context, chat model.TelegramChat, args []string) (string, bool, error) {
+	const usage = "Use: /gasto 45,90 descrição #categoria @conta"
+	if len(args) == 0 {
+		return usage, false, nil
+	}
+	amount, err := parseBotAmount(args[0])
+	if err != nil || !amount.IsPositive() {
+		return usage, false, nil
+	}
+
+	var words []string
+	var accountQuery, categoryQuery string
+	for _, arg := range args[1:] {
+		switch {
+		case strings.HasPrefix(arg, "@") && len(arg) > 1:
+			accountQuery = arg[1:]
+		case strings.HasPrefix(arg, "#") && len(arg) > 1:
+			categoryQuery = arg[1:]
+		default:
+			words = append(words, arg)
+		}
+	}
+
+	accounts, err := s.accountService.ListAccountsByUserId(ctx, chat.UserId)
+	if err != nil {
+		return "", false, err
+	}
+	var account *model.Account
+	if accountQuery != "" {
+		account = matchAccount(accounts, accountQuery)
+	} else {
+		account = defaultBotAccount(accounts, chat.DefaultAccountId)
+	}
+	if account == nil {
+		return "Não encontrei a conta. Indique com @conta ou defina uma padrão com /conta.", false, nil
+	}
+
+	tx := model.Transaction{
+		UserId:      chat.UserId,
+		Description: strings.Join(words, " "),
+		Amount:      amount,
+		Date:        s.now(),
+		Type:        model.Expense,
+		AccountId:   account.Id,
+	}
+	if categoryQuery != "" {
+		categories, err := s.categoryRepo.ListByUserId(ctx, chat.UserId)
+		if err != nil {
+			return "", false, err
+		}
+		i := slices.IndexFunc(categories, func(c model.Category) bool {
+			return c.Type == model.Expense && strings.EqualFold(c.Name, categoryQuery)
+		})
+		if i < 0 {
+			return fmt.Sprintf("Não encontrei a categoria %q.", categoryQuery), false, nil
+		}
+		tx.CategoryId = &categories[i].Id
+		if tx.Description == "" {
+			tx.Description = categories[i].Name
+		}
+	}
+	if tx.Description == "" {
+		tx.Description = "Gasto via Telegram"
+	}
+
+	if _, err := s.transactionService.CreateTransaction(ctx, tx); err != nil {
+		if errors.Is(err, ErrQuotaExceeded) {
+			return "Você atingiu o limite de transações do seu plano.", false, nil
+		}
+		// Like the API, business rule violations are reported back as they are.
+		return "Não consegui registrar o gasto: " + err.Error(), false, nil
+	}
+	return fmt.Sprintf("✅ Gasto de %s registrado em %s: %s", formatBotMoney(amount), account.Name, tx.Description), true, nil
+}
+
+func (s *BotService) balances(ctx context.Context, chat model.TelegramChat) (string, error) {
+	accounts, err := s.accountService.ListAccountsByUserId(ctx, chat.UserId)
+	if err != nil {
+		return "", err
+	}
+
+	var reply strings.Builder
+	reply.WriteString("Saldos:")
+	for _, account := range accounts {
+		if account.Type == model.Points {
+			continue
+		}
+		fmt.Fprintf(&reply, "\n• %s: %s", account.Name, formatBotMoney(account.Balance))
+	}
+	if reply.Len() == len("Saldos:") {
+		return "Você ainda não tem contas.", nil
+	}
+	return reply.String(), nil
+}
+
+func (s *BotService) budgets(ctx context.Context, chat model.TelegramChat) (string, error) {
+	year, month, err := s.budgetService.PeriodOf(ctx, chat.UserId, s.now())
+	if err != nil {
+		return "", err
+	}
+	budgets, err := s.budgetService.ListEnrichedBudgetsByPeriod(ctx, chat.UserId, month, year)
+	if err != nil {
+		return "", err
+	}
+	period := fmt.Sprintf("%02d/%d", month, year)
+	if len(budgets) == 0 {
+		return "Nenhum orçamento para " + period + ".", nil
+	}
+
+	var reply strings.Builder
+	fmt.Fprintf(&reply, "Orçamentos de %s:", period)
+	for _, budget := range budgets {
+		fmt.Fprintf(&reply, "\n• %s: %s de %s", budget.CategoryName, formatBotMoney(budget.SpentAmount), formatBotMoney(budget.Amount))
+		if budget.Amount.IsPositive() {
+			fmt.Fprintf(&reply, " (%s%%)", budget.SpentAmount.Div(budget.Amount).Mul(decimal.NewFromInt(100)).Round(0))
+		}
+	}
+	return reply.String(), nil
+}
+
+func (s *BotService) statement(ctx context.Context, chat model.TelegramChat, args []string) (string, error) {
+	accounts, err := s.accountService.ListAccountsByUserId(ctx, chat.UserId)
+	if err != nil {
+		return "", err
+	}
+	cards := slices.DeleteFunc(accounts, func(a model.Account) bool { return a.Type != model.CreditCard })
+
+	var card *model.Account
+	switch {
+	case len(args) > 0:
+		card = matchAccount(cards, strings.Join(args, " "))
+	case len(cards) == 1:
+		card = &cards[0]
+	}
+	if card == nil {
+		return "Não encontrei o cartão. Use: /fatura nome-do-cartão", nil
+	}
+	if card.StatementClosingDay == nil || card.PaymentDueDay == nil {
+		return fmt.Sprintf("O cartão %s não tem dia de fechamento e vencimento configurados.", card.Name), nil
+	}
+
+	// The open statement is the one after the last closed statement.
+	year, month, err := s.accountService.lastClosedStatement(ctx, card, s.now())
+	if err != nil {
+		return "", err
+	}
+	if month == 12 {
+		year, month = year+1, 1
+	} else {
+		month++
+	}
+	details, err := s.accountService.GetStatementDetails(ctx, chat.UserId, card.Id, year, month)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(
+		"Fatura aberta do %s\nPeríodo: %s a %s\nVencimento: %s\nTotal até agora: %s",
+		details.AccountName,
+		details.StatementPeriod.Start.Format("02/01"),
+		details.StatementPeriod.End.Format("02/01"),
+		details.PaymentDueDate.Format("02/01/2006"),
+		formatBotMoney(details.StatementTotal),
+	), nil
+}
+
+func (s *BotService) setDefaultAccount(ctx context.Context, chat model.TelegramChat, args []string) (string, error) {
+	accounts, err := s.accountService.ListAccountsByUserId(ctx, chat.UserId)
+	if err != nil {
+		return "", err
+	}
+	if len(args) == 0 {
+		if account := defaultBotAccount(accounts, chat.DefaultAccountId); account != nil {
+			return fmt.Sprintf("Os gastos vão para %s. Use /conta nome para trocar.", account.Name), nil
+		}
+		return "Use: /conta nome-da-conta", nil
+	}
+
+	account := matchAccount(accounts, strings.Join(args, " "))
+	if account == nil {
+		return "Não encontrei a conta.", nil
+	}
+	if err := s.repo.SetDefaultAccount(ctx, chat.ChatId, &account.Id); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Pronto! Os gastos vão para %s.", account.Name), nil
+}
+
+func (s *BotService) reply(ctx context.Context, chatId int64, text string) error {
+	return s.messenger.SendMessage(ctx, chatId, text)
+}
+
+// parseBotCommand splits "/gasto@MyBot 45,90 ifood" into "gasto" and its
+// arguments. Text that is not a command yields an empty command.
+func parseBotCommand(text string) (string, []string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return "", fields
+	}
+	command, _, _ := strings.Cut(strings.TrimPrefix(fields[0], "/"), "@")
+	return strings.ToLower(command), fields[1:]
+}
+
+// parseBotAmount accepts amounts written the Brazilian way, like "1.234,56" and
+// "45,90", as well as "45.90".
+func parseBotAmount(value string) (decimal.Decimal, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "R$")
+	if strings.Contains(value, ",") {
+		value = strings.ReplaceAll(value, ".", "")
+		value = strings.Replace(value, ",", ".", 1)
+	}
+	return decimal.NewFromString(value)
+}
+
+// formatBotMoney formats an amount as "R$ 1.234,56".
+func formatBotMoney(amount decimal.Decimal) string {
+	integer, fraction, _ := strings.Cut(amount.Abs().StringFixed(2), ".")
+	var grouped strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	sign := ""
+	if amount.IsNegative() {
+		sign = "-"
+	}
+	return fmt.Sprintf("R$ %s%s,%s", sign, grouped.String(), fraction)
+}
+
+func budgetAlertMessage(budget EnrichedBudget, usage decimal.Decimal) string {
+	if usage.GreaterThanOrEqual(decimal.NewFromInt(100)) {
+		return fmt.Sprintf("🚨 O orçamento de %s estourou: %s de %s.", budget.CategoryName, formatBotMoney(budget.SpentAmount), formatBotMoney(budget.Amount))
+	}
+	return fmt.Sprintf("⚠️ Você já usou %s%% do orçamento de %s: %s de %s.", usage.Round(0), budget.CategoryName, formatBotMoney(budget.SpentAmount), formatBotMoney(budget.Amount))
+}
+
+// matchAccount finds an account by name, ignoring case. Without an exact match,
+// it accepts the only account whose name contains the query.
+func matchAccount(accounts []model.Account, query string) *model.Account {
+	query = strings.ToLower(strings.TrimSpace(query))
+	var partial []int
+	for i, account := range accounts {
+		name := strings.ToLower(account.Name)
+		if name == query {
+			return &accounts[i]
+		}
+		if strings.Contains(name, query) {
+			partial = append(partial, i)
+		}
+	}
+	if len(partial) == 1 {
+		return &accounts[partial[0]]
+	}
+	return nil
+}
+
+// defaultBotAccount returns the chat's default account or, when it has none,
+// the user's first checking account.
+func defaultBotAccount(accounts []model.Account, defaultAccountId *int64) *model.Account {
+	for i, account := range accounts {
+		if defaultAccountId != nil && account.Id == *defaultAccountId {
+			return &accounts[i]
+		}
+	}
+	for i, account := range accounts {
+		if account.Type == model.Checking {
+			return &accounts[i]
+		}
+	}
+	return nil
+}
+
+// generateLinkCode returns a short random code that is easy to type in a chat.
+func generateLinkCode() (string, error) {
+	b := make([]byte, linkCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = linkCodeAlphabet[int(b[i])%len(linkCodeAlphabet)]
+	}
+	return string(b), nil
+}
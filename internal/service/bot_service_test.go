@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/testhelper"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTelegramRepository struct {
+	mock.Mock
+}
+
+func (m *MockTelegramRepository) CreateLinkCode(ctx context.Context, code model.TelegramLinkCode) (int64, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTelegramRepository) ConsumeLinkCode(ctx context.Context, codeHash string, chatId int64) (int64, error) {
+	args := m.Called(ctx, codeHash, chatId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTelegramRepository) GetChat(ctx context.Context, chatId int64) (*model.TelegramChat, error) {
+	args := m.Called(ctx, chatId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TelegramChat), args.Error(1)
+}
+
+func (m *MockTelegramRepository) ListChats(ctx context.Context, userId int64) ([]model.TelegramChat, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.TelegramChat), args.Error(1)
+}
+
+func (m *MockTelegramRepository) ListAllChats(ctx context.Context) ([]model.TelegramChat, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.TelegramChat), args.Error(1)
+}
+
+func (m *MockTelegramRepository) SetDefaultAccount(ctx context.Context, chatId int64, accountId *int64) error {
+	args := m.Called(ctx, chatId, accountId)
+	return args.Error(0)
+}
+
+func (m *MockTelegramRepository) DeleteChat(ctx context.Context, chatId, userId int64) error {
+	args := m.Called(ctx, chatId, userId)
+	return args.Error(0)
+}
+
+func (m *MockTelegramRepository) RecordBudgetAlert(ctx context.Context, chatId, budgetId int64, threshold int) (bool, error) {
+	args := m.Called(ctx, chatId, budgetId, threshold)
+	return args.Bool(0), args.Error(1)
+}
+
+// recordingMessenger keeps the bot replies instead of sending them.
+type recordingMessenger struct {
+	messages []string
+}
+
+func (m *recordingMessenger) SendMessage(ctx context.Context, chatId int64, text string) error {
+	m.messages = append(m.messages, text)
+	return nil
+}
+
+func TestBotService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	userId, chatId := int64(1), int64(555)
+	now := time.Date(2025, 7, 10, 12, 0, 0, 0, time.UTC)
+	checking := model.Account{Id: 10, UserId: userId, Name: "Conta Corrente", Type: model.Checking}
+	card := model.Account{
+		Id: 20, UserId: userId, Name: "Nubank", Type: model.CreditCard,
+		StatementClosingDay: testhelper.Ptr(3), PaymentDueDay: testhelper.Ptr(10),
+	}
+
+	type mocks struct {
+		telegram     *MockTelegramRepository
+		users        *MockUserRepository
+		categories   *MockCategoryRepository
+		accounts     *MockAccountRepository
+		transactions *MockTransactionRepository
+		budgets      *MockBudgetRepository
+		messenger    *recordingMessenger
+	}
+	setup := func() (*BotService, mocks) {
+		m := mocks{
+			telegram:     new(MockTelegramRepository),
+			users:        new(MockUserRepository),
+			categories:   new(MockCategoryRepository),
+			accounts:     new(MockAccountRepository),
+			transactions: new(MockTransactionRepository),
+			budgets:      new(MockBudgetRepository),
+			messenger:    &recordingMessenger{},
+		}
+		botService := NewBotService(
+			m.telegram,
+			m.users,
+			m.categories,
+			NewAccountService(m.accounts, m.transactions, unlimitedQuotas()),
+			NewTransactionService(m.transactions, m.accounts, unlimitedQuotas(), nil, TransactionOptions{}),
+			NewBudgetService(m.budgets, m.categories, m.transactions, calendarMonths(), nil),
+			m.messenger,
+			BotOptions{LinkCodeTTL: 15 * time.Minute},
+		)
+		botService.now = func() time.Time { return now }
+		return botService, m
+	}
+	linked := func(m mocks) {
+		m.telegram.On("GetChat", ctx, chatId).Return(&model.TelegramChat{ChatId: chatId, UserId: userId}, nil)
+		m.users.On("GetById", ctx, userId).Return(&model.User{Id: userId}, nil)
+	}
+	withAccounts := func(m mocks) {
+		m.accounts.On("ListByUserId", ctx, userId).Return([]model.Account{card, checking}, nil)
+		m.accounts.On("GetCurrentBalance", ctx, card.Id, userId).Return(decimal.NewFromInt(-320), nil)
+		m.accounts.On("GetCurrentBalance", ctx, checking.Id, userId).Return(decimal.RequireFromString("1234.5"), nil)
+	}
+
+	t.Run("CreateLinkCode", func(t *testing.T) {
+		t.Run("should store only the hash of the code", func(t *testing.T) {
+			// Arrange
+			botService, m := setup()
+			var stored model.TelegramLinkCode
+			m.telegram.On("CreateLinkCode", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				stored = args.Get(1).(model.TelegramLinkCode)
+			}).Return(int64(1), nil).Once()
+
+			// Act
+			code, expiresAt, err := botService.CreateLinkCode(ctx, userId)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Len(t, code, linkCodeLength)
+			assert.Equal(t, hashToken(code), stored.CodeHash)
+			assert.Equal(t, now.Add(15*time.Minute), expiresAt)
+		})
+	})
+
+	t.Run("HandleMessage", func(t *testing.T) {
+		t.Run("should link the chat with a valid code", func(t *testing.T) {
+			// Arrange
+			botService, m := setup()
+			m.telegram.On("ConsumeLinkCode", ctx, hashToken("K7QX2MZA"), chatId).Return(userId, nil).Once()
+
+			// Act
+			err := botService.HandleMessage(ctx, chatId, "/start k7qx2mza")
+
+			// Assert
+			require.NoError(t, err)
+			require.Len(t, m.messenger.messages, 1)
+			assert.Contains(t, m.messenger.messages[0], "Chat vinculado")
+		})
+
+		t.Run("should reject an expired code", func(t *testing.T) {
+			// Arrange
+			botService, m := setup()
+			m.telegram.On("ConsumeLinkCode", ctx, hashToken("OLDCODE1"), chatId).Return(int64(0), sql.ErrNoRows).Once()
+
+			// Act
+			err := botService.HandleMessage(ctx, chatId, "/start OLDCODE1")
+
+			// Assert
+			require.NoError(t, err)
+			assert.Contains(t, m.messenger.messages[0], "Código inválido")
+		})
+
+		t.Run("should ask unlinked chats to link first", func(t *testing.T) {
+			// Arrange
+			botService, m := setup()
+			m.telegram.On("GetChat", ctx, chatId).Return(nil, sql.ErrNoRows).Once()
+
+			// Act
+			err := botService.HandleMessage(ctx, chatId, "/saldo")
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, []string{botUnlinkedMessage}, m.messenger.messages)
+		})
+
+		t.Run("should log an expense and warn about the budget", func(t *testing.T) {
+			// Arrange
+			botService, m := setup()
+			linked(m)
+			withAccounts(m)
+			foodId := int64(5)
+			m.categories.On("ListByUserId", ctx, userId).Return([]model.Category{{Id: foodId, Name: "Alimentação", Type: model.Expense}}, nil).Once()
+			m.accounts.On("GetById", ctx, checking.Id, userId).Return(&checking, nil).Once()
+			m.transactions.On("Create", ctx, mock.MatchedBy(func(tx model.Transaction) bool {
+				return tx.Amount.Equal(decimal.RequireFromString("1045.90")) && tx.AccountId == checking.Id &&
+					*tx.CategoryId == foodId && tx.Description == "ifood" && tx.Type == model.Expense
+			})).Return(int64(1), nil).Once()
+			m.budgets.On("ListByUserAndPeriod", ctx, userId, 7, 2025).Return([]model.Budget{
+				{Id: 9, CategoryId: foodId, Amount: decimal.NewFromInt(2000), CategoryName: "Alimentação"},
+			}, nil).Once()
+			m.transactions.On("SumExpensesByCategoryAndPeriod", ctx, userId, foodId, mock.Anything, mock.Anything).Return(decimal.NewFromInt(1700), nil).Once()
+			m.telegram.On("RecordBudgetAlert", ctx, chatId, int64(9), 80).Return(true, nil).Once()
+
+			// Act
+			err := botService.HandleMessage(ctx, chatId, "/gasto 1.045,90 ifood #alimentação")
+
+			// Assert
+			require.NoError(t, err)
+			require.Len(t, m.messenger.messages, 2)
+			assert.Equal(t, "✅ Gasto de R$ 1.045,90 registrado em Conta Corrente: ifood", m.messenger.messages[0])
+			assert.Equal(t, "⚠️ Você já usou 85% do orçamento de Alimentação: R$ 1.700,00 de R$ 2.000,00.", m.messenger.messages[1])
+			m.transactions.AssertExpectations(t)
+		})
+
+		t.Run("should explain the usage when the amount is invalid", func(t *testing.T) {
+			// Arrange
+			botService, m := setup()
+			linked(m)
+
+			// Act
+			err := botService.HandleMessage(ctx, chatId, "/gasto muito ifood")
+
+			// Assert
+			require.NoError(t, err)
+			assert.Contains(t, m.messenger.messages[0], "Use: /gasto")
+			m.transactions.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should list the account balances", func(t *testing.T) {
+			// Arrange
+			botService, m := setup()
+			linked(m)
+			withAccounts(m)
+
+			// Act
+			err := botService.HandleMessage(ctx, chatId, "/saldo@GoFinanceBot")
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, "Saldos:\n• Nubank: R$ -320,00\n• Conta Corrente: R$ 1.234,50", m.messenger.messages[0])
+		})
+
+		t.Run("should show the open statement of the card", func(t *testing.T) {
+			// Arrange
+			botService, m := setup()
+			linked(m)
+			withAccounts(m)
+			m.accounts.On("GetById", ctx, card.Id, userId).Return(&card, nil).Once()
+			m.accounts.On("ListBillingCycles", ctx, card.Id, userId).Return(nil, nil).Twice()
+			m.transactions.On("ListByAccountAndDateRange", ctx, userId, card.Id, mock.Anything, mock.Anything).Return([]model.Transaction{
+				{Type: model.Expense, Amount: decimal.NewFromInt(100)},
+				{Type: model.Expense, Amount: decimal.RequireFromString("23.45")},
+			}, nil).Once()
+
+			// Act
+			err := botService.HandleMessage(ctx, chatId, "/fatura nubank")
+
+			// Assert
+			require.NoError(t, err)
+			assert.Contains(t, m.messenger.messages[0], "Vencimento: 10/08/2025")
+			assert.Contains(t, m.messenger.messages[0], "Total até agora: R$ 123,45")
+		})
+
+		t.Run("should refuse suspended users", func(t *testing.T) {
+			// Arrange
+			botService, m := setup()
+			m.telegram.On("GetChat", ctx, chatId).Return(&model.TelegramChat{ChatId: chatId, UserId: userId}, nil).Once()
+			m.users.On("GetById", ctx, userId).Return(&model.User{Id: userId, DisabledAt: &now}, nil).Once()
+
+			// Act
+			err := botService.HandleMessage(ctx, chatId, "/saldo")
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Sua conta está suspensa."}, m.messenger.messages)
+		})
+	})
+
+	t.Run("SendBudgetAlerts", func(t *testing.T) {
+		t.Run("should announce each threshold only once", func(t *testing.T) {
+			// Arrange
+			botService, m := setup()
+			m.telegram.On("ListAllChats", ctx).Return([]model.TelegramChat{{ChatId: chatId, UserId: userId}}, nil).Once()
+			m.budgets.On("ListByUserAndPeriod", ctx, userId, 7, 2025).Return([]model.Budget{
+				{Id: 1, CategoryId: 1, Amount: decimal.NewFromInt(500), CategoryName: "Mercado"},
+				{Id: 2, CategoryId: 2, Amount: decimal.NewFromInt(300), CategoryName: "Lazer"},
+				{Id: 3, CategoryId: 3, Amount: decimal.NewFromInt(1000), CategoryName: "Casa"},
+			}, nil).Once()
+			m.transactions.On("SumExpensesByCategoryAndPeriod", ctx, userId, int64(1), mock.Anything, mock.Anything).Return(decimal.NewFromInt(520), nil).Once()
+			m.transactions.On("SumExpensesByCategoryAndPeriod", ctx, userId, int64(2), mock.Anything, mock.Anything).Return(decimal.NewFromInt(250), nil).Once()
+			m.transactions.On("SumExpensesByCategoryAndPeriod", ctx, userId, int64(3), mock.Anything, mock.Anything).Return(decimal.NewFromInt(100), nil).Once()
+			m.telegram.On("RecordBudgetAlert", ctx, chatId, int64(1), 100).Return(true, nil).Once()
+			m.telegram.On("RecordBudgetAlert", ctx, chatId, int64(2), 80).Return(false, nil).Once()
+
+			// Act
+			sent, err := botService.SendBudgetAlerts(ctx)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, 1, sent)
+			assert.Equal(t, []string{"🚨 O orçamento de Mercado estourou: R$ 520,00 de R$ 500,00."}, m.messenger.messages)
+			m.telegram.AssertExpectations(t)
+		})
+	})
+
+	t.Run("parseBotAmount", func(t *testing.T) {
+		for input, expected := range map[string]string{
+			"45,90":    "45.9",
+			"45.90":    "45.9",
+			"1.234,56": "1234.56",
+			"R$12":     "12",
+		} {
+			amount, err := parseBotAmount(input)
+			require.NoError(t, err, input)
+			assert.Equal(t, expected, amount.String(), input)
+		}
+		_, err := parseBotAmount("abc")
+		assert.Error(t, err)
+	})
+}
@@ -17,6 +17,8 @@ type BudgetService struct {
 	budgetRepo      repository.BudgetRepository
 	categoryRepo    repository.CategoryRepository
 	transactionRepo repository.TransactionRepository
+	userRepo        repository.UserRepository
+	totalsRepo      repository.MonthlyTotalsRepository
 }
 
 // EnrichedBudget is a struct that holds the budget and its calculated spending.
@@ -26,19 +28,34 @@ type EnrichedBudget struct {
 	Balance     decimal.Decimal
 }
 
-// NewBudgetService creates a new instance of BudgetService.
+// NewBudgetService creates a new instance of BudgetService. When totalsRepo
+// is nil, spending is always added up from the raw transactions.
 func NewBudgetService(
 	budgetRepo repository.BudgetRepository,
 	categoryRepo repository.CategoryRepository,
 	transactionRepo repository.TransactionRepository,
+	userRepo repository.UserRepository,
+	totalsRepo repository.MonthlyTotalsRepository,
 ) *BudgetService {
 	return &BudgetService{
 		budgetRepo:      budgetRepo,
 		categoryRepo:    categoryRepo,
 		transactionRepo: transactionRepo,
+		userRepo:        userRepo,
+		totalsRepo:      totalsRepo,
 	}
 }
 
+// PeriodOf returns the year and month of the user's fiscal month that contains t.
+func (s *BudgetService) PeriodOf(ctx context.Context, userId int64, t time.Time) (year, month int, err error) {
+	fiscalMonth, err := fiscalMonthOf(ctx, s.userRepo, userId)
+	if err != nil {
+		return 0, 0, err
+	}
+	year, month = fiscalMonth.Of(t)
+	return year, month, nil
+}
+
 // CreateBudget handles logic for creating a new budget with validation.
 func (s *BudgetService) CreateBudget(ctx context.Context, budget model.Budget) (*model.Budget, error) {
 	// Business logic: Check if the category exists and belongs to the user.
@@ -69,16 +86,28 @@ func (s *BudgetService) GetEnrichedBudgetById(ctx context.Context, id, userId in
 		return nil, err
 	}
 
-	// Calculate start and end dates for the budget's month
-	startDate := time.Date(budget.Year, time.Month(budget.Month), 1, 0, 0, 0, 0, time.UTC)
-	endDate := startDate.AddDate(0, 1, 0) // First day of the next month
-
-	// Calculate spent amount
-	spent, err := s.transactionRepo.SumExpensesByCategoryAndPeriod(ctx, userId, budget.CategoryId, startDate, endDate)
+	// Calculate start and end dates for the budget's fiscal month
+	fiscalMonth, err := fiscalMonthOf(ctx, s.userRepo, userId)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to calculate spent amount for budget")
 		return nil, err
 	}
+	startDate, endDate := fiscalMonth.Period(budget.Year, budget.Month) // endDate is when the next month starts
+
+	// Calculate spent amount
+	var spent decimal.Decimal
+	if useMonthlyTotals(ctx, s.totalsRepo, fiscalMonth) {
+		expenses, err := s.expensesFromTotals(ctx, userId, budget.Year, budget.Month)
+		if err != nil {
+			return nil, err
+		}
+		spent = expenses[budget.CategoryId]
+	} else {
+		spent, err = s.transactionRepo.SumExpensesByCategoryAndPeriod(ctx, userId, budget.CategoryId, startDate, endDate)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to calculate spent amount for budget")
+			return nil, err
+		}
+	}
 
 	enrichedBudget := &EnrichedBudget{
 		Budget:      *budget,
@@ -96,15 +125,29 @@ func (s *BudgetService) ListEnrichedBudgetsByPeriod(ctx context.Context, userId
 		return nil, err
 	}
 
+	fiscalMonth, err := fiscalMonthOf(ctx, s.userRepo, userId)
+	if err != nil {
+		return nil, err
+	}
+
 	var enrichedBudgets []EnrichedBudget
-	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-	endDate := startDate.AddDate(0, 1, 0)
+	startDate, endDate := fiscalMonth.Period(year, month)
+
+	// With the aggregates, one query covers every budget of the month.
+	var expenses map[int64]decimal.Decimal
+	if useMonthlyTotals(ctx, s.totalsRepo, fiscalMonth) {
+		if expenses, err = s.expensesFromTotals(ctx, userId, year, month); err != nil {
+			return nil, err
+		}
+	}
 
 	for _, budget := range budgets {
-		spent, err := s.transactionRepo.SumExpensesByCategoryAndPeriod(ctx, userId, budget.CategoryId, startDate, endDate)
-		if err != nil {
-			log.Warn().Err(err).Int64("budget_id", budget.Id).Msg("Failed to get spent amount for budget in list")
-			spent = decimal.Zero // Default to zero if calculation fails for one item
+		spent := expenses[budget.CategoryId]
+		if expenses == nil {
+			if spent, err = s.transactionRepo.SumExpensesByCategoryAndPeriod(ctx, userId, budget.CategoryId, startDate, endDate); err != nil {
+				log.Warn().Err(err).Int64("budget_id", budget.Id).Msg("Failed to get spent amount for budget in list")
+				spent = decimal.Zero // Default to zero if calculation fails for one item
+			}
 		}
 
 		enrichedBudgets = append(enrichedBudgets, EnrichedBudget{
@@ -117,6 +160,22 @@ func (s *BudgetService) ListEnrichedBudgetsByPeriod(ctx context.Context, userId
 	return enrichedBudgets, nil
 }
 
+// expensesFromTotals reads what the user spent per category in a calendar
+// month from the monthly aggregates.
+func (s *BudgetService) expensesFromTotals(ctx context.Context, userId int64, year, month int) (map[int64]decimal.Decimal, error) {
+	totals, err := s.totalsRepo.ListByPeriod(ctx, userId, year, month)
+	if err != nil {
+		return nil, err
+	}
+	expenses := map[int64]decimal.Decimal{}
+	for _, total := range totals {
+		if total.Type == model.Expense && total.CategoryId != nil {
+			expenses[*total.CategoryId] = expenses[*total.CategoryId].Add(total.Total)
+		}
+	}
+	return expenses, nil
+}
+
 // UpdateBudget handles updating a budget's amount.
 func (s *BudgetService) UpdateBudget(ctx context.Context, id, userId int64, budget model.Budget) (*model.Budget, error) {
 	budget.Id = id
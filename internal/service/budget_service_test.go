@@ -54,6 +54,23 @@ func (m *MockBudgetRepository) Delete(ctx context.Context, id, userId int64) err
 	return args.Error(0)
 }
 
+type MockMonthlyTotalsRepository struct {
+	mock.Mock
+}
+
+func (m *MockMonthlyTotalsRepository) ListByPeriod(ctx context.Context, userId int64, year, month int) ([]model.MonthlyCategoryTotal, error) {
+	args := m.Called(ctx, userId, year, month)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.MonthlyCategoryTotal), args.Error(1)
+}
+
+func (m *MockMonthlyTotalsRepository) Rebuild(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func TestBudgetService(t *testing.T) {
 	zerolog.SetGlobalLevel(zerolog.Disabled)
 	ctx := context.Background()
@@ -63,7 +80,7 @@ func TestBudgetService(t *testing.T) {
 		mockCategoryRepo := new(MockCategoryRepository)
 
 		// This service doesn't use txRepo in the CreateBudget method, so we can pass nil
-		budgetService := NewBudgetService(mockBudgetRepo, mockCategoryRepo, nil)
+		budgetService := NewBudgetService(mockBudgetRepo, mockCategoryRepo, nil, nil, nil)
 
 		t.Run("should fail if category is not an expense type", func(t *testing.T) {
 			// Arrange
@@ -88,7 +105,7 @@ func TestBudgetService(t *testing.T) {
 			mockBudgetRepo := new(MockBudgetRepository)
 			mockTxRepo := new(MockTransactionRepository)
 			// Pass the mock for transactionRepo to the service
-			budgetService := NewBudgetService(mockBudgetRepo, nil, mockTxRepo)
+			budgetService := NewBudgetService(mockBudgetRepo, nil, mockTxRepo, calendarMonths(), nil)
 
 			ctx := context.Background()
 			userId, month, year := int64(1), 6, 2025
@@ -128,5 +145,92 @@ func TestBudgetService(t *testing.T) {
 			mockBudgetRepo.AssertExpectations(t)
 			mockTxRepo.AssertExpectations(t)
 		})
+
+		t.Run("should count spending over the user's fiscal month", func(t *testing.T) {
+			// Arrange
+			mockBudgetRepo := new(MockBudgetRepository)
+			mockTxRepo := new(MockTransactionRepository)
+			mockUserRepo := new(MockUserRepository)
+			budgetService := NewBudgetService(mockBudgetRepo, nil, mockTxRepo, mockUserRepo, nil)
+
+			ctx := context.Background()
+			userId, month, year := int64(1), 3, 2025
+			mockUserRepo.On("GetById", ctx, userId).Return(&model.User{FiscalMonthStartDay: 25, FiscalMonthLabel: model.FiscalMonthLabelEnd}, nil).Once()
+			mockBudgetRepo.On("ListByUserAndPeriod", ctx, userId, month, year).Return([]model.Budget{
+				{Id: 1, UserId: userId, CategoryId: 10, Amount: decimal.NewFromInt(800)},
+			}, nil).Once()
+			// March, labelled by its end, runs from 25 February to 24 March.
+			startDate := time.Date(2025, time.February, 25, 0, 0, 0, 0, time.UTC)
+			endDate := time.Date(2025, time.March, 25, 0, 0, 0, 0, time.UTC)
+			mockTxRepo.On("SumExpensesByCategoryAndPeriod", ctx, userId, int64(10), startDate, endDate).Return(decimal.NewFromInt(100), nil).Once()
+
+			// Act
+			enrichedBudgets, err := budgetService.ListEnrichedBudgetsByPeriod(ctx, userId, month, year)
+
+			// Assert
+			require.NoError(t, err)
+			require.Len(t, enrichedBudgets, 1)
+			assert.True(t, decimal.NewFromInt(700).Equal(enrichedBudgets[0].Balance))
+			mockTxRepo.AssertExpectations(t)
+		})
+		t.Run("should read spending from the monthly totals when they are enabled", func(t *testing.T) {
+			// Arrange
+			mockBudgetRepo := new(MockBudgetRepository)
+			mockTxRepo := new(MockTransactionRepository)
+			mockTotalsRepo := new(MockMonthlyTotalsRepository)
+			budgetService := NewBudgetService(mockBudgetRepo, nil, mockTxRepo, calendarMonths(), mockTotalsRepo)
+
+			ctx := context.Background()
+			userId, month, year := int64(1), 6, 2025
+			mockBudgetRepo.On("ListByUserAndPeriod", ctx, userId, month, year).Return([]model.Budget{
+				{Id: 1, UserId: userId, CategoryId: 10, Amount: decimal.NewFromInt(800)},
+				{Id: 2, UserId: userId, CategoryId: 20, Amount: decimal.NewFromInt(100)},
+			}, nil).Once()
+			food := int64(10)
+			mockTotalsRepo.On("ListByPeriod", ctx, userId, year, month).Return([]model.MonthlyCategoryTotal{
+				{CategoryId: &food, AccountId: 1, Type: model.Expense, Total: decimal.NewFromInt(300), Count: 2},
+				{CategoryId: &food, AccountId: 2, Type: model.Expense, Total: decimal.NewFromInt(200), Count: 1},
+				{CategoryId: &food, AccountId: 1, Type: model.Income, Total: decimal.NewFromInt(50), Count: 1},
+			}, nil).Once()
+
+			// Act
+			enrichedBudgets, err := budgetService.ListEnrichedBudgetsByPeriod(ctx, userId, month, year)
+
+			// Assert
+			require.NoError(t, err)
+			require.Len(t, enrichedBudgets, 2)
+			assert.True(t, decimal.NewFromInt(500).Equal(enrichedBudgets[0].SpentAmount))
+			assert.True(t, decimal.Zero.Equal(enrichedBudgets[1].SpentAmount))
+			mockTotalsRepo.AssertExpectations(t)
+			mockTxRepo.AssertNotCalled(t, "SumExpensesByCategoryAndPeriod")
+		})
+
+		t.Run("should fall back to the transactions for fiscal months the totals cannot answer", func(t *testing.T) {
+			// Arrange
+			mockBudgetRepo := new(MockBudgetRepository)
+			mockTxRepo := new(MockTransactionRepository)
+			mockUserRepo := new(MockUserRepository)
+			mockTotalsRepo := new(MockMonthlyTotalsRepository)
+			budgetService := NewBudgetService(mockBudgetRepo, nil, mockTxRepo, mockUserRepo, mockTotalsRepo)
+
+			ctx := context.Background()
+			userId, month, year := int64(1), 3, 2025
+			mockUserRepo.On("GetById", ctx, userId).Return(&model.User{FiscalMonthStartDay: 10, FiscalMonthLabel: model.FiscalMonthLabelStart}, nil).Once()
+			mockBudgetRepo.On("ListByUserAndPeriod", ctx, userId, month, year).Return([]model.Budget{
+				{Id: 1, UserId: userId, CategoryId: 10, Amount: decimal.NewFromInt(800)},
+			}, nil).Once()
+			startDate := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+			endDate := time.Date(2025, time.April, 10, 0, 0, 0, 0, time.UTC)
+			mockTxRepo.On("SumExpensesByCategoryAndPeriod", ctx, userId, int64(10), startDate, endDate).Return(decimal.NewFromInt(100), nil).Once()
+
+			// Act
+			enrichedBudgets, err := budgetService.ListEnrichedBudgetsByPeriod(ctx, userId, month, year)
+
+			// Assert
+			require.NoError(t, err)
+			require.Len(t, enrichedBudgets, 1)
+			assert.True(t, decimal.NewFromInt(100).Equal(enrichedBudgets[0].SpentAmount))
+			mockTotalsRepo.AssertNotCalled(t, "ListByPeriod")
+		})
 	})
 }
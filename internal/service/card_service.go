@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+)
+
+var (
+	ErrCardNotFound           = errors.New("card not found or does not belong to the account")
+	ErrCardRequiresCreditCard = errors.New("cards can only be added to credit card accounts")
+	ErrInvalidCard            = errors.New("cards need a holder name, the last 4 digits and a positive limit when one is set")
+	ErrCardLimitExceeded      = errors.New("transaction exceeds the card's limit for the statement")
+)
+
+var lastFourPattern = regexp.MustCompile(`^[0-9]{4}$`)
+
+// CardService manages the additional and virtual cards of credit card
+// accounts and the per-card limits of their purchases.
+type CardService struct {
+	repo            repository.CardRepository
+	accountRepo     repository.AccountRepository
+	transactionRepo repository.TransactionRepository
+	accountService  *AccountService
+}
+
+// NewCardService creates a new instance of CardService.
+func NewCardService(repo repository.CardRepository, accountRepo repository.AccountRepository, transactionRepo repository.TransactionRepository, accountService *AccountService) *CardService {
+	return &CardService{
+		repo:            repo,
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		accountService:  accountService,
+	}
+}
+
+// CreateCard adds a card to a credit card account.
+func (s *CardService) CreateCard(ctx context.Context, card model.Card) (*model.Card, error) {
+	card.HolderName = strings.TrimSpace(card.HolderName)
+	if err := validateCard(card); err != nil {
+		return nil, err
+	}
+	if _, err := s.creditCardAccount(ctx, card.AccountId, card.UserId); err != nil {
+		return nil, err
+	}
+	id, err := s.repo.Create(ctx, card)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetById(ctx, id, card.UserId)
+}
+
+// ListCards returns the cards of a credit card account.
+func (s *CardService) ListCards(ctx context.Context, accountId, userId int64) ([]model.Card, error) {
+	if _, err := s.creditCardAccount(ctx, accountId, userId); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByAccountId(ctx, accountId, userId)
+}
+
+// UpdateCard changes a card's holder, digits, kind and limit.
+func (s *CardService) UpdateCard(ctx context.Context, card model.Card) (*model.Card, error) {
+	card.HolderName = strings.TrimSpace(card.HolderName)
+	if err := validateCard(card); err != nil {
+		return nil, err
+	}
+	if _, err := s.cardOf(ctx, card.Id, card.AccountId, card.UserId); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, card); err != nil {
+		return nil, err
+	}
+	return s.repo.GetById(ctx, card.Id, card.UserId)
+}
+
+// DeleteCard removes a card. Its purchases stay on the account without a card.
+func (s *CardService) DeleteCard(ctx context.Context, id, accountId, userId int64) error {
+	if _, err := s.cardOf(ctx, id, accountId, userId); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id, userId)
+}
+
+// CheckTransaction verifies that the transaction's card belongs to its
+// account and, for expenses, that the purchase fits in the card's limit for
+// the statement it falls in.
+func (s *CardService) CheckTransaction(ctx context.Context, account *model.Account, tx model.Transaction) error {
+	card, err := s.cardOf(ctx, *tx.CardId, account.Id, tx.UserId)
+	if err != nil {
+		return err
+	}
+	if card.SpendLimit == nil || tx.Type != model.Expense {
+		return nil
+	}
+
+	period, err := s.accountService.StatementPeriodOf(ctx, account, tx.Date)
+	if err != nil {
+		return err
+	}
+	transactions, err := s.transactionRepo.ListByAccountAndDateRange(ctx, tx.UserId, account.Id, period.Start, period.End)
+	if err != nil {
+		return fmt.Errorf("failed to get statement transactions for card limit validation: %w", err)
+	}
+	spent := tx.Amount
+	for _, statementTx := range transactions {
+		if statementTx.Type == model.Expense && statementTx.CardId != nil && *statementTx.CardId == card.Id {
+			spent = spent.Add(statementTx.Amount)
+		}
+	}
+	if spent.GreaterThan(*card.SpendLimit) {
+		return fmt.Errorf("%w. Limit: %s, Spent: %s", ErrCardLimitExceeded, card.SpendLimit.String(), spent.Sub(tx.Amount).String())
+	}
+	return nil
+}
+
+// CheckCard verifies that a card belongs to the account, without checking its limit.
+func (s *CardService) CheckCard(ctx context.Context, cardId, accountId, userId int64) error {
+	_, err := s.cardOf(ctx, cardId, accountId, userId)
+	return err
+}
+
+// cardOf loads a card of the given account.
+func (s *CardService) cardOf(ctx context.Context, id, accountId, userId int64) (*model.Card, error) {
+	card, err := s.repo.GetById(ctx, id, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCardNotFound
+		}
+		return nil, err
+	}
+	if card.AccountId != accountId {
+		return nil, ErrCardNotFound
+	}
+	return card, nil
+}
+
+// creditCardAccount loads the account cards are added to.
+func (s *CardService) creditCardAccount(ctx context.Context, accountId, userId int64) (*model.Account, error) {
+	account, err := s.accountRepo.GetById(ctx, accountId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSourceAccountNotFound
+		}
+		return nil, err
+	}
+	if account.Type != model.CreditCard {
+		return nil, ErrCardRequiresCreditCard
+	}
+	return account, nil
+}
+
+func validateCard(card model.Card) error {
+	if card.HolderName == "" || !lastFourPattern.MatchString(card.LastFour) {
+		return ErrInvalidCard
+	}
+	if card.SpendLimit != nil && !card.SpendLimit.IsPositive() {
+		return ErrInvalidCard
+	}
+	return nil
+}
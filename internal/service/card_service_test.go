@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/testhelper"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCardRepository struct {
+	mock.Mock
+}
+
+func (m *MockCardRepository) Create(ctx context.Context, card model.Card) (int64, error) {
+	args := m.Called(ctx, card)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCardRepository) GetById(ctx context.Context, id, userId int64) (*model.Card, error) {
+	args := m.Called(ctx, id, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Card), args.Error(1)
+}
+
+func (m *MockCardRepository) ListByAccountId(ctx context.Context, accountId, userId int64) ([]model.Card, error) {
+	args := m.Called(ctx, accountId, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Card), args.Error(1)
+}
+
+func (m *MockCardRepository) Update(ctx context.Context, card model.Card) error {
+	args := m.Called(ctx, card)
+	return args.Error(0)
+}
+
+func (m *MockCardRepository) Delete(ctx context.Context, id, userId int64) error {
+	args := m.Called(ctx, id, userId)
+	return args.Error(0)
+}
+
+func TestCardService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	userId := int64(1)
+	account := &model.Account{Id: 10, UserId: userId, Type: model.CreditCard, StatementClosingDay: testhelper.Ptr(20), PaymentDueDay: testhelper.Ptr(28)}
+	card := &model.Card{Id: 3, UserId: userId, AccountId: account.Id, HolderName: "Maria", LastFour: "4321", SpendLimit: testhelper.Ptr(decimal.NewFromInt(500))}
+
+	setup := func() (*CardService, *MockCardRepository, *MockAccountRepository, *MockTransactionRepository) {
+		mockRepo := new(MockCardRepository)
+		mockAccountRepo := new(MockAccountRepository)
+		mockTxRepo := new(MockTransactionRepository)
+		accountService := NewAccountService(mockAccountRepo, mockTxRepo, unlimitedQuotas())
+		return NewCardService(mockRepo, mockAccountRepo, mockTxRepo, accountService), mockRepo, mockAccountRepo, mockTxRepo
+	}
+
+	t.Run("CreateCard", func(t *testing.T) {
+		t.Run("should add a card to a credit card account", func(t *testing.T) {
+			// Arrange
+			cardService, mockRepo, mockAccountRepo, _ := setup()
+			mockAccountRepo.On("GetById", ctx, account.Id, userId).Return(account, nil).Once()
+			mockRepo.On("Create", ctx, mock.MatchedBy(func(c model.Card) bool { return c.HolderName == "Maria" })).Return(card.Id, nil).Once()
+			mockRepo.On("GetById", ctx, card.Id, userId).Return(card, nil).Once()
+
+			// Act
+			created, err := cardService.CreateCard(ctx, model.Card{UserId: userId, AccountId: account.Id, HolderName: "  Maria ", LastFour: "4321"})
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, card.Id, created.Id)
+			mockRepo.AssertExpectations(t)
+		})
+
+		t.Run("should reject cards on other account types", func(t *testing.T) {
+			// Arrange
+			cardService, mockRepo, mockAccountRepo, _ := setup()
+			mockAccountRepo.On("GetById", ctx, int64(11), userId).Return(&model.Account{Id: 11, Type: model.Checking}, nil).Once()
+
+			// Act
+			_, err := cardService.CreateCard(ctx, model.Card{UserId: userId, AccountId: 11, HolderName: "Maria", LastFour: "4321"})
+
+			// Assert
+			assert.ErrorIs(t, err, ErrCardRequiresCreditCard)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should reject invalid digits and limits", func(t *testing.T) {
+			cardService, _, _, _ := setup()
+
+			_, digitsErr := cardService.CreateCard(ctx, model.Card{UserId: userId, AccountId: account.Id, HolderName: "Maria", LastFour: "12a4"})
+			_, limitErr := cardService.CreateCard(ctx, model.Card{UserId: userId, AccountId: account.Id, HolderName: "Maria", LastFour: "1234", SpendLimit: testhelper.Ptr(decimal.Zero)})
+
+			assert.ErrorIs(t, digitsErr, ErrInvalidCard)
+			assert.ErrorIs(t, limitErr, ErrInvalidCard)
+		})
+	})
+
+	t.Run("CheckTransaction", func(t *testing.T) {
+		purchase := model.Transaction{
+			UserId:    userId,
+			AccountId: account.Id,
+			CardId:    &card.Id,
+			Type:      model.Expense,
+			Amount:    decimal.NewFromInt(150),
+			Date:      time.Date(2025, time.June, 25, 12, 0, 0, 0, time.UTC),
+		}
+		// A purchase on 25 June falls in the statement closing on 20 July.
+		statementStart := time.Date(2025, time.June, 20, 0, 0, 0, 0, time.UTC)
+		statementEnd := time.Date(2025, time.July, 20, 0, 0, 0, 0, time.UTC)
+		otherCard := int64(4)
+
+		t.Run("should reject a purchase over the card's limit for the statement", func(t *testing.T) {
+			// Arrange
+			cardService, mockRepo, mockAccountRepo, mockTxRepo := setup()
+			mockRepo.On("GetById", ctx, card.Id, userId).Return(card, nil).Once()
+			mockAccountRepo.On("ListBillingCycles", ctx, account.Id, userId).Return(nil, nil).Once()
+			mockTxRepo.On("ListByAccountAndDateRange", ctx, userId, account.Id, statementStart, statementEnd).Return([]model.Transaction{
+				{Type: model.Expense, Amount: decimal.NewFromInt(400), CardId: &card.Id},
+				{Type: model.Expense, Amount: decimal.NewFromInt(900), CardId: &otherCard},
+				{Type: model.Expense, Amount: decimal.NewFromInt(900)},
+				{Type: model.Income, Amount: decimal.NewFromInt(100), CardId: &card.Id},
+			}, nil).Once()
+
+			// Act
+			err := cardService.CheckTransaction(ctx, account, purchase)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrCardLimitExceeded)
+			mockTxRepo.AssertExpectations(t)
+		})
+
+		t.Run("should accept a purchase within the card's limit", func(t *testing.T) {
+			// Arrange
+			cardService, mockRepo, mockAccountRepo, mockTxRepo := setup()
+			mockRepo.On("GetById", ctx, card.Id, userId).Return(card, nil).Once()
+			mockAccountRepo.On("ListBillingCycles", ctx, account.Id, userId).Return(nil, nil).Once()
+			mockTxRepo.On("ListByAccountAndDateRange", ctx, userId, account.Id, statementStart, statementEnd).Return([]model.Transaction{
+				{Type: model.Expense, Amount: decimal.NewFromInt(350), CardId: &card.Id},
+			}, nil).Once()
+
+			// Act
+			err := cardService.CheckTransaction(ctx, account, purchase)
+
+			// Assert
+			assert.NoError(t, err)
+		})
+
+		t.Run("should reject a card of another account", func(t *testing.T) {
+			// Arrange
+			cardService, mockRepo, _, _ := setup()
+			mockRepo.On("GetById", ctx, card.Id, userId).Return(&model.Card{Id: card.Id, AccountId: 99}, nil).Once()
+
+			// Act
+			err := cardService.CheckTransaction(ctx, account, purchase)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrCardNotFound)
+		})
+
+		t.Run("should be enforced when creating a transaction", func(t *testing.T) {
+			// Arrange
+			cardService, mockRepo, mockAccountRepo, mockTxRepo := setup()
+			txService := NewTransactionService(mockTxRepo, mockAccountRepo, unlimitedQuotas(), cardService, TransactionOptions{})
+			mockAccountRepo.On("GetById", ctx, account.Id, userId).Return(account, nil).Once()
+			mockRepo.On("GetById", ctx, card.Id, userId).Return(card, nil).Once()
+			mockAccountRepo.On("ListBillingCycles", ctx, account.Id, userId).Return(nil, nil).Once()
+			mockTxRepo.On("ListByAccountAndDateRange", ctx, userId, account.Id, statementStart, statementEnd).Return([]model.Transaction{
+				{Type: model.Expense, Amount: decimal.NewFromInt(400), CardId: &card.Id},
+			}, nil).Once()
+
+			// Act
+			_, err := txService.CreateTransaction(ctx, purchase)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrCardLimitExceeded)
+			mockTxRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	})
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/chart"
+)
+
+var ErrInvalidChartMonths = errors.New("cash flow charts cover between 1 and 24 months")
+
+const (
+	maxCashflowChartMonths = 24
+	// maxCategoryChartSlices is how many categories get their own slice; the
+	// rest are grouped together.
+	maxCategoryChartSlices = 8
+)
+
+// ChartService renders the reports as SVG charts from the same aggregates as
+// their JSON versions.
+type ChartService struct {
+	reportService   *ReportService
+	netWorthService *NetWorthService
+	budgetService   *BudgetService
+}
+
+// NewChartService creates a new instance of ChartService.
+func NewChartService(reportService *ReportService, netWorthService *NetWorthService, budgetService *BudgetService) *ChartService {
+	return &ChartService{
+		reportService:   reportService,
+		netWorthService: netWorthService,
+		budgetService:   budgetService,
+	}
+}
+
+// PeriodOf returns the year and month of the user's fiscal month that contains t.
+func (s *ChartService) PeriodOf(ctx context.Context, userId int64, t time.Time) (year, month int, err error) {
+	return s.reportService.PeriodOf(ctx, userId, t)
+}
+
+// Cashflow charts the income and expenses of the months up to the given one.
+func (s *ChartService) Cashflow(ctx context.Context, userId int64, year, month, months int, opts chart.Options) ([]byte, error) {
+	if months < 1 || months > maxCashflowChartMonths {
+		return nil, ErrInvalidChartMonths
+	}
+	if !validReportPeriod(year, month) {
+		return nil, ErrInvalidReportPeriod
+	}
+
+	labels := make([]string, months)
+	income := chart.Series{Name: opts.Locale.Words["income"], Values: make([]float64, months)}
+	expenses := chart.Series{Name: opts.Locale.Words["expenses"], Values: make([]float64, months)}
+	first := time.Date(year, time.Month(month)-time.Month(months-1), 1, 0, 0, 0, 0, time.UTC)
+	for i := range months {
+		date := first.AddDate(0, i, 0)
+		report, err := s.reportService.GetMonthlyReport(ctx, userId, date.Year(), int(date.Month()))
+		if err != nil {
+			return nil, err
+		}
+		labels[i] = opts.Locale.MonthLabel(date.Year(), date.Month())
+		income.Values[i] = report.TotalIncome.InexactFloat64()
+		expenses.Values[i] = report.TotalExpense.InexactFloat64()
+	}
+	return chart.Bars(opts.Locale.Words["cashflow"], labels, []chart.Series{income, expenses}, opts), nil
+}
+
+// Categories charts the share of each category in the expenses of a month.
+func (s *ChartService) Categories(ctx context.Context, userId int64, year, month int, opts chart.Options) ([]byte, error) {
+	report, err := s.reportService.GetMonthlyReport(ctx, userId, year, month)
+	if err != nil {
+		return nil, err
+	}
+
+	// The report lists the biggest expenses first.
+	var slices []chart.Slice
+	for _, total := range report.Categories {
+		if !total.Expense.IsPositive() {
+			continue
+		}
+		if len(slices) == maxCategoryChartSlices {
+			slices = append(slices, chart.Slice{Label: opts.Locale.Words["other"]})
+		}
+		if len(slices) > maxCategoryChartSlices {
+			slices[maxCategoryChartSlices].Value += total.Expense.InexactFloat64()
+			continue
+		}
+		slices = append(slices, chart.Slice{Label: total.CategoryName, Value: total.Expense.InexactFloat64()})
+	}
+	title := fmt.Sprintf("%s, %s", opts.Locale.Words["categories"], opts.Locale.MonthLabel(year, time.Month(month)))
+	return chart.Donut(title, slices, opts), nil
+}
+
+// NetWorth charts what each account is worth; debts are drawn below zero.
+func (s *ChartService) NetWorth(ctx context.Context, userId int64, opts chart.Options) ([]byte, error) {
+	netWorth, err := s.netWorthService.GetNetWorth(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]string, len(netWorth.Items))
+	values := chart.Series{Name: opts.Locale.Words["value"], Values: make([]float64, len(netWorth.Items))}
+	for i, item := range netWorth.Items {
+		labels[i] = item.Name
+		values.Values[i] = item.Value.InexactFloat64()
+	}
+	title := fmt.Sprintf("%s: %s", opts.Locale.Words["net_worth"], opts.Locale.FormatNumber(netWorth.Total.InexactFloat64(), 2))
+	return chart.Bars(title, labels, []chart.Series{values}, opts), nil
+}
+
+// Budget compares the budget of each category with what was spent in a month.
+func (s *ChartService) Budget(ctx context.Context, userId int64, year, month int, opts chart.Options) ([]byte, error) {
+	if !validReportPeriod(year, month) {
+		return nil, ErrInvalidReportPeriod
+	}
+	budgets, err := s.budgetService.ListEnrichedBudgetsByPeriod(ctx, userId, month, year)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]string, len(budgets))
+	planned := chart.Series{Name: opts.Locale.Words["budget"], Values: make([]float64, len(budgets))}
+	spent := chart.Series{Name: opts.Locale.Words["spent"], Values: make([]float64, len(budgets))}
+	for i, budget := range budgets {
+		labels[i] = budget.CategoryName
+		planned.Values[i] = budget.Amount.InexactFloat64()
+		spent.Values[i] = budget.SpentAmount.InexactFloat64()
+	}
+	title := fmt.Sprintf("%s, %s", opts.Locale.Words["budgets"], opts.Locale.MonthLabel(year, time.Month(month)))
+	return chart.Bars(title, labels, []chart.Series{planned, spent}, opts), nil
+}
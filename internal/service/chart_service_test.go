@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/chart"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/testhelper"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestChartService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	userId := int64(1)
+	opts := chart.DefaultOptions()
+
+	t.Run("should chart the cash flow of the months leading to the given one", func(t *testing.T) {
+		// Arrange
+		mockTxRepo := new(MockTransactionRepository)
+		chartService := NewChartService(NewReportService(mockTxRepo, calendarMonths(), nil), nil, nil)
+		for _, month := range []time.Month{time.November, time.December, time.January} {
+			year := 2025
+			if month == time.January {
+				year = 2026
+			}
+			start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+			mockTxRepo.On("List", ctx, userId, mock.MatchedBy(func(f repository.ListTransactionFilters) bool {
+				return f.StartDate.Equal(start)
+			})).Return([]model.Transaction{
+				{Type: model.Income, Amount: decimal.NewFromInt(1000)},
+			}, nil).Once()
+		}
+
+		// Act
+		svg, err := chartService.Cashflow(ctx, userId, 2026, 1, 3, opts)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Contains(t, string(svg), "Nov 2025")
+		assert.Contains(t, string(svg), "Jan 2026")
+		mockTxRepo.AssertExpectations(t)
+	})
+
+	t.Run("should group the smallest categories together", func(t *testing.T) {
+		// Arrange
+		mockTxRepo := new(MockTransactionRepository)
+		chartService := NewChartService(NewReportService(mockTxRepo, calendarMonths(), nil), nil, nil)
+		var transactions []model.Transaction
+		for i := range 10 {
+			transactions = append(transactions, model.Transaction{
+				Type:         model.Expense,
+				Amount:       decimal.NewFromInt(int64(100 - i)),
+				CategoryId:   testhelper.Ptr(int64(i + 1)),
+				CategoryName: testhelper.Ptr(fmt.Sprintf("Category %d", i+1)),
+			})
+		}
+		mockTxRepo.On("List", ctx, userId, mock.Anything).Return(transactions, nil).Once()
+
+		// Act
+		svg, err := chartService.Categories(ctx, userId, 2025, 5, opts)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Contains(t, string(svg), "Category 8")
+		assert.NotContains(t, string(svg), "Category 9")
+		assert.Equal(t, 1, strings.Count(string(svg), "<title>Other: 183.00</title>"))
+	})
+
+	t.Run("should reject too many months", func(t *testing.T) {
+		chartService := NewChartService(NewReportService(new(MockTransactionRepository), calendarMonths(), nil), nil, nil)
+
+		_, err := chartService.Cashflow(ctx, userId, 2025, 5, 25, opts)
+
+		assert.ErrorIs(t, err, ErrInvalidChartMonths)
+	})
+}
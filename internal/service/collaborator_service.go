@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/mailer"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/rs/zerolog"
+)
+
+var (
+	ErrCollaboratorGrantNotFound   = errors.New("collaborator access not found")
+	ErrCollaboratorInviteNotFound  = errors.New("invitation not found or no longer available")
+	ErrCollaboratorAlreadyInvited  = errors.New("this e-mail already has an active invitation")
+	ErrCannotInviteSelf            = errors.New("you cannot invite yourself as a collaborator")
+	ErrInvalidCollaboratorAccounts = errors.New("at least one account must be shared and all accounts must belong to you")
+	ErrInvalidCollaboratorPeriod   = errors.New("start date must not be after end date")
+)
+
+// InviteCollaboratorInput describes the access offered to a collaborator.
+// Nil dates leave that side of the period open.
+type InviteCollaboratorInput struct {
+	Email      string
+	AccountIds []int64
+	StartDate  *time.Time
+	EndDate    *time.Time
+}
+
+// CollaboratorService manages read-only access that users grant to other
+// registered users, such as accountants and financial advisors.
+type CollaboratorService struct {
+	repo        repository.CollaboratorRepository
+	userRepo    repository.UserRepository
+	accountRepo repository.AccountRepository
+	mailer      mailer.Mailer
+}
+
+// NewCollaboratorService creates a new instance of CollaboratorService.
+func NewCollaboratorService(
+	repo repository.CollaboratorRepository,
+	userRepo repository.UserRepository,
+	accountRepo repository.AccountRepository,
+	mailer mailer.Mailer,
+) *CollaboratorService {
+	return &CollaboratorService{
+		repo:        repo,
+		userRepo:    userRepo,
+		accountRepo: accountRepo,
+		mailer:      mailer,
+	}
+}
+
+// Invite offers read-only access to the selected accounts and period. The grant
+// only becomes usable once the invitee accepts it from their own login.
+func (s *CollaboratorService) Invite(ctx context.Context, ownerId int64, input InviteCollaboratorInput) (*model.CollaboratorGrant, error) {
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+
+	owner, err := s.userRepo.GetById(ctx, ownerId)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(owner.Email, email) {
+		return nil, ErrCannotInviteSelf
+	}
+
+	if input.StartDate != nil && input.EndDate != nil && input.StartDate.After(*input.EndDate) {
+		return nil, ErrInvalidCollaboratorPeriod
+	}
+	if len(input.AccountIds) == 0 {
+		return nil, ErrInvalidCollaboratorAccounts
+	}
+	for _, accountId := range input.AccountIds {
+		if _, err := s.accountRepo.GetById(ctx, accountId, ownerId); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, ErrInvalidCollaboratorAccounts
+			}
+			return nil, err
+		}
+	}
+
+	grant := model.CollaboratorGrant{
+		OwnerId:      ownerId,
+		InviteeEmail: email,
+		AccountIds:   input.AccountIds,
+		StartDate:    input.StartDate,
+		EndDate:      input.EndDate,
+	}
+	grant.Id, err = s.repo.Create(ctx, grant)
+	if err != nil {
+		if strings.Contains(err.Error(), "unique constraint") {
+			return nil, ErrCollaboratorAlreadyInvited
+		}
+		return nil, err
+	}
+
+	// The invitation is listed for the invitee anyway, so a failed e-mail does
+	// not undo it.
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: fmt.Sprintf("%s invited you to view their finances", owner.Name),
+		Body: fmt.Sprintf(
+			"Hi,\n\n%s invited you to get read-only access to some of their accounts.\n\nLog in with this e-mail and accept invitation #%d to start.\n",
+			owner.Name, grant.Id,
+		),
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Int64("grantId", grant.Id).Msg("failed to send collaborator invitation")
+	}
+
+	return &grant, nil
+}
+
+// ListGrants returns every grant the owner issued, including revoked ones.
+func (s *CollaboratorService) ListGrants(ctx context.Context, ownerId int64) ([]model.CollaboratorGrant, error) {
+	return s.repo.ListByOwnerId(ctx, ownerId)
+}
+
+// ListInvitations returns the grants the user accepted and the pending
+// invitations sent to their e-mail.
+func (s *CollaboratorService) ListInvitations(ctx context.Context, userId int64) ([]model.CollaboratorGrant, error) {
+	user, err := s.userRepo.GetById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListByInvitee(ctx, userId, user.Email)
+}
+
+// Accept binds a pending invitation to the user it was sent to.
+func (s *CollaboratorService) Accept(ctx context.Context, userId, grantId int64) (*model.CollaboratorGrant, error) {
+	user, err := s.userRepo.GetById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	grant, err := s.repo.GetById(ctx, grantId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCollaboratorInviteNotFound
+		}
+		return nil, err
+	}
+	// Invitations addressed to someone else are reported as missing.
+	if grant.RevokedAt != nil || grant.CollaboratorId != nil || !strings.EqualFold(grant.InviteeEmail, user.Email) {
+		return nil, ErrCollaboratorInviteNotFound
+	}
+
+	if err := s.repo.Accept(ctx, grantId, userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCollaboratorInviteNotFound
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	grant.CollaboratorId = &userId
+	grant.AcceptedAt = &now
+	return grant, nil
+}
+
+// Revoke ends a grant immediately, whether it was accepted or not.
+func (s *CollaboratorService) Revoke(ctx context.Context, ownerId, grantId int64) error {
+	if err := s.repo.Revoke(ctx, grantId, ownerId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCollaboratorGrantNotFound
+		}
+		return err
+	}
+	return nil
+}
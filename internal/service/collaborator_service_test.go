@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/mailer"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/testhelper"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCollaboratorRepository is a mock implementation of the CollaboratorRepository interface.
+type MockCollaboratorRepository struct {
+	mock.Mock
+}
+
+func (m *MockCollaboratorRepository) Create(ctx context.Context, grant model.CollaboratorGrant) (int64, error) {
+	args := m.Called(ctx, grant)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCollaboratorRepository) GetById(ctx context.Context, id int64) (*model.CollaboratorGrant, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CollaboratorGrant), args.Error(1)
+}
+
+func (m *MockCollaboratorRepository) GetActive(ctx context.Context, ownerId, collaboratorId int64) (*model.CollaboratorGrant, error) {
+	args := m.Called(ctx, ownerId, collaboratorId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CollaboratorGrant), args.Error(1)
+}
+
+func (m *MockCollaboratorRepository) ListByOwnerId(ctx context.Context, ownerId int64) ([]model.CollaboratorGrant, error) {
+	args := m.Called(ctx, ownerId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.CollaboratorGrant), args.Error(1)
+}
+
+func (m *MockCollaboratorRepository) ListByInvitee(ctx context.Context, collaboratorId int64, email string) ([]model.CollaboratorGrant, error) {
+	args := m.Called(ctx, collaboratorId, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.CollaboratorGrant), args.Error(1)
+}
+
+func (m *MockCollaboratorRepository) Accept(ctx context.Context, id, collaboratorId int64) error {
+	args := m.Called(ctx, id, collaboratorId)
+	return args.Error(0)
+}
+
+func (m *MockCollaboratorRepository) Revoke(ctx context.Context, id, ownerId int64) error {
+	args := m.Called(ctx, id, ownerId)
+	return args.Error(0)
+}
+
+func TestCollaboratorService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	owner := &model.User{Id: 1, Name: "Owner", Email: "owner@test.com"}
+	advisor := &model.User{Id: 2, Name: "Advisor", Email: "advisor@test.com"}
+
+	setup := func() (*CollaboratorService, *MockCollaboratorRepository, *MockUserRepository, *MockAccountRepository, *mailer.InMemoryMailer) {
+		mockRepo := new(MockCollaboratorRepository)
+		mockUserRepo := new(MockUserRepository)
+		mockAccountRepo := new(MockAccountRepository)
+		mail := mailer.NewInMemoryMailer()
+		return NewCollaboratorService(mockRepo, mockUserRepo, mockAccountRepo, mail), mockRepo, mockUserRepo, mockAccountRepo, mail
+	}
+
+	t.Run("Invite", func(t *testing.T) {
+		t.Run("should store the grant and e-mail the invitee", func(t *testing.T) {
+			// Arrange
+			collaboratorService, mockRepo, mockUserRepo, mockAccountRepo, mail := setup()
+			mockUserRepo.On("GetById", ctx, owner.Id).Return(owner, nil).Once()
+			mockAccountRepo.On("GetById", ctx, int64(10), owner.Id).Return(&model.Account{Id: 10}, nil).Once()
+			var stored model.CollaboratorGrant
+			mockRepo.On("Create", ctx, mock.AnythingOfType("model.CollaboratorGrant")).
+				Run(func(args mock.Arguments) { stored = args.Get(1).(model.CollaboratorGrant) }).
+				Return(int64(5), nil).Once()
+
+			// Act
+			grant, err := collaboratorService.Invite(ctx, owner.Id, InviteCollaboratorInput{
+				Email:      " Advisor@Test.com ",
+				AccountIds: []int64{10},
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, int64(5), grant.Id)
+			assert.Equal(t, "advisor@test.com", stored.InviteeEmail)
+			assert.Equal(t, []int64{10}, []int64(stored.AccountIds))
+			messages := mail.Messages()
+			if assert.Len(t, messages, 1) {
+				assert.Equal(t, "advisor@test.com", messages[0].To)
+				assert.Contains(t, messages[0].Body, "#5")
+			}
+			mockRepo.AssertExpectations(t)
+		})
+
+		t.Run("should reject accounts that do not belong to the owner", func(t *testing.T) {
+			// Arrange
+			collaboratorService, mockRepo, mockUserRepo, mockAccountRepo, _ := setup()
+			mockUserRepo.On("GetById", ctx, owner.Id).Return(owner, nil).Once()
+			mockAccountRepo.On("GetById", ctx, int64(99), owner.Id).Return(nil, sql.ErrNoRows).Once()
+
+			// Act
+			_, err := collaboratorService.Invite(ctx, owner.Id, InviteCollaboratorInput{Email: advisor.Email, AccountIds: []int64{99}})
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidCollaboratorAccounts)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should reject an empty account list, an inverted period and the owner's own e-mail", func(t *testing.T) {
+			// Arrange
+			collaboratorService, _, mockUserRepo, _, _ := setup()
+			mockUserRepo.On("GetById", ctx, owner.Id).Return(owner, nil)
+
+			// Act
+			_, errAccounts := collaboratorService.Invite(ctx, owner.Id, InviteCollaboratorInput{Email: advisor.Email})
+			_, errPeriod := collaboratorService.Invite(ctx, owner.Id, InviteCollaboratorInput{
+				Email:      advisor.Email,
+				AccountIds: []int64{10},
+				StartDate:  testhelper.Ptr(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)),
+				EndDate:    testhelper.Ptr(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+			})
+			_, errSelf := collaboratorService.Invite(ctx, owner.Id, InviteCollaboratorInput{Email: "OWNER@test.com", AccountIds: []int64{10}})
+
+			// Assert
+			assert.ErrorIs(t, errAccounts, ErrInvalidCollaboratorAccounts)
+			assert.ErrorIs(t, errPeriod, ErrInvalidCollaboratorPeriod)
+			assert.ErrorIs(t, errSelf, ErrCannotInviteSelf)
+		})
+
+		t.Run("should report a duplicate live invitation", func(t *testing.T) {
+			// Arrange
+			collaboratorService, mockRepo, mockUserRepo, mockAccountRepo, _ := setup()
+			mockUserRepo.On("GetById", ctx, owner.Id).Return(owner, nil).Once()
+			mockAccountRepo.On("GetById", ctx, int64(10), owner.Id).Return(&model.Account{Id: 10}, nil).Once()
+			mockRepo.On("Create", ctx, mock.Anything).
+				Return(int64(0), errors.New(`pq: duplicate key value violates unique constraint "idx_collaborator_grants_owner_email"`)).Once()
+
+			// Act
+			_, err := collaboratorService.Invite(ctx, owner.Id, InviteCollaboratorInput{Email: advisor.Email, AccountIds: []int64{10}})
+
+			// Assert
+			assert.ErrorIs(t, err, ErrCollaboratorAlreadyInvited)
+		})
+	})
+
+	t.Run("Accept", func(t *testing.T) {
+		t.Run("should bind the invitation to the invitee", func(t *testing.T) {
+			// Arrange
+			collaboratorService, mockRepo, mockUserRepo, _, _ := setup()
+			mockUserRepo.On("GetById", ctx, advisor.Id).Return(advisor, nil).Once()
+			mockRepo.On("GetById", ctx, int64(5)).Return(&model.CollaboratorGrant{Id: 5, OwnerId: owner.Id, InviteeEmail: advisor.Email}, nil).Once()
+			mockRepo.On("Accept", ctx, int64(5), advisor.Id).Return(nil).Once()
+
+			// Act
+			grant, err := collaboratorService.Accept(ctx, advisor.Id, 5)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, advisor.Id, *grant.CollaboratorId)
+			assert.NotNil(t, grant.AcceptedAt)
+			mockRepo.AssertExpectations(t)
+		})
+
+		t.Run("should hide invitations addressed to someone else or already revoked", func(t *testing.T) {
+			// Arrange
+			collaboratorService, mockRepo, mockUserRepo, _, _ := setup()
+			mockUserRepo.On("GetById", ctx, advisor.Id).Return(advisor, nil)
+			mockRepo.On("GetById", ctx, int64(5)).Return(&model.CollaboratorGrant{Id: 5, OwnerId: owner.Id, InviteeEmail: "other@test.com"}, nil).Once()
+			mockRepo.On("GetById", ctx, int64(6)).Return(&model.CollaboratorGrant{Id: 6, OwnerId: owner.Id, InviteeEmail: advisor.Email, RevokedAt: testhelper.Ptr(time.Now())}, nil).Once()
+
+			// Act
+			_, errOther := collaboratorService.Accept(ctx, advisor.Id, 5)
+			_, errRevoked := collaboratorService.Accept(ctx, advisor.Id, 6)
+
+			// Assert
+			assert.ErrorIs(t, errOther, ErrCollaboratorInviteNotFound)
+			assert.ErrorIs(t, errRevoked, ErrCollaboratorInviteNotFound)
+			mockRepo.AssertNotCalled(t, "Accept", mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		t.Run("should return not found for grants of other owners", func(t *testing.T) {
+			// Arrange
+			collaboratorService, mockRepo, _, _, _ := setup()
+			mockRepo.On("Revoke", ctx, int64(5), owner.Id).Return(sql.ErrNoRows).Once()
+
+			// Act
+			err := collaboratorService.Revoke(ctx, owner.Id, 5)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrCollaboratorGrantNotFound)
+		})
+	})
+}
+
+func TestAccessScope(t *testing.T) {
+	scope := model.AccessScope{
+		AccountIds: []int64{10},
+		StartDate:  testhelper.Ptr(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:    testhelper.Ptr(time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)),
+	}
+
+	t.Run("should include the whole end date", func(t *testing.T) {
+		assert.True(t, scope.AllowsDate(time.Date(2025, 1, 31, 23, 59, 0, 0, time.UTC)))
+		assert.False(t, scope.AllowsDate(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)))
+		assert.False(t, scope.AllowsDate(time.Date(2024, 12, 31, 23, 59, 0, 0, time.UTC)))
+	})
+
+	t.Run("should allow transfers into a shared account", func(t *testing.T) {
+		date := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+		assert.True(t, scope.AllowsTransaction(model.Transaction{AccountId: 20, DestinationAccountId: testhelper.Ptr(int64(10)), Date: date}))
+		assert.False(t, scope.AllowsTransaction(model.Transaction{AccountId: 20, Date: date}))
+	})
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+)
+
+var ErrInvalidFiscalMonth = errors.New("fiscal months start on a day between 1 and 31 and are labelled by their start or end")
+
+// FiscalMonth is how a user splits the year into months. Each month starts on
+// StartDay, or on the last day of months too short for it, and runs until the
+// next one starts.
+type FiscalMonth struct {
+	StartDay int
+	Label    model.FiscalMonthLabel
+}
+
+// calendarMonth is the fiscal month of users who never changed it.
+var calendarMonth = FiscalMonth{StartDay: 1, Label: model.FiscalMonthLabelStart}
+
+func (f FiscalMonth) valid() bool {
+	return f.StartDay >= 1 && f.StartDay <= 31 &&
+		(f.Label == model.FiscalMonthLabelStart || f.Label == model.FiscalMonthLabelEnd)
+}
+
+// Period returns when the fiscal month named year/month starts and when the
+// next one starts.
+func (f FiscalMonth) Period(year, month int) (start, end time.Time) {
+	startMonth := time.Month(month)
+	// A month starting on the 1st also ends in the calendar month it starts in.
+	if f.Label == model.FiscalMonthLabelEnd && f.StartDay > 1 {
+		startMonth--
+	}
+	start = f.startIn(year, startMonth)
+	return start, f.startIn(start.Year(), start.Month()+1)
+}
+
+// Of returns the year and month of the fiscal month that contains t.
+func (f FiscalMonth) Of(t time.Time) (year, month int) {
+	start := f.startIn(t.Year(), t.Month())
+	if t.Before(start) {
+		start = f.startIn(t.Year(), t.Month()-1)
+	}
+	if f.Label == model.FiscalMonthLabelEnd && f.StartDay > 1 {
+		start = start.AddDate(0, 0, 1-start.Day()).AddDate(0, 1, 0)
+	}
+	return start.Year(), int(start.Month())
+}
+
+// startIn is the day the fiscal month starting in the given calendar month
+// starts on, clamped to the month's last day.
+func (f FiscalMonth) startIn(year int, month time.Month) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	lastDayOfMonth := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(f.StartDay, lastDayOfMonth)-1)
+}
+
+// fiscalMonthOf loads the user's fiscal month.
+func fiscalMonthOf(ctx context.Context, userRepo repository.UserRepository, userId int64) (FiscalMonth, error) {
+	user, err := userRepo.GetById(ctx, userId)
+	if err != nil {
+		return FiscalMonth{}, err
+	}
+	f := FiscalMonth{StartDay: user.FiscalMonthStartDay, Label: user.FiscalMonthLabel}
+	if !f.valid() {
+		return calendarMonth, nil
+	}
+	return f, nil
+}
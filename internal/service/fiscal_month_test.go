@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFiscalMonth(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	t.Run("should compute the period of a fiscal month", func(t *testing.T) {
+		testCases := []struct {
+			name          string
+			fiscalMonth   FiscalMonth
+			year, month   int
+			expectedStart time.Time
+			expectedEnd   time.Time
+		}{
+			{"calendar month", calendarMonth, 2025, 2, date(2025, 2, 1), date(2025, 3, 1)},
+			{"calendar month labelled by its end", FiscalMonth{1, model.FiscalMonthLabelEnd}, 2025, 2, date(2025, 2, 1), date(2025, 3, 1)},
+			{"payday month labelled by its start", FiscalMonth{25, model.FiscalMonthLabelStart}, 2025, 1, date(2025, 1, 25), date(2025, 2, 25)},
+			{"payday month labelled by its end", FiscalMonth{25, model.FiscalMonthLabelEnd}, 2025, 1, date(2024, 12, 25), date(2025, 1, 25)},
+			{"start day clamped in a short month", FiscalMonth{31, model.FiscalMonthLabelStart}, 2025, 2, date(2025, 2, 28), date(2025, 3, 31)},
+			{"start day clamped in a leap year", FiscalMonth{30, model.FiscalMonthLabelEnd}, 2024, 3, date(2024, 2, 29), date(2024, 3, 30)},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				start, end := tc.fiscalMonth.Period(tc.year, tc.month)
+
+				assert.Equal(t, tc.expectedStart, start)
+				assert.Equal(t, tc.expectedEnd, end)
+			})
+		}
+	})
+
+	t.Run("should find the fiscal month of a date", func(t *testing.T) {
+		testCases := []struct {
+			name          string
+			fiscalMonth   FiscalMonth
+			date          time.Time
+			expectedYear  int
+			expectedMonth int
+		}{
+			{"calendar month", calendarMonth, date(2025, 7, 10), 2025, 7},
+			{"before payday, labelled by its start", FiscalMonth{25, model.FiscalMonthLabelStart}, date(2025, 1, 10), 2024, 12},
+			{"on payday, labelled by its start", FiscalMonth{25, model.FiscalMonthLabelStart}, date(2025, 1, 25), 2025, 1},
+			{"before payday, labelled by its end", FiscalMonth{25, model.FiscalMonthLabelEnd}, date(2025, 1, 10), 2025, 1},
+			{"after payday, labelled by its end", FiscalMonth{25, model.FiscalMonthLabelEnd}, date(2025, 12, 26), 2026, 1},
+			{"last day of a short month", FiscalMonth{31, model.FiscalMonthLabelStart}, date(2025, 2, 28), 2025, 2},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				year, month := tc.fiscalMonth.Of(tc.date)
+
+				assert.Equal(t, tc.expectedYear, year)
+				assert.Equal(t, tc.expectedMonth, month)
+			})
+		}
+	})
+
+	t.Run("should fall back to calendar months for users without a valid setting", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		mockUserRepo.On("GetById", context.Background(), int64(1)).Return(&model.User{}, nil).Once()
+
+		fiscalMonth, err := fiscalMonthOf(context.Background(), mockUserRepo, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, calendarMonth, fiscalMonth)
+	})
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrInvalidForeignPurchase            = errors.New("foreign purchases need a three-letter currency other than BRL, a positive original amount and exchange rate, and a spread that is not negative")
+	ErrForeignPurchaseRequiresCreditCard = errors.New("foreign purchases can only be credit card expenses")
+	ErrForeignPurchaseAmount             = errors.New("the amount of a foreign purchase is converted from its original amount; change the original amount or exchange rate instead")
+)
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ForeignPurchase is a purchase abroad in a statement, with the rates it was
+// converted at and the IOF charged on it.
+type ForeignPurchase struct {
+	TransactionId  int64
+	Description    string
+	Date           time.Time
+	OriginalAmount decimal.Decimal
+	ExchangeRate   decimal.Decimal
+	SpreadRate     decimal.Decimal
+	Amount         decimal.Decimal
+	IOF            decimal.Decimal
+}
+
+// ForeignCurrencyTotal adds up the purchases of a statement made in one
+// currency. Total is in BRL and does not include the IOF.
+type ForeignCurrencyTotal struct {
+	Currency      string
+	OriginalTotal decimal.Decimal
+	Total         decimal.Decimal
+	IOF           decimal.Decimal
+	Purchases     []ForeignPurchase
+}
+
+// isForeignPurchase reports whether any of the foreign purchase fields is set.
+func isForeignPurchase(tx model.Transaction) bool {
+	return tx.OriginalCurrency != nil || tx.OriginalAmount != nil || tx.ExchangeRate != nil || tx.SpreadRate != nil
+}
+
+// convertForeignPurchase validates a purchase made abroad and sets its amount
+// to the original amount at the exchange rate plus the bank's spread. It
+// returns the IOF charged on that amount at iofRate percent.
+func convertForeignPurchase(tx *model.Transaction, iofRate decimal.Decimal) (decimal.Decimal, error) {
+	if tx.OriginalCurrency == nil || tx.OriginalAmount == nil || tx.ExchangeRate == nil {
+		return decimal.Zero, ErrInvalidForeignPurchase
+	}
+	currency := strings.ToUpper(strings.TrimSpace(*tx.OriginalCurrency))
+	if !currencyPattern.MatchString(currency) || currency == "BRL" {
+		return decimal.Zero, ErrInvalidForeignPurchase
+	}
+	if !tx.OriginalAmount.IsPositive() || !tx.ExchangeRate.IsPositive() {
+		return decimal.Zero, ErrInvalidForeignPurchase
+	}
+	spread := decimal.Zero
+	if tx.SpreadRate != nil {
+		if tx.SpreadRate.IsNegative() {
+			return decimal.Zero, ErrInvalidForeignPurchase
+		}
+		spread = *tx.SpreadRate
+	}
+
+	tx.OriginalCurrency = &currency
+	tx.SpreadRate = &spread
+	rate := tx.ExchangeRate.Mul(percentOf(spread).Add(decimal.NewFromInt(1)))
+	tx.Amount = tx.OriginalAmount.Mul(rate).Round(2)
+	return tx.Amount.Mul(percentOf(iofRate)).Round(2), nil
+}
+
+// iofFee is the transaction of the IOF charged on a purchase. It is posted
+// with the purchase, so it lands in the same statement and category.
+func iofFee(purchase model.Transaction, iof decimal.Decimal) model.Transaction {
+	return model.Transaction{
+		UserId:      purchase.UserId,
+		Description: "IOF: " + purchase.Description,
+		Amount:      iof,
+		Date:        purchase.Date,
+		Type:        model.Expense,
+		AccountId:   purchase.AccountId,
+		CategoryId:  purchase.CategoryId,
+		CardId:      purchase.CardId,
+	}
+}
+
+// groupForeignPurchases groups the foreign purchases of a statement by
+// currency, matching each purchase with the IOF posted for it.
+func groupForeignPurchases(transactions []model.Transaction) []ForeignCurrencyTotal {
+	fees := map[int64]decimal.Decimal{}
+	for _, tx := range transactions {
+		if tx.FeeOfTransactionId != nil {
+			fees[*tx.FeeOfTransactionId] = fees[*tx.FeeOfTransactionId].Add(tx.Amount)
+		}
+	}
+
+	var totals []ForeignCurrencyTotal
+	indexes := map[string]int{}
+	for _, tx := range transactions {
+		if tx.OriginalCurrency == nil || tx.OriginalAmount == nil || tx.ExchangeRate == nil {
+			continue
+		}
+		i, ok := indexes[*tx.OriginalCurrency]
+		if !ok {
+			i = len(totals)
+			indexes[*tx.OriginalCurrency] = i
+			totals = append(totals, ForeignCurrencyTotal{Currency: *tx.OriginalCurrency})
+		}
+		purchase := ForeignPurchase{
+			TransactionId:  tx.Id,
+			Description:    tx.Description,
+			Date:           tx.Date,
+			OriginalAmount: *tx.OriginalAmount,
+			ExchangeRate:   *tx.ExchangeRate,
+			Amount:         tx.Amount,
+			IOF:            fees[tx.Id],
+		}
+		if tx.SpreadRate != nil {
+			purchase.SpreadRate = *tx.SpreadRate
+		}
+		totals[i].OriginalTotal = totals[i].OriginalTotal.Add(purchase.OriginalAmount)
+		totals[i].Total = totals[i].Total.Add(purchase.Amount)
+		totals[i].IOF = totals[i].IOF.Add(purchase.IOF)
+		totals[i].Purchases = append(totals[i].Purchases, purchase)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Currency < totals[j].Currency })
+	return totals
+}
+
+func percentOf(rate decimal.Decimal) decimal.Decimal {
+	return rate.Div(decimal.NewFromInt(100))
+}
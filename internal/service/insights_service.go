@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+// healthPeriodMonths is the length of the period the health metrics are
+// computed over, and of the previous period they are compared with.
+const healthPeriodMonths = 6
+
+// Names of the health metrics.
+const (
+	MetricEmergencyFund     = "emergency_fund"
+	MetricSavingsRate       = "savings_rate"
+	MetricDebtToIncome      = "debt_to_income"
+	MetricCreditUtilization = "credit_utilization"
+	MetricBudgetAdherence   = "budget_adherence"
+)
+
+// Trends of a health metric compared with the previous period.
+const (
+	TrendImproving = "improving"
+	TrendStable    = "stable"
+	TrendWorsening = "worsening"
+	TrendUnknown   = "unknown"
+)
+
+// HealthMetric is one ratio of the financial health report. Value and Score
+// are nil when there is not enough data, such as no income in the period.
+// Scores go from 0 to 100.
+type HealthMetric struct {
+	Name           string
+	Value          *decimal.Decimal
+	Score          *int
+	PreviousValue  *decimal.Decimal
+	PreviousScore  *int
+	Trend          string
+	Recommendation string
+}
+
+// HealthReport scores the user's finances over the last full months.
+type HealthReport struct {
+	Score   int
+	Period  StatementPeriod
+	Metrics []HealthMetric
+}
+
+// healthValues are the metric values of one period, before scoring.
+type healthValues map[string]*decimal.Decimal
+
+// InsightsService computes personal finance ratios from the existing data.
+type InsightsService struct {
+	accountService  *AccountService
+	transactionRepo repository.TransactionRepository
+	budgetRepo      repository.BudgetRepository
+	now             func() time.Time
+}
+
+// NewInsightsService creates a new instance of InsightsService.
+func NewInsightsService(accountService *AccountService, transactionRepo repository.TransactionRepository, budgetRepo repository.BudgetRepository) *InsightsService {
+	return &InsightsService{
+		accountService:  accountService,
+		transactionRepo: transactionRepo,
+		budgetRepo:      budgetRepo,
+		now:             time.Now,
+	}
+}
+
+// GetHealth computes the emergency fund coverage, savings rate, debt-to-income,
+// credit card utilization and budget adherence of the last six full months,
+// and compares them with the six months before. Balances of the previous
+// period are rebuilt by undoing the transactions made since then.
+func (s *InsightsService) GetHealth(ctx context.Context, userId int64) (*HealthReport, error) {
+	now := s.now()
+	end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	start := end.AddDate(0, -healthPeriodMonths, 0)
+	previousStart := start.AddDate(0, -healthPeriodMonths, 0)
+
+	accounts, err := s.accountService.ListAccountsByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := s.transactionRepo.List(ctx, userId, repository.ListTransactionFilters{StartDate: &previousStart})
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.periodValues(ctx, userId, accounts, transactions, start, end, now)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := s.periodValues(ctx, userId, accounts, transactions, previousStart, start, start)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &HealthReport{
+		Period:  StatementPeriod{Start: start, End: end.Add(-time.Second)},
+		Metrics: []HealthMetric{},
+	}
+	total, scored := 0, 0
+	for _, name := range []string{MetricEmergencyFund, MetricSavingsRate, MetricDebtToIncome, MetricCreditUtilization, MetricBudgetAdherence} {
+		metric := HealthMetric{
+			Name:          name,
+			Value:         current[name],
+			Score:         healthScore(name, current[name]),
+			PreviousValue: previous[name],
+			PreviousScore: healthScore(name, previous[name]),
+		}
+		metric.Trend = healthTrend(metric.Score, metric.PreviousScore)
+		metric.Recommendation = healthRecommendation(name, metric.Value, metric.Score)
+		if metric.Score != nil {
+			total += *metric.Score
+			scored++
+		}
+		report.Metrics = append(report.Metrics, metric)
+	}
+	if scored > 0 {
+		report.Score = (total + scored/2) / scored
+	}
+	return report, nil
+}
+
+// periodValues computes the metrics of the period [start, end). Balances are
+// taken at balancesAt.
+func (s *InsightsService) periodValues(ctx context.Context, userId int64, accounts []model.Account, transactions []model.Transaction, start, end, balancesAt time.Time) (healthValues, error) {
+	months := decimal.NewFromInt(healthPeriodMonths)
+	hundred := decimal.NewFromInt(100)
+	values := healthValues{}
+
+	income, expense := decimal.Zero, decimal.Zero
+	spent := map[string]decimal.Decimal{}
+	for _, tx := range transactions {
+		if tx.Date.Before(start) || !tx.Date.Before(end) {
+			continue
+		}
+		switch tx.Type {
+		case model.Income:
+			income = income.Add(tx.Amount)
+		case model.Expense:
+			expense = expense.Add(tx.Amount)
+			if tx.CategoryId != nil {
+				key := fmt.Sprintf("%d|%d|%d", *tx.CategoryId, tx.Date.Year(), tx.Date.Month())
+				spent[key] = spent[key].Add(tx.Amount)
+			}
+		}
+	}
+	monthlyIncome, monthlyExpense := income.Div(months), expense.Div(months)
+
+	liquid, debt, limit := decimal.Zero, decimal.Zero, decimal.Zero
+	for _, account := range accounts {
+		balance := balanceAt(account, transactions, balancesAt)
+		switch account.Type {
+		case model.Checking, model.Savings, model.Other:
+			liquid = liquid.Add(balance)
+		case model.CreditCard:
+			if balance.IsNegative() {
+				debt = debt.Add(balance.Neg())
+			}
+			if account.CreditLimit != nil && account.CreditLimit.IsPositive() {
+				limit = limit.Add(*account.CreditLimit)
+			}
+		}
+	}
+
+	if monthlyExpense.IsPositive() {
+		values[MetricEmergencyFund] = ratio(liquid, monthlyExpense, decimal.NewFromInt(1))
+	}
+	if income.IsPositive() {
+		values[MetricSavingsRate] = ratio(income.Sub(expense), income, hundred)
+		values[MetricDebtToIncome] = ratio(debt, monthlyIncome, hundred)
+	}
+	if limit.IsPositive() {
+		values[MetricCreditUtilization] = ratio(debt, limit, hundred)
+	}
+
+	kept, budgets := 0, 0
+	for month := start; month.Before(end); month = month.AddDate(0, 1, 0) {
+		monthBudgets, err := s.budgetRepo.ListByUserAndPeriod(ctx, userId, int(month.Month()), month.Year())
+		if err != nil {
+			return nil, err
+		}
+		for _, budget := range monthBudgets {
+			budgets++
+			if spent[fmt.Sprintf("%d|%d|%d", budget.CategoryId, budget.Year, budget.Month)].LessThanOrEqual(budget.Amount) {
+				kept++
+			}
+		}
+	}
+	if budgets > 0 {
+		values[MetricBudgetAdherence] = ratio(decimal.NewFromInt(int64(kept)), decimal.NewFromInt(int64(budgets)), hundred)
+	}
+	return values, nil
+}
+
+// balanceAt rebuilds the balance an account had at a date from its current
+// balance and the transactions made since then.
+func balanceAt(account model.Account, transactions []model.Transaction, date time.Time) decimal.Decimal {
+	balance := account.Balance
+	for _, tx := range transactions {
+		if tx.Date.Before(date) {
+			continue
+		}
+		if tx.AccountId == account.Id {
+			if tx.Type == model.Income {
+				balance = balance.Sub(tx.Amount)
+			} else {
+				balance = balance.Add(tx.Amount)
+			}
+		}
+		if tx.Type == model.Transfer && tx.DestinationAccountId != nil && *tx.DestinationAccountId == account.Id {
+			balance = balance.Sub(tx.Amount)
+		}
+	}
+	return balance
+}
+
+// healthScore turns a metric value into a 0 to 100 score:
+//   - emergency fund: 100 from six months of expenses;
+//   - savings rate: 100 from 20% of the income;
+//   - debt-to-income: 100 up to 30% of the monthly income, 0 from 100%;
+//   - credit utilization: 100 up to 10% of the limits, 0 from 90%;
+//   - budget adherence: the share of budgets kept.
+func healthScore(name string, value *decimal.Decimal) *int {
+	if value == nil {
+		return nil
+	}
+	v := value.InexactFloat64()
+	var score float64
+	switch name {
+	case MetricEmergencyFund:
+		score = v / 6 * 100
+	case MetricSavingsRate:
+		score = v / 20 * 100
+	case MetricDebtToIncome:
+		score = (100 - v) / 70 * 100
+	case MetricCreditUtilization:
+		score = (90 - v) / 80 * 100
+	case MetricBudgetAdherence:
+		score = v
+	}
+	rounded := int(decimal.NewFromFloat(min(max(score, 0), 100)).Round(0).IntPart())
+	return &rounded
+}
+
+// healthTrend compares the scores of two periods. Changes under five points are stable.
+func healthTrend(score, previous *int) string {
+	if score == nil || previous == nil {
+		return TrendUnknown
+	}
+	switch diff := *score - *previous; {
+	case diff >= 5:
+		return TrendImproving
+	case diff <= -5:
+		return TrendWorsening
+	default:
+		return TrendStable
+	}
+}
+
+// healthRecommendation returns a machine-readable code for what to do about a metric.
+func healthRecommendation(name string, value *decimal.Decimal, score *int) string {
+	if score == nil {
+		switch name {
+		case MetricEmergencyFund:
+			return "track_expenses"
+		case MetricCreditUtilization:
+			return "set_credit_limits"
+		case MetricBudgetAdherence:
+			return "create_budgets"
+		default:
+			return "track_income"
+		}
+	}
+	if *score == 100 {
+		return "keep_it_up"
+	}
+	switch name {
+	case MetricEmergencyFund:
+		if *score < 50 {
+			return "build_emergency_fund"
+		}
+		return "grow_emergency_fund"
+	case MetricSavingsRate:
+		if value.IsNegative() {
+			return "spending_exceeds_income"
+		}
+		return "increase_savings_rate"
+	case MetricDebtToIncome:
+		if *score < 50 {
+			return "reduce_debt"
+		}
+		return "watch_debt"
+	case MetricCreditUtilization:
+		if *score < 50 {
+			return "lower_card_utilization"
+		}
+		return "watch_card_utilization"
+	default:
+		if *score < 70 {
+			return "review_budgets"
+		}
+		return "watch_budgets"
+	}
+}
+
+func ratio(numerator, denominator, scale decimal.Decimal) *decimal.Decimal {
+	value := numerator.Mul(scale).Div(denominator).Round(2)
+	return &value
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/testhelper"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInsightsService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	userId := int64(1)
+	now := time.Date(2025, 7, 10, 9, 0, 0, 0, time.UTC)
+	salaryId, rentId := int64(1), int64(2)
+
+	setup := func() (*InsightsService, *MockAccountRepository, *MockTransactionRepository, *MockBudgetRepository) {
+		mockAccountRepo := new(MockAccountRepository)
+		mockTxRepo := new(MockTransactionRepository)
+		mockBudgetRepo := new(MockBudgetRepository)
+		accountService := NewAccountService(mockAccountRepo, mockTxRepo, unlimitedQuotas())
+		insightsService := NewInsightsService(accountService, mockTxRepo, mockBudgetRepo)
+		insightsService.now = func() time.Time { return now }
+		return insightsService, mockAccountRepo, mockTxRepo, mockBudgetRepo
+	}
+
+	metric := func(report *HealthReport, name string) HealthMetric {
+		for _, m := range report.Metrics {
+			if m.Name == name {
+				return m
+			}
+		}
+		t.Fatalf("metric %s not found", name)
+		return HealthMetric{}
+	}
+
+	t.Run("GetHealth", func(t *testing.T) {
+		t.Run("should score each metric and compare it with the previous period", func(t *testing.T) {
+			// Arrange
+			insightsService, mockAccountRepo, mockTxRepo, mockBudgetRepo := setup()
+			mockAccountRepo.On("ListByUserId", ctx, userId).Return([]model.Account{
+				{Id: 1, Type: model.Checking},
+				{Id: 2, Type: model.CreditCard, CreditLimit: testhelper.Ptr(decimal.NewFromInt(5000))},
+			}, nil).Once()
+			mockAccountRepo.On("GetCurrentBalance", ctx, int64(1), userId).Return(decimal.NewFromInt(12000), nil).Once()
+			mockAccountRepo.On("GetCurrentBalance", ctx, int64(2), userId).Return(decimal.NewFromInt(-1500), nil).Once()
+
+			var transactions []model.Transaction
+			for i := 0; i < 12; i++ {
+				date := time.Date(2024, time.July+time.Month(i), 5, 0, 0, 0, 0, time.UTC)
+				rent := decimal.NewFromInt(4500)
+				if date.Year() == 2025 {
+					rent = decimal.NewFromInt(3000)
+				}
+				transactions = append(transactions,
+					model.Transaction{Type: model.Income, Amount: decimal.NewFromInt(5000), Date: date, AccountId: 1, CategoryId: &salaryId},
+					model.Transaction{Type: model.Expense, Amount: rent, Date: date, AccountId: 1, CategoryId: &rentId},
+				)
+			}
+			transactions = append(transactions, model.Transaction{Type: model.Expense, Amount: decimal.NewFromInt(500), Date: now.AddDate(0, 0, -5), AccountId: 1})
+			mockTxRepo.On("List", ctx, userId, mock.Anything).Return(transactions, nil).Once()
+
+			for month := 1; month <= 6; month++ {
+				mockBudgetRepo.On("ListByUserAndPeriod", ctx, userId, month, 2025).Return([]model.Budget{{CategoryId: rentId, Amount: decimal.NewFromInt(3000), Month: month, Year: 2025}}, nil).Once()
+				mockBudgetRepo.On("ListByUserAndPeriod", ctx, userId, month+6, 2024).Return([]model.Budget{}, nil).Once()
+			}
+
+			// Act
+			report, err := insightsService.GetHealth(ctx, userId)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), report.Period.Start)
+
+			emergency := metric(report, MetricEmergencyFund)
+			assert.True(t, emergency.Value.Equal(decimal.NewFromInt(4)), emergency.Value.String())
+			assert.Equal(t, 67, *emergency.Score)
+			// Six months ago the checking account only had 500.
+			assert.True(t, emergency.PreviousValue.Equal(decimal.RequireFromString("0.11")), emergency.PreviousValue.String())
+			assert.Equal(t, TrendImproving, emergency.Trend)
+			assert.Equal(t, "grow_emergency_fund", emergency.Recommendation)
+
+			savings := metric(report, MetricSavingsRate)
+			assert.Equal(t, 100, *savings.Score)
+			assert.Equal(t, 50, *savings.PreviousScore)
+			assert.Equal(t, "keep_it_up", savings.Recommendation)
+
+			utilization := metric(report, MetricCreditUtilization)
+			assert.Equal(t, 75, *utilization.Score)
+			assert.Equal(t, TrendStable, utilization.Trend)
+			assert.Equal(t, "watch_card_utilization", utilization.Recommendation)
+
+			budgets := metric(report, MetricBudgetAdherence)
+			assert.Equal(t, 100, *budgets.Score)
+			assert.Nil(t, budgets.PreviousScore)
+			assert.Equal(t, TrendUnknown, budgets.Trend)
+
+			assert.Equal(t, 88, report.Score)
+		})
+
+		t.Run("should leave metrics without data unscored", func(t *testing.T) {
+			// Arrange
+			insightsService, mockAccountRepo, mockTxRepo, mockBudgetRepo := setup()
+			mockAccountRepo.On("ListByUserId", ctx, userId).Return([]model.Account{}, nil).Once()
+			mockTxRepo.On("List", ctx, userId, mock.Anything).Return([]model.Transaction{}, nil).Once()
+			mockBudgetRepo.On("ListByUserAndPeriod", ctx, userId, mock.Anything, mock.Anything).Return([]model.Budget{}, nil)
+
+			// Act
+			report, err := insightsService.GetHealth(ctx, userId)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 0, report.Score)
+			for _, m := range report.Metrics {
+				assert.Nil(t, m.Score, m.Name)
+			}
+			assert.Equal(t, "create_budgets", metric(report, MetricBudgetAdherence).Recommendation)
+		})
+	})
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/mailer"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/ratelimit"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/rs/zerolog"
+)
+
+var (
+	ErrMagicLinkRateLimited = errors.New("too many login link requests for this e-mail, try again later")
+	ErrMagicLinkInvalid     = errors.New("login link is invalid, expired or was already used")
+)
+
+// MagicLinkOptions holds the deployment-specific settings of the magic-link flow.
+type MagicLinkOptions struct {
+	// BaseURL is the verification URL; the token is appended as the "token" query parameter.
+	BaseURL string
+	// TTL is how long a link stays valid after being issued.
+	TTL time.Duration
+}
+
+// MagicLinkService implements passwordless login through single-use links sent by e-mail.
+type MagicLinkService struct {
+	userRepo      repository.UserRepository
+	magicLinkRepo repository.MagicLinkRepository
+	authService   *AuthService
+	mailer        mailer.Mailer
+	limiter       *ratelimit.Limiter
+	options       MagicLinkOptions
+}
+
+// NewMagicLinkService creates a new instance of MagicLinkService.
+func NewMagicLinkService(
+	userRepo repository.UserRepository,
+	magicLinkRepo repository.MagicLinkRepository,
+	authService *AuthService,
+	mailer mailer.Mailer,
+	limiter *ratelimit.Limiter,
+	options MagicLinkOptions,
+) *MagicLinkService {
+	return &MagicLinkService{
+		userRepo:      userRepo,
+		magicLinkRepo: magicLinkRepo,
+		authService:   authService,
+		mailer:        mailer,
+		limiter:       limiter,
+		options:       options,
+	}
+}
+
+// RequestLink issues a new login link for the e-mail and sends it.
+// To avoid leaking which e-mails are registered, an unknown e-mail is not an error:
+// the call succeeds without sending anything.
+func (s *MagicLinkService) RequestLink(ctx context.Context, email string) error {
+	logger := zerolog.Ctx(ctx)
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	// The limit applies to every request, known e-mail or not.
+	if !s.limiter.Allow(email) {
+		return ErrMagicLinkRateLimited
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Info().Msg("magic link requested for unknown e-mail")
+			return nil
+		}
+		return err
+	}
+	if user.DisabledAt != nil {
+		logger.Info().Int64("userId", user.Id).Msg("magic link requested for disabled user")
+		return nil
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate login token: %w", err)
+	}
+
+	_, err = s.magicLinkRepo.Create(ctx, model.MagicLinkToken{
+		UserId:    user.Id,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().UTC().Add(s.options.TTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	link := s.options.BaseURL + "?token=" + url.QueryEscape(token)
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Your login link",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to log in. It expires in %s and can only be used once.\n\n%s\n\nIf you did not request it, you can ignore this e-mail.\n",
+			user.Name, s.options.TTL, link,
+		),
+	})
+}
+
+// VerifyLink redeems a login token and returns a JWT for its owner.
+func (s *MagicLinkService) VerifyLink(ctx context.Context, token string) (string, error) {
+	if token == "" {
+		return "", ErrMagicLinkInvalid
+	}
+
+	userId, err := s.magicLinkRepo.Consume(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrMagicLinkInvalid
+		}
+		return "", err
+	}
+
+	return s.authService.IssueToken(userId)
+}
+
+// generateToken returns 32 random bytes encoded as URL-safe base64.
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the hex-encoded SHA-256 of a token, which is what gets stored.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/mailer"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/ratelimit"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockMagicLinkRepository is a mock implementation of the MagicLinkRepository interface.
+type MockMagicLinkRepository struct {
+	mock.Mock
+}
+
+// Create simulates storing a new login token.
+func (m *MockMagicLinkRepository) Create(ctx context.Context, token model.MagicLinkToken) (int64, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// Consume simulates redeeming a login token.
+func (m *MockMagicLinkRepository) Consume(ctx context.Context, tokenHash string) (int64, error) {
+	args := m.Called(ctx, tokenHash)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// DeleteExpired simulates removing stale login tokens.
+func (m *MockMagicLinkRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestMagicLinkService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	testJwtKey := "test_secret_key"
+	options := MagicLinkOptions{BaseURL: "https://app.test/login", TTL: 15 * time.Minute}
+
+	setup := func(maxRequests int) (*MagicLinkService, *MockUserRepository, *MockMagicLinkRepository, *mailer.InMemoryMailer) {
+		mockUserRepo := new(MockUserRepository)
+		mockMagicLinkRepo := new(MockMagicLinkRepository)
+		inMemoryMailer := mailer.NewInMemoryMailer()
+		magicLinkService := NewMagicLinkService(
+			mockUserRepo,
+			mockMagicLinkRepo,
+			NewAuthService(mockUserRepo, testJwtKey),
+			inMemoryMailer,
+			ratelimit.New(maxRequests, time.Hour),
+			options,
+		)
+		return magicLinkService, mockUserRepo, mockMagicLinkRepo, inMemoryMailer
+	}
+
+	t.Run("RequestLink", func(t *testing.T) {
+		t.Run("success: should store only the token hash and e-mail the plaintext link", func(t *testing.T) {
+			// Arrange
+			magicLinkService, mockUserRepo, mockMagicLinkRepo, inMemoryMailer := setup(3)
+			user := &model.User{Id: 7, Name: "Ana", Email: "ana@test.com"}
+			mockUserRepo.On("GetByEmail", ctx, "ana@test.com").Return(user, nil).Once()
+
+			var stored model.MagicLinkToken
+			mockMagicLinkRepo.On("Create", ctx, mock.AnythingOfType("model.MagicLinkToken")).
+				Run(func(args mock.Arguments) { stored = args.Get(1).(model.MagicLinkToken) }).
+				Return(int64(1), nil).Once()
+
+			// Act
+			err := magicLinkService.RequestLink(ctx, "  Ana@Test.com ")
+
+			// Assert
+			require.NoError(t, err)
+			messages := inMemoryMailer.Messages()
+			require.Len(t, messages, 1)
+			assert.Equal(t, "ana@test.com", messages[0].To)
+
+			token := extractToken(t, messages[0].Body, options.BaseURL)
+			assert.Equal(t, hashToken(token), stored.TokenHash)
+			assert.NotContains(t, stored.TokenHash, token)
+			assert.Equal(t, int64(7), stored.UserId)
+			assert.WithinDuration(t, time.Now().Add(options.TTL), stored.ExpiresAt, time.Minute)
+			mockUserRepo.AssertExpectations(t)
+			mockMagicLinkRepo.AssertExpectations(t)
+		})
+
+		t.Run("success: should send the link to a user registered with a mixed-case e-mail", func(t *testing.T) {
+			// Arrange
+			magicLinkService, mockUserRepo, mockMagicLinkRepo, inMemoryMailer := setup(3)
+			user := &model.User{Id: 8, Name: "John", Email: "John@Example.com"}
+			mockUserRepo.On("GetByEmail", ctx, "john@example.com").Return(user, nil).Once()
+			mockMagicLinkRepo.On("Create", ctx, mock.AnythingOfType("model.MagicLinkToken")).Return(int64(1), nil).Once()
+
+			// Act
+			err := magicLinkService.RequestLink(ctx, "John@Example.com")
+
+			// Assert
+			require.NoError(t, err)
+			messages := inMemoryMailer.Messages()
+			require.Len(t, messages, 1)
+			assert.Equal(t, "John@Example.com", messages[0].To)
+			mockUserRepo.AssertExpectations(t)
+		})
+
+		t.Run("success: should not reveal unknown e-mails", func(t *testing.T) {
+			magicLinkService, mockUserRepo, mockMagicLinkRepo, inMemoryMailer := setup(3)
+			mockUserRepo.On("GetByEmail", ctx, "ghost@test.com").Return(nil, sql.ErrNoRows).Once()
+
+			err := magicLinkService.RequestLink(ctx, "ghost@test.com")
+
+			assert.NoError(t, err)
+			assert.Empty(t, inMemoryMailer.Messages())
+			mockMagicLinkRepo.AssertNotCalled(t, "Create")
+		})
+
+		t.Run("failure: should rate limit requests per e-mail", func(t *testing.T) {
+			magicLinkService, mockUserRepo, _, _ := setup(1)
+			mockUserRepo.On("GetByEmail", ctx, "ghost@test.com").Return(nil, sql.ErrNoRows).Once()
+
+			assert.NoError(t, magicLinkService.RequestLink(ctx, "ghost@test.com"))
+			err := magicLinkService.RequestLink(ctx, "GHOST@test.com")
+
+			assert.ErrorIs(t, err, ErrMagicLinkRateLimited)
+			mockUserRepo.AssertExpectations(t)
+		})
+	})
+
+	t.Run("VerifyLink", func(t *testing.T) {
+		t.Run("success: should exchange a valid token for a JWT", func(t *testing.T) {
+			magicLinkService, _, mockMagicLinkRepo, _ := setup(3)
+			mockMagicLinkRepo.On("Consume", ctx, hashToken("valid-token")).Return(int64(7), nil).Once()
+
+			tokenString, err := magicLinkService.VerifyLink(ctx, "valid-token")
+
+			require.NoError(t, err)
+			claims := &jwt.RegisteredClaims{}
+			parsedToken, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) { return []byte(testJwtKey), nil })
+			require.NoError(t, err)
+			assert.True(t, parsedToken.Valid)
+			assert.Equal(t, "7", claims.Subject)
+		})
+
+		t.Run("failure: should reject expired, used or unknown tokens", func(t *testing.T) {
+			magicLinkService, _, mockMagicLinkRepo, _ := setup(3)
+			mockMagicLinkRepo.On("Consume", ctx, hashToken("used-token")).Return(int64(0), sql.ErrNoRows).Once()
+
+			_, err := magicLinkService.VerifyLink(ctx, "used-token")
+
+			assert.ErrorIs(t, err, ErrMagicLinkInvalid)
+		})
+
+		t.Run("failure: should reject an empty token without hitting the database", func(t *testing.T) {
+			magicLinkService, _, mockMagicLinkRepo, _ := setup(3)
+
+			_, err := magicLinkService.VerifyLink(ctx, "")
+
+			assert.ErrorIs(t, err, ErrMagicLinkInvalid)
+			mockMagicLinkRepo.AssertNotCalled(t, "Consume")
+		})
+	})
+}
+
+// extractToken finds the login link in an e-mail body and returns its token.
+func extractToken(t *testing.T, body, baseURL string) string {
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, baseURL) {
+			link, err := url.Parse(line)
+			require.NoError(t, err)
+			return link.Query().Get("token")
+		}
+	}
+	t.Fatalf("no login link found in e-mail body: %q", body)
+	return ""
+}
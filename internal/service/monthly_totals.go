@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+)
+
+// useMonthlyTotals tells whether a fiscal month can be read from the monthly
+// aggregates: they must be enabled, the month must be a calendar month and no
+// collaborator scope may narrow what the user sees, since the aggregates know
+// nothing about either.
+func useMonthlyTotals(ctx context.Context, totalsRepo repository.MonthlyTotalsRepository, fiscalMonth FiscalMonth) bool {
+	return totalsRepo != nil && fiscalMonth.StartDay == 1 && repository.AccessScopeFromContext(ctx) == nil
+}
+
+// periodTotals adds up the user's transactions of a fiscal month by category,
+// account and type, from the monthly aggregates when possible and from the
+// raw transactions otherwise.
+func periodTotals(ctx context.Context, transactionRepo repository.TransactionRepository, totalsRepo repository.MonthlyTotalsRepository, userId int64, fiscalMonth FiscalMonth, year, month int) ([]model.MonthlyCategoryTotal, error) {
+	if useMonthlyTotals(ctx, totalsRepo, fiscalMonth) {
+		return totalsRepo.ListByPeriod(ctx, userId, year, month)
+	}
+
+	start, next := fiscalMonth.Period(year, month)
+	end := next.Add(-time.Second)
+	transactions, err := transactionRepo.List(ctx, userId, repository.ListTransactionFilters{StartDate: &start, EndDate: &end})
+	if err != nil {
+		return nil, err
+	}
+	totals := make([]model.MonthlyCategoryTotal, 0, len(transactions))
+	for _, tx := range transactions {
+		totals = append(totals, model.MonthlyCategoryTotal{
+			UserId:       userId,
+			CategoryId:   tx.CategoryId,
+			CategoryName: tx.CategoryName,
+			AccountId:    tx.AccountId,
+			AccountType:  tx.AccountType,
+			Type:         tx.Type,
+			Year:         year,
+			Month:        month,
+			Total:        tx.Amount,
+			Count:        1,
+		})
+	}
+	return totals, nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// NetWorthItem is one line of the net worth: an account and what it is worth.
+// Points is only set for points accounts, whose value is an estimate.
+type NetWorthItem struct {
+	AccountId int64
+	Name      string
+	Type      model.AccountType
+	Value     decimal.Decimal
+	Points    *int64
+}
+
+// NetWorth adds up everything the user owns minus what they owe.
+type NetWorth struct {
+	Items []NetWorthItem
+	Total decimal.Decimal
+}
+
+// NetWorthService consolidates account balances and estimated values.
+type NetWorthService struct {
+	accountService *AccountService
+	pointsService  *PointsService
+	assetService   *AssetService
+}
+
+// NewNetWorthService creates a new instance of NetWorthService.
+func NewNetWorthService(accountService *AccountService, pointsService *PointsService, assetService *AssetService) *NetWorthService {
+	return &NetWorthService{
+		accountService: accountService,
+		pointsService:  pointsService,
+		assetService:   assetService,
+	}
+}
+
+// GetNetWorth values every account of the user. Credit card balances are
+// negative, so their debt is subtracted; points accounts use their estimated
+// value and asset accounts their latest valuation.
+func (s *NetWorthService) GetNetWorth(ctx context.Context, userId int64) (*NetWorth, error) {
+	accounts, err := s.accountService.ListAccountsByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	netWorth := &NetWorth{Items: []NetWorthItem{}}
+	for _, account := range accounts {
+		item := NetWorthItem{
+			AccountId: account.Id,
+			Name:      account.Name,
+			Type:      account.Type,
+			Value:     account.Balance,
+		}
+		if account.Type == model.Points {
+			points, err := s.pointsService.GetBalance(ctx, userId, account.Id)
+			if err != nil {
+				return nil, err
+			}
+			item.Points = &points
+			item.Value = EstimatePointsValue(account, points)
+		}
+		if account.Type == model.Asset {
+			item.Value, err = s.assetService.CurrentValue(ctx, account)
+			if err != nil {
+				return nil, err
+			}
+		}
+		netWorth.Items = append(netWorth.Items, item)
+		netWorth.Total = netWorth.Total.Add(item.Value)
+	}
+	return netWorth, nil
+}
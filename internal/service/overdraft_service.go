@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+var ErrOverdraftLimitExceeded = errors.New("transaction exceeds the account's balance plus its overdraft limit")
+
+// overdraftDaysPerMonth is the commercial month the monthly overdraft rate is
+// prorated over, as banks do.
+const overdraftDaysPerMonth = 30
+
+// OverdraftService charges the monthly interest of checking accounts that
+// spent days in their overdraft (cheque especial).
+type OverdraftService struct {
+	repo         repository.OverdraftRepository
+	quotas       *QuotaService
+	transactions *TransactionService
+	now          func() time.Time
+}
+
+// NewOverdraftService creates a new instance of OverdraftService.
+func NewOverdraftService(repo repository.OverdraftRepository, quotas *QuotaService, transactions *TransactionService) *OverdraftService {
+	return &OverdraftService{
+		repo:         repo,
+		quotas:       quotas,
+		transactions: transactions,
+		now:          time.Now,
+	}
+}
+
+// ChargeLastMonth charges the overdraft interest of the month that just ended
+// to every checking account with an interest rate. It is run periodically; a
+// month is charged once.
+func (s *OverdraftService) ChargeLastMonth(ctx context.Context) (int, error) {
+	logger := zerolog.Ctx(ctx)
+	now := s.now().UTC()
+	end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	start := end.AddDate(0, -1, 0)
+
+	accounts, err := s.repo.ListChargeableAccounts(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	charged := 0
+	for _, account := range accounts {
+		ok, err := s.charge(ctx, account, start, end)
+		if err != nil {
+			logger.Error().Err(err).Int64("accountId", account.Id).Msg("failed to charge overdraft interest")
+			continue
+		}
+		if ok {
+			charged++
+		}
+	}
+	return charged, nil
+}
+
+// charge records the interest of the month from start to end. The interest is
+// posted on end, the first day of the next month, so it is not charged on itself.
+func (s *OverdraftService) charge(ctx context.Context, account model.Account, start, end time.Time) (bool, error) {
+	balances, err := s.repo.DailyBalances(ctx, account, start, end)
+	if err != nil {
+		return false, err
+	}
+
+	charge := model.OverdraftInterestCharge{
+		UserId:    account.UserId,
+		AccountId: account.Id,
+		Year:      start.Year(),
+		Month:     int(start.Month()),
+		Amount:    overdraftInterest(balances, *account.OverdraftMonthlyRate),
+	}
+	var transactions []model.Transaction
+	if charge.Amount.IsPositive() {
+		transactions = append(transactions, model.Transaction{
+			UserId:      account.UserId,
+			Description: fmt.Sprintf("%s overdraft interest %04d-%02d", account.Name, charge.Year, charge.Month),
+			Amount:      charge.Amount,
+			Date:        end,
+			Type:        model.Expense,
+			AccountId:   account.Id,
+		})
+	}
+	if len(transactions) > 0 {
+		if err := s.quotas.CheckTransactionCreation(ctx, account.UserId, int64(len(transactions))); err != nil {
+			return false, err
+		}
+	}
+	ok, err := s.repo.RecordCharge(ctx, charge, transactions)
+	if err != nil || !ok {
+		return ok, err
+	}
+	for _, transaction := range transactions {
+		s.transactions.NotifyCreated(ctx, transaction)
+	}
+	return true, nil
+}
+
+// overdraftInterest prorates the monthly rate over the days that ended below
+// zero, each charged on what was owed that day.
+func overdraftInterest(dailyBalances []decimal.Decimal, monthlyRate decimal.Decimal) decimal.Decimal {
+	owed := decimal.Zero
+	for _, balance := range dailyBalances {
+		if balance.IsNegative() {
+			owed = owed.Sub(balance)
+		}
+	}
+	return owed.Mul(percentOf(monthlyRate)).Div(decimal.NewFromInt(overdraftDaysPerMonth)).Round(2)
+}
+
+// checkOverdraft rejects expenses and transfers that would take a checking
+// account with an overdraft limit further below zero than the limit allows.
+func checkOverdraft(account *model.Account, balance decimal.Decimal, tx model.Transaction) error {
+	if account.Type != model.Checking || account.OverdraftLimit == nil {
+		return nil
+	}
+	if tx.Type != model.Expense && tx.Type != model.Transfer {
+		return nil
+	}
+	available := balance.Add(*account.OverdraftLimit)
+	if tx.Amount.GreaterThan(available) {
+		return fmt.Errorf("%w. Balance: %s, Overdraft Limit: %s, Available: %s",
+			ErrOverdraftLimitExceeded, balance.String(), account.OverdraftLimit.String(), available.String())
+	}
+	return nil
+}
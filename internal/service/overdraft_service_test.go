@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/testhelper"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOverdraftRepository is a mock for the OverdraftRepository interface.
+type MockOverdraftRepository struct {
+	mock.Mock
+}
+
+func (m *MockOverdraftRepository) ListChargeableAccounts(ctx context.Context) ([]model.Account, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Account), args.Error(1)
+}
+
+func (m *MockOverdraftRepository) DailyBalances(ctx context.Context, account model.Account, start, end time.Time) ([]decimal.Decimal, error) {
+	args := m.Called(ctx, account, start, end)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]decimal.Decimal), args.Error(1)
+}
+
+func (m *MockOverdraftRepository) RecordCharge(ctx context.Context, charge model.OverdraftInterestCharge, transactions []model.Transaction) (bool, error) {
+	args := m.Called(ctx, charge, transactions)
+	return args.Bool(0), args.Error(1)
+}
+
+// dailyBalances repeats each balance for the given number of days.
+func dailyBalances(runs ...any) []decimal.Decimal {
+	var balances []decimal.Decimal
+	for i := 0; i < len(runs); i += 2 {
+		for range runs[i].(int) {
+			balances = append(balances, decimal.RequireFromString(runs[i+1].(string)))
+		}
+	}
+	return balances
+}
+
+func TestOverdraftService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	now := time.Date(2025, time.July, 1, 3, 0, 0, 0, time.UTC)
+	june := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+	july := time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)
+	checking := model.Account{
+		Id: 10, UserId: 1, Name: "Itaú", Type: model.Checking,
+		OverdraftLimit: testhelper.Ptr(decimal.NewFromInt(2000)), OverdraftMonthlyRate: testhelper.Ptr(decimal.NewFromInt(8)),
+	}
+
+	setup := func() (*OverdraftService, *MockOverdraftRepository) {
+		mockRepo := new(MockOverdraftRepository)
+		overdraftService := NewOverdraftService(mockRepo, unlimitedQuotas(), NewTransactionService(nil, nil, unlimitedQuotas(), nil, TransactionOptions{}))
+		overdraftService.now = func() time.Time { return now }
+		return overdraftService, mockRepo
+	}
+
+	t.Run("should prorate the interest over the days below zero", func(t *testing.T) {
+		// Arrange: 10 days owing 1,500 and 5 days owing 300, at 8% a month.
+		overdraftService, mockRepo := setup()
+		mockRepo.On("ListChargeableAccounts", ctx).Return([]model.Account{checking}, nil).Once()
+		mockRepo.On("DailyBalances", ctx, checking, june, july).Return(dailyBalances(10, "-1500", 5, "-300", 15, "250"), nil).Once()
+		var charge model.OverdraftInterestCharge
+		var recorded []model.Transaction
+		mockRepo.On("RecordCharge", ctx, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			charge = args.Get(1).(model.OverdraftInterestCharge)
+			recorded = args.Get(2).([]model.Transaction)
+		}).Return(true, nil).Once()
+
+		// Act
+		charged, err := overdraftService.ChargeLastMonth(ctx)
+
+		// Assert: (10 × 1,500 + 5 × 300) × 8% / 30 = 44.00
+		assert.NoError(t, err)
+		assert.Equal(t, 1, charged)
+		assert.Equal(t, 2025, charge.Year)
+		assert.Equal(t, 6, charge.Month)
+		assert.True(t, decimal.NewFromInt(44).Equal(charge.Amount), charge.Amount.String())
+		if assert.Len(t, recorded, 1) {
+			assert.Equal(t, model.Expense, recorded[0].Type)
+			assert.Equal(t, july, recorded[0].Date)
+			assert.True(t, charge.Amount.Equal(recorded[0].Amount))
+		}
+	})
+
+	t.Run("should record months without interest and post nothing", func(t *testing.T) {
+		// Arrange
+		overdraftService, mockRepo := setup()
+		mockRepo.On("ListChargeableAccounts", ctx).Return([]model.Account{checking}, nil).Once()
+		mockRepo.On("DailyBalances", ctx, checking, june, july).Return(dailyBalances(30, "100"), nil).Once()
+		mockRepo.On("RecordCharge", ctx, mock.MatchedBy(func(c model.OverdraftInterestCharge) bool { return c.Amount.IsZero() }), []model.Transaction(nil)).Return(true, nil).Once()
+
+		// Act
+		_, err := overdraftService.ChargeLastMonth(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should keep charging the other accounts when one fails", func(t *testing.T) {
+		// Arrange
+		overdraftService, mockRepo := setup()
+		other := checking
+		other.Id = 11
+		mockRepo.On("ListChargeableAccounts", ctx).Return([]model.Account{checking, other}, nil).Once()
+		mockRepo.On("DailyBalances", ctx, checking, june, july).Return(nil, errors.New("db error")).Once()
+		mockRepo.On("DailyBalances", ctx, other, june, july).Return(dailyBalances(30, "-30"), nil).Once()
+		mockRepo.On("RecordCharge", ctx, mock.Anything, mock.Anything).Return(true, nil).Once()
+
+		// Act
+		charged, err := overdraftService.ChargeLastMonth(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, charged)
+	})
+}
+
+func TestTransactionServiceOverdraftLimit(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	checking := &model.Account{Id: 10, UserId: 1, Type: model.Checking, OverdraftLimit: testhelper.Ptr(decimal.NewFromInt(500))}
+
+	testCases := []struct {
+		name        string
+		txType      model.TransactionType
+		amount      int64
+		expectError bool
+	}{
+		{name: "should accept an expense that uses the overdraft", txType: model.Expense, amount: 600},
+		{name: "should accept an expense that uses the whole overdraft", txType: model.Expense, amount: 700},
+		{name: "should reject an expense beyond the overdraft", txType: model.Expense, amount: 701, expectError: true},
+		{name: "should reject a transfer beyond the overdraft", txType: model.Transfer, amount: 800, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange: the account has 200 and 500 of overdraft.
+			mockAccountRepo := new(MockAccountRepository)
+			mockTxRepo := new(MockTransactionRepository)
+			txService := NewTransactionService(mockTxRepo, mockAccountRepo, unlimitedQuotas(), nil, TransactionOptions{})
+			destinationId := int64(20)
+			tx := model.Transaction{UserId: 1, AccountId: checking.Id, Amount: decimal.NewFromInt(tc.amount), Type: tc.txType, Date: time.Now()}
+			if tc.txType == model.Transfer {
+				tx.DestinationAccountId = &destinationId
+				mockAccountRepo.On("GetById", ctx, destinationId, int64(1)).Return(&model.Account{Id: destinationId, Type: model.Savings}, nil).Maybe()
+			}
+			mockAccountRepo.On("GetById", ctx, checking.Id, int64(1)).Return(checking, nil).Once()
+			mockAccountRepo.On("GetCurrentBalance", ctx, checking.Id, int64(1)).Return(decimal.NewFromInt(200), nil).Once()
+			mockTxRepo.On("Create", ctx, mock.Anything).Return(int64(1), nil).Maybe()
+
+			// Act
+			_, err := txService.CreateTransaction(ctx, tx)
+
+			// Assert
+			if tc.expectError {
+				assert.ErrorIs(t, err, ErrOverdraftLimitExceeded)
+				mockTxRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrPaycheckTemplateNotFound = errors.New("paycheck template not found")
+	ErrInvalidPaycheckLines     = errors.New("paychecks need at least one earning and deductions smaller than the earnings")
+	ErrInvalidPaycheckAccount   = errors.New("paychecks can only be paid into checking, savings or other accounts")
+	ErrInvalidPaycheckCategory  = errors.New("paycheck category not found")
+	ErrPaycheckAlreadyPosted    = repository.ErrPaycheckAlreadyPosted
+)
+
+// paycheckKindLabels describe the extra payments in the income transaction.
+var paycheckKindLabels = map[model.PaycheckKind]string{
+	model.PaycheckThirteenth: "13th salary",
+	model.PaycheckVacation:   "vacation pay",
+	model.PaycheckBonus:      "bonus",
+}
+
+// PostPaycheckInput pays a template for a month. Lines replace the template's
+// lines for variable months, such as the 13th salary or vacation pay; PayDate
+// defaults to the template's pay day in that month, or today.
+type PostPaycheckInput struct {
+	Kind    model.PaycheckKind
+	Year    int
+	Month   int
+	PayDate *time.Time
+	Lines   []model.PaycheckLine
+}
+
+// PaycheckLineTotal adds up the lines with the same kind, description and category.
+type PaycheckLineTotal struct {
+	Kind        model.PaycheckLineKind
+	Description string
+	CategoryId  *int64
+	Amount      decimal.Decimal
+}
+
+// PaycheckSummary totals a year of paychecks, the basis of the income tax return.
+type PaycheckSummary struct {
+	Year       int
+	Paychecks  int
+	Gross      decimal.Decimal
+	Deductions decimal.Decimal
+	Net        decimal.Decimal
+	Lines      []PaycheckLineTotal
+}
+
+// PaycheckService manages salary templates and posts their paychecks: the net
+// amount as an income transaction and the gross and deductions as lines.
+type PaycheckService struct {
+	repo         repository.PaycheckRepository
+	accountRepo  repository.AccountRepository
+	categoryRepo repository.CategoryRepository
+	quotas       *QuotaService
+	transactions *TransactionService
+	now          func() time.Time
+}
+
+// NewPaycheckService creates a new instance of PaycheckService.
+func NewPaycheckService(repo repository.PaycheckRepository, accountRepo repository.AccountRepository, categoryRepo repository.CategoryRepository, quotas *QuotaService, transactions *TransactionService) *PaycheckService {
+	return &PaycheckService{
+		repo:         repo,
+		accountRepo:  accountRepo,
+		categoryRepo: categoryRepo,
+		quotas:       quotas,
+		transactions: transactions,
+		now:          time.Now,
+	}
+}
+
+// CreateTemplate validates and stores a paycheck template.
+func (s *PaycheckService) CreateTemplate(ctx context.Context, template model.PaycheckTemplate) (*model.PaycheckTemplate, error) {
+	if err := s.validateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+	id, err := s.repo.CreateTemplate(ctx, template)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetTemplate(ctx, id, template.UserId)
+}
+
+// UpdateTemplate replaces a template. Paychecks already posted do not change.
+func (s *PaycheckService) UpdateTemplate(ctx context.Context, template model.PaycheckTemplate) (*model.PaycheckTemplate, error) {
+	if err := s.validateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateTemplate(ctx, template); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPaycheckTemplateNotFound
+		}
+		return nil, err
+	}
+	return s.repo.GetTemplate(ctx, template.Id, template.UserId)
+}
+
+// ListTemplates returns the user's paycheck templates.
+func (s *PaycheckService) ListTemplates(ctx context.Context, userId int64) ([]model.PaycheckTemplate, error) {
+	return s.repo.ListTemplates(ctx, userId)
+}
+
+// DeleteTemplate removes a template. Paychecks already posted are kept.
+func (s *PaycheckService) DeleteTemplate(ctx context.Context, id, userId int64) error {
+	if err := s.repo.DeleteTemplate(ctx, id, userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrPaycheckTemplateNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Post pays a template for a month.
+func (s *PaycheckService) Post(ctx context.Context, userId, templateId int64, input PostPaycheckInput) (*model.Paycheck, error) {
+	template, err := s.repo.GetTemplate(ctx, templateId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPaycheckTemplateNotFound
+		}
+		return nil, err
+	}
+	if input.Lines != nil {
+		if err := s.validateLines(ctx, userId, input.Lines); err != nil {
+			return nil, err
+		}
+	}
+	return s.post(ctx, *template, input)
+}
+
+// PostDueTemplates pays the regular paycheck of every scheduled template whose
+// pay day of the current month has arrived. It is run periodically; a month is
+// paid once.
+func (s *PaycheckService) PostDueTemplates(ctx context.Context) (int, error) {
+	logger := zerolog.Ctx(ctx)
+	now := s.now()
+
+	templates, err := s.repo.ListScheduledTemplates(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	posted := 0
+	for _, template := range templates {
+		payDate := benefitCreditDate(now.Year(), now.Month(), *template.PayDay)
+		if now.Before(payDate) {
+			continue
+		}
+		_, err := s.post(ctx, template, PostPaycheckInput{Kind: model.PaycheckRegular, Year: now.Year(), Month: int(now.Month()), PayDate: &payDate})
+		if errors.Is(err, ErrPaycheckAlreadyPosted) {
+			continue
+		}
+		if err != nil {
+			logger.Error().Err(err).Int64("templateId", template.Id).Msg("failed to post scheduled paycheck")
+			continue
+		}
+		posted++
+	}
+	return posted, nil
+}
+
+// ListPaychecks returns the paychecks paid in a year.
+func (s *PaycheckService) ListPaychecks(ctx context.Context, userId int64, year int) ([]model.Paycheck, error) {
+	return s.repo.ListPaychecks(ctx, userId, year)
+}
+
+// GetAnnualSummary totals the gross, deductions and net of a year of paychecks,
+// with each earning and deduction added up across the year.
+func (s *PaycheckService) GetAnnualSummary(ctx context.Context, userId int64, year int) (*PaycheckSummary, error) {
+	if !validReportPeriod(year, 1) {
+		return nil, ErrInvalidReportPeriod
+	}
+	paychecks, err := s.repo.ListPaychecks(ctx, userId, year)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &PaycheckSummary{Year: year, Paychecks: len(paychecks), Lines: []PaycheckLineTotal{}}
+	totals := map[string]*PaycheckLineTotal{}
+	var keys []string
+	for _, paycheck := range paychecks {
+		summary.Gross = summary.Gross.Add(paycheck.Gross)
+		summary.Deductions = summary.Deductions.Add(paycheck.Deductions)
+		summary.Net = summary.Net.Add(paycheck.Net)
+		for _, line := range paycheck.Lines {
+			key := fmt.Sprintf("%s|%s|%v", line.Kind, line.Description, categoryKey(line.CategoryId))
+			total, ok := totals[key]
+			if !ok {
+				total = &PaycheckLineTotal{Kind: line.Kind, Description: line.Description, CategoryId: line.CategoryId}
+				totals[key] = total
+				keys = append(keys, key)
+			}
+			total.Amount = total.Amount.Add(line.Amount)
+		}
+	}
+	for _, key := range keys {
+		summary.Lines = append(summary.Lines, *totals[key])
+	}
+	// Earnings first, then the biggest amounts.
+	sort.SliceStable(summary.Lines, func(i, j int) bool {
+		a, b := summary.Lines[i], summary.Lines[j]
+		if a.Kind != b.Kind {
+			return a.Kind == model.PaycheckEarning
+		}
+		return a.Amount.GreaterThan(b.Amount)
+	})
+	return summary, nil
+}
+
+func (s *PaycheckService) post(ctx context.Context, template model.PaycheckTemplate, input PostPaycheckInput) (*model.Paycheck, error) {
+	if !validReportPeriod(input.Year, input.Month) {
+		return nil, ErrInvalidReportPeriod
+	}
+	if input.Kind == "" {
+		input.Kind = model.PaycheckRegular
+	}
+	lines := template.Lines
+	if input.Lines != nil {
+		lines = input.Lines
+	}
+	gross, deductions, err := paycheckTotals(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	paycheck := model.Paycheck{
+		UserId:     template.UserId,
+		TemplateId: &template.Id,
+		AccountId:  template.AccountId,
+		Kind:       input.Kind,
+		Year:       input.Year,
+		Month:      input.Month,
+		Gross:      gross,
+		Deductions: deductions,
+		Net:        gross.Sub(deductions),
+		Lines:      lines,
+	}
+	switch {
+	case input.PayDate != nil:
+		paycheck.PayDate = *input.PayDate
+	case template.PayDay != nil:
+		paycheck.PayDate = benefitCreditDate(input.Year, time.Month(input.Month), *template.PayDay)
+	default:
+		paycheck.PayDate = truncateToDay(s.now())
+	}
+
+	description := template.Name
+	if label, ok := paycheckKindLabels[input.Kind]; ok {
+		description = fmt.Sprintf("%s - %s", template.Name, label)
+	}
+	income := model.Transaction{
+		UserId:      template.UserId,
+		Description: fmt.Sprintf("%s %04d-%02d", description, input.Year, input.Month),
+		Amount:      paycheck.Net,
+		Date:        paycheck.PayDate,
+		Type:        model.Income,
+		AccountId:   template.AccountId,
+		CategoryId:  template.IncomeCategoryId,
+	}
+
+	if err := s.quotas.CheckTransactionCreation(ctx, template.UserId, 1); err != nil {
+		return nil, err
+	}
+	paycheck.Id, err = s.repo.CreatePaycheck(ctx, paycheck, &income)
+	if err != nil {
+		return nil, err
+	}
+	paycheck.TransactionId = &income.Id
+	s.transactions.NotifyCreated(ctx, income)
+	return &paycheck, nil
+}
+
+func (s *PaycheckService) validateTemplate(ctx context.Context, template model.PaycheckTemplate) error {
+	account, err := s.accountRepo.GetById(ctx, template.AccountId, template.UserId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidPaycheckAccount
+		}
+		return err
+	}
+	switch account.Type {
+	case model.Checking, model.Savings, model.Other:
+	default:
+		return ErrInvalidPaycheckAccount
+	}
+	if template.IncomeCategoryId != nil {
+		if err := s.checkCategory(ctx, template.UserId, *template.IncomeCategoryId); err != nil {
+			return err
+		}
+	}
+	return s.validateLines(ctx, template.UserId, template.Lines)
+}
+
+func (s *PaycheckService) validateLines(ctx context.Context, userId int64, lines []model.PaycheckLine) error {
+	if _, _, err := paycheckTotals(lines); err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if line.CategoryId == nil {
+			continue
+		}
+		if err := s.checkCategory(ctx, userId, *line.CategoryId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *PaycheckService) checkCategory(ctx context.Context, userId, categoryId int64) error {
+	if _, err := s.categoryRepo.GetById(ctx, categoryId, userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidPaycheckCategory
+		}
+		return err
+	}
+	return nil
+}
+
+// paycheckTotals adds up the earnings and the deductions. The net amount, what
+// is left after the deductions, must be positive.
+func paycheckTotals(lines []model.PaycheckLine) (decimal.Decimal, decimal.Decimal, error) {
+	gross, deductions := decimal.Zero, decimal.Zero
+	for _, line := range lines {
+		if !line.Amount.IsPositive() {
+			return gross, deductions, ErrInvalidPaycheckLines
+		}
+		switch line.Kind {
+		case model.PaycheckEarning:
+			gross = gross.Add(line.Amount)
+		case model.PaycheckDeduction:
+			deductions = deductions.Add(line.Amount)
+		default:
+			return gross, deductions, ErrInvalidPaycheckLines
+		}
+	}
+	if !gross.Sub(deductions).IsPositive() {
+		return gross, deductions, ErrInvalidPaycheckLines
+	}
+	return gross, deductions, nil
+}
+
+func categoryKey(categoryId *int64) any {
+	if categoryId == nil {
+		return nil
+	}
+	return *categoryId
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/testhelper"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPaycheckRepository is a mock for the PaycheckRepository interface.
+type MockPaycheckRepository struct {
+	mock.Mock
+}
+
+func (m *MockPaycheckRepository) CreateTemplate(ctx context.Context, template model.PaycheckTemplate) (int64, error) {
+	args := m.Called(ctx, template)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPaycheckRepository) GetTemplate(ctx context.Context, id, userId int64) (*model.PaycheckTemplate, error) {
+	args := m.Called(ctx, id, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PaycheckTemplate), args.Error(1)
+}
+
+func (m *MockPaycheckRepository) ListTemplates(ctx context.Context, userId int64) ([]model.PaycheckTemplate, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PaycheckTemplate), args.Error(1)
+}
+
+func (m *MockPaycheckRepository) ListScheduledTemplates(ctx context.Context) ([]model.PaycheckTemplate, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PaycheckTemplate), args.Error(1)
+}
+
+func (m *MockPaycheckRepository) UpdateTemplate(ctx context.Context, template model.PaycheckTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *MockPaycheckRepository) DeleteTemplate(ctx context.Context, id, userId int64) error {
+	args := m.Called(ctx, id, userId)
+	return args.Error(0)
+}
+
+func (m *MockPaycheckRepository) CreatePaycheck(ctx context.Context, paycheck model.Paycheck, income *model.Transaction) (int64, error) {
+	args := m.Called(ctx, paycheck, income)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPaycheckRepository) ListPaychecks(ctx context.Context, userId int64, year int) ([]model.Paycheck, error) {
+	args := m.Called(ctx, userId, year)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Paycheck), args.Error(1)
+}
+
+func TestPaycheckService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	userId := int64(1)
+	now := time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC)
+	inssCategory := int64(7)
+
+	salaryLines := []model.PaycheckLine{
+		{Kind: model.PaycheckEarning, Description: "Salary", Amount: decimal.NewFromInt(8000)},
+		{Kind: model.PaycheckDeduction, Description: "INSS", CategoryId: &inssCategory, Amount: decimal.RequireFromString("908.85")},
+		{Kind: model.PaycheckDeduction, Description: "IRRF", Amount: decimal.RequireFromString("1059.42")},
+	}
+	salary := model.PaycheckTemplate{Id: 3, UserId: userId, Name: "ACME", AccountId: 10, PayDay: testhelper.Ptr(30), Active: true, Lines: salaryLines}
+
+	setup := func() (*PaycheckService, *MockPaycheckRepository, *MockAccountRepository, *MockCategoryRepository) {
+		mockRepo := new(MockPaycheckRepository)
+		mockAccountRepo := new(MockAccountRepository)
+		mockCategoryRepo := new(MockCategoryRepository)
+		transactionService := NewTransactionService(nil, mockAccountRepo, unlimitedQuotas(), nil, TransactionOptions{})
+		paycheckService := NewPaycheckService(mockRepo, mockAccountRepo, mockCategoryRepo, unlimitedQuotas(), transactionService)
+		paycheckService.now = func() time.Time { return now }
+		return paycheckService, mockRepo, mockAccountRepo, mockCategoryRepo
+	}
+
+	t.Run("CreateTemplate", func(t *testing.T) {
+		t.Run("should reject credit card accounts", func(t *testing.T) {
+			// Arrange
+			paycheckService, mockRepo, mockAccountRepo, _ := setup()
+			mockAccountRepo.On("GetById", ctx, salary.AccountId, userId).Return(&model.Account{Id: salary.AccountId, Type: model.CreditCard}, nil).Once()
+
+			// Act
+			_, err := paycheckService.CreateTemplate(ctx, salary)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidPaycheckAccount)
+			mockRepo.AssertNotCalled(t, "CreateTemplate", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should reject deductions larger than the earnings", func(t *testing.T) {
+			// Arrange
+			paycheckService, _, mockAccountRepo, _ := setup()
+			mockAccountRepo.On("GetById", ctx, salary.AccountId, userId).Return(&model.Account{Id: salary.AccountId, Type: model.Checking}, nil).Once()
+			template := salary
+			template.Lines = []model.PaycheckLine{
+				{Kind: model.PaycheckEarning, Description: "Salary", Amount: decimal.NewFromInt(1000)},
+				{Kind: model.PaycheckDeduction, Description: "Loan", Amount: decimal.NewFromInt(1000)},
+			}
+
+			// Act
+			_, err := paycheckService.CreateTemplate(ctx, template)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidPaycheckLines)
+		})
+
+		t.Run("should reject categories of other users", func(t *testing.T) {
+			// Arrange
+			paycheckService, _, mockAccountRepo, mockCategoryRepo := setup()
+			mockAccountRepo.On("GetById", ctx, salary.AccountId, userId).Return(&model.Account{Id: salary.AccountId, Type: model.Checking}, nil).Once()
+			mockCategoryRepo.On("GetById", ctx, inssCategory, userId).Return(nil, sql.ErrNoRows).Once()
+
+			// Act
+			_, err := paycheckService.CreateTemplate(ctx, salary)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidPaycheckCategory)
+		})
+	})
+
+	t.Run("Post", func(t *testing.T) {
+		t.Run("should post the net amount and keep the gross and deductions", func(t *testing.T) {
+			// Arrange
+			paycheckService, mockRepo, _, _ := setup()
+			mockRepo.On("GetTemplate", ctx, salary.Id, userId).Return(&salary, nil).Once()
+			var paycheck model.Paycheck
+			var income model.Transaction
+			mockRepo.On("CreatePaycheck", ctx, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				paycheck = args.Get(1).(model.Paycheck)
+				income = *args.Get(2).(*model.Transaction)
+			}).Return(int64(50), nil).Once()
+
+			// Act
+			posted, err := paycheckService.Post(ctx, userId, salary.Id, PostPaycheckInput{Year: 2025, Month: 2})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, int64(50), posted.Id)
+			assert.Equal(t, model.PaycheckRegular, paycheck.Kind)
+			assert.True(t, paycheck.Gross.Equal(decimal.NewFromInt(8000)))
+			assert.True(t, paycheck.Deductions.Equal(decimal.RequireFromString("1968.27")))
+			assert.True(t, income.Amount.Equal(decimal.RequireFromString("6031.73")))
+			assert.Equal(t, model.Income, income.Type)
+			assert.Equal(t, salary.AccountId, income.AccountId)
+			assert.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), income.Date)
+			assert.Equal(t, "ACME 2025-02", income.Description)
+		})
+
+		t.Run("should use the lines sent for variable months", func(t *testing.T) {
+			// Arrange
+			paycheckService, mockRepo, _, _ := setup()
+			mockRepo.On("GetTemplate", ctx, salary.Id, userId).Return(&salary, nil).Once()
+			var income model.Transaction
+			mockRepo.On("CreatePaycheck", ctx, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				income = *args.Get(2).(*model.Transaction)
+			}).Return(int64(51), nil).Once()
+			lines := []model.PaycheckLine{
+				{Kind: model.PaycheckEarning, Description: "13th salary", Amount: decimal.NewFromInt(4000)},
+				{Kind: model.PaycheckDeduction, Description: "IRRF", Amount: decimal.NewFromInt(500)},
+			}
+
+			// Act
+			posted, err := paycheckService.Post(ctx, userId, salary.Id, PostPaycheckInput{Kind: model.PaycheckThirteenth, Year: 2025, Month: 11, Lines: lines})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.True(t, posted.Net.Equal(decimal.NewFromInt(3500)))
+			assert.Equal(t, "ACME - 13th salary 2025-11", income.Description)
+			assert.Len(t, posted.Lines, 2)
+		})
+
+		t.Run("should run the savings rules on the income", func(t *testing.T) {
+			// Arrange
+			paycheckService, mockRepo, mockAccountRepo, _ := setup()
+			salaryCategory := int64(5)
+			savings := &model.Account{Id: 20, UserId: userId, Type: model.Savings}
+			template := salary
+			template.IncomeCategoryId = &salaryCategory
+			mockAccountRepo.On("GetById", ctx, salary.AccountId, userId).Return(&model.Account{Id: salary.AccountId, UserId: userId, Type: model.Checking}, nil).Maybe()
+			mockAccountRepo.On("GetById", ctx, savings.Id, userId).Return(savings, nil).Maybe()
+			mockRulesRepo := new(MockSavingsRuleRepository)
+			paycheckService.transactions.AddHook(NewSavingsRuleService(mockRulesRepo, mockAccountRepo, new(MockCategoryRepository), unlimitedQuotas()))
+			mockRepo.On("GetTemplate", ctx, salary.Id, userId).Return(&template, nil).Once()
+			mockRepo.On("CreatePaycheck", ctx, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				args.Get(2).(*model.Transaction).Id = 300
+			}).Return(int64(52), nil).Once()
+			mockRulesRepo.On("ListRulesToRun", ctx, userId).Return([]model.SavingsRule{{
+				Id: 2, UserId: userId, Type: model.SavingsPercentOfIncome, SavingsAccountId: savings.Id, CategoryId: &salaryCategory, Percent: testhelper.Ptr(decimal.NewFromInt(10)),
+			}}, nil).Once()
+			var transfer *model.Transaction
+			mockRulesRepo.On("RecordRun", ctx, mock.MatchedBy(func(run model.SavingsRuleRun) bool {
+				return run.RuleId == 2 && run.TransactionId == 300
+			}), mock.Anything).Run(func(args mock.Arguments) {
+				transfer = args.Get(2).(*model.Transaction)
+			}).Return(true, nil).Once()
+
+			// Act
+			posted, err := paycheckService.Post(ctx, userId, salary.Id, PostPaycheckInput{Year: 2025, Month: 2})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, int64(300), *posted.TransactionId)
+			if assert.NotNil(t, transfer) {
+				assert.Equal(t, model.Transfer, transfer.Type)
+				assert.Equal(t, salary.AccountId, transfer.AccountId)
+				assert.Equal(t, savings.Id, *transfer.DestinationAccountId)
+				assert.True(t, decimal.RequireFromString("603.17").Equal(transfer.Amount), transfer.Amount.String())
+			}
+			mockRulesRepo.AssertExpectations(t)
+		})
+
+		t.Run("should return not found for unknown templates", func(t *testing.T) {
+			// Arrange
+			paycheckService, mockRepo, _, _ := setup()
+			mockRepo.On("GetTemplate", ctx, int64(99), userId).Return(nil, sql.ErrNoRows).Once()
+
+			// Act
+			_, err := paycheckService.Post(ctx, userId, 99, PostPaycheckInput{Year: 2025, Month: 2})
+
+			// Assert
+			assert.ErrorIs(t, err, ErrPaycheckTemplateNotFound)
+		})
+	})
+
+	t.Run("PostDueTemplates", func(t *testing.T) {
+		t.Run("should post due templates once per month", func(t *testing.T) {
+			// Arrange
+			paycheckService, mockRepo, _, _ := setup()
+			paycheckService.now = func() time.Time { return time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC) }
+			other := salary
+			other.Id = 4
+			other.PayDay = testhelper.Ptr(1)
+			notYet := salary
+			notYet.Id = 5
+			notYet.PayDay = testhelper.Ptr(5)
+			mockRepo.On("ListScheduledTemplates", ctx).Return([]model.PaycheckTemplate{other, notYet}, nil).Once()
+			mockRepo.On("CreatePaycheck", ctx, mock.MatchedBy(func(p model.Paycheck) bool {
+				return *p.TemplateId == other.Id && p.Year == 2025 && p.Month == 3
+			}), mock.Anything).Return(int64(0), ErrPaycheckAlreadyPosted).Once()
+
+			// Act
+			posted, err := paycheckService.PostDueTemplates(ctx)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 0, posted)
+			mockRepo.AssertExpectations(t)
+		})
+	})
+
+	t.Run("GetAnnualSummary", func(t *testing.T) {
+		t.Run("should add up the lines of the year", func(t *testing.T) {
+			// Arrange
+			paycheckService, mockRepo, _, _ := setup()
+			mockRepo.On("ListPaychecks", ctx, userId, 2025).Return([]model.Paycheck{
+				{Gross: decimal.NewFromInt(8000), Deductions: decimal.RequireFromString("1968.27"), Net: decimal.RequireFromString("6031.73"), Lines: salaryLines},
+				{Gross: decimal.NewFromInt(8000), Deductions: decimal.RequireFromString("1968.27"), Net: decimal.RequireFromString("6031.73"), Lines: salaryLines},
+			}, nil).Once()
+
+			// Act
+			summary, err := paycheckService.GetAnnualSummary(ctx, userId, 2025)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 2, summary.Paychecks)
+			assert.True(t, summary.Net.Equal(decimal.RequireFromString("12063.46")))
+			assert.Len(t, summary.Lines, 3)
+			assert.Equal(t, "Salary", summary.Lines[0].Description)
+			assert.Equal(t, "IRRF", summary.Lines[1].Description)
+			assert.True(t, summary.Lines[2].Amount.Equal(decimal.RequireFromString("1817.70")))
+		})
+	})
+}
@@ -0,0 +1,617 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrInvalidScenario           = errors.New("invalid scenario: check the months, amounts, rates and installments of the changes")
+	ErrInvalidRetirementPlan     = errors.New("invalid retirement plan: check the accounts, rates, horizon and number of simulations")
+	ErrRetirementAccountNotFound = errors.New("retirement account not found")
+)
+
+const (
+	// baselineHistoryMonths is how many full months of transactions the
+	// recurring items of the baseline are averaged from.
+	baselineHistoryMonths = 3
+	// maxScenarioMonths caps how far a scenario is projected.
+	maxScenarioMonths = 120
+	// retirementHistoryMonths is how many full months the default savings and
+	// expenses of a retirement projection are averaged from.
+	retirementHistoryMonths = 12
+	// defaultRetirementSeed seeds the Monte Carlo simulation when no seed is sent,
+	// so the same plan always gets the same probability.
+	defaultRetirementSeed uint64 = 20240601
+)
+
+// RecurringItem is a monthly income or expense. Baseline items are the average
+// of a category over the last baselineHistoryMonths full months.
+type RecurringItem struct {
+	Type         model.TransactionType
+	CategoryId   *int64
+	CategoryName string
+	Amount       decimal.Decimal
+}
+
+// CategoryChange scales the recurring items of a category by Percent, such as
+// 10 for a rent that goes up 10%.
+type CategoryChange struct {
+	CategoryId int64
+	Percent    decimal.Decimal
+}
+
+// ScenarioRecurringItem is a hypothetical monthly income or expense paid from
+// StartMonth through EndMonth, or until the end of the projection. Months are
+// counted from 1, the next calendar month.
+type ScenarioRecurringItem struct {
+	Description string
+	Type        model.TransactionType
+	Amount      decimal.Decimal
+	StartMonth  int
+	EndMonth    *int
+}
+
+// ScenarioPurchase is a one-off expense paid in Month.
+type ScenarioPurchase struct {
+	Description string
+	Amount      decimal.Decimal
+	Month       int
+}
+
+// ScenarioLoan finances a purchase in equal installments (Price table) from
+// StartMonth. The item bought counts toward the net worth at AssetValue,
+// which defaults to the financed amount, and the outstanding debt against it.
+type ScenarioLoan struct {
+	Description         string
+	Principal           decimal.Decimal
+	MonthlyInterestRate decimal.Decimal
+	Installments        int
+	StartMonth          int
+	AssetValue          *decimal.Decimal
+}
+
+// ScenarioInput describes the hypothetical changes applied on top of the baseline.
+type ScenarioInput struct {
+	Months              int
+	IncomeChangePercent decimal.Decimal
+	CategoryChanges     []CategoryChange
+	RemoveCategoryIds   []int64
+	AddRecurring        []ScenarioRecurringItem
+	Purchases           []ScenarioPurchase
+	Loans               []ScenarioLoan
+}
+
+// ScenarioMonth compares the projected balance and net worth of the scenario
+// with the baseline at the end of a month.
+type ScenarioMonth struct {
+	Year             int
+	Month            int
+	BaselineBalance  decimal.Decimal
+	ScenarioBalance  decimal.Decimal
+	BaselineNetWorth decimal.Decimal
+	ScenarioNetWorth decimal.Decimal
+	LoanBalance      decimal.Decimal
+}
+
+// ScenarioResult is the month-by-month projection of a scenario.
+type ScenarioResult struct {
+	StartingBalance  decimal.Decimal
+	StartingNetWorth decimal.Decimal
+	BaselineItems    []RecurringItem
+	BaselineMonthly  decimal.Decimal
+	Months           []ScenarioMonth
+	// FirstNegativeMonth is the first month the scenario balance goes below zero.
+	FirstNegativeMonth *int
+}
+
+// PlanningService projects the user's finances into the future. It only reads
+// data; scenarios are never written to the real tables.
+type PlanningService struct {
+	transactionRepo repository.TransactionRepository
+	accountRepo     repository.AccountRepository
+	netWorthService *NetWorthService
+	now             func() time.Time
+}
+
+// NewPlanningService creates a new instance of PlanningService.
+func NewPlanningService(transactionRepo repository.TransactionRepository, accountRepo repository.AccountRepository, netWorthService *NetWorthService) *PlanningService {
+	return &PlanningService{
+		transactionRepo: transactionRepo,
+		accountRepo:     accountRepo,
+		netWorthService: netWorthService,
+		now:             time.Now,
+	}
+}
+
+// SimulateScenario projects the baseline, today's liquid balance and net worth
+// carried forward by the recurring items, and the scenario, the same with the
+// changes applied, month by month.
+func (s *PlanningService) SimulateScenario(ctx context.Context, userId int64, input ScenarioInput) (*ScenarioResult, error) {
+	if err := validateScenario(input); err != nil {
+		return nil, err
+	}
+
+	netWorth, err := s.netWorthService.GetNetWorth(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.baselineItems(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ScenarioResult{
+		StartingBalance:  liquidBalance(netWorth),
+		StartingNetWorth: netWorth.Total,
+		BaselineItems:    items,
+		BaselineMonthly:  monthlyNet(items),
+	}
+	scenarioMonthly := monthlyNet(applyScenarioChanges(items, input))
+
+	baselineBalance, scenarioBalance := result.StartingBalance, result.StartingBalance
+	assets, debts := decimal.Zero, make([]decimal.Decimal, len(input.Loans))
+	schedules := make([][]loanInstallment, len(input.Loans))
+	for i, loan := range input.Loans {
+		schedules[i] = loanSchedule(loan)
+	}
+
+	start := s.now()
+	for m := 1; m <= input.Months; m++ {
+		date := time.Date(start.Year(), start.Month()+time.Month(m), 1, 0, 0, 0, 0, time.UTC)
+
+		baselineBalance = baselineBalance.Add(result.BaselineMonthly)
+		scenarioBalance = scenarioBalance.Add(scenarioMonthly)
+		for _, item := range input.AddRecurring {
+			if m < item.StartMonth || (item.EndMonth != nil && m > *item.EndMonth) {
+				continue
+			}
+			scenarioBalance = scenarioBalance.Add(signedAmount(item.Type, item.Amount))
+		}
+		for _, purchase := range input.Purchases {
+			if purchase.Month == m {
+				scenarioBalance = scenarioBalance.Sub(purchase.Amount)
+			}
+		}
+		loanBalance := decimal.Zero
+		for i, loan := range input.Loans {
+			if m == loan.StartMonth {
+				debts[i] = loan.Principal
+				assets = assets.Add(loanAssetValue(loan))
+			}
+			if index := m - loan.StartMonth; index >= 0 && index < len(schedules[i]) {
+				installment := schedules[i][index]
+				scenarioBalance = scenarioBalance.Sub(installment.Payment)
+				debts[i] = debts[i].Sub(installment.Amortization)
+			}
+			loanBalance = loanBalance.Add(debts[i])
+		}
+
+		month := ScenarioMonth{
+			Year:             date.Year(),
+			Month:            int(date.Month()),
+			BaselineBalance:  baselineBalance,
+			ScenarioBalance:  scenarioBalance,
+			BaselineNetWorth: result.StartingNetWorth.Add(baselineBalance.Sub(result.StartingBalance)),
+			ScenarioNetWorth: result.StartingNetWorth.Add(scenarioBalance.Sub(result.StartingBalance)).Add(assets).Sub(loanBalance),
+			LoanBalance:      loanBalance,
+		}
+		result.Months = append(result.Months, month)
+		if result.FirstNegativeMonth == nil && scenarioBalance.IsNegative() {
+			result.FirstNegativeMonth = &m
+		}
+	}
+	return result, nil
+}
+
+// baselineItems averages the incomes and expenses of each category over the
+// last full months. Transfers move money between the user's own accounts and
+// are left out.
+func (s *PlanningService) baselineItems(ctx context.Context, userId int64) ([]RecurringItem, error) {
+	now := s.now()
+	end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	start := end.AddDate(0, -baselineHistoryMonths, 0)
+	end = end.Add(-time.Second)
+	transactions, err := s.transactionRepo.List(ctx, userId, repository.ListTransactionFilters{StartDate: &start, EndDate: &end})
+	if err != nil {
+		return nil, err
+	}
+
+	totals := map[string]*RecurringItem{}
+	for _, tx := range transactions {
+		if tx.Type == model.Transfer {
+			continue
+		}
+		key := fmt.Sprintf("%s|%v", tx.Type, categoryKey(tx.CategoryId))
+		item, ok := totals[key]
+		if !ok {
+			item = &RecurringItem{Type: tx.Type, CategoryId: tx.CategoryId, CategoryName: uncategorizedName}
+			if tx.CategoryName != nil {
+				item.CategoryName = *tx.CategoryName
+			}
+			totals[key] = item
+		}
+		item.Amount = item.Amount.Add(tx.Amount)
+	}
+
+	items := []RecurringItem{}
+	months := decimal.NewFromInt(baselineHistoryMonths)
+	for _, item := range totals {
+		item.Amount = item.Amount.Div(months).Round(2)
+		items = append(items, *item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Type != items[j].Type {
+			return items[i].Type == model.Income
+		}
+		return items[i].Amount.GreaterThan(items[j].Amount)
+	})
+	return items, nil
+}
+
+// applyScenarioChanges returns the baseline items with the income change, the
+// category changes and the removals of a scenario applied.
+func applyScenarioChanges(items []RecurringItem, input ScenarioInput) []RecurringItem {
+	removed := map[int64]bool{}
+	for _, id := range input.RemoveCategoryIds {
+		removed[id] = true
+	}
+	changes := map[int64]decimal.Decimal{}
+	for _, change := range input.CategoryChanges {
+		changes[change.CategoryId] = change.Percent
+	}
+
+	hundred := decimal.NewFromInt(100)
+	changed := make([]RecurringItem, 0, len(items))
+	for _, item := range items {
+		if item.CategoryId != nil && removed[*item.CategoryId] {
+			continue
+		}
+		if item.Type == model.Income && !input.IncomeChangePercent.IsZero() {
+			item.Amount = item.Amount.Mul(hundred.Add(input.IncomeChangePercent)).Div(hundred).Round(2)
+		}
+		if item.CategoryId != nil {
+			if percent, ok := changes[*item.CategoryId]; ok {
+				item.Amount = item.Amount.Mul(hundred.Add(percent)).Div(hundred).Round(2)
+			}
+		}
+		changed = append(changed, item)
+	}
+	return changed
+}
+
+// loanInstallment is one payment of a loan and the part of it that repays the principal.
+type loanInstallment struct {
+	Payment      decimal.Decimal
+	Amortization decimal.Decimal
+}
+
+// loanSchedule splits a loan into equal installments (Price table). The last
+// installment absorbs the rounding so the debt ends at zero.
+func loanSchedule(loan ScenarioLoan) []loanInstallment {
+	n := int64(loan.Installments)
+	rate := loan.MonthlyInterestRate.Div(decimal.NewFromInt(100))
+	payment := loan.Principal.Div(decimal.NewFromInt(n))
+	if rate.IsPositive() {
+		growth := decimal.NewFromInt(1).Add(rate).Pow(decimal.NewFromInt(n))
+		payment = loan.Principal.Mul(rate).Mul(growth).Div(growth.Sub(decimal.NewFromInt(1)))
+	}
+	payment = payment.Round(2)
+
+	schedule := make([]loanInstallment, 0, loan.Installments)
+	balance := loan.Principal
+	for i := 1; i <= loan.Installments; i++ {
+		interest := balance.Mul(rate).Round(2)
+		installment := loanInstallment{Payment: payment, Amortization: payment.Sub(interest)}
+		if i == loan.Installments {
+			installment = loanInstallment{Payment: balance.Add(interest), Amortization: balance}
+		}
+		balance = balance.Sub(installment.Amortization)
+		schedule = append(schedule, installment)
+	}
+	return schedule
+}
+
+func loanAssetValue(loan ScenarioLoan) decimal.Decimal {
+	if loan.AssetValue != nil {
+		return *loan.AssetValue
+	}
+	return loan.Principal
+}
+
+// liquidBalance adds up the money the user can spend: every account except
+// points and physical assets. Card debt is negative and is subtracted.
+func liquidBalance(netWorth *NetWorth) decimal.Decimal {
+	balance := decimal.Zero
+	for _, item := range netWorth.Items {
+		if item.Type == model.Points || item.Type == model.Asset {
+			continue
+		}
+		balance = balance.Add(item.Value)
+	}
+	return balance
+}
+
+func monthlyNet(items []RecurringItem) decimal.Decimal {
+	net := decimal.Zero
+	for _, item := range items {
+		net = net.Add(signedAmount(item.Type, item.Amount))
+	}
+	return net
+}
+
+func signedAmount(transactionType model.TransactionType, amount decimal.Decimal) decimal.Decimal {
+	if transactionType == model.Expense {
+		return amount.Neg()
+	}
+	return amount
+}
+
+func validateScenario(input ScenarioInput) error {
+	validMonth := func(month int) bool { return month >= 1 && month <= input.Months }
+	if input.Months < 1 || input.Months > maxScenarioMonths || input.IncomeChangePercent.LessThanOrEqual(decimal.NewFromInt(-100)) {
+		return ErrInvalidScenario
+	}
+	for _, change := range input.CategoryChanges {
+		if change.Percent.LessThan(decimal.NewFromInt(-100)) {
+			return ErrInvalidScenario
+		}
+	}
+	for _, item := range input.AddRecurring {
+		if !item.Amount.IsPositive() || !validMonth(item.StartMonth) || (item.EndMonth != nil && *item.EndMonth < item.StartMonth) {
+			return ErrInvalidScenario
+		}
+		if item.Type != model.Income && item.Type != model.Expense {
+			return ErrInvalidScenario
+		}
+	}
+	for _, purchase := range input.Purchases {
+		if !purchase.Amount.IsPositive() || !validMonth(purchase.Month) {
+			return ErrInvalidScenario
+		}
+	}
+	for _, loan := range input.Loans {
+		if !loan.Principal.IsPositive() || loan.MonthlyInterestRate.IsNegative() || loan.Installments < 1 || !validMonth(loan.StartMonth) {
+			return ErrInvalidScenario
+		}
+		if loan.AssetValue != nil && loan.AssetValue.IsNegative() {
+			return ErrInvalidScenario
+		}
+	}
+	return nil
+}
+
+// RetirementInput holds the assumptions of a retirement projection. Rates are
+// yearly percentages. MonthlyContribution and MonthlyExpenses default to the
+// average monthly savings and expenses of the last twelve full months; Seed
+// makes the Monte Carlo simulation reproducible.
+type RetirementInput struct {
+	AccountIds          []int64
+	AnnualReturn        decimal.Decimal
+	Inflation           decimal.Decimal
+	WithdrawalRate      decimal.Decimal
+	Volatility          decimal.Decimal
+	MonthlyContribution *decimal.Decimal
+	MonthlyExpenses     *decimal.Decimal
+	Years               int
+	RetirementYears     int
+	Simulations         int
+	Seed                *uint64
+}
+
+// RetirementYear is the state of the portfolio at the end of a year of the
+// projection, in today's money (Balance) and in the money of that year (NominalBalance).
+type RetirementYear struct {
+	Year           int
+	Contributions  decimal.Decimal
+	Growth         decimal.Decimal
+	Balance        decimal.Decimal
+	NominalBalance decimal.Decimal
+	Independent    bool
+}
+
+// RetirementProjection is the result of a retirement projection. The target is
+// the portfolio whose withdrawals at WithdrawalRate cover the yearly expenses.
+type RetirementProjection struct {
+	StartingBalance     decimal.Decimal
+	MonthlyContribution decimal.Decimal
+	MonthlyExpenses     decimal.Decimal
+	RealReturn          decimal.Decimal
+	Target              decimal.Decimal
+	IndependenceDate    *time.Time
+	SuccessProbability  decimal.Decimal
+	Simulations         int
+	Seed                uint64
+	Years               []RetirementYear
+}
+
+// ProjectRetirement projects the selected accounts year by year until the
+// horizon, estimates when they reach financial independence and simulates the
+// accumulation and the retirement withdrawals with random yearly returns to
+// tell how often the money lasts.
+func (s *PlanningService) ProjectRetirement(ctx context.Context, userId int64, input RetirementInput) (*RetirementProjection, error) {
+	if err := validateRetirement(input); err != nil {
+		return nil, err
+	}
+
+	startingBalance := decimal.Zero
+	for _, id := range input.AccountIds {
+		if _, err := s.accountRepo.GetById(ctx, id, userId); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, ErrRetirementAccountNotFound
+			}
+			return nil, err
+		}
+		balance, err := s.accountRepo.GetCurrentBalance(ctx, id, userId)
+		if err != nil {
+			return nil, err
+		}
+		startingBalance = startingBalance.Add(balance)
+	}
+
+	projection := &RetirementProjection{StartingBalance: startingBalance, Simulations: input.Simulations, Seed: defaultRetirementSeed}
+	if input.Seed != nil {
+		projection.Seed = *input.Seed
+	}
+	if input.MonthlyContribution == nil || input.MonthlyExpenses == nil {
+		savings, expenses, err := s.monthlyHistory(ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+		projection.MonthlyContribution, projection.MonthlyExpenses = savings, expenses
+	}
+	if input.MonthlyContribution != nil {
+		projection.MonthlyContribution = *input.MonthlyContribution
+	}
+	if input.MonthlyExpenses != nil {
+		projection.MonthlyExpenses = *input.MonthlyExpenses
+	}
+
+	// The projection is in today's money: returns are discounted by inflation.
+	realReturn := (1+input.AnnualReturn.InexactFloat64()/100)/(1+input.Inflation.InexactFloat64()/100) - 1
+	inflation := input.Inflation.InexactFloat64() / 100
+	monthlyReturn := math.Pow(1+realReturn, 1.0/12) - 1
+	contribution := projection.MonthlyContribution.InexactFloat64()
+	annualExpenses := projection.MonthlyExpenses.InexactFloat64() * 12
+	target := annualExpenses / (input.WithdrawalRate.InexactFloat64() / 100)
+	projection.RealReturn = decimal.NewFromFloat(realReturn * 100).Round(2)
+	projection.Target = decimal.NewFromFloat(target).Round(2)
+
+	now := s.now()
+	balance := startingBalance.InexactFloat64()
+	independentAfter := -1
+	if balance >= target {
+		independentAfter = 0
+	}
+	for year := 1; year <= input.Years; year++ {
+		start, contributed := balance, 0.0
+		for month := 1; month <= 12; month++ {
+			balance = balance*(1+monthlyReturn) + contribution
+			contributed += contribution
+			if independentAfter < 0 && balance >= target {
+				independentAfter = (year-1)*12 + month
+			}
+		}
+		projection.Years = append(projection.Years, RetirementYear{
+			Year:           now.Year() + year,
+			Contributions:  decimal.NewFromFloat(contributed).Round(2),
+			Growth:         decimal.NewFromFloat(balance - start - contributed).Round(2),
+			Balance:        decimal.NewFromFloat(balance).Round(2),
+			NominalBalance: decimal.NewFromFloat(balance * math.Pow(1+inflation, float64(year))).Round(2),
+			Independent:    independentAfter >= 0,
+		})
+	}
+
+	retireAfterYears := input.Years
+	if independentAfter >= 0 {
+		date := truncateToDay(now).AddDate(0, independentAfter, 0)
+		projection.IndependenceDate = &date
+		retireAfterYears = (independentAfter + 11) / 12
+	}
+	projection.SuccessProbability = decimal.NewFromFloat(simulateRetirement(retirementSimulation{
+		balance:         startingBalance.InexactFloat64(),
+		contribution:    contribution * 12,
+		annualExpenses:  annualExpenses,
+		meanReturn:      realReturn,
+		volatility:      input.Volatility.InexactFloat64() / 100,
+		accumulateYears: retireAfterYears,
+		retirementYears: input.RetirementYears,
+		simulations:     input.Simulations,
+		seed:            projection.Seed,
+	}) * 100).Round(2)
+	return projection, nil
+}
+
+// monthlyHistory returns the average monthly savings (incomes minus expenses)
+// and expenses of the last twelve full months.
+func (s *PlanningService) monthlyHistory(ctx context.Context, userId int64) (decimal.Decimal, decimal.Decimal, error) {
+	now := s.now()
+	end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	start := end.AddDate(0, -retirementHistoryMonths, 0)
+	end = end.Add(-time.Second)
+	transactions, err := s.transactionRepo.List(ctx, userId, repository.ListTransactionFilters{StartDate: &start, EndDate: &end})
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+
+	income, expense := decimal.Zero, decimal.Zero
+	for _, tx := range transactions {
+		switch tx.Type {
+		case model.Income:
+			income = income.Add(tx.Amount)
+		case model.Expense:
+			expense = expense.Add(tx.Amount)
+		}
+	}
+	months := decimal.NewFromInt(retirementHistoryMonths)
+	return income.Sub(expense).Div(months).Round(2), expense.Div(months).Round(2), nil
+}
+
+// retirementSimulation holds the yearly amounts of a Monte Carlo simulation, in
+// today's money.
+type retirementSimulation struct {
+	balance         float64
+	contribution    float64
+	annualExpenses  float64
+	meanReturn      float64
+	volatility      float64
+	accumulateYears int
+	retirementYears int
+	simulations     int
+	seed            uint64
+}
+
+// simulateRetirement draws a normally distributed real return for every year
+// of every run and returns the share of runs whose money lasts through all the
+// retirement years. The same seed always gives the same result.
+func simulateRetirement(sim retirementSimulation) float64 {
+	rng := rand.New(rand.NewPCG(sim.seed, sim.seed))
+	succeeded := 0
+	for run := 0; run < sim.simulations; run++ {
+		balance := sim.balance
+		for year := 0; year < sim.accumulateYears; year++ {
+			balance = balance*(1+sim.meanReturn+sim.volatility*rng.NormFloat64()) + sim.contribution
+		}
+		lasted := true
+		for year := 0; year < sim.retirementYears; year++ {
+			balance = balance*(1+sim.meanReturn+sim.volatility*rng.NormFloat64()) - sim.annualExpenses
+			if balance < 0 {
+				lasted = false
+				break
+			}
+		}
+		if lasted {
+			succeeded++
+		}
+	}
+	return float64(succeeded) / float64(sim.simulations)
+}
+
+func validateRetirement(input RetirementInput) error {
+	if len(input.AccountIds) == 0 || input.Years < 1 || input.Years > 80 || input.RetirementYears < 1 || input.RetirementYears > 80 {
+		return ErrInvalidRetirementPlan
+	}
+	if input.Simulations < 1 || input.Simulations > 10000 {
+		return ErrInvalidRetirementPlan
+	}
+	if input.AnnualReturn.LessThanOrEqual(decimal.NewFromInt(-100)) || input.Inflation.LessThanOrEqual(decimal.NewFromInt(-100)) {
+		return ErrInvalidRetirementPlan
+	}
+	if !input.WithdrawalRate.IsPositive() || input.Volatility.IsNegative() {
+		return ErrInvalidRetirementPlan
+	}
+	if input.MonthlyExpenses != nil && !input.MonthlyExpenses.IsPositive() {
+		return ErrInvalidRetirementPlan
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/testhelper"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPlanningService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	userId := int64(1)
+	now := time.Date(2025, 5, 15, 9, 0, 0, 0, time.UTC)
+	salaryId, rentId, gymId := int64(1), int64(2), int64(3)
+
+	setup := func() (*PlanningService, *MockAccountRepository, *MockTransactionRepository) {
+		mockAccountRepo := new(MockAccountRepository)
+		mockTxRepo := new(MockTransactionRepository)
+		accountService := NewAccountService(mockAccountRepo, mockTxRepo, unlimitedQuotas())
+		netWorthService := NewNetWorthService(accountService, NewPointsService(new(MockPointsRepository), mockAccountRepo, accountService), NewAssetService(new(MockAssetRepository), mockAccountRepo))
+		planningService := NewPlanningService(mockTxRepo, mockAccountRepo, netWorthService)
+		planningService.now = func() time.Time { return now }
+		return planningService, mockAccountRepo, mockTxRepo
+	}
+
+	history := func() []model.Transaction {
+		var transactions []model.Transaction
+		for month := time.February; month <= time.April; month++ {
+			date := time.Date(2025, month, 5, 0, 0, 0, 0, time.UTC)
+			transactions = append(transactions,
+				model.Transaction{Type: model.Income, Amount: decimal.NewFromInt(6000), Date: date, CategoryId: &salaryId},
+				model.Transaction{Type: model.Expense, Amount: decimal.NewFromInt(2000), Date: date, CategoryId: &rentId},
+				model.Transaction{Type: model.Expense, Amount: decimal.NewFromInt(100), Date: date, CategoryId: &gymId},
+				model.Transaction{Type: model.Transfer, Amount: decimal.NewFromInt(500), Date: date},
+			)
+		}
+		return transactions
+	}
+
+	t.Run("SimulateScenario", func(t *testing.T) {
+		t.Run("should compare the scenario with the baseline month by month", func(t *testing.T) {
+			// Arrange
+			planningService, mockAccountRepo, mockTxRepo := setup()
+			mockAccountRepo.On("ListByUserId", ctx, userId).Return([]model.Account{{Id: 1, Type: model.Checking}}, nil).Once()
+			mockAccountRepo.On("GetCurrentBalance", ctx, int64(1), userId).Return(decimal.NewFromInt(10000), nil).Once()
+			mockTxRepo.On("List", ctx, userId, mock.MatchedBy(func(f repository.ListTransactionFilters) bool {
+				return f.StartDate.Equal(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)) && f.EndDate.Before(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC))
+			})).Return(history(), nil).Once()
+
+			// Act
+			result, err := planningService.SimulateScenario(ctx, userId, ScenarioInput{
+				Months:            3,
+				CategoryChanges:   []CategoryChange{{CategoryId: rentId, Percent: decimal.NewFromInt(10)}},
+				RemoveCategoryIds: []int64{gymId},
+				Purchases:         []ScenarioPurchase{{Description: "Vacation", Amount: decimal.NewFromInt(5000), Month: 3}},
+				Loans:             []ScenarioLoan{{Description: "Car", Principal: decimal.NewFromInt(12000), Installments: 12, StartMonth: 2}},
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.True(t, result.BaselineMonthly.Equal(decimal.NewFromInt(3900)), result.BaselineMonthly.String())
+			assert.Len(t, result.BaselineItems, 3)
+			assert.Len(t, result.Months, 3)
+			assert.Equal(t, 2025, result.Months[0].Year)
+			assert.Equal(t, 6, result.Months[0].Month)
+			assert.True(t, result.Months[0].BaselineBalance.Equal(decimal.NewFromInt(13900)))
+			assert.True(t, result.Months[0].ScenarioBalance.Equal(decimal.NewFromInt(13800)))
+			assert.True(t, result.Months[1].ScenarioBalance.Equal(decimal.NewFromInt(16600)))
+			assert.True(t, result.Months[1].LoanBalance.Equal(decimal.NewFromInt(11000)))
+			// The car counts as an asset against the remaining debt.
+			assert.True(t, result.Months[1].ScenarioNetWorth.Equal(decimal.NewFromInt(17600)), result.Months[1].ScenarioNetWorth.String())
+			assert.True(t, result.Months[2].ScenarioBalance.Equal(decimal.NewFromInt(14400)))
+			assert.Nil(t, result.FirstNegativeMonth)
+		})
+
+		t.Run("should reject purchases outside the projection", func(t *testing.T) {
+			// Arrange
+			planningService, _, _ := setup()
+
+			// Act
+			_, err := planningService.SimulateScenario(ctx, userId, ScenarioInput{
+				Months:    6,
+				Purchases: []ScenarioPurchase{{Amount: decimal.NewFromInt(100), Month: 7}},
+			})
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidScenario)
+		})
+	})
+
+	t.Run("ProjectRetirement", func(t *testing.T) {
+		plan := func() RetirementInput {
+			return RetirementInput{
+				AccountIds:          []int64{7},
+				WithdrawalRate:      decimal.NewFromInt(4),
+				MonthlyContribution: testhelper.Ptr(decimal.NewFromInt(5000)),
+				MonthlyExpenses:     testhelper.Ptr(decimal.NewFromInt(4000)),
+				Years:               20,
+				RetirementYears:     25,
+				Simulations:         500,
+			}
+		}
+		withSavings := func(mockAccountRepo *MockAccountRepository) {
+			mockAccountRepo.On("GetById", ctx, int64(7), userId).Return(&model.Account{Id: 7, Type: model.Savings}, nil)
+			mockAccountRepo.On("GetCurrentBalance", ctx, int64(7), userId).Return(decimal.NewFromInt(100000), nil)
+		}
+
+		t.Run("should estimate the independence date and the success probability", func(t *testing.T) {
+			// Arrange
+			planningService, mockAccountRepo, mockTxRepo := setup()
+			withSavings(mockAccountRepo)
+
+			// Act
+			projection, err := planningService.ProjectRetirement(ctx, userId, plan())
+
+			// Assert
+			assert.NoError(t, err)
+			assert.True(t, projection.Target.Equal(decimal.NewFromInt(1200000)), projection.Target.String())
+			assert.Len(t, projection.Years, 20)
+			assert.True(t, projection.Years[0].Balance.Equal(decimal.NewFromInt(160000)), projection.Years[0].Balance.String())
+			assert.False(t, projection.Years[17].Independent)
+			assert.True(t, projection.Years[18].Independent)
+			// 220 months of contributions reach the 1,200,000 target.
+			assert.Equal(t, time.Date(2043, 9, 15, 0, 0, 0, 0, time.UTC), *projection.IndependenceDate)
+			// Without volatility every run is the projection, which lasts 25 years.
+			assert.True(t, projection.SuccessProbability.Equal(decimal.NewFromInt(100)), projection.SuccessProbability.String())
+			mockTxRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		t.Run("should give the same probability for the same seed", func(t *testing.T) {
+			// Arrange
+			planningService, mockAccountRepo, _ := setup()
+			withSavings(mockAccountRepo)
+			input := plan()
+			input.AnnualReturn = decimal.NewFromInt(8)
+			input.Inflation = decimal.NewFromInt(4)
+			input.Volatility = decimal.NewFromInt(15)
+			input.Seed = testhelper.Ptr(uint64(42))
+
+			// Act
+			first, err := planningService.ProjectRetirement(ctx, userId, input)
+			assert.NoError(t, err)
+			second, err := planningService.ProjectRetirement(ctx, userId, input)
+			assert.NoError(t, err)
+
+			// Assert
+			assert.True(t, first.SuccessProbability.Equal(second.SuccessProbability))
+			assert.True(t, first.SuccessProbability.GreaterThan(decimal.Zero))
+			assert.True(t, first.SuccessProbability.LessThan(decimal.NewFromInt(100)))
+			assert.Equal(t, uint64(42), first.Seed)
+		})
+
+		t.Run("should default the savings to the last twelve months", func(t *testing.T) {
+			// Arrange
+			planningService, mockAccountRepo, mockTxRepo := setup()
+			withSavings(mockAccountRepo)
+			mockTxRepo.On("List", ctx, userId, mock.Anything).Return(history(), nil).Once()
+			input := plan()
+			input.MonthlyContribution = nil
+			input.MonthlyExpenses = nil
+
+			// Act
+			projection, err := planningService.ProjectRetirement(ctx, userId, input)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.True(t, projection.MonthlyContribution.Equal(decimal.NewFromInt(975)), projection.MonthlyContribution.String())
+			assert.True(t, projection.MonthlyExpenses.Equal(decimal.NewFromInt(525)), projection.MonthlyExpenses.String())
+		})
+
+		t.Run("should reject accounts of other users", func(t *testing.T) {
+			// Arrange
+			planningService, mockAccountRepo, _ := setup()
+			mockAccountRepo.On("GetById", ctx, int64(7), userId).Return(nil, sql.ErrNoRows).Once()
+
+			// Act
+			_, err := planningService.ProjectRetirement(ctx, userId, plan())
+
+			// Assert
+			assert.ErrorIs(t, err, ErrRetirementAccountNotFound)
+		})
+	})
+
+	t.Run("loanSchedule", func(t *testing.T) {
+		t.Run("should split the loan in equal installments that repay the principal", func(t *testing.T) {
+			// Act
+			schedule := loanSchedule(ScenarioLoan{Principal: decimal.NewFromInt(10000), MonthlyInterestRate: decimal.NewFromInt(1), Installments: 12})
+
+			// Assert
+			assert.Len(t, schedule, 12)
+			assert.True(t, schedule[0].Payment.Equal(decimal.RequireFromString("888.49")), schedule[0].Payment.String())
+			repaid := decimal.Zero
+			for _, installment := range schedule {
+				repaid = repaid.Add(installment.Amortization)
+			}
+			assert.True(t, repaid.Equal(decimal.NewFromInt(10000)), repaid.String())
+		})
+	})
+}
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrPointsAccountNotFound    = errors.New("points account not found")
+	ErrPointsRuleNotFound       = errors.New("points earn rule not found")
+	ErrInvalidPointsRule        = errors.New("earn rules link a credit card to a points account and need a positive rate")
+	ErrInvalidPointsAmount      = errors.New("points must be a positive amount")
+	ErrInvalidPointsTransfer    = errors.New("transfers need two different points accounts and a non-negative bonus")
+	ErrInsufficientPoints       = repository.ErrInsufficientPoints
+	ErrPointsAccountTransaction = errors.New("points accounts do not accept transactions, use the points endpoints instead")
+)
+
+// pointsExpiringWindow is how far ahead the summary looks for expiring points.
+const pointsExpiringWindow = 90 * 24 * time.Hour
+
+// PointsSummary is the current state of a points account.
+type PointsSummary struct {
+	Account        model.Account
+	Balance        int64
+	EstimatedValue decimal.Decimal
+	// ExpiringSoon is how many of the valid points expire within pointsExpiringWindow.
+	ExpiringSoon int64
+	Lots         []model.PointsLot
+}
+
+// AddPointsInput credits points that did not come from a card statement, such
+// as an opening balance or a promotion.
+type AddPointsInput struct {
+	Points      int64
+	EarnedAt    time.Time
+	ExpiresAt   *time.Time
+	Description string
+}
+
+// TransferPointsInput moves points between programs. The destination receives
+// Points plus BonusPercent of it, rounded down.
+type TransferPointsInput struct {
+	FromAccountId int64
+	ToAccountId   int64
+	Points        int64
+	BonusPercent  decimal.Decimal
+	ExpiresAt     *time.Time
+}
+
+// PointsService manages loyalty points and airline miles accounts: earn rules
+// tied to credit cards, lots with expiration dates and transfers between programs.
+type PointsService struct {
+	repo           repository.PointsRepository
+	accountRepo    repository.AccountRepository
+	accountService *AccountService
+	now            func() time.Time
+}
+
+// NewPointsService creates a new instance of PointsService.
+func NewPointsService(repo repository.PointsRepository, accountRepo repository.AccountRepository, accountService *AccountService) *PointsService {
+	return &PointsService{
+		repo:           repo,
+		accountRepo:    accountRepo,
+		accountService: accountService,
+		now:            time.Now,
+	}
+}
+
+// CreateRule links a credit card to a points account.
+func (s *PointsService) CreateRule(ctx context.Context, rule model.PointsEarnRule) (*model.PointsEarnRule, error) {
+	if !rule.PointsPerUnit.IsPositive() || (rule.ExpirationMonths != nil && *rule.ExpirationMonths <= 0) {
+		return nil, ErrInvalidPointsRule
+	}
+	card, err := s.accountRepo.GetById(ctx, rule.CardAccountId, rule.UserId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidPointsRule
+		}
+		return nil, err
+	}
+	if card.Type != model.CreditCard {
+		return nil, ErrInvalidPointsRule
+	}
+	if _, err := s.getPointsAccount(ctx, rule.UserId, rule.PointsAccountId); err != nil {
+		if errors.Is(err, ErrPointsAccountNotFound) {
+			return nil, ErrInvalidPointsRule
+		}
+		return nil, err
+	}
+
+	rule.Id, err = s.repo.CreateRule(ctx, rule)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// ListRules returns the user's earn rules.
+func (s *PointsService) ListRules(ctx context.Context, userId int64) ([]model.PointsEarnRule, error) {
+	return s.repo.ListRules(ctx, userId)
+}
+
+// DeleteRule removes an earn rule. Points already credited stay in their lots.
+func (s *PointsService) DeleteRule(ctx context.Context, id, userId int64) error {
+	if err := s.repo.DeleteRule(ctx, id, userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrPointsRuleNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// AccrueStatement credits the points a rule earned in the card statement due in
+// the given month. Running it again for the same statement updates the lot.
+func (s *PointsService) AccrueStatement(ctx context.Context, userId, ruleId int64, year, month int) (*model.PointsLot, error) {
+	rule, err := s.repo.GetRule(ctx, ruleId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPointsRuleNotFound
+		}
+		return nil, err
+	}
+	return s.accrue(ctx, *rule, year, month)
+}
+
+// AccrueClosedStatements credits the latest closed statement of every rule. It
+// is run periodically; accruing a statement twice is harmless.
+func (s *PointsService) AccrueClosedStatements(ctx context.Context) (int, error) {
+	logger := zerolog.Ctx(ctx)
+	now := s.now()
+
+	rules, err := s.repo.ListAllRules(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	accrued := 0
+	for _, rule := range rules {
+		card, err := s.accountRepo.GetById(ctx, rule.CardAccountId, rule.UserId)
+		if err != nil {
+			logger.Warn().Err(err).Int64("ruleId", rule.Id).Msg("skipping points rule without a card")
+			continue
+		}
+		if card.StatementClosingDay == nil || card.PaymentDueDay == nil {
+			continue
+		}
+		year, month, err := s.accountService.lastClosedStatement(ctx, card, now)
+		if err != nil {
+			logger.Error().Err(err).Int64("ruleId", rule.Id).Msg("failed to accrue statement points")
+			continue
+		}
+		if _, err := s.accrue(ctx, rule, year, month); err != nil {
+			logger.Error().Err(err).Int64("ruleId", rule.Id).Msg("failed to accrue statement points")
+			continue
+		}
+		accrued++
+	}
+	return accrued, nil
+}
+
+func (s *PointsService) accrue(ctx context.Context, rule model.PointsEarnRule, year, month int) (*model.PointsLot, error) {
+	statement, err := s.accountService.GetStatementDetails(ctx, rule.UserId, rule.CardAccountId, year, month)
+	if err != nil {
+		return nil, err
+	}
+
+	earned := pointsEarningBase(statement).Mul(rule.PointsPerUnit).Floor().IntPart()
+	lot := model.PointsLot{
+		UserId:         rule.UserId,
+		AccountId:      rule.PointsAccountId,
+		Source:         model.PointsSourceStatement,
+		Points:         earned,
+		Remaining:      earned,
+		EarnedAt:       statement.StatementPeriod.End,
+		RuleId:         &rule.Id,
+		StatementYear:  &year,
+		StatementMonth: &month,
+		Description:    fmt.Sprintf("%s statement %04d-%02d", statement.AccountName, year, month),
+	}
+	if rule.ExpirationMonths != nil {
+		expiresAt := lot.EarnedAt.AddDate(0, *rule.ExpirationMonths, 0)
+		lot.ExpiresAt = &expiresAt
+	}
+	return s.repo.UpsertStatementLot(ctx, lot)
+}
+
+// pointsEarningBase is what a statement earns points on: its total without the
+// fees charged on its purchases, such as the IOF of purchases abroad.
+func pointsEarningBase(statement *StatementDetails) decimal.Decimal {
+	base := statement.StatementTotal
+	for _, tx := range statement.Transactions {
+		if tx.Type == model.Expense && tx.FeeOfTransactionId != nil {
+			base = base.Sub(tx.Amount)
+		}
+	}
+	return base
+}
+
+// AddPoints credits a manual lot to a points account.
+func (s *PointsService) AddPoints(ctx context.Context, userId, accountId int64, input AddPointsInput) (*model.PointsLot, error) {
+	if input.Points <= 0 {
+		return nil, ErrInvalidPointsAmount
+	}
+	if _, err := s.getPointsAccount(ctx, userId, accountId); err != nil {
+		return nil, err
+	}
+
+	lot := model.PointsLot{
+		UserId:      userId,
+		AccountId:   accountId,
+		Source:      model.PointsSourceManual,
+		Points:      input.Points,
+		Remaining:   input.Points,
+		EarnedAt:    input.EarnedAt,
+		ExpiresAt:   input.ExpiresAt,
+		Description: input.Description,
+	}
+	if lot.EarnedAt.IsZero() {
+		lot.EarnedAt = s.now()
+	}
+	id, err := s.repo.CreateLot(ctx, lot)
+	if err != nil {
+		return nil, err
+	}
+	lot.Id = id
+	return &lot, nil
+}
+
+// GetSummary returns the balance, estimated value and lots of a points account.
+func (s *PointsService) GetSummary(ctx context.Context, userId, accountId int64) (*PointsSummary, error) {
+	account, err := s.getPointsAccount(ctx, userId, accountId)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+
+	lots, err := s.repo.ListLots(ctx, userId, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &PointsSummary{Account: *account, Lots: lots}
+	for _, lot := range lots {
+		if lot.Expired(now) {
+			continue
+		}
+		summary.Balance += lot.Remaining
+		if lot.ExpiresAt != nil && lot.ExpiresAt.Before(now.Add(pointsExpiringWindow)) {
+			summary.ExpiringSoon += lot.Remaining
+		}
+	}
+	summary.EstimatedValue = EstimatePointsValue(*account, summary.Balance)
+	return summary, nil
+}
+
+// GetBalance returns how many valid points the account holds right now.
+func (s *PointsService) GetBalance(ctx context.Context, userId, accountId int64) (int64, error) {
+	return s.repo.GetBalance(ctx, userId, accountId, s.now())
+}
+
+// Transfer moves points between two programs, applying the bonus.
+func (s *PointsService) Transfer(ctx context.Context, userId int64, input TransferPointsInput) (*model.PointsTransfer, error) {
+	if input.Points <= 0 {
+		return nil, ErrInvalidPointsAmount
+	}
+	if input.FromAccountId == input.ToAccountId || input.BonusPercent.IsNegative() {
+		return nil, ErrInvalidPointsTransfer
+	}
+	from, err := s.getPointsAccount(ctx, userId, input.FromAccountId)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.getPointsAccount(ctx, userId, input.ToAccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	multiplier := decimal.NewFromInt(1).Add(input.BonusPercent.Div(decimal.NewFromInt(100)))
+	transfer := model.PointsTransfer{
+		UserId:         userId,
+		FromAccountId:  from.Id,
+		ToAccountId:    to.Id,
+		Points:         input.Points,
+		BonusPercent:   input.BonusPercent,
+		CreditedPoints: decimal.NewFromInt(input.Points).Mul(multiplier).Floor().IntPart(),
+		CreatedAt:      now,
+	}
+	credited := model.PointsLot{
+		UserId:      userId,
+		AccountId:   to.Id,
+		Source:      model.PointsSourceTransfer,
+		Points:      transfer.CreditedPoints,
+		Remaining:   transfer.CreditedPoints,
+		EarnedAt:    now,
+		ExpiresAt:   input.ExpiresAt,
+		Description: fmt.Sprintf("Transfer from %s", from.Name),
+	}
+
+	transfer.Id, err = s.repo.Transfer(ctx, transfer, credited, now)
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// ListTransfers returns the user's transfers, newest first.
+func (s *PointsService) ListTransfers(ctx context.Context, userId int64) ([]model.PointsTransfer, error) {
+	return s.repo.ListTransfers(ctx, userId)
+}
+
+func (s *PointsService) getPointsAccount(ctx context.Context, userId, accountId int64) (*model.Account, error) {
+	account, err := s.accountRepo.GetById(ctx, accountId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPointsAccountNotFound
+		}
+		return nil, err
+	}
+	if account.Type != model.Points {
+		return nil, ErrPointsAccountNotFound
+	}
+	return account, nil
+}
+
+// EstimatePointsValue converts points to money using the account's value per
+// 1,000 points. Accounts without a value are worth zero.
+func EstimatePointsValue(account model.Account, points int64) decimal.Decimal {
+	if account.PointsValuePerThousand == nil {
+		return decimal.Zero
+	}
+	return decimal.NewFromInt(points).Mul(*account.PointsValuePerThousand).Div(decimal.NewFromInt(1000)).Round(2)
+}
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/testhelper"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPointsRepository is a mock implementation of the PointsRepository interface.
+type MockPointsRepository struct {
+	mock.Mock
+}
+
+func (m *MockPointsRepository) CreateRule(ctx context.Context, rule model.PointsEarnRule) (int64, error) {
+	args := m.Called(ctx, rule)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPointsRepository) GetRule(ctx context.Context, id, userId int64) (*model.PointsEarnRule, error) {
+	args := m.Called(ctx, id, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PointsEarnRule), args.Error(1)
+}
+
+func (m *MockPointsRepository) ListRules(ctx context.Context, userId int64) ([]model.PointsEarnRule, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PointsEarnRule), args.Error(1)
+}
+
+func (m *MockPointsRepository) ListAllRules(ctx context.Context) ([]model.PointsEarnRule, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PointsEarnRule), args.Error(1)
+}
+
+func (m *MockPointsRepository) DeleteRule(ctx context.Context, id, userId int64) error {
+	args := m.Called(ctx, id, userId)
+	return args.Error(0)
+}
+
+func (m *MockPointsRepository) CreateLot(ctx context.Context, lot model.PointsLot) (int64, error) {
+	args := m.Called(ctx, lot)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPointsRepository) UpsertStatementLot(ctx context.Context, lot model.PointsLot) (*model.PointsLot, error) {
+	args := m.Called(ctx, lot)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PointsLot), args.Error(1)
+}
+
+func (m *MockPointsRepository) ListLots(ctx context.Context, userId, accountId int64) ([]model.PointsLot, error) {
+	args := m.Called(ctx, userId, accountId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PointsLot), args.Error(1)
+}
+
+func (m *MockPointsRepository) GetBalance(ctx context.Context, userId, accountId int64, asOf time.Time) (int64, error) {
+	args := m.Called(ctx, userId, accountId, asOf)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPointsRepository) Transfer(ctx context.Context, transfer model.PointsTransfer, creditedLot model.PointsLot, asOf time.Time) (int64, error) {
+	args := m.Called(ctx, transfer, creditedLot, asOf)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPointsRepository) ListTransfers(ctx context.Context, userId int64) ([]model.PointsTransfer, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PointsTransfer), args.Error(1)
+}
+
+func TestPointsService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	userId := int64(1)
+	now := time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC)
+	card := &model.Account{Id: 10, UserId: userId, Name: "Nubank", Type: model.CreditCard, StatementClosingDay: testhelper.Ptr(20), PaymentDueDay: testhelper.Ptr(28)}
+	livelo := &model.Account{Id: 20, Name: "Livelo", Type: model.Points, PointsValuePerThousand: testhelper.Ptr(decimal.NewFromInt(35))}
+	smiles := &model.Account{Id: 30, Name: "Smiles", Type: model.Points}
+
+	setup := func() (*PointsService, *MockPointsRepository, *MockAccountRepository, *MockTransactionRepository) {
+		mockRepo := new(MockPointsRepository)
+		mockAccountRepo := new(MockAccountRepository)
+		mockTxRepo := new(MockTransactionRepository)
+		accountService := NewAccountService(mockAccountRepo, mockTxRepo, unlimitedQuotas())
+		pointsService := NewPointsService(mockRepo, mockAccountRepo, accountService)
+		pointsService.now = func() time.Time { return now }
+		return pointsService, mockRepo, mockAccountRepo, mockTxRepo
+	}
+
+	t.Run("CreateRule", func(t *testing.T) {
+		t.Run("should only accept a credit card as the earning account", func(t *testing.T) {
+			// Arrange
+			pointsService, mockRepo, mockAccountRepo, _ := setup()
+			mockAccountRepo.On("GetById", ctx, int64(99), userId).Return(&model.Account{Id: 99, Type: model.Checking}, nil).Once()
+
+			// Act
+			_, err := pointsService.CreateRule(ctx, model.PointsEarnRule{UserId: userId, CardAccountId: 99, PointsAccountId: livelo.Id, PointsPerUnit: decimal.NewFromInt(2)})
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidPointsRule)
+			mockRepo.AssertNotCalled(t, "CreateRule", mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("AccrueStatement", func(t *testing.T) {
+		t.Run("should credit points for the statement expenses with an expiration", func(t *testing.T) {
+			// Arrange
+			pointsService, mockRepo, mockAccountRepo, mockTxRepo := setup()
+			rule := &model.PointsEarnRule{Id: 5, UserId: userId, CardAccountId: card.Id, PointsAccountId: livelo.Id, PointsPerUnit: decimal.RequireFromString("2.5"), ExpirationMonths: testhelper.Ptr(24)}
+			mockRepo.On("GetRule", ctx, rule.Id, userId).Return(rule, nil).Once()
+			mockAccountRepo.On("GetById", ctx, card.Id, userId).Return(card, nil).Once()
+			mockAccountRepo.On("ListBillingCycles", ctx, card.Id, userId).Return(nil, nil).Once()
+			mockTxRepo.On("ListByAccountAndDateRange", ctx, userId, card.Id, mock.Anything, mock.Anything).Return([]model.Transaction{
+				{Type: model.Expense, Amount: decimal.RequireFromString("400.90")},
+				{Type: model.Expense, Amount: decimal.NewFromInt(100)},
+				{Type: model.Income, Amount: decimal.NewFromInt(50)},
+			}, nil).Once()
+			var stored model.PointsLot
+			mockRepo.On("UpsertStatementLot", ctx, mock.AnythingOfType("model.PointsLot")).
+				Run(func(args mock.Arguments) { stored = args.Get(1).(model.PointsLot) }).
+				Return(&model.PointsLot{Id: 1}, nil).Once()
+
+			// Act
+			_, err := pointsService.AccrueStatement(ctx, userId, rule.Id, 2025, 7)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, int64(1252), stored.Points) // 500.90 * 2.5 = 1252.25, rounded down
+			assert.Equal(t, model.PointsSourceStatement, stored.Source)
+			assert.Equal(t, time.Date(2025, 7, 20, 0, 0, 0, 0, time.UTC), stored.EarnedAt)
+			assert.Equal(t, time.Date(2027, 7, 20, 0, 0, 0, 0, time.UTC), *stored.ExpiresAt)
+			assert.Equal(t, 7, *stored.StatementMonth)
+		})
+
+		t.Run("should not credit points for the IOF of purchases abroad", func(t *testing.T) {
+			// Arrange
+			pointsService, mockRepo, mockAccountRepo, mockTxRepo := setup()
+			rule := &model.PointsEarnRule{Id: 5, UserId: userId, CardAccountId: card.Id, PointsAccountId: livelo.Id, PointsPerUnit: decimal.NewFromInt(2)}
+			mockRepo.On("GetRule", ctx, rule.Id, userId).Return(rule, nil).Once()
+			mockAccountRepo.On("GetById", ctx, card.Id, userId).Return(card, nil).Once()
+			mockAccountRepo.On("ListBillingCycles", ctx, card.Id, userId).Return(nil, nil).Once()
+			mockTxRepo.On("ListByAccountAndDateRange", ctx, userId, card.Id, mock.Anything, mock.Anything).Return([]model.Transaction{
+				{Id: 1, Type: model.Expense, Amount: decimal.NewFromInt(520), OriginalCurrency: testhelper.Ptr("USD")},
+				{Id: 2, Type: model.Expense, Amount: decimal.RequireFromString("18.20"), FeeOfTransactionId: testhelper.Ptr(int64(1))},
+			}, nil).Once()
+			var stored model.PointsLot
+			mockRepo.On("UpsertStatementLot", ctx, mock.AnythingOfType("model.PointsLot")).
+				Run(func(args mock.Arguments) { stored = args.Get(1).(model.PointsLot) }).
+				Return(&model.PointsLot{Id: 1}, nil).Once()
+
+			// Act
+			_, err := pointsService.AccrueStatement(ctx, userId, rule.Id, 2025, 7)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, int64(1040), stored.Points) // only the 520.00 purchase earns
+		})
+	})
+
+	t.Run("AccrueClosedStatements", func(t *testing.T) {
+		t.Run("should use the previous statement before this month's closing day", func(t *testing.T) {
+			// Arrange
+			pointsService, mockRepo, mockAccountRepo, mockTxRepo := setup()
+			rule := model.PointsEarnRule{Id: 5, UserId: userId, CardAccountId: card.Id, PointsAccountId: livelo.Id, PointsPerUnit: decimal.NewFromInt(1)}
+			mockRepo.On("ListAllRules", ctx).Return([]model.PointsEarnRule{rule}, nil).Once()
+			mockAccountRepo.On("GetById", ctx, card.Id, userId).Return(card, nil)
+			mockAccountRepo.On("ListBillingCycles", ctx, card.Id, userId).Return(nil, nil)
+			mockTxRepo.On("ListByAccountAndDateRange", ctx, userId, card.Id, mock.Anything, mock.Anything).Return([]model.Transaction{}, nil).Once()
+			var stored model.PointsLot
+			mockRepo.On("UpsertStatementLot", ctx, mock.AnythingOfType("model.PointsLot")).
+				Run(func(args mock.Arguments) { stored = args.Get(1).(model.PointsLot) }).
+				Return(&model.PointsLot{Id: 1}, nil).Once()
+
+			// Act
+			accrued, err := pointsService.AccrueClosedStatements(ctx)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 1, accrued)
+			assert.Equal(t, 2025, *stored.StatementYear)
+			assert.Equal(t, 6, *stored.StatementMonth) // July's statement only closes on the 20th
+		})
+
+		t.Run("should use the billing cycle in force for the statement", func(t *testing.T) {
+			// Arrange: the card closes on the 10th from August on, but July still closes on the 20th.
+			pointsService, mockRepo, mockAccountRepo, mockTxRepo := setup()
+			rescheduled := &model.Account{Id: card.Id, UserId: userId, Name: card.Name, Type: model.CreditCard, StatementClosingDay: testhelper.Ptr(10), PaymentDueDay: testhelper.Ptr(18)}
+			rule := model.PointsEarnRule{Id: 5, UserId: userId, CardAccountId: card.Id, PointsAccountId: livelo.Id, PointsPerUnit: decimal.NewFromInt(1)}
+			mockRepo.On("ListAllRules", ctx).Return([]model.PointsEarnRule{rule}, nil).Once()
+			mockAccountRepo.On("GetById", ctx, card.Id, userId).Return(rescheduled, nil)
+			mockAccountRepo.On("ListBillingCycles", ctx, card.Id, userId).Return([]model.BillingCycle{
+				{AccountId: card.Id, StatementClosingDay: 20, PaymentDueDay: 28, EffectiveFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+				{AccountId: card.Id, StatementClosingDay: 10, PaymentDueDay: 18, EffectiveFrom: time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)},
+			}, nil)
+			mockTxRepo.On("ListByAccountAndDateRange", ctx, userId, card.Id, mock.Anything, mock.Anything).Return([]model.Transaction{}, nil).Once()
+			var stored model.PointsLot
+			mockRepo.On("UpsertStatementLot", ctx, mock.AnythingOfType("model.PointsLot")).
+				Run(func(args mock.Arguments) { stored = args.Get(1).(model.PointsLot) }).
+				Return(&model.PointsLot{Id: 1}, nil).Once()
+
+			// Act
+			accrued, err := pointsService.AccrueClosedStatements(ctx)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 1, accrued)
+			assert.Equal(t, 6, *stored.StatementMonth)
+			assert.Equal(t, time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC), stored.EarnedAt)
+		})
+	})
+
+	t.Run("GetSummary", func(t *testing.T) {
+		t.Run("should ignore expired lots and estimate the value", func(t *testing.T) {
+			// Arrange
+			pointsService, mockRepo, mockAccountRepo, _ := setup()
+			mockAccountRepo.On("GetById", ctx, livelo.Id, userId).Return(livelo, nil).Once()
+			mockRepo.On("ListLots", ctx, userId, livelo.Id).Return([]model.PointsLot{
+				{Remaining: 1000, ExpiresAt: testhelper.Ptr(time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC))},
+				{Remaining: 2000, ExpiresAt: testhelper.Ptr(time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC))},
+				{Remaining: 8000},
+			}, nil).Once()
+
+			// Act
+			summary, err := pointsService.GetSummary(ctx, userId, livelo.Id)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, int64(10000), summary.Balance)
+			assert.Equal(t, int64(2000), summary.ExpiringSoon)
+			assert.True(t, decimal.NewFromInt(350).Equal(summary.EstimatedValue))
+		})
+
+		t.Run("should not treat other account types as points accounts", func(t *testing.T) {
+			// Arrange
+			pointsService, _, mockAccountRepo, _ := setup()
+			mockAccountRepo.On("GetById", ctx, card.Id, userId).Return(card, nil).Once()
+
+			// Act
+			_, err := pointsService.GetSummary(ctx, userId, card.Id)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrPointsAccountNotFound)
+		})
+	})
+
+	t.Run("Transfer", func(t *testing.T) {
+		t.Run("should apply the bonus to the credited points", func(t *testing.T) {
+			// Arrange
+			pointsService, mockRepo, mockAccountRepo, _ := setup()
+			mockAccountRepo.On("GetById", ctx, livelo.Id, userId).Return(livelo, nil).Once()
+			mockAccountRepo.On("GetById", ctx, smiles.Id, userId).Return(smiles, nil).Once()
+			var credited model.PointsLot
+			mockRepo.On("Transfer", ctx, mock.AnythingOfType("model.PointsTransfer"), mock.AnythingOfType("model.PointsLot"), now).
+				Run(func(args mock.Arguments) { credited = args.Get(2).(model.PointsLot) }).
+				Return(int64(3), nil).Once()
+
+			// Act
+			transfer, err := pointsService.Transfer(ctx, userId, TransferPointsInput{
+				FromAccountId: livelo.Id,
+				ToAccountId:   smiles.Id,
+				Points:        10001,
+				BonusPercent:  decimal.NewFromInt(80),
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, int64(18001), transfer.CreditedPoints) // 10001 * 1.8 = 18001.8
+			assert.Equal(t, smiles.Id, credited.AccountId)
+			assert.Equal(t, model.PointsSourceTransfer, credited.Source)
+		})
+
+		t.Run("should report insufficient points", func(t *testing.T) {
+			// Arrange
+			pointsService, mockRepo, mockAccountRepo, _ := setup()
+			mockAccountRepo.On("GetById", ctx, livelo.Id, userId).Return(livelo, nil).Once()
+			mockAccountRepo.On("GetById", ctx, smiles.Id, userId).Return(smiles, nil).Once()
+			mockRepo.On("Transfer", ctx, mock.Anything, mock.Anything, now).Return(int64(0), ErrInsufficientPoints).Once()
+
+			// Act
+			_, err := pointsService.Transfer(ctx, userId, TransferPointsInput{FromAccountId: livelo.Id, ToAccountId: smiles.Id, Points: 500})
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInsufficientPoints)
+		})
+
+		t.Run("should reject transfers to the same account", func(t *testing.T) {
+			// Arrange
+			pointsService, _, _, _ := setup()
+
+			// Act
+			_, err := pointsService.Transfer(ctx, userId, TransferPointsInput{FromAccountId: livelo.Id, ToAccountId: livelo.Id, Points: 500})
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidPointsTransfer)
+		})
+	})
+}
+
+func TestNetWorthService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	userId := int64(1)
+
+	t.Run("should add balances, subtract card debt and value points and assets", func(t *testing.T) {
+		// Arrange
+		mockAccountRepo := new(MockAccountRepository)
+		mockTxRepo := new(MockTransactionRepository)
+		mockPointsRepo := new(MockPointsRepository)
+		mockAssetRepo := new(MockAssetRepository)
+		accountService := NewAccountService(mockAccountRepo, mockTxRepo, unlimitedQuotas())
+		netWorthService := NewNetWorthService(accountService, NewPointsService(mockPointsRepo, mockAccountRepo, accountService), NewAssetService(mockAssetRepo, mockAccountRepo))
+
+		mockAccountRepo.On("ListByUserId", ctx, userId).Return([]model.Account{
+			{Id: 1, Type: model.Checking},
+			{Id: 2, Type: model.CreditCard},
+			{Id: 3, Type: model.Points, PointsValuePerThousand: testhelper.Ptr(decimal.NewFromInt(20))},
+			{Id: 4, UserId: userId, Type: model.Asset, AssetPurchaseValue: testhelper.Ptr(decimal.NewFromInt(40000))},
+		}, nil).Once()
+		mockAccountRepo.On("GetCurrentBalance", ctx, int64(1), userId).Return(decimal.NewFromInt(1500), nil).Once()
+		mockAccountRepo.On("GetCurrentBalance", ctx, int64(2), userId).Return(decimal.NewFromInt(-300), nil).Once()
+		mockAccountRepo.On("GetCurrentBalance", ctx, int64(3), userId).Return(decimal.Zero, nil).Once()
+		mockPointsRepo.On("GetBalance", ctx, userId, int64(3), mock.Anything).Return(int64(25000), nil).Once()
+		mockAccountRepo.On("GetCurrentBalance", ctx, int64(4), userId).Return(decimal.Zero, nil).Once()
+		mockAssetRepo.On("ListValuations", ctx, userId, int64(4)).Return([]model.AssetValuation{
+			{Value: decimal.NewFromInt(35000), ValuedOn: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		}, nil).Once()
+
+		// Act
+		netWorth, err := netWorthService.GetNetWorth(ctx, userId)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, decimal.NewFromInt(36700).Equal(netWorth.Total), netWorth.Total.String())
+		assert.Equal(t, int64(25000), *netWorth.Items[2].Points)
+	})
+}
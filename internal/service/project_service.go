@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrProjectNotFound            = errors.New("project not found")
+	ErrInvalidProject             = errors.New("projects need a positive budget, an end date after the start date and category budgets that fit in the total")
+	ErrInvalidProjectCategory     = errors.New("project category budgets need expense categories of the user")
+	ErrProjectTransactionNotFound = errors.New("transaction not found")
+)
+
+// ProjectCategoryReport compares what was planned and spent in a category.
+// Planned is nil for categories without a sub-budget.
+type ProjectCategoryReport struct {
+	CategoryId   *int64
+	CategoryName string
+	Planned      *decimal.Decimal
+	Spent        decimal.Decimal
+}
+
+// ProjectMonth is how much a project spent in a month and up to it.
+type ProjectMonth struct {
+	Year       int
+	Month      int
+	Spent      decimal.Decimal
+	Cumulative decimal.Decimal
+}
+
+// ProjectReport compares a project's spending with its budget.
+type ProjectReport struct {
+	Project    model.Project
+	Spent      decimal.Decimal
+	Remaining  decimal.Decimal
+	Categories []ProjectCategoryReport
+	Timeline   []ProjectMonth
+}
+
+// ProjectService manages budgets for events, such as a wedding or a trip, that
+// span several categories, accounts and months.
+type ProjectService struct {
+	repo         repository.ProjectRepository
+	categoryRepo repository.CategoryRepository
+}
+
+// NewProjectService creates a new instance of ProjectService.
+func NewProjectService(repo repository.ProjectRepository, categoryRepo repository.CategoryRepository) *ProjectService {
+	return &ProjectService{repo: repo, categoryRepo: categoryRepo}
+}
+
+// Create validates and stores a project.
+func (s *ProjectService) Create(ctx context.Context, project model.Project) (*model.Project, error) {
+	if err := s.validate(ctx, project); err != nil {
+		return nil, err
+	}
+	id, err := s.repo.Create(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetById(ctx, id, project.UserId)
+}
+
+// GetById returns a project with its category budgets.
+func (s *ProjectService) GetById(ctx context.Context, id, userId int64) (*model.Project, error) {
+	project, err := s.repo.GetById(ctx, id, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, err
+	}
+	return project, nil
+}
+
+// List returns the user's projects, the most recent first.
+func (s *ProjectService) List(ctx context.Context, userId int64) ([]model.Project, error) {
+	return s.repo.List(ctx, userId)
+}
+
+// Update replaces a project and its category budgets.
+func (s *ProjectService) Update(ctx context.Context, project model.Project) (*model.Project, error) {
+	if err := s.validate(ctx, project); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, project); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, err
+	}
+	return s.repo.GetById(ctx, project.Id, project.UserId)
+}
+
+// Delete removes a project. Its transactions are kept.
+func (s *ProjectService) Delete(ctx context.Context, id, userId int64) error {
+	if err := s.repo.Delete(ctx, id, userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrProjectNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// AssignTransactions assigns transactions to a project, moving them out of
+// any other project.
+func (s *ProjectService) AssignTransactions(ctx context.Context, userId, id int64, transactionIds []int64) error {
+	if _, err := s.GetById(ctx, id, userId); err != nil {
+		return err
+	}
+	if err := s.repo.AssignTransactions(ctx, id, userId, transactionIds); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrProjectTransactionNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// UnassignTransaction removes a transaction from a project.
+func (s *ProjectService) UnassignTransaction(ctx context.Context, userId, id, transactionId int64) error {
+	if err := s.repo.UnassignTransaction(ctx, id, userId, transactionId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrProjectTransactionNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ListTransactions returns the transactions of a project, oldest first.
+func (s *ProjectService) ListTransactions(ctx context.Context, userId, id int64) ([]model.Transaction, error) {
+	if _, err := s.GetById(ctx, id, userId); err != nil {
+		return nil, err
+	}
+	return s.repo.ListTransactions(ctx, id, userId)
+}
+
+// GetReport compares a project's spending with its budget, in total, per
+// category and month by month. Expenses add to the spending and incomes, such
+// as refunds, take from it; transfers are ignored.
+func (s *ProjectService) GetReport(ctx context.Context, userId, id int64) (*ProjectReport, error) {
+	project, err := s.GetById(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := s.repo.ListTransactions(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ProjectReport{Project: *project, Categories: []ProjectCategoryReport{}}
+	categories := map[int64]*ProjectCategoryReport{}
+	for _, budget := range project.CategoryBudgets {
+		categories[budget.CategoryId] = &ProjectCategoryReport{
+			CategoryId:   &budget.CategoryId,
+			CategoryName: budget.CategoryName,
+			Planned:      &budget.Amount,
+		}
+	}
+	uncategorized := &ProjectCategoryReport{CategoryName: "Uncategorized"}
+
+	months := map[[2]int]decimal.Decimal{}
+	first, last := project.StartDate, project.EndDate
+	for _, tx := range transactions {
+		var amount decimal.Decimal
+		switch tx.Type {
+		case model.Expense:
+			amount = tx.Amount
+		case model.Income:
+			amount = tx.Amount.Neg()
+		default:
+			continue
+		}
+		report.Spent = report.Spent.Add(amount)
+
+		category := uncategorized
+		if tx.CategoryId != nil {
+			if _, ok := categories[*tx.CategoryId]; !ok {
+				categories[*tx.CategoryId] = &ProjectCategoryReport{CategoryId: tx.CategoryId}
+				if tx.CategoryName != nil {
+					categories[*tx.CategoryId].CategoryName = *tx.CategoryName
+				}
+			}
+			category = categories[*tx.CategoryId]
+		}
+		category.Spent = category.Spent.Add(amount)
+
+		key := [2]int{tx.Date.Year(), int(tx.Date.Month())}
+		months[key] = months[key].Add(amount)
+		if tx.Date.Before(first) {
+			first = tx.Date
+		}
+		if tx.Date.After(last) {
+			last = tx.Date
+		}
+	}
+	report.Remaining = project.TotalBudget.Sub(report.Spent)
+
+	for _, category := range categories {
+		report.Categories = append(report.Categories, *category)
+	}
+	sort.Slice(report.Categories, func(i, j int) bool {
+		return report.Categories[i].CategoryName < report.Categories[j].CategoryName
+	})
+	if !uncategorized.Spent.IsZero() {
+		report.Categories = append(report.Categories, *uncategorized)
+	}
+
+	cumulative := decimal.Zero
+	end := time.Date(last.Year(), last.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for month := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC); !month.After(end); month = month.AddDate(0, 1, 0) {
+		spent := months[[2]int{month.Year(), int(month.Month())}]
+		cumulative = cumulative.Add(spent)
+		report.Timeline = append(report.Timeline, ProjectMonth{
+			Year:       month.Year(),
+			Month:      int(month.Month()),
+			Spent:      spent,
+			Cumulative: cumulative,
+		})
+	}
+	return report, nil
+}
+
+func (s *ProjectService) validate(ctx context.Context, project model.Project) error {
+	if !project.TotalBudget.IsPositive() || project.EndDate.Before(project.StartDate) {
+		return ErrInvalidProject
+	}
+	planned := decimal.Zero
+	seen := map[int64]bool{}
+	for _, budget := range project.CategoryBudgets {
+		if !budget.Amount.IsPositive() || seen[budget.CategoryId] {
+			return ErrInvalidProject
+		}
+		seen[budget.CategoryId] = true
+		planned = planned.Add(budget.Amount)
+
+		category, err := s.categoryRepo.GetById(ctx, budget.CategoryId, project.UserId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidProjectCategory
+			}
+			return err
+		}
+		if category.Type != model.Expense {
+			return ErrInvalidProjectCategory
+		}
+	}
+	if planned.GreaterThan(project.TotalBudget) {
+		return ErrInvalidProject
+	}
+	return nil
+}
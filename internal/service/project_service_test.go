@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/testhelper"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockProjectRepository is a mock for the ProjectRepository interface.
+type MockProjectRepository struct {
+	mock.Mock
+}
+
+func (m *MockProjectRepository) Create(ctx context.Context, project model.Project) (int64, error) {
+	args := m.Called(ctx, project)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockProjectRepository) GetById(ctx context.Context, id, userId int64) (*model.Project, error) {
+	args := m.Called(ctx, id, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) List(ctx context.Context, userId int64) ([]model.Project, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) Update(ctx context.Context, project model.Project) error {
+	args := m.Called(ctx, project)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) Delete(ctx context.Context, id, userId int64) error {
+	args := m.Called(ctx, id, userId)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) AssignTransactions(ctx context.Context, id, userId int64, transactionIds []int64) error {
+	args := m.Called(ctx, id, userId, transactionIds)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) UnassignTransaction(ctx context.Context, id, userId, transactionId int64) error {
+	args := m.Called(ctx, id, userId, transactionId)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) ListTransactions(ctx context.Context, id, userId int64) ([]model.Transaction, error) {
+	args := m.Called(ctx, id, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Transaction), args.Error(1)
+}
+
+func TestProjectService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	userId := int64(1)
+	venueId, dressId, giftsId := int64(10), int64(11), int64(12)
+
+	wedding := model.Project{
+		Id:          5,
+		UserId:      userId,
+		Name:        "Wedding",
+		TotalBudget: decimal.NewFromInt(50000),
+		StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+		CategoryBudgets: []model.ProjectCategoryBudget{
+			{CategoryId: venueId, CategoryName: "Venue", Amount: decimal.NewFromInt(30000)},
+			{CategoryId: dressId, CategoryName: "Dress", Amount: decimal.NewFromInt(8000)},
+		},
+	}
+
+	setup := func() (*ProjectService, *MockProjectRepository, *MockCategoryRepository) {
+		mockRepo := new(MockProjectRepository)
+		mockCategoryRepo := new(MockCategoryRepository)
+		return NewProjectService(mockRepo, mockCategoryRepo), mockRepo, mockCategoryRepo
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		t.Run("should reject category budgets larger than the total", func(t *testing.T) {
+			// Arrange
+			projectService, mockRepo, mockCategoryRepo := setup()
+			mockCategoryRepo.On("GetById", ctx, mock.Anything, userId).Return(&model.Category{Type: model.Expense}, nil)
+			project := wedding
+			project.TotalBudget = decimal.NewFromInt(20000)
+
+			// Act
+			_, err := projectService.Create(ctx, project)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidProject)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should reject an end date before the start date", func(t *testing.T) {
+			// Arrange
+			projectService, _, _ := setup()
+			project := wedding
+			project.EndDate = project.StartDate.AddDate(0, 0, -1)
+
+			// Act
+			_, err := projectService.Create(ctx, project)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidProject)
+		})
+
+		t.Run("should reject income categories", func(t *testing.T) {
+			// Arrange
+			projectService, _, mockCategoryRepo := setup()
+			mockCategoryRepo.On("GetById", ctx, venueId, userId).Return(&model.Category{Type: model.Income}, nil).Once()
+
+			// Act
+			_, err := projectService.Create(ctx, wedding)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidProjectCategory)
+		})
+	})
+
+	t.Run("AssignTransactions", func(t *testing.T) {
+		t.Run("should return not found when a transaction is not the user's", func(t *testing.T) {
+			// Arrange
+			projectService, mockRepo, _ := setup()
+			mockRepo.On("GetById", ctx, wedding.Id, userId).Return(&wedding, nil).Once()
+			mockRepo.On("AssignTransactions", ctx, wedding.Id, userId, []int64{1, 2}).Return(sql.ErrNoRows).Once()
+
+			// Act
+			err := projectService.AssignTransactions(ctx, userId, wedding.Id, []int64{1, 2})
+
+			// Assert
+			assert.ErrorIs(t, err, ErrProjectTransactionNotFound)
+		})
+	})
+
+	t.Run("GetReport", func(t *testing.T) {
+		t.Run("should compare spending with the plan per category and month", func(t *testing.T) {
+			// Arrange
+			projectService, mockRepo, _ := setup()
+			mockRepo.On("GetById", ctx, wedding.Id, userId).Return(&wedding, nil).Once()
+			mockRepo.On("ListTransactions", ctx, wedding.Id, userId).Return([]model.Transaction{
+				{Type: model.Expense, Amount: decimal.NewFromInt(5000), Date: time.Date(2024, 12, 10, 0, 0, 0, 0, time.UTC), CategoryId: &venueId, CategoryName: testhelper.Ptr("Venue")},
+				{Type: model.Expense, Amount: decimal.NewFromInt(25000), Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), CategoryId: &venueId, CategoryName: testhelper.Ptr("Venue")},
+				{Type: model.Expense, Amount: decimal.NewFromInt(1200), Date: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), CategoryId: &giftsId, CategoryName: testhelper.Ptr("Gifts")},
+				{Type: model.Income, Amount: decimal.NewFromInt(200), Date: time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC), CategoryId: &giftsId, CategoryName: testhelper.Ptr("Gifts")},
+				{Type: model.Transfer, Amount: decimal.NewFromInt(999), Date: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+			}, nil).Once()
+
+			// Act
+			report, err := projectService.GetReport(ctx, userId, wedding.Id)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.True(t, report.Spent.Equal(decimal.NewFromInt(31000)), report.Spent.String())
+			assert.True(t, report.Remaining.Equal(decimal.NewFromInt(19000)))
+
+			assert.Len(t, report.Categories, 3)
+			assert.Equal(t, "Dress", report.Categories[0].CategoryName)
+			assert.True(t, report.Categories[0].Spent.IsZero())
+			assert.Equal(t, "Gifts", report.Categories[1].CategoryName)
+			assert.Nil(t, report.Categories[1].Planned)
+			assert.True(t, report.Categories[1].Spent.Equal(decimal.NewFromInt(1000)))
+			assert.True(t, report.Categories[2].Spent.Equal(decimal.NewFromInt(30000)))
+
+			// The timeline starts at the deposit paid before the project.
+			assert.Len(t, report.Timeline, 4)
+			assert.Equal(t, 2024, report.Timeline[0].Year)
+			assert.True(t, report.Timeline[2].Spent.IsZero())
+			assert.True(t, report.Timeline[3].Cumulative.Equal(decimal.NewFromInt(31000)))
+		})
+	})
+}
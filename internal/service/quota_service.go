@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/ratelimit"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+)
+
+// ErrQuotaExceeded matches every QuotaExceededError through errors.Is.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Error codes returned to API clients when a quota is exceeded.
+const (
+	QuotaCodeAccounts            = "quota_accounts_exceeded"
+	QuotaCodeMonthlyTransactions = "quota_monthly_transactions_exceeded"
+	QuotaCodeStorage             = "quota_storage_exceeded"
+	QuotaCodeDailyRequests       = "quota_daily_requests_exceeded"
+)
+
+// quotaRequestWindow is how long a daily request counter is kept. Counters
+// start over at midnight UTC because the date is part of their key.
+const quotaRequestWindow = 24 * time.Hour
+
+// QuotaExceededError is returned when an action would take the user past one of their quotas.
+type QuotaExceededError struct {
+	Code  string
+	Limit int64
+	Used  int64
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s: limit is %d, current usage is %d", e.Code, e.Limit, e.Used)
+}
+
+func (e *QuotaExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// QuotaLimits are the per-user limits of the instance. Zero disables a limit.
+type QuotaLimits struct {
+	MaxAccounts             int64
+	MaxTransactionsPerMonth int64
+	MaxStorageBytes         int64
+	MaxRequestsPerDay       int64
+}
+
+// QuotaService enforces the per-user quotas. Data quotas are checked against
+// the database; API requests are counted in memory, per UTC day.
+type QuotaService struct {
+	usageRepo repository.UsageRepository
+	limits    QuotaLimits
+	requests  *ratelimit.Limiter
+	now       func() time.Time
+}
+
+// NewQuotaService creates a new instance of QuotaService.
+func NewQuotaService(usageRepo repository.UsageRepository, limits QuotaLimits) *QuotaService {
+	maxRequests := math.MaxInt
+	if limits.MaxRequestsPerDay > 0 {
+		maxRequests = int(limits.MaxRequestsPerDay)
+	}
+	return &QuotaService{
+		usageRepo: usageRepo,
+		limits:    limits,
+		requests:  ratelimit.New(maxRequests, quotaRequestWindow),
+		now:       time.Now,
+	}
+}
+
+// CheckAccountCreation verifies that the user can create one more account.
+func (s *QuotaService) CheckAccountCreation(ctx context.Context, userId int64) error {
+	if s.limits.MaxAccounts > 0 {
+		accounts, err := s.usageRepo.CountAccounts(ctx, userId)
+		if err != nil {
+			return fmt.Errorf("failed to count accounts: %w", err)
+		}
+		if accounts+1 > s.limits.MaxAccounts {
+			return &QuotaExceededError{Code: QuotaCodeAccounts, Limit: s.limits.MaxAccounts, Used: accounts}
+		}
+	}
+	return s.checkStorage(ctx, userId)
+}
+
+// CheckTransactionCreation verifies that the user can create count more
+// transactions this month. Imports pass the size of the whole batch.
+func (s *QuotaService) CheckTransactionCreation(ctx context.Context, userId int64, count int64) error {
+	if s.limits.MaxTransactionsPerMonth > 0 {
+		created, err := s.usageRepo.CountTransactionsCreatedSince(ctx, userId, s.monthStart())
+		if err != nil {
+			return fmt.Errorf("failed to count transactions: %w", err)
+		}
+		if created+count > s.limits.MaxTransactionsPerMonth {
+			return &QuotaExceededError{Code: QuotaCodeMonthlyTransactions, Limit: s.limits.MaxTransactionsPerMonth, Used: created}
+		}
+	}
+	return s.checkStorage(ctx, userId)
+}
+
+// AllowRequest records an API request for the user and fails once the daily quota is used up.
+func (s *QuotaService) AllowRequest(userId int64) error {
+	if s.requests.Allow(s.requestKey(userId)) {
+		return nil
+	}
+	return &QuotaExceededError{Code: QuotaCodeDailyRequests, Limit: s.limits.MaxRequestsPerDay, Used: s.limits.MaxRequestsPerDay}
+}
+
+// GetUsage reports the user's consumption of every quota.
+func (s *QuotaService) GetUsage(ctx context.Context, userId int64) (*model.QuotaUsage, error) {
+	accounts, err := s.usageRepo.CountAccounts(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := s.usageRepo.CountTransactionsCreatedSince(ctx, userId, s.monthStart())
+	if err != nil {
+		return nil, err
+	}
+	storage, err := s.usageRepo.EstimateStorageBytes(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.QuotaUsage{
+		Accounts:              model.QuotaMetric{Used: accounts, Limit: s.limits.MaxAccounts},
+		TransactionsThisMonth: model.QuotaMetric{Used: transactions, Limit: s.limits.MaxTransactionsPerMonth},
+		StorageBytes:          model.QuotaMetric{Used: storage, Limit: s.limits.MaxStorageBytes},
+		RequestsToday:         model.QuotaMetric{Used: int64(s.requests.Count(s.requestKey(userId))), Limit: s.limits.MaxRequestsPerDay},
+	}, nil
+}
+
+func (s *QuotaService) checkStorage(ctx context.Context, userId int64) error {
+	if s.limits.MaxStorageBytes <= 0 {
+		return nil
+	}
+	storage, err := s.usageRepo.EstimateStorageBytes(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("failed to estimate storage: %w", err)
+	}
+	if storage >= s.limits.MaxStorageBytes {
+		return &QuotaExceededError{Code: QuotaCodeStorage, Limit: s.limits.MaxStorageBytes, Used: storage}
+	}
+	return nil
+}
+
+// monthStart returns the first instant of the current month in UTC.
+func (s *QuotaService) monthStart() time.Time {
+	now := s.now().UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// requestKey scopes the request counter to the user and the current UTC day,
+// so the count starts over at midnight.
+func (s *QuotaService) requestKey(userId int64) string {
+	return strconv.FormatInt(userId, 10) + ":" + s.now().UTC().Format(time.DateOnly)
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// unlimitedQuotas returns a QuotaService with every limit disabled, for tests
+// that do not exercise quotas. It never touches the usage repository.
+func unlimitedQuotas() *QuotaService {
+	return NewQuotaService(nil, QuotaLimits{})
+}
+
+func TestQuotaService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	userId := int64(1)
+	now := time.Date(2025, time.July, 15, 10, 0, 0, 0, time.UTC)
+	monthStart := time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)
+
+	setup := func(limits QuotaLimits) (*QuotaService, *MockUsageRepository) {
+		mockUsageRepo := new(MockUsageRepository)
+		quotaService := NewQuotaService(mockUsageRepo, limits)
+		quotaService.now = func() time.Time { return now }
+		return quotaService, mockUsageRepo
+	}
+
+	t.Run("CheckAccountCreation", func(t *testing.T) {
+		t.Run("should reject a new account when the limit is reached", func(t *testing.T) {
+			// Arrange
+			quotaService, mockUsageRepo := setup(QuotaLimits{MaxAccounts: 3})
+			mockUsageRepo.On("CountAccounts", ctx, userId).Return(int64(3), nil).Once()
+
+			// Act
+			err := quotaService.CheckAccountCreation(ctx, userId)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrQuotaExceeded)
+			var quotaErr *QuotaExceededError
+			assert.ErrorAs(t, err, &quotaErr)
+			assert.Equal(t, QuotaCodeAccounts, quotaErr.Code)
+			assert.Equal(t, int64(3), quotaErr.Limit)
+		})
+
+		t.Run("should reject a new account when storage is used up", func(t *testing.T) {
+			// Arrange
+			quotaService, mockUsageRepo := setup(QuotaLimits{MaxAccounts: 3, MaxStorageBytes: 1024})
+			mockUsageRepo.On("CountAccounts", ctx, userId).Return(int64(1), nil).Once()
+			mockUsageRepo.On("EstimateStorageBytes", ctx, userId).Return(int64(2048), nil).Once()
+
+			// Act
+			err := quotaService.CheckAccountCreation(ctx, userId)
+
+			// Assert
+			var quotaErr *QuotaExceededError
+			assert.ErrorAs(t, err, &quotaErr)
+			assert.Equal(t, QuotaCodeStorage, quotaErr.Code)
+		})
+
+		t.Run("should not query usage when no limit is configured", func(t *testing.T) {
+			// Arrange
+			quotaService, mockUsageRepo := setup(QuotaLimits{})
+
+			// Act
+			err := quotaService.CheckAccountCreation(ctx, userId)
+
+			// Assert
+			assert.NoError(t, err)
+			mockUsageRepo.AssertNotCalled(t, "CountAccounts", mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("CheckTransactionCreation", func(t *testing.T) {
+		t.Run("should count the transactions created since the start of the month", func(t *testing.T) {
+			// Arrange
+			quotaService, mockUsageRepo := setup(QuotaLimits{MaxTransactionsPerMonth: 100})
+			mockUsageRepo.On("CountTransactionsCreatedSince", ctx, userId, monthStart).Return(int64(99), nil).Twice()
+
+			// Act & Assert
+			assert.NoError(t, quotaService.CheckTransactionCreation(ctx, userId, 1))
+			err := quotaService.CheckTransactionCreation(ctx, userId, 2)
+			var quotaErr *QuotaExceededError
+			assert.ErrorAs(t, err, &quotaErr)
+			assert.Equal(t, QuotaCodeMonthlyTransactions, quotaErr.Code)
+			assert.Equal(t, int64(99), quotaErr.Used)
+			mockUsageRepo.AssertExpectations(t)
+		})
+	})
+
+	t.Run("AllowRequest", func(t *testing.T) {
+		t.Run("should reject requests past the daily limit and start over the next day", func(t *testing.T) {
+			// Arrange
+			quotaService, _ := setup(QuotaLimits{MaxRequestsPerDay: 2})
+			current := now
+			quotaService.now = func() time.Time { return current }
+
+			// Act & Assert
+			assert.NoError(t, quotaService.AllowRequest(userId))
+			assert.NoError(t, quotaService.AllowRequest(userId))
+			assert.ErrorIs(t, quotaService.AllowRequest(userId), ErrQuotaExceeded)
+			assert.NoError(t, quotaService.AllowRequest(userId+1), "other users have their own quota")
+
+			current = current.Add(24 * time.Hour)
+			assert.NoError(t, quotaService.AllowRequest(userId))
+		})
+	})
+
+	t.Run("GetUsage", func(t *testing.T) {
+		t.Run("should report usage next to the configured limits", func(t *testing.T) {
+			// Arrange
+			quotaService, mockUsageRepo := setup(QuotaLimits{MaxAccounts: 10, MaxRequestsPerDay: 100})
+			mockUsageRepo.On("CountAccounts", ctx, userId).Return(int64(4), nil).Once()
+			mockUsageRepo.On("CountTransactionsCreatedSince", ctx, userId, monthStart).Return(int64(42), nil).Once()
+			mockUsageRepo.On("EstimateStorageBytes", ctx, userId).Return(int64(8192), nil).Once()
+			assert.NoError(t, quotaService.AllowRequest(userId))
+
+			// Act
+			usage, err := quotaService.GetUsage(ctx, userId)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, model.QuotaMetric{Used: 4, Limit: 10}, usage.Accounts)
+			assert.Equal(t, model.QuotaMetric{Used: 42, Limit: 0}, usage.TransactionsThisMonth)
+			assert.Equal(t, model.QuotaMetric{Used: 8192, Limit: 0}, usage.StorageBytes)
+			assert.Equal(t, model.QuotaMetric{Used: 1, Limit: 100}, usage.RequestsToday)
+		})
+	})
+
+	t.Run("TransactionService", func(t *testing.T) {
+		t.Run("should not create a transaction past the monthly quota", func(t *testing.T) {
+			// Arrange
+			quotaService, mockUsageRepo := setup(QuotaLimits{MaxTransactionsPerMonth: 10})
+			mockUsageRepo.On("CountTransactionsCreatedSince", ctx, userId, monthStart).Return(int64(10), nil).Once()
+			mockTxRepo := new(MockTransactionRepository)
+			txService := NewTransactionService(mockTxRepo, new(MockAccountRepository), quotaService, nil, TransactionOptions{})
+
+			// Act
+			_, err := txService.CreateTransaction(ctx, model.Transaction{UserId: userId, AccountId: 1, Amount: decimal.NewFromInt(10), Type: model.Expense})
+
+			// Assert
+			assert.ErrorIs(t, err, ErrQuotaExceeded)
+			mockTxRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	})
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrInvalidReportPeriod   = errors.New("invalid report period: month must be between 1 and 12 and year between 1900 and 9999")
+	ErrInvalidReportGroupBy  = errors.New("group_by takes up to 3 distinct dimensions among category, account, type, day, week, month, year and weekday")
+	ErrInvalidReportMeasures = errors.New("measures takes 1 to 5 distinct measures among sum, count, avg, min and max")
+	ErrInvalidReportPivot    = errors.New("a pivot needs exactly 2 dimensions and 1 measure")
+	ErrReportTooManyGroups   = errors.New("the report has too many groups: narrow the filters or group by fewer dimensions")
+
+	ErrUnavailableReportDimension = errors.New("transactions have no parent categories, payees or tags yet, so reports cannot group by them")
+)
+
+// unavailableReportDimensions are the dimensions transactions do not record
+// yet. They get their own error instead of being reported as unknown.
+var unavailableReportDimensions = map[repository.ReportDimension]bool{
+	"parent_category": true,
+	"payee":           true,
+	"tag":             true,
+}
+
+// uncategorizedName labels the transactions without a category in reports.
+const uncategorizedName = "Uncategorized"
+
+// Cardinality limits of report queries.
+const (
+	maxReportDimensions   = 3
+	maxReportQueryRows    = 1000
+	maxReportPivotColumns = 50
+)
+
+// CategoryTotal is the money that went in and out of one category in a period.
+type CategoryTotal struct {
+	CategoryId   *int64
+	CategoryName string
+	Income       decimal.Decimal
+	Expense      decimal.Decimal
+}
+
+// MonthlyReport summarizes a user's incomes and expenses in a fiscal month,
+// which is the calendar month unless the user moved its start day.
+// Transfers move money between the user's own accounts and are not counted.
+// TotalExpense is split into what was paid with meal and food vouchers
+// (BenefitExpense) and with everything else (CashExpense).
+type MonthlyReport struct {
+	Year             int
+	Month            int
+	Period           StatementPeriod
+	TotalIncome      decimal.Decimal
+	TotalExpense     decimal.Decimal
+	CashExpense      decimal.Decimal
+	BenefitExpense   decimal.Decimal
+	Net              decimal.Decimal
+	TransactionCount int
+	Categories       []CategoryTotal
+}
+
+// ReportService builds read-only financial reports.
+type ReportService struct {
+	transactionRepo repository.TransactionRepository
+	userRepo        repository.UserRepository
+	totalsRepo      repository.MonthlyTotalsRepository
+}
+
+// NewReportService creates a new instance of ReportService. When totalsRepo is
+// nil, reports always add up the raw transactions.
+func NewReportService(transactionRepo repository.TransactionRepository, userRepo repository.UserRepository, totalsRepo repository.MonthlyTotalsRepository) *ReportService {
+	return &ReportService{transactionRepo: transactionRepo, userRepo: userRepo, totalsRepo: totalsRepo}
+}
+
+// PeriodOf returns the year and month of the user's fiscal month that contains t.
+func (s *ReportService) PeriodOf(ctx context.Context, userId int64, t time.Time) (year, month int, err error) {
+	fiscalMonth, err := fiscalMonthOf(ctx, s.userRepo, userId)
+	if err != nil {
+		return 0, 0, err
+	}
+	year, month = fiscalMonth.Of(t)
+	return year, month, nil
+}
+
+// GetMonthlyReport totals the user's transactions of the given fiscal month, overall and per category.
+func (s *ReportService) GetMonthlyReport(ctx context.Context, userId int64, year, month int) (*MonthlyReport, error) {
+	if !validReportPeriod(year, month) {
+		return nil, ErrInvalidReportPeriod
+	}
+
+	fiscalMonth, err := fiscalMonthOf(ctx, s.userRepo, userId)
+	if err != nil {
+		return nil, err
+	}
+	start, next := fiscalMonth.Period(year, month)
+	end := next.Add(-time.Second)
+	totals, err := periodTotals(ctx, s.transactionRepo, s.totalsRepo, userId, fiscalMonth, year, month)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &MonthlyReport{
+		Year:           year,
+		Month:          month,
+		Period:         StatementPeriod{Start: start, End: end},
+		TotalIncome:    decimal.Zero,
+		TotalExpense:   decimal.Zero,
+		CashExpense:    decimal.Zero,
+		BenefitExpense: decimal.Zero,
+		Categories:     []CategoryTotal{},
+	}
+	byCategory := map[string]*CategoryTotal{}
+	for _, row := range totals {
+		if row.Type == model.Transfer {
+			continue
+		}
+		report.TransactionCount += row.Count
+
+		name := uncategorizedName
+		if row.CategoryName != nil {
+			name = *row.CategoryName
+		}
+		total, ok := byCategory[name]
+		if !ok {
+			total = &CategoryTotal{CategoryId: row.CategoryId, CategoryName: name, Income: decimal.Zero, Expense: decimal.Zero}
+			byCategory[name] = total
+		}
+
+		switch row.Type {
+		case model.Income:
+			report.TotalIncome = report.TotalIncome.Add(row.Total)
+			total.Income = total.Income.Add(row.Total)
+		case model.Expense:
+			report.TotalExpense = report.TotalExpense.Add(row.Total)
+			total.Expense = total.Expense.Add(row.Total)
+			if row.AccountType == model.Benefit {
+				report.BenefitExpense = report.BenefitExpense.Add(row.Total)
+			} else {
+				report.CashExpense = report.CashExpense.Add(row.Total)
+			}
+		}
+	}
+	report.Net = report.TotalIncome.Sub(report.TotalExpense)
+
+	for _, total := range byCategory {
+		report.Categories = append(report.Categories, *total)
+	}
+	// Biggest expenses first, then by name so the order is stable.
+	sort.Slice(report.Categories, func(i, j int) bool {
+		a, b := report.Categories[i], report.Categories[j]
+		if !a.Expense.Equal(b.Expense) {
+			return a.Expense.GreaterThan(b.Expense)
+		}
+		return a.CategoryName < b.CategoryName
+	})
+
+	return report, nil
+}
+
+func validReportPeriod(year, month int) bool {
+	return month >= 1 && month <= 12 && year >= 1900 && year <= 9999
+}
+
+// ReportQueryRow is one group of a report query: its key for each dimension
+// and the value of each measure, in the order they were asked for. Ids holds
+// the id of the category or account a key names, so two with the same name
+// can be told apart; it is nil for other dimensions and uncategorized rows.
+type ReportQueryRow struct {
+	Keys   []string
+	Ids    []*int64
+	Values []decimal.Decimal
+}
+
+// ReportPivotRow is one key of the first dimension of a pivot, with the
+// measure for each column. Values is nil where there were no transactions.
+type ReportPivotRow struct {
+	Key    string
+	Id     *int64
+	Values []*decimal.Decimal
+}
+
+// ReportPivot lays a two dimension report out as a table, the keys of the
+// first dimension down the rows and those of the second across the columns.
+// ColumnIds holds the id each column names, like the Ids of a row.
+type ReportPivot struct {
+	Columns   []string
+	ColumnIds []*int64
+	Rows      []ReportPivotRow
+}
+
+// ReportQueryResult is the answer to a report query, as rows or as a pivot.
+// Truncated is set when there were more rows than the limit.
+type ReportQueryResult struct {
+	GroupBy   []repository.ReportDimension
+	Measures  []repository.ReportMeasure
+	Rows      []ReportQueryRow
+	Pivot     *ReportPivot
+	Truncated bool
+}
+
+// Query groups the user's transactions by the dimensions of the query and
+// summarizes each group with its measures. With pivot set, the result is laid
+// out as a table of the first dimension by the second.
+func (s *ReportService) Query(ctx context.Context, userId int64, query repository.ReportQuery, pivot bool) (*ReportQueryResult, error) {
+	for _, dimension := range query.GroupBy {
+		if unavailableReportDimensions[dimension] {
+			return nil, ErrUnavailableReportDimension
+		}
+	}
+	if !validReportDimensions(query.GroupBy) {
+		return nil, ErrInvalidReportGroupBy
+	}
+	if !validReportMeasures(query.Measures) {
+		return nil, ErrInvalidReportMeasures
+	}
+	if pivot && (len(query.GroupBy) != 2 || len(query.Measures) != 1) {
+		return nil, ErrInvalidReportPivot
+	}
+
+	limit := query.Limit
+	if pivot || limit <= 0 || limit > maxReportQueryRows {
+		limit = maxReportQueryRows
+	}
+	// One row more than the limit tells whether there were more.
+	query.Limit = limit + 1
+	rows, err := s.transactionRepo.Aggregate(ctx, userId, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReportQueryResult{GroupBy: query.GroupBy, Measures: query.Measures, Rows: []ReportQueryRow{}}
+	if len(rows) > limit {
+		if pivot {
+			return nil, ErrReportTooManyGroups
+		}
+		rows, result.Truncated = rows[:limit], true
+	}
+	for _, row := range rows {
+		keys := make([]string, len(row.Keys))
+		for i, key := range row.Keys {
+			switch {
+			case key != nil:
+				keys[i] = *key
+			case query.GroupBy[i] == repository.DimensionCategory:
+				keys[i] = uncategorizedName
+			}
+		}
+		result.Rows = append(result.Rows, ReportQueryRow{Keys: keys, Ids: row.Ids, Values: row.Values})
+	}
+
+	if pivot {
+		if result.Pivot, err = pivotReportRows(result.Rows); err != nil {
+			return nil, err
+		}
+		result.Rows = nil
+	}
+	return result, nil
+}
+
+// reportPivotKey is a key of a pivot with the id it names, so keys with the
+// same name stay apart.
+type reportPivotKey struct {
+	name string
+	id   int64
+	isId bool
+}
+
+func newReportPivotKey(name string, id *int64) reportPivotKey {
+	if id == nil {
+		return reportPivotKey{name: name}
+	}
+	return reportPivotKey{name: name, id: *id, isId: true}
+}
+
+// pivotReportRows lays out rows of two keys and one value as a pivot. The rows
+// come sorted by their keys, so the pivot rows keep that order.
+func pivotReportRows(rows []ReportQueryRow) (*ReportPivot, error) {
+	columnIndex := map[reportPivotKey]int{}
+	var columns []reportPivotKey
+	for _, row := range rows {
+		column := newReportPivotKey(row.Keys[1], row.Ids[1])
+		if _, ok := columnIndex[column]; !ok {
+			columnIndex[column] = 0
+			columns = append(columns, column)
+		}
+	}
+	if len(columns) > maxReportPivotColumns {
+		return nil, ErrReportTooManyGroups
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		a, b := columns[i], columns[j]
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		if a.isId != b.isId {
+			return b.isId
+		}
+		return a.id < b.id
+	})
+
+	pivot := &ReportPivot{Columns: make([]string, len(columns)), ColumnIds: make([]*int64, len(columns)), Rows: []ReportPivotRow{}}
+	for i, column := range columns {
+		columnIndex[column] = i
+		pivot.Columns[i] = column.name
+		if column.isId {
+			pivot.ColumnIds[i] = &column.id
+		}
+	}
+
+	for _, row := range rows {
+		last := len(pivot.Rows) - 1
+		if last < 0 || newReportPivotKey(pivot.Rows[last].Key, pivot.Rows[last].Id) != newReportPivotKey(row.Keys[0], row.Ids[0]) {
+			pivot.Rows = append(pivot.Rows, ReportPivotRow{Key: row.Keys[0], Id: row.Ids[0], Values: make([]*decimal.Decimal, len(pivot.Columns))})
+			last++
+		}
+		value := row.Values[0]
+		pivot.Rows[last].Values[columnIndex[newReportPivotKey(row.Keys[1], row.Ids[1])]] = &value
+	}
+	return pivot, nil
+}
+
+func validReportDimensions(dimensions []repository.ReportDimension) bool {
+	if len(dimensions) > maxReportDimensions {
+		return false
+	}
+	seen := map[repository.ReportDimension]bool{}
+	for _, dimension := range dimensions {
+		if !dimension.Valid() || seen[dimension] {
+			return false
+		}
+		seen[dimension] = true
+	}
+	return true
+}
+
+func validReportMeasures(measures []repository.ReportMeasure) bool {
+	if len(measures) == 0 {
+		return false
+	}
+	seen := map[repository.ReportMeasure]bool{}
+	for _, measure := range measures {
+		if !measure.Valid() || seen[measure] {
+			return false
+		}
+		seen[measure] = true
+	}
+	return true
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/testhelper"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReportService_GetMonthlyReport(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	userId := int64(1)
+
+	t.Run("should total incomes and expenses per category, ignoring transfers", func(t *testing.T) {
+		// Arrange
+		mockTxRepo := new(MockTransactionRepository)
+		reportService := NewReportService(mockTxRepo, calendarMonths(), nil)
+		food, salary := testhelper.Ptr("Food"), testhelper.Ptr("Salary")
+		transactions := []model.Transaction{
+			{Type: model.Income, Amount: decimal.NewFromInt(5000), CategoryId: testhelper.Ptr(int64(1)), CategoryName: salary},
+			{Type: model.Expense, Amount: decimal.NewFromInt(300), CategoryId: testhelper.Ptr(int64(2)), CategoryName: food},
+			{Type: model.Expense, Amount: decimal.NewFromInt(200), CategoryId: testhelper.Ptr(int64(2)), CategoryName: food},
+			{Type: model.Expense, Amount: decimal.NewFromInt(50)},
+			{Type: model.Transfer, Amount: decimal.NewFromInt(1000)},
+		}
+		expectedStart := time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)
+		expectedEnd := time.Date(2025, time.February, 28, 23, 59, 59, 0, time.UTC)
+		mockTxRepo.On("List", ctx, userId, mock.MatchedBy(func(f repository.ListTransactionFilters) bool {
+			return f.StartDate.Equal(expectedStart) && f.EndDate.Equal(expectedEnd)
+		})).Return(transactions, nil).Once()
+
+		// Act
+		report, err := reportService.GetMonthlyReport(ctx, userId, 2025, 2)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, report.TotalIncome.Equal(decimal.NewFromInt(5000)))
+		assert.True(t, report.TotalExpense.Equal(decimal.NewFromInt(550)))
+		assert.True(t, report.Net.Equal(decimal.NewFromInt(4450)))
+		assert.Equal(t, 4, report.TransactionCount)
+		assert.Len(t, report.Categories, 3)
+		assert.Equal(t, "Food", report.Categories[0].CategoryName)
+		assert.True(t, report.Categories[0].Expense.Equal(decimal.NewFromInt(500)))
+		assert.Equal(t, uncategorizedName, report.Categories[1].CategoryName)
+		mockTxRepo.AssertExpectations(t)
+	})
+
+	t.Run("should split expenses paid with benefit accounts from cash expenses", func(t *testing.T) {
+		// Arrange
+		mockTxRepo := new(MockTransactionRepository)
+		reportService := NewReportService(mockTxRepo, calendarMonths(), nil)
+		mockTxRepo.On("List", ctx, userId, mock.Anything).Return([]model.Transaction{
+			{Type: model.Income, Amount: decimal.NewFromInt(800), AccountType: model.Benefit},
+			{Type: model.Expense, Amount: decimal.NewFromInt(120), AccountType: model.Benefit},
+			{Type: model.Expense, Amount: decimal.NewFromInt(80), AccountType: model.CreditCard},
+			{Type: model.Expense, Amount: decimal.NewFromInt(30), AccountType: model.Checking},
+		}, nil).Once()
+
+		// Act
+		report, err := reportService.GetMonthlyReport(ctx, userId, 2025, 3)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, report.TotalExpense.Equal(decimal.NewFromInt(230)))
+		assert.True(t, report.BenefitExpense.Equal(decimal.NewFromInt(120)))
+		assert.True(t, report.CashExpense.Equal(decimal.NewFromInt(110)))
+	})
+
+	t.Run("should build the report from the monthly totals when they are enabled", func(t *testing.T) {
+		// Arrange
+		mockTxRepo := new(MockTransactionRepository)
+		mockTotalsRepo := new(MockMonthlyTotalsRepository)
+		reportService := NewReportService(mockTxRepo, calendarMonths(), mockTotalsRepo)
+		food, salary := testhelper.Ptr("Food"), testhelper.Ptr("Salary")
+		mockTotalsRepo.On("ListByPeriod", ctx, userId, 2025, 2).Return([]model.MonthlyCategoryTotal{
+			{Type: model.Income, Total: decimal.NewFromInt(5000), Count: 1, CategoryId: testhelper.Ptr(int64(1)), CategoryName: salary, AccountType: model.Checking},
+			{Type: model.Expense, Total: decimal.NewFromInt(500), Count: 2, CategoryId: testhelper.Ptr(int64(2)), CategoryName: food, AccountType: model.Checking},
+			{Type: model.Expense, Total: decimal.NewFromInt(120), Count: 3, CategoryId: testhelper.Ptr(int64(2)), CategoryName: food, AccountType: model.Benefit},
+			{Type: model.Transfer, Total: decimal.NewFromInt(1000), Count: 1, AccountType: model.Checking},
+		}, nil).Once()
+
+		// Act
+		report, err := reportService.GetMonthlyReport(ctx, userId, 2025, 2)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, report.TotalIncome.Equal(decimal.NewFromInt(5000)))
+		assert.True(t, report.TotalExpense.Equal(decimal.NewFromInt(620)))
+		assert.True(t, report.BenefitExpense.Equal(decimal.NewFromInt(120)))
+		assert.Equal(t, 6, report.TransactionCount)
+		assert.Len(t, report.Categories, 2)
+		assert.Equal(t, "Food", report.Categories[0].CategoryName)
+		mockTotalsRepo.AssertExpectations(t)
+		mockTxRepo.AssertNotCalled(t, "List")
+	})
+
+	t.Run("should reject an invalid month", func(t *testing.T) {
+		reportService := NewReportService(new(MockTransactionRepository), calendarMonths(), nil)
+
+		_, err := reportService.GetMonthlyReport(ctx, userId, 2025, 13)
+
+		assert.ErrorIs(t, err, ErrInvalidReportPeriod)
+	})
+}
+
+func TestReportService_Query(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	userId := int64(1)
+	row := func(first, second *string, value int64) repository.ReportRow {
+		return repository.ReportRow{Keys: []*string{first, second}, Ids: make([]*int64, 2), Values: []decimal.Decimal{decimal.NewFromInt(value)}}
+	}
+
+	t.Run("should label rows without a category and ask for one row more than the limit", func(t *testing.T) {
+		// Arrange
+		mockTxRepo := new(MockTransactionRepository)
+		reportService := NewReportService(mockTxRepo, calendarMonths(), nil)
+		mockTxRepo.On("Aggregate", ctx, userId, mock.MatchedBy(func(q repository.ReportQuery) bool {
+			return q.Limit == 3
+		})).Return([]repository.ReportRow{
+			row(testhelper.Ptr("2025-05"), testhelper.Ptr("Food"), 150),
+			row(testhelper.Ptr("2025-06"), testhelper.Ptr("Food"), 80),
+			row(testhelper.Ptr("2025-06"), nil, 5),
+		}, nil).Once()
+
+		// Act
+		result, err := reportService.Query(ctx, userId, repository.ReportQuery{
+			GroupBy:  []repository.ReportDimension{repository.DimensionMonth, repository.DimensionCategory},
+			Measures: []repository.ReportMeasure{repository.MeasureSum},
+			Limit:    2,
+		}, false)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, result.Truncated)
+		assert.Len(t, result.Rows, 2)
+		assert.Nil(t, result.Pivot)
+		mockTxRepo.AssertExpectations(t)
+	})
+
+	t.Run("should lay two dimensions out as a pivot", func(t *testing.T) {
+		// Arrange
+		mockTxRepo := new(MockTransactionRepository)
+		reportService := NewReportService(mockTxRepo, calendarMonths(), nil)
+		mockTxRepo.On("Aggregate", ctx, userId, mock.Anything).Return([]repository.ReportRow{
+			row(testhelper.Ptr("Food"), testhelper.Ptr("2025-05"), 150),
+			row(testhelper.Ptr("Food"), testhelper.Ptr("2025-06"), 80),
+			row(nil, testhelper.Ptr("2025-06"), 5),
+		}, nil).Once()
+
+		// Act
+		result, err := reportService.Query(ctx, userId, repository.ReportQuery{
+			GroupBy:  []repository.ReportDimension{repository.DimensionCategory, repository.DimensionMonth},
+			Measures: []repository.ReportMeasure{repository.MeasureSum},
+		}, true)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Nil(t, result.Rows)
+		assert.Equal(t, []string{"2025-05", "2025-06"}, result.Pivot.Columns)
+		assert.Len(t, result.Pivot.Rows, 2)
+		assert.Equal(t, "Food", result.Pivot.Rows[0].Key)
+		assert.True(t, result.Pivot.Rows[0].Values[1].Equal(decimal.NewFromInt(80)))
+		assert.Equal(t, uncategorizedName, result.Pivot.Rows[1].Key)
+		assert.Nil(t, result.Pivot.Rows[1].Values[0])
+	})
+
+	t.Run("should keep categories with the same name as uncategorized apart", func(t *testing.T) {
+		// Arrange
+		mockTxRepo := new(MockTransactionRepository)
+		reportService := NewReportService(mockTxRepo, calendarMonths(), nil)
+		named := row(testhelper.Ptr(uncategorizedName), testhelper.Ptr("2025-06"), 30)
+		named.Ids[0] = testhelper.Ptr(int64(9))
+		mockTxRepo.On("Aggregate", ctx, userId, mock.Anything).Return([]repository.ReportRow{
+			row(nil, testhelper.Ptr("2025-06"), 5),
+			named,
+		}, nil).Once()
+
+		// Act
+		result, err := reportService.Query(ctx, userId, repository.ReportQuery{
+			GroupBy:  []repository.ReportDimension{repository.DimensionCategory, repository.DimensionMonth},
+			Measures: []repository.ReportMeasure{repository.MeasureSum},
+		}, true)
+
+		// Assert
+		assert.NoError(t, err)
+		if assert.Len(t, result.Pivot.Rows, 2) {
+			assert.Nil(t, result.Pivot.Rows[0].Id)
+			assert.True(t, result.Pivot.Rows[0].Values[0].Equal(decimal.NewFromInt(5)))
+			assert.Equal(t, uncategorizedName, result.Pivot.Rows[1].Key)
+			assert.Equal(t, int64(9), *result.Pivot.Rows[1].Id)
+			assert.True(t, result.Pivot.Rows[1].Values[0].Equal(decimal.NewFromInt(30)))
+		}
+	})
+
+	t.Run("should reject queries outside the limits", func(t *testing.T) {
+		reportService := NewReportService(new(MockTransactionRepository), calendarMonths(), nil)
+		sum := []repository.ReportMeasure{repository.MeasureSum}
+
+		_, err := reportService.Query(ctx, userId, repository.ReportQuery{GroupBy: []repository.ReportDimension{"color"}, Measures: sum}, false)
+		assert.ErrorIs(t, err, ErrInvalidReportGroupBy)
+
+		for _, dimension := range []repository.ReportDimension{"parent_category", "payee", "tag"} {
+			_, err = reportService.Query(ctx, userId, repository.ReportQuery{GroupBy: []repository.ReportDimension{dimension}, Measures: sum}, false)
+			assert.ErrorIs(t, err, ErrUnavailableReportDimension)
+		}
+
+		_, err = reportService.Query(ctx, userId, repository.ReportQuery{GroupBy: []repository.ReportDimension{repository.DimensionDay, repository.DimensionDay}, Measures: sum}, false)
+		assert.ErrorIs(t, err, ErrInvalidReportGroupBy)
+
+		_, err = reportService.Query(ctx, userId, repository.ReportQuery{Measures: []repository.ReportMeasure{"median"}}, false)
+		assert.ErrorIs(t, err, ErrInvalidReportMeasures)
+
+		_, err = reportService.Query(ctx, userId, repository.ReportQuery{GroupBy: []repository.ReportDimension{repository.DimensionMonth}, Measures: sum}, true)
+		assert.ErrorIs(t, err, ErrInvalidReportPivot)
+	})
+}
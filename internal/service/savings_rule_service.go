@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrSavingsRuleNotFound       = errors.New("savings rule not found")
+	ErrSavingsTransferNotAllowed = errors.New("savings rules can only move money from a checking, savings or other account into a savings account")
+	ErrInvalidSavingsRule        = errors.New("round-up rules need a checking or credit card account and a funding account; percent-of-income rules need an income category and a percentage up to 100; both save into a savings account")
+)
+
+// SavingsRuleService moves money into savings accounts automatically as
+// transactions are created. It is registered as a TransactionService hook.
+type SavingsRuleService struct {
+	repo         repository.SavingsRuleRepository
+	accountRepo  repository.AccountRepository
+	categoryRepo repository.CategoryRepository
+	quotas       *QuotaService
+	now          func() time.Time
+}
+
+// NewSavingsRuleService creates a new instance of SavingsRuleService.
+func NewSavingsRuleService(repo repository.SavingsRuleRepository, accountRepo repository.AccountRepository, categoryRepo repository.CategoryRepository, quotas *QuotaService) *SavingsRuleService {
+	return &SavingsRuleService{
+		repo:         repo,
+		accountRepo:  accountRepo,
+		categoryRepo: categoryRepo,
+		quotas:       quotas,
+		now:          time.Now,
+	}
+}
+
+// CreateRule validates and stores a savings rule. Round-ups of a checking
+// account are paid from the account itself unless another one is given.
+func (s *SavingsRuleService) CreateRule(ctx context.Context, rule model.SavingsRule) (*model.SavingsRule, error) {
+	savings, err := s.ruleAccount(ctx, rule.UserId, rule.SavingsAccountId)
+	if err != nil {
+		return nil, err
+	}
+	if savings.Type != model.Savings {
+		return nil, ErrInvalidSavingsRule
+	}
+
+	switch rule.Type {
+	case model.SavingsRoundUp:
+		if rule.AccountId == nil || rule.CategoryId != nil || rule.Percent != nil {
+			return nil, ErrInvalidSavingsRule
+		}
+		account, err := s.ruleAccount(ctx, rule.UserId, *rule.AccountId)
+		if err != nil {
+			return nil, err
+		}
+		if account.Type != model.Checking && account.Type != model.CreditCard {
+			return nil, ErrInvalidSavingsRule
+		}
+		if rule.FundingAccountId == nil {
+			if account.Type != model.Checking {
+				return nil, ErrInvalidSavingsRule
+			}
+			rule.FundingAccountId = &account.Id
+		}
+		funding, err := s.ruleAccount(ctx, rule.UserId, *rule.FundingAccountId)
+		if err != nil {
+			return nil, err
+		}
+		if !canFundSavings(funding) || funding.Id == savings.Id {
+			return nil, ErrInvalidSavingsRule
+		}
+	case model.SavingsPercentOfIncome:
+		if rule.CategoryId == nil || rule.Percent == nil || rule.FundingAccountId != nil {
+			return nil, ErrInvalidSavingsRule
+		}
+		if !rule.Percent.IsPositive() || rule.Percent.GreaterThan(decimal.NewFromInt(100)) {
+			return nil, ErrInvalidSavingsRule
+		}
+		category, err := s.categoryRepo.GetById(ctx, *rule.CategoryId, rule.UserId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, ErrInvalidSavingsRule
+			}
+			return nil, err
+		}
+		if category.Type != model.Income {
+			return nil, ErrInvalidSavingsRule
+		}
+		if rule.AccountId != nil {
+			account, err := s.ruleAccount(ctx, rule.UserId, *rule.AccountId)
+			if err != nil {
+				return nil, err
+			}
+			if !canFundSavings(account) || account.Id == savings.Id {
+				return nil, ErrInvalidSavingsRule
+			}
+		}
+	default:
+		return nil, ErrInvalidSavingsRule
+	}
+
+	rule.Id, err = s.repo.CreateRule(ctx, rule)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// ListRules returns the user's savings rules.
+func (s *SavingsRuleService) ListRules(ctx context.Context, userId int64) ([]model.SavingsRule, error) {
+	return s.repo.ListRules(ctx, userId)
+}
+
+// DeleteRule removes a savings rule. Money it already moved stays saved.
+func (s *SavingsRuleService) DeleteRule(ctx context.Context, id, userId int64) error {
+	if err := s.repo.DeleteRule(ctx, id, userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSavingsRuleNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ListRuns returns what a rule did, newest first.
+func (s *SavingsRuleService) ListRuns(ctx context.Context, userId, ruleId int64) ([]model.SavingsRuleRun, error) {
+	runs, err := s.repo.ListRuns(ctx, userId, ruleId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSavingsRuleNotFound
+	}
+	return runs, err
+}
+
+// TransactionCreated runs the user's rules on a new transaction. Expenses are
+// rounded up for the daily settlement, and income is saved right away. A
+// transaction triggers each rule once. Income that cannot be moved, because of
+// the funding account's overdraft limit or the user's quota, is logged as an
+// unsettled run and is not retried.
+func (s *SavingsRuleService) TransactionCreated(ctx context.Context, tx model.Transaction) error {
+	if tx.Type == model.Transfer {
+		return nil
+	}
+	rules, err := s.repo.ListRulesToRun(ctx, tx.UserId)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, rule := range rules {
+		if err := s.run(ctx, rule, tx); err != nil {
+			errs = append(errs, fmt.Errorf("savings rule %d: %w", rule.Id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *SavingsRuleService) run(ctx context.Context, rule model.SavingsRule, tx model.Transaction) error {
+	run := model.SavingsRuleRun{
+		RuleId:        rule.Id,
+		UserId:        tx.UserId,
+		TransactionId: tx.Id,
+		OccurredOn:    dateOnly(tx.Date),
+	}
+
+	switch rule.Type {
+	case model.SavingsRoundUp:
+		if tx.Type != model.Expense || rule.AccountId == nil || tx.AccountId != *rule.AccountId {
+			return nil
+		}
+		run.Amount = roundUp(tx.Amount)
+		if !run.Amount.IsPositive() {
+			return nil
+		}
+		_, err := s.repo.RecordRun(ctx, run, nil)
+		return err
+
+	case model.SavingsPercentOfIncome:
+		if tx.Type != model.Income || tx.CategoryId == nil || rule.CategoryId == nil || *tx.CategoryId != *rule.CategoryId {
+			return nil
+		}
+		if (rule.AccountId != nil && tx.AccountId != *rule.AccountId) || tx.AccountId == rule.SavingsAccountId {
+			return nil
+		}
+		account, err := s.accountRepo.GetById(ctx, tx.AccountId, tx.UserId)
+		if err != nil {
+			return err
+		}
+		if !canFundSavings(account) {
+			return nil
+		}
+		run.Amount = tx.Amount.Mul(percentOf(*rule.Percent)).Round(2)
+		if !run.Amount.IsPositive() {
+			return nil
+		}
+		transfer := &model.Transaction{
+			UserId:               tx.UserId,
+			Description:          fmt.Sprintf("Savings: %s%% of %s", rule.Percent.String(), tx.Description),
+			Amount:               run.Amount,
+			Date:                 tx.Date,
+			Type:                 model.Transfer,
+			AccountId:            tx.AccountId,
+			DestinationAccountId: &rule.SavingsAccountId,
+		}
+		if err := s.checkTransfer(ctx, *transfer); err != nil {
+			if !isTransferRejection(err) {
+				return err
+			}
+			zerolog.Ctx(ctx).Warn().Err(err).Int64("ruleId", rule.Id).Int64("transactionId", tx.Id).Msg("savings rule transfer not made")
+			transfer = nil
+		}
+		_, err = s.repo.RecordRun(ctx, run, transfer)
+		return err
+	}
+	return nil
+}
+
+// SettleRoundUps moves the round-ups of the days before today into savings,
+// with one transfer per rule and day. It is run periodically; a round-up is
+// moved once. Days the funding account cannot pay for, because of its
+// overdraft limit or the user's quota, stay pending for the next run.
+func (s *SavingsRuleService) SettleRoundUps(ctx context.Context) (int, error) {
+	logger := zerolog.Ctx(ctx)
+	today := dateOnly(s.now())
+
+	pending, err := s.repo.ListPendingRoundUps(ctx, today)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	for _, day := range pending {
+		rule, err := s.repo.GetRule(ctx, day.RuleId, day.UserId)
+		if err != nil {
+			logger.Error().Err(err).Int64("ruleId", day.RuleId).Msg("failed to settle round-ups")
+			continue
+		}
+		transfer := model.Transaction{
+			UserId:               rule.UserId,
+			Description:          "Round-ups of " + day.OccurredOn.Format(time.DateOnly),
+			Amount:               day.Amount,
+			Date:                 day.OccurredOn,
+			Type:                 model.Transfer,
+			AccountId:            *rule.FundingAccountId,
+			DestinationAccountId: &rule.SavingsAccountId,
+		}
+		if err := s.checkTransfer(ctx, transfer); err != nil {
+			logger.Warn().Err(err).Int64("ruleId", day.RuleId).Msg("round-ups left pending")
+			continue
+		}
+		amount, err := s.repo.SettleRoundUps(ctx, rule.Id, day.RunIds, transfer)
+		if err != nil {
+			logger.Error().Err(err).Int64("ruleId", day.RuleId).Msg("failed to settle round-ups")
+			continue
+		}
+		if amount.IsPositive() {
+			settled++
+		}
+	}
+	return settled, nil
+}
+
+// checkTransfer applies to a rule's transfer the checks TransactionService
+// makes on transfers: the user's quota, the account types and the overdraft
+// limit of the funding account.
+func (s *SavingsRuleService) checkTransfer(ctx context.Context, transfer model.Transaction) error {
+	if err := s.quotas.CheckTransactionCreation(ctx, transfer.UserId, 1); err != nil {
+		return err
+	}
+	source, err := s.accountRepo.GetById(ctx, transfer.AccountId, transfer.UserId)
+	if err != nil {
+		return err
+	}
+	destination, err := s.accountRepo.GetById(ctx, *transfer.DestinationAccountId, transfer.UserId)
+	if err != nil {
+		return err
+	}
+	if !canFundSavings(source) || destination.Type != model.Savings {
+		return ErrSavingsTransferNotAllowed
+	}
+	if source.Type == model.Checking && source.OverdraftLimit != nil {
+		balance, err := s.accountRepo.GetCurrentBalance(ctx, source.Id, transfer.UserId)
+		if err != nil {
+			return err
+		}
+		return checkOverdraft(source, balance, transfer)
+	}
+	return nil
+}
+
+// isTransferRejection reports whether checkTransfer refused the transfer, as
+// opposed to failing to check it.
+func isTransferRejection(err error) bool {
+	var quotaErr *QuotaExceededError
+	return errors.Is(err, ErrOverdraftLimitExceeded) || errors.Is(err, ErrSavingsTransferNotAllowed) || errors.As(err, &quotaErr)
+}
+
+// ruleAccount returns one of the user's accounts named by a rule.
+func (s *SavingsRuleService) ruleAccount(ctx context.Context, userId, accountId int64) (*model.Account, error) {
+	account, err := s.accountRepo.GetById(ctx, accountId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidSavingsRule
+		}
+		return nil, err
+	}
+	return account, nil
+}
+
+// canFundSavings reports whether money can be transferred out of the account
+// into savings.
+func canFundSavings(account *model.Account) bool {
+	switch account.Type {
+	case model.Checking, model.Savings, model.Other:
+		return true
+	}
+	return false
+}
+
+// roundUp is how much is missing for the amount to reach the next whole unit.
+func roundUp(amount decimal.Decimal) decimal.Decimal {
+	return amount.Ceil().Sub(amount)
+}
+
+// dateOnly truncates a time to its UTC day.
+func dateOnly(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/testhelper"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSavingsRuleRepository is a mock for the SavingsRuleRepository interface.
+type MockSavingsRuleRepository struct {
+	mock.Mock
+}
+
+func (m *MockSavingsRuleRepository) CreateRule(ctx context.Context, rule model.SavingsRule) (int64, error) {
+	args := m.Called(ctx, rule)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSavingsRuleRepository) GetRule(ctx context.Context, id, userId int64) (*model.SavingsRule, error) {
+	args := m.Called(ctx, id, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SavingsRule), args.Error(1)
+}
+
+func (m *MockSavingsRuleRepository) ListRules(ctx context.Context, userId int64) ([]model.SavingsRule, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SavingsRule), args.Error(1)
+}
+
+func (m *MockSavingsRuleRepository) ListRulesToRun(ctx context.Context, userId int64) ([]model.SavingsRule, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SavingsRule), args.Error(1)
+}
+
+func (m *MockSavingsRuleRepository) DeleteRule(ctx context.Context, id, userId int64) error {
+	args := m.Called(ctx, id, userId)
+	return args.Error(0)
+}
+
+func (m *MockSavingsRuleRepository) RecordRun(ctx context.Context, run model.SavingsRuleRun, transfer *model.Transaction) (bool, error) {
+	args := m.Called(ctx, run, transfer)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSavingsRuleRepository) ListRuns(ctx context.Context, userId, ruleId int64) ([]model.SavingsRuleRun, error) {
+	args := m.Called(ctx, userId, ruleId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SavingsRuleRun), args.Error(1)
+}
+
+func (m *MockSavingsRuleRepository) ListPendingRoundUps(ctx context.Context, before time.Time) ([]model.PendingRoundUp, error) {
+	args := m.Called(ctx, before)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PendingRoundUp), args.Error(1)
+}
+
+func (m *MockSavingsRuleRepository) SettleRoundUps(ctx context.Context, ruleId int64, runIds []int64, transfer model.Transaction) (decimal.Decimal, error) {
+	args := m.Called(ctx, ruleId, runIds, transfer)
+	return args.Get(0).(decimal.Decimal), args.Error(1)
+}
+
+func TestSavingsRuleService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2025, time.July, d, 0, 0, 0, 0, time.UTC) }
+	checking := &model.Account{Id: 10, UserId: 1, Type: model.Checking}
+	card := &model.Account{Id: 11, UserId: 1, Type: model.CreditCard}
+	savings := &model.Account{Id: 20, UserId: 1, Type: model.Savings}
+	salary := &model.Category{Id: 5, UserId: 1, Name: "Salary", Type: model.Income}
+	roundUpRule := model.SavingsRule{Id: 1, UserId: 1, Type: model.SavingsRoundUp, SavingsAccountId: savings.Id, AccountId: &card.Id, FundingAccountId: &checking.Id}
+	incomeRule := model.SavingsRule{Id: 2, UserId: 1, Type: model.SavingsPercentOfIncome, SavingsAccountId: savings.Id, CategoryId: &salary.Id, Percent: testhelper.Ptr(decimal.NewFromInt(10))}
+
+	setup := func() (*SavingsRuleService, *MockSavingsRuleRepository, *MockAccountRepository, *MockCategoryRepository) {
+		mockRepo := new(MockSavingsRuleRepository)
+		mockAccountRepo := new(MockAccountRepository)
+		mockCategoryRepo := new(MockCategoryRepository)
+		for _, account := range []*model.Account{checking, card, savings} {
+			mockAccountRepo.On("GetById", ctx, account.Id, int64(1)).Return(account, nil).Maybe()
+		}
+		mockCategoryRepo.On("GetById", ctx, salary.Id, int64(1)).Return(salary, nil).Maybe()
+		return NewSavingsRuleService(mockRepo, mockAccountRepo, mockCategoryRepo, unlimitedQuotas()), mockRepo, mockAccountRepo, mockCategoryRepo
+	}
+
+	t.Run("should fund round-ups of a checking account from the account itself", func(t *testing.T) {
+		savingsService, mockRepo, _, _ := setup()
+		mockRepo.On("CreateRule", ctx, mock.MatchedBy(func(rule model.SavingsRule) bool {
+			return rule.FundingAccountId != nil && *rule.FundingAccountId == checking.Id
+		})).Return(int64(3), nil).Once()
+
+		rule, err := savingsService.CreateRule(ctx, model.SavingsRule{UserId: 1, Type: model.SavingsRoundUp, SavingsAccountId: savings.Id, AccountId: &checking.Id})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), rule.Id)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject invalid rules", func(t *testing.T) {
+		testCases := []struct {
+			name string
+			rule model.SavingsRule
+		}{
+			{name: "card round-ups without a funding account", rule: model.SavingsRule{Type: model.SavingsRoundUp, SavingsAccountId: savings.Id, AccountId: &card.Id}},
+			{name: "round-ups funded by a card", rule: model.SavingsRule{Type: model.SavingsRoundUp, SavingsAccountId: savings.Id, AccountId: &checking.Id, FundingAccountId: &card.Id}},
+			{name: "round-ups of a savings account", rule: model.SavingsRule{Type: model.SavingsRoundUp, SavingsAccountId: savings.Id, AccountId: &savings.Id}},
+			{name: "saving into a checking account", rule: model.SavingsRule{Type: model.SavingsPercentOfIncome, SavingsAccountId: checking.Id, CategoryId: &salary.Id, Percent: testhelper.Ptr(decimal.NewFromInt(10))}},
+			{name: "more than 100% of income", rule: model.SavingsRule{Type: model.SavingsPercentOfIncome, SavingsAccountId: savings.Id, CategoryId: &salary.Id, Percent: testhelper.Ptr(decimal.NewFromInt(101))}},
+			{name: "percent of income without a category", rule: model.SavingsRule{Type: model.SavingsPercentOfIncome, SavingsAccountId: savings.Id, Percent: testhelper.Ptr(decimal.NewFromInt(10))}},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				savingsService, mockRepo, _, _ := setup()
+				tc.rule.UserId = 1
+
+				_, err := savingsService.CreateRule(ctx, tc.rule)
+
+				assert.ErrorIs(t, err, ErrInvalidSavingsRule)
+				mockRepo.AssertNotCalled(t, "CreateRule", mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("should round card expenses up to the next whole unit", func(t *testing.T) {
+		savingsService, mockRepo, _, _ := setup()
+		mockRepo.On("ListRulesToRun", ctx, int64(1)).Return([]model.SavingsRule{roundUpRule, incomeRule}, nil).Once()
+		mockRepo.On("RecordRun", ctx, model.SavingsRuleRun{
+			RuleId: roundUpRule.Id, UserId: 1, TransactionId: 100, Amount: decimal.RequireFromString("0.25"), OccurredOn: day(3),
+		}, (*model.Transaction)(nil)).Return(true, nil).Once()
+
+		err := savingsService.TransactionCreated(ctx, model.Transaction{
+			Id: 100, UserId: 1, AccountId: card.Id, Type: model.Expense, Amount: decimal.RequireFromString("12.75"), Date: day(3).Add(15 * time.Hour),
+		})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not round up whole amounts", func(t *testing.T) {
+		savingsService, mockRepo, _, _ := setup()
+		mockRepo.On("ListRulesToRun", ctx, int64(1)).Return([]model.SavingsRule{roundUpRule}, nil).Once()
+
+		err := savingsService.TransactionCreated(ctx, model.Transaction{Id: 101, UserId: 1, AccountId: card.Id, Type: model.Expense, Amount: decimal.NewFromInt(30), Date: day(3)})
+
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "RecordRun", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should save a percentage of income in the category right away", func(t *testing.T) {
+		savingsService, mockRepo, _, _ := setup()
+		mockRepo.On("ListRulesToRun", ctx, int64(1)).Return([]model.SavingsRule{roundUpRule, incomeRule}, nil).Once()
+		var transfer *model.Transaction
+		mockRepo.On("RecordRun", ctx, mock.MatchedBy(func(run model.SavingsRuleRun) bool {
+			return run.RuleId == incomeRule.Id && run.TransactionId == 200
+		}), mock.Anything).Run(func(args mock.Arguments) {
+			transfer = args.Get(2).(*model.Transaction)
+		}).Return(true, nil).Once()
+
+		err := savingsService.TransactionCreated(ctx, model.Transaction{
+			Id: 200, UserId: 1, AccountId: checking.Id, CategoryId: &salary.Id, Type: model.Income, Amount: decimal.RequireFromString("5432.10"), Date: day(5), Description: "Salary",
+		})
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, transfer) {
+			assert.Equal(t, model.Transfer, transfer.Type)
+			assert.Equal(t, checking.Id, transfer.AccountId)
+			assert.Equal(t, savings.Id, *transfer.DestinationAccountId)
+			assert.True(t, decimal.RequireFromString("543.21").Equal(transfer.Amount), transfer.Amount.String())
+		}
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should ignore income in other categories", func(t *testing.T) {
+		savingsService, mockRepo, _, _ := setup()
+		mockRepo.On("ListRulesToRun", ctx, int64(1)).Return([]model.SavingsRule{incomeRule}, nil).Once()
+		other := int64(6)
+
+		err := savingsService.TransactionCreated(ctx, model.Transaction{Id: 201, UserId: 1, AccountId: checking.Id, CategoryId: &other, Type: model.Income, Amount: decimal.NewFromInt(100), Date: day(5)})
+
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "RecordRun", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should log income it cannot move past the funding account's overdraft limit", func(t *testing.T) {
+		// Arrange: the checking account is already 500 into its 500 overdraft.
+		savingsService, mockRepo, mockAccountRepo, _ := setup()
+		overdrawn := &model.Account{Id: 12, UserId: 1, Type: model.Checking, OverdraftLimit: testhelper.Ptr(decimal.NewFromInt(500))}
+		mockAccountRepo.On("GetById", ctx, overdrawn.Id, int64(1)).Return(overdrawn, nil)
+		mockAccountRepo.On("GetCurrentBalance", ctx, overdrawn.Id, int64(1)).Return(decimal.NewFromInt(-500), nil).Once()
+		mockRepo.On("ListRulesToRun", ctx, int64(1)).Return([]model.SavingsRule{incomeRule}, nil).Once()
+		mockRepo.On("RecordRun", ctx, mock.MatchedBy(func(run model.SavingsRuleRun) bool {
+			return run.RuleId == incomeRule.Id && decimal.NewFromInt(10).Equal(run.Amount)
+		}), (*model.Transaction)(nil)).Return(true, nil).Once()
+
+		// Act
+		err := savingsService.TransactionCreated(ctx, model.Transaction{
+			Id: 202, UserId: 1, AccountId: overdrawn.Id, CategoryId: &salary.Id, Type: model.Income, Amount: decimal.NewFromInt(100), Date: day(5),
+		})
+
+		// Assert: the run is logged without a transfer.
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should leave round-ups pending while the funding account is at its overdraft limit", func(t *testing.T) {
+		// Arrange
+		savingsService, mockRepo, mockAccountRepo, _ := setup()
+		savingsService.now = func() time.Time { return day(8) }
+		overdrawn := &model.Account{Id: 12, UserId: 1, Type: model.Checking, OverdraftLimit: testhelper.Ptr(decimal.NewFromInt(500))}
+		rule := roundUpRule
+		rule.FundingAccountId = &overdrawn.Id
+		mockAccountRepo.On("GetById", ctx, overdrawn.Id, int64(1)).Return(overdrawn, nil)
+		mockAccountRepo.On("GetCurrentBalance", ctx, overdrawn.Id, int64(1)).Return(decimal.NewFromInt(-500), nil).Once()
+		mockRepo.On("ListPendingRoundUps", ctx, day(8)).Return([]model.PendingRoundUp{
+			{RuleId: rule.Id, UserId: 1, OccurredOn: day(7), Amount: decimal.RequireFromString("1.40")},
+		}, nil).Once()
+		mockRepo.On("GetRule", ctx, rule.Id, int64(1)).Return(&rule, nil).Once()
+
+		// Act
+		settled, err := savingsService.SettleRoundUps(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Zero(t, settled)
+		mockRepo.AssertNotCalled(t, "SettleRoundUps", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should settle each day of round-ups with one transfer", func(t *testing.T) {
+		savingsService, mockRepo, _, _ := setup()
+		savingsService.now = func() time.Time { return day(8).Add(2 * time.Hour) }
+		mockRepo.On("ListPendingRoundUps", ctx, day(8)).Return([]model.PendingRoundUp{
+			{RuleId: roundUpRule.Id, UserId: 1, OccurredOn: day(6), Amount: decimal.RequireFromString("1.40"), RunIds: pq.Int64Array{60, 61}},
+			{RuleId: roundUpRule.Id, UserId: 1, OccurredOn: day(7), Amount: decimal.RequireFromString("0.60"), RunIds: pq.Int64Array{70}},
+		}, nil).Once()
+		mockRepo.On("GetRule", ctx, roundUpRule.Id, int64(1)).Return(&roundUpRule, nil).Twice()
+		for d, runIds := range map[int][]int64{6: {60, 61}, 7: {70}} {
+			mockRepo.On("SettleRoundUps", ctx, roundUpRule.Id, runIds, mock.MatchedBy(func(transfer model.Transaction) bool {
+				return transfer.AccountId == checking.Id && *transfer.DestinationAccountId == savings.Id && transfer.Date.Equal(day(d))
+			})).Return(decimal.NewFromInt(1), nil).Once()
+		}
+
+		settled, err := savingsService.SettleRoundUps(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, settled)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTransactionServiceHooks(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	checking := &model.Account{Id: 10, UserId: 1, Type: model.Checking}
+
+	t.Run("should run the hooks with the created transaction and ignore their errors", func(t *testing.T) {
+		// Arrange
+		mockAccountRepo := new(MockAccountRepository)
+		mockTxRepo := new(MockTransactionRepository)
+		mockRulesRepo := new(MockSavingsRuleRepository)
+		txService := NewTransactionService(mockTxRepo, mockAccountRepo, unlimitedQuotas(), nil, TransactionOptions{})
+		txService.AddHook(NewSavingsRuleService(mockRulesRepo, mockAccountRepo, new(MockCategoryRepository), unlimitedQuotas()))
+		mockAccountRepo.On("GetById", ctx, checking.Id, int64(1)).Return(checking, nil).Once()
+		mockTxRepo.On("Create", ctx, mock.Anything).Return(int64(42), nil).Once()
+		mockRulesRepo.On("ListRulesToRun", ctx, int64(1)).Return(nil, errors.New("connection refused")).Once()
+
+		// Act
+		id, err := txService.CreateTransaction(ctx, model.Transaction{UserId: 1, AccountId: checking.Id, Type: model.Expense, Amount: decimal.NewFromInt(10), Date: time.Now()})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), id)
+		mockRulesRepo.AssertExpectations(t)
+	})
+}
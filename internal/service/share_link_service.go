@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/ratelimit"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/rs/zerolog"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrShareLinkNotFound         = errors.New("share link not found or no longer available")
+	ErrShareLinkPasswordRequired = errors.New("a valid password is required to open this share link")
+	ErrShareLinkTooManyAttempts  = errors.New("too many wrong passwords for this share link, try again later")
+	ErrInvalidShareLinkScope     = errors.New("scope must be one of: monthly_report, statement, transactions")
+	ErrInvalidShareLinkParams    = errors.New("invalid share link parameters")
+	ErrShareLinkTTLTooLong       = errors.New("share link expiration exceeds the maximum allowed")
+)
+
+// shareLinkPasswordAttempts is how many wrong passwords a link accepts per window.
+const (
+	shareLinkPasswordAttempts = 10
+	shareLinkPasswordWindow   = 15 * time.Minute
+)
+
+// ShareLinkOptions configures the share links.
+type ShareLinkOptions struct {
+	// BaseURL is the public address of the shared content endpoint; the token is appended to it.
+	BaseURL    string
+	DefaultTTL time.Duration
+	MaxTTL     time.Duration
+}
+
+// CreateShareLinkInput describes a new share link. A zero ExpiresIn uses the
+// default TTL and an empty Password leaves the link unprotected.
+type CreateShareLinkInput struct {
+	Scope     model.ShareLinkScope
+	Params    model.ShareLinkParams
+	ExpiresIn time.Duration
+	Password  string
+}
+
+// SharedContent is what a share link resolves to. Only the field matching the
+// link scope is set.
+type SharedContent struct {
+	Link          model.ShareLink
+	MonthlyReport *MonthlyReport
+	Statement     *StatementDetails
+	Transactions  []model.Transaction
+}
+
+// ShareLinkService manages read-only links to reports, statements and
+// transaction filters that can be opened without logging in.
+type ShareLinkService struct {
+	repo             repository.ShareLinkRepository
+	transactionRepo  repository.TransactionRepository
+	accountService   *AccountService
+	reportService    *ReportService
+	passwordAttempts *ratelimit.Limiter
+	options          ShareLinkOptions
+}
+
+// NewShareLinkService creates a new instance of ShareLinkService.
+func NewShareLinkService(
+	repo repository.ShareLinkRepository,
+	transactionRepo repository.TransactionRepository,
+	accountService *AccountService,
+	reportService *ReportService,
+	options ShareLinkOptions,
+) *ShareLinkService {
+	return &ShareLinkService{
+		repo:             repo,
+		transactionRepo:  transactionRepo,
+		accountService:   accountService,
+		reportService:    reportService,
+		passwordAttempts: ratelimit.New(shareLinkPasswordAttempts, shareLinkPasswordWindow),
+		options:          options,
+	}
+}
+
+// CreateLink validates the scope, stores a new link and returns it along with
+// its token. The token is only available at this point.
+func (s *ShareLinkService) CreateLink(ctx context.Context, userId int64, input CreateShareLinkInput) (*model.ShareLink, string, error) {
+	ttl := input.ExpiresIn
+	if ttl <= 0 {
+		ttl = s.options.DefaultTTL
+	}
+	if s.options.MaxTTL > 0 && ttl > s.options.MaxTTL {
+		return nil, "", ErrShareLinkTTLTooLong
+	}
+
+	link := model.ShareLink{
+		UserId:    userId,
+		Scope:     input.Scope,
+		Params:    input.Params,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := validateShareLinkParams(link.Scope, link.Params); err != nil {
+		return nil, "", err
+	}
+	// Resolving the content once makes sure it exists and belongs to the user.
+	if _, err := s.loadContent(ctx, link); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, "", fmt.Errorf("%w: account not found", ErrInvalidShareLinkParams)
+		}
+		if errors.Is(err, ErrStatementRequiresCreditCard) {
+			return nil, "", fmt.Errorf("%w: %w", ErrInvalidShareLinkParams, err)
+		}
+		return nil, "", err
+	}
+
+	if input.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, "", err
+		}
+		passwordHash := string(hash)
+		link.PasswordHash = &passwordHash
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate share link token: %w", err)
+	}
+	link.TokenHash = hashToken(token)
+
+	id, err := s.repo.Create(ctx, link)
+	if err != nil {
+		return nil, "", err
+	}
+	link.Id = id
+	link.CreatedAt = time.Now()
+	return &link, token, nil
+}
+
+// URL returns the public address of a link.
+func (s *ShareLinkService) URL(token string) string {
+	return strings.TrimSuffix(s.options.BaseURL, "/") + "/" + token
+}
+
+// ListLinks returns the user's links, including expired and revoked ones.
+func (s *ShareLinkService) ListLinks(ctx context.Context, userId int64) ([]model.ShareLink, error) {
+	return s.repo.ListByUserId(ctx, userId)
+}
+
+// RevokeLink disables a link immediately.
+func (s *ShareLinkService) RevokeLink(ctx context.Context, id, userId int64) error {
+	if err := s.repo.Revoke(ctx, id, userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrShareLinkNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// OpenLink resolves a token to the shared content. Unknown, expired and revoked
+// links are indistinguishable to the caller. Every successful access is counted.
+func (s *ShareLinkService) OpenLink(ctx context.Context, token, password string) (*SharedContent, error) {
+	logger := zerolog.Ctx(ctx)
+
+	link, err := s.repo.GetByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrShareLinkNotFound
+		}
+		return nil, err
+	}
+	if link.RevokedAt != nil || !time.Now().Before(link.ExpiresAt) {
+		return nil, ErrShareLinkNotFound
+	}
+
+	if link.PasswordHash != nil {
+		key := strconv.FormatInt(link.Id, 10)
+		if s.passwordAttempts.Count(key) >= shareLinkPasswordAttempts {
+			return nil, ErrShareLinkTooManyAttempts
+		}
+		if bcrypt.CompareHashAndPassword([]byte(*link.PasswordHash), []byte(password)) != nil {
+			s.passwordAttempts.Allow(key)
+			return nil, ErrShareLinkPasswordRequired
+		}
+	}
+
+	content, err := s.loadContent(ctx, *link)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RecordAccess(ctx, link.Id); err != nil {
+		logger.Error().Err(err).Int64("share_link_id", link.Id).Msg("failed to record share link access")
+	} else {
+		content.Link.AccessCount++
+		now := time.Now()
+		content.Link.LastAccessedAt = &now
+	}
+	logger.Info().
+		Int64("share_link_id", link.Id).
+		Str("scope", string(link.Scope)).
+		Int64("access_count", content.Link.AccessCount).
+		Msg("share link accessed")
+
+	return content, nil
+}
+
+// loadContent builds the content a link points to, on behalf of its owner.
+func (s *ShareLinkService) loadContent(ctx context.Context, link model.ShareLink) (*SharedContent, error) {
+	content := &SharedContent{Link: link}
+	var err error
+
+	switch link.Scope {
+	case model.ShareScopeMonthlyReport:
+		content.MonthlyReport, err = s.reportService.GetMonthlyReport(ctx, link.UserId, link.Params.Year, link.Params.Month)
+	case model.ShareScopeStatement:
+		content.Statement, err = s.accountService.GetStatementDetails(ctx, link.UserId, *link.Params.AccountId, link.Params.Year, link.Params.Month)
+	case model.ShareScopeTransactions:
+		filters, _ := toListTransactionFilters(link.Params.Filter)
+		content.Transactions, err = s.transactionRepo.List(ctx, link.UserId, filters)
+	default:
+		err = ErrInvalidShareLinkScope
+	}
+	if err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func validateShareLinkParams(scope model.ShareLinkScope, params model.ShareLinkParams) error {
+	switch scope {
+	case model.ShareScopeMonthlyReport:
+		if !validReportPeriod(params.Year, params.Month) {
+			return fmt.Errorf("%w: %w", ErrInvalidShareLinkParams, ErrInvalidReportPeriod)
+		}
+	case model.ShareScopeStatement:
+		if params.AccountId == nil {
+			return fmt.Errorf("%w: account_id is required for statements", ErrInvalidShareLinkParams)
+		}
+		if !validReportPeriod(params.Year, params.Month) {
+			return fmt.Errorf("%w: %w", ErrInvalidShareLinkParams, ErrInvalidReportPeriod)
+		}
+	case model.ShareScopeTransactions:
+		if _, err := toListTransactionFilters(params.Filter); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidShareLinkParams, err)
+		}
+	default:
+		return ErrInvalidShareLinkScope
+	}
+	return nil
+}
+
+// toListTransactionFilters converts a saved filter into repository filters.
+// The end date includes the whole day, as in the transactions endpoint.
+func toListTransactionFilters(filter *model.TransactionFilter) (repository.ListTransactionFilters, error) {
+	var filters repository.ListTransactionFilters
+	if filter == nil {
+		return filters, nil
+	}
+
+	filters.Description = filter.Description
+	filters.Type = filter.Type
+	filters.AccountId = filter.AccountId
+	filters.CategoryIds = filter.CategoryIds
+	if filter.StartDate != nil {
+		startDate, err := time.Parse(time.DateOnly, *filter.StartDate)
+		if err != nil {
+			return filters, errors.New("start_date must use the YYYY-MM-DD format")
+		}
+		filters.StartDate = &startDate
+	}
+	if filter.EndDate != nil {
+		endDate, err := time.Parse(time.DateOnly, *filter.EndDate)
+		if err != nil {
+			return filters, errors.New("end_date must use the YYYY-MM-DD format")
+		}
+		endOfDay := endDate.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+		filters.EndDate = &endOfDay
+	}
+	return filters, nil
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/testhelper"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MockShareLinkRepository is a mock implementation of the ShareLinkRepository interface.
+type MockShareLinkRepository struct {
+	mock.Mock
+}
+
+func (m *MockShareLinkRepository) Create(ctx context.Context, link model.ShareLink) (int64, error) {
+	args := m.Called(ctx, link)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockShareLinkRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*model.ShareLink, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ShareLink), args.Error(1)
+}
+
+func (m *MockShareLinkRepository) ListByUserId(ctx context.Context, userId int64) ([]model.ShareLink, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ShareLink), args.Error(1)
+}
+
+func (m *MockShareLinkRepository) Revoke(ctx context.Context, id, userId int64) error {
+	args := m.Called(ctx, id, userId)
+	return args.Error(0)
+}
+
+func (m *MockShareLinkRepository) RecordAccess(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestShareLinkService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	userId := int64(1)
+	options := ShareLinkOptions{BaseURL: "https://app.test/v1/shared/", DefaultTTL: 24 * time.Hour, MaxTTL: 72 * time.Hour}
+
+	setup := func() (*ShareLinkService, *MockShareLinkRepository, *MockTransactionRepository, *MockAccountRepository) {
+		mockShareLinkRepo := new(MockShareLinkRepository)
+		mockTxRepo := new(MockTransactionRepository)
+		mockAccountRepo := new(MockAccountRepository)
+		accountService := NewAccountService(mockAccountRepo, mockTxRepo, unlimitedQuotas())
+		shareLinkService := NewShareLinkService(mockShareLinkRepo, mockTxRepo, accountService, NewReportService(mockTxRepo, calendarMonths(), nil), options)
+		return shareLinkService, mockShareLinkRepo, mockTxRepo, mockAccountRepo
+	}
+
+	t.Run("CreateLink", func(t *testing.T) {
+		t.Run("should store a hashed token and a hashed password", func(t *testing.T) {
+			// Arrange
+			shareLinkService, mockShareLinkRepo, mockTxRepo, _ := setup()
+			mockTxRepo.On("List", ctx, userId, mock.Anything).Return([]model.Transaction{}, nil).Once()
+			var stored model.ShareLink
+			mockShareLinkRepo.On("Create", ctx, mock.AnythingOfType("model.ShareLink")).
+				Run(func(args mock.Arguments) { stored = args.Get(1).(model.ShareLink) }).
+				Return(int64(7), nil).Once()
+
+			// Act
+			link, token, err := shareLinkService.CreateLink(ctx, userId, CreateShareLinkInput{
+				Scope:    model.ShareScopeMonthlyReport,
+				Params:   model.ShareLinkParams{Year: 2025, Month: 7},
+				Password: "secret-pass",
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, int64(7), link.Id)
+			assert.NotEmpty(t, token)
+			assert.Equal(t, hashToken(token), stored.TokenHash)
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(*stored.PasswordHash), []byte("secret-pass")))
+			assert.WithinDuration(t, time.Now().Add(options.DefaultTTL), stored.ExpiresAt, time.Minute)
+			assert.Equal(t, "https://app.test/v1/shared/"+token, shareLinkService.URL(token))
+		})
+
+		t.Run("should reject a statement link without an account", func(t *testing.T) {
+			shareLinkService, mockShareLinkRepo, _, _ := setup()
+
+			_, _, err := shareLinkService.CreateLink(ctx, userId, CreateShareLinkInput{
+				Scope:  model.ShareScopeStatement,
+				Params: model.ShareLinkParams{Year: 2025, Month: 7},
+			})
+
+			assert.ErrorIs(t, err, ErrInvalidShareLinkParams)
+			mockShareLinkRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should reject a statement link for another user's account", func(t *testing.T) {
+			shareLinkService, _, _, mockAccountRepo := setup()
+			mockAccountRepo.On("GetById", ctx, int64(99), userId).Return(nil, sql.ErrNoRows).Once()
+
+			_, _, err := shareLinkService.CreateLink(ctx, userId, CreateShareLinkInput{
+				Scope:  model.ShareScopeStatement,
+				Params: model.ShareLinkParams{Year: 2025, Month: 7, AccountId: testhelper.Ptr(int64(99))},
+			})
+
+			assert.ErrorIs(t, err, ErrInvalidShareLinkParams)
+		})
+
+		t.Run("should reject an expiration beyond the maximum", func(t *testing.T) {
+			shareLinkService, _, _, _ := setup()
+
+			_, _, err := shareLinkService.CreateLink(ctx, userId, CreateShareLinkInput{
+				Scope:     model.ShareScopeTransactions,
+				ExpiresIn: 100 * time.Hour,
+			})
+
+			assert.ErrorIs(t, err, ErrShareLinkTTLTooLong)
+		})
+	})
+
+	t.Run("OpenLink", func(t *testing.T) {
+		token := "shared-token"
+		passwordHash, _ := bcrypt.GenerateFromPassword([]byte("secret-pass"), bcrypt.DefaultCost)
+		activeLink := func() *model.ShareLink {
+			hash := string(passwordHash)
+			return &model.ShareLink{
+				Id:           3,
+				UserId:       userId,
+				Scope:        model.ShareScopeTransactions,
+				PasswordHash: &hash,
+				ExpiresAt:    time.Now().Add(time.Hour),
+			}
+		}
+
+		t.Run("should return the content and record the access", func(t *testing.T) {
+			// Arrange
+			shareLinkService, mockShareLinkRepo, mockTxRepo, _ := setup()
+			mockShareLinkRepo.On("GetByTokenHash", ctx, hashToken(token)).Return(activeLink(), nil).Once()
+			mockTxRepo.On("List", ctx, userId, mock.Anything).Return([]model.Transaction{{Id: 10}}, nil).Once()
+			mockShareLinkRepo.On("RecordAccess", ctx, int64(3)).Return(nil).Once()
+
+			// Act
+			content, err := shareLinkService.OpenLink(ctx, token, "secret-pass")
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, content.Transactions, 1)
+			assert.Equal(t, int64(1), content.Link.AccessCount)
+			assert.NotNil(t, content.Link.LastAccessedAt)
+			mockShareLinkRepo.AssertExpectations(t)
+		})
+
+		t.Run("should require the password without recording an access", func(t *testing.T) {
+			shareLinkService, mockShareLinkRepo, _, _ := setup()
+			mockShareLinkRepo.On("GetByTokenHash", ctx, hashToken(token)).Return(activeLink(), nil).Once()
+
+			_, err := shareLinkService.OpenLink(ctx, token, "wrong")
+
+			assert.ErrorIs(t, err, ErrShareLinkPasswordRequired)
+			mockShareLinkRepo.AssertNotCalled(t, "RecordAccess", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should lock the link after too many wrong passwords", func(t *testing.T) {
+			shareLinkService, mockShareLinkRepo, _, _ := setup()
+			mockShareLinkRepo.On("GetByTokenHash", ctx, hashToken(token)).Return(activeLink(), nil)
+
+			for i := 0; i < shareLinkPasswordAttempts; i++ {
+				_, err := shareLinkService.OpenLink(ctx, token, "wrong")
+				assert.ErrorIs(t, err, ErrShareLinkPasswordRequired)
+			}
+			_, err := shareLinkService.OpenLink(ctx, token, "secret-pass")
+
+			assert.ErrorIs(t, err, ErrShareLinkTooManyAttempts)
+		})
+
+		t.Run("should hide expired and revoked links", func(t *testing.T) {
+			shareLinkService, mockShareLinkRepo, _, _ := setup()
+			expired := activeLink()
+			expired.ExpiresAt = time.Now().Add(-time.Minute)
+			revoked := activeLink()
+			revokedAt := time.Now()
+			revoked.RevokedAt = &revokedAt
+			mockShareLinkRepo.On("GetByTokenHash", ctx, hashToken("expired")).Return(expired, nil).Once()
+			mockShareLinkRepo.On("GetByTokenHash", ctx, hashToken("revoked")).Return(revoked, nil).Once()
+			mockShareLinkRepo.On("GetByTokenHash", ctx, hashToken("unknown")).Return(nil, sql.ErrNoRows).Once()
+
+			for _, candidate := range []string{"expired", "revoked", "unknown"} {
+				_, err := shareLinkService.OpenLink(ctx, candidate, "secret-pass")
+				assert.ErrorIs(t, err, ErrShareLinkNotFound, candidate)
+			}
+		})
+	})
+}
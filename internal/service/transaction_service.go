@@ -9,6 +9,8 @@ import (
 	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
 	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
 	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
 )
 
 // Sentinel errors are used throughout the service to provide specific,
@@ -23,28 +25,70 @@ var (
 	ErrSourceAccountTransferCreditCard = errors.New("transfer transaction is not allowed for source account as credit_card")
 )
 
+// TransactionOptions holds the deployment-specific settings of transactions.
+type TransactionOptions struct {
+	// IOFRate is the percentage of IOF charged on card purchases abroad.
+	IOFRate decimal.Decimal
+}
+
+// TransactionHook is notified of every transaction created through the
+// service, after it is stored. Hooks cannot undo the transaction, so their
+// errors are logged instead of returned.
+type TransactionHook interface {
+	TransactionCreated(ctx context.Context, tx model.Transaction) error
+}
+
 // TransactionService encapsulates the business logic for transactions.
 type TransactionService struct {
 	repo        repository.TransactionRepository
 	accountRepo repository.AccountRepository
+	quotas      *QuotaService
+	cards       *CardService
+	options     TransactionOptions
+	hooks       []TransactionHook
 }
 
 // NewTransactionService creates a new instance of the TransactionService.
-func NewTransactionService(repo repository.TransactionRepository, accountRepo repository.AccountRepository) *TransactionService {
+func NewTransactionService(repo repository.TransactionRepository, accountRepo repository.AccountRepository, quotas *QuotaService, cards *CardService, options TransactionOptions) *TransactionService {
 	return &TransactionService{
 		repo:        repo,
 		accountRepo: accountRepo,
+		quotas:      quotas,
+		cards:       cards,
+		options:     options,
 	}
 }
 
+// AddHook registers a hook to run after each transaction is created.
+func (s *TransactionService) AddHook(hook TransactionHook) {
+	s.hooks = append(s.hooks, hook)
+}
+
 // CreateTransaction handles the business logic for creating a transaction,
 // including validation of accounts and amounts.
 func (s *TransactionService) CreateTransaction(ctx context.Context, tx model.Transaction) (int64, error) {
+	// Purchases abroad are converted to BRL and charged IOF on top.
+	var iof decimal.Decimal
+	if isForeignPurchase(tx) {
+		var err error
+		if iof, err = convertForeignPurchase(&tx, s.options.IOFRate); err != nil {
+			return 0, err
+		}
+	}
+
 	// Business logic validation starts here.
 	if tx.Amount.IsNegative() || tx.Amount.IsZero() {
 		return 0, ErrAmountNotPositive
 	}
 
+	count := int64(1)
+	if iof.IsPositive() {
+		count++
+	}
+	if err := s.quotas.CheckTransactionCreation(ctx, tx.UserId, count); err != nil {
+		return 0, err
+	}
+
 	// Verify that the source account exists and belongs to the user.
 	sourceAccount, err := s.accountRepo.GetById(ctx, tx.AccountId, tx.UserId)
 	if err != nil {
@@ -53,6 +97,27 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, tx model.Tra
 		}
 		return 0, fmt.Errorf("failed to get source account: %w", err)
 	}
+	if sourceAccount.Type == model.Points {
+		return 0, ErrPointsAccountTransaction
+	}
+	if sourceAccount.Type == model.Asset {
+		return 0, ErrAssetAccountTransaction
+	}
+	if err := checkBenefitTransaction(sourceAccount, tx); err != nil {
+		return 0, err
+	}
+	if isForeignPurchase(tx) && (sourceAccount.Type != model.CreditCard || tx.Type != model.Expense) {
+		return 0, ErrForeignPurchaseRequiresCreditCard
+	}
+	// The IOF is charged to the card together with the purchase.
+	charge := tx
+	charge.Amount = tx.Amount.Add(iof)
+	// Purchases made with an additional card also count against its own limit.
+	if tx.CardId != nil {
+		if err := s.cards.CheckTransaction(ctx, sourceAccount, charge); err != nil {
+			return 0, err
+		}
+	}
 
 	// Credit card limit validation for expense transactions
 	if sourceAccount.Type == model.CreditCard && sourceAccount.CreditLimit != nil {
@@ -65,7 +130,7 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, tx model.Tra
 
 		// Calculate what the new balance would be after this expense
 		// Example: current balance -800, expense 50 -> new balance -850
-		newBalance := currentBalance.Sub(tx.Amount)
+		newBalance := currentBalance.Sub(charge.Amount)
 
 		// Check if the absolute value of the new debt exceeds the credit limit
 		// Example: abs(-850) = 850, limit = 1000 -> OK
@@ -82,6 +147,17 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, tx model.Tra
 		}
 	}
 
+	// Checking accounts with an overdraft can only go as far below zero as its limit.
+	if sourceAccount.Type == model.Checking && sourceAccount.OverdraftLimit != nil {
+		currentBalance, err := s.accountRepo.GetCurrentBalance(ctx, tx.AccountId, tx.UserId)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get current balance for overdraft validation: %w", err)
+		}
+		if err := checkOverdraft(sourceAccount, currentBalance, charge); err != nil {
+			return 0, err
+		}
+	}
+
 	// Transfer-specific validations
 	if tx.Type == model.Transfer {
 		// Transfer must have a destination account
@@ -98,16 +174,35 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, tx model.Tra
 		}
 
 		// Verify destination account exists and belongs to the user
-		_, err := s.accountRepo.GetById(ctx, *tx.DestinationAccountId, tx.UserId)
-		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return 0, ErrDestinationAccountNotFound
-			}
+		if err := s.checkDestinationAccount(ctx, tx); err != nil {
 			return 0, err
 		}
 	}
 
-	return s.repo.Create(ctx, tx)
+	var id int64
+	if iof.IsPositive() {
+		id, err = s.repo.CreateWithFee(ctx, tx, iofFee(tx, iof))
+	} else {
+		id, err = s.repo.Create(ctx, tx)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	tx.Id = id
+	s.NotifyCreated(ctx, tx)
+	return id, nil
+}
+
+// NotifyCreated runs the registered hooks on a transaction that was stored by
+// another service, such as a posted paycheck, so it is treated like one
+// created through CreateTransaction.
+func (s *TransactionService) NotifyCreated(ctx context.Context, tx model.Transaction) {
+	for _, hook := range s.hooks {
+		if err := hook.TransactionCreated(ctx, tx); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Int64("transactionId", tx.Id).Msg("transaction hook failed")
+		}
+	}
 }
 
 // GetTransactionById retrieves a single transaction, ensuring it belongs to the specified user.
@@ -123,6 +218,14 @@ func (s *TransactionService) ListTransactions(ctx context.Context, userId int64,
 // UpdateTransaction handles the logic for updating an entire transaction entity.
 // It requires the userId to ensure authorization.
 func (s *TransactionService) UpdateTransaction(ctx context.Context, tx model.Transaction) (*model.Transaction, error) {
+	// Purchases abroad are converted again and charged IOF on the new amount.
+	var iof decimal.Decimal
+	if isForeignPurchase(tx) {
+		var err error
+		if iof, err = convertForeignPurchase(&tx, s.options.IOFRate); err != nil {
+			return nil, err
+		}
+	}
 
 	// Business validations for the new data.
 	if tx.Amount.IsNegative() || tx.Amount.IsZero() {
@@ -136,17 +239,85 @@ func (s *TransactionService) UpdateTransaction(ctx context.Context, tx model.Tra
 			return nil, ErrSameAccounts
 		}
 	}
+	account, err := s.accountRepo.GetById(ctx, tx.AccountId, tx.UserId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSourceAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to get source account: %w", err)
+	}
+	if account.Type == model.Points {
+		return nil, ErrPointsAccountTransaction
+	}
+	if account.Type == model.Asset {
+		return nil, ErrAssetAccountTransaction
+	}
+	if err := checkBenefitTransaction(account, tx); err != nil {
+		return nil, err
+	}
+	if isForeignPurchase(tx) && (account.Type != model.CreditCard || tx.Type != model.Expense) {
+		return nil, ErrForeignPurchaseRequiresCreditCard
+	}
+	if tx.Type == model.Transfer {
+		if err := s.checkDestinationAccount(ctx, tx); err != nil {
+			return nil, err
+		}
+	}
+	if tx.CardId != nil {
+		if err := s.cards.CheckCard(ctx, *tx.CardId, tx.AccountId, tx.UserId); err != nil {
+			return nil, err
+		}
+	}
 
 	// Persist the changes.
-	err := s.repo.Update(ctx, tx)
+	current, err := s.repo.GetById(ctx, tx.Id, tx.UserId)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.save(ctx, tx, iof, isForeignPurchase(*current)); err != nil {
+		return nil, err
+	}
 
 	// Return the newly updated transaction to the handler.
 	return s.repo.GetById(ctx, tx.Id, tx.UserId)
 }
 
+// save stores an edited transaction. The IOF of a purchase abroad is replaced
+// by the one charged on its new amount, and removed once the purchase is no
+// longer converted.
+func (s *TransactionService) save(ctx context.Context, tx model.Transaction, iof decimal.Decimal, wasForeign bool) error {
+	if !isForeignPurchase(tx) && !wasForeign {
+		return s.repo.Update(ctx, tx)
+	}
+	var fee *model.Transaction
+	if iof.IsPositive() {
+		iofTx := iofFee(tx, iof)
+		fee = &iofTx
+	}
+	return s.repo.UpdateWithFee(ctx, tx, fee)
+}
+
+// checkDestinationAccount verifies that the destination of a transfer belongs
+// to the user and can receive transfers.
+func (s *TransactionService) checkDestinationAccount(ctx context.Context, tx model.Transaction) error {
+	destinationAccount, err := s.accountRepo.GetById(ctx, *tx.DestinationAccountId, tx.UserId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrDestinationAccountNotFound
+		}
+		return err
+	}
+	switch destinationAccount.Type {
+	case model.Points:
+		return ErrPointsAccountTransaction
+	case model.Asset:
+		return ErrAssetAccountTransaction
+	case model.Benefit:
+		return ErrBenefitAccountTransfer
+	}
+	return nil
+}
+
 // PatchTransaction applies a partial update to a transaction. It fetches the
 // original transaction and merges the requested changes before saving.
 func (s *TransactionService) PatchTransaction(ctx context.Context, id, userId int64, req dto.PatchTransactionRequest) (*model.Transaction, error) {
@@ -157,10 +328,27 @@ func (s *TransactionService) PatchTransaction(ctx context.Context, id, userId in
 	}
 
 	// 2. Apply changes from the request DTO to the model.
+	wasForeign := isForeignPurchase(*txToUpdate)
 	if req.Description != nil {
 		txToUpdate.Description = *req.Description
 	}
+	if req.OriginalCurrency != nil {
+		txToUpdate.OriginalCurrency = req.OriginalCurrency
+	}
+	if req.OriginalAmount != nil {
+		txToUpdate.OriginalAmount = req.OriginalAmount
+	}
+	if req.ExchangeRate != nil {
+		txToUpdate.ExchangeRate = req.ExchangeRate
+	}
+	if req.SpreadRate != nil {
+		txToUpdate.SpreadRate = req.SpreadRate
+	}
 	if req.Amount != nil {
+		// The amount of a purchase abroad is converted from its original amount.
+		if isForeignPurchase(*txToUpdate) {
+			return nil, ErrForeignPurchaseAmount
+		}
 		if req.Amount.IsNegative() || req.Amount.IsZero() {
 			return nil, ErrAmountNotPositive
 		}
@@ -169,20 +357,54 @@ func (s *TransactionService) PatchTransaction(ctx context.Context, id, userId in
 	if req.Date != nil {
 		txToUpdate.Date = *req.Date
 	}
+	if req.CategoryId != nil {
+		txToUpdate.CategoryId = req.CategoryId
+	}
+	accountType := txToUpdate.AccountType
 	if req.AccountId != nil {
 		// Extra validation: ensure the new account exists and belongs to the user.
-		_, err := s.accountRepo.GetById(ctx, *req.AccountId, userId)
+		newAccount, err := s.accountRepo.GetById(ctx, *req.AccountId, userId)
 		if err != nil {
 			return nil, ErrNewAccountNotFound
 		}
+		if newAccount.Type == model.Points {
+			return nil, ErrPointsAccountTransaction
+		}
+		if newAccount.Type == model.Asset {
+			return nil, ErrAssetAccountTransaction
+		}
+		if err := checkBenefitTransaction(newAccount, *txToUpdate); err != nil {
+			return nil, err
+		}
+		// Cards belong to one account, so moving the purchase drops its card.
+		if *req.AccountId != txToUpdate.AccountId {
+			txToUpdate.CardId = nil
+		}
 		txToUpdate.AccountId = *req.AccountId
+		accountType = newAccount.Type
+	} else if req.CategoryId != nil && txToUpdate.AccountType == model.Benefit {
+		// The account stays the same, but its allowed categories still apply.
+		account, err := s.accountRepo.GetById(ctx, txToUpdate.AccountId, userId)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkBenefitTransaction(account, *txToUpdate); err != nil {
+			return nil, err
+		}
 	}
-	if req.CategoryId != nil {
-		txToUpdate.CategoryId = req.CategoryId
+
+	var iof decimal.Decimal
+	if isForeignPurchase(*txToUpdate) {
+		if accountType != model.CreditCard || txToUpdate.Type != model.Expense {
+			return nil, ErrForeignPurchaseRequiresCreditCard
+		}
+		if iof, err = convertForeignPurchase(txToUpdate, s.options.IOFRate); err != nil {
+			return nil, err
+		}
 	}
 
 	// 3. Save the merged, validated object.
-	if err := s.repo.Update(ctx, *txToUpdate); err != nil {
+	if err := s.save(ctx, *txToUpdate, iof, wasForeign); err != nil {
 		return nil, err
 	}
 
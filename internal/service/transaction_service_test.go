@@ -6,12 +6,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/api/dto"
 	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
 	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/testhelper"
 	"github.com/rs/zerolog"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockTransactionRepository is a mock implementation of the TransactionRepository interface,
@@ -26,6 +29,12 @@ func (m *MockTransactionRepository) Create(ctx context.Context, tx model.Transac
 	return args.Get(0).(int64), args.Error(1)
 }
 
+// CreateWithFee simulates creating a purchase together with its fee.
+func (m *MockTransactionRepository) CreateWithFee(ctx context.Context, purchase, fee model.Transaction) (int64, error) {
+	args := m.Called(ctx, purchase, fee)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // GetById simulates retrieving a single transaction by its Id and user Id.
 func (m *MockTransactionRepository) GetById(ctx context.Context, id, userId int64) (*model.Transaction, error) {
 	args := m.Called(ctx, id, userId)
@@ -57,6 +66,12 @@ func (m *MockTransactionRepository) Update(ctx context.Context, tx model.Transac
 	return args.Error(0)
 }
 
+// UpdateWithFee simulates updating a purchase and replacing its fee.
+func (m *MockTransactionRepository) UpdateWithFee(ctx context.Context, purchase model.Transaction, fee *model.Transaction) error {
+	args := m.Called(ctx, purchase, fee)
+	return args.Error(0)
+}
+
 // Delete simulates deleting a transaction by its Id and user Id.
 func (m *MockTransactionRepository) Delete(ctx context.Context, id, userId int64) error {
 	// Note: Fixed a bug here. Original was m.Called(ctx, userId, userId).
@@ -70,6 +85,24 @@ func (m *MockTransactionRepository) DeleteByAccountId(ctx context.Context, userI
 	return args.Error(0)
 }
 
+func (m *MockTransactionRepository) DeleteByAccountIdKeepingCounterparts(ctx context.Context, userId, accountId int64) error {
+	args := m.Called(ctx, userId, accountId)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) CountByAccountId(ctx context.Context, userId, accountId int64) (int64, error) {
+	args := m.Called(ctx, userId, accountId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ListTransfersByAccountId(ctx context.Context, userId, accountId int64) ([]model.Transaction, error) {
+	args := m.Called(ctx, userId, accountId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Transaction), args.Error(1)
+}
+
 func (m *MockTransactionRepository) SumExpensesByCategoryAndPeriod(ctx context.Context, userId, categoryId int64, startDate, endDate time.Time) (decimal.Decimal, error) {
 	args := m.Called(ctx, userId, categoryId, startDate, endDate)
 	// Get the first return argument and assert it's a decimal.Decimal
@@ -81,6 +114,15 @@ func (m *MockTransactionRepository) SumExpensesByCategoryAndPeriod(ctx context.C
 
 }
 
+func (m *MockTransactionRepository) Aggregate(ctx context.Context, userId int64, query repository.ReportQuery) ([]repository.ReportRow, error) {
+	args := m.Called(ctx, userId, query)
+	var rows []repository.ReportRow
+	if data := args.Get(0); data != nil {
+		rows = data.([]repository.ReportRow)
+	}
+	return rows, args.Error(1)
+}
+
 // TestTransactionService contains all tests for transaction service business logic .
 func TestTransactionService(t *testing.T) {
 	zerolog.SetGlobalLevel(zerolog.Disabled)
@@ -90,7 +132,7 @@ func TestTransactionService(t *testing.T) {
 	setup := func() (*TransactionService, *MockAccountRepository, *MockTransactionRepository) {
 		mockAccountRepo := new(MockAccountRepository)
 		mockTxRepo := new(MockTransactionRepository)
-		txService := NewTransactionService(mockTxRepo, mockAccountRepo)
+		txService := NewTransactionService(mockTxRepo, mockAccountRepo, unlimitedQuotas(), nil, TransactionOptions{})
 		return txService, mockAccountRepo, mockTxRepo
 	}
 
@@ -214,6 +256,321 @@ func TestTransactionService(t *testing.T) {
 			assert.Equal(t, "source and destination accounts cannot be the same", err.Error())
 			mockAccountRepo.AssertExpectations(t)
 		})
+
+		t.Run("failure: should reject transactions on a points account", func(t *testing.T) {
+			// Arrange
+			txService, mockAccountRepo, mockTxRepo := setup()
+			mockAccountRepo.On("GetById", ctx, baseTx.AccountId, baseTx.UserId).Return(&model.Account{Type: model.Points}, nil).Once()
+
+			// Act
+			_, err := txService.CreateTransaction(ctx, baseTx)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrPointsAccountTransaction)
+			mockTxRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+
+		t.Run("failure: should reject transactions on an asset account", func(t *testing.T) {
+			// Arrange
+			txService, mockAccountRepo, mockTxRepo := setup()
+			mockAccountRepo.On("GetById", ctx, baseTx.AccountId, baseTx.UserId).Return(&model.Account{Type: model.Asset}, nil).Once()
+
+			// Act
+			_, err := txService.CreateTransaction(ctx, baseTx)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrAssetAccountTransaction)
+			mockTxRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+
+		t.Run("benefit accounts: should only pay for the allowed categories", func(t *testing.T) {
+			// Arrange
+			txService, mockAccountRepo, mockTxRepo := setup()
+			voucher := &model.Account{Type: model.Benefit, BenefitAllowedCategoryIds: []int64{3, 7}}
+			mockAccountRepo.On("GetById", ctx, baseTx.AccountId, baseTx.UserId).Return(voucher, nil)
+			mockTxRepo.On("Create", ctx, mock.AnythingOfType("model.Transaction")).Return(int64(123), nil).Once()
+			groceries, electronics := baseTx, baseTx
+			groceries.CategoryId = testhelper.Ptr(int64(7))
+			electronics.CategoryId = testhelper.Ptr(int64(9))
+
+			// Act
+			_, allowedErr := txService.CreateTransaction(ctx, groceries)
+			_, deniedErr := txService.CreateTransaction(ctx, electronics)
+			_, uncategorizedErr := txService.CreateTransaction(ctx, baseTx)
+
+			// Assert
+			assert.NoError(t, allowedErr)
+			assert.ErrorIs(t, deniedErr, ErrBenefitCategoryNotAllowed)
+			assert.ErrorIs(t, uncategorizedErr, ErrBenefitCategoryNotAllowed)
+			mockTxRepo.AssertNumberOfCalls(t, "Create", 1)
+		})
+
+		t.Run("benefit accounts: should not take part in transfers", func(t *testing.T) {
+			// Arrange
+			txService, mockAccountRepo, mockTxRepo := setup()
+			transferTx := baseTx
+			transferTx.Type = model.Transfer
+			transferTx.DestinationAccountId = &destAccountId
+			mockAccountRepo.On("GetById", ctx, baseTx.AccountId, baseTx.UserId).Return(&model.Account{Type: model.Checking}, nil).Once()
+			mockAccountRepo.On("GetById", ctx, destAccountId, baseTx.UserId).Return(&model.Account{Type: model.Benefit}, nil).Once()
+
+			// Act
+			_, err := txService.CreateTransaction(ctx, transferTx)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrBenefitAccountTransfer)
+			mockTxRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("UpdateTransaction", func(t *testing.T) {
+		transferTx := baseTx
+		transferTx.Id = 5
+		transferTx.Type = model.Transfer
+		transferTx.DestinationAccountId = &destAccountId
+
+		testCases := []struct {
+			name        string
+			tx          model.Transaction
+			source      model.AccountType
+			destination model.AccountType
+			expectedErr error
+		}{
+			{name: "should reject moving a transaction onto a points account", tx: baseTx, source: model.Points, expectedErr: ErrPointsAccountTransaction},
+			{name: "should reject moving a transaction onto an asset account", tx: baseTx, source: model.Asset, expectedErr: ErrAssetAccountTransaction},
+			{name: "should reject a transfer to a points account", tx: transferTx, source: model.Checking, destination: model.Points, expectedErr: ErrPointsAccountTransaction},
+			{name: "should reject a transfer to an asset account", tx: transferTx, source: model.Checking, destination: model.Asset, expectedErr: ErrAssetAccountTransaction},
+			{name: "should reject a transfer to a benefit account", tx: transferTx, source: model.Checking, destination: model.Benefit, expectedErr: ErrBenefitAccountTransfer},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				// Arrange
+				txService, mockAccountRepo, mockTxRepo := setup()
+				mockAccountRepo.On("GetById", ctx, baseTx.AccountId, baseTx.UserId).Return(&model.Account{Type: tc.source}, nil).Once()
+				mockAccountRepo.On("GetById", ctx, destAccountId, baseTx.UserId).Return(&model.Account{Type: tc.destination}, nil).Maybe()
+
+				// Act
+				_, err := txService.UpdateTransaction(ctx, tc.tx)
+
+				// Assert
+				assert.ErrorIs(t, err, tc.expectedErr)
+				mockTxRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			})
+		}
+
+		t.Run("should update a transfer between regular accounts", func(t *testing.T) {
+			// Arrange
+			txService, mockAccountRepo, mockTxRepo := setup()
+			mockAccountRepo.On("GetById", ctx, baseTx.AccountId, baseTx.UserId).Return(&model.Account{Type: model.Checking}, nil).Once()
+			mockAccountRepo.On("GetById", ctx, destAccountId, baseTx.UserId).Return(&model.Account{Type: model.Savings}, nil).Once()
+			mockTxRepo.On("Update", ctx, transferTx).Return(nil).Once()
+			mockTxRepo.On("GetById", ctx, transferTx.Id, transferTx.UserId).Return(&transferTx, nil).Twice()
+
+			// Act
+			_, err := txService.UpdateTransaction(ctx, transferTx)
+
+			// Assert
+			assert.NoError(t, err)
+			mockAccountRepo.AssertExpectations(t)
+			mockTxRepo.AssertExpectations(t)
+		})
+	})
+	// TODO: Further tests for Delete and List methods can be added following the same pattern.
+}
+
+func TestTransactionServiceForeignPurchases(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+
+	setup := func() (*TransactionService, *MockAccountRepository, *MockTransactionRepository) {
+		mockAccountRepo := new(MockAccountRepository)
+		mockTxRepo := new(MockTransactionRepository)
+		options := TransactionOptions{IOFRate: decimal.RequireFromString("3.5")}
+		return NewTransactionService(mockTxRepo, mockAccountRepo, unlimitedQuotas(), nil, options), mockAccountRepo, mockTxRepo
+	}
+
+	card := &model.Account{Id: 10, UserId: 1, Type: model.CreditCard, CreditLimit: testhelper.Ptr(decimal.NewFromInt(1000))}
+	categoryId := int64(7)
+	purchase := model.Transaction{
+		UserId:           1,
+		AccountId:        card.Id,
+		CategoryId:       &categoryId,
+		Description:      "Hotel",
+		Type:             model.Expense,
+		Date:             time.Date(2025, time.June, 5, 12, 0, 0, 0, time.UTC),
+		OriginalCurrency: testhelper.Ptr("usd"),
+		OriginalAmount:   testhelper.Ptr(decimal.NewFromInt(100)),
+		ExchangeRate:     testhelper.Ptr(decimal.NewFromInt(5)),
+		SpreadRate:       testhelper.Ptr(decimal.NewFromInt(4)),
+	}
+
+	t.Run("should convert the purchase and post the IOF with it", func(t *testing.T) {
+		// Arrange
+		txService, mockAccountRepo, mockTxRepo := setup()
+		mockAccountRepo.On("GetById", ctx, card.Id, card.UserId).Return(card, nil).Once()
+		mockAccountRepo.On("GetCurrentBalance", ctx, card.Id, card.UserId).Return(decimal.NewFromInt(-400), nil).Once()
+		mockTxRepo.On("CreateWithFee", ctx,
+			mock.MatchedBy(func(tx model.Transaction) bool {
+				// 100 USD at 5.00 plus a 4% spread.
+				return tx.Amount.Equal(decimal.NewFromInt(520)) && *tx.OriginalCurrency == "USD"
+			}),
+			mock.MatchedBy(func(fee model.Transaction) bool {
+				return fee.Amount.Equal(decimal.RequireFromString("18.20")) && fee.Description == "IOF: Hotel" &&
+					fee.Type == model.Expense && fee.AccountId == card.Id && *fee.CategoryId == categoryId && fee.Date.Equal(purchase.Date)
+			}),
+		).Return(int64(42), nil).Once()
+
+		// Act
+		id, err := txService.CreateTransaction(ctx, purchase)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), id)
+		mockTxRepo.AssertExpectations(t)
+	})
+
+	t.Run("should count the IOF against the credit limit", func(t *testing.T) {
+		// Arrange: 520 fits in the 530 left, but not with its 18.20 of IOF.
+		txService, mockAccountRepo, mockTxRepo := setup()
+		mockAccountRepo.On("GetById", ctx, card.Id, card.UserId).Return(card, nil).Once()
+		mockAccountRepo.On("GetCurrentBalance", ctx, card.Id, card.UserId).Return(decimal.NewFromInt(-470), nil).Once()
+
+		// Act
+		_, err := txService.CreateTransaction(ctx, purchase)
+
+		// Assert
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds credit card limit")
+		mockTxRepo.AssertNotCalled(t, "CreateWithFee", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should only accept credit card expenses", func(t *testing.T) {
+		// Arrange
+		txService, mockAccountRepo, mockTxRepo := setup()
+		mockAccountRepo.On("GetById", ctx, card.Id, card.UserId).Return(&model.Account{Id: card.Id, Type: model.Checking}, nil).Once()
+
+		// Act
+		_, err := txService.CreateTransaction(ctx, purchase)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrForeignPurchaseRequiresCreditCard)
+		mockTxRepo.AssertNotCalled(t, "CreateWithFee", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject incomplete or local purchases", func(t *testing.T) {
+		txService, _, _ := setup()
+		withoutRate := purchase
+		withoutRate.ExchangeRate = nil
+		inReais := purchase
+		inReais.OriginalCurrency = testhelper.Ptr("BRL")
+		negativeSpread := purchase
+		negativeSpread.SpreadRate = testhelper.Ptr(decimal.NewFromInt(-1))
+
+		for _, tx := range []model.Transaction{withoutRate, inReais, negativeSpread} {
+			_, err := txService.CreateTransaction(ctx, tx)
+			assert.ErrorIs(t, err, ErrInvalidForeignPurchase)
+		}
+	})
+
+	stored := purchase
+	stored.Id = 42
+	stored.OriginalCurrency = testhelper.Ptr("USD")
+	stored.Amount = decimal.NewFromInt(520)
+	stored.AccountType = model.CreditCard
+
+	t.Run("should convert an edited purchase again and replace its IOF", func(t *testing.T) {
+		// Arrange
+		txService, mockAccountRepo, mockTxRepo := setup()
+		edited := stored
+		edited.OriginalAmount = testhelper.Ptr(decimal.NewFromInt(200))
+		mockAccountRepo.On("GetById", ctx, card.Id, card.UserId).Return(card, nil).Once()
+		mockTxRepo.On("GetById", ctx, stored.Id, stored.UserId).Return(&stored, nil).Twice()
+		mockTxRepo.On("UpdateWithFee", ctx,
+			mock.MatchedBy(func(tx model.Transaction) bool { return tx.Amount.Equal(decimal.NewFromInt(1040)) }),
+			mock.MatchedBy(func(fee *model.Transaction) bool {
+				return fee != nil && fee.Amount.Equal(decimal.RequireFromString("36.40")) && fee.Description == "IOF: Hotel"
+			}),
+		).Return(nil).Once()
+
+		// Act
+		_, err := txService.UpdateTransaction(ctx, edited)
+
+		// Assert
+		require.NoError(t, err)
+		mockTxRepo.AssertExpectations(t)
+	})
+
+	t.Run("should remove the IOF of a purchase no longer converted", func(t *testing.T) {
+		// Arrange
+		txService, mockAccountRepo, mockTxRepo := setup()
+		edited := stored
+		edited.OriginalCurrency, edited.OriginalAmount, edited.ExchangeRate, edited.SpreadRate = nil, nil, nil, nil
+		edited.Amount = decimal.NewFromInt(480)
+		mockAccountRepo.On("GetById", ctx, card.Id, card.UserId).Return(card, nil).Once()
+		mockTxRepo.On("GetById", ctx, stored.Id, stored.UserId).Return(&stored, nil).Twice()
+		mockTxRepo.On("UpdateWithFee", ctx, edited, (*model.Transaction)(nil)).Return(nil).Once()
+
+		// Act
+		_, err := txService.UpdateTransaction(ctx, edited)
+
+		// Assert
+		require.NoError(t, err)
+		mockTxRepo.AssertExpectations(t)
+		mockTxRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject patching the amount of a converted purchase", func(t *testing.T) {
+		// Arrange
+		txService, _, mockTxRepo := setup()
+		current := stored
+		mockTxRepo.On("GetById", ctx, stored.Id, stored.UserId).Return(&current, nil).Once()
+
+		// Act
+		_, err := txService.PatchTransaction(ctx, stored.Id, stored.UserId, dto.PatchTransactionRequest{Amount: testhelper.Ptr(decimal.NewFromInt(600))})
+
+		// Assert
+		assert.ErrorIs(t, err, ErrForeignPurchaseAmount)
+		mockTxRepo.AssertNotCalled(t, "UpdateWithFee", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should convert a patched purchase again and replace its IOF", func(t *testing.T) {
+		// Arrange
+		txService, _, mockTxRepo := setup()
+		current := stored
+		mockTxRepo.On("GetById", ctx, stored.Id, stored.UserId).Return(&current, nil).Twice()
+		mockTxRepo.On("UpdateWithFee", ctx,
+			mock.MatchedBy(func(tx model.Transaction) bool {
+				// 100 USD at 5.50 plus a 4% spread.
+				return tx.Amount.Equal(decimal.NewFromInt(572)) && tx.ExchangeRate.Equal(decimal.RequireFromString("5.5"))
+			}),
+			mock.MatchedBy(func(fee *model.Transaction) bool {
+				return fee != nil && fee.Amount.Equal(decimal.RequireFromString("20.02"))
+			}),
+		).Return(nil).Once()
+
+		// Act
+		_, err := txService.PatchTransaction(ctx, stored.Id, stored.UserId, dto.PatchTransactionRequest{ExchangeRate: testhelper.Ptr(decimal.RequireFromString("5.5"))})
+
+		// Assert
+		require.NoError(t, err)
+		mockTxRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not post a fee when there is no IOF", func(t *testing.T) {
+		// Arrange
+		mockAccountRepo := new(MockAccountRepository)
+		mockTxRepo := new(MockTransactionRepository)
+		txService := NewTransactionService(mockTxRepo, mockAccountRepo, unlimitedQuotas(), nil, TransactionOptions{})
+		mockAccountRepo.On("GetById", ctx, card.Id, card.UserId).Return(card, nil).Once()
+		mockAccountRepo.On("GetCurrentBalance", ctx, card.Id, card.UserId).Return(decimal.Zero, nil).Once()
+		mockTxRepo.On("Create", ctx, mock.MatchedBy(func(tx model.Transaction) bool { return tx.Amount.Equal(decimal.NewFromInt(520)) })).Return(int64(43), nil).Once()
+
+		// Act
+		_, err := txService.CreateTransaction(ctx, purchase)
+
+		// Assert
+		require.NoError(t, err)
+		mockTxRepo.AssertExpectations(t)
 	})
-	// TODO: Further tests for Update, Delete, and List methods can be added following the same pattern.
 }
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
 	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
@@ -9,6 +10,8 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+var ErrInvalidCurrentPassword = errors.New("current password is incorrect")
+
 // UserService encapsulates the business logic for user-related operations.
 // It orchestrates calls to the repository and handles tasks like password hashing.
 type UserService struct {
@@ -55,6 +58,52 @@ func (s *UserService) GetUserById(ctx context.Context, id int64) (*model.User, e
 	return s.repo.GetById(ctx, id)
 }
 
+// ChangePassword replaces the user's password after checking the current one.
+// It also clears any password reset forced by an admin.
+func (s *UserService) ChangePassword(ctx context.Context, userId int64, currentPassword, newPassword string) error {
+	user, err := s.repo.GetById(ctx, userId)
+	if err != nil {
+		return err
+	}
+	// GetById does not load the hash, so the user is fetched again by e-mail.
+	user, err = s.repo.GetByEmail(ctx, user.Email)
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword)); err != nil {
+		return ErrInvalidCurrentPassword
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdatePassword(ctx, userId, string(hashedPassword), false)
+}
+
+// UpdateFiscalMonth changes the day the user's months start on for budgets
+// and reports, and whether they are named after the calendar month they start
+// or end in. An empty label keeps the current one.
+func (s *UserService) UpdateFiscalMonth(ctx context.Context, userId int64, startDay int, label model.FiscalMonthLabel) (*model.User, error) {
+	user, err := s.repo.GetById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if label == "" {
+		label = user.FiscalMonthLabel
+	}
+	if !(FiscalMonth{StartDay: startDay, Label: label}).valid() {
+		return nil, ErrInvalidFiscalMonth
+	}
+
+	if err := s.repo.UpdateFiscalMonth(ctx, userId, startDay, label); err != nil {
+		return nil, err
+	}
+	user.FiscalMonthStartDay, user.FiscalMonthLabel = startDay, label
+	return user, nil
+}
+
 // seedDefaultCategories creates the initial set of categories for a new user.
 // This function is designed to be run in a goroutine as a non-critical background task.
 // If a category fails to be created, an error is logged, but the process continues.
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -43,6 +44,59 @@ func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*mod
 	return args.Get(0).(*model.User), args.Error(1)
 }
 
+// List simulates listing users with filters.
+func (m *MockUserRepository) List(ctx context.Context, filters repository.ListUserFilters) ([]model.User, error) {
+	args := m.Called(ctx, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.User), args.Error(1)
+}
+
+// SetDisabled simulates disabling or enabling a user.
+func (m *MockUserRepository) SetDisabled(ctx context.Context, id int64, disabled bool) error {
+	args := m.Called(ctx, id, disabled)
+	return args.Error(0)
+}
+
+// UpdatePassword simulates replacing a user's password hash.
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id int64, passwordHash string, resetRequired bool) error {
+	args := m.Called(ctx, id, passwordHash, resetRequired)
+	return args.Error(0)
+}
+
+// SetDisabledAudited simulates disabling or enabling a user along with its audit entry.
+func (m *MockUserRepository) SetDisabledAudited(ctx context.Context, id int64, disabled bool, entry model.AuditLogEntry) error {
+	args := m.Called(ctx, id, disabled, entry)
+	return args.Error(0)
+}
+
+// UpdatePasswordAudited simulates replacing a user's password hash along with its audit entry.
+func (m *MockUserRepository) UpdatePasswordAudited(ctx context.Context, id int64, passwordHash string, resetRequired bool, entry model.AuditLogEntry) error {
+	args := m.Called(ctx, id, passwordHash, resetRequired, entry)
+	return args.Error(0)
+}
+
+// PromoteToAdmin simulates granting the admin flag by e-mail.
+func (m *MockUserRepository) PromoteToAdmin(ctx context.Context, emails []string) (int64, error) {
+	args := m.Called(ctx, emails)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// UpdateFiscalMonth simulates changing the user's fiscal month.
+func (m *MockUserRepository) UpdateFiscalMonth(ctx context.Context, id int64, startDay int, label model.FiscalMonthLabel) error {
+	args := m.Called(ctx, id, startDay, label)
+	return args.Error(0)
+}
+
+// calendarMonths is a user repository whose users keep calendar months.
+func calendarMonths() *MockUserRepository {
+	mockUserRepo := new(MockUserRepository)
+	mockUserRepo.On("GetById", mock.Anything, mock.Anything).
+		Return(&model.User{FiscalMonthStartDay: 1, FiscalMonthLabel: model.FiscalMonthLabelStart}, nil).Maybe()
+	return mockUserRepo
+}
+
 // TestUserService contains all tests for the user service logic.
 func TestUserService(t *testing.T) {
 	// Disable logging for tests to keep output clean.
@@ -146,4 +200,72 @@ func TestUserService(t *testing.T) {
 			mockUserRepo.AssertExpectations(t)
 		})
 	})
+	t.Run("ChangePassword", func(t *testing.T) {
+		hash, _ := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.DefaultCost)
+		storedUser := &model.User{Id: 1, Email: "jane@example.com", PasswordHash: string(hash)}
+
+		t.Run("should store the new hash and clear the reset flag", func(t *testing.T) {
+			// Arrange
+			userService, mockUserRepo, _ := setup()
+			mockUserRepo.On("GetById", ctx, int64(1)).Return(storedUser, nil).Once()
+			mockUserRepo.On("GetByEmail", ctx, storedUser.Email).Return(storedUser, nil).Once()
+			var capturedHash string
+			mockUserRepo.On("UpdatePassword", ctx, int64(1), mock.AnythingOfType("string"), false).
+				Run(func(args mock.Arguments) { capturedHash = args.String(2) }).
+				Return(nil).Once()
+
+			// Act
+			err := userService.ChangePassword(ctx, 1, "old-password", "new-password")
+
+			// Assert
+			assert.NoError(t, err)
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(capturedHash), []byte("new-password")))
+			mockUserRepo.AssertExpectations(t)
+		})
+
+		t.Run("should reject a wrong current password", func(t *testing.T) {
+			// Arrange
+			userService, mockUserRepo, _ := setup()
+			mockUserRepo.On("GetById", ctx, int64(1)).Return(storedUser, nil).Once()
+			mockUserRepo.On("GetByEmail", ctx, storedUser.Email).Return(storedUser, nil).Once()
+
+			// Act
+			err := userService.ChangePassword(ctx, 1, "wrong-password", "new-password")
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidCurrentPassword)
+			mockUserRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("UpdateFiscalMonth", func(t *testing.T) {
+		t.Run("should keep the current label when none is given", func(t *testing.T) {
+			// Arrange
+			userService, mockUserRepo, _ := setup()
+			mockUserRepo.On("GetById", ctx, int64(1)).
+				Return(&model.User{Id: 1, FiscalMonthStartDay: 1, FiscalMonthLabel: model.FiscalMonthLabelEnd}, nil).Once()
+			mockUserRepo.On("UpdateFiscalMonth", ctx, int64(1), 25, model.FiscalMonthLabelEnd).Return(nil).Once()
+
+			// Act
+			user, err := userService.UpdateFiscalMonth(ctx, 1, 25, "")
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 25, user.FiscalMonthStartDay)
+			mockUserRepo.AssertExpectations(t)
+		})
+
+		t.Run("should reject an unknown label", func(t *testing.T) {
+			// Arrange
+			userService, mockUserRepo, _ := setup()
+			mockUserRepo.On("GetById", ctx, int64(1)).Return(&model.User{Id: 1}, nil).Once()
+
+			// Act
+			_, err := userService.UpdateFiscalMonth(ctx, 1, 25, "middle")
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidFiscalMonth)
+			mockUserRepo.AssertNotCalled(t, "UpdateFiscalMonth", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
 }
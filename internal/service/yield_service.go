@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/repository"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrYieldAccountNotFound = errors.New("savings account not found")
+	ErrInvalidIndexRates    = errors.New("index rates need a known index, a date and a rate that is not negative")
+)
+
+const (
+	// Fixed yields are compounded over calendar days and the CDI over the
+	// business days of the year, as the market quotes them.
+	calendarDaysPerYear = 365
+	businessDaysPerYear = 252
+	// indexLookbackDays is how long before a period the Selic in effect on its
+	// first days is looked up, for poupança yields.
+	indexLookbackDays = 30
+)
+
+// Above poupancaSelicThreshold percent a year, poupança pays
+// poupancaMonthlyRate percent a month; below it, 70% of the Selic.
+var (
+	poupancaSelicThreshold = decimal.RequireFromString("8.5")
+	poupancaMonthlyRate    = decimal.RequireFromString("0.5")
+	poupancaSelicShare     = decimal.NewFromInt(70)
+)
+
+// YieldService posts the yield of savings accounts and keeps the CDI and
+// Selic tables it is calculated from.
+type YieldService struct {
+	repo        repository.YieldRepository
+	accountRepo repository.AccountRepository
+	now         func() time.Time
+}
+
+// NewYieldService creates a new instance of YieldService.
+func NewYieldService(repo repository.YieldRepository, accountRepo repository.AccountRepository) *YieldService {
+	return &YieldService{
+		repo:        repo,
+		accountRepo: accountRepo,
+		now:         time.Now,
+	}
+}
+
+// AccrueDueAccounts posts the yield of every savings account for the periods
+// that ended since its last posting. It is run periodically; a period is
+// posted once, and yields that follow an index wait until the index is loaded
+// up to the last day of the period.
+func (s *YieldService) AccrueDueAccounts(ctx context.Context) (int, error) {
+	logger := zerolog.Ctx(ctx)
+	now := s.now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	accounts, err := s.repo.ListYieldAccounts(ctx)
+	if err != nil {
+		return 0, err
+	}
+	loadedUntil := map[model.IndexName]*time.Time{}
+	for _, name := range []model.IndexName{model.IndexCDI, model.IndexSelic} {
+		if loadedUntil[name], err = s.repo.LatestIndexDate(ctx, name); err != nil {
+			return 0, err
+		}
+	}
+
+	posted := 0
+	for _, account := range accounts {
+		n, err := s.accrue(ctx, account, today, loadedUntil)
+		posted += n
+		if err != nil {
+			logger.Error().Err(err).Int64("accountId", account.Id).Msg("failed to post savings yield")
+		}
+	}
+	return posted, nil
+}
+
+// accrue posts the periods of an account that ended by today.
+func (s *YieldService) accrue(ctx context.Context, account model.Account, today time.Time, loadedUntil map[model.IndexName]*time.Time) (int, error) {
+	start := yieldStart(account)
+	last, err := s.repo.LastAccrualEnd(ctx, account.Id)
+	if err != nil {
+		return 0, err
+	}
+	if last != nil {
+		start = *last
+	}
+	index, usesIndex := yieldIndex(*account.YieldType)
+
+	posted := 0
+	for {
+		end := yieldPeriodEnd(start, account.YieldFrequency)
+		if end.After(today) {
+			return posted, nil
+		}
+		if usesIndex && (loadedUntil[index] == nil || loadedUntil[index].Before(end.AddDate(0, 0, -1))) {
+			return posted, nil
+		}
+		ok, err := s.post(ctx, account, start, end)
+		if err != nil || !ok {
+			return posted, err
+		}
+		posted++
+		start = end
+	}
+}
+
+// post records the yield of the days from start until the day before end. It
+// is posted on end, so it earns yield from the next period on.
+func (s *YieldService) post(ctx context.Context, account model.Account, start, end time.Time) (bool, error) {
+	var rates []model.IndexRate
+	if index, ok := yieldIndex(*account.YieldType); ok {
+		var err error
+		if rates, err = s.repo.ListIndexRates(ctx, index, start.AddDate(0, 0, -indexLookbackDays), end); err != nil {
+			return false, err
+		}
+	}
+	balances, err := s.repo.DailyBalances(ctx, account, start, end)
+	if err != nil {
+		return false, err
+	}
+
+	accrual := model.YieldAccrual{
+		UserId:          account.UserId,
+		AccountId:       account.Id,
+		PeriodStart:     start,
+		PeriodEnd:       end,
+		GrossAmount:     accruedYield(balances, dailyYieldRates(account, start, end, rates)),
+		IncomeTaxAmount: decimal.Zero,
+	}
+	var income, incomeTax *model.Transaction
+	if accrual.GrossAmount.IsPositive() {
+		income = &model.Transaction{
+			UserId:      account.UserId,
+			Description: fmt.Sprintf("%s yield %s to %s", account.Name, start.Format(time.DateOnly), end.AddDate(0, 0, -1).Format(time.DateOnly)),
+			Amount:      accrual.GrossAmount,
+			Date:        end,
+			Type:        model.Income,
+			AccountId:   account.Id,
+		}
+		// Poupança is exempt from income tax. The whole balance counts as held
+		// since the account started earning; see incomeTaxRate.
+		if account.YieldWithholdsIR && *account.YieldType != model.YieldPoupanca {
+			daysHeld := int(end.Sub(yieldStart(account)).Hours() / 24)
+			accrual.IncomeTaxAmount = accrual.GrossAmount.Mul(percentOf(incomeTaxRate(daysHeld))).Round(2)
+		}
+		if accrual.IncomeTaxAmount.IsPositive() {
+			incomeTax = &model.Transaction{
+				UserId:      account.UserId,
+				Description: "IR: " + income.Description,
+				Amount:      accrual.IncomeTaxAmount,
+				Date:        end,
+				Type:        model.Expense,
+				AccountId:   account.Id,
+			}
+		}
+	}
+	return s.repo.RecordAccrual(ctx, accrual, income, incomeTax)
+}
+
+// ListAccruals returns the yield posted to a savings account, newest first.
+func (s *YieldService) ListAccruals(ctx context.Context, userId, accountId int64) ([]model.YieldAccrual, error) {
+	account, err := s.accountRepo.GetById(ctx, accountId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrYieldAccountNotFound
+		}
+		return nil, err
+	}
+	if account.Type != model.Savings {
+		return nil, ErrYieldAccountNotFound
+	}
+	return s.repo.ListAccruals(ctx, userId, accountId)
+}
+
+// ListIndexRates returns the rates of an index from start until the day before end.
+func (s *YieldService) ListIndexRates(ctx context.Context, name model.IndexName, start, end time.Time) ([]model.IndexRate, error) {
+	if name != model.IndexCDI && name != model.IndexSelic {
+		return nil, ErrInvalidIndexRates
+	}
+	return s.repo.ListIndexRates(ctx, name, start, end)
+}
+
+// yieldStart is the day an account starts earning yield.
+func yieldStart(account model.Account) time.Time {
+	start := account.CreatedAt.UTC()
+	if account.YieldStartDate != nil {
+		start = account.YieldStartDate.UTC()
+	}
+	return time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// yieldIndex returns the index a yield type follows, if any.
+func yieldIndex(yieldType model.YieldType) (model.IndexName, bool) {
+	switch yieldType {
+	case model.YieldCDI:
+		return model.IndexCDI, true
+	case model.YieldPoupanca:
+		return model.IndexSelic, true
+	}
+	return "", false
+}
+
+// yieldPeriodEnd is the day after the last day of the period that starts on
+// start. Monthly periods end with the calendar month; the default frequency is
+// monthly.
+func yieldPeriodEnd(start time.Time, frequency *model.YieldFrequency) time.Time {
+	if frequency != nil {
+		switch *frequency {
+		case model.YieldDaily:
+			return start.AddDate(0, 0, 1)
+		case model.YieldWeekly:
+			return start.AddDate(0, 0, 7)
+		}
+	}
+	return time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// dailyYieldRates returns the rate the account earns on each day of the
+// period. Days without an index rate, such as weekends, earn nothing for CDI
+// yields; poupança uses the latest Selic on or before each day.
+func dailyYieldRates(account model.Account, start, end time.Time, rates []model.IndexRate) []decimal.Decimal {
+	daily := make([]decimal.Decimal, int(end.Sub(start).Hours()/24))
+	switch *account.YieldType {
+	case model.YieldFixed:
+		rate := compoundRate(*account.YieldRate, calendarDaysPerYear)
+		for i := range daily {
+			daily[i] = rate
+		}
+	case model.YieldCDI:
+		share := percentOf(*account.YieldRate)
+		byDay := map[string]decimal.Decimal{}
+		for _, rate := range rates {
+			byDay[rate.Date.Format(time.DateOnly)] = compoundRate(rate.AnnualRate, businessDaysPerYear).Mul(share)
+		}
+		for i := range daily {
+			daily[i] = byDay[start.AddDate(0, 0, i).Format(time.DateOnly)]
+		}
+	case model.YieldPoupanca:
+		selic, next := decimal.Zero, 0
+		for i := range daily {
+			day := start.AddDate(0, 0, i).Format(time.DateOnly)
+			for next < len(rates) && rates[next].Date.Format(time.DateOnly) <= day {
+				selic = rates[next].AnnualRate
+				next++
+			}
+			daily[i] = poupancaDailyRate(selic)
+		}
+	}
+	return daily
+}
+
+// poupancaDailyRate is the poupança yield for one calendar day with the Selic
+// at the given percentage a year.
+func poupancaDailyRate(selic decimal.Decimal) decimal.Decimal {
+	monthly := poupancaMonthlyRate.InexactFloat64() / 100
+	if !selic.GreaterThan(poupancaSelicThreshold) {
+		annual := selic.Mul(percentOf(poupancaSelicShare)).InexactFloat64() / 100
+		monthly = math.Pow(1+annual, 1.0/12) - 1
+	}
+	return decimal.NewFromFloat(math.Pow(1+monthly, 12.0/calendarDaysPerYear) - 1)
+}
+
+// compoundRate turns a percentage a year into the rate of one of the given
+// periods of the year.
+func compoundRate(annualPercent decimal.Decimal, periodsPerYear int) decimal.Decimal {
+	return decimal.NewFromFloat(math.Pow(1+annualPercent.InexactFloat64()/100, 1.0/float64(periodsPerYear)) - 1)
+}
+
+// accruedYield compounds the daily rates over the end-of-day balances. Yield
+// is only earned on positive balances.
+func accruedYield(dailyBalances, dailyRates []decimal.Decimal) decimal.Decimal {
+	accrued := decimal.Zero
+	for i, balance := range dailyBalances {
+		if i >= len(dailyRates) {
+			break
+		}
+		if base := balance.Add(accrued); base.IsPositive() {
+			accrued = accrued.Add(base.Mul(dailyRates[i]))
+		}
+	}
+	return accrued.Round(2)
+}
+
+// incomeTaxRate is the IR percentage withheld from fixed income yield by the
+// regressive table, which falls the longer the money is held.
+//
+// The table applies to each deposit on its own, but the account only keeps a
+// balance, so the days are counted from the account's yield start for all of
+// it: money deposited into an old account is taxed at the old account's rate.
+// Keeping each application in its own account gives the exact rates.
+func incomeTaxRate(daysHeld int) decimal.Decimal {
+	switch {
+	case daysHeld <= 180:
+		return decimal.RequireFromString("22.5")
+	case daysHeld <= 360:
+		return decimal.NewFromInt(20)
+	case daysHeld <= 720:
+		return decimal.RequireFromString("17.5")
+	}
+	return decimal.NewFromInt(15)
+}
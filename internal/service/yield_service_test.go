@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/model"
+	"github.com/matheusmazzoni/gofinance-tracker-api/internal/testhelper"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockYieldRepository is a mock for the YieldRepository interface.
+type MockYieldRepository struct {
+	mock.Mock
+}
+
+func (m *MockYieldRepository) ListYieldAccounts(ctx context.Context) ([]model.Account, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Account), args.Error(1)
+}
+
+func (m *MockYieldRepository) LastAccrualEnd(ctx context.Context, accountId int64) (*time.Time, error) {
+	args := m.Called(ctx, accountId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockYieldRepository) DailyBalances(ctx context.Context, account model.Account, start, end time.Time) ([]decimal.Decimal, error) {
+	args := m.Called(ctx, account, start, end)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]decimal.Decimal), args.Error(1)
+}
+
+func (m *MockYieldRepository) RecordAccrual(ctx context.Context, accrual model.YieldAccrual, income *model.Transaction, incomeTax *model.Transaction) (bool, error) {
+	args := m.Called(ctx, accrual, income, incomeTax)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockYieldRepository) ListAccruals(ctx context.Context, userId, accountId int64) ([]model.YieldAccrual, error) {
+	args := m.Called(ctx, userId, accountId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.YieldAccrual), args.Error(1)
+}
+
+func (m *MockYieldRepository) SaveIndexRates(ctx context.Context, rates []model.IndexRate, entry model.AuditLogEntry) error {
+	args := m.Called(ctx, rates, entry)
+	return args.Error(0)
+}
+
+func (m *MockYieldRepository) ListIndexRates(ctx context.Context, name model.IndexName, start, end time.Time) ([]model.IndexRate, error) {
+	args := m.Called(ctx, name, start, end)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.IndexRate), args.Error(1)
+}
+
+func (m *MockYieldRepository) LatestIndexDate(ctx context.Context, name model.IndexName) (*time.Time, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func TestYieldService(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+	day := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 0, 0, 0, 0, time.UTC) }
+	now := day(time.July, 8).Add(3 * time.Hour)
+	daily, weekly := model.YieldDaily, model.YieldWeekly
+
+	setup := func(latestCDI, latestSelic *time.Time) (*YieldService, *MockYieldRepository) {
+		mockRepo := new(MockYieldRepository)
+		yieldService := NewYieldService(mockRepo, new(MockAccountRepository))
+		yieldService.now = func() time.Time { return now }
+		mockRepo.On("LatestIndexDate", ctx, model.IndexCDI).Return(latestCDI, nil).Once()
+		mockRepo.On("LatestIndexDate", ctx, model.IndexSelic).Return(latestSelic, nil).Once()
+		return yieldService, mockRepo
+	}
+
+	t.Run("should post the CDI yield with the IR withheld", func(t *testing.T) {
+		// Arrange: 110% of the CDI, posted daily, with the last posting ending on Friday.
+		account := model.Account{
+			Id: 10, UserId: 1, Name: "CDB", Type: model.Savings, CreatedAt: day(time.June, 1),
+			YieldType: testhelper.Ptr(model.YieldCDI), YieldRate: testhelper.Ptr(decimal.NewFromInt(110)),
+			YieldFrequency: &daily, YieldWithholdsIR: true,
+		}
+		yieldService, mockRepo := setup(testhelper.Ptr(day(time.July, 7)), nil)
+		mockRepo.On("ListYieldAccounts", ctx).Return([]model.Account{account}, nil).Once()
+		mockRepo.On("LastAccrualEnd", ctx, account.Id).Return(testhelper.Ptr(day(time.July, 4)), nil).Once()
+		cdi := []model.IndexRate{{Name: model.IndexCDI, Date: day(time.July, 4), AnnualRate: decimal.RequireFromString("14.9")}}
+		for _, d := range []int{4, 5, 6, 7} {
+			mockRepo.On("ListIndexRates", ctx, model.IndexCDI, day(time.June, d), day(time.July, d+1)).Return(cdi, nil).Once()
+			mockRepo.On("DailyBalances", ctx, account, day(time.July, d), day(time.July, d+1)).Return([]decimal.Decimal{decimal.NewFromInt(10000)}, nil).Once()
+		}
+		var accruals []model.YieldAccrual
+		var incomes, taxes []*model.Transaction
+		mockRepo.On("RecordAccrual", ctx, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			accruals = append(accruals, args.Get(1).(model.YieldAccrual))
+			incomes = append(incomes, args.Get(2).(*model.Transaction))
+			taxes = append(taxes, args.Get(3).(*model.Transaction))
+		}).Return(true, nil)
+
+		// Act
+		posted, err := yieldService.AccrueDueAccounts(ctx)
+
+		// Assert: Friday to Monday are posted; today's CDI is not loaded yet.
+		assert.NoError(t, err)
+		assert.Equal(t, 4, posted)
+		// 10,000 × ((1.149)^(1/252) - 1) × 110% = 6.06, of which 22.5% is IR.
+		assert.True(t, decimal.RequireFromString("6.06").Equal(accruals[0].GrossAmount), accruals[0].GrossAmount.String())
+		assert.True(t, decimal.RequireFromString("1.36").Equal(accruals[0].IncomeTaxAmount), accruals[0].IncomeTaxAmount.String())
+		if assert.NotNil(t, incomes[0]) && assert.NotNil(t, taxes[0]) {
+			assert.Equal(t, model.Income, incomes[0].Type)
+			assert.Equal(t, day(time.July, 5), incomes[0].Date)
+			assert.Equal(t, model.Expense, taxes[0].Type)
+			assert.True(t, accruals[0].IncomeTaxAmount.Equal(taxes[0].Amount))
+		}
+		// The weekend has no CDI rate, so nothing is earned.
+		assert.True(t, accruals[1].GrossAmount.IsZero())
+		assert.Nil(t, incomes[1])
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should catch up on the weeks of a fixed yield", func(t *testing.T) {
+		// Arrange: 12% a year posted weekly, starting three weeks and a day ago.
+		account := model.Account{
+			Id: 11, UserId: 1, Name: "Prefixado", Type: model.Savings, CreatedAt: day(time.January, 1),
+			YieldType: testhelper.Ptr(model.YieldFixed), YieldRate: testhelper.Ptr(decimal.NewFromInt(12)),
+			YieldFrequency: &weekly, YieldStartDate: testhelper.Ptr(day(time.June, 17)),
+		}
+		yieldService, mockRepo := setup(nil, nil)
+		mockRepo.On("ListYieldAccounts", ctx).Return([]model.Account{account}, nil).Once()
+		mockRepo.On("LastAccrualEnd", ctx, account.Id).Return(nil, nil).Once()
+		mockRepo.On("DailyBalances", ctx, account, mock.Anything, mock.Anything).Return(dailyBalances(7, "10000"), nil).Times(3)
+		var accruals []model.YieldAccrual
+		mockRepo.On("RecordAccrual", ctx, mock.Anything, mock.Anything, (*model.Transaction)(nil)).Run(func(args mock.Arguments) {
+			accruals = append(accruals, args.Get(1).(model.YieldAccrual))
+		}).Return(true, nil).Times(3)
+
+		// Act
+		posted, err := yieldService.AccrueDueAccounts(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 3, posted)
+		assert.Equal(t, day(time.June, 17), accruals[0].PeriodStart)
+		assert.Equal(t, day(time.July, 8), accruals[2].PeriodEnd)
+		// A week of 10,000 at 12% a year, compounded daily.
+		assert.True(t, decimal.RequireFromString("21.76").Equal(accruals[0].GrossAmount), accruals[0].GrossAmount.String())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should withhold the IR by the account's age, not the deposit's", func(t *testing.T) {
+		// Arrange: an account earning for over two years whose balance was just deposited.
+		account := model.Account{
+			Id: 13, UserId: 1, Name: "CDB antigo", Type: model.Savings, CreatedAt: day(time.January, 1).AddDate(-3, 0, 0),
+			YieldType: testhelper.Ptr(model.YieldFixed), YieldRate: testhelper.Ptr(decimal.NewFromInt(12)),
+			YieldFrequency: &daily, YieldWithholdsIR: true,
+		}
+		yieldService, mockRepo := setup(nil, nil)
+		mockRepo.On("ListYieldAccounts", ctx).Return([]model.Account{account}, nil).Once()
+		mockRepo.On("LastAccrualEnd", ctx, account.Id).Return(testhelper.Ptr(day(time.July, 7)), nil).Once()
+		mockRepo.On("DailyBalances", ctx, account, day(time.July, 7), day(time.July, 8)).Return([]decimal.Decimal{decimal.NewFromInt(100000)}, nil).Once()
+		var accrual model.YieldAccrual
+		mockRepo.On("RecordAccrual", ctx, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			accrual = args.Get(1).(model.YieldAccrual)
+		}).Return(true, nil).Once()
+
+		// Act
+		posted, err := yieldService.AccrueDueAccounts(ctx)
+
+		// Assert: the lowest rate, 15%, even though the money was held for a day.
+		assert.NoError(t, err)
+		assert.Equal(t, 1, posted)
+		assert.True(t, accrual.GrossAmount.IsPositive())
+		assert.True(t, accrual.GrossAmount.Mul(decimal.NewFromInt(15)).Div(decimal.NewFromInt(100)).Round(2).Equal(accrual.IncomeTaxAmount), accrual.IncomeTaxAmount.String())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should wait for the Selic before posting poupança", func(t *testing.T) {
+		// Arrange
+		account := model.Account{
+			Id: 12, UserId: 1, Name: "Poupança", Type: model.Savings, CreatedAt: day(time.May, 10),
+			YieldType: testhelper.Ptr(model.YieldPoupanca),
+		}
+		yieldService, mockRepo := setup(nil, testhelper.Ptr(day(time.June, 27)))
+		mockRepo.On("ListYieldAccounts", ctx).Return([]model.Account{account}, nil).Once()
+		mockRepo.On("LastAccrualEnd", ctx, account.Id).Return(testhelper.Ptr(day(time.June, 1)), nil).Once()
+
+		// Act
+		posted, err := yieldService.AccrueDueAccounts(ctx)
+
+		// Assert: June 30th has no Selic loaded yet.
+		assert.NoError(t, err)
+		assert.Zero(t, posted)
+		mockRepo.AssertNotCalled(t, "RecordAccrual", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestYieldRates(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, time.June, d, 0, 0, 0, 0, time.UTC) }
+
+	t.Run("poupança pays 0.5% a month while the Selic is above 8.5%", func(t *testing.T) {
+		account := model.Account{YieldType: testhelper.Ptr(model.YieldPoupanca)}
+		selic := []model.IndexRate{{Date: day(1), AnnualRate: decimal.NewFromInt(15)}}
+
+		yield := accruedYield(dailyBalances(30, "10000"), dailyYieldRates(account, day(1), day(1).AddDate(0, 0, 30), selic))
+
+		assert.True(t, decimal.RequireFromString("49.31").Equal(yield), yield.String())
+	})
+
+	t.Run("poupança pays 70% of the Selic otherwise", func(t *testing.T) {
+		account := model.Account{YieldType: testhelper.Ptr(model.YieldPoupanca)}
+		selic := []model.IndexRate{{Date: day(1).AddDate(0, 0, -3), AnnualRate: decimal.NewFromInt(8)}}
+
+		yield := accruedYield(dailyBalances(30, "10000"), dailyYieldRates(account, day(1), day(1).AddDate(0, 0, 30), selic))
+
+		assert.True(t, decimal.RequireFromString("44.89").Equal(yield), yield.String())
+	})
+
+	t.Run("yield is only earned on positive balances", func(t *testing.T) {
+		rates := []decimal.Decimal{decimal.RequireFromString("0.01"), decimal.RequireFromString("0.01")}
+
+		yield := accruedYield([]decimal.Decimal{decimal.NewFromInt(-500), decimal.NewFromInt(1000)}, rates)
+
+		assert.True(t, decimal.NewFromInt(10).Equal(yield), yield.String())
+	})
+
+	t.Run("the IR falls with the holding period", func(t *testing.T) {
+		for daysHeld, rate := range map[int]string{30: "22.5", 180: "22.5", 181: "20", 500: "17.5", 721: "15"} {
+			assert.True(t, decimal.RequireFromString(rate).Equal(incomeTaxRate(daysHeld)), "%d days", daysHeld)
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultAPIURL is the address of the public Telegram Bot API.
+const DefaultAPIURL = "https://api.telegram.org"
+
+// Update is an incoming event. Only text messages are used by the bot.
+type Update struct {
+	UpdateId int64    `json:"update_id"`
+	Message  *Message `json:"message,omitempty"`
+}
+
+// Message is a message sent to the bot.
+type Message struct {
+	MessageId int64  `json:"message_id"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text"`
+}
+
+// Chat is the conversation a message belongs to.
+type Chat struct {
+	Id int64 `json:"id"`
+}
+
+// Handler processes the text messages received by the bot.
+type Handler interface {
+	HandleMessage(ctx context.Context, chatId int64, text string) error
+}
+
+// Client calls the Telegram Bot API. The base URL is configurable so the bot
+// can be run against a local stub server.
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewClient creates a new Client. An empty baseURL uses DefaultAPIURL.
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultAPIURL
+	}
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		// Long polling keeps requests open, so the timeout is set per call instead.
+		http: &http.Client{},
+	}
+}
+
+// apiResponse is the envelope of every Bot API response.
+type apiResponse struct {
+	Ok          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	Description string          `json:"description"`
+}
+
+// SendMessage sends a plain-text message to a chat.
+func (c *Client) SendMessage(ctx context.Context, chatId int64, text string) error {
+	return c.call(ctx, "sendMessage", map[string]any{"chat_id": chatId, "text": text}, nil)
+}
+
+// GetUpdates waits up to timeout for updates newer than offset.
+func (c *Client) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	var updates []Update
+	params := map[string]any{
+		"offset":          offset,
+		"timeout":         int(timeout.Seconds()),
+		"allowed_updates": []string{"message"},
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout+10*time.Second)
+	defer cancel()
+	err := c.call(ctx, "getUpdates", params, &updates)
+	return updates, err
+}
+
+func (c *Client) call(ctx context.Context, method string, params any, result any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/bot%s/%s", c.baseURL, c.token, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		// The URL carries the bot token, so it is never part of the error.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("telegram %s failed: %w", method, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Error closing telegram response body")
+		}
+	}()
+
+	var envelope apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("telegram %s returned an invalid response: %w", method, err)
+	}
+	if !envelope.Ok {
+		return fmt.Errorf("telegram %s failed: %s", method, envelope.Description)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(envelope.Result, result)
+}
+
+// Poller fetches updates through long polling and hands every text message to
+// a Handler. It is used when no webhook is configured.
+type Poller struct {
+	client  *Client
+	handler Handler
+	timeout time.Duration
+	backoff time.Duration
+	logger  zerolog.Logger
+}
+
+// NewPoller creates a new Poller.
+func NewPoller(client *Client, handler Handler, logger zerolog.Logger) *Poller {
+	return &Poller{
+		client:  client,
+		handler: handler,
+		timeout: 30 * time.Second,
+		backoff: 5 * time.Second,
+		logger:  logger.With().Str("component", "TelegramPoller").Logger(),
+	}
+}
+
+// Run polls until ctx is cancelled. Failed requests are retried after a pause.
+func (p *Poller) Run(ctx context.Context) {
+	ctx = p.logger.WithContext(ctx)
+	var offset int64
+	for ctx.Err() == nil {
+		updates, err := p.client.GetUpdates(ctx, offset, p.timeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			p.logger.Error().Err(err).Msg("failed to fetch telegram updates")
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(p.backoff):
+			}
+			continue
+		}
+		for _, update := range updates {
+			offset = max(offset, update.UpdateId+1)
+			Dispatch(ctx, p.handler, update)
+		}
+	}
+}
+
+// Dispatch hands the text message of an update, if any, to the handler. Errors
+// are only logged: the update is never delivered again.
+func Dispatch(ctx context.Context, handler Handler, update Update) {
+	if update.Message == nil || update.Message.Text == "" {
+		return
+	}
+	if err := handler.HandleMessage(ctx, update.Message.Chat.Id, update.Message.Text); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Int64("chatId", update.Message.Chat.Id).Msg("failed to handle telegram message")
+	}
+}
@@ -0,0 +1,144 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubAPI is a local stand-in for the Bot API. It serves a fixed batch of
+// updates once and records every message sent.
+type stubAPI struct {
+	mu      sync.Mutex
+	updates []Update
+	offsets []int64
+	sent    []map[string]any
+}
+
+func (s *stubAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var params map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&params)
+	switch r.URL.Path {
+	case "/bottest-token/getUpdates":
+		s.offsets = append(s.offsets, int64(params["offset"].(float64)))
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": s.updates})
+		s.updates = nil
+	case "/bottest-token/sendMessage":
+		if params["text"] == "" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"ok": false, "description": "Bad Request: message text is empty"})
+			return
+		}
+		s.sent = append(s.sent, params)
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": map[string]any{"message_id": 1}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": false, "description": "Not Found"})
+	}
+}
+
+type recordingHandler struct {
+	mu       sync.Mutex
+	messages []string
+	done     chan struct{}
+}
+
+func (h *recordingHandler) HandleMessage(ctx context.Context, chatId int64, text string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.messages = append(h.messages, text)
+	if len(h.messages) == 2 {
+		close(h.done)
+	}
+	return nil
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should send messages to the configured API", func(t *testing.T) {
+		// Arrange
+		stub := &stubAPI{}
+		server := httptest.NewServer(stub)
+		defer server.Close()
+		client := NewClient(server.URL, "test-token")
+
+		// Act
+		err := client.SendMessage(ctx, 42, "Olá")
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, stub.sent, 1)
+		assert.Equal(t, float64(42), stub.sent[0]["chat_id"])
+		assert.Equal(t, "Olá", stub.sent[0]["text"])
+	})
+
+	t.Run("should return the API description when a call fails", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(&stubAPI{})
+		defer server.Close()
+		client := NewClient(server.URL, "test-token")
+
+		// Act
+		err := client.SendMessage(ctx, 42, "")
+
+		// Assert
+		assert.ErrorContains(t, err, "message text is empty")
+	})
+
+	t.Run("should not leak the token when the API is unreachable", func(t *testing.T) {
+		// Arrange
+		client := NewClient("http://127.0.0.1:1", "secret-token")
+
+		// Act
+		err := client.SendMessage(ctx, 42, "Olá")
+
+		// Assert
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "secret-token")
+	})
+}
+
+func TestPoller(t *testing.T) {
+	t.Run("should hand text messages to the handler and acknowledge them", func(t *testing.T) {
+		// Arrange
+		stub := &stubAPI{updates: []Update{
+			{UpdateId: 10, Message: &Message{Chat: Chat{Id: 1}, Text: "/saldo"}},
+			{UpdateId: 11},
+			{UpdateId: 12, Message: &Message{Chat: Chat{Id: 1}, Text: "/orcamento"}},
+		}}
+		server := httptest.NewServer(stub)
+		defer server.Close()
+		handler := &recordingHandler{done: make(chan struct{})}
+		poller := NewPoller(NewClient(server.URL, "test-token"), handler, zerolog.Nop())
+		poller.timeout = 0
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Act
+		go poller.Run(ctx)
+
+		// Assert
+		select {
+		case <-handler.done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the poller did not deliver the messages")
+		}
+		assert.Equal(t, []string{"/saldo", "/orcamento"}, handler.messages)
+		assert.Eventually(t, func() bool {
+			stub.mu.Lock()
+			defer stub.mu.Unlock()
+			return len(stub.offsets) > 1 && stub.offsets[1] == 13
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}